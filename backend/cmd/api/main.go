@@ -3,23 +3,32 @@ package main
 import (
 	"SynDataGen/backend/internal/auth"
 	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/events"
 	"SynDataGen/backend/internal/job"
+	"SynDataGen/backend/internal/jobtemplate"
+	"SynDataGen/backend/internal/metering"
+	"SynDataGen/backend/internal/notification"
 	"SynDataGen/backend/internal/platform/firestore"
 	"SynDataGen/backend/internal/platform/logger"
 	"SynDataGen/backend/internal/platform/pipeline"
 	"SynDataGen/backend/internal/platform/storage"
 	"SynDataGen/backend/internal/project"
+	"SynDataGen/backend/internal/retention"
+	"SynDataGen/backend/internal/schedule"
+	"SynDataGen/backend/internal/workflow"
 	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	fs "cloud.google.com/go/firestore"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 )
@@ -35,6 +44,90 @@ func getEnv(key, fallback string) string {
 	return value
 }
 
+// notificationMailer returns the mailer configured by SMTP_HOST, SMTP_PORT, SMTP_USERNAME,
+// SMTP_PASSWORD and NOTIFICATION_EMAIL_FROM, or nil if SMTP_HOST is not set, which
+// disables email notifications.
+func notificationMailer() (notification.Mailer, error) {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		return nil, nil
+	}
+	port, err := strconv.Atoi(getEnv("SMTP_PORT", "587"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
+	}
+	mailer, err := notification.NewSMTPMailer(notification.SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("NOTIFICATION_EMAIL_FROM"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mailer, nil
+}
+
+// jobLimitConfig reads the job limits from JOB_CREATES_PER_USER_PER_MINUTE,
+// JOB_MAX_RUNNING_PER_PROJECT, JOB_MAX_RUNNING_PER_CUSTOMER and JOB_MAX_RECORDS, where "0"
+// (the default) disables a limit, and JOB_QUEUE_OVER_LIMIT.
+func jobLimitConfig() (job.LimitConfig, error) {
+	var cfg job.LimitConfig
+	ints := []struct {
+		key    string
+		target *int
+	}{
+		{"JOB_CREATES_PER_USER_PER_MINUTE", &cfg.CreatesPerUserPerMinute},
+		{"JOB_MAX_RUNNING_PER_PROJECT", &cfg.MaxRunningPerProject},
+		{"JOB_MAX_RUNNING_PER_CUSTOMER", &cfg.MaxRunningPerCustomer},
+	}
+	for _, entry := range ints {
+		value, err := strconv.Atoi(getEnv(entry.key, "0"))
+		if err != nil || value < 0 {
+			return cfg, fmt.Errorf("%s must be a non-negative integer", entry.key)
+		}
+		*entry.target = value
+	}
+	maxRecords, err := strconv.ParseInt(getEnv("JOB_MAX_RECORDS", "0"), 10, 64)
+	if err != nil || maxRecords < 0 {
+		return cfg, fmt.Errorf("JOB_MAX_RECORDS must be a non-negative integer")
+	}
+	cfg.MaxRecordsPerJob = maxRecords
+	if cfg.QueueOverLimit, err = strconv.ParseBool(getEnv("JOB_QUEUE_OVER_LIMIT", "false")); err != nil {
+		return cfg, fmt.Errorf("JOB_QUEUE_OVER_LIMIT must be true or false")
+	}
+	return cfg, nil
+}
+
+// dataGenTokenSource builds the DataGen API token source selected by DATAGEN_AUTH:
+// "client_credentials" (OAuth2), "gcp_id_token" (service account or ADC), "static"
+// or "none" (the default).
+func dataGenTokenSource(ctx context.Context) (pipeline.TokenSource, error) {
+	switch mode := getEnv("DATAGEN_AUTH", "none"); mode {
+	case "client_credentials":
+		var scopes []string
+		if raw := getEnv("DATAGEN_SCOPES", ""); raw != "" {
+			scopes = strings.Fields(strings.ReplaceAll(raw, ",", " "))
+		}
+		return pipeline.NewClientCredentialsTokenSource(pipeline.ClientCredentialsConfig{
+			TokenURL:     getEnv("DATAGEN_TOKEN_URL", ""),
+			ClientID:     getEnv("DATAGEN_CLIENT_ID", ""),
+			ClientSecret: getEnv("DATAGEN_CLIENT_SECRET", ""),
+			Scopes:       scopes,
+			Audience:     getEnv("DATAGEN_AUDIENCE", ""),
+		})
+	case "gcp_id_token":
+		return pipeline.NewGCPIDTokenSource(ctx, getEnv("DATAGEN_AUDIENCE", getEnv("DATAGEN_API_URL", "")), getEnv("DATAGEN_CREDENTIALS_FILE", ""))
+	case "static":
+		return pipeline.NewStaticTokenSource(getEnv("DATAGEN_TOKEN", "")), nil
+	case "none":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown DATAGEN_AUTH %q", mode)
+	}
+}
+
 // initFirestore initializes the Firestore client.
 // In a real app, consider more robust error handling and configuration.
 func initFirestore(ctx context.Context) (*fs.Client, error) {
@@ -58,8 +151,10 @@ func initFirestore(ctx context.Context) (*fs.Client, error) {
 
 // setupRouter configures the Gin router with routes and handlers.
 // Pass core.StorageService for type safety
-func setupRouter(authSvc auth.AuthService, projectSvc project.ProjectService, jobSvc job.JobService, storageSvc core.StorageService) *gin.Engine {
+func setupRouter(authSvc auth.AuthService, projectSvc project.ProjectService, jobSvc job.JobService, templateSvc jobtemplate.TemplateService, scheduleSvc schedule.ScheduleService, workflowSvc workflow.WorkflowService, usageSvc metering.UsageService, meter *metering.Meter, notificationSvc notification.NotificationService, storageSvc core.StorageService, eventBus *events.Bus) *gin.Engine {
 	router := gin.Default() // Includes logger and recovery middleware
+	// Match routes on the escaped path so dataset IDs can carry %2F-encoded folders (e.g. jobs/<id>/output.parquet)
+	router.UseRawPath = true
 
 	// Configure CORS based on environment variable
 	allowedOriginsEnv := getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:3000") // Default for safety & local dev
@@ -76,7 +171,7 @@ func setupRouter(authSvc auth.AuthService, projectSvc project.ProjectService, jo
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"}, // Add Authorization if needed later
-		ExposeHeaders:    []string{"Content-Length"},
+		ExposeHeaders:    []string{"Content-Length", "Retry-After"},                             // Retry-After tells clients when a rate or concurrency limit frees up
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -100,6 +195,9 @@ func setupRouter(authSvc auth.AuthService, projectSvc project.ProjectService, jo
 	// API v1 Group
 	apiV1 := router.Group("/api/v1")
 	{
+		// Bytes served from project data are metered as egress
+		apiV1.Use(meter.EgressMiddleware("/api/v1/projects/:projectId/datasets/:datasetId/content"))
+
 		// --- Auth Routes (Manual Registration) ---
 		authHandlers := auth.NewAuthHandlers(authSvc)
 		authRoutes := apiV1.Group("/auth")
@@ -122,6 +220,30 @@ func setupRouter(authSvc auth.AuthService, projectSvc project.ProjectService, jo
 		// --- Job Routes ---
 		jobHandlers := job.NewJobHandler(jobSvc)
 		jobHandlers.RegisterRoutes(apiV1, auth.AuthMiddleware(authSvc))
+
+		// --- Job Template Routes ---
+		templateHandlers := jobtemplate.NewTemplateHandler(templateSvc)
+		templateHandlers.RegisterRoutes(apiV1, auth.AuthMiddleware(authSvc))
+
+		// --- Schedule Routes ---
+		scheduleHandlers := schedule.NewScheduleHandler(scheduleSvc)
+		scheduleHandlers.RegisterRoutes(apiV1, auth.AuthMiddleware(authSvc))
+
+		// --- Workflow Routes ---
+		workflowHandlers := workflow.NewWorkflowHandler(workflowSvc)
+		workflowHandlers.RegisterRoutes(apiV1, auth.AuthMiddleware(authSvc))
+
+		// --- Event Stream Routes ---
+		eventHandlers := events.NewHandler(eventBus, projectSvc)
+		eventHandlers.RegisterRoutes(apiV1, auth.AuthMiddleware(authSvc))
+
+		// --- Usage Routes ---
+		usageHandlers := metering.NewUsageHandler(usageSvc)
+		usageHandlers.RegisterRoutes(apiV1, auth.AuthMiddleware(authSvc))
+
+		// --- Notification Routes ---
+		notificationHandlers := notification.NewNotificationHandler(notificationSvc)
+		notificationHandlers.RegisterRoutes(apiV1, auth.AuthMiddleware(authSvc))
 	}
 
 	return router
@@ -156,6 +278,13 @@ func main() {
 	userRepo := firestore.NewUserRepository(firestoreClient)
 	projectRepo := firestore.NewProjectRepository(firestoreClient)
 	jobRepo := firestore.NewJobRepository(firestoreClient, logger.Logger)
+	templateRepo := firestore.NewTemplateRepository(firestoreClient, logger.Logger)
+	scheduleRepo := firestore.NewScheduleRepository(firestoreClient, logger.Logger)
+	workflowRepo := firestore.NewWorkflowRepository(firestoreClient, logger.Logger)
+	leaseRepo := firestore.NewLeaseRepository(firestoreClient, logger.Logger)
+	rateLimitRepo := firestore.NewRateLimitRepository(firestoreClient, logger.Logger)
+	usageRepo := firestore.NewUsageRepository(firestoreClient, logger.Logger)
+	notificationRepo := firestore.NewNotificationRepository(firestoreClient, logger.Logger)
 
 	// Storage Service Initialization
 	storageCfg := storage.Config{
@@ -169,17 +298,212 @@ func main() {
 	defer storageSvcInstance.Close()
 	logger.Logger.Info("GCP Storage service initialized successfully")
 
-	// Pipeline Client (Stub for now, pass logger)
-	pipelineClient := pipeline.NewStubPipelineClient(log.Default())
-	logger.Logger.Info("Stub Pipeline client initialized")
+	// Pipeline Client: "stub" (default) only simulates statuses, "local" generates data
+	// in-process and "datagen" calls the external DataGen pipeline. "stub-datagen" serves the
+	// simulator as a local DataGen API and talks to it through the DataGen client. "local"
+	// keeps job state in the process, so run a single replica with it: jobs it does not know
+	// are reported as failed.
+	var pipelineClient job.PipelineClient
+	switch pipelineKind := getEnv("PIPELINE_CLIENT", "stub"); pipelineKind {
+	case "local":
+		pipelineClient, err = pipeline.NewLocalPipelineClient(pipeline.LocalConfig{
+			Storage:  storageSvcInstance,
+			Projects: projectRepo,
+			Logger:   logger.Logger,
+		})
+	case "datagen":
+		var tokenSource pipeline.TokenSource
+		if tokenSource, err = dataGenTokenSource(ctx); err == nil {
+			pipelineClient, err = pipeline.NewDataGenPipelineClient(pipeline.Config{
+				BaseURL:     getEnv("DATAGEN_API_URL", ""),
+				Logger:      logger.Logger,
+				TokenSource: tokenSource,
+			})
+		}
+	case "stub-datagen":
+		stubAddr := getEnv("STUB_DATAGEN_ADDR", "127.0.0.1:8090")
+		stubToken := uuid.NewString() // Exercises the client's bearer authentication
+		stubServer := &http.Server{Addr: stubAddr, Handler: pipeline.NewStubPipeline(pipeline.StubConfig{Token: stubToken}).Handler()}
+		go func() {
+			if err := stubServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				logger.Logger.Fatal("Stub DataGen API stopped", zap.Error(err))
+			}
+		}()
+		pipelineClient, err = pipeline.NewDataGenPipelineClient(pipeline.Config{
+			BaseURL:     "http://" + stubAddr,
+			Logger:      logger.Logger,
+			TokenSource: pipeline.NewStaticTokenSource(stubToken),
+		})
+	case "stub":
+		pipelineClient = pipeline.NewStubPipelineClient(log.Default())
+	default:
+		err = fmt.Errorf("unknown PIPELINE_CLIENT %q", pipelineKind)
+	}
+	if err != nil {
+		logger.Logger.Fatal("Failed to initialize pipeline client", zap.Error(err))
+	}
+	logger.Logger.Info("Pipeline client initialized")
 
 	// --- Service Initializations ---
 	authSvc := auth.NewAuthService(userRepo)
 	projectSvc := project.NewProjectService(projectRepo, userRepo, storageSvcInstance)
-	jobSvc := job.NewJobService(jobRepo, projectSvc, pipelineClient)
+	jobLimits, err := jobLimitConfig()
+	if err != nil {
+		logger.Logger.Fatal("Invalid job limits", zap.Error(err))
+	}
+	jobLimiter := job.NewLimiter(jobLimits, projectRepo, jobRepo, rateLimitRepo)
+
+	// Job estimates price pipeline compute at JOB_COMPUTE_COST_PER_HOUR (in JOB_COST_CURRENCY)
+	// and are recalibrated from completed jobs every JOB_ESTIMATE_CALIBRATION_INTERVAL
+	computeCostPerHour, err := strconv.ParseFloat(getEnv("JOB_COMPUTE_COST_PER_HOUR", "0"), 64)
+	if err != nil || computeCostPerHour < 0 {
+		logger.Logger.Fatal("Invalid JOB_COMPUTE_COST_PER_HOUR", zap.String("value", getEnv("JOB_COMPUTE_COST_PER_HOUR", "")), zap.Error(err))
+	}
+	calibrationInterval, err := time.ParseDuration(getEnv("JOB_ESTIMATE_CALIBRATION_INTERVAL", job.DefaultCalibrationInterval.String()))
+	if err != nil {
+		logger.Logger.Fatal("Invalid JOB_ESTIMATE_CALIBRATION_INTERVAL", zap.Error(err))
+	}
+	estimator := job.NewEstimator(job.EstimatorConfig{
+		ComputeCostPerHour: computeCostPerHour,
+		Currency:           getEnv("JOB_COST_CURRENCY", job.DefaultEstimateCurrency),
+		Interval:           calibrationInterval,
+	}, projectRepo, jobRepo)
+
+	// Background loops that must run on one replica at a time share leases, held under this ID
+	replicaID := uuid.NewString()
+
+	// Usage meter records completed jobs, storage and egress; storage is billed in periods
+	// of METERING_INTERVAL
+	meteringInterval, err := time.ParseDuration(getEnv("METERING_INTERVAL", metering.DefaultInterval.String()))
+	if err != nil {
+		logger.Logger.Fatal("Invalid METERING_INTERVAL", zap.Error(err))
+	}
+	meter := metering.NewMeter(usageRepo, projectRepo, jobRepo, leaseRepo, metering.Config{
+		Interval: meteringInterval,
+		HolderID: replicaID,
+	})
+	usageSvc := metering.NewUsageService(usageRepo, projectSvc)
+
+	// Notifier queues deliveries for finished jobs; the lease holder sends them every
+	// NOTIFICATION_DISPATCH_INTERVAL, retrying failures up to NOTIFICATION_MAX_ATTEMPTS times
+	mailer, err := notificationMailer()
+	if err != nil {
+		logger.Logger.Fatal("Invalid SMTP configuration", zap.Error(err))
+	}
+	if mailer == nil {
+		logger.Logger.Warn("SMTP_HOST not set, email notifications are disabled")
+	}
+	notificationInterval, err := time.ParseDuration(getEnv("NOTIFICATION_DISPATCH_INTERVAL", notification.DefaultDispatchInterval.String()))
+	if err != nil {
+		logger.Logger.Fatal("Invalid NOTIFICATION_DISPATCH_INTERVAL", zap.Error(err))
+	}
+	notificationAttempts, err := strconv.Atoi(getEnv("NOTIFICATION_MAX_ATTEMPTS", strconv.Itoa(notification.DefaultMaxAttempts)))
+	if err != nil {
+		logger.Logger.Fatal("Invalid NOTIFICATION_MAX_ATTEMPTS", zap.Error(err))
+	}
+	notifier := notification.NewNotifier(notificationRepo, jobRepo, projectRepo, leaseRepo, notification.NewChannels(mailer, nil), notification.NotifierConfig{
+		Interval:    notificationInterval,
+		HolderID:    replicaID,
+		MaxAttempts: notificationAttempts,
+	})
+	notificationSvc := notification.NewNotificationService(notificationRepo, userRepo, projectSvc, notifier)
+
+	// Event bus: "memory" (default) keeps events in this process, "firestore" fans them out
+	// to every replica through the events collection
+	var eventBroker events.Broker
+	switch brokerKind := getEnv("EVENT_BROKER", "memory"); brokerKind {
+	case "memory":
+	case "firestore":
+		eventBroker = firestore.NewEventBroker(firestoreClient, logger.Logger)
+	default:
+		logger.Logger.Fatal("Unknown EVENT_BROKER", zap.String("broker", brokerKind))
+	}
+	eventBus := events.NewBus(eventBroker)
+	go eventBus.Run(ctx)
+
+	jobSvc := job.NewJobService(jobRepo, projectSvc, pipelineClient, job.JobServiceOptions{
+		Events:    eventBus,
+		Limiter:   jobLimiter,
+		Estimator: estimator,
+		Usage:     meter,
+		Notifier:  notifier,
+	})
+	projectSvc.SetArchiveHook(jobSvc.CancelProjectJobs) // Archiving a project cancels its in-flight jobs
+	templateSvc := jobtemplate.NewTemplateService(templateRepo, projectSvc, jobSvc)
+	scheduleSvc := schedule.NewScheduleService(scheduleRepo, projectSvc, templateSvc)
+	workflowSvc := workflow.NewWorkflowService(workflowRepo, projectSvc, jobSvc)
+
+	// Status watcher syncs running jobs so their events are published; replicas share a
+	// lease so only one syncs per tick. "0" disables it
+	watchInterval, err := time.ParseDuration(getEnv("JOB_STATUS_WATCH_INTERVAL", job.DefaultWatchInterval.String()))
+	if err != nil {
+		logger.Logger.Fatal("Invalid JOB_STATUS_WATCH_INTERVAL", zap.Error(err))
+	}
+	if watchInterval > 0 {
+		watcher := job.NewStatusWatcher(projectRepo, jobRepo, leaseRepo, jobSvc, job.StatusWatcherConfig{
+			Interval: watchInterval,
+			HolderID: replicaID,
+		})
+		go watcher.Run(ctx)
+	}
+
+	// Every replica calibrates its own job estimates; calibration only reads jobs
+	go estimator.Run(ctx)
+
+	// Retention sweeper enforces DataRetentionDays for every storage backend; replicas share a lease
+	sweepInterval, err := time.ParseDuration(getEnv("RETENTION_SWEEP_INTERVAL", retention.DefaultInterval.String()))
+	if err != nil {
+		logger.Logger.Fatal("Invalid RETENTION_SWEEP_INTERVAL", zap.Error(err))
+	}
+	sweeper := retention.NewSweeper(projectRepo, jobRepo, storageSvcInstance, projectSvc, leaseRepo, retention.Config{
+		Interval: sweepInterval,
+		HolderID: replicaID,
+	})
+	go sweeper.Run(ctx)
+
+	// Scheduler fires due schedules; replicas share a lease so only one fires per tick
+	scheduleInterval, err := time.ParseDuration(getEnv("SCHEDULER_TICK_INTERVAL", schedule.DefaultTickInterval.String()))
+	if err != nil {
+		logger.Logger.Fatal("Invalid SCHEDULER_TICK_INTERVAL", zap.Error(err))
+	}
+	scheduler := schedule.NewScheduler(scheduleRepo, leaseRepo, jobRepo, jobSvc, templateSvc, schedule.SchedulerConfig{
+		Interval: scheduleInterval,
+		HolderID: replicaID,
+	})
+	go scheduler.Run(ctx)
+
+	// Workflow orchestrator starts workflow steps as their dependencies complete
+	workflowInterval, err := time.ParseDuration(getEnv("WORKFLOW_TICK_INTERVAL", workflow.DefaultOrchestratorInterval.String()))
+	if err != nil {
+		logger.Logger.Fatal("Invalid WORKFLOW_TICK_INTERVAL", zap.Error(err))
+	}
+	orchestrator := workflow.NewOrchestrator(workflowRepo, leaseRepo, jobRepo, jobSvc, pipelineClient, workflow.OrchestratorConfig{
+		Interval: workflowInterval,
+		HolderID: replicaID,
+	})
+	go orchestrator.Run(ctx)
+
+	// Queue dispatcher submits jobs queued by a concurrency limit once there is capacity
+	if jobLimits.QueueOverLimit {
+		queueInterval, err := time.ParseDuration(getEnv("JOB_QUEUE_DISPATCH_INTERVAL", job.DefaultQueueDispatchInterval.String()))
+		if err != nil {
+			logger.Logger.Fatal("Invalid JOB_QUEUE_DISPATCH_INTERVAL", zap.Error(err))
+		}
+		dispatcher := job.NewQueueDispatcher(projectRepo, jobRepo, leaseRepo, jobSvc, job.QueueDispatcherConfig{
+			Interval: queueInterval,
+			HolderID: replicaID,
+		})
+		go dispatcher.Run(ctx)
+	}
+
+	// Usage meter records jobs that completed unmetered and samples storage on the lease holder
+	go meter.Run(ctx)
+
+	// Notification dispatcher sends due deliveries on the lease holder
+	go notifier.Run(ctx)
 
 	// Setup Router
-	router := setupRouter(authSvc, projectSvc, jobSvc, storageSvcInstance)
+	router := setupRouter(authSvc, projectSvc, jobSvc, templateSvc, scheduleSvc, workflowSvc, usageSvc, meter, notificationSvc, storageSvcInstance, eventBus)
 
 	// Start Server
 	port := getEnv("PORT", "8080")
@@ -0,0 +1,54 @@
+package access
+
+import (
+	"SynDataGen/backend/internal/auth"
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"SynDataGen/backend/internal/project"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCode reports a package's own error with an HTTP status and error code.
+type ErrorCode struct {
+	Err    error
+	Status int
+	Code   string
+}
+
+// RequireUserID returns the authenticated user, aborting the request if there is none.
+func RequireUserID(c *gin.Context, action string) (string, bool) {
+	userID, ok := c.Get(auth.UserIDKey)
+	if !ok || userID == "" {
+		logger.Logger.Error("UserID not found in context during " + action)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User ID missing"})
+		return "", false
+	}
+	return userID.(string), true
+}
+
+// AbortWithError maps a service error to a response. Missing resources are reported with
+// notFound, denied access and archived projects the same way everywhere, and the caller's
+// own errors through codes. Any other error is reported as fallback.
+func AbortWithError(c *gin.Context, err error, notFound, fallback string, codes ...ErrorCode) {
+	switch {
+	case errors.Is(err, core.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": notFound})
+		return
+	case errors.Is(err, core.ErrForbidden) || errors.Is(err, project.ErrProjectAccessDenied):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: You do not have permission for this action"})
+		return
+	case errors.Is(err, core.ErrProjectArchived):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "PROJECT_ARCHIVED", "message": err.Error()})
+		return
+	}
+	for _, code := range codes {
+		if errors.Is(err, code.Err) {
+			c.AbortWithStatusJSON(code.Status, gin.H{"error": code.Code, "message": err.Error()})
+			return
+		}
+	}
+	c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": fallback})
+}
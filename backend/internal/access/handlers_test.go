@@ -0,0 +1,73 @@
+package access
+
+import (
+	"SynDataGen/backend/internal/auth"
+	"SynDataGen/backend/internal/core"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+var errWidgetBroken = errors.New("widget broken")
+
+// abortWith runs AbortWithError on a test context and returns the recorded response.
+func abortWith(err error) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	AbortWithError(c, err, "Widget or project not found", "Failed to get widget",
+		ErrorCode{Err: errWidgetBroken, Status: http.StatusConflict, Code: "WIDGET_BROKEN"})
+	return w
+}
+
+func TestAbortWithError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		body   string
+	}{
+		{"NotFound", fmt.Errorf("lookup: %w", core.ErrNotFound), http.StatusNotFound, "Widget or project not found"},
+		{"Forbidden", fmt.Errorf("%w: insufficient role", core.ErrForbidden), http.StatusForbidden, "Forbidden"},
+		{"Archived", core.ErrProjectArchived, http.StatusConflict, "PROJECT_ARCHIVED"},
+		{"PackageCode", fmt.Errorf("%w: gears", errWidgetBroken), http.StatusConflict, `"message":"widget broken: gears"`},
+		{"Fallback", errors.New("db down"), http.StatusInternalServerError, "Failed to get widget"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := abortWith(tt.err)
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.body)
+		})
+	}
+}
+
+func TestRequireUserID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("Success", func(t *testing.T) {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Set(auth.UserIDKey, "user-1")
+
+		userID, ok := RequireUserID(c, "Test")
+
+		assert.True(t, ok)
+		assert.Equal(t, "user-1", userID)
+	})
+
+	t.Run("Failure_Missing", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		_, ok := RequireUserID(c, "Test")
+
+		assert.False(t, ok)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
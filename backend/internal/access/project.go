@@ -0,0 +1,64 @@
+// Package access holds the project access checks and handler error responses shared by
+// the packages that manage project resources.
+package access
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// ProjectGetter retrieves a project the caller may view. project.ProjectService satisfies
+// this interface.
+type ProjectGetter interface {
+	GetProjectByID(ctx context.Context, projectID string, callerID string) (*core.Project, error)
+}
+
+// roleLevels orders the project roles from least to most privileged.
+var roleLevels = map[core.Role]int{
+	core.RoleViewer: 1,
+	core.RoleMember: 2,
+	core.RoleAdmin:  3,
+	core.RoleOwner:  4,
+}
+
+// RoleAtLeast reports whether role grants everything requiredRole does.
+func RoleAtLeast(role, requiredRole core.Role) bool {
+	return roleLevels[role] >= roleLevels[requiredRole] && roleLevels[role] > 0
+}
+
+// Authorize checks that the user has at least requiredRole in the project and returns it.
+func Authorize(ctx context.Context, projects ProjectGetter, projectID, userID string, requiredRole core.Role) (*core.Project, error) {
+	proj, err := projects.GetProjectByID(ctx, projectID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("project access check failed: %w", err) // NotFound or AccessDenied
+	}
+
+	userRole := proj.TeamMembers[userID]
+	if !RoleAtLeast(userRole, requiredRole) {
+		logger.Logger.Warn("Authorization failed: Insufficient role for project action",
+			zap.String("projectID", projectID),
+			zap.String("userID", userID),
+			zap.String("userRole", string(userRole)),
+			zap.String("requiredRole", string(requiredRole)),
+		)
+		return nil, fmt.Errorf("%w: insufficient role %s, requires %s", core.ErrForbidden, userRole, requiredRole)
+	}
+	return proj, nil
+}
+
+// AuthorizeChange checks like Authorize and also requires the project to be active, as
+// archived projects are read-only. what names what the caller is changing, for the error.
+func AuthorizeChange(ctx context.Context, projects ProjectGetter, projectID, userID string, requiredRole core.Role, what string) (*core.Project, error) {
+	proj, err := Authorize(ctx, projects, projectID, userID, requiredRole)
+	if err != nil {
+		return nil, err
+	}
+	if proj.Status == core.ProjectStatusArchived {
+		return nil, fmt.Errorf("%w: cannot change %s in project %s", core.ErrProjectArchived, what, projectID)
+	}
+	return proj, nil
+}
@@ -0,0 +1,85 @@
+package access
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/project"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockProjectGetter is a mock implementation of ProjectGetter.
+type MockProjectGetter struct {
+	mock.Mock
+}
+
+func (m *MockProjectGetter) GetProjectByID(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+func TestRoleAtLeast(t *testing.T) {
+	assert.True(t, RoleAtLeast(core.RoleOwner, core.RoleAdmin))
+	assert.True(t, RoleAtLeast(core.RoleMember, core.RoleMember))
+	assert.False(t, RoleAtLeast(core.RoleViewer, core.RoleMember))
+	assert.False(t, RoleAtLeast("", core.RoleViewer), "non-members hold no role")
+}
+
+func TestAuthorize(t *testing.T) {
+	ctx := context.Background()
+	proj := &core.Project{ID: "proj-1", Status: core.ProjectStatusActive, TeamMembers: map[string]core.Role{"member": core.RoleMember, "viewer": core.RoleViewer}}
+	archived := &core.Project{ID: "proj-2", Status: core.ProjectStatusArchived, TeamMembers: map[string]core.Role{"member": core.RoleMember}}
+
+	t.Run("Success", func(t *testing.T) {
+		projects := new(MockProjectGetter)
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(proj, nil).Once()
+
+		got, err := Authorize(ctx, projects, "proj-1", "member", core.RoleMember)
+
+		require.NoError(t, err)
+		assert.Equal(t, proj, got)
+	})
+
+	t.Run("Failure_InsufficientRole", func(t *testing.T) {
+		projects := new(MockProjectGetter)
+		projects.On("GetProjectByID", ctx, "proj-1", "viewer").Return(proj, nil).Once()
+
+		_, err := Authorize(ctx, projects, "proj-1", "viewer", core.RoleMember)
+
+		assert.ErrorIs(t, err, core.ErrForbidden)
+	})
+
+	t.Run("Failure_AccessDenied", func(t *testing.T) {
+		projects := new(MockProjectGetter)
+		projects.On("GetProjectByID", ctx, "proj-1", "stranger").Return(nil, project.ErrProjectAccessDenied).Once()
+
+		_, err := Authorize(ctx, projects, "proj-1", "stranger", core.RoleViewer)
+
+		assert.ErrorIs(t, err, project.ErrProjectAccessDenied)
+	})
+
+	t.Run("Success_ArchivedProjectReadable", func(t *testing.T) {
+		projects := new(MockProjectGetter)
+		projects.On("GetProjectByID", ctx, "proj-2", "member").Return(archived, nil).Once()
+
+		_, err := Authorize(ctx, projects, "proj-2", "member", core.RoleViewer)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Failure_ChangeInArchivedProject", func(t *testing.T) {
+		projects := new(MockProjectGetter)
+		projects.On("GetProjectByID", ctx, "proj-2", "member").Return(archived, nil).Once()
+
+		_, err := AuthorizeChange(ctx, projects, "proj-2", "member", core.RoleMember, "widgets")
+
+		assert.ErrorIs(t, err, core.ErrProjectArchived)
+		assert.ErrorContains(t, err, "cannot change widgets in project proj-2")
+	})
+}
@@ -7,6 +7,7 @@ type JobStatus string
 
 const (
 	JobStatusPending   JobStatus = "pending"
+	JobStatusQueued    JobStatus = "queued" // Held back by a concurrency limit; submitted once capacity frees up
 	JobStatusRunning   JobStatus = "running"
 	JobStatusCompleted JobStatus = "completed"
 	JobStatusFailed    JobStatus = "failed"
@@ -15,17 +16,74 @@ const (
 
 // Job represents a data generation job instance.
 type Job struct {
-	ID            string     `firestore:"id,omitempty" json:"id"`                                 // Unique job identifier (e.g., UUID)
-	ProjectID     string     `firestore:"projectId" json:"projectId"`                             // ID of the project this job belongs to
-	UserID        string     `firestore:"userId" json:"userId"`                                   // ID of the user who created the job
-	Status        JobStatus  `firestore:"status" json:"status"`                                   // Current status of the job
-	JobType       string     `firestore:"jobType" json:"jobType"`                                 // Type of data generation (e.g., 'csv', 'json', 'sql')
-	JobConfig     string     `firestore:"jobConfig" json:"jobConfig"`                             // Configuration for the job (e.g., JSON string defining schema, rows)
-	CreatedAt     time.Time  `firestore:"createdAt" json:"createdAt"`                             // Timestamp when the job was created
-	UpdatedAt     time.Time  `firestore:"updatedAt" json:"updatedAt"`                             // Timestamp when the job was last updated
-	PipelineJobID string     `firestore:"pipelineJobId,omitempty" json:"pipelineJobId,omitempty"` // External pipeline's ID for this job
-	StartedAt     *time.Time `firestore:"startedAt,omitempty" json:"startedAt,omitempty"`         // Timestamp when the job started processing
-	CompletedAt   *time.Time `firestore:"completedAt,omitempty" json:"completedAt,omitempty"`     // Timestamp when the job finished (successfully or failed)
-	ResultURI     string     `firestore:"resultUri,omitempty" json:"resultUri,omitempty"`         // URI pointing to the generated data artifact (e.g., GCS path)
-	Error         string     `firestore:"error,omitempty" json:"error,omitempty"`                 // Error message if the job failed
+	ID              string     `firestore:"id,omitempty" json:"id"`                                     // Unique job identifier (e.g., UUID)
+	ProjectID       string     `firestore:"projectId" json:"projectId"`                                 // ID of the project this job belongs to
+	UserID          string     `firestore:"userId" json:"userId"`                                       // ID of the user who created the job
+	Status          JobStatus  `firestore:"status" json:"status"`                                       // Current status of the job
+	JobType         string     `firestore:"jobType" json:"jobType"`                                     // Type of data generation (e.g., 'csv', 'json', 'sql')
+	JobConfig       string     `firestore:"jobConfig" json:"jobConfig"`                                 // Configuration for the job (e.g., JSON string defining schema, rows)
+	CreatedAt       time.Time  `firestore:"createdAt" json:"createdAt"`                                 // Timestamp when the job was created
+	UpdatedAt       time.Time  `firestore:"updatedAt" json:"updatedAt"`                                 // Timestamp when the job was last updated
+	PipelineJobID   string     `firestore:"pipelineJobId,omitempty" json:"pipelineJobId,omitempty"`     // External pipeline's ID for this job
+	StartedAt       *time.Time `firestore:"startedAt,omitempty" json:"startedAt,omitempty"`             // Timestamp when the job started processing
+	CompletedAt     *time.Time `firestore:"completedAt,omitempty" json:"completedAt,omitempty"`         // Timestamp when the job finished (successfully or failed)
+	ResultURI       string     `firestore:"resultUri,omitempty" json:"resultUri,omitempty"`             // URI pointing to the generated data artifact (e.g., GCS path)
+	Error           string     `firestore:"error,omitempty" json:"error,omitempty"`                     // Error message if the job failed
+	ResultExpiredAt *time.Time `firestore:"resultExpiredAt,omitempty" json:"resultExpiredAt,omitempty"` // Set when data retention removed the result; ResultURI is kept for reference
+
+	// Lineage and automatic retries
+	RetryOf     string          `firestore:"retryOf,omitempty" json:"retryOf,omitempty"`         // Job this one retries
+	ClonedFrom  string          `firestore:"clonedFrom,omitempty" json:"clonedFrom,omitempty"`   // Job this one was cloned from
+	Attempt     int             `firestore:"attempt,omitempty" json:"attempt,omitempty"`         // 1 for a new job, one more for each retry
+	RetryPolicy *JobRetryPolicy `firestore:"retryPolicy,omitempty" json:"retryPolicy,omitempty"` // Automatic retries of failed attempts, if any
+	RetryAt     *time.Time      `firestore:"retryAt,omitempty" json:"retryAt,omitempty"`         // When an automatic retry is due to be submitted
+
+	// Template the job was created from, if any
+	TemplateID      string `firestore:"templateId,omitempty" json:"templateId,omitempty"`
+	TemplateVersion int    `firestore:"templateVersion,omitempty" json:"templateVersion,omitempty"` // Exact version used
+
+	// Schedule or workflow that created the job, if any
+	ScheduleID string `firestore:"scheduleId,omitempty" json:"scheduleId,omitempty"`
+	WorkflowID string `firestore:"workflowId,omitempty" json:"workflowId,omitempty"`
+
+	// Predicted output and cost at creation, and what the job took once completed
+	Estimate *JobEstimate `firestore:"estimate,omitempty" json:"estimate,omitempty"`
+	Actuals  *JobActuals  `firestore:"actuals,omitempty" json:"actuals,omitempty"`
+
+	// Set once a completed job's usage is recorded. Stored as null until then, so that
+	// unmetered jobs can be queried.
+	MeteredAt *time.Time `firestore:"meteredAt" json:"meteredAt,omitempty"`
+}
+
+// JobRetryPolicy configures automatic retries of a job whose pipeline run fails.
+type JobRetryPolicy struct {
+	MaxAttempts    int      `firestore:"maxAttempts" json:"maxAttempts"`             // Attempts including the first; 1 disables retries
+	BackoffSeconds int      `firestore:"backoffSeconds" json:"backoffSeconds"`       // Wait before the first retry, doubling for each later one
+	RetryOn        []string `firestore:"retryOn,omitempty" json:"retryOn,omitempty"` // Error classes to retry; defaults to transient errors
+}
+
+// JobEstimate predicts the output size, duration and compute cost of a job before it runs.
+type JobEstimate struct {
+	Records         int64      `firestore:"records" json:"records"`                               // Records the config asks for, over all tables
+	Columns         int        `firestore:"columns" json:"columns"`                               // Columns over all tables; 0 if the config has no schema
+	OutputBytes     int64      `firestore:"outputBytes" json:"outputBytes"`                       // Predicted size of the generated output
+	DurationSeconds float64    `firestore:"durationSeconds" json:"durationSeconds"`               // Predicted time from start to completion
+	ComputeCost     float64    `firestore:"computeCost" json:"computeCost"`                       // Predicted compute cost, in Currency
+	Currency        string     `firestore:"currency" json:"currency"`                             // ISO 4217 code
+	Basis           string     `firestore:"basis" json:"basis"`                                   // "calibrated" from completed jobs, or "default"
+	SampleSize      int        `firestore:"sampleSize,omitempty" json:"sampleSize,omitempty"`     // Completed jobs a calibrated model was fitted to
+	CalibratedAt    *time.Time `firestore:"calibratedAt,omitempty" json:"calibratedAt,omitempty"` // When a calibrated model was fitted
+}
+
+// JobActuals records what a completed job took, for comparison with its estimate.
+type JobActuals struct {
+	OutputBytes     int64   `firestore:"outputBytes,omitempty" json:"outputBytes,omitempty"` // 0 if the pipeline does not report output sizes
+	Records         int64   `firestore:"records,omitempty" json:"records,omitempty"`         // Rows generated; 0 if the pipeline does not report them
+	DurationSeconds float64 `firestore:"durationSeconds" json:"durationSeconds"`             // Time from start to completion
+	ComputeCost     float64 `firestore:"computeCost" json:"computeCost"`                     // Duration priced like the estimate, in Currency
+	Currency        string  `firestore:"currency" json:"currency"`
+
+	// Actual divided by estimated values, when the job has an estimate; 0 if either is unknown
+	DurationVsEstimate    float64 `firestore:"durationVsEstimate,omitempty" json:"durationVsEstimate,omitempty"`
+	OutputBytesVsEstimate float64 `firestore:"outputBytesVsEstimate,omitempty" json:"outputBytesVsEstimate,omitempty"`
 }
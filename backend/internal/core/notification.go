@@ -0,0 +1,61 @@
+package core
+
+import "time"
+
+// NotificationChannelType names a way of delivering notifications.
+type NotificationChannelType string
+
+const (
+	NotificationChannelEmail   NotificationChannelType = "email"   // Sent through the configured Mailer
+	NotificationChannelWebhook NotificationChannelType = "webhook" // JSON POSTed to an HTTPS URL, signed with HMAC-SHA256
+	NotificationChannelSlack   NotificationChannelType = "slack"   // Slack-compatible incoming webhook
+)
+
+// NotificationChannel is one destination of a notification preference.
+type NotificationChannel struct {
+	Type   NotificationChannelType `firestore:"type" json:"type"`
+	Target string                  `firestore:"target" json:"target"`      // Email address, or the webhook URL
+	Secret string                  `firestore:"secret,omitempty" json:"-"` // Webhook signing key; never returned
+}
+
+// NotificationPreference decides which job status changes are notified and where. A user's
+// preference covers the jobs they create; a project's covers every job in the project.
+type NotificationPreference struct {
+	ID        string                `firestore:"id,omitempty" json:"id"`
+	UserID    string                `firestore:"userId,omitempty" json:"userId,omitempty"`       // Set for a user's preference
+	ProjectID string                `firestore:"projectId,omitempty" json:"projectId,omitempty"` // Set for a project's preference
+	Enabled   bool                  `firestore:"enabled" json:"enabled"`
+	Statuses  []JobStatus           `firestore:"statuses" json:"statuses"` // Final job statuses that are notified
+	Channels  []NotificationChannel `firestore:"channels" json:"channels"`
+	UpdatedBy string                `firestore:"updatedBy" json:"updatedBy"`
+	UpdatedAt time.Time             `firestore:"updatedAt" json:"updatedAt"`
+}
+
+// NotificationDeliveryStatus is the state of one notification delivery.
+type NotificationDeliveryStatus string
+
+const (
+	NotificationDeliveryPending   NotificationDeliveryStatus = "pending"   // Waiting for its next attempt
+	NotificationDeliveryDelivered NotificationDeliveryStatus = "delivered" // Accepted by the channel
+	NotificationDeliveryFailed    NotificationDeliveryStatus = "failed"    // Gave up after the last attempt
+)
+
+// NotificationDelivery sends one job status change to one channel of a preference, and
+// logs its attempts. The channel is copied from the preference when the delivery is
+// created, so later preference changes do not affect it.
+type NotificationDelivery struct {
+	ID            string                     `firestore:"id,omitempty" json:"id"`
+	PreferenceID  string                     `firestore:"preferenceId" json:"preferenceId"`
+	ProjectID     string                     `firestore:"projectId,omitempty" json:"projectId,omitempty"`
+	JobID         string                     `firestore:"jobId,omitempty" json:"jobId,omitempty"` // Empty for test sends
+	JobStatus     JobStatus                  `firestore:"jobStatus,omitempty" json:"jobStatus,omitempty"`
+	Test          bool                       `firestore:"test,omitempty" json:"test,omitempty"` // Sent from the test endpoint; never retried
+	Channel       NotificationChannel        `firestore:"channel" json:"channel"`
+	Status        NotificationDeliveryStatus `firestore:"status" json:"status"`
+	Attempts      int                        `firestore:"attempts" json:"attempts"`
+	LastError     string                     `firestore:"lastError,omitempty" json:"lastError,omitempty"`
+	CreatedAt     time.Time                  `firestore:"createdAt" json:"createdAt"`
+	NextAttemptAt *time.Time                 `firestore:"nextAttemptAt,omitempty" json:"nextAttemptAt,omitempty"` // Set while pending
+	LastAttemptAt *time.Time                 `firestore:"lastAttemptAt,omitempty" json:"lastAttemptAt,omitempty"`
+	DeliveredAt   *time.Time                 `firestore:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
+}
@@ -12,10 +12,24 @@ const (
 	RoleViewer Role = "viewer" // Can view project, jobs, settings (read-only)
 )
 
+// Project lifecycle statuses. Archived projects are read-only: no new jobs,
+// job submissions or dataset uploads are accepted until they are unarchived.
+const (
+	ProjectStatusActive   = "active"
+	ProjectStatusArchived = "archived"
+)
+
+// JobOutputPrefix is the object prefix under which job outputs are written in a
+// project bucket (jobs/<jobID>/...). Everything else in the bucket is a dataset.
+const JobOutputPrefix = "jobs/"
+
 // ProjectSettings defines configurable settings for a project.
 type ProjectSettings struct {
-	DataRetentionDays int `json:"dataRetentionDays" firestore:"dataRetentionDays"`
-	MaxStorageGB      int `json:"maxStorageGB" firestore:"maxStorageGB"`
+	DataRetentionDays int `json:"dataRetentionDays" firestore:"dataRetentionDays"` // 0 keeps data forever
+	MaxStorageGB      int `json:"maxStorageGB" firestore:"maxStorageGB"`           // 0 means unlimited
+	// RequirePIIAcknowledgement blocks job submission while the input dataset's PII scan
+	// is incomplete or has unacknowledged high-severity findings.
+	RequirePIIAcknowledgement bool `json:"requirePiiAcknowledgement" firestore:"requirePiiAcknowledgement"`
 }
 
 // ProjectStorage details the Cloud Storage bucket associated with a project.
@@ -24,6 +38,8 @@ type ProjectStorage struct {
 	BucketURI        string `json:"bucketUri,omitempty" firestore:"bucketUri,omitempty"` // Added GCS URI (e.g., gs://bucket-name)
 	Region           string `json:"region" firestore:"region"`
 	UsedStorageBytes int64  `json:"usedStorageBytes" firestore:"usedStorageBytes"` // Updated periodically
+
+	SampledPeriod *time.Time `json:"-" firestore:"sampledPeriod,omitempty"` // Start of the last storage period billed by the usage meter
 }
 
 // TeamMember represents a user associated with a project and their role.
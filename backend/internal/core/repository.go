@@ -11,7 +11,15 @@ import (
 var (
 	ErrNotFound  = errors.New("resource not found")
 	ErrForbidden = errors.New("user does not have permission for this action")
-	// Add other common errors like ErrConflict, ErrBadRequest etc.
+	// ErrProjectArchived is returned when a write is attempted on an archived project.
+	ErrProjectArchived = errors.New("project is archived")
+	// ErrStorageQuotaExceeded is returned when a write would push a project past MaxStorageGB.
+	ErrStorageQuotaExceeded = errors.New("project storage quota exceeded")
+	// ErrNotSupported is returned by backends that do not implement an optional capability.
+	ErrNotSupported = errors.New("operation not supported by this backend")
+	// ErrConflict is returned when creating a resource whose ID is already taken.
+	ErrConflict = errors.New("resource already exists")
+	// Add other common errors like ErrBadRequest etc.
 )
 
 // UserRepository defines the interface for interacting with user data storage.
@@ -40,15 +48,31 @@ type ProjectRepository interface {
 	// Supports pagination via limit and offset.
 	ListProjects(ctx context.Context, customerID string, statusFilter string, limit, offset int) ([]*Project, error)
 
+	// ListProjectsByCustomer retrieves every project whose CustomerID is customerID,
+	// optionally filtered by status.
+	ListProjectsByCustomer(ctx context.Context, customerID string, statusFilter string) ([]*Project, error)
+
 	// CountProjects retrieves the total count of projects matching filters.
 	CountProjects(ctx context.Context, customerID string, statusFilter string) (int, error)
 
 	// UpdateProject updates an existing project.
 	UpdateProject(ctx context.Context, project *Project) error
 
+	// UpdateStorageUsage sets only the project's recorded storage usage.
+	// Returns ErrNotFound if the project does not exist.
+	UpdateStorageUsage(ctx context.Context, projectID string, usedBytes int64) error
+
+	// UpdateStorageSampledPeriod sets only the start of the last storage period the usage
+	// meter billed for the project. Returns ErrNotFound if the project does not exist.
+	UpdateStorageSampledPeriod(ctx context.Context, projectID string, period time.Time) error
+
 	// DeleteProject removes a project (or marks it as deleted).
 	// The implementation decides if this is a hard or soft delete.
 	DeleteProject(ctx context.Context, id string) error
+
+	// ListAllProjects retrieves every project regardless of membership or status.
+	// Intended for background maintenance such as the retention sweeper.
+	ListAllProjects(ctx context.Context) ([]*Project, error)
 }
 
 // JobRepository defines the interface for data access operations related to Jobs.
@@ -66,16 +90,167 @@ type JobRepository interface {
 	// UpdateJobStatus updates the status and potentially timestamps and pipeline ID of a job.
 	UpdateJobStatus(ctx context.Context, jobID string, newStatus JobStatus, pipelineJobID string, startedAt, completedAt *time.Time, jobError string) error
 
+	// TransitionJobStatus updates a job's status like UpdateJobStatus, provided it is still
+	// fromStatus. Returns ErrConflict if another writer changed the status first.
+	TransitionJobStatus(ctx context.Context, jobID string, fromStatus, newStatus JobStatus, pipelineJobID string, startedAt, completedAt *time.Time, jobError string) error
+
 	// UpdateJobResult updates the result URI of a completed job.
 	UpdateJobResult(ctx context.Context, jobID string, resultURI string) error
 
+	// UpdateJobActuals records what a completed job took, for comparison with its estimate.
+	UpdateJobActuals(ctx context.Context, jobID string, actuals *JobActuals) error
+
+	// MarkJobResultExpired records that a job's result was removed by data retention.
+	MarkJobResultExpired(ctx context.Context, jobID string, expiredAt time.Time) error
+
+	// MarkJobMetered records that a completed job's usage was recorded.
+	MarkJobMetered(ctx context.Context, jobID string, meteredAt time.Time) error
+
+	// ListUnmeteredJobs retrieves up to limit completed jobs whose usage is not yet recorded.
+	ListUnmeteredJobs(ctx context.Context, limit int) ([]*Job, error)
+
 	// ListJobsAcrossProjects retrieves jobs from a list of specified project IDs.
 	// Supports filtering and pagination across the combined set of projects.
 	ListJobsAcrossProjects(ctx context.Context, projectIDs []string, statusFilter string, limit, offset int) ([]*Job, int, error) // Returns jobs, total count, error
 
+	// ListJobsByStatus retrieves up to limit jobs with the given status, ordered by ID and
+	// starting after the job startAfterID (from the beginning if empty).
+	ListJobsByStatus(ctx context.Context, status JobStatus, limit int, startAfterID string) ([]*Job, error)
+
+	// CountJobs counts the jobs with the given status across the specified projects.
+	CountJobs(ctx context.Context, projectIDs []string, status JobStatus) (int, error)
+
 	// TODO: Consider adding methods for advanced filtering or deletion if required.
 }
 
+// JobTemplateRepository defines the interface for data access operations related to job templates.
+type JobTemplateRepository interface {
+	// SaveTemplateVersion stores template as the template's current state and as an immutable
+	// snapshot of template.Version. Returns ErrConflict if that version already exists.
+	SaveTemplateVersion(ctx context.Context, template *JobTemplate) error
+
+	// GetTemplate retrieves the current version of a template.
+	GetTemplate(ctx context.Context, templateID string) (*JobTemplate, error)
+
+	// GetTemplateVersion retrieves a specific version of a template. Versions outlive the
+	// template itself, so jobs created from a deleted template stay traceable.
+	GetTemplateVersion(ctx context.Context, templateID string, version int) (*JobTemplate, error)
+
+	// ListTemplatesByProjectID retrieves the current version of a project's templates, ordered by name.
+	ListTemplatesByProjectID(ctx context.Context, projectID string, limit, offset int) ([]*JobTemplate, int, error) // Returns templates, total count, error
+
+	// DeleteTemplate removes a template, keeping its versions.
+	DeleteTemplate(ctx context.Context, templateID string) error
+}
+
+// ScheduleRepository defines the interface for data access operations related to job schedules.
+type ScheduleRepository interface {
+	// CreateSchedule persists a new schedule.
+	CreateSchedule(ctx context.Context, schedule *Schedule) error
+
+	// GetSchedule retrieves a schedule by its ID. Returns ErrNotFound if it does not exist.
+	GetSchedule(ctx context.Context, scheduleID string) (*Schedule, error)
+
+	// ListSchedulesByProjectID retrieves a project's schedules ordered by name.
+	ListSchedulesByProjectID(ctx context.Context, projectID string, limit, offset int) ([]*Schedule, int, error) // Returns schedules, total count, error
+
+	// UpdateSchedule replaces the user-editable fields of a schedule and its NextRunAt,
+	// leaving the run state recorded by the scheduler untouched.
+	UpdateSchedule(ctx context.Context, schedule *Schedule) error
+
+	// DeleteSchedule removes a schedule. Returns ErrNotFound if it does not exist.
+	DeleteSchedule(ctx context.Context, scheduleID string) error
+
+	// ListDueSchedules retrieves enabled schedules whose NextRunAt is at or before now,
+	// earliest first.
+	ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*Schedule, error)
+
+	// ClaimScheduleRun atomically moves a schedule's NextRunAt from due to next. Returns
+	// ErrConflict if NextRunAt is no longer due, because the run was claimed elsewhere or the
+	// schedule was changed, so each run is started at most once.
+	ClaimScheduleRun(ctx context.Context, scheduleID string, due time.Time, next *time.Time) error
+
+	// RecordScheduleRun stores the outcome of a run as the schedule's last run.
+	RecordScheduleRun(ctx context.Context, scheduleID string, run ScheduleRun) error
+}
+
+// WorkflowRepository defines the interface for data access operations related to workflows.
+type WorkflowRepository interface {
+	// CreateWorkflow persists a new workflow.
+	CreateWorkflow(ctx context.Context, workflow *Workflow) error
+
+	// GetWorkflow retrieves a workflow by its ID. Returns ErrNotFound if it does not exist.
+	GetWorkflow(ctx context.Context, workflowID string) (*Workflow, error)
+
+	// ListWorkflowsByProjectID retrieves a project's workflows, newest first.
+	ListWorkflowsByProjectID(ctx context.Context, projectID string, limit, offset int) ([]*Workflow, int, error) // Returns workflows, total count, error
+
+	// ListActiveWorkflows retrieves pending and running workflows across all projects,
+	// oldest first.
+	ListActiveWorkflows(ctx context.Context, limit int) ([]*Workflow, error)
+
+	// SaveWorkflowState stores a workflow's status, progress, error, steps and timestamps.
+	// Returns ErrConflict if the stored workflow's UpdatedAt is no longer prevUpdatedAt,
+	// because another writer saved it first.
+	SaveWorkflowState(ctx context.Context, workflow *Workflow, prevUpdatedAt time.Time) error
+
+	// DeleteWorkflow removes a workflow. Returns ErrNotFound if it does not exist.
+	DeleteWorkflow(ctx context.Context, workflowID string) error
+}
+
+// LeaseRepository grants named, time-limited leases so that only one replica performs a
+// periodic task at a time.
+type LeaseRepository interface {
+	// AcquireLease grants or renews the lease for holder until ttl from now. It returns false
+	// without error while another holder's lease is unexpired.
+	AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+}
+
+// RateLimitRepository counts events in fixed time windows, shared by every replica.
+type RateLimitRepository interface {
+	// TakeRateLimitToken counts one event for key in the window of the given length starting
+	// at windowStart, unless limit events were already counted there. It reports whether the
+	// event was counted.
+	TakeRateLimitToken(ctx context.Context, key string, windowStart time.Time, window time.Duration, limit int) (bool, error)
+}
+
+// UsageRepository stores metered usage events.
+type UsageRepository interface {
+	// RecordUsage stores a usage event under its ID. It returns false without error if an
+	// event with that ID was already recorded.
+	RecordUsage(ctx context.Context, event *UsageEvent) (bool, error)
+
+	// ListUsage retrieves the usage events of the given projects that occurred in [from, to).
+	ListUsage(ctx context.Context, projectIDs []string, from, to time.Time) ([]*UsageEvent, error)
+}
+
+// NotificationRepository stores notification preferences and the log of their deliveries.
+type NotificationRepository interface {
+	// GetPreference retrieves a preference by its ID. Returns ErrNotFound if it does not exist.
+	GetPreference(ctx context.Context, preferenceID string) (*NotificationPreference, error)
+
+	// SavePreference creates or replaces a preference.
+	SavePreference(ctx context.Context, pref *NotificationPreference) error
+
+	// DeletePreference removes a preference, keeping its deliveries. Returns ErrNotFound if it
+	// does not exist.
+	DeletePreference(ctx context.Context, preferenceID string) error
+
+	// CreateDelivery stores a new delivery under its ID. It returns false without error if a
+	// delivery with that ID already exists.
+	CreateDelivery(ctx context.Context, delivery *NotificationDelivery) (bool, error)
+
+	// UpdateDelivery records the outcome of a delivery attempt.
+	UpdateDelivery(ctx context.Context, delivery *NotificationDelivery) error
+
+	// ListDueDeliveries retrieves up to limit pending deliveries whose next attempt is due at
+	// or before now, oldest first.
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*NotificationDelivery, error)
+
+	// ListDeliveries retrieves a preference's deliveries, newest first.
+	ListDeliveries(ctx context.Context, preferenceID string, limit, offset int) ([]*NotificationDelivery, int, error) // Returns deliveries, total count, error
+}
+
 // ObjectSummary contains basic information about a storage object.
 type ObjectSummary struct {
 	Name        string    `json:"name"`
@@ -84,6 +259,34 @@ type ObjectSummary struct {
 	URI         string    `json:"uri"`         // Full gs:// URI
 }
 
+// ListObjectsOptions controls a paginated, optionally hierarchical object listing.
+type ListObjectsOptions struct {
+	Prefix    string // Only list objects whose names begin with Prefix
+	Delimiter string // If set (usually "/"), names with the delimiter after Prefix are rolled up into Prefixes
+	PageSize  int    // Maximum number of objects plus prefixes per page
+	PageToken string // Token from a previous ObjectPage; empty for the first page
+}
+
+// ObjectPage is a single page of an object listing.
+type ObjectPage struct {
+	Objects       []ObjectSummary
+	Prefixes      []string // Common prefixes ("folders"), only populated when a delimiter is set
+	NextPageToken string   // Empty when there are no more results
+}
+
+// ObjectMetadata contains the full attributes of a single storage object.
+type ObjectMetadata struct {
+	Name        string            `json:"name"`
+	Size        int64             `json:"size"` // Size in bytes
+	ContentType string            `json:"contentType"`
+	MD5         string            `json:"md5,omitempty"` // Hex-encoded; empty for composite objects
+	CRC32C      uint32            `json:"crc32c"`
+	Created     time.Time         `json:"created"`
+	Updated     time.Time         `json:"updated"`
+	Metadata    map[string]string `json:"metadata,omitempty"` // Custom key/value metadata
+	URI         string            `json:"uri"`                // Full gs:// URI
+}
+
 // StorageService defines the interface for interacting with object storage.
 type StorageService interface {
 	// CreateProjectBucket creates a dedicated storage bucket for a project.
@@ -94,19 +297,53 @@ type StorageService interface {
 	// Returns the GCS URI (gs://bucket/object) of the uploaded file or an error.
 	UploadFile(ctx context.Context, bucketName, objectName string, reader io.Reader) (uri string, err error)
 
+	// UploadNewFile uploads like UploadFile but fails with ErrConflict, writing nothing,
+	// if the object already exists.
+	UploadNewFile(ctx context.Context, bucketName, objectName string, reader io.Reader) (uri string, err error)
+
 	// ListObjects lists objects within a bucket, potentially with a prefix.
 	ListObjects(ctx context.Context, bucketName, prefix string) ([]ObjectSummary, error)
 
+	// ListObjectsPage lists a single page of objects and, with a delimiter, common prefixes.
+	ListObjectsPage(ctx context.Context, bucketName string, opts ListObjectsOptions) (*ObjectPage, error)
+
 	// ReadObject reads the content of a specific object from a bucket.
 	// Returns the object content as bytes or an error (e.g., ErrNotFound).
 	ReadObject(ctx context.Context, bucketName, objectName string) ([]byte, error)
 
+	// OpenObject returns a streaming reader for an object and its size in bytes (-1 if unknown).
+	// The caller must close the reader. Returns ErrNotFound if the object does not exist.
+	OpenObject(ctx context.Context, bucketName, objectName string) (reader io.ReadCloser, size int64, err error)
+
+	// OpenObjectReaderAt returns random access to an object, for formats such as Parquet
+	// that read a footer and then selected byte ranges. The reader is bound to ctx.
+	OpenObjectReaderAt(ctx context.Context, bucketName, objectName string) (reader io.ReaderAt, size int64, err error)
+
+	// GetObjectMetadata returns the attributes of a single object.
+	// Returns ErrNotFound if the object does not exist.
+	GetObjectMetadata(ctx context.Context, bucketName, objectName string) (*ObjectMetadata, error)
+
+	// UpdateObjectMetadata merges the given key/value pairs into the object's custom metadata.
+	// Returns ErrNotFound if the object does not exist.
+	UpdateObjectMetadata(ctx context.Context, bucketName, objectName string, metadata map[string]string) error
+
+	// CopyObject copies an object, including its custom metadata, to a new name in the same bucket.
+	// Returns ErrNotFound if the source object does not exist.
+	CopyObject(ctx context.Context, bucketName, srcObjectName, dstObjectName string) error
+
+	// DeleteObject removes a single object from a bucket.
+	// Returns ErrNotFound if the object does not exist.
+	DeleteObject(ctx context.Context, bucketName, objectName string) error
+
+	// SetBucketRetention configures the bucket to delete objects older than retentionDays.
+	// A retentionDays of 0 removes the rule. Backends without lifecycle support return
+	// ErrNotSupported and rely on the retention sweeper instead.
+	SetBucketRetention(ctx context.Context, bucketName string, retentionDays int) error
+
 	// DeleteProjectBucket removes the storage bucket associated with a project.
 	// Force delete should remove contents first if necessary.
 	DeleteProjectBucket(ctx context.Context, bucketName string, force bool) error
 
 	// Close cleans up any underlying resources used by the storage service.
 	Close() error
-
-	// TODO: Add other methods as needed (e.g., SetBucketLifecycle, GetObjectMetadata)
 }
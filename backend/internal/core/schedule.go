@@ -0,0 +1,59 @@
+package core
+
+import "time"
+
+// ScheduleOverlapPolicy decides what a schedule does when it is due while the job from its
+// previous run is still pending or running.
+type ScheduleOverlapPolicy string
+
+const (
+	ScheduleOverlapSkip           ScheduleOverlapPolicy = "skip"            // Skip this run
+	ScheduleOverlapQueue          ScheduleOverlapPolicy = "queue"           // Run once the previous job finishes
+	ScheduleOverlapCancelPrevious ScheduleOverlapPolicy = "cancel-previous" // Cancel the previous job, then run
+)
+
+// Outcomes of a schedule's most recent run.
+const (
+	ScheduleRunSubmitted = "submitted"
+	ScheduleRunSkipped   = "skipped"
+	ScheduleRunFailed    = "failed"
+)
+
+// Schedule creates and submits a job on a cron schedule. The job is built either from a
+// job template or from a job type and config stored on the schedule.
+type Schedule struct {
+	ID            string                `firestore:"id,omitempty" json:"id"`
+	ProjectID     string                `firestore:"projectId" json:"projectId"`
+	Name          string                `firestore:"name" json:"name"`
+	Cron          string                `firestore:"cron" json:"cron"`         // Five-field cron expression or a descriptor such as @daily
+	Timezone      string                `firestore:"timezone" json:"timezone"` // IANA name the cron expression is evaluated in
+	Enabled       bool                  `firestore:"enabled" json:"enabled"`
+	OverlapPolicy ScheduleOverlapPolicy `firestore:"overlapPolicy" json:"overlapPolicy"`
+	CreatedBy     string                `firestore:"createdBy" json:"createdBy"`
+	UpdatedBy     string                `firestore:"updatedBy" json:"updatedBy"` // Jobs are created on behalf of this user
+	CreatedAt     time.Time             `firestore:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time             `firestore:"updatedAt" json:"updatedAt"`
+
+	// What to run: a template, or a job type and config
+	TemplateID        string                 `firestore:"templateId,omitempty" json:"templateId,omitempty"`
+	TemplateVersion   int                    `firestore:"templateVersion,omitempty" json:"templateVersion,omitempty"` // 0 uses the current version
+	TemplateVariables map[string]interface{} `firestore:"templateVariables,omitempty" json:"templateVariables,omitempty"`
+	JobType           string                 `firestore:"jobType,omitempty" json:"jobType,omitempty"`
+	JobConfig         string                 `firestore:"jobConfig,omitempty" json:"jobConfig,omitempty"`
+	RetryPolicy       *JobRetryPolicy        `firestore:"retryPolicy,omitempty" json:"retryPolicy,omitempty"`
+
+	// Run state, maintained by the scheduler
+	NextRunAt     *time.Time `firestore:"nextRunAt" json:"nextRunAt,omitempty"` // Nil while disabled
+	LastRunAt     *time.Time `firestore:"lastRunAt,omitempty" json:"lastRunAt,omitempty"`
+	LastRunStatus string     `firestore:"lastRunStatus,omitempty" json:"lastRunStatus,omitempty"` // One of the ScheduleRun* outcomes
+	LastRunError  string     `firestore:"lastRunError,omitempty" json:"lastRunError,omitempty"`
+	LastJobID     string     `firestore:"lastJobId,omitempty" json:"lastJobId,omitempty"`
+}
+
+// ScheduleRun records the outcome of one run of a schedule.
+type ScheduleRun struct {
+	RanAt  time.Time
+	Status string // One of the ScheduleRun* outcomes
+	Error  string
+	JobID  string // Empty unless a job was created
+}
@@ -0,0 +1,36 @@
+package core
+
+import "time"
+
+// Types of job template variables.
+const (
+	TemplateVariableString  = "string"
+	TemplateVariableNumber  = "number"
+	TemplateVariableInteger = "integer"
+	TemplateVariableBoolean = "boolean"
+)
+
+// TemplateVariable declares a {{name}} placeholder in a job template's config.
+type TemplateVariable struct {
+	Name        string      `firestore:"name" json:"name"`
+	Type        string      `firestore:"type" json:"type"` // One of the TemplateVariable* types
+	Description string      `firestore:"description,omitempty" json:"description,omitempty"`
+	Default     interface{} `firestore:"default,omitempty" json:"default,omitempty"` // Variables without a default must be supplied
+}
+
+// JobTemplate is a reusable job definition scoped to a project. Every change creates a new
+// version; jobs created from a template record the version they were created from.
+type JobTemplate struct {
+	ID          string             `firestore:"id,omitempty" json:"id"`
+	ProjectID   string             `firestore:"projectId" json:"projectId"`
+	Name        string             `firestore:"name" json:"name"`
+	Description string             `firestore:"description,omitempty" json:"description,omitempty"`
+	JobType     string             `firestore:"jobType" json:"jobType"`
+	JobConfig   string             `firestore:"jobConfig" json:"jobConfig"` // JSON config with {{name}} placeholders
+	Variables   []TemplateVariable `firestore:"variables" json:"variables"`
+	Version     int                `firestore:"version" json:"version"` // Starts at 1, incremented by every update
+	CreatedBy   string             `firestore:"createdBy" json:"createdBy"`
+	UpdatedBy   string             `firestore:"updatedBy" json:"updatedBy"` // Author of this version
+	CreatedAt   time.Time          `firestore:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time          `firestore:"updatedAt" json:"updatedAt"` // When this version was saved
+}
@@ -0,0 +1,28 @@
+package core
+
+import "time"
+
+// UsageMetric names a metered quantity.
+type UsageMetric string
+
+const (
+	UsageRecordsGenerated UsageMetric = "recordsGenerated" // Records written by a completed job
+	UsageComputeSeconds   UsageMetric = "computeSeconds"   // Pipeline run time of a completed job
+	UsageStorageByteHours UsageMetric = "storageByteHours" // Stored bytes multiplied by the hours they were stored
+	UsageEgressBytes      UsageMetric = "egressBytes"      // Bytes sent to clients by download endpoints
+)
+
+// UsageEvent records a quantity of one metric used by a project. Events are written once
+// and never updated; events whose ID is derived from what they meter (a job, a storage
+// sampling period) are recorded at most once however often they are reported.
+type UsageEvent struct {
+	ID         string      `firestore:"id,omitempty" json:"id"`
+	CustomerID string      `firestore:"customerId" json:"customerId"` // Customer billed for the project when the usage occurred
+	ProjectID  string      `firestore:"projectId" json:"projectId"`
+	JobID      string      `firestore:"jobId,omitempty" json:"jobId,omitempty"`     // Set for job usage
+	JobType    string      `firestore:"jobType,omitempty" json:"jobType,omitempty"` // Set for job usage
+	Metric     UsageMetric `firestore:"metric" json:"metric"`
+	Quantity   float64     `firestore:"quantity" json:"quantity"`
+	OccurredAt time.Time   `firestore:"occurredAt" json:"occurredAt"` // When the usage happened; reports filter on this
+	RecordedAt time.Time   `firestore:"recordedAt" json:"recordedAt"`
+}
@@ -0,0 +1,38 @@
+package core
+
+import "time"
+
+// Workflow runs a set of job steps in dependency order. A step is started once every step it
+// depends on has completed, and can read their results. Workflows and their steps move
+// through the same statuses as jobs.
+type Workflow struct {
+	ID          string         `firestore:"id,omitempty" json:"id"`
+	ProjectID   string         `firestore:"projectId" json:"projectId"`
+	Name        string         `firestore:"name" json:"name"`
+	CreatedBy   string         `firestore:"createdBy" json:"createdBy"` // Step jobs are created on behalf of this user
+	Status      JobStatus      `firestore:"status" json:"status"`
+	Progress    int            `firestore:"progress" json:"progress"` // Percentage, averaged over the steps
+	Steps       []WorkflowStep `firestore:"steps" json:"steps"`       // In dependency order
+	Error       string         `firestore:"error,omitempty" json:"error,omitempty"`
+	CreatedAt   time.Time      `firestore:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time      `firestore:"updatedAt" json:"updatedAt"`
+	CompletedAt *time.Time     `firestore:"completedAt,omitempty" json:"completedAt,omitempty"`
+}
+
+// WorkflowStep is one job of a workflow.
+type WorkflowStep struct {
+	Name        string          `firestore:"name" json:"name"`
+	JobType     string          `firestore:"jobType" json:"jobType"`
+	JobConfig   string          `firestore:"jobConfig" json:"jobConfig"` // May read upstream results through {{steps.<name>.resultUri}}
+	DependsOn   []string        `firestore:"dependsOn,omitempty" json:"dependsOn,omitempty"`
+	RetryPolicy *JobRetryPolicy `firestore:"retryPolicy,omitempty" json:"retryPolicy,omitempty"`
+
+	// Run state, maintained by the orchestrator
+	Status      JobStatus  `firestore:"status" json:"status"`
+	JobID       string     `firestore:"jobId,omitempty" json:"jobId,omitempty"` // Latest attempt, following automatic retries
+	Progress    int        `firestore:"progress" json:"progress"`               // Percentage
+	ResultURI   string     `firestore:"resultUri,omitempty" json:"resultUri,omitempty"`
+	Error       string     `firestore:"error,omitempty" json:"error,omitempty"`
+	StartedAt   *time.Time `firestore:"startedAt,omitempty" json:"startedAt,omitempty"`
+	CompletedAt *time.Time `firestore:"completedAt,omitempty" json:"completedAt,omitempty"`
+}
@@ -0,0 +1,165 @@
+package events
+
+import (
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultSubscriptionBuffer is how many undelivered events a subscriber may fall behind by
+// before it is dropped.
+const DefaultSubscriptionBuffer = 64
+
+// Delays between attempts to reconnect to a failed broker.
+const (
+	minBrokerRetryDelay = time.Second
+	maxBrokerRetryDelay = 30 * time.Second
+)
+
+// Broker fans events out across replicas. Every replica's Bus publishes to the broker and
+// receives all events, including its own, through Subscribe.
+type Broker interface {
+	// Publish sends an event to every subscribed replica.
+	Publish(ctx context.Context, event Event) error
+
+	// Subscribe calls deliver for each event published after it starts, blocking until
+	// ctx is cancelled or the subscription fails.
+	Subscribe(ctx context.Context, deliver func(Event)) error
+}
+
+// Bus delivers published events to the subscribers of this process. Without a broker
+// events stay in the process; with one they reach the subscribers of every replica.
+type Bus struct {
+	broker Broker
+	now    func() time.Time // Overridable for tests
+
+	mu   sync.RWMutex
+	subs map[*Subscription]struct{}
+}
+
+// Ensure Bus satisfies the publisher interface.
+var _ Publisher = (*Bus)(nil)
+
+// NewBus creates an event bus. broker may be nil for a single-replica deployment; otherwise
+// Run must be started to receive events.
+func NewBus(broker Broker) *Bus {
+	return &Bus{
+		broker: broker,
+		now:    func() time.Time { return time.Now().UTC() },
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish stamps an event and delivers it. If the broker rejects the event it is still
+// delivered to this replica's subscribers.
+func (b *Bus) Publish(ctx context.Context, event Event) {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = b.now()
+	}
+	if b.broker == nil {
+		b.deliver(event)
+		return
+	}
+	if err := b.broker.Publish(ctx, event); err != nil {
+		logger.Logger.Warn("Failed to publish event to broker, delivering locally only",
+			zap.String("eventType", event.Type),
+			zap.String("jobID", event.JobID),
+			zap.Error(err),
+		)
+		b.deliver(event)
+	}
+}
+
+// Run receives events from the broker until ctx is cancelled, reconnecting with backoff
+// when the subscription fails. Events published while disconnected are not replayed.
+func (b *Bus) Run(ctx context.Context) {
+	if b.broker == nil {
+		return
+	}
+	logger.Logger.Info("Event bus subscribed to broker")
+	delay := minBrokerRetryDelay
+	for {
+		started := b.now()
+		err := b.broker.Subscribe(ctx, b.deliver)
+		if ctx.Err() != nil {
+			logger.Logger.Info("Event bus stopped")
+			return
+		}
+		if b.now().Sub(started) > maxBrokerRetryDelay {
+			delay = minBrokerRetryDelay // The subscription was healthy for a while
+		}
+		logger.Logger.Error("Event broker subscription failed, reconnecting", zap.Duration("retryIn", delay), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxBrokerRetryDelay)
+	}
+}
+
+// Subscribe registers a subscriber that receives every event delivered from now on. A
+// subscriber more than buffer events behind is dropped and its Done channel closed.
+func (b *Bus) Subscribe(buffer int) *Subscription {
+	if buffer <= 0 {
+		buffer = DefaultSubscriptionBuffer
+	}
+	ch := make(chan Event, buffer)
+	sub := &Subscription{C: ch, ch: ch, done: make(chan struct{}), bus: b}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	return sub
+}
+
+// deliver hands an event to every subscriber without blocking on slow ones.
+func (b *Bus) deliver(event Event) {
+	var slow []*Subscription
+	b.mu.RLock()
+	for sub := range b.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	b.mu.RUnlock()
+	for _, sub := range slow {
+		logger.Logger.Warn("Dropping event subscriber that fell behind", zap.Int("buffer", cap(sub.ch)))
+		sub.Close()
+	}
+}
+
+// Subscription is one subscriber's view of a Bus.
+type Subscription struct {
+	// C receives events in the order they were delivered.
+	C <-chan Event
+
+	ch   chan Event
+	done chan struct{}
+	once sync.Once
+	bus  *Bus
+}
+
+// Done is closed once the subscription ends, either through Close or because the
+// subscriber fell behind.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// Close unsubscribes. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		s.bus.mu.Unlock()
+		close(s.done)
+	})
+}
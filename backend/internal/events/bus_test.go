@@ -0,0 +1,152 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBroker is an in-memory Broker shared by several buses, like replicas sharing Firestore.
+type fakeBroker struct {
+	mu         sync.Mutex
+	subs       []func(Event)
+	publishErr error
+	subscribed chan struct{}
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{subscribed: make(chan struct{}, 10)}
+}
+
+func (b *fakeBroker) Publish(ctx context.Context, event Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.publishErr != nil {
+		return b.publishErr
+	}
+	for _, deliver := range b.subs {
+		deliver(event)
+	}
+	return nil
+}
+
+func (b *fakeBroker) Subscribe(ctx context.Context, deliver func(Event)) error {
+	b.mu.Lock()
+	b.subs = append(b.subs, deliver)
+	b.mu.Unlock()
+	b.subscribed <- struct{}{}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func receive(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event := <-sub.C:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return Event{}
+	}
+}
+
+func TestBus_Local(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_FansOutToSubscribers", func(t *testing.T) {
+		bus := NewBus(nil)
+		first, second := bus.Subscribe(0), bus.Subscribe(0)
+		defer first.Close()
+		defer second.Close()
+
+		bus.Publish(ctx, Event{Type: TypeJobStatus, JobID: "job-1"})
+
+		for _, sub := range []*Subscription{first, second} {
+			event := receive(t, sub)
+			assert.Equal(t, "job-1", event.JobID)
+			assert.NotEmpty(t, event.ID, "events are stamped with an ID")
+			assert.False(t, event.OccurredAt.IsZero())
+		}
+	})
+
+	t.Run("Success_ClosedSubscriptionStopsReceiving", func(t *testing.T) {
+		bus := NewBus(nil)
+		sub := bus.Subscribe(1)
+		sub.Close()
+		sub.Close() // Idempotent
+
+		bus.Publish(ctx, Event{Type: TypeJobStatus})
+
+		assert.Empty(t, sub.C)
+		assert.Empty(t, bus.subs)
+	})
+
+	t.Run("Failure_SlowSubscriberIsDropped", func(t *testing.T) {
+		bus := NewBus(nil)
+		slow := bus.Subscribe(2)
+		fast := bus.Subscribe(10)
+		defer fast.Close()
+
+		for range 3 {
+			bus.Publish(ctx, Event{Type: TypeJobProgress})
+		}
+
+		select {
+		case <-slow.Done():
+		default:
+			t.Fatal("a subscriber that fell behind is dropped")
+		}
+		assert.Len(t, fast.C, 3, "other subscribers are unaffected")
+	})
+}
+
+func TestBus_Broker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	broker := newFakeBroker()
+	replicaA, replicaB := NewBus(broker), NewBus(broker)
+	go replicaA.Run(ctx)
+	go replicaB.Run(ctx)
+	<-broker.subscribed
+	<-broker.subscribed
+	subA, subB := replicaA.Subscribe(0), replicaB.Subscribe(0)
+
+	t.Run("Success_ReachesEveryReplica", func(t *testing.T) {
+		replicaA.Publish(ctx, Event{Type: TypeJobStatus, JobID: "job-1"})
+
+		assert.Equal(t, "job-1", receive(t, subA).JobID)
+		assert.Equal(t, "job-1", receive(t, subB).JobID)
+	})
+
+	t.Run("Failure_BrokerDownDeliversLocally", func(t *testing.T) {
+		broker.mu.Lock()
+		broker.publishErr = errors.New("broker unavailable")
+		broker.mu.Unlock()
+
+		replicaA.Publish(ctx, Event{Type: TypeJobStatus, JobID: "job-2"})
+
+		assert.Equal(t, "job-2", receive(t, subA).JobID)
+		assert.Empty(t, subB.C)
+	})
+
+	t.Run("Success_RunStopsWithContext", func(t *testing.T) {
+		done := make(chan struct{})
+		runCtx, stop := context.WithCancel(ctx)
+		go func() {
+			NewBus(broker).Run(runCtx)
+			close(done)
+		}()
+		<-broker.subscribed
+		stop()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			require.Fail(t, "Run did not return after cancellation")
+		}
+	})
+}
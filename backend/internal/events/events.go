@@ -0,0 +1,45 @@
+package events
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"time"
+)
+
+// Event types pushed to clients.
+const (
+	TypeJobStatus   = "job.status"   // A job moved to a new status
+	TypeJobProgress = "job.progress" // A running job reported more progress
+	TypeJobResult   = "job.result"   // A completed job's result location was recorded
+)
+
+// Event is a change to a job, scoped to the project it belongs to.
+type Event struct {
+	ID         string         `firestore:"id" json:"id"`
+	Type       string         `firestore:"type" json:"type"`
+	ProjectID  string         `firestore:"projectId" json:"projectId"`
+	JobID      string         `firestore:"jobId" json:"jobId"`
+	Status     core.JobStatus `firestore:"status,omitempty" json:"status,omitempty"`
+	Progress   *int           `firestore:"progress,omitempty" json:"progress,omitempty"` // Percentage, for job.progress
+	ResultURI  string         `firestore:"resultUri,omitempty" json:"resultUri,omitempty"`
+	Error      string         `firestore:"error,omitempty" json:"error,omitempty"`
+	OccurredAt time.Time      `firestore:"occurredAt" json:"occurredAt"`
+}
+
+// JobStatusEvent describes a job's current status.
+func JobStatusEvent(job *core.Job) Event {
+	return Event{Type: TypeJobStatus, ProjectID: job.ProjectID, JobID: job.ID, Status: job.Status, Error: job.Error}
+}
+
+// Publisher accepts events for delivery. Publishing never fails the caller: delivery
+// problems are logged by the implementation.
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// Discard is a Publisher that drops every event.
+var Discard Publisher = discard{}
+
+type discard struct{}
+
+func (discard) Publish(context.Context, Event) {}
@@ -0,0 +1,146 @@
+package events
+
+import (
+	"SynDataGen/backend/internal/auth"
+	"SynDataGen/backend/internal/platform/logger"
+	"SynDataGen/backend/internal/project"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Event stream timings: how often an idle stream sends a keepalive comment, how long the
+// caller's project list is trusted, and how often an event from an unknown project may
+// trigger an early reload of it.
+const (
+	defaultKeepAliveInterval = 15 * time.Second
+	defaultProjectsMaxAge    = time.Minute
+	defaultProjectsMinAge    = 5 * time.Second
+)
+
+// ProjectLister lists the projects a user can view.
+// project.ProjectService satisfies this interface.
+type ProjectLister interface {
+	ListProjects(ctx context.Context, userID string, statusFilter string, limit, offset int) (*project.ListProjectsResponse, error)
+}
+
+// Handler streams bus events to clients as Server-Sent Events.
+type Handler struct {
+	bus               *Bus
+	projects          ProjectLister
+	keepAliveInterval time.Duration
+	projectsMaxAge    time.Duration
+	projectsMinAge    time.Duration
+}
+
+// NewHandler creates a new event stream Handler.
+func NewHandler(bus *Bus, projects ProjectLister) *Handler {
+	return &Handler{
+		bus:               bus,
+		projects:          projects,
+		keepAliveInterval: defaultKeepAliveInterval,
+		projectsMaxAge:    defaultProjectsMaxAge,
+		projectsMinAge:    defaultProjectsMinAge,
+	}
+}
+
+// RegisterRoutes registers the event stream route with the Gin router group.
+func (h *Handler) RegisterRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	rg.GET("/events", authMiddleware, h.StreamEvents) // GET /api/v1/events
+}
+
+// StreamEvents handles GET /events requests, pushing job events from every project the
+// caller can view. Each event's SSE name is its type. Events are not replayed, so clients
+// should refetch the jobs they show after (re)connecting.
+func (h *Handler) StreamEvents(c *gin.Context) {
+	userID, ok := c.Get(auth.UserIDKey)
+	if !ok || userID == "" {
+		logger.Logger.Error("UserID not found in context during StreamEvents")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User ID missing"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	// 1. Subscribe before loading the caller's projects so no event slips in between
+	sub := h.bus.Subscribe(DefaultSubscriptionBuffer)
+	defer sub.Close()
+	visible, err := h.visibleProjects(ctx, userID.(string))
+	if err != nil {
+		logger.Logger.Error("Failed to list projects for event stream", zap.Error(err), zap.String("userId", userID.(string)))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to open event stream"})
+		return
+	}
+	loadedAt := time.Now()
+
+	// 2. Stream events until the client goes away
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering
+	c.Status(http.StatusOK)
+	fmt.Fprint(c.Writer, ": connected\n\n")
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(h.keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return // Client went away
+		case <-sub.Done():
+			logger.Logger.Warn("Event stream dropped, subscriber fell behind", zap.String("userId", userID.(string)))
+			writeServerSentEvent(c.Writer, "", "error", gin.H{"error": "EVENT_STREAM_LAGGED", "message": "Too many undelivered events; reconnect and refetch"})
+			c.Writer.Flush()
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(c.Writer, ": keepalive\n\n")
+			c.Writer.Flush()
+		case event := <-sub.C:
+			// 3. Reload the project list when it is stale, or early for an unknown project
+			// (the caller may just have been added to it)
+			age := time.Since(loadedAt)
+			if age >= h.projectsMaxAge || (!visible[event.ProjectID] && age >= h.projectsMinAge) {
+				if reloaded, err := h.visibleProjects(ctx, userID.(string)); err != nil {
+					logger.Logger.Warn("Failed to reload projects for event stream", zap.Error(err), zap.String("userId", userID.(string)))
+				} else {
+					visible = reloaded
+				}
+				loadedAt = time.Now()
+			}
+			if !visible[event.ProjectID] {
+				continue
+			}
+			writeServerSentEvent(c.Writer, event.ID, event.Type, event)
+			c.Writer.Flush()
+		}
+	}
+}
+
+// visibleProjects returns the IDs of every project the user can view, archived ones included.
+func (h *Handler) visibleProjects(ctx context.Context, userID string) (map[string]bool, error) {
+	resp, err := h.projects.ListProjects(ctx, userID, "all", 1000, 0) // High limit to get all projects
+	if err != nil {
+		return nil, err
+	}
+	visible := make(map[string]bool, len(resp.Projects))
+	for _, proj := range resp.Projects {
+		visible[proj.ID] = true
+	}
+	return visible, nil
+}
+
+// writeServerSentEvent writes one SSE event with a JSON payload. An empty id leaves the
+// client's last event ID unchanged.
+func writeServerSentEvent(w io.Writer, id, event string, data interface{}) {
+	payload, _ := json.Marshal(data)
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
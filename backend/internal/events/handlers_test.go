@@ -0,0 +1,167 @@
+package events
+
+import (
+	"SynDataGen/backend/internal/auth"
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/project"
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockProjectLister is a mock implementation of ProjectLister.
+type MockProjectLister struct {
+	mock.Mock
+}
+
+func (m *MockProjectLister) ListProjects(ctx context.Context, userID string, statusFilter string, limit, offset int) (*project.ListProjectsResponse, error) {
+	args := m.Called(ctx, userID, statusFilter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.ListProjectsResponse), args.Error(1)
+}
+
+func projectList(ids ...string) *project.ListProjectsResponse {
+	resp := &project.ListProjectsResponse{}
+	for _, id := range ids {
+		resp.Projects = append(resp.Projects, &core.Project{ID: id})
+	}
+	return resp
+}
+
+// setupEventServer serves the event stream, authenticating callers by the X-User-ID header.
+func setupEventServer(t *testing.T, handler *Handler) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockAuthMiddleware := func(c *gin.Context) {
+		if userID := c.GetHeader("X-User-ID"); userID != "" {
+			c.Set(auth.UserIDKey, userID)
+		}
+		c.Next()
+	}
+	handler.RegisterRoutes(router.Group("/"), mockAuthMiddleware)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// sseReader reads events from a stream, skipping comments.
+type sseReader struct {
+	lines *bufio.Scanner
+}
+
+func (r *sseReader) next(t *testing.T) (name string, event Event) {
+	t.Helper()
+	for r.lines.Scan() {
+		line := r.lines.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			name = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event))
+		case line == "" && name != "":
+			return name, event
+		}
+	}
+	t.Fatal("stream ended")
+	return "", Event{}
+}
+
+func openStream(t *testing.T, server *httptest.Server, userID string) (*http.Response, *sseReader) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	if userID != "" {
+		req.Header.Set("X-User-ID", userID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	reader := &sseReader{lines: bufio.NewScanner(resp.Body)}
+	if resp.StatusCode == http.StatusOK {
+		require.True(t, reader.lines.Scan())
+		require.Equal(t, ": connected", reader.lines.Text()) // Subscribed from here on
+	}
+	return resp, reader
+}
+
+func TestHandler_StreamEvents(t *testing.T) {
+	ctx := context.Background()
+	userID := "user-1"
+
+	t.Run("Success_StreamsVisibleProjectsOnly", func(t *testing.T) {
+		bus := NewBus(nil)
+		lister := new(MockProjectLister)
+		handler := NewHandler(bus, lister)
+		handler.projectsMinAge = time.Hour
+		lister.On("ListProjects", mock.Anything, userID, "all", 1000, 0).Return(projectList("proj-a"), nil).Once()
+		server := setupEventServer(t, handler)
+
+		resp, stream := openStream(t, server, userID)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		bus.Publish(ctx, Event{Type: TypeJobStatus, ProjectID: "proj-b", JobID: "hidden"})
+		progress := 30
+		bus.Publish(ctx, Event{Type: TypeJobProgress, ProjectID: "proj-a", JobID: "job-1", Progress: &progress})
+
+		name, event := stream.next(t)
+		assert.Equal(t, TypeJobProgress, name)
+		assert.Equal(t, "job-1", event.JobID)
+		assert.Equal(t, 30, *event.Progress)
+		lister.AssertExpectations(t)
+	})
+
+	t.Run("Success_ReloadsProjectsForUnknownProject", func(t *testing.T) {
+		bus := NewBus(nil)
+		lister := new(MockProjectLister)
+		handler := NewHandler(bus, lister)
+		handler.projectsMinAge = 0
+		lister.On("ListProjects", mock.Anything, userID, "all", 1000, 0).Return(projectList("proj-a"), nil).Once()
+		lister.On("ListProjects", mock.Anything, userID, "all", 1000, 0).Return(projectList("proj-a", "proj-new"), nil).Once()
+		server := setupEventServer(t, handler)
+
+		_, stream := openStream(t, server, userID)
+		bus.Publish(ctx, Event{Type: TypeJobStatus, ProjectID: "proj-new", JobID: "job-2", Status: core.JobStatusPending})
+
+		name, event := stream.next(t)
+		assert.Equal(t, TypeJobStatus, name)
+		assert.Equal(t, core.JobStatusPending, event.Status)
+		lister.AssertExpectations(t)
+	})
+
+	t.Run("Failure_Unauthorized", func(t *testing.T) {
+		server := setupEventServer(t, NewHandler(NewBus(nil), new(MockProjectLister)))
+
+		resp, _ := openStream(t, server, "")
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Failure_ProjectListError", func(t *testing.T) {
+		bus := NewBus(nil)
+		lister := new(MockProjectLister)
+		lister.On("ListProjects", mock.Anything, userID, "all", 1000, 0).Return(nil, errors.New("db down")).Once()
+		server := setupEventServer(t, NewHandler(bus, lister))
+
+		resp, _ := openStream(t, server, userID)
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.Eventually(t, func() bool {
+			bus.mu.RLock()
+			defer bus.mu.RUnlock()
+			return len(bus.subs) == 0
+		}, time.Second, 10*time.Millisecond, "the subscription is released")
+	})
+}
@@ -0,0 +1,369 @@
+package job
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrEstimatesUnavailable is returned when no Estimator is configured.
+var ErrEstimatesUnavailable = errors.New("job estimates are not available")
+
+// Bases an estimate can be made on.
+const (
+	EstimateBasisCalibrated = "calibrated" // Fitted to completed jobs with the same output format
+	EstimateBasisDefault    = "default"    // Too few completed jobs; built-in rates
+)
+
+const (
+	// DefaultCalibrationInterval is how often an Estimator refits its models when no
+	// interval is configured.
+	DefaultCalibrationInterval = time.Hour
+
+	// DefaultEstimateCurrency is the currency compute costs are quoted in when none is configured.
+	DefaultEstimateCurrency = "USD"
+
+	// calibrationSampleLimit is how many of the most recently created completed jobs a
+	// calibration reads.
+	calibrationSampleLimit = 500
+
+	// minCalibrationSamples is how many completed jobs of an output format are needed
+	// before its built-in rates are replaced by fitted ones.
+	minCalibrationSamples = 5
+
+	// defaultEstimateColumns is the column count assumed for configs without a schema.
+	defaultEstimateColumns = 10
+)
+
+// Built-in rates used until enough jobs of an output format have completed.
+const (
+	defaultStartupSeconds = 10.0 // Pipeline overhead before the first row
+	defaultSecondsPerCell = 1e-6 // About a million values generated per second
+	defaultBytesPerCell   = 12.0 // Output formats not listed in formatBytesPerCell
+)
+
+// formatBytesPerCell is the built-in output size of one value in each output format.
+var formatBytesPerCell = map[string]float64{
+	"csv":     10,
+	"json":    24, // Every value repeats its column name
+	"parquet": 4,  // Compressed columns
+}
+
+// EstimatorConfig configures an Estimator.
+type EstimatorConfig struct {
+	ComputeCostPerHour float64       // Price of an hour of pipeline compute; 0 quotes no cost
+	Currency           string        // Currency of ComputeCostPerHour; defaults to DefaultEstimateCurrency
+	Interval           time.Duration // How often Run refits the models; defaults to DefaultCalibrationInterval
+}
+
+// estimateModel predicts a job's output size and duration from its cell count, the
+// number of values it generates (records times columns).
+type estimateModel struct {
+	bytesPerCell   float64
+	startupSeconds float64
+	secondsPerCell float64
+	samples        int // Completed jobs the duration was fitted to; 0 for built-in rates
+}
+
+// Estimator predicts the output size, duration and compute cost of jobs, with models
+// calibrated from the actuals of completed jobs. Each replica calibrates its own models;
+// calibration only reads jobs. A nil *Estimator makes no estimates.
+type Estimator struct {
+	config      EstimatorConfig
+	projectRepo core.ProjectRepository
+	jobRepo     core.JobRepository
+	now         func() time.Time
+
+	mu           sync.RWMutex
+	models       map[string]estimateModel // Fitted models by output format
+	calibratedAt time.Time
+}
+
+// NewEstimator creates a new Estimator using built-in rates until it is calibrated.
+func NewEstimator(config EstimatorConfig, projectRepo core.ProjectRepository, jobRepo core.JobRepository) *Estimator {
+	if projectRepo == nil || jobRepo == nil {
+		panic("job.NewEstimator: all dependencies are required")
+	}
+	if config.Currency == "" {
+		config.Currency = DefaultEstimateCurrency
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultCalibrationInterval
+	}
+	return &Estimator{
+		config:      config,
+		projectRepo: projectRepo,
+		jobRepo:     jobRepo,
+		now:         func() time.Time { return time.Now().UTC() },
+		models:      make(map[string]estimateModel),
+	}
+}
+
+// Run calibrates the models now and then once per interval until ctx is cancelled.
+func (e *Estimator) Run(ctx context.Context) {
+	logger.Logger.Info("Job estimate calibration started", zap.Duration("interval", e.config.Interval))
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.Calibrate(ctx); err != nil && ctx.Err() == nil {
+			logger.Logger.Error("Job estimate calibration failed", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			logger.Logger.Info("Job estimate calibration stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Calibrate refits the models to the most recent completed jobs with recorded actuals
+// and returns how many jobs it used. Output formats with too few jobs keep built-in rates.
+func (e *Estimator) Calibrate(ctx context.Context) (int, error) {
+	// 1. Read recent completed jobs
+	projectIDs, err := unarchivedProjectIDs(ctx, e.projectRepo)
+	if err != nil {
+		return 0, err
+	}
+	jobs, _, err := e.jobRepo.ListJobsAcrossProjects(ctx, projectIDs, string(core.JobStatusCompleted), calibrationSampleLimit, 0)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list completed jobs: %w", err)
+	}
+
+	// 2. Group their actuals by output format
+	samples := make(map[string][]calibrationSample)
+	used := 0
+	for _, job := range jobs {
+		if job.Actuals == nil || job.Actuals.DurationSeconds <= 0 {
+			continue // Completed before actuals were recorded
+		}
+		shape := jobShape(job.JobType, job.JobConfig)
+		if shape.cells <= 0 {
+			continue
+		}
+		samples[shape.format] = append(samples[shape.format], calibrationSample{
+			cells:       shape.cells,
+			seconds:     job.Actuals.DurationSeconds,
+			outputBytes: job.Actuals.OutputBytes,
+		})
+		used++
+	}
+
+	// 3. Fit every format with enough samples
+	models := make(map[string]estimateModel)
+	for format, formatSamples := range samples {
+		if len(formatSamples) >= minCalibrationSamples {
+			models[format] = fitModel(format, formatSamples)
+		}
+	}
+	e.mu.Lock()
+	e.models = models
+	e.calibratedAt = e.now()
+	e.mu.Unlock()
+
+	logger.Logger.Info("Calibrated job estimates",
+		zap.Int("completedJobs", len(jobs)),
+		zap.Int("samples", used),
+		zap.Int("calibratedFormats", len(models)),
+	)
+	return used, nil
+}
+
+// Estimate predicts a job's output size, duration and compute cost from its type and config.
+func (e *Estimator) Estimate(jobType, jobConfig string) *core.JobEstimate {
+	if e == nil {
+		return nil
+	}
+	shape := jobShape(jobType, jobConfig)
+	e.mu.RLock()
+	model, calibrated := e.models[shape.format]
+	calibratedAt := e.calibratedAt
+	e.mu.RUnlock()
+	if !calibrated {
+		model = defaultModel(shape.format)
+	}
+
+	seconds := model.startupSeconds + float64(shape.cells)*model.secondsPerCell
+	estimate := &core.JobEstimate{
+		Records:         shape.records,
+		Columns:         shape.columns,
+		OutputBytes:     int64(math.Round(float64(shape.cells) * model.bytesPerCell)),
+		DurationSeconds: roundTo(seconds, 1),
+		ComputeCost:     e.computeCost(seconds),
+		Currency:        e.config.Currency,
+		Basis:           EstimateBasisDefault,
+	}
+	if calibrated {
+		estimate.Basis = EstimateBasisCalibrated
+		estimate.SampleSize = model.samples
+		estimate.CalibratedAt = &calibratedAt
+	}
+	return estimate
+}
+
+// Actuals returns what a completed job took, priced like its estimate and compared with
+// it. outputBytes is 0 if the pipeline does not report output sizes.
+func (e *Estimator) Actuals(job *core.Job, outputBytes int64) *core.JobActuals {
+	actuals := &core.JobActuals{OutputBytes: outputBytes}
+	if job.StartedAt != nil && job.CompletedAt != nil {
+		actuals.DurationSeconds = roundTo(job.CompletedAt.Sub(*job.StartedAt).Seconds(), 1)
+	}
+	if e != nil {
+		actuals.ComputeCost = e.computeCost(actuals.DurationSeconds)
+		actuals.Currency = e.config.Currency
+	}
+	if estimate := job.Estimate; estimate != nil {
+		if estimate.DurationSeconds > 0 && actuals.DurationSeconds > 0 {
+			actuals.DurationVsEstimate = roundTo(actuals.DurationSeconds/estimate.DurationSeconds, 2)
+		}
+		if estimate.OutputBytes > 0 && outputBytes > 0 {
+			actuals.OutputBytesVsEstimate = roundTo(float64(outputBytes)/float64(estimate.OutputBytes), 2)
+		}
+	}
+	return actuals
+}
+
+// computeCost prices seconds of pipeline compute, to a hundredth of a cent.
+func (e *Estimator) computeCost(seconds float64) float64 {
+	return roundTo(seconds/3600*e.config.ComputeCostPerHour, 4)
+}
+
+// calibrationSample is the size and actuals of one completed job.
+type calibrationSample struct {
+	cells       int64
+	seconds     float64
+	outputBytes int64 // 0 if unknown
+}
+
+// defaultModel returns the built-in rates for an output format.
+func defaultModel(format string) estimateModel {
+	bytesPerCell, ok := formatBytesPerCell[format]
+	if !ok {
+		bytesPerCell = defaultBytesPerCell
+	}
+	return estimateModel{
+		bytesPerCell:   bytesPerCell,
+		startupSeconds: defaultStartupSeconds,
+		secondsPerCell: defaultSecondsPerCell,
+	}
+}
+
+// fitModel fits a format's duration to a least-squares line over cell count and its
+// output size to the bytes per cell across the samples. Fits that would predict
+// negative or constant durations fall back to simpler ones, and output sizes keep the
+// built-in rate unless enough samples report them.
+func fitModel(format string, samples []calibrationSample) estimateModel {
+	model := defaultModel(format)
+	model.samples = len(samples)
+
+	// 1. Output size: total bytes over total cells of the samples that report bytes
+	var sizedCells, sizedBytes float64
+	sized := 0
+	for _, sample := range samples {
+		if sample.outputBytes > 0 {
+			sizedCells += float64(sample.cells)
+			sizedBytes += float64(sample.outputBytes)
+			sized++
+		}
+	}
+	if sized >= minCalibrationSamples {
+		model.bytesPerCell = sizedBytes / sizedCells
+	}
+
+	// 2. Duration: seconds = startup + cells * secondsPerCell
+	n := float64(len(samples))
+	var sumX, sumY float64
+	for _, sample := range samples {
+		sumX += float64(sample.cells)
+		sumY += sample.seconds
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var sxx, sxy, sumXX, sumXY float64
+	for _, sample := range samples {
+		x, y := float64(sample.cells), sample.seconds
+		sxx += (x - meanX) * (x - meanX)
+		sxy += (x - meanX) * (y - meanY)
+		sumXX += x * x
+		sumXY += x * y
+	}
+	switch slope := sxy / sxx; {
+	case sxx == 0 || slope <= 0:
+		// Sizes too alike to tell what a cell costs: keep the built-in rate on top of
+		// the observed overhead
+		model.startupSeconds = math.Max(meanY-meanX*model.secondsPerCell, 0)
+	case meanY-slope*meanX < 0:
+		// A negative overhead: fit a line through the origin instead
+		model.startupSeconds, model.secondsPerCell = 0, sumXY/sumXX
+	default:
+		model.startupSeconds, model.secondsPerCell = meanY-slope*meanX, slope
+	}
+	return model
+}
+
+// configShape is the size of a job as its estimate sees it.
+type configShape struct {
+	format  string // Output format, lower case
+	records int64
+	columns int
+	cells   int64 // Values generated: records times columns, per table
+}
+
+// jobShape reads the output format, record and column counts from a job config: the
+// parameters.format or job type, and each table's recordCount and schema, or the
+// top-level ones for a single-table config. Tables without a schema count as
+// defaultEstimateColumns columns.
+func jobShape(jobType, jobConfig string) configShape {
+	var cfg struct {
+		Schema     []json.RawMessage `json:"schema"`
+		Parameters struct {
+			RecordCount int64  `json:"recordCount"`
+			Format      string `json:"format"`
+		} `json:"parameters"`
+		Tables []struct {
+			RecordCount int64             `json:"recordCount"`
+			Schema      []json.RawMessage `json:"schema"`
+		} `json:"tables"`
+	}
+	_ = json.Unmarshal([]byte(jobConfig), &cfg) // Configs that are not JSON objects have no size
+
+	s := configShape{format: strings.ToLower(cfg.Parameters.Format)}
+	if s.format == "" {
+		s.format = strings.ToLower(jobType)
+	}
+	addTable := func(records int64, columns int) {
+		s.records += records
+		s.columns += columns
+		if columns == 0 {
+			columns = defaultEstimateColumns
+		}
+		s.cells += records * int64(columns)
+	}
+	if len(cfg.Tables) == 0 {
+		addTable(cfg.Parameters.RecordCount, len(cfg.Schema))
+		return s
+	}
+	for _, table := range cfg.Tables {
+		records := table.RecordCount
+		if records <= 0 {
+			records = cfg.Parameters.RecordCount
+		}
+		addTable(records, len(table.Schema))
+	}
+	return s
+}
+
+// roundTo rounds x to the given number of decimal places.
+func roundTo(x float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(x*scale) / scale
+}
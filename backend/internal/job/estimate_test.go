@@ -0,0 +1,271 @@
+package job
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var calibrationNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func setupTestEstimator(cfg EstimatorConfig) (*Estimator, *MockProjectRepository, *MockJobRepository) {
+	projectRepo := new(MockProjectRepository)
+	jobRepo := new(MockJobRepository)
+	estimator := NewEstimator(cfg, projectRepo, jobRepo)
+	estimator.now = func() time.Time { return calibrationNow }
+	return estimator, projectRepo, jobRepo
+}
+
+// completedJob returns a completed job of format with records rows of two columns that
+// took seconds and wrote outputBytes.
+func completedJob(format string, records int64, seconds float64, outputBytes int64) *core.Job {
+	started := calibrationNow.Add(-time.Hour)
+	completed := started.Add(time.Duration(seconds * float64(time.Second)))
+	return &core.Job{
+		ID:          fmt.Sprintf("%s-%d", format, records),
+		Status:      core.JobStatusCompleted,
+		JobType:     format,
+		JobConfig:   fmt.Sprintf(`{"schema":[{"name":"id"},{"name":"name"}],"parameters":{"recordCount":%d}}`, records),
+		StartedAt:   &started,
+		CompletedAt: &completed,
+		Actuals:     &core.JobActuals{DurationSeconds: seconds, OutputBytes: outputBytes},
+	}
+}
+
+func TestJobShape(t *testing.T) {
+	cases := []struct {
+		name    string
+		jobType string
+		config  string
+		want    configShape
+	}{
+		{"SingleTable", "CSV", `{"schema":[{"name":"a"},{"name":"b"},{"name":"c"}],"parameters":{"recordCount":100}}`, configShape{format: "csv", records: 100, columns: 3, cells: 300}},
+		{"FormatParameterWins", "csv", `{"schema":[{"name":"a"}],"parameters":{"recordCount":10,"format":"Parquet"}}`, configShape{format: "parquet", records: 10, columns: 1, cells: 10}},
+		{"Tables", "json", `{"parameters":{"recordCount":50},"tables":[{"recordCount":10,"schema":[{"name":"a"},{"name":"b"}]},{"schema":[{"name":"c"}]}]}`, configShape{format: "json", records: 60, columns: 3, cells: 70}},
+		{"NoSchemaAssumesDefaultColumns", "csv", `{"parameters":{"recordCount":7}}`, configShape{format: "csv", records: 7, columns: 0, cells: 7 * defaultEstimateColumns}},
+		{"NotJSON", "csv", `rows=10`, configShape{format: "csv"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, jobShape(tc.jobType, tc.config))
+		})
+	}
+}
+
+func TestEstimator_Estimate(t *testing.T) {
+	t.Run("Success_DefaultRates", func(t *testing.T) {
+		estimator, _, _ := setupTestEstimator(EstimatorConfig{ComputeCostPerHour: 3.6})
+
+		estimate := estimator.Estimate("csv", `{"schema":[{"name":"a"},{"name":"b"}],"parameters":{"recordCount":5000000}}`)
+
+		require.NotNil(t, estimate)
+		assert.Equal(t, int64(5000000), estimate.Records)
+		assert.Equal(t, 2, estimate.Columns)
+		assert.Equal(t, int64(100000000), estimate.OutputBytes) // 10 bytes per CSV value
+		assert.Equal(t, 20.0, estimate.DurationSeconds)         // 10s startup + 10M values at 1M/s
+		assert.Equal(t, 0.02, estimate.ComputeCost)
+		assert.Equal(t, DefaultEstimateCurrency, estimate.Currency)
+		assert.Equal(t, EstimateBasisDefault, estimate.Basis)
+		assert.Nil(t, estimate.CalibratedAt)
+	})
+
+	t.Run("Success_NilEstimatorMakesNone", func(t *testing.T) {
+		var estimator *Estimator
+
+		assert.Nil(t, estimator.Estimate("csv", `{"parameters":{"recordCount":10}}`))
+	})
+}
+
+func TestEstimator_Calibrate(t *testing.T) {
+	ctx := context.Background()
+	projects := []*core.Project{
+		{ID: "proj-a", Status: core.ProjectStatusActive},
+		{ID: "proj-archived", Status: core.ProjectStatusArchived},
+	}
+
+	t.Run("Success_FitsFormatsWithEnoughSamples", func(t *testing.T) {
+		estimator, projectRepo, jobRepo := setupTestEstimator(EstimatorConfig{ComputeCostPerHour: 36})
+		// CSV jobs take 4s plus 1s per 10k values and write 8 bytes per value
+		var jobs []*core.Job
+		for _, records := range []int64{10000, 20000, 50000, 100000, 200000} {
+			cells := float64(records * 2)
+			jobs = append(jobs, completedJob("csv", records, 4+cells/10000, int64(cells*8)))
+		}
+		jobs = append(jobs,
+			completedJob("json", 1000, 30, 0), // Too few JSON jobs to fit
+			&core.Job{ID: "old", JobType: "csv", JobConfig: `{"parameters":{"recordCount":10}}`}, // Completed before actuals were recorded
+		)
+		projectRepo.On("ListAllProjects", ctx).Return(projects, nil).Once()
+		jobRepo.On("ListJobsAcrossProjects", ctx, []string{"proj-a"}, string(core.JobStatusCompleted), calibrationSampleLimit, 0).Return(jobs, len(jobs), nil).Once()
+
+		used, err := estimator.Calibrate(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 6, used)
+		csv := estimator.Estimate("csv", `{"schema":[{"name":"a"},{"name":"b"}],"parameters":{"recordCount":500000}}`)
+		assert.Equal(t, EstimateBasisCalibrated, csv.Basis)
+		assert.Equal(t, 5, csv.SampleSize)
+		assert.Equal(t, calibrationNow, *csv.CalibratedAt)
+		assert.InDelta(t, 104.0, csv.DurationSeconds, 0.1)
+		assert.InDelta(t, 8000000, csv.OutputBytes, 1)
+		assert.InDelta(t, 1.04, csv.ComputeCost, 0.001)
+		json := estimator.Estimate("json", `{"schema":[{"name":"a"}],"parameters":{"recordCount":1000}}`)
+		assert.Equal(t, EstimateBasisDefault, json.Basis)
+	})
+
+	t.Run("Success_NegativeOverheadFitsThroughOrigin", func(t *testing.T) {
+		estimator, projectRepo, jobRepo := setupTestEstimator(EstimatorConfig{})
+		var jobs []*core.Job
+		for _, tc := range []struct {
+			records int64
+			seconds float64
+		}{{10000, 1}, {20000, 1}, {30000, 2}, {40000, 4}, {50000, 6}} {
+			jobs = append(jobs, completedJob("parquet", tc.records, tc.seconds, 0))
+		}
+		projectRepo.On("ListAllProjects", ctx).Return(projects, nil).Once()
+		jobRepo.On("ListJobsAcrossProjects", ctx, []string{"proj-a"}, string(core.JobStatusCompleted), calibrationSampleLimit, 0).Return(jobs, len(jobs), nil).Once()
+
+		_, err := estimator.Calibrate(ctx)
+		require.NoError(t, err)
+		estimate := estimator.Estimate("parquet", `{"schema":[{"name":"a"},{"name":"b"}],"parameters":{"recordCount":0}}`)
+
+		assert.Equal(t, 0.0, estimate.DurationSeconds, "no overhead through the origin")
+		assert.Equal(t, EstimateBasisCalibrated, estimate.Basis)
+	})
+
+	t.Run("Failure_ListError", func(t *testing.T) {
+		estimator, projectRepo, jobRepo := setupTestEstimator(EstimatorConfig{})
+		projectRepo.On("ListAllProjects", ctx).Return(projects, nil).Once()
+		jobRepo.On("ListJobsAcrossProjects", ctx, []string{"proj-a"}, string(core.JobStatusCompleted), calibrationSampleLimit, 0).Return(nil, 0, errors.New("unavailable")).Once()
+
+		_, err := estimator.Calibrate(ctx)
+
+		assert.ErrorContains(t, err, "failed to list completed jobs")
+	})
+}
+
+func TestEstimator_Actuals(t *testing.T) {
+	estimator, _, _ := setupTestEstimator(EstimatorConfig{ComputeCostPerHour: 3.6})
+	job := completedJob("csv", 1000, 50, 0)
+	job.Estimate = &core.JobEstimate{DurationSeconds: 40, OutputBytes: 20000}
+
+	actuals := estimator.Actuals(job, 25000)
+
+	assert.Equal(t, 50.0, actuals.DurationSeconds)
+	assert.Equal(t, int64(25000), actuals.OutputBytes)
+	assert.Equal(t, 0.05, actuals.ComputeCost)
+	assert.Equal(t, DefaultEstimateCurrency, actuals.Currency)
+	assert.Equal(t, 1.25, actuals.DurationVsEstimate)
+	assert.Equal(t, 1.25, actuals.OutputBytesVsEstimate)
+
+	unknownSize := estimator.Actuals(job, 0)
+	assert.Zero(t, unknownSize.OutputBytesVsEstimate)
+}
+
+func TestJobService_Estimates(t *testing.T) {
+	ctx := context.Background()
+	proj := &core.Project{
+		ID:          "proj-1",
+		Status:      core.ProjectStatusActive,
+		TeamMembers: map[string]core.Role{"member": core.RoleMember, "viewer": core.RoleViewer},
+	}
+	config := `{"schema":[{"name":"a"}],"parameters":{"recordCount":1000}}`
+	setup := func() (JobService, *MockJobRepository, *MockProjectService, *MockPipelineClient) {
+		estimator, _, _ := setupTestEstimator(EstimatorConfig{ComputeCostPerHour: 1})
+		return setupTestServiceWith(JobServiceOptions{Estimator: estimator})
+	}
+
+	t.Run("Success_ViewerEstimates", func(t *testing.T) {
+		service, jobRepo, projectSvc, _ := setup()
+		projectSvc.On("GetProjectByID", ctx, "proj-1", "viewer").Return(proj, nil).Once()
+
+		estimate, err := service.EstimateJob(ctx, "proj-1", "viewer", EstimateJobRequest{JobType: "csv", JobConfig: config})
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(1000), estimate.Records)
+		assert.Equal(t, EstimateBasisDefault, estimate.Basis)
+		jobRepo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_NoEstimator", func(t *testing.T) {
+		service, _, projectSvc, _ := setupTestService()
+		projectSvc.On("GetProjectByID", ctx, "proj-1", "viewer").Return(proj, nil).Once()
+
+		_, err := service.EstimateJob(ctx, "proj-1", "viewer", EstimateJobRequest{JobType: "csv", JobConfig: config})
+
+		assert.ErrorIs(t, err, ErrEstimatesUnavailable)
+	})
+
+	t.Run("Success_CreatedJobCarriesEstimate", func(t *testing.T) {
+		service, jobRepo, projectSvc, _ := setup()
+		projectSvc.On("GetProjectByID", ctx, "proj-1", "member").Return(proj, nil).Once()
+		jobRepo.On("CreateJob", ctx, mock.AnythingOfType("*core.Job")).Return(nil).Once()
+
+		job, err := service.CreateJob(ctx, "proj-1", "member", CreateJobRequest{ProjectID: "proj-1", JobType: "csv", JobConfig: config})
+
+		require.NoError(t, err)
+		require.NotNil(t, job.Estimate)
+		assert.Equal(t, int64(10000), job.Estimate.OutputBytes)
+	})
+
+	t.Run("Success_CompletionRecordsActuals", func(t *testing.T) {
+		service, jobRepo, projectSvc, pipeline := setup()
+		started := time.Now().UTC().Add(-time.Minute)
+		running := &core.Job{
+			ID:            "job-1",
+			ProjectID:     "proj-1",
+			Status:        core.JobStatusRunning,
+			PipelineJobID: "pipe-1",
+			StartedAt:     &started,
+			Estimate:      &core.JobEstimate{DurationSeconds: 30},
+		}
+		jobRepo.On("GetJobByID", ctx, "job-1").Return(running, nil).Once()
+		pipeline.On("CheckStatus", ctx, "pipe-1").Return(core.JobStatusCompleted, "", nil).Once()
+		jobRepo.On("TransitionJobStatus", ctx, "job-1", core.JobStatusRunning, core.JobStatusCompleted, "pipe-1", &started, mock.AnythingOfType("*time.Time"), "").Return(nil).Once()
+		pipeline.On("ResultURI", ctx, "pipe-1").Return("", nil).Once()
+		jobRepo.On("UpdateJobActuals", ctx, "job-1", mock.MatchedBy(func(actuals *core.JobActuals) bool {
+			return actuals.DurationSeconds >= 60 && actuals.DurationVsEstimate >= 2 && actuals.Currency == DefaultEstimateCurrency
+		})).Return(nil).Once()
+		projectSvc.On("RefreshStorageUsage", ctx, "proj-1").Return(nil, errors.New("skipped")).Once()
+
+		job, err := service.RefreshJobStatus(ctx, "job-1")
+
+		require.NoError(t, err)
+		require.NotNil(t, job.Actuals)
+		jobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_CompletionRecordsReportedOutput", func(t *testing.T) {
+		estimator, _, _ := setupTestEstimator(EstimatorConfig{ComputeCostPerHour: 1})
+		jobRepo := new(MockJobRepository)
+		projectSvc := new(MockProjectService)
+		pipeline := new(MockReportingPipelineClient)
+		service := NewJobService(jobRepo, projectSvc, pipeline, JobServiceOptions{Estimator: estimator})
+		started := time.Now().UTC().Add(-time.Minute)
+		running := &core.Job{ID: "job-1", ProjectID: "proj-1", Status: core.JobStatusRunning, PipelineJobID: "pipe-1", StartedAt: &started}
+		jobRepo.On("GetJobByID", ctx, "job-1").Return(running, nil).Once()
+		pipeline.On("CheckStatus", ctx, "pipe-1").Return(core.JobStatusCompleted, "", nil).Once()
+		jobRepo.On("TransitionJobStatus", ctx, "job-1", core.JobStatusRunning, core.JobStatusCompleted, "pipe-1", &started, mock.AnythingOfType("*time.Time"), "").Return(nil).Once()
+		pipeline.On("ResultURI", ctx, "pipe-1").Return("", nil).Once()
+		pipeline.On("OutputBytes", ctx, "pipe-1").Return(int64(4096), nil).Once()
+		pipeline.On("GeneratedRecords", ctx, "pipe-1").Return(int64(640), nil).Once()
+		jobRepo.On("UpdateJobActuals", ctx, "job-1", mock.MatchedBy(func(actuals *core.JobActuals) bool {
+			return actuals.OutputBytes == 4096 && actuals.Records == 640
+		})).Return(nil).Once()
+		projectSvc.On("RefreshStorageUsage", ctx, "proj-1").Return(nil, errors.New("skipped")).Once()
+
+		job, err := service.RefreshJobStatus(ctx, "job-1")
+
+		require.NoError(t, err)
+		require.NotNil(t, job.Actuals)
+		assert.Equal(t, int64(640), job.Actuals.Records)
+		jobRepo.AssertExpectations(t)
+	})
+}
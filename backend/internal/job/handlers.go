@@ -4,10 +4,16 @@ import (
 	"SynDataGen/backend/internal/auth"
 	"SynDataGen/backend/internal/core"
 	"SynDataGen/backend/internal/platform/logger"
+	"SynDataGen/backend/internal/project"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"math"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
@@ -16,20 +22,52 @@ import (
 // CreateJobRequest defines the expected JSON body for creating a job.
 // Struct tags are used by Gin for binding and validation.
 type CreateJobRequest struct {
-	ProjectID string `json:"projectId" binding:"required"`
+	ProjectID   string               `json:"projectId" binding:"required"`
+	JobType     string               `json:"jobType" binding:"required"`
+	JobConfig   string               `json:"jobConfig" binding:"required"`
+	RetryPolicy *core.JobRetryPolicy `json:"retryPolicy,omitempty"` // Optional automatic retries
+
+	// Set by the template service, scheduler and workflow orchestrator for the jobs they
+	// create; never bound from requests
+	TemplateID      string `json:"-"`
+	TemplateVersion int    `json:"-"`
+	ScheduleID      string `json:"-"`
+	WorkflowID      string `json:"-"`
+}
+
+// EstimateJobRequest defines the expected JSON body for estimating a job.
+type EstimateJobRequest struct {
 	JobType   string `json:"jobType" binding:"required"`
 	JobConfig string `json:"jobConfig" binding:"required"`
 }
 
+// CloneJobRequest defines the optional JSON body for cloning a job. Fields left out are
+// copied from the source job.
+type CloneJobRequest struct {
+	JobType         string               `json:"jobType,omitempty"`
+	JobConfig       string               `json:"jobConfig,omitempty"`       // Replaces the source's config
+	ConfigOverrides json.RawMessage      `json:"configOverrides,omitempty"` // JSON merge patch applied to the source's config
+	RetryPolicy     *core.JobRetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// Job log paging limits and how often a followed log is polled for new entries.
+const (
+	defaultLogPageSize     = 100
+	maxLogPageSize         = 1000
+	defaultLogPollInterval = 2 * time.Second
+)
+
 // JobHandler handles HTTP requests for jobs.
 type JobHandler struct {
-	service JobService
+	service         JobService
+	logPollInterval time.Duration
 }
 
 // NewJobHandler creates a new JobHandler.
 func NewJobHandler(s JobService) *JobHandler {
 	return &JobHandler{
-		service: s,
+		service:         s,
+		logPollInterval: defaultLogPollInterval,
 	}
 }
 
@@ -42,18 +80,23 @@ func (h *JobHandler) RegisterRoutes(rg *gin.RouterGroup, authMiddleware gin.Hand
 	projectJobs := rg.Group("/projects/:projectId/jobs")
 	projectJobs.Use(authMiddleware) // Apply auth middleware
 	{
-		projectJobs.POST("", h.CreateJob)        // POST /api/v1/projects/:projectId/jobs
-		projectJobs.GET("", h.ListJobsByProject) // GET /api/v1/projects/:projectId/jobs
+		projectJobs.POST("", h.CreateJob)            // POST /api/v1/projects/:projectId/jobs
+		projectJobs.GET("", h.ListJobsByProject)     // GET /api/v1/projects/:projectId/jobs
+		projectJobs.POST("/estimate", h.EstimateJob) // POST /api/v1/projects/:projectId/jobs/estimate
 	}
 
 	// Route for getting a specific job by its ID and performing actions
 	jobSpecific := rg.Group("/jobs")
 	jobSpecific.Use(authMiddleware) // Apply auth middleware
 	{
-		jobSpecific.GET("/:jobId", h.GetJob)              // GET /api/v1/jobs/:jobId
-		jobSpecific.POST("/:jobId/submit", h.SubmitJob)   // POST /api/v1/jobs/:jobId/submit
-		jobSpecific.DELETE("/:jobId", h.CancelJob)        // DELETE /api/v1/jobs/:jobId (Assume maps to Cancel)
-		jobSpecific.POST("/:jobId/sync", h.SyncJobStatus) // POST /api/v1/jobs/:jobId/sync
+		jobSpecific.GET("/:jobId", h.GetJob)                          // GET /api/v1/jobs/:jobId
+		jobSpecific.POST("/:jobId/submit", h.SubmitJob)               // POST /api/v1/jobs/:jobId/submit
+		jobSpecific.DELETE("/:jobId", h.CancelJob)                    // DELETE /api/v1/jobs/:jobId (Assume maps to Cancel)
+		jobSpecific.POST("/:jobId/sync", h.SyncJobStatus)             // POST /api/v1/jobs/:jobId/sync
+		jobSpecific.POST("/:jobId/retry", h.RetryJob)                 // POST /api/v1/jobs/:jobId/retry
+		jobSpecific.POST("/:jobId/clone", h.CloneJob)                 // POST /api/v1/jobs/:jobId/clone
+		jobSpecific.GET("/:jobId/quality-report", h.GetQualityReport) // GET /api/v1/jobs/:jobId/quality-report
+		jobSpecific.GET("/:jobId/logs", h.GetJobLogs)                 // GET /api/v1/jobs/:jobId/logs
 	}
 
 	// Route for listing all jobs accessible by the user
@@ -99,6 +142,12 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Project not found"})
 		} else if errors.Is(err, core.ErrForbidden) {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: You do not have permission to create jobs in this project"})
+		} else if errors.Is(err, core.ErrProjectArchived) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "PROJECT_ARCHIVED", "message": err.Error()})
+		} else if errors.Is(err, ErrInvalidJobConfig) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_JOB_CONFIG", "message": err.Error()})
+		} else if AbortWithLimitError(c, err) {
+			return
 		} else {
 			// Consider mapping other specific service errors to 4xx codes if appropriate
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
@@ -109,6 +158,43 @@ func (h *JobHandler) CreateJob(c *gin.Context) {
 	c.JSON(http.StatusCreated, job)
 }
 
+// EstimateJob handles POST /projects/:projectId/jobs/estimate requests.
+func (h *JobHandler) EstimateJob(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := c.Get(auth.UserIDKey)
+	if !ok || userID == "" {
+		logger.Logger.Error("UserID not found in context during EstimateJob")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User ID missing"})
+		return
+	}
+
+	var req EstimateJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Logger.Warn("Invalid request body for EstimateJob", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	estimate, err := h.service.EstimateJob(c.Request.Context(), projectID, userID.(string), req)
+	if err != nil {
+		logger.Logger.Error("Failed to estimate job via service", zap.Error(err), zap.String("userId", userID.(string)), zap.String("projectId", projectID))
+		if errors.Is(err, core.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		} else if errors.Is(err, core.ErrForbidden) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: You do not have permission to view this project"})
+		} else if errors.Is(err, ErrInvalidJobConfig) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_JOB_CONFIG", "message": err.Error()})
+		} else if errors.Is(err, ErrEstimatesUnavailable) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "ESTIMATES_UNAVAILABLE", "message": err.Error()})
+		} else {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to estimate job"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, estimate)
+}
+
 // GetJob handles GET /jobs/:jobId requests.
 func (h *JobHandler) GetJob(c *gin.Context) {
 	jobID := c.Param("jobId")
@@ -204,8 +290,21 @@ func (h *JobHandler) SubmitJob(c *gin.Context) {
 			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		} else if errors.Is(err, core.ErrForbidden) {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: You do not have permission to submit this job"})
+		} else if errors.Is(err, core.ErrProjectArchived) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "PROJECT_ARCHIVED", "message": err.Error()})
+		} else if errors.Is(err, core.ErrStorageQuotaExceeded) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "STORAGE_QUOTA_EXCEEDED", "message": err.Error()})
+		} else if errors.Is(err, ErrPIIReviewRequired) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "PII_REVIEW_REQUIRED", "message": err.Error()})
+		} else if errors.Is(err, project.ErrDatasetNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "DATASET_NOT_FOUND", "message": err.Error()})
 		} else if strings.Contains(err.Error(), "cannot be submitted") { // Check for specific service error message
 			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_JOB_STATUS", "message": err.Error()})
+		} else if AbortWithLimitError(c, err) {
+			return
+		} else if errors.Is(err, ErrPipelineUnavailable) {
+			c.Header("Retry-After", "30")
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "PIPELINE_UNAVAILABLE", "message": err.Error()})
 		} else if strings.Contains(err.Error(), "pipeline submission failed") { // Check for pipeline error
 			// Return the updated job status (likely Failed) and the error message
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "PIPELINE_SUBMIT_FAILED", "message": err.Error(), "job": job})
@@ -216,6 +315,10 @@ func (h *JobHandler) SubmitJob(c *gin.Context) {
 		return
 	}
 
+	if job.Status == core.JobStatusQueued {
+		c.JSON(http.StatusAccepted, job) // Submitted later by the queue dispatcher
+		return
+	}
 	c.JSON(http.StatusOK, job) // Return the updated job state (likely Running)
 }
 
@@ -247,6 +350,89 @@ func (h *JobHandler) CancelJob(c *gin.Context) {
 	c.JSON(http.StatusOK, job) // Return updated job status (Cancelled)
 }
 
+// RetryJob handles POST /jobs/:jobId/retry requests.
+func (h *JobHandler) RetryJob(c *gin.Context) {
+	jobID := c.Param("jobId")
+	userID, ok := c.Get(auth.UserIDKey)
+	if !ok || userID == "" {
+		logger.Logger.Error("UserID not found in context during RetryJob")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User ID missing"})
+		return
+	}
+
+	job, err := h.service.RetryJob(c.Request.Context(), jobID, userID.(string))
+	if err != nil {
+		logger.Logger.Error("Failed to retry job via service", zap.Error(err), zap.String("userId", userID.(string)), zap.String("jobId", jobID))
+		h.abortWithCopyError(c, err, "Failed to retry job")
+		return
+	}
+
+	c.JSON(http.StatusCreated, job) // Return the new job (Pending)
+}
+
+// CloneJob handles POST /jobs/:jobId/clone requests. The body is optional.
+func (h *JobHandler) CloneJob(c *gin.Context) {
+	jobID := c.Param("jobId")
+	userID, ok := c.Get(auth.UserIDKey)
+	if !ok || userID == "" {
+		logger.Logger.Error("UserID not found in context during CloneJob")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User ID missing"})
+		return
+	}
+
+	var req CloneJobRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Logger.Warn("Invalid request body for CloneJob", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	job, err := h.service.CloneJob(c.Request.Context(), jobID, userID.(string), req)
+	if err != nil {
+		logger.Logger.Error("Failed to clone job via service", zap.Error(err), zap.String("userId", userID.(string)), zap.String("jobId", jobID))
+		h.abortWithCopyError(c, err, "Failed to clone job")
+		return
+	}
+
+	c.JSON(http.StatusCreated, job) // Return the new job (Pending)
+}
+
+// abortWithCopyError maps errors from RetryJob and CloneJob to responses.
+func (h *JobHandler) abortWithCopyError(c *gin.Context, err error, fallback string) {
+	if errors.Is(err, core.ErrNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+	} else if errors.Is(err, core.ErrForbidden) || errors.Is(err, project.ErrProjectAccessDenied) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: You do not have permission to create jobs in this project"})
+	} else if errors.Is(err, core.ErrProjectArchived) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "PROJECT_ARCHIVED", "message": err.Error()})
+	} else if errors.Is(err, ErrJobNotRetryable) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "JOB_NOT_RETRYABLE", "message": err.Error()})
+	} else if errors.Is(err, ErrInvalidJobConfig) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_JOB_CONFIG", "message": err.Error()})
+	} else if !AbortWithLimitError(c, err) {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// AbortWithLimitError responds to an error from the job limits and reports whether err was
+// one: a *LimitError becomes 429 with Retry-After, and ErrRecordLimitExceeded becomes 413.
+func AbortWithLimitError(c *gin.Context, err error) bool {
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		retryAfter := int(math.Ceil(limitErr.RetryAfter.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "JOB_LIMIT_EXCEEDED", "limit": limitErr.Limit, "message": err.Error()})
+		return true
+	}
+	if errors.Is(err, ErrRecordLimitExceeded) {
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "RECORD_LIMIT_EXCEEDED", "message": err.Error()})
+		return true
+	}
+	return false
+}
+
 // SyncJobStatus handles POST /jobs/:jobId/sync requests.
 func (h *JobHandler) SyncJobStatus(c *gin.Context) {
 	jobID := c.Param("jobId")
@@ -276,6 +462,169 @@ func (h *JobHandler) SyncJobStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, job) // Return potentially updated job status
 }
 
+// GetQualityReport handles GET /jobs/:jobId/quality-report requests.
+// Query parameters: refresh (recompute instead of using the stored report).
+func (h *JobHandler) GetQualityReport(c *gin.Context) {
+	jobID := c.Param("jobId")
+	userID, ok := c.Get(auth.UserIDKey)
+	if !ok || userID == "" {
+		logger.Logger.Error("UserID not found in context during GetQualityReport")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User ID missing"})
+		return
+	}
+
+	refresh := false
+	if raw := c.Query("refresh"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": "refresh must be true or false"})
+			return
+		}
+		refresh = parsed
+	}
+
+	report, err := h.service.GetQualityReport(c.Request.Context(), jobID, userID.(string), refresh)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		} else if errors.Is(err, core.ErrForbidden) || errors.Is(err, project.ErrProjectAccessDenied) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: You do not have permission to view this job"})
+		} else if errors.Is(err, ErrJobNotCompleted) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "JOB_NOT_COMPLETED", "message": err.Error()})
+		} else if errors.Is(err, ErrJobResultUnavailable) {
+			c.AbortWithStatusJSON(http.StatusGone, gin.H{"error": "RESULT_UNAVAILABLE", "message": err.Error()})
+		} else if errors.Is(err, ErrNoInputDataset) {
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "NO_INPUT_DATASET", "message": err.Error()})
+		} else if errors.Is(err, project.ErrDatasetNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "DATASET_NOT_FOUND", "message": err.Error()})
+		} else if errors.Is(err, project.ErrUnsupportedDatasetFormat) {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": "UNSUPPORTED_DATASET_FORMAT", "message": err.Error()})
+		} else {
+			logger.Logger.Error("Failed to build quality report via service", zap.Error(err), zap.String("userId", userID.(string)), zap.String("jobId", jobID))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to build quality report"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// GetJobLogs handles GET /jobs/:jobId/logs requests. With follow=true the log is streamed as
+// Server-Sent Events ("log" per entry, then "end") until the job reaches a final status.
+func (h *JobHandler) GetJobLogs(c *gin.Context) {
+	jobID := c.Param("jobId")
+	userID, ok := c.Get(auth.UserIDKey)
+	if !ok || userID == "" {
+		logger.Logger.Error("UserID not found in context during GetJobLogs")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User ID missing"})
+		return
+	}
+
+	// 1. Parse paging parameters. A reconnecting EventSource resumes from Last-Event-ID.
+	cursor := c.Query("cursor")
+	if cursor == "" {
+		cursor = c.GetHeader("Last-Event-ID")
+	}
+	var since int64
+	if cursor != "" {
+		parsed, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || parsed < 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": "cursor must be a value returned as nextCursor"})
+			return
+		}
+		since = parsed
+	}
+	limit := defaultLogPageSize
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > maxLogPageSize {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": fmt.Sprintf("limit must be between 1 and %d", maxLogPageSize)})
+			return
+		}
+		limit = parsed
+	}
+	follow := false
+	if raw := c.Query("follow"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": "follow must be true or false"})
+			return
+		}
+		follow = parsed
+	}
+
+	// 2. Fetch the first page; errors are reported as JSON even when following
+	page, err := h.service.GetJobLogs(c.Request.Context(), jobID, userID.(string), since, limit)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		} else if errors.Is(err, core.ErrForbidden) || errors.Is(err, project.ErrProjectAccessDenied) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Forbidden: You do not have permission to view this job"})
+		} else if errors.Is(err, ErrPipelineUnavailable) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "PIPELINE_UNAVAILABLE", "message": err.Error()})
+		} else {
+			logger.Logger.Error("Failed to get job logs via service", zap.Error(err), zap.String("userId", userID.(string)), zap.String("jobId", jobID))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job logs"})
+		}
+		return
+	}
+	if !follow {
+		c.JSON(http.StatusOK, page)
+		return
+	}
+
+	// 3. Stream entries, polling for more until the job is final and fully read
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable proxy buffering
+	c.Status(http.StatusOK)
+	ctx := c.Request.Context()
+	for {
+		for _, entry := range page.Logs {
+			writeServerSentEvent(c.Writer, strconv.FormatInt(entry.Sequence, 10), "log", entry)
+		}
+		if !page.HasMore && isFinalJobStatus(page.JobStatus) {
+			writeServerSentEvent(c.Writer, "", "end", gin.H{"jobStatus": page.JobStatus, "nextCursor": page.NextCursor})
+			c.Writer.Flush()
+			return
+		}
+		c.Writer.Flush()
+
+		if !page.HasMore {
+			select {
+			case <-ctx.Done():
+				return // Client went away
+			case <-time.After(h.logPollInterval):
+			}
+		}
+		since, _ = strconv.ParseInt(page.NextCursor, 10, 64)
+		if page, err = h.service.GetJobLogs(ctx, jobID, userID.(string), since, limit); err != nil {
+			if ctx.Err() == nil {
+				logger.Logger.Warn("Job log stream stopped", zap.Error(err), zap.String("jobId", jobID))
+				writeServerSentEvent(c.Writer, "", "error", gin.H{"error": "LOG_STREAM_FAILED", "message": err.Error()})
+				c.Writer.Flush()
+			}
+			return
+		}
+	}
+}
+
+// writeServerSentEvent writes one SSE event with a JSON payload. An empty id leaves the
+// client's last event ID unchanged.
+func writeServerSentEvent(w io.Writer, id, event string, data interface{}) {
+	payload, _ := json.Marshal(data)
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+}
+
+// isFinalJobStatus reports whether a job can no longer change status.
+func isFinalJobStatus(status core.JobStatus) bool {
+	return status == core.JobStatusCompleted || status == core.JobStatusFailed || status == core.JobStatusCancelled
+}
+
 // ListAllJobs handles GET /jobs requests.
 func (h *JobHandler) ListAllJobs(c *gin.Context) {
 	userID, ok := c.Get(auth.UserIDKey)
@@ -3,6 +3,7 @@ package job
 import (
 	"SynDataGen/backend/internal/auth" // For auth.UserIDKey
 	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/project"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -60,6 +61,22 @@ func (m *MockJobService) ListJobsByProject(ctx context.Context, projectID, userI
 	return args.Get(0).([]*core.Job), args.Int(1), args.Error(2)
 }
 
+func (m *MockJobService) RetryJob(ctx context.Context, jobID, userID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobService) CloneJob(ctx context.Context, jobID, userID string, req CloneJobRequest) (*core.Job, error) {
+	args := m.Called(ctx, jobID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
 func (m *MockJobService) CancelJob(ctx context.Context, jobID, userID string) (*core.Job, error) {
 	// Implementation not strictly needed for current handler tests
 	args := m.Called(ctx, jobID, userID)
@@ -78,6 +95,67 @@ func (m *MockJobService) SyncJobStatus(ctx context.Context, jobID, userID string
 	return args.Get(0).(*core.Job), args.Error(1)
 }
 
+func (m *MockJobService) ListAllAccessibleJobs(ctx context.Context, userID string, statusFilter string, limit, offset int) ([]*core.Job, int, error) {
+	args := m.Called(ctx, userID, statusFilter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.Job), args.Int(1), args.Error(2)
+}
+
+func (m *MockJobService) GetQualityReport(ctx context.Context, jobID, userID string, refresh bool) (*project.FidelityReport, error) {
+	args := m.Called(ctx, jobID, userID, refresh)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.FidelityReport), args.Error(1)
+}
+
+func (m *MockJobService) CancelProjectJobs(ctx context.Context, projectID string) error {
+	args := m.Called(ctx, projectID)
+	return args.Error(0)
+}
+
+func (m *MockJobService) RefreshJobStatus(ctx context.Context, jobID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobService) FailQueuedJob(ctx context.Context, jobID, reason string) (*core.Job, error) {
+	args := m.Called(ctx, jobID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobService) FailDueRetry(ctx context.Context, jobID, reason string) (*core.Job, error) {
+	args := m.Called(ctx, jobID, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobService) EstimateJob(ctx context.Context, projectID, userID string, req EstimateJobRequest) (*core.JobEstimate, error) {
+	args := m.Called(ctx, projectID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.JobEstimate), args.Error(1)
+}
+
+func (m *MockJobService) GetJobLogs(ctx context.Context, jobID, userID string, since int64, limit int) (*JobLogPage, error) {
+	args := m.Called(ctx, jobID, userID, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*JobLogPage), args.Error(1)
+}
+
 // --- Helper to setup Gin test context ---
 func setupGinTestRouter(handler *JobHandler) (*gin.Engine, *MockJobService) {
 	gin.SetMode(gin.TestMode)
@@ -89,8 +167,12 @@ func setupGinTestRouter(handler *JobHandler) (*gin.Engine, *MockJobService) {
 
 	// Mock auth middleware - just sets the user ID in context
 	mockAuthMiddleware := func(c *gin.Context) {
-		// Set a default user ID for tests unless overridden in the test case
-		c.Set(auth.UserIDKey, "test-user-id")
+		// Copy the user ID from the request context, mirroring what the real
+		// middleware does after validating the session. Requests without one
+		// reach the handler unauthenticated.
+		if userID, ok := c.Request.Context().Value(auth.UserIDKey).(string); ok && userID != "" {
+			c.Set(auth.UserIDKey, userID)
+		}
 		c.Next()
 	}
 
@@ -138,7 +220,7 @@ func TestJobHandler_CreateJob(t *testing.T) {
 		reqBody := bytes.NewBuffer(bodyBytes)
 
 		// Mock service call
-		mockService.On("CreateJob", mock.Anything, userID, projectID, validReqBody).Return(mockCreatedJob, nil).Once()
+		mockService.On("CreateJob", mock.Anything, projectID, userID, validReqBody).Return(mockCreatedJob, nil).Once()
 
 		// Create request and recorder
 		w := httptest.NewRecorder()
@@ -186,7 +268,7 @@ func TestJobHandler_CreateJob(t *testing.T) {
 		// Reset mock for sub-test
 		// router, _ = setupGinTestRouter(handler)
 
-		reqBody := bytes.NewBufferString(`{"projectId": "abc", "jobType": "test", "jobConfig": "{invalid"}`) // Invalid JSON in config
+		reqBody := bytes.NewBufferString(`{"projectId": "abc", "jobType": "test", "jobConfig": {invalid}`) // Malformed JSON body
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/jobs", reqBody)
@@ -246,7 +328,7 @@ func TestJobHandler_CreateJob(t *testing.T) {
 		bodyBytes, _ := json.Marshal(validReqBody)
 		reqBody := bytes.NewBuffer(bodyBytes)
 
-		mockService.On("CreateJob", mock.Anything, userID, projectID, validReqBody).Return(nil, core.ErrNotFound).Once()
+		mockService.On("CreateJob", mock.Anything, projectID, userID, validReqBody).Return(nil, core.ErrNotFound).Once()
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/jobs", reqBody)
@@ -267,7 +349,7 @@ func TestJobHandler_CreateJob(t *testing.T) {
 		bodyBytes, _ := json.Marshal(validReqBody)
 		reqBody := bytes.NewBuffer(bodyBytes)
 
-		mockService.On("CreateJob", mock.Anything, userID, projectID, validReqBody).Return(nil, core.ErrForbidden).Once()
+		mockService.On("CreateJob", mock.Anything, projectID, userID, validReqBody).Return(nil, core.ErrForbidden).Once()
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/jobs", reqBody)
@@ -289,7 +371,7 @@ func TestJobHandler_CreateJob(t *testing.T) {
 		bodyBytes, _ := json.Marshal(validReqBody)
 		reqBody := bytes.NewBuffer(bodyBytes)
 
-		mockService.On("CreateJob", mock.Anything, userID, projectID, validReqBody).Return(nil, internalError).Once()
+		mockService.On("CreateJob", mock.Anything, projectID, userID, validReqBody).Return(nil, internalError).Once()
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/jobs", reqBody)
@@ -303,6 +385,50 @@ func TestJobHandler_CreateJob(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
+	t.Run("ServiceError_ProjectArchived", func(t *testing.T) {
+		// Reset mock for sub-test
+		router, mockService = setupGinTestRouter(handler)
+
+		archivedErr := fmt.Errorf("%w: cannot create jobs in project %s", core.ErrProjectArchived, projectID)
+		bodyBytes, _ := json.Marshal(validReqBody)
+		reqBody := bytes.NewBuffer(bodyBytes)
+
+		mockService.On("CreateJob", mock.Anything, projectID, userID, validReqBody).Return(nil, archivedErr).Once()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/jobs", reqBody)
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, userID))
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusConflict, w.Code)
+		assert.Contains(w.Body.String(), "PROJECT_ARCHIVED")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("ServiceError_InvalidJobConfig", func(t *testing.T) {
+		// Reset mock for sub-test
+		router, mockService = setupGinTestRouter(handler)
+
+		configErr := fmt.Errorf("%w: schemaFromDataset: dataset not found", ErrInvalidJobConfig)
+		bodyBytes, _ := json.Marshal(validReqBody)
+		reqBody := bytes.NewBuffer(bodyBytes)
+
+		mockService.On("CreateJob", mock.Anything, projectID, userID, validReqBody).Return(nil, configErr).Once()
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/jobs", reqBody)
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, userID))
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+		assert.Contains(w.Body.String(), "INVALID_JOB_CONFIG")
+		mockService.AssertExpectations(t)
+	})
+
 }
 
 func TestJobHandler_GetJob(t *testing.T) {
@@ -574,3 +700,407 @@ func TestJobHandler_ListJobsByProject(t *testing.T) {
 }
 
 // All handlers tested
+
+func TestJobHandler_GetJobLogs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	handler := NewJobHandler(nil) // Service will be injected by setupGinTestRouter
+	handler.logPollInterval = time.Millisecond
+
+	jobID := "job-" + uuid.NewString()
+	userID := "user-" + uuid.NewString()
+	entry := func(seq int64) LogEntry {
+		return LogEntry{Sequence: seq, Level: LogLevelInfo, Message: fmt.Sprintf("line %d", seq)}
+	}
+
+	newRequest := func(query string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, "/jobs/"+jobID+"/logs"+query, nil)
+		return req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, userID))
+	}
+
+	t.Run("Success_Page", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+		page := &JobLogPage{Logs: []LogEntry{entry(6), entry(7)}, NextCursor: "7", HasMore: true, JobStatus: core.JobStatusRunning}
+		mockService.On("GetJobLogs", mock.Anything, jobID, userID, int64(5), 2).Return(page, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest("?cursor=5&limit=2"))
+
+		assert.Equal(http.StatusOK, w.Code)
+		var resp JobLogPage
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(page.Logs, resp.Logs)
+		assert.Equal("7", resp.NextCursor)
+		assert.True(resp.HasMore)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Success_FollowStreamsUntilFinal", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+		mockService.On("GetJobLogs", mock.Anything, jobID, userID, int64(0), 100).
+			Return(&JobLogPage{Logs: []LogEntry{entry(1)}, NextCursor: "1", JobStatus: core.JobStatusRunning}, nil).Once()
+		mockService.On("GetJobLogs", mock.Anything, jobID, userID, int64(1), 100).
+			Return(&JobLogPage{Logs: []LogEntry{}, NextCursor: "1", JobStatus: core.JobStatusRunning}, nil).Once()
+		mockService.On("GetJobLogs", mock.Anything, jobID, userID, int64(1), 100).
+			Return(&JobLogPage{Logs: []LogEntry{entry(2)}, NextCursor: "2", JobStatus: core.JobStatusCompleted}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest("?follow=true"))
+
+		assert.Equal(http.StatusOK, w.Code)
+		assert.Equal("text/event-stream", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		assert.Contains(body, "id: 1\nevent: log\ndata: {\"sequence\":1,")
+		assert.Contains(body, "id: 2\nevent: log\n")
+		assert.Contains(body, "event: end\ndata: {\"jobStatus\":\"completed\",\"nextCursor\":\"2\"}\n\n")
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Success_FollowResumesFromLastEventID", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+		mockService.On("GetJobLogs", mock.Anything, jobID, userID, int64(42), 100).
+			Return(&JobLogPage{Logs: []LogEntry{}, NextCursor: "42", JobStatus: core.JobStatusFailed}, nil).Once()
+
+		req := newRequest("?follow=true")
+		req.Header.Set("Last-Event-ID", "42")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Contains(w.Body.String(), "event: end")
+		mockService.AssertExpectations(t)
+	})
+
+	for _, query := range []string{"?cursor=abc", "?limit=0", "?limit=5000", "?follow=maybe"} {
+		t.Run("InvalidQuery_"+query, func(t *testing.T) {
+			router, mockService := setupGinTestRouter(handler)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newRequest(query))
+
+			assert.Equal(http.StatusBadRequest, w.Code)
+			mockService.AssertNotCalled(t, "GetJobLogs", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+
+	for _, tc := range []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"NotFound", core.ErrNotFound, http.StatusNotFound, "Job not found"},
+		{"Forbidden", project.ErrProjectAccessDenied, http.StatusForbidden, "Forbidden"},
+		{"PipelineUnavailable", ErrPipelineUnavailable, http.StatusServiceUnavailable, "PIPELINE_UNAVAILABLE"},
+		{"Internal", errors.New("boom"), http.StatusInternalServerError, "Failed to get job logs"},
+	} {
+		t.Run("ServiceError_"+tc.name, func(t *testing.T) {
+			router, mockService := setupGinTestRouter(handler)
+			mockService.On("GetJobLogs", mock.Anything, jobID, userID, int64(0), 100).Return(nil, tc.err).Once()
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newRequest("?follow=true"))
+
+			assert.Equal(tc.status, w.Code)
+			assert.Contains(w.Body.String(), tc.code)
+		})
+	}
+}
+
+func TestJobHandler_GetQualityReport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	handler := NewJobHandler(nil) // Service will be injected by setupGinTestRouter
+
+	jobID := "job-" + uuid.NewString()
+	userID := "user-" + uuid.NewString()
+
+	newRequest := func(query string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, "/jobs/"+jobID+"/quality-report"+query, nil)
+		return req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, userID))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+		ks := 0.1
+		report := &project.FidelityReport{RealDatasetID: "customers.csv", Summary: project.FidelitySummary{MeanKS: &ks}}
+		mockService.On("GetQualityReport", mock.Anything, jobID, userID, true).Return(report, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest("?refresh=true"))
+
+		assert.Equal(http.StatusOK, w.Code)
+		var resp project.FidelityReport
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal("customers.csv", resp.RealDatasetID)
+		assert.Equal(0.1, *resp.Summary.MeanKS)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("InvalidRefresh", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest("?refresh=sometimes"))
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "GetQualityReport", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"NotFound", core.ErrNotFound, http.StatusNotFound, "Job not found"},
+		{"NotCompleted", ErrJobNotCompleted, http.StatusConflict, "JOB_NOT_COMPLETED"},
+		{"ResultUnavailable", ErrJobResultUnavailable, http.StatusGone, "RESULT_UNAVAILABLE"},
+		{"NoInputDataset", ErrNoInputDataset, http.StatusUnprocessableEntity, "NO_INPUT_DATASET"},
+		{"DatasetNotFound", fmt.Errorf("wrapped: %w", project.ErrDatasetNotFound), http.StatusNotFound, "DATASET_NOT_FOUND"},
+		{"Internal", errors.New("boom"), http.StatusInternalServerError, "Failed to build quality report"},
+	} {
+		t.Run("ServiceError_"+tc.name, func(t *testing.T) {
+			router, mockService := setupGinTestRouter(handler)
+			mockService.On("GetQualityReport", mock.Anything, jobID, userID, false).Return(nil, tc.err).Once()
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newRequest(""))
+
+			assert.Equal(tc.status, w.Code)
+			assert.Contains(w.Body.String(), tc.code)
+		})
+	}
+}
+
+func TestJobHandler_RetryJob(t *testing.T) {
+	assert := assert.New(t)
+	handler := NewJobHandler(nil) // Service will be injected by setupGinTestRouter
+
+	jobID := "job-" + uuid.NewString()
+	userID := "user-" + uuid.NewString()
+
+	newRequest := func() *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/jobs/"+jobID+"/retry", nil)
+		return req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, userID))
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+		retry := &core.Job{ID: "job-retry", RetryOf: jobID, Attempt: 2, Status: core.JobStatusPending}
+		mockService.On("RetryJob", mock.Anything, jobID, userID).Return(retry, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest())
+
+		assert.Equal(http.StatusCreated, w.Code)
+		var got core.Job
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &got))
+		assert.Equal(jobID, got.RetryOf)
+		assert.Equal(2, got.Attempt)
+		mockService.AssertExpectations(t)
+	})
+
+	for _, tc := range []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"NotFound", core.ErrNotFound, http.StatusNotFound, "Job not found"},
+		{"Forbidden", project.ErrProjectAccessDenied, http.StatusForbidden, "Forbidden"},
+		{"Archived", fmt.Errorf("%w: cannot create jobs", core.ErrProjectArchived), http.StatusConflict, "PROJECT_ARCHIVED"},
+		{"NotRetryable", fmt.Errorf("%w: job is running", ErrJobNotRetryable), http.StatusConflict, "JOB_NOT_RETRYABLE"},
+		{"RateLimited", &LimitError{Limit: LimitCreateRate, RetryAfter: 20 * time.Second}, http.StatusTooManyRequests, "JOB_LIMIT_EXCEEDED"},
+		{"TooManyRecords", fmt.Errorf("%w: 5000 records", ErrRecordLimitExceeded), http.StatusRequestEntityTooLarge, "RECORD_LIMIT_EXCEEDED"},
+		{"Internal", errors.New("boom"), http.StatusInternalServerError, "Failed to retry job"},
+	} {
+		t.Run("ServiceError_"+tc.name, func(t *testing.T) {
+			router, mockService := setupGinTestRouter(handler)
+			mockService.On("RetryJob", mock.Anything, jobID, userID).Return(nil, tc.err).Once()
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newRequest())
+
+			assert.Equal(tc.status, w.Code)
+			assert.Contains(w.Body.String(), tc.code)
+		})
+	}
+}
+
+func TestJobHandler_SubmitJobLimits(t *testing.T) {
+	assert := assert.New(t)
+	handler := NewJobHandler(nil) // Service will be injected by setupGinTestRouter
+
+	jobID := "job-" + uuid.NewString()
+	userID := "user-" + uuid.NewString()
+
+	newRequest := func() *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/jobs/"+jobID+"/submit", nil)
+		return req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, userID))
+	}
+
+	t.Run("Success_Queued", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+		mockService.On("SubmitJob", mock.Anything, jobID, userID).Return(&core.Job{ID: jobID, Status: core.JobStatusQueued}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest())
+
+		assert.Equal(http.StatusAccepted, w.Code)
+		assert.Contains(w.Body.String(), `"status":"queued"`)
+	})
+
+	t.Run("Failure_LimitReached", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+		mockService.On("SubmitJob", mock.Anything, jobID, userID).
+			Return(nil, &LimitError{Limit: LimitProjectRunning, RetryAfter: 1500 * time.Millisecond, Message: "project has 5 running jobs"}).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest())
+
+		assert.Equal(http.StatusTooManyRequests, w.Code)
+		assert.Equal("2", w.Header().Get("Retry-After"))
+		assert.Contains(w.Body.String(), `"limit":"projectRunning"`)
+	})
+}
+
+func TestJobHandler_EstimateJob(t *testing.T) {
+	assert := assert.New(t)
+	handler := NewJobHandler(nil) // Service will be injected by setupGinTestRouter
+
+	projectID := "proj-" + uuid.NewString()
+	userID := "user-" + uuid.NewString()
+	body := `{"jobType":"csv","jobConfig":"{\"parameters\":{\"recordCount\":100}}"}`
+	estimateReq := EstimateJobRequest{JobType: "csv", JobConfig: `{"parameters":{"recordCount":100}}`}
+
+	newRequest := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/jobs/estimate", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, userID))
+	}
+
+	testCases := []struct {
+		name           string
+		body           string
+		mockSetup      func(*MockJobService)
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name: "Success",
+			body: body,
+			mockSetup: func(m *MockJobService) {
+				m.On("EstimateJob", mock.Anything, projectID, userID, estimateReq).Return(&core.JobEstimate{Records: 100, OutputBytes: 10000, Basis: EstimateBasisDefault}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `"outputBytes":10000`,
+		},
+		{
+			name:           "Failure_MissingConfig",
+			body:           `{"jobType":"csv"}`,
+			mockSetup:      func(m *MockJobService) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "Invalid request body",
+		},
+		{
+			name: "Failure_Forbidden",
+			body: body,
+			mockSetup: func(m *MockJobService) {
+				m.On("EstimateJob", mock.Anything, projectID, userID, estimateReq).Return(nil, core.ErrForbidden).Once()
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   "Forbidden",
+		},
+		{
+			name: "Failure_Unavailable",
+			body: body,
+			mockSetup: func(m *MockJobService) {
+				m.On("EstimateJob", mock.Anything, projectID, userID, estimateReq).Return(nil, ErrEstimatesUnavailable).Once()
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   "ESTIMATES_UNAVAILABLE",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router, mockService := setupGinTestRouter(handler)
+			tc.mockSetup(mockService)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, newRequest(tc.body))
+
+			assert.Equal(tc.expectedStatus, w.Code)
+			assert.Contains(w.Body.String(), tc.expectedBody)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestJobHandler_CloneJob(t *testing.T) {
+	assert := assert.New(t)
+	handler := NewJobHandler(nil) // Service will be injected by setupGinTestRouter
+
+	jobID := "job-" + uuid.NewString()
+	userID := "user-" + uuid.NewString()
+
+	newRequest := func(body string) *http.Request {
+		var req *http.Request
+		if body == "" {
+			req, _ = http.NewRequest(http.MethodPost, "/jobs/"+jobID+"/clone", nil)
+		} else {
+			req, _ = http.NewRequest(http.MethodPost, "/jobs/"+jobID+"/clone", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+		}
+		return req.WithContext(context.WithValue(req.Context(), auth.UserIDKey, userID))
+	}
+
+	t.Run("Success_WithoutBody", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+		mockService.On("CloneJob", mock.Anything, jobID, userID, CloneJobRequest{}).Return(&core.Job{ID: "job-clone", ClonedFrom: jobID}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest(""))
+
+		assert.Equal(http.StatusCreated, w.Code)
+		assert.Contains(w.Body.String(), `"clonedFrom":"`+jobID+`"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Success_WithOverrides", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+		expected := CloneJobRequest{
+			ConfigOverrides: json.RawMessage(`{"rows":500}`),
+			RetryPolicy:     &core.JobRetryPolicy{MaxAttempts: 3},
+		}
+		mockService.On("CloneJob", mock.Anything, jobID, userID, expected).Return(&core.Job{ID: "job-clone"}, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest(`{"configOverrides":{"rows":500},"retryPolicy":{"maxAttempts":3}}`))
+
+		assert.Equal(http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure_InvalidBody", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest(`{"jobType":`))
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "CloneJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_InvalidConfig", func(t *testing.T) {
+		router, mockService := setupGinTestRouter(handler)
+		mockService.On("CloneJob", mock.Anything, jobID, userID, mock.Anything).
+			Return(nil, fmt.Errorf("%w: configOverrides must be JSON", ErrInvalidJobConfig)).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newRequest(`{"jobConfig":"{}"}`))
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+		assert.Contains(w.Body.String(), "INVALID_JOB_CONFIG")
+	})
+}
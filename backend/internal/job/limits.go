@@ -0,0 +1,228 @@
+package job
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Job limit errors
+var (
+	// ErrJobLimitExceeded is wrapped by every *LimitError.
+	ErrJobLimitExceeded    = errors.New("job limit exceeded")
+	ErrRecordLimitExceeded = errors.New("job exceeds the record limit")
+)
+
+// Limits a LimitError can report.
+const (
+	LimitCreateRate      = "createRate"      // Jobs created per user per minute
+	LimitProjectRunning  = "projectRunning"  // Running jobs per project
+	LimitCustomerRunning = "customerRunning" // Running jobs across a customer's projects
+)
+
+const (
+	// createRateWindow is the window the creation rate limit counts jobs in.
+	createRateWindow = time.Minute
+
+	// capacityRetryAfter is the wait suggested once a concurrency limit is reached, as
+	// running jobs finish at unpredictable times.
+	capacityRetryAfter = 30 * time.Second
+
+	// customerProjectsPageSize is the page size used when listing a customer's projects.
+	customerProjectsPageSize = 100
+)
+
+// LimitError reports a job limit that was reached and when trying again may succeed.
+type LimitError struct {
+	Limit      string        // One of the Limit* constants
+	RetryAfter time.Duration // Suggested wait before trying again
+	Message    string
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrJobLimitExceeded, e.Message)
+}
+
+// Unwrap lets errors.Is match ErrJobLimitExceeded.
+func (e *LimitError) Unwrap() error {
+	return ErrJobLimitExceeded
+}
+
+// LimitConfig configures the limits on job creation and submission. A zero limit is not
+// enforced.
+type LimitConfig struct {
+	CreatesPerUserPerMinute int   // Jobs a user may create, retry or clone per minute
+	MaxRunningPerProject    int   // Jobs running at once in one project
+	MaxRunningPerCustomer   int   // Jobs running at once across the projects of one customer
+	MaxRecordsPerJob        int64 // Records one job may generate, summed over its tables
+	QueueOverLimit          bool  // Queue submissions over a concurrency limit instead of rejecting them
+}
+
+// Limiter enforces a LimitConfig. Its counts live in the repositories, so the limits hold
+// across replicas. Concurrency limits count running jobs when a job is submitted, so
+// submissions racing on different replicas may briefly exceed them by a job or two; queued
+// jobs are submitted by a single QueueDispatcher and never do. A nil *Limiter enforces
+// nothing.
+type Limiter struct {
+	config      LimitConfig
+	projectRepo core.ProjectRepository
+	jobRepo     core.JobRepository
+	counters    core.RateLimitRepository
+	now         func() time.Time
+}
+
+// NewLimiter creates a new Limiter.
+func NewLimiter(config LimitConfig, projectRepo core.ProjectRepository, jobRepo core.JobRepository, counters core.RateLimitRepository) *Limiter {
+	if projectRepo == nil || jobRepo == nil || counters == nil {
+		panic("job.NewLimiter: all dependencies are required")
+	}
+	return &Limiter{
+		config:      config,
+		projectRepo: projectRepo,
+		jobRepo:     jobRepo,
+		counters:    counters,
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// queueOverLimit reports whether submissions over a concurrency limit are queued.
+func (l *Limiter) queueOverLimit() bool {
+	return l != nil && l.config.QueueOverLimit
+}
+
+// checkRecords returns ErrRecordLimitExceeded if a job config asks for more records than
+// one job may generate.
+func (l *Limiter) checkRecords(jobConfig string) error {
+	if l == nil || l.config.MaxRecordsPerJob <= 0 {
+		return nil
+	}
+	if records := RecordCount(jobConfig); records > l.config.MaxRecordsPerJob {
+		return fmt.Errorf("%w: the job asks for %d records, the limit is %d", ErrRecordLimitExceeded, records, l.config.MaxRecordsPerJob)
+	}
+	return nil
+}
+
+// takeCreateToken counts a job created by userID against the creation rate limit.
+func (l *Limiter) takeCreateToken(ctx context.Context, userID string) error {
+	if l == nil || l.config.CreatesPerUserPerMinute <= 0 {
+		return nil
+	}
+	now := l.now()
+	windowStart := now.Truncate(createRateWindow)
+	taken, err := l.counters.TakeRateLimitToken(ctx, "job-create:"+userID, windowStart, createRateWindow, l.config.CreatesPerUserPerMinute)
+	if err != nil {
+		return fmt.Errorf("failed to check job creation rate: %w", err)
+	}
+	if !taken {
+		return &LimitError{
+			Limit:      LimitCreateRate,
+			RetryAfter: windowStart.Add(createRateWindow).Sub(now),
+			Message:    fmt.Sprintf("at most %d jobs can be created per minute", l.config.CreatesPerUserPerMinute),
+		}
+	}
+	return nil
+}
+
+// checkCapacity returns a *LimitError if another job may not start running in proj.
+func (l *Limiter) checkCapacity(ctx context.Context, proj *core.Project) error {
+	if l == nil {
+		return nil
+	}
+	// 1. Jobs running in the project
+	if limit := l.config.MaxRunningPerProject; limit > 0 {
+		running, err := l.jobRepo.CountJobs(ctx, []string{proj.ID}, core.JobStatusRunning)
+		if err != nil {
+			return fmt.Errorf("failed to count running jobs: %w", err)
+		}
+		if running >= limit {
+			return &LimitError{
+				Limit:      LimitProjectRunning,
+				RetryAfter: capacityRetryAfter,
+				Message:    fmt.Sprintf("project %s already has %d running jobs, the limit is %d", proj.ID, running, limit),
+			}
+		}
+	}
+
+	// 2. Jobs running across the customer's projects
+	if limit := l.config.MaxRunningPerCustomer; limit > 0 && ProjectCustomer(proj) != "" {
+		projectIDs, err := l.customerProjectIDs(ctx, proj)
+		if err != nil {
+			return err
+		}
+		running, err := l.jobRepo.CountJobs(ctx, projectIDs, core.JobStatusRunning)
+		if err != nil {
+			return fmt.Errorf("failed to count running jobs: %w", err)
+		}
+		if running >= limit {
+			return &LimitError{
+				Limit:      LimitCustomerRunning,
+				RetryAfter: capacityRetryAfter,
+				Message:    fmt.Sprintf("the projects of this customer already have %d running jobs, the limit is %d", running, limit),
+			}
+		}
+	}
+	return nil
+}
+
+// customerProjectIDs returns the IDs of the active projects that belong to proj's customer.
+// Projects created without a CustomerID belong to their owner, so for those the owner's
+// projects that also lack one are included.
+func (l *Limiter) customerProjectIDs(ctx context.Context, proj *core.Project) ([]string, error) {
+	customer := ProjectCustomer(proj)
+	projects, err := l.projectRepo.ListProjectsByCustomer(ctx, customer, core.ProjectStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects of customer %s: %w", customer, err)
+	}
+	var ids []string
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+	if proj.CustomerID != "" {
+		return ids, nil
+	}
+
+	// The customer is a user: add the projects they own without a CustomerID
+	for offset := 0; ; offset += customerProjectsPageSize {
+		owned, err := l.projectRepo.ListProjects(ctx, customer, core.ProjectStatusActive, customerProjectsPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects of owner %s: %w", customer, err)
+		}
+		for _, p := range owned {
+			if p.CustomerID == "" && ProjectCustomer(p) == customer {
+				ids = append(ids, p.ID)
+			}
+		}
+		if len(owned) < customerProjectsPageSize {
+			return ids, nil
+		}
+	}
+}
+
+// ProjectCustomer returns the customer a project belongs to: its CustomerID, or its owner
+// for projects created without one.
+func ProjectCustomer(proj *core.Project) string {
+	if proj.CustomerID != "" {
+		return proj.CustomerID
+	}
+	var owners []string
+	for userID, role := range proj.TeamMembers {
+		if role == core.RoleOwner {
+			owners = append(owners, userID)
+		}
+	}
+	if len(owners) == 0 {
+		return ""
+	}
+	sort.Strings(owners)
+	return owners[0]
+}
+
+// RecordCount returns how many records a job config asks for: the sum of its tables'
+// recordCount, each defaulting to parameters.recordCount, or parameters.recordCount for a
+// single-table config. Configs that are not JSON objects count as zero.
+func RecordCount(jobConfig string) int64 {
+	return jobShape("", jobConfig).records
+}
@@ -0,0 +1,230 @@
+package job
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockRateLimitRepository is a mock implementation of core.RateLimitRepository.
+type MockRateLimitRepository struct {
+	mock.Mock
+}
+
+func (m *MockRateLimitRepository) TakeRateLimitToken(ctx context.Context, key string, windowStart time.Time, window time.Duration, limit int) (bool, error) {
+	args := m.Called(ctx, key, windowStart, window, limit)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockProjectRepository) ListProjects(ctx context.Context, userID string, statusFilter string, limit, offset int) ([]*core.Project, error) {
+	args := m.Called(ctx, userID, statusFilter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) ListProjectsByCustomer(ctx context.Context, customerID string, statusFilter string) ([]*core.Project, error) {
+	args := m.Called(ctx, customerID, statusFilter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Project), args.Error(1)
+}
+
+var limitsNow = time.Date(2026, 5, 4, 10, 0, 15, 0, time.UTC)
+
+func setupTestLimiter(cfg LimitConfig) (*Limiter, *MockProjectRepository, *MockJobRepository, *MockRateLimitRepository) {
+	projectRepo := new(MockProjectRepository)
+	jobRepo := new(MockJobRepository)
+	counters := new(MockRateLimitRepository)
+	limiter := NewLimiter(cfg, projectRepo, jobRepo, counters)
+	limiter.now = func() time.Time { return limitsNow }
+	return limiter, projectRepo, jobRepo, counters
+}
+
+func TestRecordCount(t *testing.T) {
+	cases := []struct {
+		name   string
+		config string
+		want   int64
+	}{
+		{"SingleTable", `{"parameters":{"recordCount":500},"schema":[]}`, 500},
+		{"Tables", `{"parameters":{"recordCount":100},"tables":[{"name":"a","recordCount":20},{"name":"b"}]}`, 120},
+		{"NoCount", `{"rows":10}`, 0},
+		{"NotJSON", `rows=10`, 0},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, RecordCount(tc.config))
+		})
+	}
+}
+
+func TestLimiter_TakeCreateToken(t *testing.T) {
+	ctx := context.Background()
+	windowStart := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Success_UnderLimit", func(t *testing.T) {
+		limiter, _, _, counters := setupTestLimiter(LimitConfig{CreatesPerUserPerMinute: 5})
+		counters.On("TakeRateLimitToken", ctx, "job-create:user-1", windowStart, time.Minute, 5).Return(true, nil).Once()
+
+		assert.NoError(t, limiter.takeCreateToken(ctx, "user-1"))
+		counters.AssertExpectations(t)
+	})
+
+	t.Run("Failure_LimitReached", func(t *testing.T) {
+		limiter, _, _, counters := setupTestLimiter(LimitConfig{CreatesPerUserPerMinute: 5})
+		counters.On("TakeRateLimitToken", ctx, "job-create:user-1", windowStart, time.Minute, 5).Return(false, nil).Once()
+
+		err := limiter.takeCreateToken(ctx, "user-1")
+
+		var limitErr *LimitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.ErrorIs(t, err, ErrJobLimitExceeded)
+		assert.Equal(t, LimitCreateRate, limitErr.Limit)
+		assert.Equal(t, 45*time.Second, limitErr.RetryAfter) // Until the next window
+	})
+
+	t.Run("Success_Unlimited", func(t *testing.T) {
+		var limiter *Limiter
+		assert.NoError(t, limiter.takeCreateToken(ctx, "user-1"))
+	})
+}
+
+func TestLimiter_CheckCapacity(t *testing.T) {
+	ctx := context.Background()
+	proj := &core.Project{ID: "proj-1", TeamMembers: map[string]core.Role{"owner": core.RoleOwner, "member": core.RoleMember}}
+
+	t.Run("Success_UnderLimits", func(t *testing.T) {
+		limiter, projectRepo, jobRepo, _ := setupTestLimiter(LimitConfig{MaxRunningPerProject: 3, MaxRunningPerCustomer: 5})
+		jobRepo.On("CountJobs", ctx, []string{"proj-1"}, core.JobStatusRunning).Return(2, nil).Once()
+		projectRepo.On("ListProjectsByCustomer", ctx, "owner", core.ProjectStatusActive).Return([]*core.Project{
+			{ID: "proj-3", CustomerID: "owner"},
+		}, nil).Once()
+		projectRepo.On("ListProjects", ctx, "owner", core.ProjectStatusActive, customerProjectsPageSize, 0).Return([]*core.Project{
+			proj,
+			{ID: "proj-2", TeamMembers: map[string]core.Role{"owner": core.RoleOwner}},
+			{ID: "proj-shared", TeamMembers: map[string]core.Role{"owner": core.RoleMember, "other": core.RoleOwner}}, // Another customer's
+			{ID: "proj-billed", CustomerID: "cust-2", TeamMembers: map[string]core.Role{"owner": core.RoleOwner}},     // Billed to a customer
+		}, nil).Once()
+		jobRepo.On("CountJobs", ctx, []string{"proj-3", "proj-1", "proj-2"}, core.JobStatusRunning).Return(4, nil).Once()
+
+		assert.NoError(t, limiter.checkCapacity(ctx, proj))
+		jobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_ProjectLimit", func(t *testing.T) {
+		limiter, _, jobRepo, _ := setupTestLimiter(LimitConfig{MaxRunningPerProject: 3})
+		jobRepo.On("CountJobs", ctx, []string{"proj-1"}, core.JobStatusRunning).Return(3, nil).Once()
+
+		err := limiter.checkCapacity(ctx, proj)
+
+		var limitErr *LimitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, LimitProjectRunning, limitErr.Limit)
+		assert.Equal(t, capacityRetryAfter, limitErr.RetryAfter)
+	})
+
+	t.Run("Failure_CustomerLimit", func(t *testing.T) {
+		limiter, projectRepo, jobRepo, _ := setupTestLimiter(LimitConfig{MaxRunningPerCustomer: 5})
+		customerProj := &core.Project{ID: "proj-1", CustomerID: "cust-1"}
+		projectRepo.On("ListProjectsByCustomer", ctx, "cust-1", core.ProjectStatusActive).
+			Return([]*core.Project{customerProj, {ID: "proj-2", CustomerID: "cust-1"}}, nil).Once()
+		jobRepo.On("CountJobs", ctx, []string{"proj-1", "proj-2"}, core.JobStatusRunning).Return(5, nil).Once()
+
+		err := limiter.checkCapacity(ctx, customerProj)
+
+		var limitErr *LimitError
+		require.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, LimitCustomerRunning, limitErr.Limit)
+		projectRepo.AssertNotCalled(t, "ListProjects", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestJobService_Limits(t *testing.T) {
+	ctx := context.Background()
+	proj := &core.Project{ID: "proj-1", Status: core.ProjectStatusActive, TeamMembers: map[string]core.Role{"member": core.RoleMember}}
+	pending := func() *core.Job {
+		return &core.Job{ID: "job-1", ProjectID: "proj-1", UserID: "member", Status: core.JobStatusPending, JobType: "csv", JobConfig: `{"parameters":{"recordCount":10}}`}
+	}
+	setup := func(cfg LimitConfig) (JobService, *MockJobRepository, *MockProjectService, *MockPipelineClient, *MockRateLimitRepository) {
+		jobRepo, projectSvc, pipeline := new(MockJobRepository), new(MockProjectService), new(MockPipelineClient)
+		counters := new(MockRateLimitRepository)
+		limiter := NewLimiter(cfg, new(MockProjectRepository), jobRepo, counters)
+		limiter.now = func() time.Time { return limitsNow }
+		service := NewJobService(jobRepo, projectSvc, pipeline, JobServiceOptions{Limiter: limiter})
+		return service, jobRepo, projectSvc, pipeline, counters
+	}
+
+	t.Run("Failure_CreateRateLimited", func(t *testing.T) {
+		service, jobRepo, projectSvc, _, counters := setup(LimitConfig{CreatesPerUserPerMinute: 1})
+		projectSvc.On("GetProjectByID", ctx, "proj-1", "member").Return(proj, nil).Once()
+		counters.On("TakeRateLimitToken", ctx, "job-create:member", mock.Anything, time.Minute, 1).Return(false, nil).Once()
+
+		_, err := service.CreateJob(ctx, "proj-1", "member", CreateJobRequest{ProjectID: "proj-1", JobType: "csv", JobConfig: `{}`})
+
+		assert.ErrorIs(t, err, ErrJobLimitExceeded)
+		jobRepo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_CreateTooManyRecords", func(t *testing.T) {
+		service, jobRepo, projectSvc, _, counters := setup(LimitConfig{CreatesPerUserPerMinute: 1, MaxRecordsPerJob: 1000})
+		projectSvc.On("GetProjectByID", ctx, "proj-1", "member").Return(proj, nil).Once()
+
+		_, err := service.CreateJob(ctx, "proj-1", "member", CreateJobRequest{ProjectID: "proj-1", JobType: "csv", JobConfig: `{"parameters":{"recordCount":5000}}`})
+
+		assert.ErrorIs(t, err, ErrRecordLimitExceeded)
+		counters.AssertNotCalled(t, "TakeRateLimitToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		jobRepo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_SubmitOverLimit", func(t *testing.T) {
+		service, jobRepo, projectSvc, pipeline, _ := setup(LimitConfig{MaxRunningPerProject: 2})
+		jobRepo.On("GetJobByID", ctx, "job-1").Return(pending(), nil).Once()
+		projectSvc.On("GetProjectByID", ctx, "proj-1", "member").Return(proj, nil).Once()
+		jobRepo.On("CountJobs", ctx, []string{"proj-1"}, core.JobStatusRunning).Return(2, nil).Once()
+
+		_, err := service.SubmitJob(ctx, "job-1", "member")
+
+		assert.ErrorIs(t, err, ErrJobLimitExceeded)
+		pipeline.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		jobRepo.AssertNotCalled(t, "TransitionJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_SubmitOverLimitQueued", func(t *testing.T) {
+		service, jobRepo, projectSvc, pipeline, _ := setup(LimitConfig{MaxRunningPerProject: 2, QueueOverLimit: true})
+		jobRepo.On("GetJobByID", ctx, "job-1").Return(pending(), nil).Once()
+		projectSvc.On("GetProjectByID", ctx, "proj-1", "member").Return(proj, nil).Once()
+		jobRepo.On("CountJobs", ctx, []string{"proj-1"}, core.JobStatusRunning).Return(2, nil).Once()
+		jobRepo.On("TransitionJobStatus", ctx, "job-1", core.JobStatusPending, core.JobStatusQueued, "", (*time.Time)(nil), (*time.Time)(nil), "").Return(nil).Once()
+
+		job, err := service.SubmitJob(ctx, "job-1", "member")
+
+		require.NoError(t, err)
+		assert.Equal(t, core.JobStatusQueued, job.Status)
+		pipeline.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		jobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_QueuedJobSubmittedWithCapacity", func(t *testing.T) {
+		service, jobRepo, projectSvc, pipeline, _ := setup(LimitConfig{MaxRunningPerProject: 2, QueueOverLimit: true})
+		queued := pending()
+		queued.Status = core.JobStatusQueued
+		jobRepo.On("GetJobByID", ctx, "job-1").Return(queued, nil).Once()
+		projectSvc.On("GetProjectByID", ctx, "proj-1", "member").Return(proj, nil).Once()
+		jobRepo.On("CountJobs", ctx, []string{"proj-1"}, core.JobStatusRunning).Return(1, nil).Once()
+		pipeline.On("Submit", mock.Anything, queued.JobConfig, "csv", "proj-1").Return("pipe-1", nil).Once()
+		jobRepo.On("TransitionJobStatus", ctx, "job-1", core.JobStatusQueued, core.JobStatusRunning, "pipe-1", mock.AnythingOfType("*time.Time"), (*time.Time)(nil), "").Return(nil).Once()
+
+		job, err := service.SubmitJob(ctx, "job-1", "member")
+
+		require.NoError(t, err)
+		assert.Equal(t, core.JobStatusRunning, job.Status)
+	})
+}
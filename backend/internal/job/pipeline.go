@@ -3,11 +3,33 @@ package job
 import (
 	"SynDataGen/backend/internal/core"
 	"context"
+	"errors"
+	"time"
 )
 
+// ErrPipelineUnavailable marks pipeline errors that are transient (timeouts, 5xx responses, an
+// open circuit breaker). Callers may retry the same request later.
+var ErrPipelineUnavailable = errors.New("pipeline temporarily unavailable")
+
+type idempotencyKeyCtxKey struct{}
+
+// WithIdempotencyKey attaches the key a PipelineClient should send with a submission so that
+// repeated attempts for the same job never create more than one pipeline job.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtxKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the key set by WithIdempotencyKey, or "".
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKeyCtxKey{}).(string)
+	return key
+}
+
 // PipelineClient defines the interface for interacting with the external data generation pipeline API.
 type PipelineClient interface {
 	// Submit sends a job configuration to the pipeline and returns the pipeline's unique identifier for the job.
+	// Errors wrapping ErrPipelineUnavailable are transient: the pipeline may or may not have
+	// accepted the job, so retry with the same idempotency key, which is safe.
 	Submit(ctx context.Context, jobConfig string, jobType string, projectID string) (pipelineJobID string, err error)
 
 	// CheckStatus queries the pipeline for the status of a specific job using the pipeline's job ID.
@@ -17,5 +39,50 @@ type PipelineClient interface {
 	// Cancel sends a cancellation request to the pipeline for a specific job.
 	Cancel(ctx context.Context, pipelineJobID string) error
 
-	// TODO: Add methods for getting logs or results directly from the pipeline if needed.
+	// ResultURI returns where a completed job's output was written, or "" if the pipeline
+	// does not report output locations.
+	ResultURI(ctx context.Context, pipelineJobID string) (string, error)
+
+	// GetLogs returns up to limit log entries of a job with a sequence number greater than
+	// since, oldest first. Pass since 0 to read from the beginning.
+	GetLogs(ctx context.Context, pipelineJobID string, since int64, limit int) ([]LogEntry, error)
+}
+
+// ProgressReporter is implemented by pipeline clients that know how far a running job has
+// got. The job service announces progress changes for clients that do.
+type ProgressReporter interface {
+	// Progress returns the percentage of a job completed so far, from 0 to 100.
+	Progress(ctx context.Context, pipelineJobID string) (int, error)
+}
+
+// OutputSizeReporter is implemented by pipeline clients that know how much output a
+// completed job wrote. The job service records it with the job's actuals.
+type OutputSizeReporter interface {
+	// OutputBytes returns the total size of a completed job's output files.
+	OutputBytes(ctx context.Context, pipelineJobID string) (int64, error)
+}
+
+// RecordCountReporter is implemented by pipeline clients that know how many rows a
+// completed job generated. The job service records it with the job's actuals, and
+// usage is billed on it instead of the requested record count.
+type RecordCountReporter interface {
+	// GeneratedRecords returns the total rows written across a completed job's tables.
+	GeneratedRecords(ctx context.Context, pipelineJobID string) (int64, error)
+}
+
+// Log levels reported in LogEntry.Level.
+const (
+	LogLevelInfo  = "INFO"
+	LogLevelWarn  = "WARN"
+	LogLevelError = "ERROR"
+)
+
+// LogEntry is one line of a job's pipeline log. Sequence numbers start at 1 and grow by one
+// per entry, so the last sequence read is the cursor for the next page.
+type LogEntry struct {
+	Sequence  int64     `json:"sequence"`
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Stage     string    `json:"stage,omitempty"`
+	Message   string    `json:"message"`
 }
@@ -0,0 +1,132 @@
+package job
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/lease"
+	"SynDataGen/backend/internal/platform/logger"
+	"SynDataGen/backend/internal/project"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultQueueDispatchInterval is how often the QueueDispatcher submits queued jobs when no
+// interval is configured.
+const DefaultQueueDispatchInterval = 10 * time.Second
+
+// queueLeaseName is the lease held by the replica that submits queued jobs.
+const queueLeaseName = "job-queue-dispatcher"
+
+// QueueDispatcherConfig holds configuration for the QueueDispatcher.
+type QueueDispatcherConfig struct {
+	Interval time.Duration // Time between ticks; defaults to DefaultQueueDispatchInterval
+	HolderID string        // Identifies this replica in the lease; required
+}
+
+// QueueDispatcher submits jobs queued by a concurrency limit once their project and
+// customer have capacity again, oldest first. Only the replica holding the dispatcher
+// lease submits, so queued jobs never race each other past a limit.
+type QueueDispatcher struct {
+	projectRepo core.ProjectRepository
+	jobRepo     core.JobRepository
+	service     JobService
+	runner      *lease.Runner
+}
+
+// NewQueueDispatcher creates a new QueueDispatcher.
+func NewQueueDispatcher(projectRepo core.ProjectRepository, jobRepo core.JobRepository, leaseRepo core.LeaseRepository, service JobService, cfg QueueDispatcherConfig) *QueueDispatcher {
+	if projectRepo == nil || jobRepo == nil || leaseRepo == nil || service == nil {
+		panic("job.NewQueueDispatcher: all dependencies are required")
+	}
+	if cfg.HolderID == "" {
+		panic("job.NewQueueDispatcher: HolderID is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultQueueDispatchInterval
+	}
+	return &QueueDispatcher{
+		projectRepo: projectRepo,
+		jobRepo:     jobRepo,
+		service:     service,
+		runner:      lease.NewRunner(leaseRepo, queueLeaseName, cfg.HolderID, cfg.Interval),
+	}
+}
+
+// Run dispatches queued jobs immediately and then once per interval until ctx is cancelled.
+func (d *QueueDispatcher) Run(ctx context.Context) {
+	d.runner.Run(ctx, func(ctx context.Context) error {
+		_, err := d.dispatch(ctx)
+		return err
+	})
+}
+
+// DispatchOnce submits the queued jobs that fit under the limits, if this replica holds the
+// lease, and returns how many were submitted.
+func (d *QueueDispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	var submitted int
+	_, err := d.runner.TickOnce(ctx, func(ctx context.Context) (err error) {
+		submitted, err = d.dispatch(ctx)
+		return err
+	})
+	return submitted, err
+}
+
+// dispatch submits the queued jobs that fit under the limits. Jobs still over a limit stay
+// queued; jobs whose creator may no longer submit them fail.
+func (d *QueueDispatcher) dispatch(ctx context.Context) (int, error) {
+	// 1. Collect the queued jobs of active projects, oldest first
+	projectIDs, err := unarchivedProjectIDs(ctx, d.projectRepo)
+	if err != nil {
+		return 0, err
+	}
+	queued, err := listJobsByStatus(ctx, d.jobRepo, projectIDs, core.JobStatusQueued)
+	if err != nil {
+		return 0, err
+	}
+	sort.SliceStable(queued, func(i, j int) bool {
+		return queued[i].CreatedAt.Before(queued[j].CreatedAt)
+	})
+
+	// 2. Submit each on behalf of the user who created it, skipping projects found at capacity
+	full := make(map[string]bool)
+	var submitted, failed int
+	for _, job := range queued {
+		if ctx.Err() != nil {
+			return submitted, ctx.Err()
+		}
+		if full[job.ProjectID] {
+			continue
+		}
+		_, err := d.service.SubmitJob(ctx, job.ID, job.UserID)
+		switch {
+		case err == nil:
+			submitted++
+		case errors.Is(err, ErrJobLimitExceeded):
+			full[job.ProjectID] = true // Later jobs of the project wait their turn
+		case errors.Is(err, ErrPipelineUnavailable):
+			return submitted, err // Nothing can be submitted until the pipeline is back
+		case errors.Is(err, core.ErrForbidden) || errors.Is(err, project.ErrProjectAccessDenied):
+			// The creator lost access to the project; the job would otherwise stay queued forever
+			reason := fmt.Sprintf("Queued job could not be submitted: %v", err)
+			if _, failErr := d.service.FailQueuedJob(ctx, job.ID, reason); failErr != nil {
+				logger.Logger.Warn("Failed to fail unauthorized queued job", zap.String("jobID", job.ID), zap.Error(failErr))
+				failed++
+			}
+		default:
+			logger.Logger.Warn("Failed to submit queued job", zap.String("jobID", job.ID), zap.Error(err))
+			failed++
+		}
+	}
+
+	if submitted > 0 {
+		logger.Logger.Info("Submitted queued jobs", zap.Int("submitted", submitted), zap.Int("queued", len(queued)))
+	}
+	if failed > 0 {
+		return submitted, fmt.Errorf("failed to submit %d of %d queued jobs", failed, len(queued))
+	}
+	return submitted, nil
+}
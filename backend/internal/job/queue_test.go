@@ -0,0 +1,114 @@
+package job
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/project"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockLeaseRepository is a mock implementation of core.LeaseRepository.
+type MockLeaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockLeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, name, holder, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestQueueDispatcher_DispatchOnce(t *testing.T) {
+	ctx := context.Background()
+	projects := []*core.Project{
+		{ID: "proj-a", Status: core.ProjectStatusActive},
+		{ID: "proj-b", Status: core.ProjectStatusActive},
+		{ID: "proj-archived", Status: core.ProjectStatusArchived},
+	}
+	base := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+	setup := func() (*QueueDispatcher, *MockProjectRepository, *MockJobRepository, *MockLeaseRepository, *MockJobService) {
+		projectRepo := new(MockProjectRepository)
+		jobRepo := new(MockJobRepository)
+		leaseRepo := new(MockLeaseRepository)
+		service := new(MockJobService)
+		dispatcher := NewQueueDispatcher(projectRepo, jobRepo, leaseRepo, service, QueueDispatcherConfig{Interval: time.Minute, HolderID: "replica-1"})
+		return dispatcher, projectRepo, jobRepo, leaseRepo, service
+	}
+
+	t.Run("Success_OldestFirstSkippingFullProjects", func(t *testing.T) {
+		dispatcher, projectRepo, jobRepo, leaseRepo, service := setup()
+		leaseRepo.On("AcquireLease", ctx, queueLeaseName, "replica-1", 2*time.Minute).Return(true, nil).Once()
+		projectRepo.On("ListAllProjects", ctx).Return(projects, nil).Once()
+		// Listed by ID, as the repository does, not by age
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusQueued, watchPageSize, "").Return([]*core.Job{
+			{ID: "a-3", ProjectID: "proj-a", UserID: "u1", CreatedAt: base.Add(3 * time.Minute)},
+			{ID: "b-1", ProjectID: "proj-b", UserID: "u2", CreatedAt: base.Add(2 * time.Minute)},
+			{ID: "a-2", ProjectID: "proj-a", UserID: "u1", CreatedAt: base.Add(time.Minute)},
+			{ID: "a-1", ProjectID: "proj-a", UserID: "u1", CreatedAt: base},
+		}, nil).Once()
+		service.On("SubmitJob", ctx, "a-1", "u1").Return(&core.Job{ID: "a-1", Status: core.JobStatusRunning}, nil).Once()
+		service.On("SubmitJob", ctx, "a-2", "u1").Return(nil, &LimitError{Limit: LimitProjectRunning}).Once()
+		service.On("SubmitJob", ctx, "b-1", "u2").Return(&core.Job{ID: "b-1", Status: core.JobStatusRunning}, nil).Once()
+
+		submitted, err := dispatcher.DispatchOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, submitted)
+		service.AssertExpectations(t)
+		service.AssertNotCalled(t, "SubmitJob", ctx, "a-3", "u1") // proj-a is at capacity
+	})
+
+	t.Run("Failure_PipelineUnavailableStopsTick", func(t *testing.T) {
+		dispatcher, projectRepo, jobRepo, leaseRepo, service := setup()
+		leaseRepo.On("AcquireLease", ctx, queueLeaseName, "replica-1", 2*time.Minute).Return(true, nil).Once()
+		projectRepo.On("ListAllProjects", ctx).Return(projects, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusQueued, watchPageSize, "").Return([]*core.Job{
+			{ID: "b-1", ProjectID: "proj-b", UserID: "u2", CreatedAt: base.Add(time.Minute)},
+			{ID: "a-1", ProjectID: "proj-a", UserID: "u1", CreatedAt: base},
+		}, nil).Once()
+		service.On("SubmitJob", ctx, "a-1", "u1").Return(nil, fmt.Errorf("pipeline submission failed: %w", ErrPipelineUnavailable)).Once()
+
+		_, err := dispatcher.DispatchOnce(ctx)
+
+		assert.ErrorIs(t, err, ErrPipelineUnavailable)
+		service.AssertNotCalled(t, "SubmitJob", ctx, "b-1", "u2")
+	})
+
+	t.Run("Success_UnauthorizedJobFails", func(t *testing.T) {
+		dispatcher, projectRepo, jobRepo, leaseRepo, service := setup()
+		leaseRepo.On("AcquireLease", ctx, queueLeaseName, "replica-1", 2*time.Minute).Return(true, nil).Once()
+		projectRepo.On("ListAllProjects", ctx).Return(projects, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusQueued, watchPageSize, "").Return([]*core.Job{
+			{ID: "a-1", ProjectID: "proj-a", UserID: "removed", CreatedAt: base},
+			{ID: "a-2", ProjectID: "proj-a", UserID: "u1", CreatedAt: base.Add(time.Minute)},
+		}, nil).Once()
+		service.On("SubmitJob", ctx, "a-1", "removed").Return(nil, fmt.Errorf("project access check failed: %w", project.ErrProjectAccessDenied)).Once()
+		service.On("FailQueuedJob", ctx, "a-1", mock.MatchedBy(func(reason string) bool {
+			return strings.Contains(reason, project.ErrProjectAccessDenied.Error())
+		})).Return(&core.Job{ID: "a-1", Status: core.JobStatusFailed}, nil).Once()
+		service.On("SubmitJob", ctx, "a-2", "u1").Return(&core.Job{ID: "a-2", Status: core.JobStatusRunning}, nil).Once()
+
+		submitted, err := dispatcher.DispatchOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, submitted)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("Success_LeaseHeldElsewhere", func(t *testing.T) {
+		dispatcher, projectRepo, _, leaseRepo, _ := setup()
+		leaseRepo.On("AcquireLease", ctx, queueLeaseName, "replica-1", 2*time.Minute).Return(false, nil).Once()
+
+		submitted, err := dispatcher.DispatchOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, submitted)
+		projectRepo.AssertNotCalled(t, "ListAllProjects", mock.Anything)
+	})
+}
@@ -0,0 +1,139 @@
+package job
+
+import (
+	"SynDataGen/backend/internal/core"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Error classes of failed pipeline runs, used by JobRetryPolicy.RetryOn.
+const (
+	ErrorClassTransient = "transient" // Timeouts, unavailable dependencies, preemption
+	ErrorClassResource  = "resource"  // Exhausted quota, memory or disk
+	ErrorClassInvalid   = "invalid"   // The config or input was rejected; retrying cannot help
+	ErrorClassInternal  = "internal"  // Any other pipeline failure
+)
+
+// Retry policy limits.
+const (
+	maxRetryAttempts       = 10
+	maxRetryBackoffSeconds = 3600
+	maxRetryDelay          = 6 * time.Hour
+)
+
+// errorClassKeywords maps lower-case fragments of pipeline error messages to their class,
+// checked in order.
+var errorClassKeywords = []struct {
+	class    string
+	keywords []string
+}{
+	{ErrorClassInvalid, []string{"invalid", "validation", "malformed", "unsupported"}},
+	{ErrorClassResource, []string{"quota", "out of memory", "disk full", "no space", "resource exhausted", "resources exhausted"}},
+	{ErrorClassTransient, []string{"timeout", "timed out", "deadline exceeded", "unavailable", "temporar", "connection reset", "connection refused", "preempt", "try again"}},
+}
+
+// retryNamespace derives the IDs of automatic retries from the failed job's ID, so a
+// failure observed twice schedules a single retry.
+var retryNamespace = uuid.MustParse("5b7c2f4e-1d0a-4c39-9e2b-6f8a1c3d7e90")
+
+// AutomaticRetryID returns the ID the automatic retry of a failed job is created with.
+func AutomaticRetryID(jobID string) string {
+	return uuid.NewSHA1(retryNamespace, []byte(jobID)).String()
+}
+
+// ClassifyJobError returns the error class of a pipeline failure message.
+func ClassifyJobError(message string) string {
+	lower := strings.ToLower(message)
+	for _, entry := range errorClassKeywords {
+		for _, keyword := range entry.keywords {
+			if strings.Contains(lower, keyword) {
+				return entry.class
+			}
+		}
+	}
+	return ErrorClassInternal
+}
+
+// validateRetryPolicy checks a policy supplied with a job.
+func validateRetryPolicy(policy *core.JobRetryPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.MaxAttempts < 1 || policy.MaxAttempts > maxRetryAttempts {
+		return fmt.Errorf("%w: retryPolicy.maxAttempts must be between 1 and %d", ErrInvalidJobConfig, maxRetryAttempts)
+	}
+	if policy.BackoffSeconds < 0 || policy.BackoffSeconds > maxRetryBackoffSeconds {
+		return fmt.Errorf("%w: retryPolicy.backoffSeconds must be between 0 and %d", ErrInvalidJobConfig, maxRetryBackoffSeconds)
+	}
+	for _, class := range policy.RetryOn {
+		switch class {
+		case ErrorClassTransient, ErrorClassResource, ErrorClassInvalid, ErrorClassInternal:
+		default:
+			return fmt.Errorf("%w: unknown error class %q in retryPolicy.retryOn", ErrInvalidJobConfig, class)
+		}
+	}
+	return nil
+}
+
+// retryDelay reports whether a job that failed with errMsg should be retried automatically,
+// and after how long.
+func retryDelay(job *core.Job, errMsg string) (time.Duration, bool) {
+	policy := job.RetryPolicy
+	attempt := max(job.Attempt, 1)
+	if policy == nil || attempt >= policy.MaxAttempts {
+		return 0, false
+	}
+	retryOn := policy.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = []string{ErrorClassTransient}
+	}
+	if !slices.Contains(retryOn, ClassifyJobError(errMsg)) {
+		return 0, false
+	}
+	delay := time.Duration(policy.BackoffSeconds) * time.Second
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay), true
+}
+
+// mergeJobConfig applies a JSON merge patch (RFC 7396) to a job config: object members
+// are merged recursively, null removes a member and any other value replaces it.
+func mergeJobConfig(config string, patch json.RawMessage) (string, error) {
+	var target, changes interface{}
+	if err := json.Unmarshal([]byte(config), &target); err != nil {
+		return "", fmt.Errorf("%w: the source job's config is not JSON, so it cannot be overridden", ErrInvalidJobConfig)
+	}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return "", fmt.Errorf("%w: configOverrides must be JSON: %v", ErrInvalidJobConfig, err)
+	}
+	merged, err := json.Marshal(mergePatch(target, changes))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode job config: %w", err)
+	}
+	return string(merged), nil
+}
+
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = mergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
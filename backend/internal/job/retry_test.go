@@ -0,0 +1,107 @@
+package job
+
+import (
+	"SynDataGen/backend/internal/core"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyJobError(t *testing.T) {
+	for message, class := range map[string]string{
+		"Deadline exceeded while writing rows": ErrorClassTransient,
+		"worker was preempted":                 ErrorClassTransient,
+		"Quota exceeded for GPUs":              ErrorClassResource,
+		"container ran out of memory":          ErrorClassResource,
+		"invalid column type 'blob'":           ErrorClassInvalid,
+		"validation failed: timeout too low":   ErrorClassInvalid, // Checked before transient keywords
+		"segmentation fault":                   ErrorClassInternal,
+		"":                                     ErrorClassInternal,
+	} {
+		assert.Equal(t, class, ClassifyJobError(message), message)
+	}
+}
+
+func TestValidateRetryPolicy(t *testing.T) {
+	assert.NoError(t, validateRetryPolicy(nil))
+	assert.NoError(t, validateRetryPolicy(&core.JobRetryPolicy{MaxAttempts: 3, BackoffSeconds: 30, RetryOn: []string{ErrorClassTransient, ErrorClassResource}}))
+
+	for name, policy := range map[string]*core.JobRetryPolicy{
+		"ZeroAttempts":     {MaxAttempts: 0},
+		"TooManyAttempts":  {MaxAttempts: maxRetryAttempts + 1},
+		"NegativeBackoff":  {MaxAttempts: 2, BackoffSeconds: -1},
+		"BackoffTooLong":   {MaxAttempts: 2, BackoffSeconds: maxRetryBackoffSeconds + 1},
+		"UnknownClassName": {MaxAttempts: 2, RetryOn: []string{"flaky"}},
+	} {
+		assert.ErrorIs(t, validateRetryPolicy(policy), ErrInvalidJobConfig, name)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := &core.JobRetryPolicy{MaxAttempts: 4, BackoffSeconds: 10}
+
+	t.Run("Success_ExponentialBackoff", func(t *testing.T) {
+		for attempt, expected := range map[int]time.Duration{0: 10 * time.Second, 1: 10 * time.Second, 2: 20 * time.Second, 3: 40 * time.Second} {
+			delay, ok := retryDelay(&core.Job{Attempt: attempt, RetryPolicy: policy}, "request timed out")
+			require.True(t, ok)
+			assert.Equal(t, expected, delay, "attempt %d", attempt)
+		}
+	})
+
+	t.Run("Success_DelayIsCapped", func(t *testing.T) {
+		long := &core.JobRetryPolicy{MaxAttempts: maxRetryAttempts, BackoffSeconds: maxRetryBackoffSeconds}
+		delay, ok := retryDelay(&core.Job{Attempt: maxRetryAttempts - 1, RetryPolicy: long}, "unavailable")
+		require.True(t, ok)
+		assert.Equal(t, maxRetryDelay, delay)
+	})
+
+	t.Run("Success_RetryOnDefaultsToTransient", func(t *testing.T) {
+		_, ok := retryDelay(&core.Job{Attempt: 1, RetryPolicy: policy}, "quota exceeded")
+		assert.False(t, ok)
+
+		withResource := &core.JobRetryPolicy{MaxAttempts: 2, RetryOn: []string{ErrorClassResource}}
+		_, ok = retryDelay(&core.Job{Attempt: 1, RetryPolicy: withResource}, "quota exceeded")
+		assert.True(t, ok)
+	})
+
+	t.Run("Failure_NoPolicyOrAttemptsLeft", func(t *testing.T) {
+		_, ok := retryDelay(&core.Job{Attempt: 1}, "timeout")
+		assert.False(t, ok)
+		_, ok = retryDelay(&core.Job{Attempt: 4, RetryPolicy: policy}, "timeout")
+		assert.False(t, ok)
+	})
+}
+
+func TestMergeJobConfig(t *testing.T) {
+	t.Run("Success_MergesRecursively", func(t *testing.T) {
+		merged, err := mergeJobConfig(
+			`{"rows":10,"output":{"format":"csv","compress":true},"seed":7}`,
+			json.RawMessage(`{"rows":20,"output":{"compress":null,"path":"out/"},"seed":null,"tables":["a"]}`),
+		)
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"rows":20,"output":{"format":"csv","path":"out/"},"tables":["a"]}`, merged)
+	})
+
+	t.Run("Success_NonObjectPatchReplaces", func(t *testing.T) {
+		merged, err := mergeJobConfig(`{"rows":10}`, json.RawMessage(`[1,2]`))
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `[1,2]`, merged)
+	})
+
+	t.Run("Failure_SourceNotJSON", func(t *testing.T) {
+		_, err := mergeJobConfig(`rows=10`, json.RawMessage(`{"rows":20}`))
+
+		assert.ErrorIs(t, err, ErrInvalidJobConfig)
+	})
+
+	t.Run("Failure_PatchNotJSON", func(t *testing.T) {
+		_, err := mergeJobConfig(`{"rows":10}`, json.RawMessage(`{rows}`))
+
+		assert.ErrorIs(t, err, ErrInvalidJobConfig)
+	})
+}
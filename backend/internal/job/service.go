@@ -2,16 +2,44 @@ package job
 
 import (
 	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/events"
 	"SynDataGen/backend/internal/platform/logger"
 	"SynDataGen/backend/internal/project" // Import project service
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// Job errors
+var (
+	ErrInvalidJobConfig     = errors.New("invalid job configuration")
+	ErrJobNotCompleted      = errors.New("job has not completed")
+	ErrJobResultUnavailable = errors.New("job result is not available")
+	ErrNoInputDataset       = errors.New("job has no input dataset")
+	ErrPIIReviewRequired    = errors.New("input dataset PII findings require review")
+	ErrJobNotRetryable      = errors.New("only failed or cancelled jobs can be retried")
+)
+
+// configSchemaSourceKey names a project dataset in a job config. When the config has no
+// "schema" section, CreateJob fills it in from the dataset's inferred schema.
+const configSchemaSourceKey = "schemaFromDataset"
+
+// configInputDatasetKey names the real dataset a job imitates, either as a dataset name
+// or as {"storageUri": "gs://<bucket>/<name>"}. Without it, schemaFromDataset is used.
+const configInputDatasetKey = "inputDataset"
+
+// qualityReportName is the file a job's fidelity report is stored in, next to its result.
+const qualityReportName = "quality-report.json"
+
 // --- Service Interface ---
 
 // JobService defines the interface for job-related business logic.
@@ -20,6 +48,8 @@ type JobService interface {
 	CreateJob(ctx context.Context, projectID, userID string, req CreateJobRequest) (*core.Job, error)
 
 	// SubmitJob submits a previously created job to the external pipeline, requiring Member role.
+	// A job over a concurrency limit is queued instead if the limits allow it, and returned
+	// with JobStatusQueued.
 	SubmitJob(ctx context.Context, jobID, userID string) (*core.Job, error)
 
 	// GetJobByID retrieves a specific job by its ID, requiring Viewer role.
@@ -31,21 +61,96 @@ type JobService interface {
 	// CancelJob requests cancellation of a job, requiring Member role.
 	CancelJob(ctx context.Context, jobID, userID string) (*core.Job, error)
 
+	// RetryJob creates a pending copy of a failed or cancelled job, linked to it through
+	// RetryOf, requiring Member role.
+	RetryJob(ctx context.Context, jobID, userID string) (*core.Job, error)
+
+	// CloneJob creates a pending copy of any job with the request's overrides applied,
+	// linked to it through ClonedFrom, requiring Member role.
+	CloneJob(ctx context.Context, jobID, userID string, req CloneJobRequest) (*core.Job, error)
+
 	// SyncJobStatus checks pipeline status, requiring Viewer role.
 	SyncJobStatus(ctx context.Context, jobID, userID string) (*core.Job, error)
 
 	// ListAllAccessibleJobs retrieves jobs across all projects accessible to the user.
 	ListAllAccessibleJobs(ctx context.Context, userID string, statusFilter string, limit, offset int) ([]*core.Job, int, error)
 
+	// GetQualityReport compares a completed job's result with its input dataset, requiring
+	// Viewer role. The report is stored next to the result and reused unless refresh is set.
+	GetQualityReport(ctx context.Context, jobID, userID string, refresh bool) (*project.FidelityReport, error)
+
+	// GetJobLogs returns a page of a job's pipeline log after the since cursor, requiring
+	// Viewer role. The job's status is synced first, so a page from a finished job is final.
+	GetJobLogs(ctx context.Context, jobID, userID string, since int64, limit int) (*JobLogPage, error)
+
+	// CancelProjectJobs cancels every pending, queued or running job in a project.
+	// It performs no authorization and is intended as a project archive hook.
+	CancelProjectJobs(ctx context.Context, projectID string) error
+
+	// RefreshJobStatus checks pipeline status like SyncJobStatus.
+	// It performs no authorization and is intended for the StatusWatcher.
+	RefreshJobStatus(ctx context.Context, jobID string) (*core.Job, error)
+
+	// FailQueuedJob marks a queued job that can never be submitted as failed with reason.
+	// It performs no authorization and is intended for the QueueDispatcher.
+	FailQueuedJob(ctx context.Context, jobID, reason string) (*core.Job, error)
+
+	// FailDueRetry marks a pending automatic retry that can never be submitted as failed
+	// with reason. It performs no authorization and is intended for the StatusWatcher.
+	FailDueRetry(ctx context.Context, jobID, reason string) (*core.Job, error)
+
+	// EstimateJob predicts the output size, duration and compute cost of a job config
+	// without creating a job, requiring Viewer role.
+	EstimateJob(ctx context.Context, projectID, userID string, req EstimateJobRequest) (*core.JobEstimate, error)
+
 	// TODO: Add methods for deleting jobs or accessing results if needed in the service layer.
 }
 
+// JobLogPage is a page of a job's pipeline log.
+type JobLogPage struct {
+	Logs       []LogEntry     `json:"logs"`
+	NextCursor string         `json:"nextCursor"` // Pass as cursor to read the entries after this page
+	HasMore    bool           `json:"hasMore"`    // More entries are already available
+	JobStatus  core.JobStatus `json:"jobStatus"`
+}
+
+// UsageRecorder meters the usage of completed jobs. Recording a job's usage more than
+// once must have no further effect. metering.Meter satisfies this interface.
+type UsageRecorder interface {
+	RecordJobUsage(ctx context.Context, job *core.Job) error
+}
+
+// StatusNotifier sends notifications about jobs that reached a final status. It should
+// only queue them, since it runs on the status transition. notification.Notifier
+// satisfies this interface.
+type StatusNotifier interface {
+	NotifyJobStatus(ctx context.Context, job *core.Job) error
+}
+
+// JobServiceOptions holds the optional collaborators of a JobService. Each one left unset
+// disables what it provides.
+type JobServiceOptions struct {
+	Events    events.Publisher // Receives job status, progress and result changes; defaults to events.Discard
+	Limiter   *Limiter         // Limits enforced on job creation and submission
+	Estimator *Estimator       // Predicts the cost of new jobs
+	Usage     UsageRecorder    // Meters the usage of completed jobs
+	Notifier  StatusNotifier   // Is told when jobs finish
+}
+
 // jobService implements the JobService interface.
 type jobService struct {
 	jobRepo    core.JobRepository
 	projectSvc project.ProjectService // Use ProjectService for auth checks
 	pipeline   PipelineClient         // Interface for the external pipeline
+	events     events.Publisher       // Receives job changes; events.Discard by default
+	limiter    *Limiter               // Rate and concurrency limits; nil enforces none
+	estimator  *Estimator             // Predicts job costs; nil makes no estimates
+	usage      UsageRecorder          // Meters completed jobs; nil meters nothing
+	notifier   StatusNotifier         // Notifies about finished jobs; nil notifies nobody
 	// logger      *log.Logger // Using global logger now
+
+	progressMu sync.Mutex
+	progress   map[string]int // Last progress published per running job
 }
 
 // NewJobService creates a new job service instance.
@@ -53,18 +158,91 @@ func NewJobService(
 	jobRepo core.JobRepository,
 	projectSvc project.ProjectService, // Inject ProjectService
 	pipeline PipelineClient,
+	opts JobServiceOptions,
 ) JobService {
 	// Removed logger injection, using global logger
+	if opts.Events == nil {
+		opts.Events = events.Discard
+	}
 	return &jobService{
 		jobRepo:    jobRepo,
 		projectSvc: projectSvc,
 		pipeline:   pipeline,
+		events:     opts.Events,
+		limiter:    opts.Limiter,
+		estimator:  opts.Estimator,
+		usage:      opts.Usage,
+		notifier:   opts.Notifier,
+		progress:   make(map[string]int),
+	}
+}
+
+// checkCreateLimits checks a job about to be created by userID against the record limit,
+// then counts it against the user's creation rate.
+func (s *jobService) checkCreateLimits(ctx context.Context, userID, jobConfig string) error {
+	if err := s.limiter.checkRecords(jobConfig); err != nil {
+		return err
+	}
+	if err := s.limiter.takeCreateToken(ctx, userID); err != nil {
+		logger.Logger.Warn("Job creation rate limit reached", zap.String("userID", userID), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// publishStatus announces a job's current status.
+func (s *jobService) publishStatus(ctx context.Context, job *core.Job) {
+	if isFinalJobStatus(job.Status) {
+		s.progressMu.Lock()
+		delete(s.progress, job.ID)
+		s.progressMu.Unlock()
+		s.notifyStatus(ctx, job)
+	}
+	s.events.Publish(ctx, events.JobStatusEvent(job))
+}
+
+// notifyStatus hands a finished job to the notifier. Failures only cost the notifications.
+func (s *jobService) notifyStatus(ctx context.Context, job *core.Job) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.NotifyJobStatus(ctx, job); err != nil {
+		logger.Logger.Error("Failed to queue job notifications", zap.String("jobID", job.ID), zap.String("status", string(job.Status)), zap.Error(err))
 	}
 }
 
+// publishProgress announces a running job's progress if the pipeline reports it and it
+// has changed since the last announcement. Failures only cost the announcement.
+func (s *jobService) publishProgress(ctx context.Context, job *core.Job) {
+	reporter, ok := s.pipeline.(ProgressReporter)
+	if !ok {
+		return
+	}
+	progress, err := reporter.Progress(ctx, job.PipelineJobID)
+	if err != nil {
+		logger.Logger.Debug("Failed to get job progress from pipeline", zap.String("jobID", job.ID), zap.Error(err))
+		return
+	}
+	s.progressMu.Lock()
+	last, seen := s.progress[job.ID]
+	s.progress[job.ID] = progress
+	s.progressMu.Unlock()
+	if seen && last == progress {
+		return
+	}
+	s.events.Publish(ctx, events.Event{
+		Type:      events.TypeJobProgress,
+		ProjectID: job.ProjectID,
+		JobID:     job.ID,
+		Status:    job.Status,
+		Progress:  &progress,
+	})
+}
+
 // authorizeJobAction checks if the user has the required role for the job's project.
 // It leverages the injected ProjectService, which handles the actual RBAC logic.
-func (s *jobService) authorizeJobAction(ctx context.Context, projectID, userID string, requiredRole core.Role) error {
+// On success it returns the project so callers can inspect its state.
+func (s *jobService) authorizeJobAction(ctx context.Context, projectID, userID string, requiredRole core.Role) (*core.Project, error) {
 	// Use ProjectService GetProjectByID - it implicitly performs the auth check based on its internal logic
 	// We just need to ensure the user has *at least* the requiredRole.
 	// ProjectService's GetProjectByID requires RoleViewer by default.
@@ -79,7 +257,7 @@ func (s *jobService) authorizeJobAction(ctx context.Context, projectID, userID s
 			zap.String("requiredRole", string(requiredRole)),
 			zap.Error(err),
 		)
-		return fmt.Errorf("project access check failed: %w", err) // Return the underlying error (NotFound or AccessDenied)
+		return nil, fmt.Errorf("project access check failed: %w", err) // Return the underlying error (NotFound or AccessDenied)
 	}
 
 	// Now check if the user's role meets the specific requirement for this job action
@@ -90,7 +268,7 @@ func (s *jobService) authorizeJobAction(ctx context.Context, projectID, userID s
 			zap.String("projectID", projectID),
 			zap.String("userID", userID),
 		)
-		return core.ErrForbidden // Or a more specific internal error
+		return nil, core.ErrForbidden // Or a more specific internal error
 	}
 
 	// Define role hierarchy (could be moved to core or a shared helper)
@@ -111,7 +289,7 @@ func (s *jobService) authorizeJobAction(ctx context.Context, projectID, userID s
 			zap.String("userRole", string(userRole)),
 			zap.String("requiredRole", string(requiredRole)),
 		)
-		return core.ErrForbidden
+		return nil, fmt.Errorf("%w: insufficient role %s, requires %s", core.ErrForbidden, userRole, requiredRole)
 	}
 
 	logger.Logger.Debug("Authorization successful for job action",
@@ -119,7 +297,7 @@ func (s *jobService) authorizeJobAction(ctx context.Context, projectID, userID s
 		zap.String("userID", userID),
 		zap.String("requiredRole", string(requiredRole)),
 	)
-	return nil // Authorized
+	return proj, nil // Authorized
 }
 
 // CreateJob validates and creates a new job record, requiring Member role.
@@ -130,9 +308,14 @@ func (s *jobService) CreateJob(ctx context.Context, projectID, userID string, re
 		zap.String("jobType", req.JobType),
 	)
 	// 1. Check Permissions (Requires Member role)
-	if err := s.authorizeJobAction(ctx, projectID, userID, core.RoleMember); err != nil {
+	proj, err := s.authorizeJobAction(ctx, projectID, userID, core.RoleMember)
+	if err != nil {
 		return nil, err // Error logged in helper
 	}
+	if proj.Status == core.ProjectStatusArchived {
+		logger.Logger.Warn("Cannot create job in archived project", zap.String("projectID", projectID))
+		return nil, fmt.Errorf("%w: cannot create jobs in project %s", core.ErrProjectArchived, projectID)
+	}
 
 	// 2. Validate Inputs
 	if req.JobType == "" {
@@ -141,20 +324,37 @@ func (s *jobService) CreateJob(ctx context.Context, projectID, userID string, re
 	if req.JobConfig == "" { // TODO: Add richer config validation
 		return nil, fmt.Errorf("job configuration cannot be empty")
 	}
+	if err := validateRetryPolicy(req.RetryPolicy); err != nil {
+		return nil, err
+	}
+	jobConfig, err := s.resolveConfigSchema(ctx, projectID, userID, req.JobConfig)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCreateLimits(ctx, userID, jobConfig); err != nil {
+		return nil, err
+	}
 
 	// 3. Create Job Struct
 	now := time.Now().UTC()
 	newJob := &core.Job{
-		ID:            uuid.NewString(), // Generate unique ID
-		ProjectID:     projectID,
-		UserID:        userID,                // User who created the job
-		Status:        core.JobStatusPending, // Initial status before submission
-		JobType:       req.JobType,
-		JobConfig:     req.JobConfig,
-		CreatedAt:     now,
-		UpdatedAt:     now,
-		ResultURI:     "", // No result initially
-		PipelineJobID: "", // No pipeline ID initially
+		ID:              uuid.NewString(), // Generate unique ID
+		ProjectID:       projectID,
+		UserID:          userID,                // User who created the job
+		Status:          core.JobStatusPending, // Initial status before submission
+		JobType:         req.JobType,
+		JobConfig:       jobConfig,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		ResultURI:       "", // No result initially
+		PipelineJobID:   "", // No pipeline ID initially
+		Attempt:         1,
+		RetryPolicy:     req.RetryPolicy,
+		TemplateID:      req.TemplateID,
+		TemplateVersion: req.TemplateVersion,
+		ScheduleID:      req.ScheduleID,
+		WorkflowID:      req.WorkflowID,
+		Estimate:        s.estimator.Estimate(req.JobType, jobConfig),
 	}
 
 	// 4. Persist to Repository
@@ -167,9 +367,54 @@ func (s *jobService) CreateJob(ctx context.Context, projectID, userID string, re
 		zap.String("jobID", newJob.ID),
 		zap.String("projectID", projectID),
 	)
+	s.publishStatus(ctx, newJob)
 	return newJob, nil
 }
 
+// resolveConfigSchema pre-populates a job config's "schema" section from the dataset named
+// by its schemaFromDataset key. Configs without the key, with an explicit schema, or that
+// are not JSON objects are returned unchanged.
+func (s *jobService) resolveConfigSchema(ctx context.Context, projectID, userID, jobConfig string) (string, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(jobConfig), &fields); err != nil {
+		return jobConfig, nil
+	}
+	rawSource, ok := fields[configSchemaSourceKey]
+	if !ok {
+		return jobConfig, nil
+	}
+	if _, hasSchema := fields["schema"]; hasSchema {
+		return jobConfig, nil // An explicit schema wins over the sample
+	}
+	var datasetID string
+	if err := json.Unmarshal(rawSource, &datasetID); err != nil || datasetID == "" {
+		return "", fmt.Errorf("%w: %s must be a dataset name", ErrInvalidJobConfig, configSchemaSourceKey)
+	}
+
+	schema, err := s.projectSvc.GetDatasetSchema(ctx, projectID, datasetID, userID, 0)
+	if err != nil {
+		if errors.Is(err, project.ErrDatasetNotFound) || errors.Is(err, project.ErrUnsupportedDatasetFormat) {
+			return "", fmt.Errorf("%w: %s: %v", ErrInvalidJobConfig, configSchemaSourceKey, err)
+		}
+		return "", fmt.Errorf("failed to infer schema from dataset %s: %w", datasetID, err)
+	}
+	encodedSchema, err := json.Marshal(schema.JobConfigSchema)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode inferred schema: %w", err)
+	}
+	fields["schema"] = encodedSchema
+	resolved, err := json.Marshal(fields)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode job config: %w", err)
+	}
+	logger.Logger.Info("Populated job config schema from dataset",
+		zap.String("projectID", projectID),
+		zap.String("datasetID", datasetID),
+		zap.Int("fields", len(schema.JobConfigSchema)),
+	)
+	return string(resolved), nil
+}
+
 // SubmitJob submits a job to the pipeline, requiring Member role.
 func (s *jobService) SubmitJob(ctx context.Context, jobID, userID string) (*core.Job, error) {
 	logger.Logger.Info("Attempting to submit job", zap.String("jobID", jobID), zap.String("userID", userID))
@@ -180,12 +425,22 @@ func (s *jobService) SubmitJob(ctx context.Context, jobID, userID string) (*core
 	}
 
 	// 2. Check Permissions (Requires Member role for the job's project)
-	if err := s.authorizeJobAction(ctx, job.ProjectID, userID, core.RoleMember); err != nil {
+	proj, err := s.authorizeJobAction(ctx, job.ProjectID, userID, core.RoleMember)
+	if err != nil {
 		return nil, err // Error logged in helper
 	}
+	if proj.Status == core.ProjectStatusArchived {
+		logger.Logger.Warn("Cannot submit job in archived project", zap.String("jobID", jobID), zap.String("projectID", job.ProjectID))
+		return nil, fmt.Errorf("%w: cannot submit jobs in project %s", core.ErrProjectArchived, job.ProjectID)
+	}
+	// Output size is unknown until the pipeline finishes, so require at least some headroom.
+	if err := project.CheckStorageQuota(proj, 1); err != nil {
+		logger.Logger.Warn("Cannot submit job, project storage quota exhausted", zap.String("jobID", jobID), zap.String("projectID", job.ProjectID))
+		return nil, err
+	}
 
-	// 3. Check if Submittable (must be Pending)
-	if job.Status != core.JobStatusPending {
+	// 3. Check if Submittable (must be Pending, or Queued by an earlier submission)
+	if job.Status != core.JobStatusPending && job.Status != core.JobStatusQueued {
 		logger.Logger.Warn("Cannot submit job, status is not pending",
 			zap.String("jobID", jobID),
 			zap.String("status", string(job.Status)),
@@ -193,8 +448,33 @@ func (s *jobService) SubmitJob(ctx context.Context, jobID, userID string) (*core
 		return nil, fmt.Errorf("job %s cannot be submitted, status is %s", jobID, job.Status)
 	}
 
-	// 4. Submit to Pipeline Client
-	pipelineJobID, err := s.pipeline.Submit(ctx, job.JobConfig, job.JobType, job.ProjectID)
+	// 4. Hold back jobs reading datasets whose PII findings await review, if the project requires it
+	if proj.Settings.RequirePIIAcknowledgement {
+		if err := s.checkInputPIIReviewed(ctx, job, proj, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Check the concurrency limits, queueing the job if it may wait for capacity
+	if err := s.limiter.checkCapacity(ctx, proj); err != nil {
+		var limitErr *LimitError
+		if errors.As(err, &limitErr) && job.Status == core.JobStatusPending && s.limiter.queueOverLimit() {
+			return s.queueJob(ctx, job, limitErr)
+		}
+		logger.Logger.Warn("Cannot submit job, concurrency limit reached", zap.String("jobID", jobID), zap.Error(err))
+		return nil, err
+	}
+
+	// 6. Submit to Pipeline Client, keyed by our job ID so retried submissions are deduplicated
+	pipelineJobID, err := s.pipeline.Submit(WithIdempotencyKey(ctx, job.ID), job.JobConfig, job.JobType, job.ProjectID)
+	if errors.Is(err, ErrPipelineUnavailable) {
+		// Transient outage: the job stays pending so it can be submitted again.
+		logger.Logger.Warn("Pipeline unavailable, job left pending",
+			zap.String("jobID", jobID),
+			zap.Error(err),
+		)
+		return nil, fmt.Errorf("pipeline submission failed: %w", err)
+	}
 	if err != nil {
 		// Pipeline client should log specifics. Update job status to Failed.
 		errMsg := fmt.Sprintf("Pipeline submission failed: %v", err)
@@ -202,7 +482,10 @@ func (s *jobService) SubmitJob(ctx context.Context, jobID, userID string) (*core
 			zap.String("jobID", jobID),
 			zap.Error(err),
 		)
-		updateErr := s.jobRepo.UpdateJobStatus(ctx, jobID, core.JobStatusFailed, "", nil, nil, errMsg)
+		updateErr := s.jobRepo.TransitionJobStatus(ctx, jobID, job.Status, core.JobStatusFailed, "", nil, nil, errMsg)
+		if errors.Is(updateErr, core.ErrConflict) {
+			return s.lostTransition(ctx, job, updateErr)
+		}
 		if updateErr != nil {
 			logger.Logger.Error("CRITICAL: Failed to update job status to Failed after pipeline error",
 				zap.String("jobID", jobID),
@@ -214,6 +497,7 @@ func (s *jobService) SubmitJob(ctx context.Context, jobID, userID string) (*core
 		}
 		job.Status = core.JobStatusFailed // Update local struct for return
 		job.Error = errMsg
+		s.publishStatus(ctx, job)
 		return job, fmt.Errorf("pipeline submission failed: %w", err) // Return original pipeline error
 	}
 	logger.Logger.Info("Job submitted to pipeline",
@@ -221,10 +505,13 @@ func (s *jobService) SubmitJob(ctx context.Context, jobID, userID string) (*core
 		zap.String("pipelineJobID", pipelineJobID),
 	)
 
-	// 5. Update Job Status & Pipeline ID in Repository
+	// 7. Update Job Status & Pipeline ID in Repository
 	now := time.Now().UTC()
 	statusToSet := core.JobStatusRunning // Assume Running
-	err = s.jobRepo.UpdateJobStatus(ctx, jobID, statusToSet, pipelineJobID, &now, nil, "")
+	err = s.jobRepo.TransitionJobStatus(ctx, jobID, job.Status, statusToSet, pipelineJobID, &now, nil, "")
+	if errors.Is(err, core.ErrConflict) {
+		return s.lostTransition(ctx, job, err)
+	}
 	if err != nil {
 		logger.Logger.Error("CRITICAL: Pipeline accepted job but failed to update local status",
 			zap.String("jobID", jobID),
@@ -245,10 +532,46 @@ func (s *jobService) SubmitJob(ctx context.Context, jobID, userID string) (*core
 		zap.String("jobID", jobID),
 		zap.String("newStatus", string(statusToSet)),
 	)
+	s.publishStatus(ctx, job)
+
+	return job, nil
+}
 
+// queueJob holds back a pending job that reached a concurrency limit until the
+// QueueDispatcher submits it.
+func (s *jobService) queueJob(ctx context.Context, job *core.Job, limitErr *LimitError) (*core.Job, error) {
+	err := s.jobRepo.TransitionJobStatus(ctx, job.ID, job.Status, core.JobStatusQueued, "", nil, nil, "")
+	if errors.Is(err, core.ErrConflict) {
+		return s.lostTransition(ctx, job, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue job %s: %w", job.ID, err)
+	}
+	job.Status = core.JobStatusQueued
+	job.UpdatedAt = time.Now().UTC()
+	logger.Logger.Info("Job queued until capacity frees up",
+		zap.String("jobID", job.ID),
+		zap.String("limit", limitErr.Limit),
+	)
+	s.publishStatus(ctx, job)
 	return job, nil
 }
 
+// lostTransition returns the stored state of a job whose status another writer changed
+// first. That writer publishes the change, so nothing is published here.
+func (s *jobService) lostTransition(ctx context.Context, job *core.Job, conflict error) (*core.Job, error) {
+	logger.Logger.Info("Job status changed concurrently, skipping update",
+		zap.String("jobID", job.ID),
+		zap.String("expectedStatus", string(job.Status)),
+		zap.Error(conflict),
+	)
+	current, err := s.jobRepo.GetJobByID(ctx, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload job %s after concurrent update: %w", job.ID, err)
+	}
+	return current, nil
+}
+
 // GetJobByID retrieves a job, requiring Viewer role.
 func (s *jobService) GetJobByID(ctx context.Context, jobID, userID string) (*core.Job, error) {
 	logger.Logger.Debug("Attempting to get job", zap.String("jobID", jobID), zap.String("userID", userID))
@@ -258,7 +581,7 @@ func (s *jobService) GetJobByID(ctx context.Context, jobID, userID string) (*cor
 	}
 
 	// Check permissions (Requires Viewer role for the job's project)
-	if err := s.authorizeJobAction(ctx, job.ProjectID, userID, core.RoleViewer); err != nil {
+	if _, err := s.authorizeJobAction(ctx, job.ProjectID, userID, core.RoleViewer); err != nil {
 		return nil, err // Error logged by helper
 	}
 
@@ -270,7 +593,7 @@ func (s *jobService) GetJobByID(ctx context.Context, jobID, userID string) (*cor
 func (s *jobService) ListJobsByProject(ctx context.Context, projectID, userID string, limit, offset int) ([]*core.Job, int, error) {
 	logger.Logger.Debug("Attempting to list jobs", zap.String("projectID", projectID), zap.String("userID", userID))
 	// Check permissions (Requires Viewer role for the project)
-	if err := s.authorizeJobAction(ctx, projectID, userID, core.RoleViewer); err != nil {
+	if _, err := s.authorizeJobAction(ctx, projectID, userID, core.RoleViewer); err != nil {
 		return nil, 0, err // Error logged by helper
 	}
 
@@ -297,12 +620,12 @@ func (s *jobService) CancelJob(ctx context.Context, jobID, userID string) (*core
 	}
 
 	// 2. Check Permissions (Requires Member role for the job's project)
-	if err := s.authorizeJobAction(ctx, job.ProjectID, userID, core.RoleMember); err != nil {
+	if _, err := s.authorizeJobAction(ctx, job.ProjectID, userID, core.RoleMember); err != nil {
 		return nil, err // Error logged by helper
 	}
 
-	// 3. Check if Cancellable (Pending, Running)
-	if job.Status != core.JobStatusPending && job.Status != core.JobStatusRunning {
+	// 3. Check if Cancellable (Pending, Queued, Running)
+	if job.Status != core.JobStatusPending && job.Status != core.JobStatusQueued && job.Status != core.JobStatusRunning {
 		logger.Logger.Warn("Cannot cancel job, status is not cancellable",
 			zap.String("jobID", jobID),
 			zap.String("status", string(job.Status)),
@@ -337,9 +660,12 @@ func (s *jobService) CancelJob(ctx context.Context, jobID, userID string) (*core
 		}
 	}
 
-	// 6. Update Job Status Locally
+	// 6. Update Job Status Locally, unless the job moved on (e.g. completed) meanwhile
 	now := time.Now().UTC()
-	err = s.jobRepo.UpdateJobStatus(ctx, jobID, statusToSet, pipelineJobID, job.StartedAt, &now, cancelMsg)
+	err = s.jobRepo.TransitionJobStatus(ctx, jobID, job.Status, statusToSet, pipelineJobID, job.StartedAt, &now, cancelMsg)
+	if errors.Is(err, core.ErrConflict) {
+		return s.lostTransition(ctx, job, err)
+	}
 	if err != nil {
 		logger.Logger.Error("CRITICAL: Failed to update local status after cancellation request",
 			zap.String("jobID", jobID),
@@ -355,10 +681,171 @@ func (s *jobService) CancelJob(ctx context.Context, jobID, userID string) (*core
 	job.Error = cancelMsg
 	job.UpdatedAt = now
 	logger.Logger.Info("Successfully marked job as cancelled locally.", zap.String("jobID", jobID))
+	s.publishStatus(ctx, job)
 
 	return job, nil
 }
 
+// RetryJob creates a pending retry of a failed or cancelled job, requiring Member role.
+func (s *jobService) RetryJob(ctx context.Context, jobID, userID string) (*core.Job, error) {
+	logger.Logger.Info("Attempting to retry job", zap.String("jobID", jobID), zap.String("userID", userID))
+	// 1. Get the source job and check it may be copied
+	source, err := s.getCopyableJob(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if source.Status != core.JobStatusFailed && source.Status != core.JobStatusCancelled {
+		return nil, fmt.Errorf("%w: job %s is %s", ErrJobNotRetryable, jobID, source.Status)
+	}
+
+	// 2. Create the retry with the same config and policy
+	retry := newJobFrom(source, userID)
+	retry.RetryOf = source.ID
+	retry.Attempt = max(source.Attempt, 1) + 1
+	if err := s.checkCreateLimits(ctx, userID, retry.JobConfig); err != nil {
+		return nil, err
+	}
+	if err := s.storeJob(ctx, retry); err != nil {
+		return nil, err
+	}
+	return retry, nil
+}
+
+// CloneJob creates a pending copy of a job with overrides applied, requiring Member role.
+func (s *jobService) CloneJob(ctx context.Context, jobID, userID string, req CloneJobRequest) (*core.Job, error) {
+	logger.Logger.Info("Attempting to clone job", zap.String("jobID", jobID), zap.String("userID", userID))
+	// 1. Get the source job and check it may be copied
+	source, err := s.getCopyableJob(ctx, jobID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Apply the overrides
+	clone := newJobFrom(source, userID)
+	clone.ClonedFrom = source.ID
+	if req.JobType != "" || req.JobConfig != "" || len(req.ConfigOverrides) > 0 {
+		clone.TemplateID, clone.TemplateVersion = "", 0 // No longer what the template produces
+	}
+	if req.JobType != "" {
+		clone.JobType = req.JobType
+	}
+	switch {
+	case req.JobConfig != "" && len(req.ConfigOverrides) > 0:
+		return nil, fmt.Errorf("%w: pass either jobConfig or configOverrides, not both", ErrInvalidJobConfig)
+	case req.JobConfig != "":
+		clone.JobConfig = req.JobConfig
+	case len(req.ConfigOverrides) > 0:
+		if clone.JobConfig, err = mergeJobConfig(source.JobConfig, req.ConfigOverrides); err != nil {
+			return nil, err
+		}
+	}
+	if req.RetryPolicy != nil {
+		if err := validateRetryPolicy(req.RetryPolicy); err != nil {
+			return nil, err
+		}
+		clone.RetryPolicy = req.RetryPolicy
+	}
+	if clone.JobConfig, err = s.resolveConfigSchema(ctx, clone.ProjectID, userID, clone.JobConfig); err != nil {
+		return nil, err
+	}
+
+	// 3. Persist the clone
+	if err := s.checkCreateLimits(ctx, userID, clone.JobConfig); err != nil {
+		return nil, err
+	}
+	if err := s.storeJob(ctx, clone); err != nil {
+		return nil, err
+	}
+	return clone, nil
+}
+
+// getCopyableJob returns a job the user may copy into a new one: Member role is required
+// and the project must not be archived.
+func (s *jobService) getCopyableJob(ctx context.Context, jobID, userID string) (*core.Job, error) {
+	source, err := s.jobRepo.GetJobByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s: %w", jobID, err)
+	}
+	proj, err := s.authorizeJobAction(ctx, source.ProjectID, userID, core.RoleMember)
+	if err != nil {
+		return nil, err // Error logged in helper
+	}
+	if proj.Status == core.ProjectStatusArchived {
+		logger.Logger.Warn("Cannot copy job in archived project", zap.String("jobID", jobID), zap.String("projectID", source.ProjectID))
+		return nil, fmt.Errorf("%w: cannot create jobs in project %s", core.ErrProjectArchived, source.ProjectID)
+	}
+	return source, nil
+}
+
+// newJobFrom returns a new pending job with the type, config and retry policy of source.
+func newJobFrom(source *core.Job, userID string) *core.Job {
+	now := time.Now().UTC()
+	return &core.Job{
+		ID:              uuid.NewString(),
+		ProjectID:       source.ProjectID,
+		UserID:          userID,
+		Status:          core.JobStatusPending,
+		JobType:         source.JobType,
+		JobConfig:       source.JobConfig,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Attempt:         1,
+		RetryPolicy:     source.RetryPolicy,
+		TemplateID:      source.TemplateID,
+		TemplateVersion: source.TemplateVersion,
+	}
+}
+
+// storeJob estimates a new job, persists it and announces it.
+func (s *jobService) storeJob(ctx context.Context, job *core.Job) error {
+	job.Estimate = s.estimator.Estimate(job.JobType, job.JobConfig)
+	if err := s.jobRepo.CreateJob(ctx, job); err != nil {
+		return fmt.Errorf("failed to store new job: %w", err)
+	}
+	logger.Logger.Info("Successfully created job",
+		zap.String("jobID", job.ID),
+		zap.String("projectID", job.ProjectID),
+		zap.String("retryOf", job.RetryOf),
+		zap.String("clonedFrom", job.ClonedFrom),
+	)
+	s.publishStatus(ctx, job)
+	return nil
+}
+
+// scheduleRetry creates the automatic retry of a failed job if its retry policy covers the
+// failure. The retry is submitted by the StatusWatcher once its RetryAt has passed. Its ID
+// is derived from the failed job's, so observing the failure again schedules nothing new.
+func (s *jobService) scheduleRetry(ctx context.Context, failed *core.Job) {
+	delay, ok := retryDelay(failed, failed.Error)
+	if !ok {
+		return
+	}
+	retry := newJobFrom(failed, failed.UserID)
+	retry.ID = AutomaticRetryID(failed.ID)
+	retry.RetryOf = failed.ID
+	retry.WorkflowID = failed.WorkflowID // The workflow step follows its retries
+	retry.Attempt = max(failed.Attempt, 1) + 1
+	retryAt := retry.CreatedAt.Add(delay)
+	retry.RetryAt = &retryAt
+	retry.Estimate = s.estimator.Estimate(retry.JobType, retry.JobConfig)
+
+	err := s.jobRepo.CreateJob(ctx, retry)
+	if errors.Is(err, core.ErrConflict) {
+		return // Already scheduled
+	}
+	if err != nil {
+		logger.Logger.Error("Failed to schedule automatic job retry", zap.String("jobID", failed.ID), zap.Error(err))
+		return
+	}
+	logger.Logger.Info("Scheduled automatic job retry",
+		zap.String("jobID", failed.ID),
+		zap.String("retryJobID", retry.ID),
+		zap.Int("attempt", retry.Attempt),
+		zap.Time("retryAt", retryAt),
+	)
+	s.publishStatus(ctx, retry)
+}
+
 // SyncJobStatus checks pipeline status, requiring Viewer role.
 func (s *jobService) SyncJobStatus(ctx context.Context, jobID, userID string) (*core.Job, error) {
 	logger.Logger.Debug("Attempting to sync status for job", zap.String("jobID", jobID), zap.String("userID", userID))
@@ -369,11 +856,63 @@ func (s *jobService) SyncJobStatus(ctx context.Context, jobID, userID string) (*
 	}
 
 	// 2. Check Permissions (Requires Viewer role for the job's project)
-	if err := s.authorizeJobAction(ctx, job.ProjectID, userID, core.RoleViewer); err != nil {
+	if _, err := s.authorizeJobAction(ctx, job.ProjectID, userID, core.RoleViewer); err != nil {
 		return nil, err // Error logged by helper
 	}
 
-	// 3. Check if job is in a final state already
+	return s.syncJob(ctx, job)
+}
+
+// RefreshJobStatus checks pipeline status without authorization.
+func (s *jobService) RefreshJobStatus(ctx context.Context, jobID string) (*core.Job, error) {
+	job, err := s.jobRepo.GetJobByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s for status refresh: %w", jobID, err)
+	}
+	return s.syncJob(ctx, job)
+}
+
+// FailQueuedJob marks a queued job as failed without authorization.
+func (s *jobService) FailQueuedJob(ctx context.Context, jobID, reason string) (*core.Job, error) {
+	return s.failUnsubmitted(ctx, jobID, core.JobStatusQueued, reason)
+}
+
+// FailDueRetry marks a pending automatic retry as failed without authorization.
+func (s *jobService) FailDueRetry(ctx context.Context, jobID, reason string) (*core.Job, error) {
+	return s.failUnsubmitted(ctx, jobID, core.JobStatusPending, reason)
+}
+
+// failUnsubmitted moves a job that was never submitted from status to failed.
+func (s *jobService) failUnsubmitted(ctx context.Context, jobID string, status core.JobStatus, reason string) (*core.Job, error) {
+	job, err := s.jobRepo.GetJobByID(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s to fail it: %w", jobID, err)
+	}
+	if job.Status != status {
+		return nil, fmt.Errorf("job %s cannot be failed, status is %s, not %s", jobID, job.Status, status)
+	}
+
+	now := time.Now().UTC()
+	err = s.jobRepo.TransitionJobStatus(ctx, jobID, status, core.JobStatusFailed, "", nil, &now, reason)
+	if errors.Is(err, core.ErrConflict) {
+		return s.lostTransition(ctx, job, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark %s job %s as failed: %w", status, jobID, err)
+	}
+	job.Status = core.JobStatusFailed
+	job.CompletedAt = &now
+	job.Error = reason
+	job.UpdatedAt = now
+	logger.Logger.Info("Unsubmitted job failed", zap.String("jobID", jobID), zap.String("fromStatus", string(status)), zap.String("reason", reason))
+	s.publishStatus(ctx, job)
+	return job, nil
+}
+
+// syncJob updates a job from the pipeline's view of it, publishing any change.
+func (s *jobService) syncJob(ctx context.Context, job *core.Job) (*core.Job, error) {
+	jobID := job.ID
+	// 1. Check if job is in a final state already
 	if job.Status == core.JobStatusCompleted || job.Status == core.JobStatusFailed || job.Status == core.JobStatusCancelled {
 		logger.Logger.Debug("Skipping status sync, job already in final state",
 			zap.String("jobID", jobID),
@@ -382,7 +921,7 @@ func (s *jobService) SyncJobStatus(ctx context.Context, jobID, userID string) (*
 		return job, nil // No need to sync
 	}
 
-	// 4. Get Pipeline ID
+	// 2. Get Pipeline ID
 	pipelineJobID := job.PipelineJobID
 	if pipelineJobID == "" {
 		// This can happen if the job is still Pending and hasn't been submitted
@@ -390,7 +929,7 @@ func (s *jobService) SyncJobStatus(ctx context.Context, jobID, userID string) (*
 		return job, nil // No pipeline ID to check
 	}
 
-	// 5. Check Status with Pipeline Client
+	// 3. Check Status with Pipeline Client
 	newStatus, pipelineError, err := s.pipeline.CheckStatus(ctx, pipelineJobID)
 	if err != nil {
 		logger.Logger.Warn("Error checking pipeline status",
@@ -408,7 +947,7 @@ func (s *jobService) SyncJobStatus(ctx context.Context, jobID, userID string) (*
 		zap.String("pipelineStatus", string(newStatus)),
 	)
 
-	// 6. Update Local Status if Changed
+	// 4. Update Local Status if Changed
 	if newStatus != job.Status {
 		logger.Logger.Info("Status change detected, updating local record",
 			zap.String("jobID", jobID),
@@ -420,7 +959,11 @@ func (s *jobService) SyncJobStatus(ctx context.Context, jobID, userID string) (*
 		if newStatus == core.JobStatusCompleted || newStatus == core.JobStatusFailed || newStatus == core.JobStatusCancelled {
 			completedAt = &now
 		}
-		err = s.jobRepo.UpdateJobStatus(ctx, jobID, newStatus, pipelineJobID, job.StartedAt, completedAt, pipelineError)
+		err = s.jobRepo.TransitionJobStatus(ctx, jobID, job.Status, newStatus, pipelineJobID, job.StartedAt, completedAt, pipelineError)
+		if errors.Is(err, core.ErrConflict) {
+			// Another replica or request recorded the change and published it
+			return s.lostTransition(ctx, job, err)
+		}
 		if err != nil {
 			logger.Logger.Error("CRITICAL: Failed to update local status after pipeline sync",
 				zap.String("jobID", jobID),
@@ -433,6 +976,34 @@ func (s *jobService) SyncJobStatus(ctx context.Context, jobID, userID string) (*
 		job.CompletedAt = completedAt
 		job.Error = pipelineError
 		job.UpdatedAt = now
+		s.publishStatus(ctx, job)
+
+		if newStatus == core.JobStatusFailed {
+			s.scheduleRetry(ctx, job)
+		}
+
+		// Completed jobs write outputs to the project bucket; record where and keep usage current
+		if newStatus == core.JobStatusCompleted {
+			s.recordJobResult(ctx, job)
+			s.recordJobActuals(ctx, job)
+			s.recordJobUsage(ctx, job)
+			if job.ResultURI != "" {
+				s.events.Publish(ctx, events.Event{
+					Type:      events.TypeJobResult,
+					ProjectID: job.ProjectID,
+					JobID:     job.ID,
+					Status:    job.Status,
+					ResultURI: job.ResultURI,
+				})
+			}
+			if _, err := s.projectSvc.RefreshStorageUsage(ctx, job.ProjectID); err != nil {
+				logger.Logger.Warn("Failed to refresh project storage usage after job completion",
+					zap.String("jobID", jobID),
+					zap.String("projectID", job.ProjectID),
+					zap.Error(err),
+				)
+			}
+		}
 	} else {
 		logger.Logger.Debug("No status change detected.", zap.String("jobID", jobID))
 		// Optionally update UpdatedAt even if status didn't change, to show sync happened?
@@ -440,12 +1011,252 @@ func (s *jobService) SyncJobStatus(ctx context.Context, jobID, userID string) (*
 		// err = s.jobRepo.UpdateJob(ctx, job) // Need an UpdateJob method if doing this
 	}
 
-	// TODO: Potentially fetch result URI if status is Completed
-	// if newStatus == core.JobStatusCompleted { ... fetch and update result URI ... }
+	if job.Status == core.JobStatusRunning {
+		s.publishProgress(ctx, job)
+	}
 
 	return job, nil
 }
 
+// recordJobResult stores the output location the pipeline reports for a completed job.
+// Failures are logged: the job's completion is already recorded.
+func (s *jobService) recordJobResult(ctx context.Context, job *core.Job) {
+	resultURI, err := s.pipeline.ResultURI(ctx, job.PipelineJobID)
+	if err != nil {
+		logger.Logger.Warn("Failed to get result URI from pipeline",
+			zap.String("jobID", job.ID),
+			zap.String("pipelineJobID", job.PipelineJobID),
+			zap.Error(err),
+		)
+		return
+	}
+	if resultURI == "" {
+		return // The pipeline does not report output locations
+	}
+	if err := s.jobRepo.UpdateJobResult(ctx, job.ID, resultURI); err != nil {
+		logger.Logger.Error("Failed to store job result URI",
+			zap.String("jobID", job.ID),
+			zap.String("resultURI", resultURI),
+			zap.Error(err),
+		)
+		return
+	}
+	job.ResultURI = resultURI
+}
+
+// recordJobActuals stores what a completed job took next to its estimate. Failures are
+// logged: the job's completion is already recorded.
+func (s *jobService) recordJobActuals(ctx context.Context, job *core.Job) {
+	var outputBytes int64
+	if reporter, ok := s.pipeline.(OutputSizeReporter); ok {
+		size, err := reporter.OutputBytes(ctx, job.PipelineJobID)
+		if err != nil {
+			logger.Logger.Warn("Failed to get job output size from pipeline", zap.String("jobID", job.ID), zap.Error(err))
+		}
+		outputBytes = size
+	}
+	actuals := s.estimator.Actuals(job, outputBytes)
+	if reporter, ok := s.pipeline.(RecordCountReporter); ok {
+		records, err := reporter.GeneratedRecords(ctx, job.PipelineJobID)
+		if err != nil {
+			logger.Logger.Warn("Failed to get job record count from pipeline", zap.String("jobID", job.ID), zap.Error(err))
+		}
+		actuals.Records = records
+	}
+	if err := s.jobRepo.UpdateJobActuals(ctx, job.ID, actuals); err != nil {
+		logger.Logger.Error("Failed to store job actuals", zap.String("jobID", job.ID), zap.Error(err))
+		return
+	}
+	job.Actuals = actuals
+	if job.Estimate != nil {
+		logger.Logger.Info("Job completed against its estimate",
+			zap.String("jobID", job.ID),
+			zap.Float64("estimatedSeconds", job.Estimate.DurationSeconds),
+			zap.Float64("actualSeconds", actuals.DurationSeconds),
+			zap.Int64("estimatedBytes", job.Estimate.OutputBytes),
+			zap.Int64("actualBytes", actuals.OutputBytes),
+		)
+	}
+}
+
+// recordJobUsage meters a completed job. Failures are logged: the job stays unmetered and
+// the usage recorder's reconciliation records it later.
+func (s *jobService) recordJobUsage(ctx context.Context, job *core.Job) {
+	if s.usage == nil {
+		return
+	}
+	if err := s.usage.RecordJobUsage(ctx, job); err != nil {
+		logger.Logger.Warn("Failed to record job usage", zap.String("jobID", job.ID), zap.Error(err))
+	}
+}
+
+// EstimateJob predicts the cost of a job config, requiring Viewer role.
+func (s *jobService) EstimateJob(ctx context.Context, projectID, userID string, req EstimateJobRequest) (*core.JobEstimate, error) {
+	// 1. Check Permissions (Requires Viewer role)
+	if _, err := s.authorizeJobAction(ctx, projectID, userID, core.RoleViewer); err != nil {
+		return nil, err // Error logged in helper
+	}
+	if s.estimator == nil {
+		return nil, ErrEstimatesUnavailable
+	}
+
+	// 2. Validate Inputs, resolving the schema the job would be created with
+	if req.JobType == "" {
+		return nil, fmt.Errorf("%w: job type cannot be empty", ErrInvalidJobConfig)
+	}
+	if req.JobConfig == "" {
+		return nil, fmt.Errorf("%w: job configuration cannot be empty", ErrInvalidJobConfig)
+	}
+	jobConfig, err := s.resolveConfigSchema(ctx, projectID, userID, req.JobConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Estimate
+	return s.estimator.Estimate(req.JobType, jobConfig), nil
+}
+
+// GetJobLogs returns a page of a job's pipeline log, requiring Viewer role.
+func (s *jobService) GetJobLogs(ctx context.Context, jobID, userID string, since int64, limit int) (*JobLogPage, error) {
+	// 1. Get the job with a fresh status (checks Viewer role). Logs are read afterwards, so
+	// once the status is final the page holds every remaining entry.
+	job, err := s.SyncJobStatus(ctx, jobID, userID)
+	if job == nil {
+		return nil, err
+	}
+	if err != nil {
+		logger.Logger.Warn("Serving job logs with a stale status", zap.String("jobID", jobID), zap.Error(err))
+	}
+	page := &JobLogPage{Logs: []LogEntry{}, NextCursor: strconv.FormatInt(since, 10), JobStatus: job.Status}
+	if job.PipelineJobID == "" {
+		return page, nil // Not submitted yet, so nothing has been logged
+	}
+
+	// 2. Read one entry beyond the limit to learn whether more are waiting
+	logs, err := s.pipeline.GetLogs(ctx, job.PipelineJobID, since, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pipeline logs for job %s: %w", jobID, err)
+	}
+	if len(logs) > limit {
+		logs, page.HasMore = logs[:limit], true
+	}
+	if len(logs) > 0 {
+		page.Logs = logs
+		page.NextCursor = strconv.FormatInt(logs[len(logs)-1].Sequence, 10)
+	}
+	return page, nil
+}
+
+// GetQualityReport compares a completed job's result with its input dataset, requiring Viewer role.
+func (s *jobService) GetQualityReport(ctx context.Context, jobID, userID string, refresh bool) (*project.FidelityReport, error) {
+	// 1. Get Job
+	job, err := s.jobRepo.GetJobByID(ctx, jobID)
+	if err != nil {
+		return nil, err // Error logged by repo
+	}
+
+	// 2. Check Permissions (Requires Viewer role for the job's project)
+	proj, err := s.authorizeJobAction(ctx, job.ProjectID, userID, core.RoleViewer)
+	if err != nil {
+		return nil, err // Error logged by helper
+	}
+
+	// 3. Locate the result and the dataset it imitates
+	if job.Status != core.JobStatusCompleted {
+		return nil, fmt.Errorf("%w: job %s is %s", ErrJobNotCompleted, jobID, job.Status)
+	}
+	if job.ResultExpiredAt != nil {
+		return nil, fmt.Errorf("%w: removed by data retention on %s", ErrJobResultUnavailable, job.ResultExpiredAt.Format(time.RFC3339))
+	}
+	resultObject, ok := bucketObject(job.ResultURI, proj.Storage.BucketName)
+	if !ok {
+		return nil, fmt.Errorf("%w: result %q is not in the project bucket", ErrJobResultUnavailable, job.ResultURI)
+	}
+	inputDataset, ok := jobInputDataset(job.JobConfig, proj.Storage.BucketName)
+	if !ok {
+		return nil, fmt.Errorf("%w: set %s or %s in the job config", ErrNoInputDataset, configInputDatasetKey, configSchemaSourceKey)
+	}
+
+	// 4. Compare, storing the report alongside the outputs
+	report, err := s.projectSvc.CompareDatasets(ctx, job.ProjectID, userID, project.DatasetComparisonRequest{
+		RealDatasetID:      inputDataset,
+		SyntheticDatasetID: resultObject,
+		ReportName:         path.Join(path.Dir(resultObject), qualityReportName),
+		Refresh:            refresh,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build quality report for job %s: %w", jobID, err)
+	}
+	return report, nil
+}
+
+// checkInputPIIReviewed fails with ErrPIIReviewRequired when the job's input dataset has
+// not finished its PII scan or has unacknowledged high-severity findings.
+func (s *jobService) checkInputPIIReviewed(ctx context.Context, job *core.Job, proj *core.Project, userID string) error {
+	inputDataset, ok := jobInputDataset(job.JobConfig, proj.Storage.BucketName)
+	if !ok {
+		return nil // Nothing read from the project's datasets
+	}
+	scan, err := s.projectSvc.GetDatasetPIIScan(ctx, job.ProjectID, inputDataset, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check PII scan of input dataset %s: %w", inputDataset, err)
+	}
+	if !scan.NeedsReview() {
+		return nil
+	}
+	logger.Logger.Warn("Cannot submit job, input dataset PII findings need review",
+		zap.String("jobID", job.ID),
+		zap.String("datasetID", inputDataset),
+		zap.String("scanStatus", scan.Status),
+		zap.Int("highSeverityCount", scan.HighSeverityCount),
+	)
+	if scan.Status != project.PIIScanCompleted {
+		return fmt.Errorf("%w: PII scan of %s is %s", ErrPIIReviewRequired, inputDataset, scan.Status)
+	}
+	return fmt.Errorf("%w: %s has %d unacknowledged high-severity PII findings", ErrPIIReviewRequired, inputDataset, scan.HighSeverityCount)
+}
+
+// jobInputDataset returns the project dataset a job config names as its input.
+func jobInputDataset(jobConfig, bucketName string) (string, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(jobConfig), &fields); err != nil {
+		return "", false
+	}
+	var name string
+	if raw, ok := fields[configInputDatasetKey]; ok {
+		var input struct {
+			StorageURI string `json:"storageUri"`
+		}
+		if json.Unmarshal(raw, &name) == nil && name != "" {
+			return name, true
+		}
+		if json.Unmarshal(raw, &input) == nil && input.StorageURI != "" {
+			return bucketObject(input.StorageURI, bucketName)
+		}
+	}
+	if raw, ok := fields[configSchemaSourceKey]; ok && json.Unmarshal(raw, &name) == nil && name != "" {
+		return name, true
+	}
+	return "", false
+}
+
+// bucketObject returns the object name of a "gs://<bucket>/<object>" URI in the given
+// bucket. Bare object names are returned as is.
+func bucketObject(uri, bucketName string) (string, bool) {
+	if uri == "" {
+		return "", false
+	}
+	rest, isURI := strings.CutPrefix(uri, "gs://")
+	if !isURI {
+		return uri, true
+	}
+	bucket, object, ok := strings.Cut(rest, "/")
+	if !ok || bucket != bucketName || object == "" {
+		return "", false
+	}
+	return object, true
+}
+
 // ListAllAccessibleJobs retrieves jobs across all projects the user can view.
 func (s *jobService) ListAllAccessibleJobs(ctx context.Context, userID string, statusFilter string, limit, offset int) ([]*core.Job, int, error) {
 	logger.Logger.Info("Listing all accessible jobs for user", zap.String("userID", userID))
@@ -455,7 +1266,8 @@ func (s *jobService) ListAllAccessibleJobs(ctx context.Context, userID string, s
 	// Assuming ListProjects handles the permission check internally.
 	// We might need a dedicated projectService method like GetAllAccessibleProjectIDs if ListProjects requires pagination.
 	// For now, fetch all projects with a high limit (or iterate through pages if necessary).
-	accessibleProjectsResp, err := s.projectSvc.ListProjects(ctx, userID, "", 1000, 0) // High limit to get all projects
+	// Archived projects are included: their job history stays visible even though they are read-only.
+	accessibleProjectsResp, err := s.projectSvc.ListProjects(ctx, userID, "all", 1000, 0) // High limit to get all projects
 	if err != nil {
 		logger.Logger.Error("Failed to list projects to determine accessible jobs", zap.String("userID", userID), zap.Error(err))
 		// Don't expose internal error details directly
@@ -483,3 +1295,72 @@ func (s *jobService) ListAllAccessibleJobs(ctx context.Context, userID string, s
 	logger.Logger.Info("Successfully listed all accessible jobs", zap.String("userID", userID), zap.Int("count", len(jobs)), zap.Int("total", totalCount))
 	return jobs, totalCount, nil
 }
+
+// CancelProjectJobs cancels every pending, queued or running job in a project.
+// Pipeline cancellation failures are logged and the job is still marked cancelled locally,
+// matching CancelJob; jobs that finish meanwhile are left as they are. Only repository
+// failures are returned.
+func (s *jobService) CancelProjectJobs(ctx context.Context, projectID string) error {
+	logger.Logger.Info("Cancelling in-flight jobs for project", zap.String("projectID", projectID))
+
+	// 1. Collect active jobs page by page (the repository has no status filter per project)
+	const pageSize = 100
+	var active []*core.Job
+	for offset := 0; ; offset += pageSize {
+		jobs, total, err := s.jobRepo.ListJobsByProjectID(ctx, projectID, pageSize, offset)
+		if err != nil {
+			return fmt.Errorf("failed to list jobs for project %s: %w", projectID, err)
+		}
+		for _, job := range jobs {
+			if job.Status == core.JobStatusPending || job.Status == core.JobStatusQueued || job.Status == core.JobStatusRunning {
+				active = append(active, job)
+			}
+		}
+		if len(jobs) < pageSize || offset+len(jobs) >= total {
+			break
+		}
+	}
+
+	// 2. Cancel each one, continuing past individual failures
+	var failed, skipped int
+	for _, job := range active {
+		cancelMsg := "Cancelled because the project was archived"
+		if job.PipelineJobID != "" {
+			if err := s.pipeline.Cancel(ctx, job.PipelineJobID); err != nil {
+				cancelMsg = fmt.Sprintf("Cancelled because the project was archived; pipeline cancellation failed: %v", err)
+				logger.Logger.Error("Pipeline cancellation failed during project archive",
+					zap.String("jobID", job.ID),
+					zap.String("pipelineJobID", job.PipelineJobID),
+					zap.Error(err),
+				)
+			}
+		}
+		now := time.Now().UTC()
+		err := s.jobRepo.TransitionJobStatus(ctx, job.ID, job.Status, core.JobStatusCancelled, job.PipelineJobID, job.StartedAt, &now, cancelMsg)
+		if errors.Is(err, core.ErrConflict) {
+			// The job moved on (e.g. completed) since it was listed; its writer published that
+			logger.Logger.Info("Job status changed during project archive, not cancelling", zap.String("jobID", job.ID), zap.Error(err))
+			skipped++
+			continue
+		}
+		if err != nil {
+			logger.Logger.Error("Failed to mark job cancelled during project archive", zap.String("jobID", job.ID), zap.Error(err))
+			failed++
+			continue
+		}
+		cancelled := *job
+		cancelled.Status, cancelled.CompletedAt, cancelled.Error = core.JobStatusCancelled, &now, cancelMsg
+		s.publishStatus(ctx, &cancelled)
+	}
+
+	logger.Logger.Info("Finished cancelling project jobs",
+		zap.String("projectID", projectID),
+		zap.Int("cancelled", len(active)-failed-skipped),
+		zap.Int("skipped", skipped),
+		zap.Int("failed", failed),
+	)
+	if failed > 0 {
+		return fmt.Errorf("failed to cancel %d of %d jobs in project %s", failed, len(active), projectID)
+	}
+	return nil
+}
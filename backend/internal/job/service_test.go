@@ -2,8 +2,10 @@ package job
 
 import (
 	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/events"
 	"SynDataGen/backend/internal/project"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
@@ -38,7 +40,10 @@ func (m *MockJobRepository) GetJobByID(ctx context.Context, jobID string) (*core
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*core.Job), args.Error(1)
+	// Return a copy like the real repo does, so the service mutating the
+	// returned job never leaks into fixtures shared between sub-tests.
+	job := *args.Get(0).(*core.Job)
+	return &job, args.Error(1)
 }
 
 func (m *MockJobRepository) ListJobsByProjectID(ctx context.Context, projectID string, limit int, offset int) ([]*core.Job, int, error) {
@@ -54,11 +59,80 @@ func (m *MockJobRepository) UpdateJobStatus(ctx context.Context, jobID string, n
 	return args.Error(0)
 }
 
+func (m *MockJobRepository) TransitionJobStatus(ctx context.Context, jobID string, fromStatus, newStatus core.JobStatus, pipelineJobID string, startedAt *time.Time, completedAt *time.Time, jobError string) error {
+	args := m.Called(ctx, jobID, fromStatus, newStatus, pipelineJobID, startedAt, completedAt, jobError)
+	return args.Error(0)
+}
+
 func (m *MockJobRepository) UpdateJobResult(ctx context.Context, jobID string, resultURI string) error {
 	args := m.Called(ctx, jobID, resultURI)
 	return args.Error(0)
 }
 
+func (m *MockJobRepository) UpdateJobActuals(ctx context.Context, jobID string, actuals *core.JobActuals) error {
+	args := m.Called(ctx, jobID, actuals)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) MarkJobMetered(ctx context.Context, jobID string, meteredAt time.Time) error {
+	args := m.Called(ctx, jobID, meteredAt)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListUnmeteredJobs(ctx context.Context, limit int) ([]*core.Job, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) MarkJobResultExpired(ctx context.Context, jobID string, expiredAt time.Time) error {
+	args := m.Called(ctx, jobID, expiredAt)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListJobsAcrossProjects(ctx context.Context, projectIDs []string, statusFilter string, limit, offset int) ([]*core.Job, int, error) {
+	args := m.Called(ctx, projectIDs, statusFilter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.Job), args.Int(1), args.Error(2)
+}
+
+func (m *MockJobRepository) ListJobsByStatus(ctx context.Context, status core.JobStatus, limit int, startAfterID string) ([]*core.Job, error) {
+	args := m.Called(ctx, status, limit, startAfterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) CountJobs(ctx context.Context, projectIDs []string, status core.JobStatus) (int, error) {
+	args := m.Called(ctx, projectIDs, status)
+	return args.Int(0), args.Error(1)
+}
+
+// MockUsageRecorder is a mock implementation of UsageRecorder.
+type MockUsageRecorder struct {
+	mock.Mock
+}
+
+func (m *MockUsageRecorder) RecordJobUsage(ctx context.Context, job *core.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+// MockStatusNotifier is a mock implementation of StatusNotifier.
+type MockStatusNotifier struct {
+	mock.Mock
+}
+
+func (m *MockStatusNotifier) NotifyJobStatus(ctx context.Context, job *core.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
 // MockProjectService is a mock implementation of project.ProjectService
 type MockProjectService struct {
 	mock.Mock
@@ -126,6 +200,140 @@ func (m *MockProjectService) RemoveMember(ctx context.Context, projectID string,
 	return args.Get(0).(*core.Project), args.Error(1)
 }
 
+func (m *MockProjectService) GetDatasetContent(ctx context.Context, projectID string, datasetID string, callerID string, query project.DatasetContentQuery) (*project.DatasetContent, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.DatasetContent), args.Error(1)
+}
+
+func (m *MockProjectService) GetDatasetSchema(ctx context.Context, projectID string, datasetID string, callerID string, sampleRows int) (*project.DatasetSchema, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID, sampleRows)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.DatasetSchema), args.Error(1)
+}
+
+func (m *MockProjectService) GetDatasetProfile(ctx context.Context, projectID string, datasetID string, callerID string, refresh bool) (*project.DatasetProfile, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID, refresh)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.DatasetProfile), args.Error(1)
+}
+
+func (m *MockProjectService) GetDatasetPIIScan(ctx context.Context, projectID string, datasetID string, callerID string) (*project.PIIScan, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.PIIScan), args.Error(1)
+}
+
+func (m *MockProjectService) ScanDatasetPII(ctx context.Context, projectID string, datasetID string, callerID string) (*project.PIIScan, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.PIIScan), args.Error(1)
+}
+
+func (m *MockProjectService) AcknowledgeDatasetPII(ctx context.Context, projectID string, datasetID string, callerID string) (*project.PIIScan, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.PIIScan), args.Error(1)
+}
+
+func (m *MockProjectService) CompareDatasets(ctx context.Context, projectID string, callerID string, req project.DatasetComparisonRequest) (*project.FidelityReport, error) {
+	args := m.Called(ctx, projectID, callerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.FidelityReport), args.Error(1)
+}
+
+func (m *MockProjectService) ArchiveProject(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+func (m *MockProjectService) UnarchiveProject(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+func (m *MockProjectService) SetArchiveHook(hook project.ArchiveHook) {
+	m.Called(hook)
+}
+
+func (m *MockProjectService) GetStorageUsage(ctx context.Context, projectID string, callerID string) (*project.StorageUsage, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.StorageUsage), args.Error(1)
+}
+
+func (m *MockProjectService) RefreshStorageUsage(ctx context.Context, projectID string) (*project.StorageUsage, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.StorageUsage), args.Error(1)
+}
+
+func (m *MockProjectService) UploadDataset(ctx context.Context, projectID string, callerID string, req project.UploadDatasetRequest) (*project.DatasetMetadata, error) {
+	args := m.Called(ctx, projectID, callerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.DatasetMetadata), args.Error(1)
+}
+
+func (m *MockProjectService) GetDatasetMetadata(ctx context.Context, projectID string, datasetID string, callerID string) (*project.DatasetMetadata, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.DatasetMetadata), args.Error(1)
+}
+
+func (m *MockProjectService) RenameDataset(ctx context.Context, projectID string, datasetID string, callerID string, req project.RenameDatasetRequest) (*project.DatasetMetadata, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.DatasetMetadata), args.Error(1)
+}
+
+func (m *MockProjectService) DeleteDataset(ctx context.Context, projectID string, datasetID string, callerID string) error {
+	args := m.Called(ctx, projectID, datasetID, callerID)
+	return args.Error(0)
+}
+
+func (m *MockProjectService) ListDatasets(ctx context.Context, projectID string, callerID string, req project.ListDatasetsRequest) (*project.DatasetListing, error) {
+	args := m.Called(ctx, projectID, callerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.DatasetListing), args.Error(1)
+}
+
+func (m *MockProjectService) CreateDatasetFolder(ctx context.Context, projectID string, callerID string, req project.CreateFolderRequest) (string, error) {
+	args := m.Called(ctx, projectID, callerID, req)
+	return args.String(0), args.Error(1)
+}
+
 // MockPipelineClient is a mock implementation of PipelineClient
 type MockPipelineClient struct {
 	mock.Mock
@@ -147,14 +355,66 @@ func (m *MockPipelineClient) Cancel(ctx context.Context, pipelineJobID string) e
 	return args.Error(0)
 }
 
+func (m *MockPipelineClient) ResultURI(ctx context.Context, pipelineJobID string) (string, error) {
+	args := m.Called(ctx, pipelineJobID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockPipelineClient) GetLogs(ctx context.Context, pipelineJobID string, since int64, limit int) ([]LogEntry, error) {
+	args := m.Called(ctx, pipelineJobID, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]LogEntry), args.Error(1)
+}
+
+// MockProgressPipelineClient is a MockPipelineClient that also reports progress.
+type MockProgressPipelineClient struct {
+	MockPipelineClient
+}
+
+func (m *MockProgressPipelineClient) Progress(ctx context.Context, pipelineJobID string) (int, error) {
+	args := m.Called(ctx, pipelineJobID)
+	return args.Int(0), args.Error(1)
+}
+
+// MockReportingPipelineClient is a MockPipelineClient that also reports job output.
+type MockReportingPipelineClient struct {
+	MockPipelineClient
+}
+
+func (m *MockReportingPipelineClient) OutputBytes(ctx context.Context, pipelineJobID string) (int64, error) {
+	args := m.Called(ctx, pipelineJobID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockReportingPipelineClient) GeneratedRecords(ctx context.Context, pipelineJobID string) (int64, error) {
+	args := m.Called(ctx, pipelineJobID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// MockEventPublisher records published events.
+type MockEventPublisher struct {
+	events []events.Event
+}
+
+func (m *MockEventPublisher) Publish(ctx context.Context, event events.Event) {
+	m.events = append(m.events, event)
+}
+
 // --- Helper to create service with mocks ---
 func setupTestService() (JobService, *MockJobRepository, *MockProjectService, *MockPipelineClient) {
+	return setupTestServiceWith(JobServiceOptions{})
+}
+
+// setupTestServiceWith creates a service with mocks and the given collaborators.
+func setupTestServiceWith(opts JobServiceOptions) (JobService, *MockJobRepository, *MockProjectService, *MockPipelineClient) {
 	mockJobRepo := new(MockJobRepository)
 	mockProjectSvc := new(MockProjectService)
 	mockPipeline := new(MockPipelineClient)
 	// Logger is no longer injected
 
-	service := NewJobService(mockJobRepo, mockProjectSvc, mockPipeline)
+	service := NewJobService(mockJobRepo, mockProjectSvc, mockPipeline, opts)
 	return service, mockJobRepo, mockProjectSvc, mockPipeline
 }
 
@@ -249,7 +509,7 @@ func TestJobService_CreateJob(t *testing.T) {
 		require.Error(err)
 		assert.Nil(job)
 		assert.ErrorIs(err, core.ErrForbidden)
-		assert.Contains(err.Error(), "Insufficient role")
+		assert.Contains(err.Error(), "insufficient role")
 
 		mockProjectSvc.AssertExpectations(t)
 	})
@@ -351,6 +611,91 @@ func TestJobService_CreateJob(t *testing.T) {
 		mockProjectSvc.AssertExpectations(t)
 		mockJobRepo.AssertExpectations(t)
 	})
+
+	t.Run("Failure_ProjectArchived", func(t *testing.T) {
+		service, _, mockProjectSvc, _ := setupTestService()
+		archivedProject := *mockProject
+		archivedProject.Status = core.ProjectStatusArchived
+
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(&archivedProject, nil).Once()
+		// CreateJob should NOT be called on the repository
+
+		job, err := service.CreateJob(ctx, projectID, memberID, req)
+
+		require.Error(err)
+		assert.Nil(job)
+		assert.ErrorIs(err, core.ErrProjectArchived)
+
+		mockProjectSvc.AssertExpectations(t)
+	})
+
+	t.Run("Success_SchemaFromDataset", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		sampleReq := CreateJobRequest{JobType: "DATA_GEN", JobConfig: `{"records": 10, "schemaFromDataset": "uploads/sample.csv"}`}
+		inferred := &project.DatasetSchema{
+			DatasetID: "uploads/sample.csv",
+			JobConfigSchema: []project.JobSchemaField{
+				{Name: "id", Type: project.ColumnTypeInteger},
+				{Name: "plan", Type: project.ColumnTypeCategorical, Values: []string{"free", "pro"}},
+			},
+		}
+
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		mockProjectSvc.On("GetDatasetSchema", ctx, projectID, "uploads/sample.csv", memberID, 0).Return(inferred, nil).Once()
+		mockJobRepo.On("CreateJob", ctx, mock.AnythingOfType("*core.Job")).Return(nil).Once()
+
+		job, err := service.CreateJob(ctx, projectID, memberID, sampleReq)
+
+		require.NoError(err)
+		assert.JSONEq(`{
+			"records": 10,
+			"schemaFromDataset": "uploads/sample.csv",
+			"schema": [{"name": "id", "type": "integer"}, {"name": "plan", "type": "categorical", "values": ["free", "pro"]}]
+		}`, job.JobConfig)
+		mockProjectSvc.AssertExpectations(t)
+		mockJobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_ExplicitSchemaKept", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		explicitReq := CreateJobRequest{JobType: "DATA_GEN", JobConfig: `{"schemaFromDataset": "sample.csv", "schema": []}`}
+
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		mockJobRepo.On("CreateJob", ctx, mock.AnythingOfType("*core.Job")).Return(nil).Once()
+
+		job, err := service.CreateJob(ctx, projectID, memberID, explicitReq)
+
+		require.NoError(err)
+		assert.Equal(explicitReq.JobConfig, job.JobConfig)
+		mockProjectSvc.AssertNotCalled(t, "GetDatasetSchema", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_SchemaDatasetNotFound", func(t *testing.T) {
+		service, _, mockProjectSvc, _ := setupTestService()
+		missingReq := CreateJobRequest{JobType: "DATA_GEN", JobConfig: `{"schemaFromDataset": "missing.csv"}`}
+
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		mockProjectSvc.On("GetDatasetSchema", ctx, projectID, "missing.csv", memberID, 0).Return(nil, project.ErrDatasetNotFound).Once()
+
+		job, err := service.CreateJob(ctx, projectID, memberID, missingReq)
+
+		require.Error(err)
+		assert.Nil(job)
+		assert.ErrorIs(err, ErrInvalidJobConfig)
+		mockProjectSvc.AssertExpectations(t)
+	})
+
+	t.Run("Failure_SchemaDatasetNotAString", func(t *testing.T) {
+		service, _, mockProjectSvc, _ := setupTestService()
+		badReq := CreateJobRequest{JobType: "DATA_GEN", JobConfig: `{"schemaFromDataset": 42}`}
+
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+
+		job, err := service.CreateJob(ctx, projectID, memberID, badReq)
+
+		assert.Nil(job)
+		assert.ErrorIs(err, ErrInvalidJobConfig)
+	})
 }
 
 func TestJobService_SubmitJob(t *testing.T) {
@@ -368,6 +713,8 @@ func TestJobService_SubmitJob(t *testing.T) {
 	jobType := "SUBMIT_TEST"
 	jobConfig := `{"param": "value"}`
 	pipelineID := "pipe-" + uuid.NewString()
+	// Submissions carry the job ID as their idempotency key
+	submitCtx := mock.MatchedBy(func(c context.Context) bool { return IdempotencyKeyFromContext(c) == jobID })
 
 	mockJobPending := &core.Job{
 		ID:        jobID,
@@ -409,9 +756,9 @@ func TestJobService_SubmitJob(t *testing.T) {
 		// 2. Expect project access check (member submitting)
 		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
 		// 3. Expect pipeline submission
-		mockPipeline.On("Submit", ctx, jobConfig, jobType, projectID).Return(pipelineID, nil).Once()
+		mockPipeline.On("Submit", submitCtx, jobConfig, jobType, projectID).Return(pipelineID, nil).Once()
 		// 4. Expect status update
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusRunning, pipelineID, mock.AnythingOfType("*time.Time"), (*time.Time)(nil), "").Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusPending, core.JobStatusRunning, pipelineID, mock.AnythingOfType("*time.Time"), (*time.Time)(nil), "").Return(nil).Once()
 
 		job, err := service.SubmitJob(ctx, jobID, memberID)
 
@@ -434,9 +781,9 @@ func TestJobService_SubmitJob(t *testing.T) {
 		// 2. Expect project access check (owner submitting)
 		mockProjectSvc.On("GetProjectByID", ctx, projectID, ownerID).Return(mockProject, nil).Once()
 		// 3. Expect pipeline submission
-		mockPipeline.On("Submit", ctx, jobConfig, jobType, projectID).Return(pipelineID, nil).Once()
+		mockPipeline.On("Submit", submitCtx, jobConfig, jobType, projectID).Return(pipelineID, nil).Once()
 		// 4. Expect status update
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusRunning, pipelineID, mock.AnythingOfType("*time.Time"), (*time.Time)(nil), "").Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusPending, core.JobStatusRunning, pipelineID, mock.AnythingOfType("*time.Time"), (*time.Time)(nil), "").Return(nil).Once()
 
 		job, err := service.SubmitJob(ctx, jobID, ownerID)
 
@@ -477,7 +824,7 @@ func TestJobService_SubmitJob(t *testing.T) {
 		require.Error(err)
 		assert.Nil(job)
 		assert.ErrorIs(err, core.ErrForbidden)
-		assert.Contains(err.Error(), "Insufficient role")
+		assert.Contains(err.Error(), "insufficient role")
 
 		mockJobRepo.AssertExpectations(t)
 		mockProjectSvc.AssertExpectations(t)
@@ -539,6 +886,87 @@ func TestJobService_SubmitJob(t *testing.T) {
 		mockProjectSvc.AssertExpectations(t)
 	})
 
+	t.Run("Failure_StorageQuotaExhausted", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		fullProject := *mockProject
+		fullProject.Settings.MaxStorageGB = 1
+		fullProject.Storage.UsedStorageBytes = 1 << 30
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(mockJobPending, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(&fullProject, nil).Once()
+		// Pipeline and UpdateStatus should NOT be called
+
+		job, err := service.SubmitJob(ctx, jobID, memberID)
+
+		require.Error(err)
+		assert.Nil(job)
+		assert.ErrorIs(err, core.ErrStorageQuotaExceeded)
+
+		mockJobRepo.AssertExpectations(t)
+		mockProjectSvc.AssertExpectations(t)
+	})
+
+	t.Run("PIIAcknowledgementRequired", func(t *testing.T) {
+		guardedProject := *mockProject
+		guardedProject.Settings.RequirePIIAcknowledgement = true
+		guardedProject.Storage.BucketName = "bucket-submit"
+		inputJob := *mockJobPending
+		inputJob.JobConfig = `{"inputDataset": "customers.csv"}`
+		acknowledgedAt := time.Now().UTC()
+
+		for name, tc := range map[string]struct {
+			scan    *project.PIIScan
+			blocked bool
+		}{
+			"Blocked_Unacknowledged": {&project.PIIScan{Status: project.PIIScanCompleted, HighSeverityCount: 2}, true},
+			"Blocked_ScanPending":    {&project.PIIScan{Status: project.PIIScanPending}, true},
+			"Allowed_Acknowledged":   {&project.PIIScan{Status: project.PIIScanCompleted, HighSeverityCount: 2, AcknowledgedAt: &acknowledgedAt}, false},
+			"Allowed_MediumOnly":     {&project.PIIScan{Status: project.PIIScanCompleted}, false},
+		} {
+			t.Run(name, func(t *testing.T) {
+				service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
+
+				mockJobRepo.On("GetJobByID", ctx, jobID).Return(&inputJob, nil).Once()
+				mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(&guardedProject, nil).Once()
+				mockProjectSvc.On("GetDatasetPIIScan", ctx, projectID, "customers.csv", memberID).Return(tc.scan, nil).Once()
+				if !tc.blocked {
+					mockPipeline.On("Submit", submitCtx, inputJob.JobConfig, jobType, projectID).Return(pipelineID, nil).Once()
+					mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusPending, core.JobStatusRunning, pipelineID, mock.AnythingOfType("*time.Time"), (*time.Time)(nil), "").Return(nil).Once()
+				}
+
+				_, err := service.SubmitJob(ctx, jobID, memberID)
+
+				if tc.blocked {
+					assert.ErrorIs(err, ErrPIIReviewRequired)
+					mockPipeline.AssertNotCalled(t, "Submit", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				} else {
+					require.NoError(err)
+				}
+				mockProjectSvc.AssertExpectations(t)
+				mockPipeline.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("Failure_ProjectArchived", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		archivedProject := *mockProject
+		archivedProject.Status = core.ProjectStatusArchived
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(mockJobPending, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(&archivedProject, nil).Once()
+		// Pipeline and UpdateStatus should NOT be called
+
+		job, err := service.SubmitJob(ctx, jobID, memberID)
+
+		require.Error(err)
+		assert.Nil(job)
+		assert.ErrorIs(err, core.ErrProjectArchived)
+
+		mockJobRepo.AssertExpectations(t)
+		mockProjectSvc.AssertExpectations(t)
+	})
+
 	t.Run("PipelineSubmitError", func(t *testing.T) {
 		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
 		pipelineError := errors.New("pipeline unavailable")
@@ -548,9 +976,9 @@ func TestJobService_SubmitJob(t *testing.T) {
 		// 2. Expect project access check
 		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
 		// 3. Expect pipeline submission to fail
-		mockPipeline.On("Submit", ctx, jobConfig, jobType, projectID).Return("", pipelineError).Once()
+		mockPipeline.On("Submit", submitCtx, jobConfig, jobType, projectID).Return("", pipelineError).Once()
 		// 4. Expect status update to FAILED because pipeline failed
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusFailed, "", (*time.Time)(nil), (*time.Time)(nil), fmt.Sprintf("Pipeline submission failed: %v", pipelineError)).Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusPending, core.JobStatusFailed, "", (*time.Time)(nil), (*time.Time)(nil), fmt.Sprintf("Pipeline submission failed: %v", pipelineError)).Return(nil).Once()
 
 		job, err := service.SubmitJob(ctx, jobID, memberID)
 
@@ -565,19 +993,37 @@ func TestJobService_SubmitJob(t *testing.T) {
 		mockPipeline.AssertExpectations(t)
 	})
 
-	t.Run("PipelineSubmitError_UpdateStatusError", func(t *testing.T) {
+	t.Run("PipelineUnavailable_JobStaysPending", func(t *testing.T) {
 		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
-		pipelineError := errors.New("pipeline unavailable")
-		updateError := errors.New("failed to update status in db")
+		pipelineError := fmt.Errorf("%w: circuit breaker open", ErrPipelineUnavailable)
 
-		// 1. Expect GetJobByID
 		mockJobRepo.On("GetJobByID", ctx, jobID).Return(mockJobPending, nil).Once()
-		// 2. Expect project access check
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		mockPipeline.On("Submit", submitCtx, jobConfig, jobType, projectID).Return("", pipelineError).Once()
+		// No status update: the job can be submitted again once the pipeline recovers
+
+		job, err := service.SubmitJob(ctx, jobID, memberID)
+
+		require.Error(err)
+		assert.Nil(job)
+		assert.ErrorIs(err, ErrPipelineUnavailable)
+		mockJobRepo.AssertNotCalled(t, "TransitionJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockPipeline.AssertExpectations(t)
+	})
+
+	t.Run("PipelineSubmitError_UpdateStatusError", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
+		pipelineError := errors.New("pipeline unavailable")
+		updateError := errors.New("failed to update status in db")
+
+		// 1. Expect GetJobByID
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(mockJobPending, nil).Once()
+		// 2. Expect project access check
 		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
 		// 3. Expect pipeline submission to fail
-		mockPipeline.On("Submit", ctx, jobConfig, jobType, projectID).Return("", pipelineError).Once()
+		mockPipeline.On("Submit", submitCtx, jobConfig, jobType, projectID).Return("", pipelineError).Once()
 		// 4. Expect status update to FAILED to also fail
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusFailed, "", (*time.Time)(nil), (*time.Time)(nil), fmt.Sprintf("Pipeline submission failed: %v", pipelineError)).Return(updateError).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusPending, core.JobStatusFailed, "", (*time.Time)(nil), (*time.Time)(nil), fmt.Sprintf("Pipeline submission failed: %v", pipelineError)).Return(updateError).Once()
 
 		job, err := service.SubmitJob(ctx, jobID, memberID)
 
@@ -600,9 +1046,9 @@ func TestJobService_SubmitJob(t *testing.T) {
 		// 2. Expect project access check
 		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
 		// 3. Expect pipeline submission to succeed
-		mockPipeline.On("Submit", ctx, jobConfig, jobType, projectID).Return(pipelineID, nil).Once()
+		mockPipeline.On("Submit", submitCtx, jobConfig, jobType, projectID).Return(pipelineID, nil).Once()
 		// 4. Expect status update to fail
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusRunning, pipelineID, mock.AnythingOfType("*time.Time"), (*time.Time)(nil), "").Return(updateError).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusPending, core.JobStatusRunning, pipelineID, mock.AnythingOfType("*time.Time"), (*time.Time)(nil), "").Return(updateError).Once()
 
 		job, err := service.SubmitJob(ctx, jobID, memberID)
 
@@ -969,7 +1415,7 @@ func TestJobService_CancelJob(t *testing.T) {
 		// 3. Call Pipeline Cancel
 		mockPipeline.On("Cancel", ctx, pipelineID).Return(nil).Once()
 		// 4. Update Job Status
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusCancelled, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), "Cancelled by user via pipeline request").Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusRunning, core.JobStatusCancelled, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), "Cancelled by user via pipeline request").Return(nil).Once()
 
 		job, err := service.CancelJob(ctx, jobID, memberID)
 
@@ -990,7 +1436,7 @@ func TestJobService_CancelJob(t *testing.T) {
 		mockJobRepo.On("GetJobByID", ctx, jobID).Return(mockJobRunning, nil).Once()
 		mockProjectSvc.On("GetProjectByID", ctx, projectID, ownerID).Return(mockProject, nil).Once()
 		mockPipeline.On("Cancel", ctx, pipelineID).Return(nil).Once()
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusCancelled, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), "Cancelled by user via pipeline request").Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusRunning, core.JobStatusCancelled, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), "Cancelled by user via pipeline request").Return(nil).Once()
 
 		job, err := service.CancelJob(ctx, jobID, ownerID)
 
@@ -1012,7 +1458,7 @@ func TestJobService_CancelJob(t *testing.T) {
 		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
 		// 3. Pipeline Cancel should NOT be called
 		// 4. Update Job Status
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusCancelled, "", (*time.Time)(nil), mock.AnythingOfType("*time.Time"), "Cancelled by user before submission").Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusPending, core.JobStatusCancelled, "", (*time.Time)(nil), mock.AnythingOfType("*time.Time"), "Cancelled by user before submission").Return(nil).Once()
 
 		job, err := service.CancelJob(ctx, jobID, memberID)
 
@@ -1049,7 +1495,7 @@ func TestJobService_CancelJob(t *testing.T) {
 		require.Error(err)
 		assert.Nil(job)
 		assert.ErrorIs(err, core.ErrForbidden)
-		assert.Contains(err.Error(), "Insufficient role")
+		assert.Contains(err.Error(), "insufficient role")
 		mockJobRepo.AssertExpectations(t)
 		mockProjectSvc.AssertExpectations(t)
 	})
@@ -1091,7 +1537,7 @@ func TestJobService_CancelJob(t *testing.T) {
 		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
 		mockPipeline.On("Cancel", ctx, pipelineID).Return(pipelineError).Once()
 		// Update status should still be called, noting the pipeline error
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusCancelled, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), expectedErrMsg).Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusRunning, core.JobStatusCancelled, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), expectedErrMsg).Return(nil).Once()
 
 		job, err := service.CancelJob(ctx, jobID, memberID)
 
@@ -1113,7 +1559,7 @@ func TestJobService_CancelJob(t *testing.T) {
 		mockJobRepo.On("GetJobByID", ctx, jobID).Return(mockJobRunning, nil).Once()
 		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
 		mockPipeline.On("Cancel", ctx, pipelineID).Return(nil).Once()
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusCancelled, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), "Cancelled by user via pipeline request").Return(updateError).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusRunning, core.JobStatusCancelled, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), "Cancelled by user via pipeline request").Return(updateError).Once()
 
 		job, err := service.CancelJob(ctx, jobID, memberID)
 
@@ -1183,7 +1629,13 @@ func TestJobService_SyncJobStatus(t *testing.T) {
 		// 3. Check Pipeline Status (returns Completed)
 		mockPipeline.On("CheckStatus", ctx, pipelineID).Return(core.JobStatusCompleted, "", nil).Once()
 		// 4. Update Job Status (because status changed)
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusCompleted, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), "").Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusRunning, core.JobStatusCompleted, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), "").Return(nil).Once()
+		// 5. Ask for the output location (this pipeline reports none)
+		mockPipeline.On("ResultURI", ctx, pipelineID).Return("", nil).Once()
+		// 6. Record the job's actuals
+		mockJobRepo.On("UpdateJobActuals", ctx, jobID, mock.AnythingOfType("*core.JobActuals")).Return(nil).Once()
+		// 7. Refresh storage usage (job outputs landed in the bucket)
+		mockProjectSvc.On("RefreshStorageUsage", ctx, projectID).Return(&project.StorageUsage{}, nil).Once()
 
 		job, err := service.SyncJobStatus(ctx, jobID, viewerID)
 
@@ -1191,12 +1643,84 @@ func TestJobService_SyncJobStatus(t *testing.T) {
 		require.NotNil(job)
 		assert.Equal(core.JobStatusCompleted, job.Status)
 		assert.NotNil(job.CompletedAt)
+		assert.Empty(job.ResultURI)
+		mockJobRepo.AssertNotCalled(t, "UpdateJobResult", mock.Anything, mock.Anything, mock.Anything)
 
 		mockJobRepo.AssertExpectations(t)
 		mockProjectSvc.AssertExpectations(t)
 		mockPipeline.AssertExpectations(t)
 	})
 
+	t.Run("Success_CompletedJobRecordsResultURI", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
+		running := *mockJobRunning
+		resultURI := "gs://sync-bucket/jobs/" + pipelineID + "/output.csv"
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(&running, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+		mockPipeline.On("CheckStatus", ctx, pipelineID).Return(core.JobStatusCompleted, "", nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusRunning, core.JobStatusCompleted, pipelineID, running.StartedAt, mock.AnythingOfType("*time.Time"), "").Return(nil).Once()
+		mockPipeline.On("ResultURI", ctx, pipelineID).Return(resultURI, nil).Once()
+		mockJobRepo.On("UpdateJobResult", ctx, jobID, resultURI).Return(nil).Once()
+		mockJobRepo.On("UpdateJobActuals", ctx, jobID, mock.AnythingOfType("*core.JobActuals")).Return(nil).Once()
+		mockProjectSvc.On("RefreshStorageUsage", ctx, projectID).Return(&project.StorageUsage{}, nil).Once()
+
+		job, err := service.SyncJobStatus(ctx, jobID, viewerID)
+
+		require.NoError(err)
+		assert.Equal(resultURI, job.ResultURI)
+		mockJobRepo.AssertExpectations(t)
+		mockPipeline.AssertExpectations(t)
+	})
+
+	t.Run("Success_CompletedJobRecordsUsage", func(t *testing.T) {
+		recorder := new(MockUsageRecorder)
+		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestServiceWith(JobServiceOptions{Usage: recorder})
+		running := *mockJobRunning
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(&running, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+		mockPipeline.On("CheckStatus", ctx, pipelineID).Return(core.JobStatusCompleted, "", nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusRunning, core.JobStatusCompleted, pipelineID, running.StartedAt, mock.AnythingOfType("*time.Time"), "").Return(nil).Once()
+		mockPipeline.On("ResultURI", ctx, pipelineID).Return("", nil).Once()
+		mockJobRepo.On("UpdateJobActuals", ctx, jobID, mock.AnythingOfType("*core.JobActuals")).Return(nil).Once()
+		// A recording failure leaves the job for the recorder's reconciliation
+		recorder.On("RecordJobUsage", ctx, mock.MatchedBy(func(j *core.Job) bool {
+			return j.ID == jobID && j.Status == core.JobStatusCompleted && j.Actuals != nil
+		})).Return(errors.New("unavailable")).Once()
+		mockProjectSvc.On("RefreshStorageUsage", ctx, projectID).Return(&project.StorageUsage{}, nil).Once()
+
+		job, err := service.SyncJobStatus(ctx, jobID, viewerID)
+
+		require.NoError(err)
+		assert.Equal(core.JobStatusCompleted, job.Status)
+		recorder.AssertExpectations(t)
+	})
+
+	t.Run("Success_CompletedJobNotifies", func(t *testing.T) {
+		notifier := new(MockStatusNotifier)
+		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestServiceWith(JobServiceOptions{Notifier: notifier})
+		running := *mockJobRunning
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(&running, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+		mockPipeline.On("CheckStatus", ctx, pipelineID).Return(core.JobStatusCompleted, "", nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusRunning, core.JobStatusCompleted, pipelineID, running.StartedAt, mock.AnythingOfType("*time.Time"), "").Return(nil).Once()
+		// A notifier failure does not fail the sync
+		notifier.On("NotifyJobStatus", ctx, mock.MatchedBy(func(j *core.Job) bool {
+			return j.ID == jobID && j.Status == core.JobStatusCompleted
+		})).Return(errors.New("unavailable")).Once()
+		mockPipeline.On("ResultURI", ctx, pipelineID).Return("", nil).Once()
+		mockJobRepo.On("UpdateJobActuals", ctx, jobID, mock.AnythingOfType("*core.JobActuals")).Return(nil).Once()
+		mockProjectSvc.On("RefreshStorageUsage", ctx, projectID).Return(&project.StorageUsage{}, nil).Once()
+
+		job, err := service.SyncJobStatus(ctx, jobID, viewerID)
+
+		require.NoError(err)
+		assert.Equal(core.JobStatusCompleted, job.Status)
+		notifier.AssertExpectations(t)
+	})
+
 	t.Run("Success_MemberSyncs_StatusUnchanged", func(t *testing.T) {
 		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
 
@@ -1217,7 +1741,7 @@ func TestJobService_SyncJobStatus(t *testing.T) {
 		mockJobRepo.AssertExpectations(t)
 		mockProjectSvc.AssertExpectations(t)
 		mockPipeline.AssertExpectations(t)
-		mockJobRepo.AssertNotCalled(t, "UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockJobRepo.AssertNotCalled(t, "TransitionJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("Success_StatusChangedToFailed", func(t *testing.T) {
@@ -1231,7 +1755,7 @@ func TestJobService_SyncJobStatus(t *testing.T) {
 		// 3. Check Pipeline Status (returns Failed with message)
 		mockPipeline.On("CheckStatus", ctx, pipelineID).Return(core.JobStatusFailed, pipelineErrorMsg, nil).Once()
 		// 4. Update Job Status
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusFailed, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), pipelineErrorMsg).Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusRunning, core.JobStatusFailed, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), pipelineErrorMsg).Return(nil).Once()
 
 		job, err := service.SyncJobStatus(ctx, jobID, viewerID)
 
@@ -1339,7 +1863,7 @@ func TestJobService_SyncJobStatus(t *testing.T) {
 		mockJobRepo.On("GetJobByID", ctx, jobID).Return(mockJobRunning, nil).Once()
 		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
 		mockPipeline.On("CheckStatus", ctx, pipelineID).Return(core.JobStatusCompleted, "", nil).Once()
-		mockJobRepo.On("UpdateJobStatus", ctx, jobID, core.JobStatusCompleted, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), "").Return(updateError).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, jobID, core.JobStatusRunning, core.JobStatusCompleted, pipelineID, mockJobRunning.StartedAt, mock.AnythingOfType("*time.Time"), "").Return(updateError).Once()
 
 		job, err := service.SyncJobStatus(ctx, jobID, viewerID)
 
@@ -1426,7 +1950,7 @@ func TestJobService_authorizeJobAction(t *testing.T) {
 			mockProjectSvc.On("GetProjectByID", ctx, projectID, tt.userID).Return(tt.mockProjectSvcReturnProject, tt.mockProjectSvcReturnError).Once()
 
 			// Call the function under test
-			actErr := service.(*jobService).authorizeJobAction(ctx, projectID, tt.userID, tt.requiredRole)
+			_, actErr := service.(*jobService).authorizeJobAction(ctx, projectID, tt.userID, tt.requiredRole)
 
 			// Assertions
 			if tt.expectedError == nil {
@@ -1448,3 +1972,705 @@ func TestJobService_authorizeJobAction(t *testing.T) {
 }
 
 // All service methods tested
+
+func TestJobService_CancelProjectJobs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-" + uuid.NewString()
+	startedAt := time.Now().UTC().Add(-time.Hour)
+
+	pendingJob := &core.Job{ID: "job-pending", ProjectID: projectID, Status: core.JobStatusPending}
+	runningJob := &core.Job{ID: "job-running", ProjectID: projectID, Status: core.JobStatusRunning, PipelineJobID: "pipe-1", StartedAt: &startedAt}
+	completedJob := &core.Job{ID: "job-completed", ProjectID: projectID, Status: core.JobStatusCompleted}
+	jobs := []*core.Job{pendingJob, runningJob, completedJob}
+
+	t.Run("Success_CancelsOnlyActiveJobs", func(t *testing.T) {
+		service, mockJobRepo, _, mockPipeline := setupTestService()
+
+		mockJobRepo.On("ListJobsByProjectID", ctx, projectID, 100, 0).Return(jobs, len(jobs), nil).Once()
+		mockPipeline.On("Cancel", ctx, "pipe-1").Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, pendingJob.ID, core.JobStatusPending, core.JobStatusCancelled, "", (*time.Time)(nil), mock.AnythingOfType("*time.Time"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, runningJob.ID, core.JobStatusRunning, core.JobStatusCancelled, "pipe-1", &startedAt, mock.AnythingOfType("*time.Time"), mock.AnythingOfType("string")).Return(nil).Once()
+
+		err := service.CancelProjectJobs(ctx, projectID)
+
+		require.NoError(err)
+		mockJobRepo.AssertExpectations(t)
+		mockPipeline.AssertExpectations(t)
+	})
+
+	t.Run("Success_PipelineCancelFailureStillMarksCancelled", func(t *testing.T) {
+		service, mockJobRepo, _, mockPipeline := setupTestService()
+
+		mockJobRepo.On("ListJobsByProjectID", ctx, projectID, 100, 0).Return([]*core.Job{runningJob}, 1, nil).Once()
+		mockPipeline.On("Cancel", ctx, "pipe-1").Return(errors.New("pipeline unreachable")).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, runningJob.ID, core.JobStatusRunning, core.JobStatusCancelled, "pipe-1", &startedAt, mock.AnythingOfType("*time.Time"), mock.MatchedBy(func(msg string) bool {
+			return assert.Contains(msg, "pipeline unreachable")
+		})).Return(nil).Once()
+
+		err := service.CancelProjectJobs(ctx, projectID)
+
+		require.NoError(err)
+		mockJobRepo.AssertExpectations(t)
+		mockPipeline.AssertExpectations(t)
+	})
+
+	t.Run("Success_SkipsJobsThatFinishedMeanwhile", func(t *testing.T) {
+		service, mockJobRepo, _, mockPipeline := setupTestService()
+
+		mockJobRepo.On("ListJobsByProjectID", ctx, projectID, 100, 0).Return([]*core.Job{runningJob, pendingJob}, 2, nil).Once()
+		mockPipeline.On("Cancel", ctx, "pipe-1").Return(nil).Once()
+		// The running job completed after it was listed
+		mockJobRepo.On("TransitionJobStatus", ctx, runningJob.ID, core.JobStatusRunning, core.JobStatusCancelled, "pipe-1", &startedAt, mock.AnythingOfType("*time.Time"), mock.AnythingOfType("string")).
+			Return(fmt.Errorf("job changed: %w", core.ErrConflict)).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, pendingJob.ID, core.JobStatusPending, core.JobStatusCancelled, "", (*time.Time)(nil), mock.AnythingOfType("*time.Time"), mock.AnythingOfType("string")).Return(nil).Once()
+
+		err := service.CancelProjectJobs(ctx, projectID)
+
+		require.NoError(err)
+		mockJobRepo.AssertNotCalled(t, "UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockJobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_UpdateStatusError", func(t *testing.T) {
+		service, mockJobRepo, _, _ := setupTestService()
+
+		mockJobRepo.On("ListJobsByProjectID", ctx, projectID, 100, 0).Return([]*core.Job{pendingJob}, 1, nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, pendingJob.ID, core.JobStatusPending, core.JobStatusCancelled, "", (*time.Time)(nil), mock.AnythingOfType("*time.Time"), mock.AnythingOfType("string")).Return(errors.New("db down")).Once()
+
+		err := service.CancelProjectJobs(ctx, projectID)
+
+		require.Error(err)
+		assert.Contains(err.Error(), "failed to cancel 1 of 1 jobs")
+		mockJobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_ListJobsError", func(t *testing.T) {
+		service, mockJobRepo, _, _ := setupTestService()
+		repoErr := errors.New("list failed")
+
+		mockJobRepo.On("ListJobsByProjectID", ctx, projectID, 100, 0).Return(nil, 0, repoErr).Once()
+
+		err := service.CancelProjectJobs(ctx, projectID)
+
+		require.Error(err)
+		assert.ErrorIs(err, repoErr)
+		mockJobRepo.AssertExpectations(t)
+	})
+}
+
+func TestJobService_FailQueuedJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	queuedJob := &core.Job{ID: "job-queued", ProjectID: "proj-1", UserID: "removed", Status: core.JobStatusQueued}
+
+	t.Run("Success", func(t *testing.T) {
+		service, mockJobRepo, _, _ := setupTestService()
+		mockJobRepo.On("GetJobByID", ctx, queuedJob.ID).Return(queuedJob, nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, queuedJob.ID, core.JobStatusQueued, core.JobStatusFailed, "", (*time.Time)(nil), mock.AnythingOfType("*time.Time"), "access revoked").Return(nil).Once()
+
+		job, err := service.FailQueuedJob(ctx, queuedJob.ID, "access revoked")
+
+		require.NoError(err)
+		assert.Equal(core.JobStatusFailed, job.Status)
+		assert.Equal("access revoked", job.Error)
+		assert.NotNil(job.CompletedAt)
+		mockJobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_NotQueued", func(t *testing.T) {
+		service, mockJobRepo, _, _ := setupTestService()
+		running := *queuedJob
+		running.Status = core.JobStatusRunning
+		mockJobRepo.On("GetJobByID", ctx, queuedJob.ID).Return(&running, nil).Once()
+
+		_, err := service.FailQueuedJob(ctx, queuedJob.ID, "access revoked")
+
+		require.Error(err)
+		mockJobRepo.AssertNotCalled(t, "TransitionJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestJobService_FailDueRetry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+	retryAt := time.Now().Add(-time.Minute)
+	dueRetry := &core.Job{ID: "job-retry", ProjectID: "proj-1", UserID: "removed", Status: core.JobStatusPending, RetryAt: &retryAt}
+
+	t.Run("Success", func(t *testing.T) {
+		service, mockJobRepo, _, _ := setupTestService()
+		mockJobRepo.On("GetJobByID", ctx, dueRetry.ID).Return(dueRetry, nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, dueRetry.ID, core.JobStatusPending, core.JobStatusFailed, "", (*time.Time)(nil), mock.AnythingOfType("*time.Time"), "access revoked").Return(nil).Once()
+
+		job, err := service.FailDueRetry(ctx, dueRetry.ID, "access revoked")
+
+		require.NoError(err)
+		assert.Equal(core.JobStatusFailed, job.Status)
+		assert.Equal("access revoked", job.Error)
+		mockJobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_NotPending", func(t *testing.T) {
+		service, mockJobRepo, _, _ := setupTestService()
+		running := *dueRetry
+		running.Status = core.JobStatusRunning
+		mockJobRepo.On("GetJobByID", ctx, dueRetry.ID).Return(&running, nil).Once()
+
+		_, err := service.FailDueRetry(ctx, dueRetry.ID, "access revoked")
+
+		require.Error(err)
+		mockJobRepo.AssertNotCalled(t, "TransitionJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestJobService_GetQualityReport(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	jobID := "job-" + uuid.NewString()
+	projectID := "proj-" + uuid.NewString()
+	viewerID := "user-viewer-" + uuid.NewString()
+	bucketName := "bucket-quality"
+
+	mockProject := &core.Project{
+		ID:          projectID,
+		Storage:     core.ProjectStorage{BucketName: bucketName},
+		TeamMembers: map[string]core.Role{viewerID: core.RoleViewer},
+	}
+	completedJob := func(config, resultURI string) *core.Job {
+		return &core.Job{
+			ID:        jobID,
+			ProjectID: projectID,
+			Status:    core.JobStatusCompleted,
+			JobConfig: config,
+			ResultURI: resultURI,
+		}
+	}
+	resultURI := "gs://" + bucketName + "/jobs/" + jobID + "/data.csv"
+
+	t.Run("Success_ComparesWithInputDataset", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		report := &project.FidelityReport{RealDatasetID: "customers.csv"}
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(completedJob(`{"inputDataset": "customers.csv"}`, resultURI), nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+		mockProjectSvc.On("CompareDatasets", ctx, projectID, viewerID, project.DatasetComparisonRequest{
+			RealDatasetID:      "customers.csv",
+			SyntheticDatasetID: "jobs/" + jobID + "/data.csv",
+			ReportName:         "jobs/" + jobID + "/quality-report.json",
+			Refresh:            true,
+		}).Return(report, nil).Once()
+
+		got, err := service.GetQualityReport(ctx, jobID, viewerID, true)
+
+		require.NoError(err)
+		assert.Equal(report, got)
+		mockProjectSvc.AssertExpectations(t)
+	})
+
+	t.Run("Success_InputFromStorageURIAndSchemaSource", func(t *testing.T) {
+		for config, want := range map[string]string{
+			`{"inputDataset": {"storageUri": "gs://bucket-quality/raw/a.parquet"}}`: "raw/a.parquet",
+			`{"schemaFromDataset": "seed.csv"}`:                                     "seed.csv",
+		} {
+			service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+			mockJobRepo.On("GetJobByID", ctx, jobID).Return(completedJob(config, resultURI), nil).Once()
+			mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+			mockProjectSvc.On("CompareDatasets", ctx, projectID, viewerID, mock.MatchedBy(func(req project.DatasetComparisonRequest) bool {
+				return req.RealDatasetID == want
+			})).Return(&project.FidelityReport{}, nil).Once()
+
+			_, err := service.GetQualityReport(ctx, jobID, viewerID, false)
+
+			require.NoError(err, config)
+			mockProjectSvc.AssertExpectations(t)
+		}
+	})
+
+	t.Run("Failure_JobNotCompleted", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		job := completedJob(`{"inputDataset": "customers.csv"}`, "")
+		job.Status = core.JobStatusRunning
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(job, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+
+		_, err := service.GetQualityReport(ctx, jobID, viewerID, false)
+
+		assert.ErrorIs(err, ErrJobNotCompleted)
+	})
+
+	t.Run("Failure_ResultExpired", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		expiredAt := time.Now().UTC()
+		job := completedJob(`{"inputDataset": "customers.csv"}`, resultURI)
+		job.ResultExpiredAt = &expiredAt
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(job, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+
+		_, err := service.GetQualityReport(ctx, jobID, viewerID, false)
+
+		assert.ErrorIs(err, ErrJobResultUnavailable)
+	})
+
+	t.Run("Failure_ResultOutsideProjectBucket", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(completedJob(`{"inputDataset": "customers.csv"}`, "gs://elsewhere/data.csv"), nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+
+		_, err := service.GetQualityReport(ctx, jobID, viewerID, false)
+
+		assert.ErrorIs(err, ErrJobResultUnavailable)
+	})
+
+	t.Run("Failure_NoInputDataset", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(completedJob(`{"rows": 100}`, resultURI), nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+
+		_, err := service.GetQualityReport(ctx, jobID, viewerID, false)
+
+		assert.ErrorIs(err, ErrNoInputDataset)
+		mockProjectSvc.AssertNotCalled(t, "CompareDatasets", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_StrangerDenied", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(completedJob(`{"inputDataset": "customers.csv"}`, resultURI), nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, "stranger").Return(nil, project.ErrProjectAccessDenied).Once()
+
+		_, err := service.GetQualityReport(ctx, jobID, "stranger", false)
+
+		assert.ErrorIs(err, project.ErrProjectAccessDenied)
+	})
+}
+
+func TestJobService_GetJobLogs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	jobID := "job-" + uuid.NewString()
+	projectID := "proj-" + uuid.NewString()
+	viewerID := "user-viewer-" + uuid.NewString()
+	strangerID := "user-stranger-" + uuid.NewString()
+	pipelineID := "pipe-" + uuid.NewString()
+
+	mockProject := &core.Project{ID: projectID, TeamMembers: map[string]core.Role{viewerID: core.RoleViewer}}
+	completedJob := &core.Job{ID: jobID, ProjectID: projectID, Status: core.JobStatusCompleted, PipelineJobID: pipelineID}
+	entries := func(from, to int64) []LogEntry {
+		var logs []LogEntry
+		for seq := from; seq <= to; seq++ {
+			logs = append(logs, LogEntry{Sequence: seq, Level: LogLevelInfo, Message: fmt.Sprintf("line %d", seq)})
+		}
+		return logs
+	}
+
+	t.Run("Success_PageWithMore", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(completedJob, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+		mockPipeline.On("GetLogs", ctx, pipelineID, int64(10), 3).Return(entries(11, 13), nil).Once()
+
+		page, err := service.GetJobLogs(ctx, jobID, viewerID, 10, 2)
+
+		require.NoError(err)
+		assert.Equal(entries(11, 12), page.Logs)
+		assert.Equal("12", page.NextCursor)
+		assert.True(page.HasMore)
+		assert.Equal(core.JobStatusCompleted, page.JobStatus)
+		mockPipeline.AssertExpectations(t)
+	})
+
+	t.Run("Success_CaughtUp", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(completedJob, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+		mockPipeline.On("GetLogs", ctx, pipelineID, int64(13), 101).Return([]LogEntry{}, nil).Once()
+
+		page, err := service.GetJobLogs(ctx, jobID, viewerID, 13, 100)
+
+		require.NoError(err)
+		assert.Empty(page.Logs)
+		assert.Equal("13", page.NextCursor, "the cursor stays put until new entries arrive")
+		assert.False(page.HasMore)
+	})
+
+	t.Run("Success_NotSubmitted", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
+		pendingJob := &core.Job{ID: jobID, ProjectID: projectID, Status: core.JobStatusPending}
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(pendingJob, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+
+		page, err := service.GetJobLogs(ctx, jobID, viewerID, 0, 100)
+
+		require.NoError(err)
+		assert.Empty(page.Logs)
+		assert.Equal(core.JobStatusPending, page.JobStatus)
+		mockPipeline.AssertNotCalled(t, "GetLogs", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_PermissionDenied", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(completedJob, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, strangerID).Return(nil, project.ErrProjectAccessDenied).Once()
+
+		page, err := service.GetJobLogs(ctx, jobID, strangerID, 0, 100)
+
+		assert.Nil(page)
+		assert.ErrorIs(err, project.ErrProjectAccessDenied)
+		mockPipeline.AssertNotCalled(t, "GetLogs", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_PipelineError", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, mockPipeline := setupTestService()
+		mockJobRepo.On("GetJobByID", ctx, jobID).Return(completedJob, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+		mockPipeline.On("GetLogs", ctx, pipelineID, int64(0), 101).Return(nil, ErrPipelineUnavailable).Once()
+
+		_, err := service.GetJobLogs(ctx, jobID, viewerID, 0, 100)
+
+		assert.ErrorIs(err, ErrPipelineUnavailable)
+	})
+}
+
+func TestJobService_Events(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-" + uuid.NewString()
+	memberID := "user-member-" + uuid.NewString()
+	pipelineID := "pipe-" + uuid.NewString()
+	mockProject := &core.Project{ID: projectID, TeamMembers: map[string]core.Role{memberID: core.RoleMember}}
+	runningJob := &core.Job{ID: "job-running", ProjectID: projectID, Status: core.JobStatusRunning, PipelineJobID: pipelineID}
+
+	setup := func() (JobService, *MockJobRepository, *MockProjectService, *MockProgressPipelineClient, *MockEventPublisher) {
+		mockJobRepo := new(MockJobRepository)
+		mockProjectSvc := new(MockProjectService)
+		mockPipeline := new(MockProgressPipelineClient)
+		publisher := &MockEventPublisher{}
+		service := NewJobService(mockJobRepo, mockProjectSvc, mockPipeline, JobServiceOptions{Events: publisher})
+		return service, mockJobRepo, mockProjectSvc, mockPipeline, publisher
+	}
+
+	t.Run("Success_CreatePublishesPending", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _, publisher := setup()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		mockJobRepo.On("CreateJob", ctx, mock.AnythingOfType("*core.Job")).Return(nil).Once()
+
+		job, err := service.CreateJob(ctx, projectID, memberID, CreateJobRequest{JobType: "csv", JobConfig: `{}`})
+
+		require.NoError(err)
+		require.Len(publisher.events, 1)
+		assert.Equal(events.TypeJobStatus, publisher.events[0].Type)
+		assert.Equal(job.ID, publisher.events[0].JobID)
+		assert.Equal(projectID, publisher.events[0].ProjectID)
+		assert.Equal(core.JobStatusPending, publisher.events[0].Status)
+	})
+
+	t.Run("Success_ProgressPublishedOnChange", func(t *testing.T) {
+		service, mockJobRepo, _, mockPipeline, publisher := setup()
+		mockJobRepo.On("GetJobByID", ctx, runningJob.ID).Return(runningJob, nil)
+		mockPipeline.On("CheckStatus", ctx, pipelineID).Return(core.JobStatusRunning, "", nil)
+		mockPipeline.On("Progress", ctx, pipelineID).Return(40, nil).Twice()
+		mockPipeline.On("Progress", ctx, pipelineID).Return(60, nil).Once()
+
+		for range 3 {
+			_, err := service.RefreshJobStatus(ctx, runningJob.ID)
+			require.NoError(err)
+		}
+
+		require.Len(publisher.events, 2, "an unchanged percentage is not announced again")
+		assert.Equal(events.TypeJobProgress, publisher.events[0].Type)
+		assert.Equal(40, *publisher.events[0].Progress)
+		assert.Equal(60, *publisher.events[1].Progress)
+		mockPipeline.AssertExpectations(t)
+	})
+
+	t.Run("Success_CompletionPublishesStatusAndResult", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, mockPipeline, publisher := setup()
+		mockJobRepo.On("GetJobByID", ctx, runningJob.ID).Return(runningJob, nil).Once()
+		mockPipeline.On("CheckStatus", ctx, pipelineID).Return(core.JobStatusCompleted, "", nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, runningJob.ID, core.JobStatusRunning, core.JobStatusCompleted, pipelineID, (*time.Time)(nil), mock.AnythingOfType("*time.Time"), "").Return(nil).Once()
+		mockPipeline.On("ResultURI", ctx, pipelineID).Return("gs://bucket/out.csv", nil).Once()
+		mockJobRepo.On("UpdateJobResult", ctx, runningJob.ID, "gs://bucket/out.csv").Return(nil).Once()
+		mockJobRepo.On("UpdateJobActuals", ctx, runningJob.ID, mock.AnythingOfType("*core.JobActuals")).Return(nil).Once()
+		mockProjectSvc.On("RefreshStorageUsage", ctx, projectID).Return(&project.StorageUsage{}, nil).Once()
+
+		_, err := service.RefreshJobStatus(ctx, runningJob.ID)
+
+		require.NoError(err)
+		require.Len(publisher.events, 2)
+		assert.Equal(events.TypeJobStatus, publisher.events[0].Type)
+		assert.Equal(core.JobStatusCompleted, publisher.events[0].Status)
+		assert.Equal(events.TypeJobResult, publisher.events[1].Type)
+		assert.Equal("gs://bucket/out.csv", publisher.events[1].ResultURI)
+		mockProjectSvc.AssertNotCalled(t, "GetProjectByID", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_LostTransitionPublishesNothing", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, mockPipeline, publisher := setup()
+		completed := *runningJob
+		completed.Status = core.JobStatusCompleted
+		mockJobRepo.On("GetJobByID", ctx, runningJob.ID).Return(runningJob, nil).Once()
+		mockPipeline.On("CheckStatus", ctx, pipelineID).Return(core.JobStatusCompleted, "", nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, runningJob.ID, core.JobStatusRunning, core.JobStatusCompleted, pipelineID, (*time.Time)(nil), mock.AnythingOfType("*time.Time"), "").
+			Return(fmt.Errorf("job changed: %w", core.ErrConflict)).Once()
+		mockJobRepo.On("GetJobByID", ctx, runningJob.ID).Return(&completed, nil).Once()
+
+		job, err := service.RefreshJobStatus(ctx, runningJob.ID)
+
+		require.NoError(err)
+		assert.Equal(core.JobStatusCompleted, job.Status)
+		assert.Empty(publisher.events, "the replica that won the transition publishes it")
+		mockPipeline.AssertNotCalled(t, "ResultURI", mock.Anything, mock.Anything)
+		mockProjectSvc.AssertNotCalled(t, "RefreshStorageUsage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_CancelLosesRaceToCompletion", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, mockPipeline, publisher := setup()
+		completed := *runningJob
+		completed.Status = core.JobStatusCompleted
+		mockJobRepo.On("GetJobByID", ctx, runningJob.ID).Return(runningJob, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		mockPipeline.On("Cancel", ctx, pipelineID).Return(nil).Once()
+		// The watcher recorded the completion after the job was read
+		mockJobRepo.On("TransitionJobStatus", ctx, runningJob.ID, core.JobStatusRunning, core.JobStatusCancelled, pipelineID, (*time.Time)(nil), mock.AnythingOfType("*time.Time"), mock.AnythingOfType("string")).
+			Return(fmt.Errorf("job changed: %w", core.ErrConflict)).Once()
+		mockJobRepo.On("GetJobByID", ctx, runningJob.ID).Return(&completed, nil).Once()
+
+		job, err := service.CancelJob(ctx, runningJob.ID, memberID)
+
+		require.NoError(err)
+		assert.Equal(core.JobStatusCompleted, job.Status, "a finished job is not overwritten as cancelled")
+		assert.Empty(publisher.events)
+		mockJobRepo.AssertNotCalled(t, "UpdateJobStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockJobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_ProjectArchivePublishesCancellations", func(t *testing.T) {
+		service, mockJobRepo, _, mockPipeline, publisher := setup()
+		mockJobRepo.On("ListJobsByProjectID", ctx, projectID, 100, 0).Return([]*core.Job{runningJob}, 1, nil).Once()
+		mockPipeline.On("Cancel", ctx, pipelineID).Return(nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, runningJob.ID, core.JobStatusRunning, core.JobStatusCancelled, pipelineID, (*time.Time)(nil), mock.AnythingOfType("*time.Time"), mock.AnythingOfType("string")).Return(nil).Once()
+
+		require.NoError(service.CancelProjectJobs(ctx, projectID))
+
+		require.Len(publisher.events, 1)
+		assert.Equal(core.JobStatusCancelled, publisher.events[0].Status)
+		assert.Equal(core.JobStatusRunning, runningJob.Status, "listed jobs are not modified")
+	})
+}
+
+func TestJobService_RetryJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-" + uuid.NewString()
+	memberID := "user-member-" + uuid.NewString()
+	viewerID := "user-viewer-" + uuid.NewString()
+	mockProject := &core.Project{ID: projectID, Status: core.ProjectStatusActive, TeamMembers: map[string]core.Role{memberID: core.RoleMember, viewerID: core.RoleViewer}}
+	policy := &core.JobRetryPolicy{MaxAttempts: 3, BackoffSeconds: 60}
+	failedJob := &core.Job{ID: "job-failed", ProjectID: projectID, UserID: viewerID, Status: core.JobStatusFailed, JobType: "csv", JobConfig: `{"rows":10}`, Attempt: 2, RetryPolicy: policy}
+
+	t.Run("Success_CopiesConfigAndLinksSource", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		mockJobRepo.On("GetJobByID", ctx, failedJob.ID).Return(failedJob, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		mockJobRepo.On("CreateJob", ctx, mock.AnythingOfType("*core.Job")).Return(nil).Once()
+
+		retry, err := service.RetryJob(ctx, failedJob.ID, memberID)
+
+		require.NoError(err)
+		assert.NotEqual(failedJob.ID, retry.ID)
+		assert.Equal(failedJob.ID, retry.RetryOf)
+		assert.Equal(3, retry.Attempt)
+		assert.Equal(memberID, retry.UserID, "the retry belongs to whoever asked for it")
+		assert.Equal(core.JobStatusPending, retry.Status)
+		assert.Equal(failedJob.JobType, retry.JobType)
+		assert.Equal(failedJob.JobConfig, retry.JobConfig)
+		assert.Equal(policy, retry.RetryPolicy)
+		assert.Nil(retry.RetryAt, "manual retries are submitted by the caller")
+		mockJobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_JobNotRetryable", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		completed := &core.Job{ID: "job-done", ProjectID: projectID, Status: core.JobStatusCompleted}
+		mockJobRepo.On("GetJobByID", ctx, completed.ID).Return(completed, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+
+		_, err := service.RetryJob(ctx, completed.ID, memberID)
+
+		assert.ErrorIs(err, ErrJobNotRetryable)
+		mockJobRepo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+	})
+
+	t.Run("PermissionDenied_ViewerCannotRetry", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		mockJobRepo.On("GetJobByID", ctx, failedJob.ID).Return(failedJob, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+
+		_, err := service.RetryJob(ctx, failedJob.ID, viewerID)
+
+		assert.ErrorIs(err, core.ErrForbidden)
+		mockJobRepo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_ProjectArchived", func(t *testing.T) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		archived := &core.Project{ID: projectID, Status: core.ProjectStatusArchived, TeamMembers: mockProject.TeamMembers}
+		mockJobRepo.On("GetJobByID", ctx, failedJob.ID).Return(failedJob, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(archived, nil).Once()
+
+		_, err := service.RetryJob(ctx, failedJob.ID, memberID)
+
+		assert.ErrorIs(err, core.ErrProjectArchived)
+	})
+
+	t.Run("JobNotFound", func(t *testing.T) {
+		service, mockJobRepo, _, _ := setupTestService()
+		mockJobRepo.On("GetJobByID", ctx, "missing").Return(nil, core.ErrNotFound).Once()
+
+		_, err := service.RetryJob(ctx, "missing", memberID)
+
+		assert.ErrorIs(err, core.ErrNotFound)
+	})
+}
+
+func TestJobService_CloneJob(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-" + uuid.NewString()
+	memberID := "user-member-" + uuid.NewString()
+	mockProject := &core.Project{ID: projectID, Status: core.ProjectStatusActive, TeamMembers: map[string]core.Role{memberID: core.RoleMember}}
+	source := &core.Job{ID: "job-source", ProjectID: projectID, Status: core.JobStatusCompleted, JobType: "csv", JobConfig: `{"rows":10,"seed":7}`, Attempt: 1}
+
+	setup := func() (JobService, *MockJobRepository) {
+		service, mockJobRepo, mockProjectSvc, _ := setupTestService()
+		mockJobRepo.On("GetJobByID", ctx, source.ID).Return(source, nil).Once()
+		mockProjectSvc.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		return service, mockJobRepo
+	}
+
+	t.Run("Success_AppliesOverrides", func(t *testing.T) {
+		service, mockJobRepo := setup()
+		mockJobRepo.On("CreateJob", ctx, mock.AnythingOfType("*core.Job")).Return(nil).Once()
+
+		clone, err := service.CloneJob(ctx, source.ID, memberID, CloneJobRequest{
+			ConfigOverrides: json.RawMessage(`{"rows":500,"seed":null}`),
+			RetryPolicy:     &core.JobRetryPolicy{MaxAttempts: 2},
+		})
+
+		require.NoError(err)
+		assert.Equal(source.ID, clone.ClonedFrom)
+		assert.Empty(clone.RetryOf)
+		assert.Equal(1, clone.Attempt)
+		assert.JSONEq(`{"rows":500}`, clone.JobConfig)
+		assert.Equal(2, clone.RetryPolicy.MaxAttempts)
+		mockJobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_ReplacesTypeAndConfig", func(t *testing.T) {
+		service, mockJobRepo := setup()
+		mockJobRepo.On("CreateJob", ctx, mock.AnythingOfType("*core.Job")).Return(nil).Once()
+
+		clone, err := service.CloneJob(ctx, source.ID, memberID, CloneJobRequest{JobType: "parquet", JobConfig: `{"rows":1}`})
+
+		require.NoError(err)
+		assert.Equal("parquet", clone.JobType)
+		assert.Equal(`{"rows":1}`, clone.JobConfig)
+	})
+
+	t.Run("Failure_ConfigAndOverrides", func(t *testing.T) {
+		service, mockJobRepo := setup()
+
+		_, err := service.CloneJob(ctx, source.ID, memberID, CloneJobRequest{JobConfig: `{}`, ConfigOverrides: json.RawMessage(`{}`)})
+
+		assert.ErrorIs(err, ErrInvalidJobConfig)
+		mockJobRepo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_InvalidRetryPolicy", func(t *testing.T) {
+		service, mockJobRepo := setup()
+
+		_, err := service.CloneJob(ctx, source.ID, memberID, CloneJobRequest{RetryPolicy: &core.JobRetryPolicy{MaxAttempts: 0}})
+
+		assert.ErrorIs(err, ErrInvalidJobConfig)
+		mockJobRepo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+	})
+}
+
+func TestJobService_AutomaticRetry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	pipelineID := "pipe-" + uuid.NewString()
+	newRunningJob := func(policy *core.JobRetryPolicy, attempt int) *core.Job {
+		return &core.Job{ID: "job-running", ProjectID: "proj-1", UserID: "user-1", Status: core.JobStatusRunning, PipelineJobID: pipelineID, JobConfig: `{}`, Attempt: attempt, RetryPolicy: policy}
+	}
+	failWith := func(mockJobRepo *MockJobRepository, mockPipeline *MockPipelineClient, job *core.Job, message string) {
+		mockJobRepo.On("GetJobByID", ctx, job.ID).Return(job, nil).Once()
+		mockPipeline.On("CheckStatus", ctx, pipelineID).Return(core.JobStatusFailed, message, nil).Once()
+		mockJobRepo.On("TransitionJobStatus", ctx, job.ID, core.JobStatusRunning, core.JobStatusFailed, pipelineID, (*time.Time)(nil), mock.AnythingOfType("*time.Time"), message).Return(nil).Once()
+	}
+
+	t.Run("Success_SchedulesRetryForCoveredFailure", func(t *testing.T) {
+		service, mockJobRepo, _, mockPipeline := setupTestService()
+		job := newRunningJob(&core.JobRetryPolicy{MaxAttempts: 3, BackoffSeconds: 30}, 2)
+		failWith(mockJobRepo, mockPipeline, job, "worker preempted")
+		var scheduled *core.Job
+		mockJobRepo.On("CreateJob", ctx, mock.AnythingOfType("*core.Job")).Run(func(args mock.Arguments) {
+			scheduled = args.Get(1).(*core.Job)
+		}).Return(nil).Once()
+
+		_, err := service.RefreshJobStatus(ctx, job.ID)
+
+		require.NoError(err)
+		require.NotNil(scheduled)
+		assert.Equal(job.ID, scheduled.RetryOf)
+		assert.Equal(3, scheduled.Attempt)
+		assert.Equal("user-1", scheduled.UserID)
+		require.NotNil(scheduled.RetryAt)
+		assert.WithinDuration(time.Now().Add(60*time.Second), *scheduled.RetryAt, 5*time.Second, "backoff doubles per attempt")
+
+		// The ID is derived from the failed job, so replicas agree on it
+		again, mockJobRepo2, _, mockPipeline2 := setupTestService()
+		failWith(mockJobRepo2, mockPipeline2, newRunningJob(job.RetryPolicy, 2), "worker preempted")
+		mockJobRepo2.On("CreateJob", ctx, mock.MatchedBy(func(j *core.Job) bool { return j.ID == scheduled.ID })).
+			Return(fmt.Errorf("job %s: %w", scheduled.ID, core.ErrConflict)).Once()
+		_, err = again.RefreshJobStatus(ctx, job.ID)
+		require.NoError(err, "an already scheduled retry is not an error")
+		mockJobRepo2.AssertExpectations(t)
+	})
+
+	for name, job := range map[string]*core.Job{
+		"NoPolicy":          newRunningJob(nil, 1),
+		"AttemptsExhausted": newRunningJob(&core.JobRetryPolicy{MaxAttempts: 2}, 2),
+		"ClassNotCovered":   newRunningJob(&core.JobRetryPolicy{MaxAttempts: 3, RetryOn: []string{ErrorClassResource}}, 1),
+	} {
+		t.Run("Success_NoRetry_"+name, func(t *testing.T) {
+			service, mockJobRepo, _, mockPipeline := setupTestService()
+			failWith(mockJobRepo, mockPipeline, job, "connection reset by peer")
+
+			_, err := service.RefreshJobStatus(ctx, job.ID)
+
+			require.NoError(err)
+			mockJobRepo.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package job
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/lease"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultWatchInterval is how often the StatusWatcher syncs running jobs when no interval
+// is configured.
+const DefaultWatchInterval = 10 * time.Second
+
+const (
+	watchLeaseName = "job-status-watcher" // Lease held by the replica that syncs jobs
+	watchPageSize  = 100                  // Page size used when listing running jobs
+)
+
+// StatusWatcherConfig holds configuration for the StatusWatcher.
+type StatusWatcherConfig struct {
+	Interval time.Duration // Time between ticks; defaults to DefaultWatchInterval
+	HolderID string        // Identifies this replica in the lease; required
+}
+
+// StatusWatcher keeps running jobs in step with the pipeline, so that status, progress and
+// result events are published without any client polling for them. It also submits
+// automatic retries once they are due. Only the replica holding the watcher lease syncs;
+// status changes are compare-and-set, so a request racing the watcher publishes each
+// change once.
+type StatusWatcher struct {
+	projectRepo core.ProjectRepository
+	jobRepo     core.JobRepository
+	service     JobService
+	runner      *lease.Runner
+}
+
+// NewStatusWatcher creates a new StatusWatcher.
+func NewStatusWatcher(projectRepo core.ProjectRepository, jobRepo core.JobRepository, leaseRepo core.LeaseRepository, service JobService, cfg StatusWatcherConfig) *StatusWatcher {
+	if projectRepo == nil || jobRepo == nil || leaseRepo == nil || service == nil {
+		panic("job.NewStatusWatcher: all dependencies are required")
+	}
+	if cfg.HolderID == "" {
+		panic("job.NewStatusWatcher: HolderID is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultWatchInterval
+	}
+	return &StatusWatcher{
+		projectRepo: projectRepo,
+		jobRepo:     jobRepo,
+		service:     service,
+		runner:      lease.NewRunner(leaseRepo, watchLeaseName, cfg.HolderID, cfg.Interval),
+	}
+}
+
+// Run syncs running jobs immediately and then once per interval until ctx is cancelled.
+func (w *StatusWatcher) Run(ctx context.Context) {
+	w.runner.Run(ctx, func(ctx context.Context) error {
+		_, err := w.sync(ctx)
+		return err
+	})
+}
+
+// SyncOnce syncs running jobs and submits due retries, if this replica holds the lease,
+// and returns how many jobs were handled.
+func (w *StatusWatcher) SyncOnce(ctx context.Context) (int, error) {
+	var handled int
+	_, err := w.runner.TickOnce(ctx, func(ctx context.Context) (err error) {
+		handled, err = w.sync(ctx)
+		return err
+	})
+	return handled, err
+}
+
+// sync syncs every running job of an active project with the pipeline and submits the
+// automatic retries that are due, returning how many jobs were handled. Failures for
+// individual jobs are logged and skipped.
+func (w *StatusWatcher) sync(ctx context.Context) (int, error) {
+	// 1. Running jobs only exist in active projects; archiving cancels them
+	projectIDs, err := unarchivedProjectIDs(ctx, w.projectRepo)
+	if err != nil {
+		return 0, err
+	}
+
+	// 2. Collect the jobs before acting on them, as that moves them out of the listings
+	running, err := listJobsByStatus(ctx, w.jobRepo, projectIDs, core.JobStatusRunning)
+	if err != nil {
+		return 0, err
+	}
+	pending, err := listJobsByStatus(ctx, w.jobRepo, projectIDs, core.JobStatusPending)
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().UTC()
+	var due []*core.Job
+	for _, job := range pending {
+		if job.RetryAt != nil && !job.RetryAt.After(now) {
+			due = append(due, job)
+		}
+	}
+
+	// 3. Sync each running job, continuing past individual failures
+	var failed int
+	for _, job := range running {
+		if ctx.Err() != nil {
+			return len(running) - failed, ctx.Err()
+		}
+		if _, err := w.service.RefreshJobStatus(ctx, job.ID); err != nil {
+			logger.Logger.Debug("Failed to refresh job status", zap.String("jobID", job.ID), zap.Error(err))
+			failed++
+		}
+	}
+
+	// 4. Submit the due retries on behalf of the user who ran the failed job
+	var failedRetries int
+	for _, job := range due {
+		if ctx.Err() != nil {
+			return len(running) + len(due) - failed - failedRetries, ctx.Err()
+		}
+		submitted, err := w.service.SubmitJob(ctx, job.ID, job.UserID)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrPipelineUnavailable) || errors.Is(err, ErrJobLimitExceeded):
+			// The retry stays pending and is submitted again on a later tick
+			logger.Logger.Warn("Failed to submit automatic job retry", zap.String("jobID", job.ID), zap.Error(err))
+			failedRetries++
+		case submitted != nil && submitted.Status == core.JobStatusFailed:
+			// The pipeline rejected the retry and SubmitJob already failed it
+		default:
+			// Submitting again cannot succeed; the retry would otherwise be resubmitted every tick
+			reason := fmt.Sprintf("Automatic retry could not be submitted: %v", err)
+			if _, failErr := w.service.FailDueRetry(ctx, job.ID, reason); failErr != nil {
+				logger.Logger.Warn("Failed to fail unsubmittable job retry", zap.String("jobID", job.ID), zap.Error(failErr))
+				failedRetries++
+			}
+		}
+	}
+
+	handled := len(running) + len(due) - failed - failedRetries
+	var errs []error
+	if failed > 0 {
+		errs = append(errs, fmt.Errorf("failed to sync %d of %d running jobs", failed, len(running)))
+	}
+	if failedRetries > 0 {
+		errs = append(errs, fmt.Errorf("failed to submit %d of %d due retries", failedRetries, len(due)))
+	}
+	return handled, errors.Join(errs...)
+}
+
+// unarchivedProjectIDs returns the IDs of every project that is not archived.
+func unarchivedProjectIDs(ctx context.Context, projectRepo core.ProjectRepository) ([]string, error) {
+	projects, err := projectRepo.ListAllProjects(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	projectIDs := make([]string, 0, len(projects))
+	for _, proj := range projects {
+		if proj.Status != core.ProjectStatusArchived {
+			projectIDs = append(projectIDs, proj.ID)
+		}
+	}
+	return projectIDs, nil
+}
+
+// listJobsByStatus returns every job with the given status in the given projects. Jobs are
+// read with one status query per page, resuming after the last job of the previous page.
+func listJobsByStatus(ctx context.Context, jobRepo core.JobRepository, projectIDs []string, status core.JobStatus) ([]*core.Job, error) {
+	inProjects := make(map[string]bool, len(projectIDs))
+	for _, id := range projectIDs {
+		inProjects[id] = true
+	}
+
+	var all []*core.Job
+	startAfter := ""
+	for {
+		jobs, err := jobRepo.ListJobsByStatus(ctx, status, watchPageSize, startAfter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s jobs: %w", status, err)
+		}
+		for _, job := range jobs {
+			if inProjects[job.ProjectID] {
+				all = append(all, job)
+			}
+		}
+		if len(jobs) < watchPageSize {
+			return all, nil
+		}
+		startAfter = jobs[len(jobs)-1].ID
+	}
+}
@@ -0,0 +1,168 @@
+package job
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockProjectRepository mocks listing every project.
+type MockProjectRepository struct {
+	mock.Mock
+	core.ProjectRepository
+}
+
+func (m *MockProjectRepository) ListAllProjects(ctx context.Context) ([]*core.Project, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Project), args.Error(1)
+}
+
+// newTestWatcher creates a StatusWatcher whose replica always holds the lease.
+func newTestWatcher(projectRepo core.ProjectRepository, jobRepo core.JobRepository, service JobService) *StatusWatcher {
+	leaseRepo := new(MockLeaseRepository)
+	leaseRepo.On("AcquireLease", mock.Anything, watchLeaseName, "replica-1", 2*DefaultWatchInterval).Return(true, nil)
+	return NewStatusWatcher(projectRepo, jobRepo, leaseRepo, service, StatusWatcherConfig{HolderID: "replica-1"})
+}
+
+func TestStatusWatcher_SyncOnce(t *testing.T) {
+	ctx := context.Background()
+	projects := []*core.Project{
+		{ID: "proj-active", Status: core.ProjectStatusActive},
+		{ID: "proj-archived", Status: core.ProjectStatusArchived},
+	}
+
+	t.Run("Success_SyncsRunningJobsOfActiveProjects", func(t *testing.T) {
+		projectRepo := new(MockProjectRepository)
+		jobRepo := new(MockJobRepository)
+		service := new(MockJobService)
+		watcher := newTestWatcher(projectRepo, jobRepo, service)
+
+		firstPage := make([]*core.Job, watchPageSize)
+		for i := range firstPage {
+			firstPage[i] = &core.Job{ID: "job-a", ProjectID: "proj-active"}
+		}
+		firstPage[watchPageSize-1] = &core.Job{ID: "job-archived", ProjectID: "proj-archived"}
+		projectRepo.On("ListAllProjects", ctx).Return(projects, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusRunning, watchPageSize, "").Return(firstPage, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusRunning, watchPageSize, "job-archived").Return([]*core.Job{{ID: "job-b", ProjectID: "proj-active"}}, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusPending, watchPageSize, "").Return([]*core.Job{}, nil).Once()
+		service.On("RefreshJobStatus", ctx, "job-a").Return(&core.Job{}, nil).Times(watchPageSize - 1)
+		service.On("RefreshJobStatus", ctx, "job-b").Return(&core.Job{}, nil).Once()
+
+		synced, err := watcher.SyncOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, watchPageSize, synced)
+		service.AssertNotCalled(t, "RefreshJobStatus", ctx, "job-archived")
+		jobRepo.AssertExpectations(t)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("Failure_IndividualSyncErrorsAreCounted", func(t *testing.T) {
+		projectRepo := new(MockProjectRepository)
+		jobRepo := new(MockJobRepository)
+		service := new(MockJobService)
+		watcher := newTestWatcher(projectRepo, jobRepo, service)
+
+		projectRepo.On("ListAllProjects", ctx).Return(projects, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusRunning, watchPageSize, "").
+			Return([]*core.Job{{ID: "job-a", ProjectID: "proj-active"}, {ID: "job-b", ProjectID: "proj-active"}}, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusPending, watchPageSize, "").Return([]*core.Job{}, nil).Once()
+		service.On("RefreshJobStatus", ctx, "job-a").Return(nil, errors.New("pipeline down")).Once()
+		service.On("RefreshJobStatus", ctx, "job-b").Return(&core.Job{}, nil).Once()
+
+		synced, err := watcher.SyncOnce(ctx)
+
+		assert.ErrorContains(t, err, "failed to sync 1 of 2 running jobs")
+		assert.Equal(t, 1, synced)
+		service.AssertExpectations(t)
+	})
+
+	t.Run("Success_SubmitsDueRetries", func(t *testing.T) {
+		projectRepo := new(MockProjectRepository)
+		jobRepo := new(MockJobRepository)
+		service := new(MockJobService)
+		watcher := newTestWatcher(projectRepo, jobRepo, service)
+
+		past, future := time.Now().Add(-time.Minute), time.Now().Add(time.Hour)
+		pending := []*core.Job{
+			{ID: "retry-due", ProjectID: "proj-active", UserID: "user-1", RetryAt: &past},
+			{ID: "retry-later", ProjectID: "proj-active", UserID: "user-1", RetryAt: &future},
+			{ID: "manual", ProjectID: "proj-active", UserID: "user-1"}, // Waiting for its creator to submit it
+		}
+		projectRepo.On("ListAllProjects", ctx).Return(projects, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusRunning, watchPageSize, "").Return([]*core.Job{}, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusPending, watchPageSize, "").Return(pending, nil).Once()
+		service.On("SubmitJob", ctx, "retry-due", "user-1").Return(&core.Job{}, nil).Once()
+
+		handled, err := watcher.SyncOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		service.AssertExpectations(t)
+		service.AssertNotCalled(t, "SubmitJob", ctx, "retry-later", mock.Anything)
+	})
+
+	t.Run("Success_FailsRetriesThatCannotBeSubmitted", func(t *testing.T) {
+		projectRepo := new(MockProjectRepository)
+		jobRepo := new(MockJobRepository)
+		service := new(MockJobService)
+		watcher := newTestWatcher(projectRepo, jobRepo, service)
+
+		past := time.Now().Add(-time.Minute)
+		pending := []*core.Job{
+			{ID: "retry-outage", ProjectID: "proj-active", UserID: "user-1", RetryAt: &past},
+			{ID: "retry-forbidden", ProjectID: "proj-active", UserID: "removed", RetryAt: &past},
+		}
+		projectRepo.On("ListAllProjects", ctx).Return(projects, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusRunning, watchPageSize, "").Return([]*core.Job{}, nil).Once()
+		jobRepo.On("ListJobsByStatus", ctx, core.JobStatusPending, watchPageSize, "").Return(pending, nil).Once()
+		service.On("SubmitJob", ctx, "retry-outage", "user-1").Return(nil, fmt.Errorf("pipeline submission failed: %w", ErrPipelineUnavailable)).Once()
+		service.On("SubmitJob", ctx, "retry-forbidden", "removed").Return(nil, fmt.Errorf("%w: insufficient role", core.ErrForbidden)).Once()
+		service.On("FailDueRetry", ctx, "retry-forbidden", mock.MatchedBy(func(reason string) bool {
+			return strings.Contains(reason, "insufficient role")
+		})).Return(&core.Job{ID: "retry-forbidden", Status: core.JobStatusFailed}, nil).Once()
+
+		handled, err := watcher.SyncOnce(ctx)
+
+		assert.ErrorContains(t, err, "failed to submit 1 of 2 due retries")
+		assert.Equal(t, 1, handled, "the forbidden retry is failed; the outage one is retried later")
+		service.AssertExpectations(t)
+		service.AssertNotCalled(t, "FailDueRetry", ctx, "retry-outage", mock.Anything)
+	})
+
+	t.Run("Skip_NotLeaseHolder", func(t *testing.T) {
+		projectRepo := new(MockProjectRepository)
+		leaseRepo := new(MockLeaseRepository)
+		leaseRepo.On("AcquireLease", ctx, watchLeaseName, "replica-2", 2*time.Minute).Return(false, nil).Once()
+		watcher := NewStatusWatcher(projectRepo, new(MockJobRepository), leaseRepo, new(MockJobService), StatusWatcherConfig{Interval: time.Minute, HolderID: "replica-2"})
+
+		handled, err := watcher.SyncOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, handled)
+		projectRepo.AssertNotCalled(t, "ListAllProjects", mock.Anything)
+		leaseRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_ListProjectsError", func(t *testing.T) {
+		projectRepo := new(MockProjectRepository)
+		watcher := newTestWatcher(projectRepo, new(MockJobRepository), new(MockJobService))
+		projectRepo.On("ListAllProjects", ctx).Return(nil, errors.New("db down")).Once()
+
+		_, err := watcher.SyncOnce(ctx)
+
+		assert.ErrorContains(t, err, "failed to list projects")
+	})
+}
@@ -0,0 +1,251 @@
+package jobtemplate
+
+import (
+	"SynDataGen/backend/internal/access"
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"SynDataGen/backend/internal/platform/logger"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// TemplateRequest defines the JSON body for creating a template or saving a new version.
+type TemplateRequest struct {
+	Name        string                  `json:"name" binding:"required"`
+	Description string                  `json:"description"`
+	JobType     string                  `json:"jobType" binding:"required"`
+	JobConfig   string                  `json:"jobConfig" binding:"required"` // JSON object with {{name}} placeholders
+	Variables   []core.TemplateVariable `json:"variables"`
+	BaseVersion int                     `json:"baseVersion,omitempty"` // Updates only: reject if the template has moved on
+}
+
+// CreateJobFromTemplateRequest defines the JSON body for creating a job from a template.
+type CreateJobFromTemplateRequest struct {
+	Version     int                    `json:"version,omitempty"`   // Defaults to the current version
+	Variables   map[string]interface{} `json:"variables,omitempty"` // Values by variable name
+	Submit      bool                   `json:"submit,omitempty"`    // Submit the job to the pipeline once created
+	RetryPolicy *core.JobRetryPolicy   `json:"retryPolicy,omitempty"`
+
+	ScheduleID string `json:"-"` // Set by the scheduler; never bound from requests
+}
+
+// CreateJobFromTemplateResponse is the result of creating a job from a template.
+type CreateJobFromTemplateResponse struct {
+	Job         *core.Job `json:"job"`
+	Submitted   bool      `json:"submitted"`
+	SubmitError string    `json:"submitError,omitempty"` // Why submission failed; the job was still created
+}
+
+// TemplateHandler handles HTTP requests for job templates.
+type TemplateHandler struct {
+	service TemplateService
+}
+
+// NewTemplateHandler creates a new TemplateHandler.
+func NewTemplateHandler(s TemplateService) *TemplateHandler {
+	return &TemplateHandler{service: s}
+}
+
+// RegisterRoutes registers job template routes with the Gin router group.
+func (h *TemplateHandler) RegisterRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	templates := rg.Group("/projects/:projectId/job-templates")
+	templates.Use(authMiddleware)
+	{
+		templates.POST("", h.CreateTemplate)                                  // POST /api/v1/projects/:projectId/job-templates
+		templates.GET("", h.ListTemplates)                                    // GET /api/v1/projects/:projectId/job-templates
+		templates.GET("/:templateId", h.GetTemplate)                          // GET /api/v1/projects/:projectId/job-templates/:templateId
+		templates.PUT("/:templateId", h.UpdateTemplate)                       // PUT /api/v1/projects/:projectId/job-templates/:templateId
+		templates.DELETE("/:templateId", h.DeleteTemplate)                    // DELETE /api/v1/projects/:projectId/job-templates/:templateId
+		templates.GET("/:templateId/versions/:version", h.GetTemplateVersion) // GET /api/v1/projects/:projectId/job-templates/:templateId/versions/:version
+	}
+
+	fromTemplate := rg.Group("/projects/:projectId/jobs/from-template")
+	fromTemplate.Use(authMiddleware)
+	{
+		fromTemplate.POST("/:templateId", h.CreateJobFromTemplate) // POST /api/v1/projects/:projectId/jobs/from-template/:templateId
+	}
+}
+
+// errorCodes maps this package's errors to responses.
+var errorCodes = []access.ErrorCode{
+	{Err: ErrTemplateVersionConflict, Status: http.StatusConflict, Code: "TEMPLATE_VERSION_CONFLICT"},
+	{Err: ErrInvalidTemplate, Status: http.StatusBadRequest, Code: "INVALID_TEMPLATE"},
+	{Err: ErrInvalidVariables, Status: http.StatusBadRequest, Code: "INVALID_TEMPLATE_VARIABLES"},
+	{Err: job.ErrInvalidJobConfig, Status: http.StatusBadRequest, Code: "INVALID_JOB_CONFIG"},
+}
+
+// abortWithError maps service errors to responses.
+func abortWithError(c *gin.Context, err error, fallback string) {
+	if job.AbortWithLimitError(c, err) {
+		return
+	}
+	access.AbortWithError(c, err, "Template or project not found", fallback, errorCodes...)
+}
+
+// CreateTemplate handles POST /projects/:projectId/job-templates requests.
+func (h *TemplateHandler) CreateTemplate(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "CreateTemplate")
+	if !ok {
+		return
+	}
+
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Logger.Warn("Invalid request body for CreateTemplate", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	template, err := h.service.CreateTemplate(c.Request.Context(), projectID, userID, req)
+	if err != nil {
+		logger.Logger.Error("Failed to create template via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to create template")
+		return
+	}
+	c.JSON(http.StatusCreated, template)
+}
+
+// ListTemplates handles GET /projects/:projectId/job-templates requests.
+func (h *TemplateHandler) ListTemplates(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "ListTemplates")
+	if !ok {
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "20")
+	offsetStr := c.DefaultQuery("offset", "0")
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid 'limit' query parameter"})
+		return
+	}
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid 'offset' query parameter"})
+		return
+	}
+
+	templates, total, err := h.service.ListTemplates(c.Request.Context(), projectID, userID, limit, offset)
+	if err != nil {
+		logger.Logger.Error("Failed to list templates via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to list templates")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"templates": templates,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// GetTemplate handles GET /projects/:projectId/job-templates/:templateId requests.
+func (h *TemplateHandler) GetTemplate(c *gin.Context) {
+	projectID, templateID := c.Param("projectId"), c.Param("templateId")
+	userID, ok := access.RequireUserID(c, "GetTemplate")
+	if !ok {
+		return
+	}
+
+	template, err := h.service.GetTemplate(c.Request.Context(), projectID, templateID, userID)
+	if err != nil {
+		logger.Logger.Error("Failed to get template via service", zap.Error(err), zap.String("userId", userID), zap.String("templateId", templateID))
+		abortWithError(c, err, "Failed to retrieve template")
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// GetTemplateVersion handles GET /projects/:projectId/job-templates/:templateId/versions/:version requests.
+func (h *TemplateHandler) GetTemplateVersion(c *gin.Context) {
+	projectID, templateID := c.Param("projectId"), c.Param("templateId")
+	userID, ok := access.RequireUserID(c, "GetTemplateVersion")
+	if !ok {
+		return
+	}
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid template version"})
+		return
+	}
+
+	template, err := h.service.GetTemplateVersion(c.Request.Context(), projectID, templateID, version, userID)
+	if err != nil {
+		logger.Logger.Error("Failed to get template version via service", zap.Error(err), zap.String("userId", userID), zap.String("templateId", templateID), zap.Int("version", version))
+		abortWithError(c, err, "Failed to retrieve template version")
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdateTemplate handles PUT /projects/:projectId/job-templates/:templateId requests.
+func (h *TemplateHandler) UpdateTemplate(c *gin.Context) {
+	projectID, templateID := c.Param("projectId"), c.Param("templateId")
+	userID, ok := access.RequireUserID(c, "UpdateTemplate")
+	if !ok {
+		return
+	}
+
+	var req TemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Logger.Warn("Invalid request body for UpdateTemplate", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	template, err := h.service.UpdateTemplate(c.Request.Context(), projectID, templateID, userID, req)
+	if err != nil {
+		logger.Logger.Error("Failed to update template via service", zap.Error(err), zap.String("userId", userID), zap.String("templateId", templateID))
+		abortWithError(c, err, "Failed to update template")
+		return
+	}
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate handles DELETE /projects/:projectId/job-templates/:templateId requests.
+func (h *TemplateHandler) DeleteTemplate(c *gin.Context) {
+	projectID, templateID := c.Param("projectId"), c.Param("templateId")
+	userID, ok := access.RequireUserID(c, "DeleteTemplate")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTemplate(c.Request.Context(), projectID, templateID, userID); err != nil {
+		logger.Logger.Error("Failed to delete template via service", zap.Error(err), zap.String("userId", userID), zap.String("templateId", templateID))
+		abortWithError(c, err, "Failed to delete template")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// CreateJobFromTemplate handles POST /projects/:projectId/jobs/from-template/:templateId requests.
+// The body is optional when every variable has a default.
+func (h *TemplateHandler) CreateJobFromTemplate(c *gin.Context) {
+	projectID, templateID := c.Param("projectId"), c.Param("templateId")
+	userID, ok := access.RequireUserID(c, "CreateJobFromTemplate")
+	if !ok {
+		return
+	}
+
+	var req CreateJobFromTemplateRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			logger.Logger.Warn("Invalid request body for CreateJobFromTemplate", zap.Error(err))
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	resp, err := h.service.CreateJobFromTemplate(c.Request.Context(), projectID, templateID, userID, req)
+	if err != nil {
+		logger.Logger.Error("Failed to create job from template via service", zap.Error(err), zap.String("userId", userID), zap.String("templateId", templateID))
+		abortWithError(c, err, "Failed to create job from template")
+		return
+	}
+	c.JSON(http.StatusCreated, resp)
+}
@@ -0,0 +1,267 @@
+package jobtemplate
+
+import (
+	"SynDataGen/backend/internal/auth"
+	"SynDataGen/backend/internal/core"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockTemplateService is a mock implementation of TemplateService.
+type MockTemplateService struct {
+	mock.Mock
+}
+
+func (m *MockTemplateService) CreateTemplate(ctx context.Context, projectID, userID string, req TemplateRequest) (*core.JobTemplate, error) {
+	args := m.Called(ctx, projectID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.JobTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) GetTemplate(ctx context.Context, projectID, templateID, userID string) (*core.JobTemplate, error) {
+	args := m.Called(ctx, projectID, templateID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.JobTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) GetTemplateVersion(ctx context.Context, projectID, templateID string, version int, userID string) (*core.JobTemplate, error) {
+	args := m.Called(ctx, projectID, templateID, version, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.JobTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) ListTemplates(ctx context.Context, projectID, userID string, limit, offset int) ([]*core.JobTemplate, int, error) {
+	args := m.Called(ctx, projectID, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.JobTemplate), args.Int(1), args.Error(2)
+}
+
+func (m *MockTemplateService) UpdateTemplate(ctx context.Context, projectID, templateID, userID string, req TemplateRequest) (*core.JobTemplate, error) {
+	args := m.Called(ctx, projectID, templateID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.JobTemplate), args.Error(1)
+}
+
+func (m *MockTemplateService) DeleteTemplate(ctx context.Context, projectID, templateID, userID string) error {
+	args := m.Called(ctx, projectID, templateID, userID)
+	return args.Error(0)
+}
+
+func (m *MockTemplateService) CreateJobFromTemplate(ctx context.Context, projectID, templateID, userID string, req CreateJobFromTemplateRequest) (*CreateJobFromTemplateResponse, error) {
+	args := m.Called(ctx, projectID, templateID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*CreateJobFromTemplateResponse), args.Error(1)
+}
+
+const (
+	testProjectID = "proj-1"
+	testUserID    = "user-1"
+)
+
+func setupGinTestRouter() (*gin.Engine, *MockTemplateService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockService := new(MockTemplateService)
+	mockAuthMiddleware := func(c *gin.Context) {
+		if userID := c.GetHeader("X-User-ID"); userID != "" {
+			c.Set(auth.UserIDKey, userID)
+		}
+		c.Next()
+	}
+	NewTemplateHandler(mockService).RegisterRoutes(router.Group("/"), mockAuthMiddleware)
+	return router, mockService
+}
+
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body == "" {
+		req, _ = http.NewRequest(method, path, nil)
+	} else {
+		req, _ = http.NewRequest(method, path, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-User-ID", testUserID)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestTemplateHandler_CreateTemplate(t *testing.T) {
+	path := "/projects/" + testProjectID + "/job-templates"
+
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		expected := TemplateRequest{
+			Name:      "Customers",
+			JobType:   "csv",
+			JobConfig: `{"rows":"{{rows}}"}`,
+			Variables: []core.TemplateVariable{{Name: "rows", Type: "integer"}},
+		}
+		mockService.On("CreateTemplate", mock.Anything, testProjectID, testUserID, expected).Return(&core.JobTemplate{ID: "tmpl-1", Version: 1}, nil).Once()
+
+		w := serve(router, http.MethodPost, path, `{"name":"Customers","jobType":"csv","jobConfig":"{\"rows\":\"{{rows}}\"}","variables":[{"name":"rows","type":"integer"}]}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"version":1`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure_MissingFields", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+
+		w := serve(router, http.MethodPost, path, `{"name":"Customers"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "CreateTemplate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_InvalidTemplate", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("CreateTemplate", mock.Anything, testProjectID, testUserID, mock.Anything).
+			Return(nil, fmt.Errorf("%w: placeholder {{count}} is not a declared variable", ErrInvalidTemplate)).Once()
+
+		w := serve(router, http.MethodPost, path, `{"name":"x","jobType":"csv","jobConfig":"{\"rows\":\"{{count}}\"}"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_TEMPLATE")
+	})
+}
+
+func TestTemplateHandler_Templates(t *testing.T) {
+	base := "/projects/" + testProjectID + "/job-templates/tmpl-1"
+
+	t.Run("Success_List", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("ListTemplates", mock.Anything, testProjectID, testUserID, 5, 10).Return([]*core.JobTemplate{{ID: "tmpl-1"}}, 11, nil).Once()
+
+		w := serve(router, http.MethodGet, "/projects/"+testProjectID+"/job-templates?limit=5&offset=10", "")
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var body struct {
+			Templates []core.JobTemplate `json:"templates"`
+			Total     int                `json:"total"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Len(t, body.Templates, 1)
+		assert.Equal(t, 11, body.Total)
+	})
+
+	t.Run("Success_GetVersion", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("GetTemplateVersion", mock.Anything, testProjectID, "tmpl-1", 2, testUserID).Return(&core.JobTemplate{ID: "tmpl-1", Version: 2}, nil).Once()
+
+		w := serve(router, http.MethodGet, base+"/versions/2", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure_InvalidVersion", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+
+		w := serve(router, http.MethodGet, base+"/versions/latest", "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "GetTemplateVersion", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_UpdateConflict", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("UpdateTemplate", mock.Anything, testProjectID, "tmpl-1", testUserID, mock.Anything).
+			Return(nil, fmt.Errorf("%w: based on version 1, current version is 2", ErrTemplateVersionConflict)).Once()
+
+		w := serve(router, http.MethodPut, base, `{"name":"x","jobType":"csv","jobConfig":"{}","baseVersion":1}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "TEMPLATE_VERSION_CONFLICT")
+	})
+
+	t.Run("Success_Delete", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("DeleteTemplate", mock.Anything, testProjectID, "tmpl-1", testUserID).Return(nil).Once()
+
+		w := serve(router, http.MethodDelete, base, "")
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Failure_NotFound", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("GetTemplate", mock.Anything, testProjectID, "tmpl-1", testUserID).Return(nil, core.ErrNotFound).Once()
+
+		w := serve(router, http.MethodGet, base, "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestTemplateHandler_CreateJobFromTemplate(t *testing.T) {
+	path := "/projects/" + testProjectID + "/jobs/from-template/tmpl-1"
+
+	t.Run("Success_WithVariables", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		expected := CreateJobFromTemplateRequest{Variables: map[string]interface{}{"rows": 500.0}, Submit: true}
+		resp := &CreateJobFromTemplateResponse{Job: &core.Job{ID: "job-1", TemplateID: "tmpl-1", TemplateVersion: 2}, Submitted: true}
+		mockService.On("CreateJobFromTemplate", mock.Anything, testProjectID, "tmpl-1", testUserID, expected).Return(resp, nil).Once()
+
+		w := serve(router, http.MethodPost, path, `{"variables":{"rows":500},"submit":true}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Contains(t, w.Body.String(), `"templateVersion":2`)
+		assert.Contains(t, w.Body.String(), `"submitted":true`)
+	})
+
+	t.Run("Success_WithoutBody", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("CreateJobFromTemplate", mock.Anything, testProjectID, "tmpl-1", testUserID, CreateJobFromTemplateRequest{}).
+			Return(&CreateJobFromTemplateResponse{Job: &core.Job{ID: "job-1"}}, nil).Once()
+
+		w := serve(router, http.MethodPost, path, "")
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Failure_InvalidVariables", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("CreateJobFromTemplate", mock.Anything, testProjectID, "tmpl-1", testUserID, mock.Anything).
+			Return(nil, fmt.Errorf("%w: missing values for rows", ErrInvalidVariables)).Once()
+
+		w := serve(router, http.MethodPost, path, `{"variables":{}}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_TEMPLATE_VARIABLES")
+	})
+
+	t.Run("Failure_ProjectArchived", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("CreateJobFromTemplate", mock.Anything, testProjectID, "tmpl-1", testUserID, mock.Anything).
+			Return(nil, fmt.Errorf("%w: cannot create jobs", core.ErrProjectArchived)).Once()
+
+		w := serve(router, http.MethodPost, path, "")
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "PROJECT_ARCHIVED")
+	})
+}
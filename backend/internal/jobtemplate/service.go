@@ -0,0 +1,292 @@
+package jobtemplate
+
+import (
+	"SynDataGen/backend/internal/access"
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Template errors
+var (
+	ErrInvalidTemplate         = errors.New("invalid job template")
+	ErrInvalidVariables        = errors.New("invalid template variables")
+	ErrTemplateVersionConflict = errors.New("job template was changed by someone else")
+)
+
+// JobCreator creates and submits jobs. job.JobService satisfies this interface.
+type JobCreator interface {
+	CreateJob(ctx context.Context, projectID, userID string, req job.CreateJobRequest) (*core.Job, error)
+	SubmitJob(ctx context.Context, jobID, userID string) (*core.Job, error)
+}
+
+// --- Service Interface ---
+
+// TemplateService defines the interface for job template business logic.
+type TemplateService interface {
+	// CreateTemplate creates version 1 of a new template, requiring Member role.
+	CreateTemplate(ctx context.Context, projectID, userID string, req TemplateRequest) (*core.JobTemplate, error)
+
+	// GetTemplate retrieves the current version of a template, requiring Viewer role.
+	GetTemplate(ctx context.Context, projectID, templateID, userID string) (*core.JobTemplate, error)
+
+	// GetTemplateVersion retrieves a specific version of a template, requiring Viewer role.
+	// Versions of deleted templates remain available.
+	GetTemplateVersion(ctx context.Context, projectID, templateID string, version int, userID string) (*core.JobTemplate, error)
+
+	// ListTemplates retrieves a paginated list of a project's templates, requiring Viewer role.
+	ListTemplates(ctx context.Context, projectID, userID string, limit, offset int) ([]*core.JobTemplate, int, error)
+
+	// UpdateTemplate saves the request as the next version of a template, requiring Member role.
+	UpdateTemplate(ctx context.Context, projectID, templateID, userID string, req TemplateRequest) (*core.JobTemplate, error)
+
+	// DeleteTemplate deletes a template, requiring Member role. Jobs created from it keep
+	// their reference to the version they used.
+	DeleteTemplate(ctx context.Context, projectID, templateID, userID string) error
+
+	// CreateJobFromTemplate renders a template version with the request's variables and
+	// creates (and optionally submits) the resulting job through the job service.
+	CreateJobFromTemplate(ctx context.Context, projectID, templateID, userID string, req CreateJobFromTemplateRequest) (*CreateJobFromTemplateResponse, error)
+}
+
+// templateService implements the TemplateService interface.
+type templateService struct {
+	templateRepo core.JobTemplateRepository
+	projects     access.ProjectGetter
+	jobs         JobCreator
+}
+
+// NewTemplateService creates a new job template service instance.
+func NewTemplateService(templateRepo core.JobTemplateRepository, projects access.ProjectGetter, jobs JobCreator) TemplateService {
+	if templateRepo == nil || projects == nil || jobs == nil {
+		panic("jobtemplate.NewTemplateService: all dependencies are required")
+	}
+	return &templateService{
+		templateRepo: templateRepo,
+		projects:     projects,
+		jobs:         jobs,
+	}
+}
+
+// getProjectTemplate returns the current version of a template in the given project.
+// Templates of other projects are reported as not found.
+func (s *templateService) getProjectTemplate(ctx context.Context, projectID, templateID string) (*core.JobTemplate, error) {
+	template, err := s.templateRepo.GetTemplate(ctx, templateID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template %s: %w", templateID, err)
+	}
+	if template.ProjectID != projectID {
+		return nil, fmt.Errorf("template %s is not in project %s: %w", templateID, projectID, core.ErrNotFound)
+	}
+	return template, nil
+}
+
+// validateRequest checks a template create or update request.
+func validateRequest(req TemplateRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidTemplate)
+	}
+	if req.JobType == "" {
+		return fmt.Errorf("%w: jobType cannot be empty", ErrInvalidTemplate)
+	}
+	return validateTemplateConfig(req.JobConfig, req.Variables)
+}
+
+// CreateTemplate validates and stores version 1 of a new template.
+func (s *templateService) CreateTemplate(ctx context.Context, projectID, userID string, req TemplateRequest) (*core.JobTemplate, error) {
+	logger.Logger.Info("Attempting to create job template", zap.String("projectID", projectID), zap.String("userID", userID))
+	// 1. Authorize and validate
+	if _, err := access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleMember, "templates"); err != nil {
+		return nil, err
+	}
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+
+	// 2. Store the first version
+	now := time.Now().UTC()
+	template := &core.JobTemplate{
+		ID:          uuid.NewString(),
+		ProjectID:   projectID,
+		Name:        req.Name,
+		Description: req.Description,
+		JobType:     req.JobType,
+		JobConfig:   req.JobConfig,
+		Variables:   req.Variables,
+		Version:     1,
+		CreatedBy:   userID,
+		UpdatedBy:   userID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if template.Variables == nil {
+		template.Variables = []core.TemplateVariable{}
+	}
+	if err := s.templateRepo.SaveTemplateVersion(ctx, template); err != nil {
+		return nil, fmt.Errorf("failed to store new template: %w", err)
+	}
+	logger.Logger.Info("Successfully created job template", zap.String("templateID", template.ID), zap.String("projectID", projectID))
+	return template, nil
+}
+
+// GetTemplate retrieves the current version of a template.
+func (s *templateService) GetTemplate(ctx context.Context, projectID, templateID, userID string) (*core.JobTemplate, error) {
+	if _, err := access.Authorize(ctx, s.projects, projectID, userID, core.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.getProjectTemplate(ctx, projectID, templateID)
+}
+
+// GetTemplateVersion retrieves a specific version of a template.
+func (s *templateService) GetTemplateVersion(ctx context.Context, projectID, templateID string, version int, userID string) (*core.JobTemplate, error) {
+	if _, err := access.Authorize(ctx, s.projects, projectID, userID, core.RoleViewer); err != nil {
+		return nil, err
+	}
+	template, err := s.templateRepo.GetTemplateVersion(ctx, templateID, version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get template %s version %d: %w", templateID, version, err)
+	}
+	if template.ProjectID != projectID {
+		return nil, fmt.Errorf("template %s is not in project %s: %w", templateID, projectID, core.ErrNotFound)
+	}
+	return template, nil
+}
+
+// ListTemplates retrieves a paginated list of a project's templates.
+func (s *templateService) ListTemplates(ctx context.Context, projectID, userID string, limit, offset int) ([]*core.JobTemplate, int, error) {
+	if _, err := access.Authorize(ctx, s.projects, projectID, userID, core.RoleViewer); err != nil {
+		return nil, 0, err
+	}
+	templates, total, err := s.templateRepo.ListTemplatesByProjectID(ctx, projectID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list templates for project %s: %w", projectID, err)
+	}
+	return templates, total, nil
+}
+
+// UpdateTemplate saves the request as the next version of a template. If the request names
+// the version it was based on, the update fails when that is no longer the current version.
+func (s *templateService) UpdateTemplate(ctx context.Context, projectID, templateID, userID string, req TemplateRequest) (*core.JobTemplate, error) {
+	logger.Logger.Info("Attempting to update job template", zap.String("templateID", templateID), zap.String("userID", userID))
+	// 1. Authorize and validate
+	if _, err := access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleMember, "templates"); err != nil {
+		return nil, err
+	}
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	current, err := s.getProjectTemplate(ctx, projectID, templateID)
+	if err != nil {
+		return nil, err
+	}
+	if req.BaseVersion != 0 && req.BaseVersion != current.Version {
+		return nil, fmt.Errorf("%w: based on version %d, current version is %d", ErrTemplateVersionConflict, req.BaseVersion, current.Version)
+	}
+
+	// 2. Store the next version; a concurrent update saving it first wins
+	next := *current
+	next.Name = req.Name
+	next.Description = req.Description
+	next.JobType = req.JobType
+	next.JobConfig = req.JobConfig
+	next.Variables = req.Variables
+	if next.Variables == nil {
+		next.Variables = []core.TemplateVariable{}
+	}
+	next.Version = current.Version + 1
+	next.UpdatedBy = userID
+	next.UpdatedAt = time.Now().UTC()
+	if err := s.templateRepo.SaveTemplateVersion(ctx, &next); err != nil {
+		if errors.Is(err, core.ErrConflict) {
+			return nil, fmt.Errorf("%w: version %d was saved concurrently", ErrTemplateVersionConflict, next.Version)
+		}
+		return nil, fmt.Errorf("failed to store template version: %w", err)
+	}
+	logger.Logger.Info("Successfully updated job template", zap.String("templateID", templateID), zap.Int("version", next.Version))
+	return &next, nil
+}
+
+// DeleteTemplate deletes a template, keeping its versions.
+func (s *templateService) DeleteTemplate(ctx context.Context, projectID, templateID, userID string) error {
+	if _, err := access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleMember, "templates"); err != nil {
+		return err
+	}
+	if _, err := s.getProjectTemplate(ctx, projectID, templateID); err != nil {
+		return err
+	}
+	if err := s.templateRepo.DeleteTemplate(ctx, templateID); err != nil {
+		return fmt.Errorf("failed to delete template %s: %w", templateID, err)
+	}
+	logger.Logger.Info("Deleted job template", zap.String("templateID", templateID), zap.String("userID", userID))
+	return nil
+}
+
+// CreateJobFromTemplate renders a template and creates the job. Authorization, archive
+// checks and config validation beyond the template are left to the job service.
+func (s *templateService) CreateJobFromTemplate(ctx context.Context, projectID, templateID, userID string, req CreateJobFromTemplateRequest) (*CreateJobFromTemplateResponse, error) {
+	logger.Logger.Info("Attempting to create job from template",
+		zap.String("projectID", projectID),
+		zap.String("templateID", templateID),
+		zap.Int("version", req.Version),
+		zap.String("userID", userID),
+	)
+	// 1. Get the requested version, the current one by default
+	var template *core.JobTemplate
+	var err error
+	if req.Version == 0 {
+		template, err = s.GetTemplate(ctx, projectID, templateID, userID)
+	} else {
+		template, err = s.GetTemplateVersion(ctx, projectID, templateID, req.Version, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Render the config
+	values, err := resolveVariables(template.Variables, req.Variables)
+	if err != nil {
+		return nil, err
+	}
+	jobConfig, err := renderConfig(template.JobConfig, values)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Create the job, recording the exact version used
+	created, err := s.jobs.CreateJob(ctx, projectID, userID, job.CreateJobRequest{
+		ProjectID:       projectID,
+		JobType:         template.JobType,
+		JobConfig:       jobConfig,
+		RetryPolicy:     req.RetryPolicy,
+		TemplateID:      template.ID,
+		TemplateVersion: template.Version,
+		ScheduleID:      req.ScheduleID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp := &CreateJobFromTemplateResponse{Job: created}
+	if !req.Submit {
+		return resp, nil
+	}
+
+	// 4. Submit it if asked; the job exists either way, so a failure is reported alongside it
+	submitted, err := s.jobs.SubmitJob(ctx, created.ID, userID)
+	if submitted != nil {
+		resp.Job = submitted
+	}
+	if err != nil {
+		logger.Logger.Warn("Job created from template could not be submitted", zap.String("jobID", created.ID), zap.Error(err))
+		resp.SubmitError = err.Error()
+		return resp, nil
+	}
+	resp.Submitted = true
+	return resp, nil
+}
@@ -0,0 +1,367 @@
+package jobtemplate
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"SynDataGen/backend/internal/project"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mocks ---
+
+// MockTemplateRepository is a mock implementation of core.JobTemplateRepository.
+type MockTemplateRepository struct {
+	mock.Mock
+}
+
+func (m *MockTemplateRepository) SaveTemplateVersion(ctx context.Context, template *core.JobTemplate) error {
+	args := m.Called(ctx, template)
+	return args.Error(0)
+}
+
+func (m *MockTemplateRepository) GetTemplate(ctx context.Context, templateID string) (*core.JobTemplate, error) {
+	args := m.Called(ctx, templateID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.JobTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) GetTemplateVersion(ctx context.Context, templateID string, version int) (*core.JobTemplate, error) {
+	args := m.Called(ctx, templateID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.JobTemplate), args.Error(1)
+}
+
+func (m *MockTemplateRepository) ListTemplatesByProjectID(ctx context.Context, projectID string, limit, offset int) ([]*core.JobTemplate, int, error) {
+	args := m.Called(ctx, projectID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.JobTemplate), args.Int(1), args.Error(2)
+}
+
+func (m *MockTemplateRepository) DeleteTemplate(ctx context.Context, templateID string) error {
+	args := m.Called(ctx, templateID)
+	return args.Error(0)
+}
+
+// MockProjectGetter is a mock implementation of access.ProjectGetter.
+type MockProjectGetter struct {
+	mock.Mock
+}
+
+func (m *MockProjectGetter) GetProjectByID(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+// MockJobCreator is a mock implementation of JobCreator.
+type MockJobCreator struct {
+	mock.Mock
+}
+
+func (m *MockJobCreator) CreateJob(ctx context.Context, projectID, userID string, req job.CreateJobRequest) (*core.Job, error) {
+	args := m.Called(ctx, projectID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobCreator) SubmitJob(ctx context.Context, jobID, userID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func setupTestService() (TemplateService, *MockTemplateRepository, *MockProjectGetter, *MockJobCreator) {
+	repo := new(MockTemplateRepository)
+	projects := new(MockProjectGetter)
+	jobs := new(MockJobCreator)
+	return NewTemplateService(repo, projects, jobs), repo, projects, jobs
+}
+
+// --- Tests ---
+
+func TestTemplateService_CreateTemplate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-" + uuid.NewString()
+	memberID := "user-member-" + uuid.NewString()
+	viewerID := "user-viewer-" + uuid.NewString()
+	mockProject := &core.Project{ID: projectID, Status: core.ProjectStatusActive, TeamMembers: map[string]core.Role{memberID: core.RoleMember, viewerID: core.RoleViewer}}
+	req := TemplateRequest{
+		Name:      "Customers",
+		JobType:   "csv",
+		JobConfig: `{"rows":"{{rows}}"}`,
+		Variables: []core.TemplateVariable{{Name: "rows", Type: core.TemplateVariableInteger, Default: 1000.0}},
+	}
+
+	t.Run("Success_StoresVersionOne", func(t *testing.T) {
+		service, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		repo.On("SaveTemplateVersion", ctx, mock.AnythingOfType("*core.JobTemplate")).Return(nil).Once()
+
+		template, err := service.CreateTemplate(ctx, projectID, memberID, req)
+
+		require.NoError(err)
+		assert.NotEmpty(template.ID)
+		assert.Equal(projectID, template.ProjectID)
+		assert.Equal(1, template.Version)
+		assert.Equal(memberID, template.CreatedBy)
+		assert.Equal(req.Variables, template.Variables)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("PermissionDenied_ViewerCannotCreate", func(t *testing.T) {
+		service, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, viewerID).Return(mockProject, nil).Once()
+
+		_, err := service.CreateTemplate(ctx, projectID, viewerID, req)
+
+		assert.ErrorIs(err, core.ErrForbidden)
+		repo.AssertNotCalled(t, "SaveTemplateVersion", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_ProjectArchived", func(t *testing.T) {
+		service, _, projects, _ := setupTestService()
+		archived := &core.Project{ID: projectID, Status: core.ProjectStatusArchived, TeamMembers: mockProject.TeamMembers}
+		projects.On("GetProjectByID", ctx, projectID, memberID).Return(archived, nil).Once()
+
+		_, err := service.CreateTemplate(ctx, projectID, memberID, req)
+
+		assert.ErrorIs(err, core.ErrProjectArchived)
+	})
+
+	t.Run("ValidationError_UndeclaredPlaceholder", func(t *testing.T) {
+		service, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		invalid := req
+		invalid.JobConfig = `{"rows":"{{count}}"}`
+
+		_, err := service.CreateTemplate(ctx, projectID, memberID, invalid)
+
+		assert.ErrorIs(err, ErrInvalidTemplate)
+		repo.AssertNotCalled(t, "SaveTemplateVersion", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTemplateService_UpdateTemplate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-" + uuid.NewString()
+	memberID := "user-member-" + uuid.NewString()
+	mockProject := &core.Project{ID: projectID, Status: core.ProjectStatusActive, TeamMembers: map[string]core.Role{memberID: core.RoleMember}}
+	current := &core.JobTemplate{ID: "tmpl-1", ProjectID: projectID, Name: "Old", JobType: "csv", JobConfig: `{}`, Version: 3, CreatedBy: "creator"}
+	req := TemplateRequest{Name: "New", JobType: "csv", JobConfig: `{"rows":10}`}
+
+	setup := func() (TemplateService, *MockTemplateRepository) {
+		service, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		repo.On("GetTemplate", ctx, current.ID).Return(current, nil).Once()
+		return service, repo
+	}
+
+	t.Run("Success_SavesNextVersion", func(t *testing.T) {
+		service, repo := setup()
+		repo.On("SaveTemplateVersion", ctx, mock.MatchedBy(func(tmpl *core.JobTemplate) bool { return tmpl.Version == 4 })).Return(nil).Once()
+
+		updated, err := service.UpdateTemplate(ctx, projectID, current.ID, memberID, req)
+
+		require.NoError(err)
+		assert.Equal(4, updated.Version)
+		assert.Equal("New", updated.Name)
+		assert.Equal("creator", updated.CreatedBy)
+		assert.Equal(memberID, updated.UpdatedBy)
+		assert.Equal(3, current.Version, "the stored version is not modified")
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_StaleBaseVersion", func(t *testing.T) {
+		service, repo := setup()
+		stale := req
+		stale.BaseVersion = 2
+
+		_, err := service.UpdateTemplate(ctx, projectID, current.ID, memberID, stale)
+
+		assert.ErrorIs(err, ErrTemplateVersionConflict)
+		repo.AssertNotCalled(t, "SaveTemplateVersion", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_ConcurrentUpdate", func(t *testing.T) {
+		service, repo := setup()
+		repo.On("SaveTemplateVersion", ctx, mock.Anything).Return(fmt.Errorf("version 4: %w", core.ErrConflict)).Once()
+
+		_, err := service.UpdateTemplate(ctx, projectID, current.ID, memberID, req)
+
+		assert.ErrorIs(err, ErrTemplateVersionConflict)
+	})
+
+	t.Run("Failure_TemplateOfAnotherProject", func(t *testing.T) {
+		service, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-other", memberID).Return(&core.Project{ID: "proj-other", TeamMembers: mockProject.TeamMembers}, nil).Once()
+		repo.On("GetTemplate", ctx, current.ID).Return(current, nil).Once()
+
+		_, err := service.UpdateTemplate(ctx, "proj-other", current.ID, memberID, req)
+
+		assert.ErrorIs(err, core.ErrNotFound)
+	})
+}
+
+func TestTemplateService_DeleteTemplate(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-" + uuid.NewString()
+	memberID := "user-member-" + uuid.NewString()
+	mockProject := &core.Project{ID: projectID, Status: core.ProjectStatusActive, TeamMembers: map[string]core.Role{memberID: core.RoleMember}}
+
+	t.Run("Success", func(t *testing.T) {
+		service, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		repo.On("GetTemplate", ctx, "tmpl-1").Return(&core.JobTemplate{ID: "tmpl-1", ProjectID: projectID}, nil).Once()
+		repo.On("DeleteTemplate", ctx, "tmpl-1").Return(nil).Once()
+
+		require.NoError(t, service.DeleteTemplate(ctx, projectID, "tmpl-1", memberID))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_StrangerCannotDelete", func(t *testing.T) {
+		service, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, "stranger").Return(nil, project.ErrProjectAccessDenied).Once()
+
+		err := service.DeleteTemplate(ctx, projectID, "tmpl-1", "stranger")
+
+		assert.ErrorIs(t, err, project.ErrProjectAccessDenied)
+		repo.AssertNotCalled(t, "DeleteTemplate", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTemplateService_CreateJobFromTemplate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-" + uuid.NewString()
+	memberID := "user-member-" + uuid.NewString()
+	mockProject := &core.Project{ID: projectID, Status: core.ProjectStatusActive, TeamMembers: map[string]core.Role{memberID: core.RoleMember}}
+	template := &core.JobTemplate{
+		ID:        "tmpl-1",
+		ProjectID: projectID,
+		JobType:   "csv",
+		JobConfig: `{"rows":"{{rows}}","path":"out/{{region}}"}`,
+		Variables: []core.TemplateVariable{
+			{Name: "rows", Type: core.TemplateVariableInteger},
+			{Name: "region", Type: core.TemplateVariableString, Default: "eu"},
+		},
+		Version: 2,
+	}
+	expectedRequest := job.CreateJobRequest{
+		ProjectID:       projectID,
+		JobType:         "csv",
+		JobConfig:       `{"path":"out/eu","rows":500}`,
+		TemplateID:      template.ID,
+		TemplateVersion: 2,
+	}
+
+	t.Run("Success_CreatesJobFromCurrentVersion", func(t *testing.T) {
+		service, repo, projects, jobs := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		repo.On("GetTemplate", ctx, template.ID).Return(template, nil).Once()
+		created := &core.Job{ID: "job-1", Status: core.JobStatusPending, TemplateID: template.ID, TemplateVersion: 2}
+		jobs.On("CreateJob", ctx, projectID, memberID, expectedRequest).Return(created, nil).Once()
+
+		resp, err := service.CreateJobFromTemplate(ctx, projectID, template.ID, memberID, CreateJobFromTemplateRequest{
+			Variables: map[string]interface{}{"rows": 500.0},
+		})
+
+		require.NoError(err)
+		assert.Equal(created, resp.Job)
+		assert.False(resp.Submitted)
+		jobs.AssertNotCalled(t, "SubmitJob", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_PinnedVersionAndSubmit", func(t *testing.T) {
+		service, repo, projects, jobs := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		versionOne := *template
+		versionOne.Version = 1
+		versionOne.JobConfig = `{"rows":"{{rows}}"}`
+		repo.On("GetTemplateVersion", ctx, template.ID, 1).Return(&versionOne, nil).Once()
+		jobs.On("CreateJob", ctx, projectID, memberID, mock.MatchedBy(func(req job.CreateJobRequest) bool {
+			return req.TemplateVersion == 1 && req.JobConfig == `{"rows":7}`
+		})).Return(&core.Job{ID: "job-2", Status: core.JobStatusPending}, nil).Once()
+		jobs.On("SubmitJob", ctx, "job-2", memberID).Return(&core.Job{ID: "job-2", Status: core.JobStatusRunning}, nil).Once()
+
+		resp, err := service.CreateJobFromTemplate(ctx, projectID, template.ID, memberID, CreateJobFromTemplateRequest{
+			Version:   1,
+			Variables: map[string]interface{}{"rows": 7.0},
+			Submit:    true,
+		})
+
+		require.NoError(err)
+		assert.True(resp.Submitted)
+		assert.Equal(core.JobStatusRunning, resp.Job.Status)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("Success_SubmitFailureKeepsJob", func(t *testing.T) {
+		service, repo, projects, jobs := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		repo.On("GetTemplate", ctx, template.ID).Return(template, nil).Once()
+		jobs.On("CreateJob", ctx, projectID, memberID, expectedRequest).Return(&core.Job{ID: "job-3", Status: core.JobStatusPending}, nil).Once()
+		jobs.On("SubmitJob", ctx, "job-3", memberID).Return(nil, errors.New("pipeline unavailable")).Once()
+
+		resp, err := service.CreateJobFromTemplate(ctx, projectID, template.ID, memberID, CreateJobFromTemplateRequest{
+			Variables: map[string]interface{}{"rows": 500.0},
+			Submit:    true,
+		})
+
+		require.NoError(err)
+		assert.Equal("job-3", resp.Job.ID)
+		assert.False(resp.Submitted)
+		assert.Equal("pipeline unavailable", resp.SubmitError)
+	})
+
+	t.Run("Failure_MissingVariable", func(t *testing.T) {
+		service, repo, projects, jobs := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		repo.On("GetTemplate", ctx, template.ID).Return(template, nil).Once()
+
+		_, err := service.CreateJobFromTemplate(ctx, projectID, template.ID, memberID, CreateJobFromTemplateRequest{})
+
+		assert.ErrorIs(err, ErrInvalidVariables)
+		jobs.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_JobServiceRejects", func(t *testing.T) {
+		service, repo, projects, jobs := setupTestService()
+		projects.On("GetProjectByID", ctx, projectID, memberID).Return(mockProject, nil).Once()
+		repo.On("GetTemplate", ctx, template.ID).Return(template, nil).Once()
+		jobs.On("CreateJob", ctx, projectID, memberID, expectedRequest).Return(nil, fmt.Errorf("%w: bad schema", job.ErrInvalidJobConfig)).Once()
+
+		_, err := service.CreateJobFromTemplate(ctx, projectID, template.ID, memberID, CreateJobFromTemplateRequest{
+			Variables: map[string]interface{}{"rows": 500.0},
+		})
+
+		assert.ErrorIs(err, job.ErrInvalidJobConfig)
+	})
+}
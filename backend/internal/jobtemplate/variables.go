@@ -0,0 +1,169 @@
+package jobtemplate
+
+import (
+	"SynDataGen/backend/internal/core"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// placeholderPattern matches a {{name}} placeholder, allowing spaces inside the braces.
+var placeholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// variableNamePattern is the form of a declared variable name.
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validateTemplateConfig checks that a template config is a JSON object whose placeholders
+// are all declared, and that the declared variables are well formed.
+func validateTemplateConfig(config string, variables []core.TemplateVariable) error {
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(config), &parsed); err != nil {
+		return fmt.Errorf("%w: jobConfig must be JSON: %v", ErrInvalidTemplate, err)
+	}
+	if _, ok := parsed.(map[string]interface{}); !ok {
+		return fmt.Errorf("%w: jobConfig must be a JSON object", ErrInvalidTemplate)
+	}
+
+	declared := make(map[string]bool, len(variables))
+	for _, variable := range variables {
+		if !variableNamePattern.MatchString(variable.Name) {
+			return fmt.Errorf("%w: invalid variable name %q", ErrInvalidTemplate, variable.Name)
+		}
+		if declared[variable.Name] {
+			return fmt.Errorf("%w: variable %q is declared twice", ErrInvalidTemplate, variable.Name)
+		}
+		declared[variable.Name] = true
+		switch variable.Type {
+		case core.TemplateVariableString, core.TemplateVariableNumber, core.TemplateVariableInteger, core.TemplateVariableBoolean:
+		default:
+			return fmt.Errorf("%w: variable %q has unknown type %q", ErrInvalidTemplate, variable.Name, variable.Type)
+		}
+		if variable.Default != nil {
+			if err := checkVariableValue(variable, variable.Default); err != nil {
+				return fmt.Errorf("%w: default of %v", ErrInvalidTemplate, err)
+			}
+		}
+	}
+
+	for _, match := range placeholderPattern.FindAllStringSubmatch(config, -1) {
+		if !declared[match[1]] {
+			return fmt.Errorf("%w: placeholder {{%s}} is not a declared variable", ErrInvalidTemplate, match[1])
+		}
+	}
+	return nil
+}
+
+// checkVariableValue checks that a decoded JSON value matches the variable's type.
+func checkVariableValue(variable core.TemplateVariable, value interface{}) error {
+	ok := false
+	switch variable.Type {
+	case core.TemplateVariableString:
+		_, ok = value.(string)
+	case core.TemplateVariableNumber:
+		_, ok = value.(float64)
+	case core.TemplateVariableInteger:
+		number, isNumber := value.(float64)
+		ok = isNumber && number == math.Trunc(number)
+	case core.TemplateVariableBoolean:
+		_, ok = value.(bool)
+	}
+	if !ok {
+		return fmt.Errorf("variable %q must be a %s", variable.Name, variable.Type)
+	}
+	return nil
+}
+
+// resolveVariables combines the supplied values with the template's defaults. Every variable
+// without a default must be supplied, and only declared variables may be.
+func resolveVariables(variables []core.TemplateVariable, supplied map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(variables))
+	var missing []string
+	for _, variable := range variables {
+		value, ok := supplied[variable.Name]
+		if !ok || value == nil {
+			if variable.Default == nil {
+				missing = append(missing, variable.Name)
+				continue
+			}
+			value = variable.Default
+		}
+		if err := checkVariableValue(variable, value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidVariables, err)
+		}
+		values[variable.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: missing values for %s", ErrInvalidVariables, strings.Join(missing, ", "))
+	}
+
+	var unknown []string
+	for name := range supplied {
+		if _, ok := values[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: unknown variables %s", ErrInvalidVariables, strings.Join(unknown, ", "))
+	}
+	return values, nil
+}
+
+// renderConfig substitutes variable values into a template config. A string that is exactly
+// one placeholder is replaced by the value itself, keeping its JSON type; placeholders
+// inside longer strings are replaced by the value's text.
+func renderConfig(config string, values map[string]interface{}) (string, error) {
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(config), &parsed); err != nil {
+		return "", fmt.Errorf("%w: jobConfig must be JSON: %v", ErrInvalidTemplate, err)
+	}
+	rendered, err := json.Marshal(substitute(parsed, values))
+	if err != nil {
+		return "", fmt.Errorf("failed to encode rendered job config: %w", err)
+	}
+	if match := placeholderPattern.FindString(string(rendered)); match != "" {
+		// A value contained a placeholder of its own, or one was used as an object key
+		return "", fmt.Errorf("%w: rendered config still contains %s", ErrInvalidVariables, match)
+	}
+	return string(rendered), nil
+}
+
+func substitute(node interface{}, values map[string]interface{}) interface{} {
+	switch typed := node.(type) {
+	case map[string]interface{}:
+		for key, child := range typed {
+			typed[key] = substitute(child, values)
+		}
+		return typed
+	case []interface{}:
+		for i, child := range typed {
+			typed[i] = substitute(child, values)
+		}
+		return typed
+	case string:
+		if match := placeholderPattern.FindStringSubmatchIndex(typed); match != nil && match[0] == 0 && match[1] == len(typed) {
+			return values[typed[match[2]:match[3]]]
+		}
+		return placeholderPattern.ReplaceAllStringFunc(typed, func(placeholder string) string {
+			return formatValue(values[placeholderPattern.FindStringSubmatch(placeholder)[1]])
+		})
+	default:
+		return node
+	}
+}
+
+// formatValue returns the text of a variable value embedded in a string.
+func formatValue(value interface{}) string {
+	switch typed := value.(type) {
+	case string:
+		return typed
+	case float64:
+		return strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		return fmt.Sprint(typed)
+	}
+}
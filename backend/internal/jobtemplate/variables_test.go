@@ -0,0 +1,85 @@
+package jobtemplate
+
+import (
+	"SynDataGen/backend/internal/core"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testVariables = []core.TemplateVariable{
+	{Name: "rows", Type: core.TemplateVariableInteger},
+	{Name: "region", Type: core.TemplateVariableString, Default: "eu"},
+	{Name: "ratio", Type: core.TemplateVariableNumber, Default: 0.5},
+	{Name: "strict", Type: core.TemplateVariableBoolean, Default: false},
+}
+
+func TestValidateTemplateConfig(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		assert.NoError(t, validateTemplateConfig(`{"rows":"{{rows}}","path":"out/{{ region }}/data"}`, testVariables))
+	})
+
+	for name, tc := range map[string]struct {
+		config    string
+		variables []core.TemplateVariable
+	}{
+		"NotJSON":               {`rows={{rows}}`, testVariables},
+		"NotAnObject":           {`["{{rows}}"]`, testVariables},
+		"UndeclaredPlaceholder": {`{"rows":"{{count}}"}`, testVariables},
+		"InvalidName":           {`{}`, []core.TemplateVariable{{Name: "row-count", Type: core.TemplateVariableInteger}}},
+		"DuplicateName":         {`{}`, []core.TemplateVariable{{Name: "a", Type: "string"}, {Name: "a", Type: "number"}}},
+		"UnknownType":           {`{}`, []core.TemplateVariable{{Name: "a", Type: "date"}}},
+		"DefaultWrongType":      {`{}`, []core.TemplateVariable{{Name: "a", Type: core.TemplateVariableInteger, Default: 1.5}}},
+	} {
+		t.Run("Failure_"+name, func(t *testing.T) {
+			assert.ErrorIs(t, validateTemplateConfig(tc.config, tc.variables), ErrInvalidTemplate)
+		})
+	}
+}
+
+func TestResolveVariables(t *testing.T) {
+	t.Run("Success_AppliesDefaults", func(t *testing.T) {
+		values, err := resolveVariables(testVariables, map[string]interface{}{"rows": 100.0, "strict": true})
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"rows": 100.0, "region": "eu", "ratio": 0.5, "strict": true}, values)
+	})
+
+	for name, supplied := range map[string]map[string]interface{}{
+		"Missing":         {"region": "us"},
+		"WrongType":       {"rows": "100"},
+		"NotAnInteger":    {"rows": 10.5},
+		"UnknownVariable": {"rows": 1.0, "colour": "red"},
+	} {
+		t.Run("Failure_"+name, func(t *testing.T) {
+			_, err := resolveVariables(testVariables, supplied)
+
+			assert.ErrorIs(t, err, ErrInvalidVariables)
+		})
+	}
+}
+
+func TestRenderConfig(t *testing.T) {
+	values := map[string]interface{}{"rows": 100.0, "region": "eu", "ratio": 0.25, "strict": true}
+
+	t.Run("Success_KeepsTypesOfWholeValues", func(t *testing.T) {
+		rendered, err := renderConfig(`{"rows":"{{rows}}","strict":"{{ strict }}","tables":[{"ratio":"{{ratio}}"}]}`, values)
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"rows":100,"strict":true,"tables":[{"ratio":0.25}]}`, rendered)
+	})
+
+	t.Run("Success_EmbedsTextInStrings", func(t *testing.T) {
+		rendered, err := renderConfig(`{"path":"out/{{region}}/{{rows}}-rows","note":"{{strict}}!"}`, values)
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"path":"out/eu/100-rows","note":"true!"}`, rendered)
+	})
+
+	t.Run("Failure_ValueWithPlaceholder", func(t *testing.T) {
+		_, err := renderConfig(`{"region":"{{region}}"}`, map[string]interface{}{"region": "{{rows}}"})
+
+		assert.ErrorIs(t, err, ErrInvalidVariables)
+	})
+}
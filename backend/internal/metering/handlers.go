@@ -0,0 +1,157 @@
+package metering
+
+import (
+	"SynDataGen/backend/internal/auth"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// UsageHandler handles HTTP requests for usage reports.
+type UsageHandler struct {
+	service UsageService
+}
+
+// NewUsageHandler creates a new UsageHandler.
+func NewUsageHandler(s UsageService) *UsageHandler {
+	return &UsageHandler{service: s}
+}
+
+// RegisterRoutes registers usage routes with the Gin router group.
+func (h *UsageHandler) RegisterRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	usage := rg.Group("/usage")
+	usage.Use(authMiddleware)
+	{
+		usage.GET("", h.GetUsage) // GET /api/v1/usage?from=&to=&groupBy=&format=
+	}
+}
+
+// GetUsage handles GET /usage requests. from and to accept RFC 3339 timestamps or
+// YYYY-MM-DD dates, and format=csv returns the report's groups as a CSV file.
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	userID, ok := c.Get(auth.UserIDKey)
+	if !ok || userID == "" {
+		logger.Logger.Error("UserID not found in context during GetUsage")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User ID missing"})
+		return
+	}
+
+	query := ReportQuery{GroupBy: GroupBy(c.Query("groupBy"))}
+	var err error
+	if query.From, err = parseReportTime(c.Query("from")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_USAGE_QUERY", "message": "Invalid 'from' query parameter"})
+		return
+	}
+	if query.To, err = parseReportTime(c.Query("to")); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_USAGE_QUERY", "message": "Invalid 'to' query parameter"})
+		return
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_USAGE_QUERY", "message": "format must be json or csv"})
+		return
+	}
+
+	report, err := h.service.GetUsageReport(c.Request.Context(), userID.(string), query)
+	if err != nil {
+		if errors.Is(err, ErrInvalidUsageQuery) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_USAGE_QUERY", "message": err.Error()})
+			return
+		}
+		logger.Logger.Error("Failed to get usage report via service", zap.Error(err), zap.Any("userId", userID))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to get usage report"})
+		return
+	}
+
+	if format == "csv" {
+		writeReportCSV(c, report)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// parseReportTime parses an RFC 3339 timestamp or a YYYY-MM-DD date (midnight UTC). An
+// empty value is the zero time.
+func parseReportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// writeReportCSV writes a report's groups, one row each, as a CSV attachment.
+func writeReportCSV(c *gin.Context, report *UsageReport) {
+	filename := fmt.Sprintf("usage-%s-%s-%s.csv", report.GroupBy, report.From.Format("20060102"), report.To.Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	header := []string{string(report.GroupBy)}
+	if report.GroupBy == GroupByProject {
+		header = append(header, "projectName", "customerId")
+	}
+	header = append(header, "recordsGenerated", "computeSeconds", "storageByteHours", "egressBytes")
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write(header)
+	for _, group := range report.Groups {
+		row := []string{group.Key}
+		if report.GroupBy == GroupByProject {
+			row = append(row, group.ProjectName, group.CustomerID)
+		}
+		row = append(row,
+			formatQuantity(group.RecordsGenerated),
+			formatQuantity(group.ComputeSeconds),
+			formatQuantity(group.StorageByteHours),
+			formatQuantity(group.EgressBytes),
+		)
+		_ = w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		logger.Logger.Warn("Failed to write usage CSV", zap.Error(err)) // Headers are sent; nothing else to do
+	}
+}
+
+// formatQuantity formats a quantity without an exponent or trailing zeros.
+func formatQuantity(q float64) string {
+	return strconv.FormatFloat(q, 'f', -1, 64)
+}
+
+// EgressMiddleware meters the bytes of successful responses to the given routes, named
+// by their full path pattern (for example "/api/v1/projects/:projectId/datasets/:datasetId/content").
+// The routes must have a :projectId parameter. It must be added to a group before the
+// routes are registered.
+func (m *Meter) EgressMiddleware(routes ...string) gin.HandlerFunc {
+	metered := make(map[string]bool, len(routes))
+	for _, route := range routes {
+		metered[route] = true
+	}
+	return func(c *gin.Context) {
+		c.Next()
+		if !metered[c.FullPath()] {
+			return
+		}
+
+		status, size := c.Writer.Status(), c.Writer.Size()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices || size <= 0 {
+			return
+		}
+		projectID := c.Param("projectId")
+		// The response is written; record it even if the client has gone
+		if err := m.RecordEgress(context.WithoutCancel(c.Request.Context()), projectID, int64(size)); err != nil {
+			logger.Logger.Warn("Failed to record egress usage", zap.String("projectID", projectID), zap.Int("bytes", size), zap.Error(err))
+		}
+	}
+}
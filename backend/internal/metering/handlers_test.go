@@ -0,0 +1,172 @@
+package metering
+
+import (
+	"SynDataGen/backend/internal/auth"
+	"SynDataGen/backend/internal/core"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockUsageService is a mock implementation of UsageService.
+type MockUsageService struct {
+	mock.Mock
+}
+
+func (m *MockUsageService) GetUsageReport(ctx context.Context, userID string, query ReportQuery) (*UsageReport, error) {
+	args := m.Called(ctx, userID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*UsageReport), args.Error(1)
+}
+
+func setupGinTestRouter() (*gin.Engine, *MockUsageService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockService := new(MockUsageService)
+	mockAuthMiddleware := func(c *gin.Context) {
+		if userID := c.GetHeader("X-User-ID"); userID != "" {
+			c.Set(auth.UserIDKey, userID)
+		}
+		c.Next()
+	}
+	NewUsageHandler(mockService).RegisterRoutes(router.Group("/"), mockAuthMiddleware)
+	return router, mockService
+}
+
+func serve(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-User-ID", "user-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestUsageHandler_GetUsage(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	report := &UsageReport{
+		From: from, To: to, GroupBy: GroupByProject,
+		Groups: []UsageGroup{
+			{Key: "proj-1", ProjectName: "Demo, Inc", CustomerID: "cust-1", UsageTotals: UsageTotals{RecordsGenerated: 1500, ComputeSeconds: 12.5}},
+		},
+		Customers: []CustomerUsage{{CustomerID: "cust-1", UsageTotals: UsageTotals{RecordsGenerated: 1500, ComputeSeconds: 12.5}}},
+		Total:     UsageTotals{RecordsGenerated: 1500, ComputeSeconds: 12.5},
+	}
+
+	t.Run("Success_JSON", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("GetUsageReport", mock.Anything, "user-1", ReportQuery{From: from, To: to, GroupBy: GroupByProject}).Return(report, nil).Once()
+
+		w := serve(router, "/usage?from=2026-03-01&to=2026-03-03T00:00:00Z&groupBy=project")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"key":"proj-1"`)
+		assert.Contains(t, w.Body.String(), `"recordsGenerated":1500`)
+	})
+
+	t.Run("Success_CSV", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("GetUsageReport", mock.Anything, "user-1", ReportQuery{From: from, To: to}).Return(report, nil).Once()
+
+		w := serve(router, "/usage?from=2026-03-01&to=2026-03-03&format=csv")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename="usage-project-20260301-20260303.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, strings.Join([]string{
+			"project,projectName,customerId,recordsGenerated,computeSeconds,storageByteHours,egressBytes",
+			`proj-1,"Demo, Inc",cust-1,1500,12.5,0,0`,
+			"",
+		}, "\n"), w.Body.String())
+	})
+
+	t.Run("Failure_InvalidTime", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+
+		w := serve(router, "/usage?from=yesterday")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_USAGE_QUERY")
+		mockService.AssertNotCalled(t, "GetUsageReport", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_InvalidFormat", func(t *testing.T) {
+		router, _ := setupGinTestRouter()
+
+		w := serve(router, "/usage?format=xml")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Failure_InvalidQuery", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("GetUsageReport", mock.Anything, "user-1", ReportQuery{GroupBy: "customer"}).
+			Return(nil, fmt.Errorf("%w: groupBy must be project, day or jobType", ErrInvalidUsageQuery)).Once()
+
+		w := serve(router, "/usage?groupBy=customer")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "groupBy must be")
+	})
+
+	t.Run("Failure_ServiceError", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("GetUsageReport", mock.Anything, "user-1", ReportQuery{}).Return(nil, errors.New("unavailable")).Once()
+
+		w := serve(router, "/usage")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestMeter_EgressMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	route := "/projects/:projectId/datasets/:datasetId/content"
+	setup := func() (*gin.Engine, meterMocks) {
+		meter, m := setupMeterTest()
+		router := gin.New()
+		router.Use(meter.EgressMiddleware(route))
+		router.GET(route, func(c *gin.Context) {
+			if c.Param("datasetId") == "missing.csv" {
+				c.String(http.StatusNotFound, "not found")
+				return
+			}
+			c.String(http.StatusOK, "0123456789")
+		})
+		router.GET("/projects/:projectId/datasets/:datasetId/schema", func(c *gin.Context) { c.String(http.StatusOK, "{}") })
+		return router, m
+	}
+
+	t.Run("Success_MetersDownload", func(t *testing.T) {
+		router, m := setup()
+		m.projects.On("GetProjectByID", mock.Anything, "proj-1").Return(&core.Project{ID: "proj-1", CustomerID: "cust-1"}, nil).Once()
+		m.usage.On("RecordUsage", mock.Anything, mock.MatchedBy(func(e *core.UsageEvent) bool {
+			return e.Metric == core.UsageEgressBytes && e.Quantity == 10 && e.ProjectID == "proj-1" && e.CustomerID == "cust-1"
+		})).Return(true, nil).Once()
+
+		w := serve(router, "/projects/proj-1/datasets/data.csv/content")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		m.usage.AssertExpectations(t)
+	})
+
+	t.Run("Skip_OtherRoutesAndErrors", func(t *testing.T) {
+		router, m := setup()
+
+		serve(router, "/projects/proj-1/datasets/data.csv/schema")
+		serve(router, "/projects/proj-1/datasets/missing.csv/content")
+
+		m.usage.AssertNotCalled(t, "RecordUsage", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,304 @@
+package metering
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"SynDataGen/backend/internal/platform/lease"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// DefaultInterval is how often the meter reconciles jobs and samples storage when no
+// interval is configured. Storage is billed in periods of this length.
+const DefaultInterval = time.Hour
+
+const (
+	leaseName          = "metering" // Lease held by the replica that reconciles and samples
+	reconcileBatchSize = 100        // Unmetered jobs recorded per tick at most; the rest wait for the next
+	backfillPeriods    = 24 * 7     // Storage periods billed per project per tick at most; the rest wait for the next
+)
+
+// Config holds configuration for the Meter.
+type Config struct {
+	Interval time.Duration // Time between ticks and length of a storage period; defaults to DefaultInterval
+	HolderID string        // Identifies this replica in the lease; required
+}
+
+// TickResult summarizes a single tick.
+type TickResult struct {
+	JobsMetered    int
+	StorageSamples int
+}
+
+// Meter records usage events attributed to a project and its customer.
+//
+// Completed jobs are metered exactly once: each of their events has an ID derived from the
+// job, so reporting a job twice records nothing new, and a job is only marked metered once
+// all its events are stored. The job service meters jobs as they complete; the replica
+// holding the metering lease also records any completed job left unmetered, and samples
+// each project's stored bytes once per period, backfilling periods no replica sampled.
+type Meter struct {
+	usageRepo   core.UsageRepository
+	projectRepo core.ProjectRepository
+	jobRepo     core.JobRepository
+	runner      *lease.Runner
+	interval    time.Duration    // Length of a storage period
+	now         func() time.Time // Overridable for tests
+}
+
+// NewMeter creates a new Meter.
+func NewMeter(usageRepo core.UsageRepository, projectRepo core.ProjectRepository, jobRepo core.JobRepository, leaseRepo core.LeaseRepository, cfg Config) *Meter {
+	if usageRepo == nil || projectRepo == nil || jobRepo == nil || leaseRepo == nil {
+		panic("metering.NewMeter: all dependencies are required")
+	}
+	if cfg.HolderID == "" {
+		panic("metering.NewMeter: HolderID is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	return &Meter{
+		usageRepo:   usageRepo,
+		projectRepo: projectRepo,
+		jobRepo:     jobRepo,
+		runner:      lease.NewRunner(leaseRepo, leaseName, cfg.HolderID, cfg.Interval),
+		interval:    cfg.Interval,
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run ticks immediately and then once per interval until ctx is cancelled.
+func (m *Meter) Run(ctx context.Context) {
+	m.runner.Run(ctx, func(ctx context.Context) error {
+		_, err := m.meter(ctx)
+		return err
+	})
+}
+
+// TickOnce records the usage of unmetered completed jobs and samples storage up to the
+// current period, if this replica holds the lease.
+func (m *Meter) TickOnce(ctx context.Context) (TickResult, error) {
+	var res TickResult
+	_, err := m.runner.TickOnce(ctx, func(ctx context.Context) (err error) {
+		res, err = m.meter(ctx)
+		return err
+	})
+	return res, err
+}
+
+// meter records unmetered jobs and samples storage. Per-job and per-project failures are
+// returned together; they do not stop the tick.
+func (m *Meter) meter(ctx context.Context) (TickResult, error) {
+	var res TickResult
+	projects, err := m.projectRepo.ListAllProjects(ctx)
+	if err != nil {
+		return res, fmt.Errorf("failed to list projects for metering: %w", err)
+	}
+	byID := make(map[string]*core.Project, len(projects))
+	for _, proj := range projects {
+		byID[proj.ID] = proj
+	}
+
+	// 1. Record jobs whose usage was not recorded when they completed
+	var errs []error
+	jobs, err := m.jobRepo.ListUnmeteredJobs(ctx, reconcileBatchSize)
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list unmetered jobs: %w", err))
+	}
+	for _, j := range jobs {
+		if err := m.recordJobUsage(ctx, j, byID[j.ProjectID]); err != nil {
+			errs = append(errs, fmt.Errorf("job %s: %w", j.ID, err))
+			continue
+		}
+		res.JobsMetered++
+	}
+
+	// 2. Bill each project's stored bytes for the periods up to the current one
+	period := m.now().Truncate(m.interval)
+	for _, proj := range projects {
+		recorded, err := m.sampleStorage(ctx, proj, period)
+		res.StorageSamples += recorded
+		if err != nil {
+			errs = append(errs, fmt.Errorf("project %s: %w", proj.ID, err))
+		}
+	}
+
+	if res.JobsMetered > 0 || res.StorageSamples > 0 {
+		logger.Logger.Info("Usage meter tick completed",
+			zap.Int("jobsMetered", res.JobsMetered),
+			zap.Int("storageSamples", res.StorageSamples),
+			zap.Time("period", period),
+		)
+	}
+	return res, errors.Join(errs...)
+}
+
+// RecordJobUsage records the records generated and compute seconds of a completed job,
+// then marks it metered. Jobs that are not completed or already metered are ignored.
+func (m *Meter) RecordJobUsage(ctx context.Context, j *core.Job) error {
+	if j.Status != core.JobStatusCompleted || j.MeteredAt != nil {
+		return nil
+	}
+	proj, err := m.projectRepo.GetProjectByID(ctx, j.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project for job usage: %w", err)
+	}
+	return m.recordJobUsage(ctx, j, proj)
+}
+
+// recordJobUsage records a completed job's events and marks it metered. proj may be nil
+// for a deleted project, leaving the usage without a customer.
+func (m *Meter) recordJobUsage(ctx context.Context, j *core.Job, proj *core.Project) error {
+	now := m.now()
+	occurredAt := now
+	if j.CompletedAt != nil {
+		occurredAt = *j.CompletedAt
+	}
+	quantities := []struct {
+		metric   core.UsageMetric
+		quantity float64
+	}{
+		{core.UsageRecordsGenerated, float64(jobRecords(j))},
+		{core.UsageComputeSeconds, jobComputeSeconds(j)},
+	}
+	for _, q := range quantities {
+		if q.quantity <= 0 {
+			continue
+		}
+		event := &core.UsageEvent{
+			ID:         jobUsageID(j.ID, q.metric),
+			CustomerID: customerOf(proj),
+			ProjectID:  j.ProjectID,
+			JobID:      j.ID,
+			JobType:    j.JobType,
+			Metric:     q.metric,
+			Quantity:   q.quantity,
+			OccurredAt: occurredAt,
+			RecordedAt: now,
+		}
+		if _, err := m.usageRepo.RecordUsage(ctx, event); err != nil {
+			return err
+		}
+	}
+	if err := m.jobRepo.MarkJobMetered(ctx, j.ID, now); err != nil {
+		return fmt.Errorf("failed to mark job metered: %w", err)
+	}
+	j.MeteredAt = &now
+	return nil
+}
+
+// sampleStorage bills a project's stored bytes for each period after the last one billed,
+// up to the period starting at current, and records how far it got. Periods missed by
+// ticker drift, a lease handoff or downtime are billed at the current usage, at most
+// backfillPeriods per tick. It returns how many new samples were recorded; a period's
+// event ID is derived from its start, so a period is sampled at most once.
+func (m *Meter) sampleStorage(ctx context.Context, proj *core.Project, current time.Time) (int, error) {
+	from := current
+	if last := proj.Storage.SampledPeriod; last != nil {
+		from = last.Truncate(m.interval).Add(m.interval)
+	}
+	if from.After(current) {
+		return 0, nil // Already sampled
+	}
+	to := current
+	if limit := from.Add(time.Duration(backfillPeriods-1) * m.interval); limit.Before(to) {
+		to = limit
+	}
+
+	var recorded int
+	var sampled time.Time
+	var sampleErr error
+	for period := from; !period.After(to); period = period.Add(m.interval) {
+		if proj.Storage.UsedStorageBytes > 0 {
+			ok, err := m.usageRepo.RecordUsage(ctx, &core.UsageEvent{
+				ID:         "storage:" + proj.ID + ":" + strconv.FormatInt(period.Unix(), 10),
+				CustomerID: customerOf(proj),
+				ProjectID:  proj.ID,
+				Metric:     core.UsageStorageByteHours,
+				Quantity:   float64(proj.Storage.UsedStorageBytes) * m.interval.Hours(),
+				OccurredAt: period,
+				RecordedAt: m.now(),
+			})
+			if err != nil {
+				sampleErr = err
+				break
+			}
+			if ok {
+				recorded++
+			}
+		}
+		sampled = period
+	}
+
+	if !sampled.IsZero() {
+		if err := m.projectRepo.UpdateStorageSampledPeriod(ctx, proj.ID, sampled); err != nil {
+			// The samples are stored; the next tick finds them recorded and moves on
+			return recorded, errors.Join(sampleErr, fmt.Errorf("failed to record sampled storage period: %w", err))
+		}
+		proj.Storage.SampledPeriod = &sampled
+	}
+	return recorded, sampleErr
+}
+
+// RecordEgress records bytes sent to a client from a project's data.
+func (m *Meter) RecordEgress(ctx context.Context, projectID string, bytes int64) error {
+	if bytes <= 0 {
+		return nil
+	}
+	proj, err := m.projectRepo.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("failed to get project for egress usage: %w", err)
+	}
+	now := m.now()
+	_, err = m.usageRepo.RecordUsage(ctx, &core.UsageEvent{
+		ID:         "egress:" + uuid.NewString(), // Every download is new usage
+		CustomerID: customerOf(proj),
+		ProjectID:  projectID,
+		Metric:     core.UsageEgressBytes,
+		Quantity:   float64(bytes),
+		OccurredAt: now,
+		RecordedAt: now,
+	})
+	return err
+}
+
+// jobUsageID is the ID of a job's event for one metric.
+func jobUsageID(jobID string, metric core.UsageMetric) string {
+	return "job:" + jobID + ":" + string(metric)
+}
+
+// jobRecords returns how many rows a completed job generated: its recorded actual count,
+// or the count its config requested when the pipeline does not report one.
+func jobRecords(j *core.Job) int64 {
+	if j.Actuals != nil && j.Actuals.Records > 0 {
+		return j.Actuals.Records
+	}
+	return job.RecordCount(j.JobConfig)
+}
+
+// jobComputeSeconds returns how long a completed job ran: its recorded actual duration, or
+// the time between its start and completion.
+func jobComputeSeconds(j *core.Job) float64 {
+	if j.Actuals != nil && j.Actuals.DurationSeconds > 0 {
+		return j.Actuals.DurationSeconds
+	}
+	if j.StartedAt != nil && j.CompletedAt != nil && j.CompletedAt.After(*j.StartedAt) {
+		return j.CompletedAt.Sub(*j.StartedAt).Seconds()
+	}
+	return 0
+}
+
+// customerOf returns the customer billed for a project, or "" if the project is unknown.
+func customerOf(proj *core.Project) string {
+	if proj == nil {
+		return ""
+	}
+	return job.ProjectCustomer(proj)
+}
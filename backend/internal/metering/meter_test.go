@@ -0,0 +1,353 @@
+package metering
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUsageRepository is a mock implementation of core.UsageRepository.
+type MockUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockUsageRepository) RecordUsage(ctx context.Context, event *core.UsageEvent) (bool, error) {
+	args := m.Called(ctx, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUsageRepository) ListUsage(ctx context.Context, projectIDs []string, from, to time.Time) ([]*core.UsageEvent, error) {
+	args := m.Called(ctx, projectIDs, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.UsageEvent), args.Error(1)
+}
+
+// MockProjectRepository mocks project lookups.
+type MockProjectRepository struct {
+	mock.Mock
+	core.ProjectRepository
+}
+
+func (m *MockProjectRepository) GetProjectByID(ctx context.Context, id string) (*core.Project, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) ListAllProjects(ctx context.Context) ([]*core.Project, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) UpdateStorageSampledPeriod(ctx context.Context, projectID string, period time.Time) error {
+	args := m.Called(ctx, projectID, period)
+	return args.Error(0)
+}
+
+// MockJobRepository mocks the job metering methods.
+type MockJobRepository struct {
+	mock.Mock
+	core.JobRepository
+}
+
+func (m *MockJobRepository) MarkJobMetered(ctx context.Context, jobID string, meteredAt time.Time) error {
+	args := m.Called(ctx, jobID, meteredAt)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListUnmeteredJobs(ctx context.Context, limit int) ([]*core.Job, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Job), args.Error(1)
+}
+
+// MockLeaseRepository is a mock implementation of core.LeaseRepository.
+type MockLeaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockLeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, name, holder, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+var testNow = time.Date(2026, 3, 10, 10, 25, 0, 0, time.UTC)
+
+type meterMocks struct {
+	usage    *MockUsageRepository
+	projects *MockProjectRepository
+	jobs     *MockJobRepository
+	leases   *MockLeaseRepository
+}
+
+func setupMeterTest() (*Meter, meterMocks) {
+	m := meterMocks{
+		usage:    new(MockUsageRepository),
+		projects: new(MockProjectRepository),
+		jobs:     new(MockJobRepository),
+		leases:   new(MockLeaseRepository),
+	}
+	meter := NewMeter(m.usage, m.projects, m.jobs, m.leases, Config{HolderID: "replica-1"})
+	meter.now = func() time.Time { return testNow }
+	return meter, m
+}
+
+// completedJob returns a completed csv job of 1000 records that ran for 90 seconds.
+func completedJob() *core.Job {
+	started := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	completed := started.Add(90 * time.Second)
+	return &core.Job{
+		ID: "job-1", ProjectID: "proj-1", Status: core.JobStatusCompleted, JobType: "csv",
+		JobConfig: `{"parameters":{"recordCount":1000}}`, StartedAt: &started, CompletedAt: &completed,
+	}
+}
+
+var testProject = &core.Project{ID: "proj-1", Name: "Demo", TeamMembers: map[string]core.Role{"owner-1": core.RoleOwner}}
+
+// eventWith matches a usage event with the given ID and quantity.
+func eventWith(id string, quantity float64) interface{} {
+	return mock.MatchedBy(func(e *core.UsageEvent) bool { return e.ID == id && e.Quantity == quantity })
+}
+
+func TestMeter_RecordJobUsage(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		job := completedJob()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		var recorded []*core.UsageEvent
+		m.usage.On("RecordUsage", ctx, mock.AnythingOfType("*core.UsageEvent")).
+			Run(func(args mock.Arguments) { recorded = append(recorded, args.Get(1).(*core.UsageEvent)) }).
+			Return(true, nil).Twice()
+		m.jobs.On("MarkJobMetered", ctx, "job-1", testNow).Return(nil).Once()
+
+		err := meter.RecordJobUsage(ctx, job)
+
+		require.NoError(t, err)
+		require.Len(t, recorded, 2)
+		assert.Equal(t, &core.UsageEvent{
+			ID: "job:job-1:recordsGenerated", CustomerID: "owner-1", ProjectID: "proj-1", JobID: "job-1", JobType: "csv",
+			Metric: core.UsageRecordsGenerated, Quantity: 1000, OccurredAt: *job.CompletedAt, RecordedAt: testNow,
+		}, recorded[0])
+		assert.Equal(t, "job:job-1:computeSeconds", recorded[1].ID)
+		assert.Equal(t, 90.0, recorded[1].Quantity)
+		assert.Equal(t, &testNow, job.MeteredAt)
+		m.jobs.AssertExpectations(t)
+	})
+
+	t.Run("Success_PrefersActuals", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		job := completedJob()
+		job.Actuals = &core.JobActuals{DurationSeconds: 75, Records: 640}
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.usage.On("RecordUsage", ctx, eventWith("job:job-1:recordsGenerated", 640)).Return(true, nil).Once()
+		m.usage.On("RecordUsage", ctx, eventWith("job:job-1:computeSeconds", 75)).Return(true, nil).Once()
+		m.jobs.On("MarkJobMetered", ctx, "job-1", testNow).Return(nil).Once()
+
+		require.NoError(t, meter.RecordJobUsage(ctx, job))
+		m.usage.AssertExpectations(t)
+	})
+
+	t.Run("Success_AlreadyRecordedEventsStillMarkJob", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.usage.On("RecordUsage", ctx, mock.Anything).Return(false, nil).Twice()
+		m.jobs.On("MarkJobMetered", ctx, "job-1", testNow).Return(nil).Once()
+
+		require.NoError(t, meter.RecordJobUsage(ctx, completedJob()))
+		m.jobs.AssertExpectations(t)
+	})
+
+	t.Run("Skip_AlreadyMeteredOrNotCompleted", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		metered := completedJob()
+		metered.MeteredAt = &testNow
+		failed := completedJob()
+		failed.Status = core.JobStatusFailed
+
+		require.NoError(t, meter.RecordJobUsage(ctx, metered))
+		require.NoError(t, meter.RecordJobUsage(ctx, failed))
+		m.usage.AssertNotCalled(t, "RecordUsage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_RecordLeavesJobUnmetered", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		job := completedJob()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.usage.On("RecordUsage", ctx, mock.Anything).Return(false, errors.New("unavailable")).Once()
+
+		err := meter.RecordJobUsage(ctx, job)
+
+		assert.Error(t, err)
+		assert.Nil(t, job.MeteredAt)
+		m.jobs.AssertNotCalled(t, "MarkJobMetered", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestMeter_TickOnce(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_ReconcilesJobsAndSamplesStorage", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		stored := &core.Project{ID: "proj-1", CustomerID: "cust-1", Storage: core.ProjectStorage{UsedStorageBytes: 2048}}
+		empty := &core.Project{ID: "proj-2", CustomerID: "cust-2"}
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-1", 2*time.Hour).Return(true, nil).Once()
+		m.projects.On("ListAllProjects", ctx).Return([]*core.Project{stored, empty}, nil).Once()
+		m.jobs.On("ListUnmeteredJobs", ctx, reconcileBatchSize).Return([]*core.Job{completedJob()}, nil).Once()
+		m.usage.On("RecordUsage", ctx, eventWith("job:job-1:recordsGenerated", 1000)).Return(true, nil).Once()
+		m.usage.On("RecordUsage", ctx, eventWith("job:job-1:computeSeconds", 90)).Return(true, nil).Once()
+		m.jobs.On("MarkJobMetered", ctx, "job-1", testNow).Return(nil).Once()
+		period := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+		m.usage.On("RecordUsage", ctx, mock.MatchedBy(func(e *core.UsageEvent) bool {
+			return e.ID == "storage:proj-1:1773136800" && e.Metric == core.UsageStorageByteHours &&
+				e.Quantity == 2048 && e.CustomerID == "cust-1" && e.OccurredAt.Equal(period)
+		})).Return(true, nil).Once()
+		m.projects.On("UpdateStorageSampledPeriod", ctx, "proj-1", period).Return(nil).Once()
+		m.projects.On("UpdateStorageSampledPeriod", ctx, "proj-2", period).Return(nil).Once()
+
+		res, err := meter.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, TickResult{JobsMetered: 1, StorageSamples: 1}, res)
+		m.usage.AssertExpectations(t)
+		m.projects.AssertExpectations(t)
+		m.projects.AssertNotCalled(t, "GetProjectByID", mock.Anything, mock.Anything) // Listed projects are reused
+	})
+
+	t.Run("Success_BackfillsMissedPeriods", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		period := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+		last := period.Add(-3 * time.Hour)
+		stored := &core.Project{ID: "proj-1", Storage: core.ProjectStorage{UsedStorageBytes: 2048, SampledPeriod: &last}}
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-1", 2*time.Hour).Return(true, nil).Once()
+		m.projects.On("ListAllProjects", ctx).Return([]*core.Project{stored}, nil).Once()
+		m.jobs.On("ListUnmeteredJobs", ctx, reconcileBatchSize).Return([]*core.Job{}, nil).Once()
+		m.usage.On("RecordUsage", ctx, eventWith("storage:proj-1:1773129600", 2048)).Return(true, nil).Once()
+		m.usage.On("RecordUsage", ctx, eventWith("storage:proj-1:1773133200", 2048)).Return(false, nil).Once() // Recorded before a lease handoff
+		m.usage.On("RecordUsage", ctx, eventWith("storage:proj-1:1773136800", 2048)).Return(true, nil).Once()
+		m.projects.On("UpdateStorageSampledPeriod", ctx, "proj-1", period).Return(nil).Once()
+
+		res, err := meter.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, TickResult{StorageSamples: 2}, res)
+		m.usage.AssertExpectations(t)
+		m.projects.AssertExpectations(t)
+	})
+
+	t.Run("Success_BackfillIsBoundedPerTick", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		last := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		stored := &core.Project{ID: "proj-1", Storage: core.ProjectStorage{UsedStorageBytes: 1, SampledPeriod: &last}}
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-1", 2*time.Hour).Return(true, nil).Once()
+		m.projects.On("ListAllProjects", ctx).Return([]*core.Project{stored}, nil).Once()
+		m.jobs.On("ListUnmeteredJobs", ctx, reconcileBatchSize).Return([]*core.Job{}, nil).Once()
+		m.usage.On("RecordUsage", ctx, mock.Anything).Return(true, nil).Times(backfillPeriods)
+		m.projects.On("UpdateStorageSampledPeriod", ctx, "proj-1", last.Add(backfillPeriods*time.Hour)).Return(nil).Once()
+
+		res, err := meter.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, TickResult{StorageSamples: backfillPeriods}, res)
+		m.projects.AssertExpectations(t)
+	})
+
+	t.Run("Success_PeriodAlreadySampled", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		period := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+		stored := &core.Project{ID: "proj-1", Storage: core.ProjectStorage{UsedStorageBytes: 2048, SampledPeriod: &period}}
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-1", 2*time.Hour).Return(true, nil).Once()
+		m.projects.On("ListAllProjects", ctx).Return([]*core.Project{stored}, nil).Once()
+		m.jobs.On("ListUnmeteredJobs", ctx, reconcileBatchSize).Return([]*core.Job{}, nil).Once()
+
+		res, err := meter.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, TickResult{}, res)
+		m.usage.AssertNotCalled(t, "RecordUsage", mock.Anything, mock.Anything)
+		m.projects.AssertNotCalled(t, "UpdateStorageSampledPeriod", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_StorageErrorKeepsSampledPeriod", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		last := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC)
+		stored := &core.Project{ID: "proj-1", Storage: core.ProjectStorage{UsedStorageBytes: 10, SampledPeriod: &last}}
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-1", 2*time.Hour).Return(true, nil).Once()
+		m.projects.On("ListAllProjects", ctx).Return([]*core.Project{stored}, nil).Once()
+		m.jobs.On("ListUnmeteredJobs", ctx, reconcileBatchSize).Return([]*core.Job{}, nil).Once()
+		m.usage.On("RecordUsage", ctx, eventWith("storage:proj-1:1773133200", 10)).Return(true, nil).Once()
+		m.usage.On("RecordUsage", ctx, eventWith("storage:proj-1:1773136800", 10)).Return(false, errors.New("unavailable")).Once()
+		m.projects.On("UpdateStorageSampledPeriod", ctx, "proj-1", last.Add(time.Hour)).Return(nil).Once()
+
+		res, err := meter.TickOnce(ctx)
+
+		assert.ErrorContains(t, err, "project proj-1")
+		assert.Equal(t, TickResult{StorageSamples: 1}, res)
+		m.projects.AssertExpectations(t)
+	})
+
+	t.Run("Skip_NotLeaseHolder", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-1", 2*time.Hour).Return(false, nil).Once()
+
+		res, err := meter.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, TickResult{}, res)
+		m.projects.AssertNotCalled(t, "ListAllProjects", mock.Anything)
+	})
+
+	t.Run("Failure_JobErrorDoesNotStopSampling", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		stored := &core.Project{ID: "proj-1", Storage: core.ProjectStorage{UsedStorageBytes: 10}}
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-1", 2*time.Hour).Return(true, nil).Once()
+		m.projects.On("ListAllProjects", ctx).Return([]*core.Project{stored}, nil).Once()
+		m.jobs.On("ListUnmeteredJobs", ctx, reconcileBatchSize).Return([]*core.Job{completedJob()}, nil).Once()
+		m.usage.On("RecordUsage", ctx, eventWith("job:job-1:recordsGenerated", 1000)).Return(false, errors.New("unavailable")).Once()
+		m.usage.On("RecordUsage", ctx, eventWith("storage:proj-1:1773136800", 10)).Return(true, nil).Once()
+		m.projects.On("UpdateStorageSampledPeriod", ctx, "proj-1", mock.Anything).Return(nil).Once()
+
+		res, err := meter.TickOnce(ctx)
+
+		assert.ErrorContains(t, err, "job job-1")
+		assert.Equal(t, TickResult{StorageSamples: 1}, res)
+	})
+}
+
+func TestMeter_RecordEgress(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		meter, m := setupMeterTest()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.usage.On("RecordUsage", ctx, mock.MatchedBy(func(e *core.UsageEvent) bool {
+			return e.Metric == core.UsageEgressBytes && e.Quantity == 512 && e.CustomerID == "owner-1" && e.ProjectID == "proj-1"
+		})).Return(true, nil).Once()
+
+		require.NoError(t, meter.RecordEgress(ctx, "proj-1", 512))
+		m.usage.AssertExpectations(t)
+	})
+
+	t.Run("Skip_Empty", func(t *testing.T) {
+		meter, m := setupMeterTest()
+
+		require.NoError(t, meter.RecordEgress(ctx, "proj-1", 0))
+		m.usage.AssertNotCalled(t, "RecordUsage", mock.Anything, mock.Anything)
+	})
+}
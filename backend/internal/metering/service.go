@@ -0,0 +1,227 @@
+package metering
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"SynDataGen/backend/internal/project"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrInvalidUsageQuery is returned when a usage report's range or grouping is invalid.
+var ErrInvalidUsageQuery = errors.New("invalid usage query")
+
+// GroupBy selects how a usage report groups events.
+type GroupBy string
+
+const (
+	GroupByProject GroupBy = "project"
+	GroupByDay     GroupBy = "day" // UTC calendar days
+	GroupByJobType GroupBy = "jobType"
+)
+
+const (
+	DefaultReportRange     = 30 * 24 * time.Hour  // Range reported when no start is given
+	maxReportRange         = 366 * 24 * time.Hour // Longest range a report may cover
+	reportProjectsPageSize = 100                  // Page size used when listing the caller's projects
+)
+
+// ProjectLister lists the projects a user is a member of. project.ProjectService satisfies
+// this interface.
+type ProjectLister interface {
+	ListProjects(ctx context.Context, userID string, statusFilter string, limit, offset int) (*project.ListProjectsResponse, error)
+}
+
+// ReportQuery selects the usage to report. Zero times default to the DefaultReportRange
+// ending now, and an empty GroupBy to GroupByProject.
+type ReportQuery struct {
+	From    time.Time
+	To      time.Time
+	GroupBy GroupBy
+}
+
+// UsageTotals sums the quantities of each metric.
+type UsageTotals struct {
+	RecordsGenerated float64 `json:"recordsGenerated"`
+	ComputeSeconds   float64 `json:"computeSeconds"`
+	StorageByteHours float64 `json:"storageByteHours"`
+	EgressBytes      float64 `json:"egressBytes"`
+}
+
+// UsageGroup is the usage of one project, day or job type.
+type UsageGroup struct {
+	Key         string `json:"key"`                   // Project ID, YYYY-MM-DD or job type; usage not tied to a job has an empty job type
+	ProjectName string `json:"projectName,omitempty"` // Set when grouping by project
+	CustomerID  string `json:"customerId,omitempty"`  // Set when grouping by project
+	UsageTotals
+}
+
+// CustomerUsage is the usage billed to one customer.
+type CustomerUsage struct {
+	CustomerID string `json:"customerId"`
+	UsageTotals
+}
+
+// UsageReport is the usage of a user's projects over a time range.
+type UsageReport struct {
+	From      time.Time       `json:"from"`
+	To        time.Time       `json:"to"`
+	GroupBy   GroupBy         `json:"groupBy"`
+	Groups    []UsageGroup    `json:"groups"`    // Ordered by key
+	Customers []CustomerUsage `json:"customers"` // Ordered by customer ID
+	Total     UsageTotals     `json:"total"`
+}
+
+// UsageService defines the interface for usage reports.
+type UsageService interface {
+	// GetUsageReport aggregates the usage of the projects the user owns or administers.
+	GetUsageReport(ctx context.Context, userID string, query ReportQuery) (*UsageReport, error)
+}
+
+// usageService implements the UsageService interface.
+type usageService struct {
+	usageRepo core.UsageRepository
+	projects  ProjectLister
+	now       func() time.Time // Overridable for tests
+}
+
+// NewUsageService creates a new usage service instance.
+func NewUsageService(usageRepo core.UsageRepository, projects ProjectLister) UsageService {
+	return &usageService{
+		usageRepo: usageRepo,
+		projects:  projects,
+		now:       func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// GetUsageReport aggregates usage events in [query.From, query.To).
+func (s *usageService) GetUsageReport(ctx context.Context, userID string, query ReportQuery) (*UsageReport, error) {
+	// 1. Validate Inputs
+	if query.To.IsZero() {
+		query.To = s.now()
+	}
+	if query.From.IsZero() {
+		query.From = query.To.Add(-DefaultReportRange)
+	}
+	if query.GroupBy == "" {
+		query.GroupBy = GroupByProject
+	}
+	switch query.GroupBy {
+	case GroupByProject, GroupByDay, GroupByJobType:
+	default:
+		return nil, fmt.Errorf("%w: groupBy must be project, day or jobType", ErrInvalidUsageQuery)
+	}
+	if !query.From.Before(query.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidUsageQuery)
+	}
+	if query.To.Sub(query.From) > maxReportRange {
+		return nil, fmt.Errorf("%w: range cannot exceed %d days", ErrInvalidUsageQuery, int(maxReportRange.Hours()/24))
+	}
+
+	// 2. Find the projects whose usage the user may see
+	projects, err := s.billableProjects(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	projectIDs := make([]string, 0, len(projects))
+	for id := range projects {
+		projectIDs = append(projectIDs, id)
+	}
+
+	// 3. Aggregate their events
+	events, err := s.usageRepo.ListUsage(ctx, projectIDs, query.From.UTC(), query.To.UTC())
+	if err != nil {
+		logger.Logger.Error("Failed to list usage events", zap.Error(err), zap.String("userID", userID))
+		return nil, fmt.Errorf("failed to list usage: %w", err)
+	}
+	return aggregate(query, events, projects), nil
+}
+
+// billableProjects returns the projects, by ID, where the user is an owner or admin.
+func (s *usageService) billableProjects(ctx context.Context, userID string) (map[string]*core.Project, error) {
+	projects := make(map[string]*core.Project)
+	for offset := 0; ; offset += reportProjectsPageSize {
+		resp, err := s.projects.ListProjects(ctx, userID, "", reportProjectsPageSize, offset)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list projects for usage report: %w", err)
+		}
+		for _, proj := range resp.Projects {
+			if role := proj.TeamMembers[userID]; role == core.RoleOwner || role == core.RoleAdmin {
+				projects[proj.ID] = proj
+			}
+		}
+		if len(resp.Projects) < reportProjectsPageSize {
+			return projects, nil
+		}
+	}
+}
+
+// aggregate sums events into the report's groups, customers and total.
+func aggregate(query ReportQuery, events []*core.UsageEvent, projects map[string]*core.Project) *UsageReport {
+	report := &UsageReport{
+		From:      query.From,
+		To:        query.To,
+		GroupBy:   query.GroupBy,
+		Groups:    []UsageGroup{},
+		Customers: []CustomerUsage{},
+	}
+	groups := make(map[string]*UsageGroup)
+	customers := make(map[string]*CustomerUsage)
+	for _, event := range events {
+		var key string
+		switch query.GroupBy {
+		case GroupByProject:
+			key = event.ProjectID
+		case GroupByDay:
+			key = event.OccurredAt.UTC().Format("2006-01-02")
+		case GroupByJobType:
+			key = event.JobType
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &UsageGroup{Key: key}
+			if proj := projects[key]; query.GroupBy == GroupByProject && proj != nil {
+				group.ProjectName = proj.Name
+				group.CustomerID = customerOf(proj)
+			}
+			groups[key] = group
+		}
+		customer, ok := customers[event.CustomerID]
+		if !ok {
+			customer = &CustomerUsage{CustomerID: event.CustomerID}
+			customers[event.CustomerID] = customer
+		}
+		group.add(event)
+		customer.add(event)
+		report.Total.add(event)
+	}
+
+	for _, group := range groups {
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool { return report.Groups[i].Key < report.Groups[j].Key })
+	for _, customer := range customers {
+		report.Customers = append(report.Customers, *customer)
+	}
+	sort.Slice(report.Customers, func(i, j int) bool { return report.Customers[i].CustomerID < report.Customers[j].CustomerID })
+	return report
+}
+
+// add counts an event's quantity towards its metric.
+func (t *UsageTotals) add(event *core.UsageEvent) {
+	switch event.Metric {
+	case core.UsageRecordsGenerated:
+		t.RecordsGenerated += event.Quantity
+	case core.UsageComputeSeconds:
+		t.ComputeSeconds += event.Quantity
+	case core.UsageStorageByteHours:
+		t.StorageByteHours += event.Quantity
+	case core.UsageEgressBytes:
+		t.EgressBytes += event.Quantity
+	}
+}
@@ -0,0 +1,139 @@
+package metering
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/project"
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockProjectLister is a mock implementation of ProjectLister.
+type MockProjectLister struct {
+	mock.Mock
+}
+
+func (m *MockProjectLister) ListProjects(ctx context.Context, userID string, statusFilter string, limit, offset int) (*project.ListProjectsResponse, error) {
+	args := m.Called(ctx, userID, statusFilter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.ListProjectsResponse), args.Error(1)
+}
+
+func setupUsageServiceTest() (*usageService, *MockUsageRepository, *MockProjectLister) {
+	usageRepo := new(MockUsageRepository)
+	projects := new(MockProjectLister)
+	svc := NewUsageService(usageRepo, projects).(*usageService)
+	svc.now = func() time.Time { return testNow }
+	return svc, usageRepo, projects
+}
+
+func TestUsageService_GetUsageReport(t *testing.T) {
+	ctx := context.Background()
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)
+	day1 := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+
+	owned := &core.Project{ID: "proj-1", Name: "Owned", CustomerID: "cust-1", TeamMembers: map[string]core.Role{"user-1": core.RoleOwner}}
+	administered := &core.Project{ID: "proj-2", Name: "Administered", CustomerID: "cust-2", TeamMembers: map[string]core.Role{"user-1": core.RoleAdmin}}
+	viewed := &core.Project{ID: "proj-3", Name: "Viewed", TeamMembers: map[string]core.Role{"user-1": core.RoleViewer}}
+	events := []*core.UsageEvent{
+		{ProjectID: "proj-1", CustomerID: "cust-1", JobType: "csv", Metric: core.UsageRecordsGenerated, Quantity: 100, OccurredAt: day1},
+		{ProjectID: "proj-1", CustomerID: "cust-1", JobType: "csv", Metric: core.UsageComputeSeconds, Quantity: 30, OccurredAt: day1},
+		{ProjectID: "proj-1", CustomerID: "cust-1", Metric: core.UsageStorageByteHours, Quantity: 4096, OccurredAt: day2},
+		{ProjectID: "proj-2", CustomerID: "cust-2", JobType: "json", Metric: core.UsageRecordsGenerated, Quantity: 50, OccurredAt: day2},
+		{ProjectID: "proj-2", CustomerID: "cust-2", Metric: core.UsageEgressBytes, Quantity: 512, OccurredAt: day2},
+	}
+	listProjects := func(projects *MockProjectLister) {
+		projects.On("ListProjects", ctx, "user-1", "", reportProjectsPageSize, 0).
+			Return(&project.ListProjectsResponse{Projects: []*core.Project{owned, administered, viewed}}, nil).Once()
+	}
+	billable := mock.MatchedBy(func(ids []string) bool {
+		sorted := append([]string(nil), ids...)
+		sort.Strings(sorted)
+		return reflect.DeepEqual([]string{"proj-1", "proj-2"}, sorted) // Viewers do not see usage
+	})
+
+	t.Run("Success_GroupByProject", func(t *testing.T) {
+		svc, usageRepo, projects := setupUsageServiceTest()
+		listProjects(projects)
+		usageRepo.On("ListUsage", ctx, billable, from, to).Return(events, nil).Once()
+
+		report, err := svc.GetUsageReport(ctx, "user-1", ReportQuery{From: from, To: to})
+
+		require.NoError(t, err)
+		assert.Equal(t, GroupByProject, report.GroupBy)
+		assert.Equal(t, []UsageGroup{
+			{Key: "proj-1", ProjectName: "Owned", CustomerID: "cust-1", UsageTotals: UsageTotals{RecordsGenerated: 100, ComputeSeconds: 30, StorageByteHours: 4096}},
+			{Key: "proj-2", ProjectName: "Administered", CustomerID: "cust-2", UsageTotals: UsageTotals{RecordsGenerated: 50, EgressBytes: 512}},
+		}, report.Groups)
+		assert.Equal(t, []CustomerUsage{
+			{CustomerID: "cust-1", UsageTotals: UsageTotals{RecordsGenerated: 100, ComputeSeconds: 30, StorageByteHours: 4096}},
+			{CustomerID: "cust-2", UsageTotals: UsageTotals{RecordsGenerated: 50, EgressBytes: 512}},
+		}, report.Customers)
+		assert.Equal(t, UsageTotals{RecordsGenerated: 150, ComputeSeconds: 30, StorageByteHours: 4096, EgressBytes: 512}, report.Total)
+	})
+
+	t.Run("Success_GroupByDay", func(t *testing.T) {
+		svc, usageRepo, projects := setupUsageServiceTest()
+		listProjects(projects)
+		usageRepo.On("ListUsage", ctx, billable, from, to).Return(events, nil).Once()
+
+		report, err := svc.GetUsageReport(ctx, "user-1", ReportQuery{From: from, To: to, GroupBy: GroupByDay})
+
+		require.NoError(t, err)
+		require.Len(t, report.Groups, 2)
+		assert.Equal(t, UsageGroup{Key: "2026-03-01", UsageTotals: UsageTotals{RecordsGenerated: 100, ComputeSeconds: 30}}, report.Groups[0])
+		assert.Equal(t, UsageGroup{Key: "2026-03-02", UsageTotals: UsageTotals{RecordsGenerated: 50, StorageByteHours: 4096, EgressBytes: 512}}, report.Groups[1])
+	})
+
+	t.Run("Success_GroupByJobType", func(t *testing.T) {
+		svc, usageRepo, projects := setupUsageServiceTest()
+		listProjects(projects)
+		usageRepo.On("ListUsage", ctx, billable, from, to).Return(events, nil).Once()
+
+		report, err := svc.GetUsageReport(ctx, "user-1", ReportQuery{From: from, To: to, GroupBy: GroupByJobType})
+
+		require.NoError(t, err)
+		keys := []string{}
+		for _, group := range report.Groups {
+			keys = append(keys, group.Key)
+		}
+		assert.Equal(t, []string{"", "csv", "json"}, keys) // Storage and egress are not tied to a job
+		assert.Equal(t, 4096.0, report.Groups[0].StorageByteHours)
+	})
+
+	t.Run("Success_DefaultRange", func(t *testing.T) {
+		svc, usageRepo, projects := setupUsageServiceTest()
+		listProjects(projects)
+		usageRepo.On("ListUsage", ctx, billable, testNow.Add(-DefaultReportRange), testNow).Return([]*core.UsageEvent{}, nil).Once()
+
+		report, err := svc.GetUsageReport(ctx, "user-1", ReportQuery{})
+
+		require.NoError(t, err)
+		assert.Empty(t, report.Groups)
+		assert.Equal(t, testNow, report.To)
+	})
+
+	t.Run("Failure_InvalidQuery", func(t *testing.T) {
+		svc, _, projects := setupUsageServiceTest()
+
+		for _, query := range []ReportQuery{
+			{From: from, To: to, GroupBy: "customer"},
+			{From: to, To: from},
+			{From: from, To: from.AddDate(2, 0, 0)},
+		} {
+			_, err := svc.GetUsageReport(ctx, "user-1", query)
+			assert.ErrorIs(t, err, ErrInvalidUsageQuery)
+		}
+		projects.AssertNotCalled(t, "ListProjects", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
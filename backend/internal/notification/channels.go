@@ -0,0 +1,151 @@
+package notification
+
+import (
+	"SynDataGen/backend/internal/core"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers of webhook requests. Receivers verify a request by recomputing the signature
+// with Sign from the timestamp header and the raw body.
+const (
+	SignatureHeader = "X-SynDataGen-Signature" // "sha256=" and the hex HMAC-SHA256 of "<timestamp>.<body>"
+	TimestampHeader = "X-SynDataGen-Timestamp" // Unix seconds when the request was signed
+	DeliveryHeader  = "X-SynDataGen-Delivery"  // Delivery ID; the same for every retry
+	EventHeader     = "X-SynDataGen-Event"
+)
+
+// EventTest is the event of messages sent from the test endpoint.
+const EventTest = "notification.test"
+
+// DefaultHTTPTimeout bounds each webhook request when no client is configured.
+const DefaultHTTPTimeout = 10 * time.Second
+
+// JobSummary describes the job a notification is about.
+type JobSummary struct {
+	ID          string         `json:"id"`
+	ProjectID   string         `json:"projectId"`
+	ProjectName string         `json:"projectName,omitempty"`
+	JobType     string         `json:"jobType"`
+	Status      core.JobStatus `json:"status"`
+	Error       string         `json:"error,omitempty"`
+	ResultURI   string         `json:"resultUri,omitempty"`
+	CreatedAt   time.Time      `json:"createdAt"`
+	CompletedAt *time.Time     `json:"completedAt,omitempty"`
+	Attempt     int            `json:"attempt,omitempty"` // Above 1 for automatic retries
+}
+
+// Message is a notification rendered for sending. Webhooks receive it as JSON.
+type Message struct {
+	DeliveryID string      `json:"id"`
+	Event      string      `json:"event"` // job.completed, job.failed, job.cancelled or notification.test
+	Subject    string      `json:"subject"`
+	Text       string      `json:"text"` // Plain-text body
+	Job        *JobSummary `json:"job,omitempty"`
+	OccurredAt time.Time   `json:"occurredAt"`
+}
+
+// Sender delivers messages to channels of one type.
+type Sender interface {
+	Send(ctx context.Context, channel core.NotificationChannel, msg Message) error
+}
+
+// Channels maps each supported channel type to its sender.
+type Channels map[core.NotificationChannelType]Sender
+
+// NewChannels returns the built-in senders: webhooks and Slack through client, and email
+// through mailer if one is configured. A nil client uses DefaultHTTPTimeout.
+func NewChannels(mailer Mailer, client *http.Client) Channels {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+	channels := Channels{
+		core.NotificationChannelWebhook: &webhookSender{client: client},
+		core.NotificationChannelSlack:   &slackSender{client: client},
+	}
+	if mailer != nil {
+		channels[core.NotificationChannelEmail] = &emailSender{mailer: mailer}
+	}
+	return channels
+}
+
+// emailSender sends messages as plain-text email.
+type emailSender struct {
+	mailer Mailer
+}
+
+func (s *emailSender) Send(ctx context.Context, channel core.NotificationChannel, msg Message) error {
+	return s.mailer.SendMail(ctx, []string{channel.Target}, msg.Subject, msg.Text)
+}
+
+// webhookSender POSTs messages as signed JSON.
+type webhookSender struct {
+	client *http.Client
+}
+
+func (s *webhookSender) Send(ctx context.Context, channel core.NotificationChannel, msg Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	return postJSON(ctx, s.client, channel.Target, body, map[string]string{
+		SignatureHeader: Sign(channel.Secret, timestamp, body),
+		TimestampHeader: strconv.FormatInt(timestamp, 10),
+		DeliveryHeader:  msg.DeliveryID,
+		EventHeader:     msg.Event,
+	})
+}
+
+// Sign returns the signature of a webhook body sent at timestamp (Unix seconds).
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// slackSender posts messages to Slack-compatible incoming webhooks.
+type slackSender struct {
+	client *http.Client
+}
+
+func (s *slackSender) Send(ctx context.Context, channel core.NotificationChannel, msg Message) error {
+	body, err := json.Marshal(map[string]string{"text": "*" + msg.Subject + "*\n" + msg.Text})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack payload: %w", err)
+	}
+	return postJSON(ctx, s.client, channel.Target, body, nil)
+}
+
+// postJSON POSTs body to url, treating any non-2xx response as a failure.
+func postJSON(ctx context.Context, client *http.Client, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SynDataGen-Notifications")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10)) // Drain so the connection is reused
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
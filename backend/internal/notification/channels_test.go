@@ -0,0 +1,155 @@
+package notification
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockMailer is a mock implementation of Mailer.
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) SendMail(ctx context.Context, to []string, subject, body string) error {
+	args := m.Called(ctx, to, subject, body)
+	return args.Error(0)
+}
+
+// capturedRequest is a request received by a test endpoint.
+type capturedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// testEndpoint starts an HTTPS server that records requests and responds with status.
+func testEndpoint(t *testing.T, status int) (*httptest.Server, *[]capturedRequest) {
+	var received []capturedRequest
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, capturedRequest{header: r.Header.Clone(), body: body})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+var testMessage = Message{
+	DeliveryID: "job-1:failed:project-proj-1:0",
+	Event:      "job.failed",
+	Subject:    "Job job-1 failed in Demo",
+	Text:       "Status: failed\n",
+	Job:        &JobSummary{ID: "job-1", ProjectID: "proj-1", Status: core.JobStatusFailed},
+	OccurredAt: testNow,
+}
+
+func TestChannels_Webhook(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_SignsPayload", func(t *testing.T) {
+		server, received := testEndpoint(t, http.StatusNoContent)
+		channels := NewChannels(nil, server.Client())
+		channel := core.NotificationChannel{Type: core.NotificationChannelWebhook, Target: server.URL, Secret: "s3cret"}
+
+		err := channels[core.NotificationChannelWebhook].Send(ctx, channel, testMessage)
+
+		require.NoError(t, err)
+		require.Len(t, *received, 1)
+		req := (*received)[0]
+		timestamp, err := strconv.ParseInt(req.header.Get(TimestampHeader), 10, 64)
+		require.NoError(t, err)
+		assert.Equal(t, Sign("s3cret", timestamp, req.body), req.header.Get(SignatureHeader))
+		assert.Equal(t, "job.failed", req.header.Get(EventHeader))
+		assert.Equal(t, testMessage.DeliveryID, req.header.Get(DeliveryHeader))
+		var payload Message
+		require.NoError(t, json.Unmarshal(req.body, &payload))
+		assert.Equal(t, testMessage, payload)
+	})
+
+	t.Run("Failure_NonSuccessStatus", func(t *testing.T) {
+		server, _ := testEndpoint(t, http.StatusInternalServerError)
+		channels := NewChannels(nil, server.Client())
+		channel := core.NotificationChannel{Type: core.NotificationChannelWebhook, Target: server.URL, Secret: "s3cret"}
+
+		err := channels[core.NotificationChannelWebhook].Send(ctx, channel, testMessage)
+
+		assert.EqualError(t, err, "endpoint responded with status 500")
+	})
+}
+
+func TestSign(t *testing.T) {
+	// Known answer: HMAC-SHA256 keyed "secret" over `1700000000.{"a":1}`
+	assert.Equal(t,
+		"sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686",
+		Sign("secret", 1700000000, []byte(`{"a":1}`)),
+	)
+}
+
+func TestChannels_Slack(t *testing.T) {
+	server, received := testEndpoint(t, http.StatusOK)
+	channels := NewChannels(nil, server.Client())
+
+	err := channels[core.NotificationChannelSlack].Send(context.Background(), core.NotificationChannel{Type: core.NotificationChannelSlack, Target: server.URL}, testMessage)
+
+	require.NoError(t, err)
+	require.Len(t, *received, 1)
+	assert.JSONEq(t, `{"text":"*Job job-1 failed in Demo*\nStatus: failed\n"}`, string((*received)[0].body))
+	assert.Empty(t, (*received)[0].header.Get(SignatureHeader))
+}
+
+func TestChannels_Email(t *testing.T) {
+	ctx := context.Background()
+	assert.NotContains(t, NewChannels(nil, nil), core.NotificationChannelEmail) // Email needs a mailer
+
+	mailer := new(MockMailer)
+	mailer.On("SendMail", ctx, []string{"user@example.com"}, testMessage.Subject, testMessage.Text).Return(nil).Once()
+	channels := NewChannels(mailer, nil)
+
+	err := channels[core.NotificationChannelEmail].Send(ctx, core.NotificationChannel{Type: core.NotificationChannelEmail, Target: "user@example.com"}, testMessage)
+
+	require.NoError(t, err)
+	mailer.AssertExpectations(t)
+}
+
+func TestSMTPMailer_SendMail(t *testing.T) {
+	_, err := NewSMTPMailer(SMTPConfig{Host: "smtp.example.com", From: "not an address"})
+	assert.Error(t, err)
+
+	mailer, err := NewSMTPMailer(SMTPConfig{Host: "smtp.example.com", Username: "apikey", Password: "pw", From: "SynDataGen <noreply@example.com>"})
+	require.NoError(t, err)
+	var addr, from string
+	var to []string
+	var msg []byte
+	mailer.send = func(a string, auth smtp.Auth, f string, t []string, m []byte) error {
+		addr, from, to, msg = a, f, t, m
+		return nil
+	}
+
+	err = mailer.SendMail(context.Background(), []string{"user@example.com"}, "Job failed\r\nBcc: evil@example.com", "line one\nline two")
+
+	require.NoError(t, err)
+	assert.Equal(t, "smtp.example.com:587", addr)
+	assert.Equal(t, "noreply@example.com", from) // The envelope takes the bare address
+	assert.Contains(t, string(msg), "From: \"SynDataGen\" <noreply@example.com>\r\n")
+	assert.Equal(t, []string{"user@example.com"}, to)
+	headers, body, found := strings.Cut(string(msg), "\r\n\r\n")
+	require.True(t, found)
+	assert.Contains(t, headers, "Subject: Job failed  Bcc: evil@example.com\r\n") // Newlines cannot inject headers
+	assert.NotContains(t, headers, "\r\nBcc:")
+	assert.Contains(t, headers, "To: user@example.com\r\n")
+	assert.Equal(t, "line one\r\nline two", body)
+	_, err = time.Parse(time.RFC1123Z, strings.TrimPrefix(strings.Split(headers, "\r\n")[3], "Date: "))
+	assert.NoError(t, err)
+}
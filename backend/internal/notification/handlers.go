@@ -0,0 +1,171 @@
+package notification
+
+import (
+	"SynDataGen/backend/internal/access"
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// PreferenceRequest defines the JSON body for replacing a notification preference.
+type PreferenceRequest struct {
+	Enabled  *bool            `json:"enabled"`  // Defaults to true
+	Statuses []core.JobStatus `json:"statuses"` // Final statuses to notify; defaults to completed and failed
+	Channels []ChannelRequest `json:"channels" binding:"dive"`
+}
+
+// ChannelRequest defines one channel of a PreferenceRequest.
+type ChannelRequest struct {
+	Type   core.NotificationChannelType `json:"type" binding:"required"`
+	Target string                       `json:"target"`           // Email address or https URL; a user's email defaults to their address
+	Secret string                       `json:"secret,omitempty"` // Webhook signing key; omit to keep the current one for the same URL
+}
+
+// NotificationHandler handles HTTP requests for notification preferences.
+type NotificationHandler struct {
+	service NotificationService
+}
+
+// NewNotificationHandler creates a new NotificationHandler.
+func NewNotificationHandler(s NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: s}
+}
+
+// RegisterRoutes registers the caller's and projects' notification routes with the Gin
+// router group.
+func (h *NotificationHandler) RegisterRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	for _, path := range []string{"/notifications", "/projects/:projectId/notifications"} {
+		notifications := rg.Group(path)
+		notifications.Use(authMiddleware)
+		{
+			notifications.GET("/preferences", h.GetPreference)       // GET /api/v1[/projects/:projectId]/notifications/preferences
+			notifications.PUT("/preferences", h.UpdatePreference)    // PUT /api/v1[/projects/:projectId]/notifications/preferences
+			notifications.DELETE("/preferences", h.DeletePreference) // DELETE /api/v1[/projects/:projectId]/notifications/preferences
+			notifications.POST("/preferences/test", h.SendTest)      // POST /api/v1[/projects/:projectId]/notifications/preferences/test
+			notifications.GET("/deliveries", h.ListDeliveries)       // GET /api/v1[/projects/:projectId]/notifications/deliveries
+		}
+	}
+}
+
+// errorCodes maps this package's errors to responses.
+var errorCodes = []access.ErrorCode{
+	{Err: ErrInvalidPreference, Status: http.StatusBadRequest, Code: "INVALID_NOTIFICATION_PREFERENCE"},
+}
+
+// abortWithError maps service errors to responses.
+func abortWithError(c *gin.Context, err error, fallback string) {
+	access.AbortWithError(c, err, "Notification preference or project not found", fallback, errorCodes...)
+}
+
+// GetPreference handles GET [/projects/:projectId]/notifications/preferences requests.
+func (h *NotificationHandler) GetPreference(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "GetPreference")
+	if !ok {
+		return
+	}
+
+	pref, err := h.service.GetPreference(c.Request.Context(), userID, projectID)
+	if err != nil {
+		logger.Logger.Error("Failed to get notification preference via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to get notification preference")
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// UpdatePreference handles PUT [/projects/:projectId]/notifications/preferences requests.
+func (h *NotificationHandler) UpdatePreference(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "UpdatePreference")
+	if !ok {
+		return
+	}
+
+	var req PreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Logger.Warn("Invalid request body for UpdatePreference", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	pref, err := h.service.UpdatePreference(c.Request.Context(), userID, projectID, req)
+	if err != nil {
+		logger.Logger.Error("Failed to update notification preference via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to update notification preference")
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// DeletePreference handles DELETE [/projects/:projectId]/notifications/preferences requests.
+func (h *NotificationHandler) DeletePreference(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "DeletePreference")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeletePreference(c.Request.Context(), userID, projectID); err != nil {
+		logger.Logger.Error("Failed to delete notification preference via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to delete notification preference")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SendTest handles POST [/projects/:projectId]/notifications/preferences/test requests.
+func (h *NotificationHandler) SendTest(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "SendTest")
+	if !ok {
+		return
+	}
+
+	deliveries, err := h.service.SendTest(c.Request.Context(), userID, projectID)
+	if err != nil {
+		logger.Logger.Error("Failed to send test notification via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to send test notification")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
+
+// ListDeliveries handles GET [/projects/:projectId]/notifications/deliveries requests.
+func (h *NotificationHandler) ListDeliveries(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "ListDeliveries")
+	if !ok {
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "20")
+	offsetStr := c.DefaultQuery("offset", "0")
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid 'limit' query parameter"})
+		return
+	}
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid 'offset' query parameter"})
+		return
+	}
+
+	deliveries, total, err := h.service.ListDeliveries(c.Request.Context(), userID, projectID, limit, offset)
+	if err != nil {
+		logger.Logger.Error("Failed to list notification deliveries via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to list notification deliveries")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"deliveries": deliveries,
+		"total":      total,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
@@ -0,0 +1,203 @@
+package notification
+
+import (
+	"SynDataGen/backend/internal/auth"
+	"SynDataGen/backend/internal/core"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockNotificationService is a mock implementation of NotificationService.
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) GetPreference(ctx context.Context, userID, projectID string) (*core.NotificationPreference, error) {
+	args := m.Called(ctx, userID, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationService) UpdatePreference(ctx context.Context, userID, projectID string, req PreferenceRequest) (*core.NotificationPreference, error) {
+	args := m.Called(ctx, userID, projectID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationService) DeletePreference(ctx context.Context, userID, projectID string) error {
+	args := m.Called(ctx, userID, projectID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationService) ListDeliveries(ctx context.Context, userID, projectID string, limit, offset int) ([]*core.NotificationDelivery, int, error) {
+	args := m.Called(ctx, userID, projectID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.NotificationDelivery), args.Int(1), args.Error(2)
+}
+
+func (m *MockNotificationService) SendTest(ctx context.Context, userID, projectID string) ([]*core.NotificationDelivery, error) {
+	args := m.Called(ctx, userID, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.NotificationDelivery), args.Error(1)
+}
+
+func setupGinTestRouter() (*gin.Engine, *MockNotificationService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockService := new(MockNotificationService)
+	mockAuthMiddleware := func(c *gin.Context) {
+		if userID := c.GetHeader("X-User-ID"); userID != "" {
+			c.Set(auth.UserIDKey, userID)
+		}
+		c.Next()
+	}
+	NewNotificationHandler(mockService).RegisterRoutes(router.Group("/"), mockAuthMiddleware)
+	return router, mockService
+}
+
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "user-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestNotificationHandler_GetPreference(t *testing.T) {
+	t.Run("Success_UserPreferenceHidesSecrets", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("GetPreference", mock.Anything, "user-1", "").Return(&core.NotificationPreference{
+			ID: "user-user-1", UserID: "user-1", Enabled: true, Channels: []core.NotificationChannel{webhook},
+		}, nil).Once()
+
+		w := serve(router, http.MethodGet, "/notifications/preferences", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"target":"https://hooks.example.com/a"`)
+		assert.NotContains(t, w.Body.String(), "s3cret")
+	})
+
+	t.Run("Failure_Forbidden", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("GetPreference", mock.Anything, "user-1", "proj-1").Return(nil, fmt.Errorf("%w: insufficient role", core.ErrForbidden)).Once()
+
+		w := serve(router, http.MethodGet, "/projects/proj-1/notifications/preferences", "")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestNotificationHandler_UpdatePreference(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		req := PreferenceRequest{
+			Statuses: []core.JobStatus{core.JobStatusFailed},
+			Channels: []ChannelRequest{{Type: core.NotificationChannelSlack, Target: "https://hooks.slack.com/x"}},
+		}
+		mockService.On("UpdatePreference", mock.Anything, "user-1", "proj-1", req).Return(&core.NotificationPreference{ID: "project-proj-1"}, nil).Once()
+
+		w := serve(router, http.MethodPut, "/projects/proj-1/notifications/preferences",
+			`{"statuses":["failed"],"channels":[{"type":"slack","target":"https://hooks.slack.com/x"}]}`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure_InvalidPreference", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("UpdatePreference", mock.Anything, "user-1", "", mock.Anything).
+			Return(nil, fmt.Errorf("%w: channels[0]: target must be an https URL", ErrInvalidPreference)).Once()
+
+		w := serve(router, http.MethodPut, "/notifications/preferences", `{"channels":[{"type":"slack","target":"http://x"}]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_NOTIFICATION_PREFERENCE")
+	})
+
+	t.Run("Failure_MissingChannelType", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+
+		w := serve(router, http.MethodPut, "/notifications/preferences", `{"channels":[{"target":"https://x"}]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "UpdatePreference", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_ArchivedProject", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("UpdatePreference", mock.Anything, "user-1", "proj-1", mock.Anything).Return(nil, core.ErrProjectArchived).Once()
+
+		w := serve(router, http.MethodPut, "/projects/proj-1/notifications/preferences", `{}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestNotificationHandler_DeletePreference(t *testing.T) {
+	router, mockService := setupGinTestRouter()
+	mockService.On("DeletePreference", mock.Anything, "user-1", "").Return(nil).Once()
+	mockService.On("DeletePreference", mock.Anything, "user-1", "proj-1").Return(fmt.Errorf("failed: %w", core.ErrNotFound)).Once()
+
+	assert.Equal(t, http.StatusNoContent, serve(router, http.MethodDelete, "/notifications/preferences", "").Code)
+	assert.Equal(t, http.StatusNotFound, serve(router, http.MethodDelete, "/projects/proj-1/notifications/preferences", "").Code)
+}
+
+func TestNotificationHandler_SendTest(t *testing.T) {
+	router, mockService := setupGinTestRouter()
+	mockService.On("SendTest", mock.Anything, "user-1", "").Return([]*core.NotificationDelivery{
+		{ID: "d-1", Test: true, Channel: webhook, Status: core.NotificationDeliveryFailed, Attempts: 1, LastError: "request failed"},
+	}, nil).Once()
+
+	w := serve(router, http.MethodPost, "/notifications/preferences/test", "")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"lastError":"request failed"`)
+	assert.NotContains(t, w.Body.String(), "s3cret")
+}
+
+func TestNotificationHandler_ListDeliveries(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("ListDeliveries", mock.Anything, "user-1", "proj-1", 5, 10).Return([]*core.NotificationDelivery{pendingDelivery(1)}, 11, nil).Once()
+
+		w := serve(router, http.MethodGet, "/projects/proj-1/notifications/deliveries?limit=5&offset=10", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"total":11`)
+	})
+
+	t.Run("Failure_InvalidLimit", func(t *testing.T) {
+		router, _ := setupGinTestRouter()
+
+		w := serve(router, http.MethodGet, "/notifications/deliveries?limit=-1", "")
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Failure_ServiceError", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("ListDeliveries", mock.Anything, "user-1", "", 20, 0).Return(nil, 0, errors.New("unavailable")).Once()
+
+		w := serve(router, http.MethodGet, "/notifications/deliveries", "")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
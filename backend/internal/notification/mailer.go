@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mailer sends plain-text email.
+type Mailer interface {
+	SendMail(ctx context.Context, to []string, subject, body string) error
+}
+
+// SMTPConfig holds configuration for an SMTPMailer.
+type SMTPConfig struct {
+	Host     string // Required
+	Port     int    // Defaults to 587
+	Username string // Authenticates with PLAIN when set
+	Password string
+	From     string // Sender address; required
+}
+
+// SMTPMailer sends email through an SMTP server, using STARTTLS when the server offers it.
+type SMTPMailer struct {
+	addr   string
+	auth   smtp.Auth
+	from   string // From header, which may include a display name
+	sender string // Envelope sender address
+
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error // Overridable for tests
+}
+
+// NewSMTPMailer creates a new SMTPMailer.
+func NewSMTPMailer(cfg SMTPConfig) (*SMTPMailer, error) {
+	if cfg.Host == "" {
+		return nil, errors.New("SMTP host is required")
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", cfg.From, err)
+	}
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+	m := &SMTPMailer{
+		addr:   net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		from:   from.String(),
+		sender: from.Address,
+		send:   smtp.SendMail,
+	}
+	if cfg.Username != "" {
+		m.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return m, nil
+}
+
+// SendMail sends one message to every recipient. net/smtp cannot be cancelled, so ctx is
+// only checked before sending.
+func (m *SMTPMailer) SendMail(ctx context.Context, to []string, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", stripNewlines(subject)))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	if err := m.send(m.addr, m.auth, m.sender, to, msg.Bytes()); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// stripNewlines keeps a header value on one line.
+func stripNewlines(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(s)
+}
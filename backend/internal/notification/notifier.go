@@ -0,0 +1,355 @@
+package notification
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/lease"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Defaults for NotifierConfig.
+const (
+	DefaultDispatchInterval = 15 * time.Second
+	DefaultMaxAttempts      = 5
+	DefaultRetryBackoff     = time.Minute // Doubles after each failed attempt
+)
+
+const (
+	leaseName         = "notifications" // Lease held by the replica that sends deliveries
+	dispatchBatchSize = 100             // Deliveries sent per tick at most; the rest wait for the next
+)
+
+// defaultStatuses are notified by preferences that do not list statuses.
+var defaultStatuses = []core.JobStatus{core.JobStatusCompleted, core.JobStatusFailed}
+
+// NotifierConfig holds configuration for the Notifier.
+type NotifierConfig struct {
+	Interval     time.Duration // Time between dispatch ticks; defaults to DefaultDispatchInterval
+	HolderID     string        // Identifies this replica in the lease; required
+	MaxAttempts  int           // Attempts per delivery including the first; defaults to DefaultMaxAttempts
+	RetryBackoff time.Duration // Wait after the first failed attempt; defaults to DefaultRetryBackoff
+}
+
+// DispatchResult summarizes a single dispatch tick.
+type DispatchResult struct {
+	Delivered int
+	Retrying  int // Failed attempts that will be retried
+	Failed    int // Deliveries given up on
+}
+
+// Notifier turns finished jobs into notification deliveries and sends them.
+//
+// When a job reaches a final status, the project's preference and its creator's preference
+// each queue one delivery per channel. A delivery's ID is derived from the job, status,
+// preference and channel, so observing the same transition twice queues nothing new. The
+// replica holding the notifications lease sends due deliveries, retrying failures with
+// exponential backoff until MaxAttempts is reached.
+type Notifier struct {
+	notificationRepo core.NotificationRepository
+	jobRepo          core.JobRepository
+	projectRepo      core.ProjectRepository
+	channels         Channels
+	runner           *lease.Runner
+	maxAttempts      int
+	retryBackoff     time.Duration
+	now              func() time.Time // Overridable for tests
+}
+
+// NewNotifier creates a new Notifier.
+func NewNotifier(notificationRepo core.NotificationRepository, jobRepo core.JobRepository, projectRepo core.ProjectRepository, leaseRepo core.LeaseRepository, channels Channels, cfg NotifierConfig) *Notifier {
+	if notificationRepo == nil || jobRepo == nil || projectRepo == nil || leaseRepo == nil || channels == nil {
+		panic("notification.NewNotifier: all dependencies are required")
+	}
+	if cfg.HolderID == "" {
+		panic("notification.NewNotifier: HolderID is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultDispatchInterval
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = DefaultRetryBackoff
+	}
+	return &Notifier{
+		notificationRepo: notificationRepo,
+		jobRepo:          jobRepo,
+		projectRepo:      projectRepo,
+		channels:         channels,
+		runner:           lease.NewRunner(leaseRepo, leaseName, cfg.HolderID, cfg.Interval),
+		maxAttempts:      cfg.MaxAttempts,
+		retryBackoff:     cfg.RetryBackoff,
+		now:              func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// ProjectPreferenceID returns the ID of a project's notification preference.
+func ProjectPreferenceID(projectID string) string {
+	return "project-" + projectID
+}
+
+// UserPreferenceID returns the ID of a user's notification preference.
+func UserPreferenceID(userID string) string {
+	return "user-" + userID
+}
+
+// NotifyJobStatus queues the deliveries for a job that reached a final status. The job's
+// creator is only notified while they are still a member of its project.
+func (n *Notifier) NotifyJobStatus(ctx context.Context, job *core.Job) error {
+	// 1. Find the preferences covering the job
+	preferenceIDs := []string{ProjectPreferenceID(job.ProjectID)}
+	if job.UserID != "" {
+		proj, err := n.projectRepo.GetProjectByID(ctx, job.ProjectID)
+		if err != nil && !errors.Is(err, core.ErrNotFound) {
+			return fmt.Errorf("failed to get project %s: %w", job.ProjectID, err)
+		}
+		if proj != nil {
+			if _, member := proj.TeamMembers[job.UserID]; member {
+				preferenceIDs = append(preferenceIDs, UserPreferenceID(job.UserID))
+			}
+		}
+	}
+
+	// 2. Queue one delivery per channel of each preference that wants this status
+	var errs []error
+	for _, preferenceID := range preferenceIDs {
+		pref, err := n.notificationRepo.GetPreference(ctx, preferenceID)
+		if errors.Is(err, core.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("preference %s: %w", preferenceID, err))
+			continue
+		}
+		if !wantsStatus(pref, job.Status) {
+			continue
+		}
+		now := n.now()
+		for i, channel := range pref.Channels {
+			delivery := &core.NotificationDelivery{
+				ID:            strings.Join([]string{job.ID, string(job.Status), pref.ID, strconv.Itoa(i)}, ":"),
+				PreferenceID:  pref.ID,
+				ProjectID:     job.ProjectID,
+				JobID:         job.ID,
+				JobStatus:     job.Status,
+				Channel:       channel,
+				Status:        core.NotificationDeliveryPending,
+				CreatedAt:     now,
+				NextAttemptAt: &now,
+			}
+			if _, err := n.notificationRepo.CreateDelivery(ctx, delivery); err != nil {
+				errs = append(errs, fmt.Errorf("preference %s: %w", preferenceID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// wantsStatus reports whether pref notifies jobs reaching status.
+func wantsStatus(pref *core.NotificationPreference, status core.JobStatus) bool {
+	if !pref.Enabled {
+		return false
+	}
+	statuses := pref.Statuses
+	if len(statuses) == 0 {
+		statuses = defaultStatuses
+	}
+	return slices.Contains(statuses, status)
+}
+
+// Run ticks immediately and then once per interval until ctx is cancelled.
+func (n *Notifier) Run(ctx context.Context) {
+	n.runner.Run(ctx, func(ctx context.Context) error {
+		_, err := n.dispatch(ctx)
+		return err
+	})
+}
+
+// TickOnce sends the deliveries that are due, if this replica holds the lease.
+func (n *Notifier) TickOnce(ctx context.Context) (DispatchResult, error) {
+	var res DispatchResult
+	_, err := n.runner.TickOnce(ctx, func(ctx context.Context) (err error) {
+		res, err = n.dispatch(ctx)
+		return err
+	})
+	return res, err
+}
+
+// dispatch sends each due delivery and records the attempt. Failed attempts are recorded on
+// their deliveries; storage failures are returned together and do not stop the tick.
+func (n *Notifier) dispatch(ctx context.Context) (DispatchResult, error) {
+	var res DispatchResult
+	due, err := n.notificationRepo.ListDueDeliveries(ctx, n.now(), dispatchBatchSize)
+	if err != nil {
+		return res, fmt.Errorf("failed to list due deliveries: %w", err)
+	}
+	var errs []error
+	for _, delivery := range due {
+		msg, err := n.render(ctx, delivery)
+		switch {
+		case errors.Is(err, core.ErrNotFound):
+			n.giveUp(delivery, "job no longer exists")
+		case err != nil:
+			errs = append(errs, fmt.Errorf("delivery %s: %w", delivery.ID, err))
+			continue // Retried next tick without counting an attempt
+		default:
+			if sendErr := n.attempt(ctx, delivery, msg); sendErr != nil {
+				logger.Logger.Warn("Notification delivery attempt failed",
+					zap.String("deliveryID", delivery.ID),
+					zap.String("channel", string(delivery.Channel.Type)),
+					zap.Int("attempts", delivery.Attempts),
+					zap.Error(sendErr),
+				)
+			}
+		}
+		if err := n.notificationRepo.UpdateDelivery(ctx, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("delivery %s: %w", delivery.ID, err))
+			continue
+		}
+		switch delivery.Status {
+		case core.NotificationDeliveryDelivered:
+			res.Delivered++
+		case core.NotificationDeliveryFailed:
+			res.Failed++
+		default:
+			res.Retrying++
+		}
+	}
+	return res, errors.Join(errs...)
+}
+
+// SendTest sends a test message to every channel of pref right away and logs one
+// delivery per channel. Test deliveries are not retried.
+func (n *Notifier) SendTest(ctx context.Context, pref *core.NotificationPreference) ([]*core.NotificationDelivery, error) {
+	deliveries := make([]*core.NotificationDelivery, 0, len(pref.Channels))
+	for _, channel := range pref.Channels {
+		now := n.now()
+		delivery := &core.NotificationDelivery{
+			ID:           uuid.NewString(),
+			PreferenceID: pref.ID,
+			ProjectID:    pref.ProjectID,
+			Test:         true,
+			Channel:      channel,
+			Status:       core.NotificationDeliveryPending,
+			CreatedAt:    now,
+		}
+		msg := Message{
+			DeliveryID: delivery.ID,
+			Event:      EventTest,
+			Subject:    "SynDataGen test notification",
+			Text:       "Notifications for this " + preferenceScope(pref) + " are delivered to this channel.",
+			OccurredAt: now,
+		}
+		_ = n.attempt(ctx, delivery, msg) // The outcome is recorded on the delivery
+		if _, err := n.notificationRepo.CreateDelivery(ctx, delivery); err != nil {
+			return nil, fmt.Errorf("failed to log test delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+// preferenceScope names what pref covers in messages.
+func preferenceScope(pref *core.NotificationPreference) string {
+	if pref.ProjectID != "" {
+		return "project"
+	}
+	return "account"
+}
+
+// attempt sends msg for delivery once and records the outcome on it: delivered, pending
+// with a later next attempt, or failed once no attempts are left. The caller saves it.
+func (n *Notifier) attempt(ctx context.Context, delivery *core.NotificationDelivery, msg Message) error {
+	now := n.now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	sender, ok := n.channels[delivery.Channel.Type]
+	if !ok {
+		err := fmt.Errorf("channel type %q is not configured", delivery.Channel.Type)
+		n.giveUp(delivery, err.Error())
+		return err
+	}
+	if err := sender.Send(ctx, delivery.Channel, msg); err != nil {
+		if delivery.Test || delivery.Attempts >= n.maxAttempts {
+			n.giveUp(delivery, err.Error())
+			return err
+		}
+		next := now.Add(n.retryBackoff << (delivery.Attempts - 1))
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = &next
+		return err
+	}
+	delivery.Status = core.NotificationDeliveryDelivered
+	delivery.LastError = ""
+	delivery.NextAttemptAt = nil
+	delivery.DeliveredAt = &now
+	return nil
+}
+
+// giveUp marks delivery failed with reason.
+func (n *Notifier) giveUp(delivery *core.NotificationDelivery, reason string) {
+	delivery.Status = core.NotificationDeliveryFailed
+	delivery.LastError = reason
+	delivery.NextAttemptAt = nil
+}
+
+// render builds the message of a job delivery from the job's current state. It returns
+// core.ErrNotFound if the job was deleted.
+func (n *Notifier) render(ctx context.Context, delivery *core.NotificationDelivery) (Message, error) {
+	job, err := n.jobRepo.GetJobByID(ctx, delivery.JobID)
+	if err != nil {
+		return Message{}, err
+	}
+	summary := &JobSummary{
+		ID:          job.ID,
+		ProjectID:   job.ProjectID,
+		ProjectName: job.ProjectID,
+		JobType:     job.JobType,
+		Status:      delivery.JobStatus, // The status notified, even if the job moved on
+		Error:       job.Error,
+		ResultURI:   job.ResultURI,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+		Attempt:     job.Attempt,
+	}
+	if proj, err := n.projectRepo.GetProjectByID(ctx, job.ProjectID); err == nil {
+		summary.ProjectName = proj.Name
+	}
+	occurredAt := delivery.CreatedAt
+	if job.CompletedAt != nil {
+		occurredAt = *job.CompletedAt
+	}
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "Project: %s\n", summary.ProjectName)
+	fmt.Fprintf(&text, "Job: %s (%s)\n", summary.ID, summary.JobType)
+	fmt.Fprintf(&text, "Status: %s\n", summary.Status)
+	if summary.Attempt > 1 {
+		fmt.Fprintf(&text, "Attempt: %d\n", summary.Attempt)
+	}
+	if summary.Error != "" {
+		fmt.Fprintf(&text, "Error: %s\n", summary.Error)
+	}
+	if summary.ResultURI != "" {
+		fmt.Fprintf(&text, "Result: %s\n", summary.ResultURI)
+	}
+	return Message{
+		DeliveryID: delivery.ID,
+		Event:      "job." + string(delivery.JobStatus),
+		Subject:    fmt.Sprintf("Job %s %s in %s", summary.ID, summary.Status, summary.ProjectName),
+		Text:       text.String(),
+		Job:        summary,
+		OccurredAt: occurredAt,
+	}, nil
+}
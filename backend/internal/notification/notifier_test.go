@@ -0,0 +1,372 @@
+package notification
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockNotificationRepository is a mock implementation of core.NotificationRepository.
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) GetPreference(ctx context.Context, preferenceID string) (*core.NotificationPreference, error) {
+	args := m.Called(ctx, preferenceID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.NotificationPreference), args.Error(1)
+}
+
+func (m *MockNotificationRepository) SavePreference(ctx context.Context, pref *core.NotificationPreference) error {
+	args := m.Called(ctx, pref)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) DeletePreference(ctx context.Context, preferenceID string) error {
+	args := m.Called(ctx, preferenceID)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) CreateDelivery(ctx context.Context, delivery *core.NotificationDelivery) (bool, error) {
+	args := m.Called(ctx, delivery)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateDelivery(ctx context.Context, delivery *core.NotificationDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*core.NotificationDelivery, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.NotificationDelivery), args.Error(1)
+}
+
+func (m *MockNotificationRepository) ListDeliveries(ctx context.Context, preferenceID string, limit, offset int) ([]*core.NotificationDelivery, int, error) {
+	args := m.Called(ctx, preferenceID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.NotificationDelivery), args.Int(1), args.Error(2)
+}
+
+// MockJobRepository mocks job lookups.
+type MockJobRepository struct {
+	mock.Mock
+	core.JobRepository
+}
+
+func (m *MockJobRepository) GetJobByID(ctx context.Context, jobID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+// MockProjectRepository mocks project lookups.
+type MockProjectRepository struct {
+	mock.Mock
+	core.ProjectRepository
+}
+
+func (m *MockProjectRepository) GetProjectByID(ctx context.Context, id string) (*core.Project, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+// MockLeaseRepository is a mock implementation of core.LeaseRepository.
+type MockLeaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockLeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, name, holder, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockSender is a mock implementation of Sender.
+type MockSender struct {
+	mock.Mock
+}
+
+func (m *MockSender) Send(ctx context.Context, channel core.NotificationChannel, msg Message) error {
+	args := m.Called(ctx, channel, msg)
+	return args.Error(0)
+}
+
+var testNow = time.Date(2026, 3, 10, 10, 25, 0, 0, time.UTC)
+
+type notifierMocks struct {
+	notifications *MockNotificationRepository
+	jobs          *MockJobRepository
+	projects      *MockProjectRepository
+	leases        *MockLeaseRepository
+	webhook       *MockSender
+}
+
+func setupNotifierTest() (*Notifier, notifierMocks) {
+	m := notifierMocks{
+		notifications: new(MockNotificationRepository),
+		jobs:          new(MockJobRepository),
+		projects:      new(MockProjectRepository),
+		leases:        new(MockLeaseRepository),
+		webhook:       new(MockSender),
+	}
+	channels := Channels{core.NotificationChannelWebhook: m.webhook}
+	notifier := NewNotifier(m.notifications, m.jobs, m.projects, m.leases, channels, NotifierConfig{HolderID: "replica-1", MaxAttempts: 3})
+	notifier.now = func() time.Time { return testNow }
+	return notifier, m
+}
+
+var (
+	testProject = &core.Project{ID: "proj-1", Name: "Demo", TeamMembers: map[string]core.Role{"user-1": core.RoleMember}}
+	webhook     = core.NotificationChannel{Type: core.NotificationChannelWebhook, Target: "https://hooks.example.com/a", Secret: "s3cret"}
+	email       = core.NotificationChannel{Type: core.NotificationChannelEmail, Target: "user@example.com"}
+)
+
+// failedJob returns a job of user-1 in proj-1 that failed.
+func failedJob() *core.Job {
+	completed := testNow.Add(-time.Minute)
+	return &core.Job{
+		ID: "job-1", ProjectID: "proj-1", UserID: "user-1", Status: core.JobStatusFailed, JobType: "csv",
+		Error: "pipeline crashed", CreatedAt: testNow.Add(-time.Hour), CompletedAt: &completed,
+	}
+}
+
+func TestNotifier_NotifyJobStatus(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_ProjectAndCreatorPreferences", func(t *testing.T) {
+		notifier, m := setupNotifierTest()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.notifications.On("GetPreference", ctx, "project-proj-1").Return(&core.NotificationPreference{
+			ID: "project-proj-1", ProjectID: "proj-1", Enabled: true, Channels: []core.NotificationChannel{webhook},
+		}, nil).Once()
+		m.notifications.On("GetPreference", ctx, "user-user-1").Return(&core.NotificationPreference{
+			ID: "user-user-1", UserID: "user-1", Enabled: true, Statuses: []core.JobStatus{core.JobStatusFailed},
+			Channels: []core.NotificationChannel{email, webhook},
+		}, nil).Once()
+		var created []*core.NotificationDelivery
+		m.notifications.On("CreateDelivery", ctx, mock.AnythingOfType("*core.NotificationDelivery")).
+			Run(func(args mock.Arguments) { created = append(created, args.Get(1).(*core.NotificationDelivery)) }).
+			Return(true, nil).Times(3)
+
+		err := notifier.NotifyJobStatus(ctx, failedJob())
+
+		require.NoError(t, err)
+		require.Len(t, created, 3)
+		assert.Equal(t, &core.NotificationDelivery{
+			ID: "job-1:failed:project-proj-1:0", PreferenceID: "project-proj-1", ProjectID: "proj-1", JobID: "job-1",
+			JobStatus: core.JobStatusFailed, Channel: webhook, Status: core.NotificationDeliveryPending,
+			CreatedAt: testNow, NextAttemptAt: &testNow,
+		}, created[0])
+		assert.Equal(t, "job-1:failed:user-user-1:0", created[1].ID)
+		assert.Equal(t, email, created[1].Channel)
+		assert.Equal(t, "job-1:failed:user-user-1:1", created[2].ID)
+	})
+
+	t.Run("Skip_UnwantedStatusAndFormerMember", func(t *testing.T) {
+		notifier, m := setupNotifierTest()
+		job := failedJob()
+		job.Status = core.JobStatusCancelled
+		job.UserID = "former-1"
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.notifications.On("GetPreference", ctx, "project-proj-1").Return(&core.NotificationPreference{
+			ID: "project-proj-1", Enabled: true, Channels: []core.NotificationChannel{webhook}, // Defaults to completed and failed
+		}, nil).Once()
+
+		err := notifier.NotifyJobStatus(ctx, job)
+
+		require.NoError(t, err)
+		m.notifications.AssertNotCalled(t, "GetPreference", ctx, "user-former-1")
+		m.notifications.AssertNotCalled(t, "CreateDelivery", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Skip_DisabledOrMissingPreferences", func(t *testing.T) {
+		notifier, m := setupNotifierTest()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.notifications.On("GetPreference", ctx, "project-proj-1").Return(nil, core.ErrNotFound).Once()
+		m.notifications.On("GetPreference", ctx, "user-user-1").Return(&core.NotificationPreference{
+			ID: "user-user-1", Enabled: false, Channels: []core.NotificationChannel{webhook},
+		}, nil).Once()
+
+		err := notifier.NotifyJobStatus(ctx, failedJob())
+
+		require.NoError(t, err)
+		m.notifications.AssertNotCalled(t, "CreateDelivery", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_PreferenceError", func(t *testing.T) {
+		notifier, m := setupNotifierTest()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.notifications.On("GetPreference", ctx, "project-proj-1").Return(nil, errors.New("unavailable")).Once()
+		m.notifications.On("GetPreference", ctx, "user-user-1").Return(nil, core.ErrNotFound).Once()
+
+		err := notifier.NotifyJobStatus(ctx, failedJob())
+
+		assert.ErrorContains(t, err, "preference project-proj-1")
+	})
+}
+
+// pendingDelivery returns a due webhook delivery for the failure of job-1.
+func pendingDelivery(attempts int) *core.NotificationDelivery {
+	return &core.NotificationDelivery{
+		ID: "job-1:failed:project-proj-1:0", PreferenceID: "project-proj-1", ProjectID: "proj-1", JobID: "job-1",
+		JobStatus: core.JobStatusFailed, Channel: webhook, Status: core.NotificationDeliveryPending,
+		Attempts: attempts, CreatedAt: testNow.Add(-time.Minute), NextAttemptAt: &testNow,
+	}
+}
+
+func TestNotifier_TickOnce(t *testing.T) {
+	ctx := context.Background()
+	acquire := func(m notifierMocks) {
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-1", 2*DefaultDispatchInterval).Return(true, nil).Once()
+	}
+
+	t.Run("Success_Delivers", func(t *testing.T) {
+		notifier, m := setupNotifierTest()
+		acquire(m)
+		delivery := pendingDelivery(0)
+		m.notifications.On("ListDueDeliveries", ctx, testNow, dispatchBatchSize).Return([]*core.NotificationDelivery{delivery}, nil).Once()
+		m.jobs.On("GetJobByID", ctx, "job-1").Return(failedJob(), nil).Once()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.webhook.On("Send", ctx, webhook, mock.MatchedBy(func(msg Message) bool {
+			return msg.DeliveryID == delivery.ID && msg.Event == "job.failed" &&
+				msg.Subject == "Job job-1 failed in Demo" && msg.Job.ProjectName == "Demo" &&
+				msg.Text == "Project: Demo\nJob: job-1 (csv)\nStatus: failed\nError: pipeline crashed\n"
+		})).Return(nil).Once()
+		m.notifications.On("UpdateDelivery", ctx, delivery).Return(nil).Once()
+
+		res, err := notifier.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, DispatchResult{Delivered: 1}, res)
+		assert.Equal(t, core.NotificationDeliveryDelivered, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, &testNow, delivery.DeliveredAt)
+		assert.Nil(t, delivery.NextAttemptAt)
+		m.webhook.AssertExpectations(t)
+	})
+
+	t.Run("Success_RetriesWithBackoff", func(t *testing.T) {
+		notifier, m := setupNotifierTest()
+		acquire(m)
+		delivery := pendingDelivery(1)
+		m.notifications.On("ListDueDeliveries", ctx, testNow, dispatchBatchSize).Return([]*core.NotificationDelivery{delivery}, nil).Once()
+		m.jobs.On("GetJobByID", ctx, "job-1").Return(failedJob(), nil).Once()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.webhook.On("Send", ctx, webhook, mock.Anything).Return(errors.New("endpoint responded with status 503")).Once()
+		m.notifications.On("UpdateDelivery", ctx, delivery).Return(nil).Once()
+
+		res, err := notifier.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, DispatchResult{Retrying: 1}, res)
+		assert.Equal(t, core.NotificationDeliveryPending, delivery.Status)
+		assert.Equal(t, 2, delivery.Attempts)
+		assert.Equal(t, "endpoint responded with status 503", delivery.LastError)
+		assert.Equal(t, testNow.Add(2*DefaultRetryBackoff), *delivery.NextAttemptAt) // Doubled after the second attempt
+	})
+
+	t.Run("Success_GivesUpAfterMaxAttempts", func(t *testing.T) {
+		notifier, m := setupNotifierTest()
+		acquire(m)
+		delivery := pendingDelivery(2)
+		m.notifications.On("ListDueDeliveries", ctx, testNow, dispatchBatchSize).Return([]*core.NotificationDelivery{delivery}, nil).Once()
+		m.jobs.On("GetJobByID", ctx, "job-1").Return(failedJob(), nil).Once()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.webhook.On("Send", ctx, webhook, mock.Anything).Return(errors.New("request failed")).Once()
+		m.notifications.On("UpdateDelivery", ctx, delivery).Return(nil).Once()
+
+		res, err := notifier.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, DispatchResult{Failed: 1}, res)
+		assert.Equal(t, core.NotificationDeliveryFailed, delivery.Status)
+		assert.Equal(t, 3, delivery.Attempts)
+		assert.Nil(t, delivery.NextAttemptAt)
+	})
+
+	t.Run("Success_FailsDeletedJobsAndUnsupportedChannels", func(t *testing.T) {
+		notifier, m := setupNotifierTest()
+		acquire(m)
+		deleted := pendingDelivery(0)
+		emailed := pendingDelivery(0)
+		emailed.ID, emailed.JobID, emailed.Channel = "job-2:failed:project-proj-1:0", "job-2", email
+		m.notifications.On("ListDueDeliveries", ctx, testNow, dispatchBatchSize).Return([]*core.NotificationDelivery{deleted, emailed}, nil).Once()
+		m.jobs.On("GetJobByID", ctx, "job-1").Return(nil, core.ErrNotFound).Once()
+		job2 := failedJob()
+		job2.ID = "job-2"
+		m.jobs.On("GetJobByID", ctx, "job-2").Return(job2, nil).Once()
+		m.projects.On("GetProjectByID", ctx, "proj-1").Return(testProject, nil).Once()
+		m.notifications.On("UpdateDelivery", ctx, mock.Anything).Return(nil).Twice()
+
+		res, err := notifier.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, DispatchResult{Failed: 2}, res)
+		assert.Equal(t, "job no longer exists", deleted.LastError)
+		assert.Equal(t, 0, deleted.Attempts)
+		assert.Equal(t, `channel type "email" is not configured`, emailed.LastError)
+		m.webhook.AssertNotCalled(t, "Send", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Skip_LeaseHeldElsewhere", func(t *testing.T) {
+		notifier, m := setupNotifierTest()
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-1", 2*DefaultDispatchInterval).Return(false, nil).Once()
+
+		res, err := notifier.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, DispatchResult{}, res)
+		m.notifications.AssertNotCalled(t, "ListDueDeliveries", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_JobLookupLeavesDeliveryDue", func(t *testing.T) {
+		notifier, m := setupNotifierTest()
+		acquire(m)
+		delivery := pendingDelivery(0)
+		m.notifications.On("ListDueDeliveries", ctx, testNow, dispatchBatchSize).Return([]*core.NotificationDelivery{delivery}, nil).Once()
+		m.jobs.On("GetJobByID", ctx, "job-1").Return(nil, errors.New("unavailable")).Once()
+
+		_, err := notifier.TickOnce(ctx)
+
+		assert.ErrorContains(t, err, "unavailable")
+		assert.Equal(t, 0, delivery.Attempts)
+		m.notifications.AssertNotCalled(t, "UpdateDelivery", mock.Anything, mock.Anything)
+	})
+}
+
+func TestNotifier_SendTest(t *testing.T) {
+	ctx := context.Background()
+	notifier, m := setupNotifierTest()
+	other := core.NotificationChannel{Type: core.NotificationChannelWebhook, Target: "https://hooks.example.com/b", Secret: "s3cret"}
+	pref := &core.NotificationPreference{ID: "project-proj-1", ProjectID: "proj-1", Channels: []core.NotificationChannel{webhook, other}}
+	m.webhook.On("Send", ctx, webhook, mock.MatchedBy(func(msg Message) bool { return msg.Event == EventTest && msg.Job == nil })).Return(nil).Once()
+	m.webhook.On("Send", ctx, other, mock.Anything).Return(errors.New("request failed")).Once()
+	m.notifications.On("CreateDelivery", ctx, mock.AnythingOfType("*core.NotificationDelivery")).Return(true, nil).Twice()
+
+	deliveries, err := notifier.SendTest(ctx, pref)
+
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.True(t, deliveries[0].Test)
+	assert.Equal(t, core.NotificationDeliveryDelivered, deliveries[0].Status)
+	assert.Equal(t, core.NotificationDeliveryFailed, deliveries[1].Status) // Tests are not retried
+	assert.Equal(t, "request failed", deliveries[1].LastError)
+}
@@ -0,0 +1,281 @@
+package notification
+
+import (
+	"SynDataGen/backend/internal/access"
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Notification errors
+var (
+	ErrInvalidPreference = errors.New("invalid notification preference")
+)
+
+const maxChannels = 10 // Channels per preference
+
+// --- Service Interface ---
+
+// NotificationService manages notification preferences. Every method addresses the
+// caller's own preference when projectID is empty, and the project's preference otherwise,
+// which requires Admin role.
+type NotificationService interface {
+	// GetPreference retrieves a preference, or a disabled one with no channels if none is saved.
+	GetPreference(ctx context.Context, userID, projectID string) (*core.NotificationPreference, error)
+
+	// UpdatePreference replaces a preference.
+	UpdatePreference(ctx context.Context, userID, projectID string, req PreferenceRequest) (*core.NotificationPreference, error)
+
+	// DeletePreference deletes a preference. Queued deliveries are still sent.
+	DeletePreference(ctx context.Context, userID, projectID string) error
+
+	// ListDeliveries retrieves a paginated log of a preference's deliveries, newest first.
+	ListDeliveries(ctx context.Context, userID, projectID string, limit, offset int) ([]*core.NotificationDelivery, int, error)
+
+	// SendTest sends a test message to each channel of a saved preference and returns the
+	// logged deliveries. Failures are reported on the deliveries.
+	SendTest(ctx context.Context, userID, projectID string) ([]*core.NotificationDelivery, error)
+}
+
+// notificationService implements the NotificationService interface.
+type notificationService struct {
+	notificationRepo core.NotificationRepository
+	userRepo         core.UserRepository
+	projects         access.ProjectGetter
+	notifier         *Notifier
+	now              func() time.Time // Overridable for tests
+}
+
+// NewNotificationService creates a new notification service instance.
+func NewNotificationService(notificationRepo core.NotificationRepository, userRepo core.UserRepository, projects access.ProjectGetter, notifier *Notifier) NotificationService {
+	if notificationRepo == nil || userRepo == nil || projects == nil || notifier == nil {
+		panic("notification.NewNotificationService: all dependencies are required")
+	}
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		projects:         projects,
+		notifier:         notifier,
+		now:              func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// preferenceID authorizes the caller for the addressed preference and returns its ID.
+// Project preferences require Admin role, and changing them requires an active project.
+func (s *notificationService) preferenceID(ctx context.Context, userID, projectID string, write bool) (string, error) {
+	if projectID == "" {
+		return UserPreferenceID(userID), nil
+	}
+	var err error
+	if write {
+		_, err = access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleAdmin, "notifications")
+	} else {
+		_, err = access.Authorize(ctx, s.projects, projectID, userID, core.RoleAdmin)
+	}
+	if err != nil {
+		return "", err
+	}
+	return ProjectPreferenceID(projectID), nil
+}
+
+// GetPreference retrieves a preference.
+func (s *notificationService) GetPreference(ctx context.Context, userID, projectID string) (*core.NotificationPreference, error) {
+	preferenceID, err := s.preferenceID(ctx, userID, projectID, false)
+	if err != nil {
+		return nil, err
+	}
+	pref, err := s.notificationRepo.GetPreference(ctx, preferenceID)
+	if errors.Is(err, core.ErrNotFound) {
+		pref = &core.NotificationPreference{
+			ID:       preferenceID,
+			Statuses: slices.Clone(defaultStatuses),
+			Channels: []core.NotificationChannel{},
+		}
+		if projectID == "" {
+			pref.UserID = userID
+		} else {
+			pref.ProjectID = projectID
+		}
+		return pref, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preference: %w", err)
+	}
+	return pref, nil
+}
+
+// UpdatePreference replaces a preference.
+func (s *notificationService) UpdatePreference(ctx context.Context, userID, projectID string, req PreferenceRequest) (*core.NotificationPreference, error) {
+	// 1. Authorize and load the current preference, whose webhook secrets may be kept
+	preferenceID, err := s.preferenceID(ctx, userID, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.notificationRepo.GetPreference(ctx, preferenceID)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get notification preference: %w", err)
+	}
+
+	// 2. Validate the request
+	statuses, err := validateStatuses(req.Statuses)
+	if err != nil {
+		return nil, err
+	}
+	channels, err := s.validateChannels(ctx, userID, projectID, req.Channels, current)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. Save
+	pref := &core.NotificationPreference{
+		ID:        preferenceID,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+		Statuses:  statuses,
+		Channels:  channels,
+		UpdatedBy: userID,
+		UpdatedAt: s.now(),
+	}
+	if projectID == "" {
+		pref.UserID = userID
+	} else {
+		pref.ProjectID = projectID
+	}
+	if err := s.notificationRepo.SavePreference(ctx, pref); err != nil {
+		return nil, fmt.Errorf("failed to save notification preference: %w", err)
+	}
+	logger.Logger.Info("Updated notification preference",
+		zap.String("preferenceID", preferenceID),
+		zap.String("userID", userID),
+		zap.Int("channels", len(channels)),
+	)
+	return pref, nil
+}
+
+// validateStatuses checks the notified statuses, defaulting to completed and failed.
+func validateStatuses(requested []core.JobStatus) ([]core.JobStatus, error) {
+	if len(requested) == 0 {
+		return slices.Clone(defaultStatuses), nil
+	}
+	statuses := []core.JobStatus{}
+	for _, status := range requested {
+		switch status {
+		case core.JobStatusCompleted, core.JobStatusFailed, core.JobStatusCancelled:
+		default:
+			return nil, fmt.Errorf("%w: status %q cannot be notified; use completed, failed or cancelled", ErrInvalidPreference, status)
+		}
+		if !slices.Contains(statuses, status) {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, nil
+}
+
+// validateChannels checks the requested channels. An email channel of a user's preference
+// without a target uses the user's address, and a webhook without a secret keeps the
+// secret current has for the same URL.
+func (s *notificationService) validateChannels(ctx context.Context, userID, projectID string, requested []ChannelRequest, current *core.NotificationPreference) ([]core.NotificationChannel, error) {
+	if len(requested) > maxChannels {
+		return nil, fmt.Errorf("%w: at most %d channels are allowed", ErrInvalidPreference, maxChannels)
+	}
+	channels := make([]core.NotificationChannel, 0, len(requested))
+	for i, req := range requested {
+		if _, ok := s.notifier.channels[req.Type]; !ok {
+			return nil, fmt.Errorf("%w: channels[%d]: channel type %q is not supported", ErrInvalidPreference, i, req.Type)
+		}
+		channel := core.NotificationChannel{Type: req.Type, Target: req.Target}
+		switch req.Type {
+		case core.NotificationChannelEmail:
+			if channel.Target == "" && projectID == "" {
+				user, err := s.userRepo.GetUserByID(ctx, userID)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get user %s: %w", userID, err)
+				}
+				channel.Target = user.Email
+			}
+			addr, err := mail.ParseAddress(channel.Target)
+			if err != nil {
+				return nil, fmt.Errorf("%w: channels[%d]: invalid email address %q", ErrInvalidPreference, i, channel.Target)
+			}
+			channel.Target = addr.Address
+		case core.NotificationChannelWebhook, core.NotificationChannelSlack:
+			u, err := url.Parse(channel.Target)
+			if err != nil || u.Scheme != "https" || u.Host == "" {
+				return nil, fmt.Errorf("%w: channels[%d]: target must be an https URL", ErrInvalidPreference, i)
+			}
+		}
+		if req.Type == core.NotificationChannelWebhook {
+			channel.Secret = req.Secret
+			if channel.Secret == "" {
+				channel.Secret = existingSecret(current, channel.Target)
+			}
+			if channel.Secret == "" {
+				return nil, fmt.Errorf("%w: channels[%d]: webhooks require a signing secret", ErrInvalidPreference, i)
+			}
+		}
+		channels = append(channels, channel)
+	}
+	return channels, nil
+}
+
+// existingSecret returns the secret of pref's webhook channel for target, if any.
+func existingSecret(pref *core.NotificationPreference, target string) string {
+	if pref == nil {
+		return ""
+	}
+	for _, channel := range pref.Channels {
+		if channel.Type == core.NotificationChannelWebhook && channel.Target == target {
+			return channel.Secret
+		}
+	}
+	return ""
+}
+
+// DeletePreference deletes a preference.
+func (s *notificationService) DeletePreference(ctx context.Context, userID, projectID string) error {
+	preferenceID, err := s.preferenceID(ctx, userID, projectID, true)
+	if err != nil {
+		return err
+	}
+	if err := s.notificationRepo.DeletePreference(ctx, preferenceID); err != nil {
+		return fmt.Errorf("failed to delete notification preference: %w", err)
+	}
+	logger.Logger.Info("Deleted notification preference", zap.String("preferenceID", preferenceID), zap.String("userID", userID))
+	return nil
+}
+
+// ListDeliveries retrieves a preference's deliveries.
+func (s *notificationService) ListDeliveries(ctx context.Context, userID, projectID string, limit, offset int) ([]*core.NotificationDelivery, int, error) {
+	preferenceID, err := s.preferenceID(ctx, userID, projectID, false)
+	if err != nil {
+		return nil, 0, err
+	}
+	deliveries, total, err := s.notificationRepo.ListDeliveries(ctx, preferenceID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notification deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// SendTest sends a test message to each channel of a saved preference.
+func (s *notificationService) SendTest(ctx context.Context, userID, projectID string) ([]*core.NotificationDelivery, error) {
+	preferenceID, err := s.preferenceID(ctx, userID, projectID, true)
+	if err != nil {
+		return nil, err
+	}
+	pref, err := s.notificationRepo.GetPreference(ctx, preferenceID)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get notification preference: %w", err)
+	}
+	if pref == nil || len(pref.Channels) == 0 {
+		return nil, fmt.Errorf("%w: no channels are configured", ErrInvalidPreference)
+	}
+	return s.notifier.SendTest(ctx, pref)
+}
@@ -0,0 +1,218 @@
+package notification
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockProjectGetter is a mock implementation of access.ProjectGetter.
+type MockProjectGetter struct {
+	mock.Mock
+}
+
+func (m *MockProjectGetter) GetProjectByID(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+// MockUserRepository mocks user lookups.
+type MockUserRepository struct {
+	mock.Mock
+	core.UserRepository
+}
+
+func (m *MockUserRepository) GetUserByID(ctx context.Context, id string) (*core.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.User), args.Error(1)
+}
+
+type serviceMocks struct {
+	notifierMocks
+	users         *MockUserRepository
+	projectGetter *MockProjectGetter
+}
+
+func setupNotificationServiceTest() (*notificationService, serviceMocks) {
+	notifier, nm := setupNotifierTest()
+	notifier.channels[core.NotificationChannelEmail] = new(MockSender)
+	notifier.channels[core.NotificationChannelSlack] = new(MockSender)
+	m := serviceMocks{notifierMocks: nm, users: new(MockUserRepository), projectGetter: new(MockProjectGetter)}
+	svc := NewNotificationService(m.notifications, m.users, m.projectGetter, notifier).(*notificationService)
+	svc.now = func() time.Time { return testNow }
+	return svc, m
+}
+
+var adminProject = &core.Project{ID: "proj-1", Status: core.ProjectStatusActive, TeamMembers: map[string]core.Role{
+	"admin-1":  core.RoleAdmin,
+	"viewer-1": core.RoleViewer,
+}}
+
+func TestNotificationService_GetPreference(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_DefaultWhenUnsaved", func(t *testing.T) {
+		svc, m := setupNotificationServiceTest()
+		m.notifications.On("GetPreference", ctx, "user-user-1").Return(nil, core.ErrNotFound).Once()
+
+		pref, err := svc.GetPreference(ctx, "user-1", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, &core.NotificationPreference{
+			ID: "user-user-1", UserID: "user-1", Statuses: []core.JobStatus{core.JobStatusCompleted, core.JobStatusFailed},
+			Channels: []core.NotificationChannel{},
+		}, pref)
+	})
+
+	t.Run("Failure_ProjectRequiresAdmin", func(t *testing.T) {
+		svc, m := setupNotificationServiceTest()
+		m.projectGetter.On("GetProjectByID", ctx, "proj-1", "viewer-1").Return(adminProject, nil).Once()
+
+		_, err := svc.GetPreference(ctx, "viewer-1", "proj-1")
+
+		assert.ErrorIs(t, err, core.ErrForbidden)
+		m.notifications.AssertNotCalled(t, "GetPreference", mock.Anything, mock.Anything)
+	})
+}
+
+func TestNotificationService_UpdatePreference(t *testing.T) {
+	ctx := context.Background()
+	disabled := false
+
+	t.Run("Success_UserPreference", func(t *testing.T) {
+		svc, m := setupNotificationServiceTest()
+		m.notifications.On("GetPreference", ctx, "user-user-1").Return(&core.NotificationPreference{
+			ID: "user-user-1", Channels: []core.NotificationChannel{webhook},
+		}, nil).Once()
+		m.users.On("GetUserByID", ctx, "user-1").Return(&core.User{ID: "user-1", Email: "user@example.com"}, nil).Once()
+		m.notifications.On("SavePreference", ctx, mock.AnythingOfType("*core.NotificationPreference")).Return(nil).Once()
+
+		pref, err := svc.UpdatePreference(ctx, "user-1", "", PreferenceRequest{
+			Enabled: &disabled,
+			Channels: []ChannelRequest{
+				{Type: core.NotificationChannelEmail},                           // Defaults to the user's address
+				{Type: core.NotificationChannelWebhook, Target: webhook.Target}, // Keeps the saved secret
+				{Type: core.NotificationChannelSlack, Target: "https://hooks.slack.com/services/T0/B0/x"},
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &core.NotificationPreference{
+			ID: "user-user-1", UserID: "user-1", Enabled: false,
+			Statuses: []core.JobStatus{core.JobStatusCompleted, core.JobStatusFailed},
+			Channels: []core.NotificationChannel{
+				email,
+				webhook,
+				{Type: core.NotificationChannelSlack, Target: "https://hooks.slack.com/services/T0/B0/x"},
+			},
+			UpdatedBy: "user-1", UpdatedAt: testNow,
+		}, pref)
+		m.notifications.AssertExpectations(t)
+	})
+
+	t.Run("Success_ProjectPreference", func(t *testing.T) {
+		svc, m := setupNotificationServiceTest()
+		m.projectGetter.On("GetProjectByID", ctx, "proj-1", "admin-1").Return(adminProject, nil).Once()
+		m.notifications.On("GetPreference", ctx, "project-proj-1").Return(nil, core.ErrNotFound).Once()
+		m.notifications.On("SavePreference", ctx, mock.AnythingOfType("*core.NotificationPreference")).Return(nil).Once()
+
+		pref, err := svc.UpdatePreference(ctx, "admin-1", "proj-1", PreferenceRequest{
+			Statuses: []core.JobStatus{core.JobStatusFailed, core.JobStatusFailed, core.JobStatusCancelled},
+			Channels: []ChannelRequest{{Type: core.NotificationChannelWebhook, Target: "https://hooks.example.com/new", Secret: "n3w"}},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "project-proj-1", pref.ID)
+		assert.Equal(t, "proj-1", pref.ProjectID)
+		assert.True(t, pref.Enabled)
+		assert.Equal(t, []core.JobStatus{core.JobStatusFailed, core.JobStatusCancelled}, pref.Statuses)
+		assert.Equal(t, "n3w", pref.Channels[0].Secret)
+	})
+
+	t.Run("Failure_InvalidRequests", func(t *testing.T) {
+		svc, m := setupNotificationServiceTest()
+		m.projectGetter.On("GetProjectByID", ctx, "proj-1", "admin-1").Return(adminProject, nil)
+		m.notifications.On("GetPreference", ctx, "project-proj-1").Return(nil, core.ErrNotFound)
+		tooMany := make([]ChannelRequest, maxChannels+1)
+		for i := range tooMany {
+			tooMany[i] = ChannelRequest{Type: core.NotificationChannelSlack, Target: "https://hooks.slack.com/x"}
+		}
+
+		for name, req := range map[string]PreferenceRequest{
+			"status":         {Statuses: []core.JobStatus{core.JobStatusRunning}},
+			"channel type":   {Channels: []ChannelRequest{{Type: "sms", Target: "+15550100"}}},
+			"http webhook":   {Channels: []ChannelRequest{{Type: core.NotificationChannelWebhook, Target: "http://hooks.example.com", Secret: "s"}}},
+			"missing secret": {Channels: []ChannelRequest{{Type: core.NotificationChannelWebhook, Target: "https://hooks.example.com/new"}}},
+			"project email":  {Channels: []ChannelRequest{{Type: core.NotificationChannelEmail}}}, // No user address to default to
+			"bad email":      {Channels: []ChannelRequest{{Type: core.NotificationChannelEmail, Target: "nobody"}}},
+			"too many":       {Channels: tooMany},
+		} {
+			_, err := svc.UpdatePreference(ctx, "admin-1", "proj-1", req)
+			assert.ErrorIs(t, err, ErrInvalidPreference, name)
+		}
+		m.notifications.AssertNotCalled(t, "SavePreference", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_ArchivedProject", func(t *testing.T) {
+		svc, m := setupNotificationServiceTest()
+		archived := *adminProject
+		archived.Status = core.ProjectStatusArchived
+		m.projectGetter.On("GetProjectByID", ctx, "proj-1", "admin-1").Return(&archived, nil).Once()
+
+		_, err := svc.UpdatePreference(ctx, "admin-1", "proj-1", PreferenceRequest{})
+
+		assert.ErrorIs(t, err, core.ErrProjectArchived)
+	})
+}
+
+func TestNotificationService_ListDeliveries(t *testing.T) {
+	ctx := context.Background()
+	svc, m := setupNotificationServiceTest()
+	m.projectGetter.On("GetProjectByID", ctx, "proj-1", "admin-1").Return(adminProject, nil).Once()
+	m.notifications.On("ListDeliveries", ctx, "project-proj-1", 20, 0).Return([]*core.NotificationDelivery{pendingDelivery(1)}, 1, nil).Once()
+
+	deliveries, total, err := svc.ListDeliveries(ctx, "admin-1", "proj-1", 20, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+	assert.Len(t, deliveries, 1)
+}
+
+func TestNotificationService_SendTest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		svc, m := setupNotificationServiceTest()
+		m.notifications.On("GetPreference", ctx, "user-user-1").Return(&core.NotificationPreference{
+			ID: "user-user-1", UserID: "user-1", Channels: []core.NotificationChannel{webhook},
+		}, nil).Once()
+		m.webhook.On("Send", ctx, webhook, mock.Anything).Return(nil).Once()
+		m.notifications.On("CreateDelivery", ctx, mock.AnythingOfType("*core.NotificationDelivery")).Return(true, nil).Once()
+
+		deliveries, err := svc.SendTest(ctx, "user-1", "")
+
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, core.NotificationDeliveryDelivered, deliveries[0].Status)
+	})
+
+	t.Run("Failure_NoChannels", func(t *testing.T) {
+		svc, m := setupNotificationServiceTest()
+		m.notifications.On("GetPreference", ctx, "user-user-1").Return(nil, core.ErrNotFound).Once()
+
+		_, err := svc.SendTest(ctx, "user-1", "")
+
+		assert.ErrorIs(t, err, ErrInvalidPreference)
+	})
+}
@@ -0,0 +1,82 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+
+	"SynDataGen/backend/internal/events"
+
+	"go.uber.org/zap"
+)
+
+const (
+	eventCollection = "events"
+	// eventTTL is how long event documents are kept. Configure a Firestore TTL policy on
+	// the expireAt field of the events collection to delete them.
+	eventTTL = time.Hour
+)
+
+// eventDocument is an event as stored in the 'events' collection.
+type eventDocument struct {
+	events.Event
+	ExpireAt time.Time `firestore:"expireAt"`
+}
+
+// eventBroker implements events.Broker by writing events to a Firestore collection that
+// every replica listens to.
+type eventBroker struct {
+	client *firestore.Client
+	logger *zap.Logger
+}
+
+// NewEventBroker creates a Firestore-backed event broker for multi-replica deployments.
+func NewEventBroker(client *firestore.Client, logger *zap.Logger) events.Broker {
+	if logger == nil {
+		logger = zap.L() // Use global logger if none provided
+	}
+	return &eventBroker{
+		client: client,
+		logger: logger.Named("EventBroker"),
+	}
+}
+
+// Publish stores an event, keyed by its ID so a repeated publish is written once.
+func (b *eventBroker) Publish(ctx context.Context, event events.Event) error {
+	doc := eventDocument{Event: event, ExpireAt: event.OccurredAt.Add(eventTTL)}
+	if _, err := b.client.Collection(eventCollection).Doc(event.ID).Set(ctx, doc); err != nil {
+		return fmt.Errorf("failed to store event %s in firestore: %w", event.ID, err)
+	}
+	return nil
+}
+
+// Subscribe listens for events stored after it starts and delivers them in the order the
+// listener reports them.
+func (b *eventBroker) Subscribe(ctx context.Context, deliver func(events.Event)) error {
+	since := time.Now().UTC()
+	it := b.client.Collection(eventCollection).Where("occurredAt", ">", since).Snapshots(ctx)
+	defer it.Stop()
+
+	for {
+		snap, err := it.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("event listener failed: %w", err)
+		}
+		for _, change := range snap.Changes {
+			if change.Kind != firestore.DocumentAdded {
+				continue // Events are immutable; removals are TTL deletions
+			}
+			var doc eventDocument
+			if err := change.Doc.DataTo(&doc); err != nil {
+				b.logger.Warn("Skipping malformed event document", zap.String("docID", change.Doc.Ref.ID), zap.Error(err))
+				continue
+			}
+			deliver(doc.Event)
+		}
+	}
+}
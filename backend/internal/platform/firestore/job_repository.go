@@ -2,6 +2,7 @@ package firestore
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -51,7 +52,7 @@ func (r *jobRepository) CreateJob(ctx context.Context, job *core.Job) error {
 	if err != nil {
 		if status.Code(err) == codes.AlreadyExists {
 			r.logger.Info("Job document with ID already exists", zap.String("jobID", job.ID))
-			return fmt.Errorf("job with ID %s already exists: %w", job.ID, err)
+			return fmt.Errorf("job with ID %s already exists: %w", job.ID, core.ErrConflict)
 		}
 		r.logger.Error("Error creating job document", zap.String("jobID", job.ID), zap.Error(err))
 		return fmt.Errorf("failed to create job document %s in firestore: %w", job.ID, err)
@@ -163,6 +164,57 @@ func (r *jobRepository) UpdateJobStatus(ctx context.Context, jobID string, newSt
 	r.logger.Info("Updating status for job", zap.String("jobID", jobID), zap.String("newStatus", string(newStatus)), zap.String("pipelineJobID", pipelineJobID))
 
 	docRef := r.client.Collection(jobCollection).Doc(jobID)
+	_, err := docRef.Update(ctx, jobStatusUpdates(newStatus, pipelineJobID, startedAt, completedAt, jobError))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			r.logger.Info("Job document not found for status update", zap.String("jobID", jobID))
+			return core.ErrNotFound
+		}
+		r.logger.Error("Error updating status for job", zap.String("jobID", jobID), zap.Error(err))
+		return fmt.Errorf("failed to update status for job %s: %w", jobID, err)
+	}
+
+	r.logger.Info("Successfully updated status for job", zap.String("jobID", jobID))
+	return nil
+}
+
+// TransitionJobStatus updates a job's status inside a transaction, failing with
+// core.ErrConflict if the stored status is no longer fromStatus.
+func (r *jobRepository) TransitionJobStatus(ctx context.Context, jobID string, fromStatus, newStatus core.JobStatus, pipelineJobID string, startedAt, completedAt *time.Time, jobError string) error {
+	r.logger.Info("Transitioning status for job", zap.String("jobID", jobID), zap.String("fromStatus", string(fromStatus)), zap.String("newStatus", string(newStatus)))
+
+	docRef := r.client.Collection(jobCollection).Doc(jobID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		dsnap, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		current, err := dsnap.DataAt("status")
+		if err != nil {
+			return err
+		}
+		if current != string(fromStatus) {
+			return fmt.Errorf("job %s is %v, not %s: %w", jobID, current, fromStatus, core.ErrConflict)
+		}
+		return tx.Update(docRef, jobStatusUpdates(newStatus, pipelineJobID, startedAt, completedAt, jobError))
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			r.logger.Info("Job document not found for status transition", zap.String("jobID", jobID))
+			return core.ErrNotFound
+		}
+		if errors.Is(err, core.ErrConflict) {
+			r.logger.Info("Job status changed concurrently", zap.String("jobID", jobID), zap.Error(err))
+			return err
+		}
+		r.logger.Error("Error transitioning status for job", zap.String("jobID", jobID), zap.Error(err))
+		return fmt.Errorf("failed to transition status for job %s: %w", jobID, err)
+	}
+	return nil
+}
+
+// jobStatusUpdates builds the field updates that record a job's new status.
+func jobStatusUpdates(newStatus core.JobStatus, pipelineJobID string, startedAt, completedAt *time.Time, jobError string) []firestore.Update {
 	updates := []firestore.Update{
 		{Path: "status", Value: newStatus},
 		{Path: "updatedAt", Value: time.Now().UTC()},
@@ -187,44 +239,158 @@ func (r *jobRepository) UpdateJobStatus(ctx context.Context, jobID string, newSt
 			updates = append(updates, firestore.Update{Path: "error", Value: firestore.Delete})
 		}
 	}
+	return updates
+}
+
+// UpdateJobResult updates the result URI of a completed job document.
+func (r *jobRepository) UpdateJobResult(ctx context.Context, jobID string, resultURI string) error {
+	r.logger.Info("Updating result URI for job", zap.String("jobID", jobID))
+	docRef := r.client.Collection(jobCollection).Doc(jobID)
+	updates := []firestore.Update{
+		{Path: "resultUri", Value: resultURI},
+		{Path: "updatedAt", Value: time.Now().UTC()},
+	}
 
 	_, err := docRef.Update(ctx, updates)
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
-			r.logger.Info("Job document not found for status update", zap.String("jobID", jobID))
+			r.logger.Info("Job document not found for result update", zap.String("jobID", jobID))
 			return core.ErrNotFound
 		}
-		r.logger.Error("Error updating status for job", zap.String("jobID", jobID), zap.Error(err))
-		return fmt.Errorf("failed to update status for job %s: %w", jobID, err)
+		r.logger.Error("Error updating result URI for job", zap.String("jobID", jobID), zap.Error(err))
+		return fmt.Errorf("failed to update result URI for job %s: %w", jobID, err)
 	}
 
-	r.logger.Info("Successfully updated status for job", zap.String("jobID", jobID))
+	r.logger.Info("Successfully updated result URI for job", zap.String("jobID", jobID))
 	return nil
 }
 
-// UpdateJobResult updates the result URI of a completed job document.
-func (r *jobRepository) UpdateJobResult(ctx context.Context, jobID string, resultURI string) error {
-	r.logger.Info("Updating result URI for job", zap.String("jobID", jobID))
+// UpdateJobActuals records what a completed job took.
+func (r *jobRepository) UpdateJobActuals(ctx context.Context, jobID string, actuals *core.JobActuals) error {
 	docRef := r.client.Collection(jobCollection).Doc(jobID)
 	updates := []firestore.Update{
-		{Path: "resultUri", Value: resultURI},
+		{Path: "actuals", Value: actuals},
 		{Path: "updatedAt", Value: time.Now().UTC()},
 	}
 
 	_, err := docRef.Update(ctx, updates)
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
-			r.logger.Info("Job document not found for result update", zap.String("jobID", jobID))
+			r.logger.Info("Job document not found for actuals update", zap.String("jobID", jobID))
 			return core.ErrNotFound
 		}
-		r.logger.Error("Error updating result URI for job", zap.String("jobID", jobID), zap.Error(err))
-		return fmt.Errorf("failed to update result URI for job %s: %w", jobID, err)
+		r.logger.Error("Error updating actuals for job", zap.String("jobID", jobID), zap.Error(err))
+		return fmt.Errorf("failed to update actuals for job %s: %w", jobID, err)
+	}
+	return nil
+}
+
+// MarkJobResultExpired records that a job's result was removed by data retention.
+func (r *jobRepository) MarkJobResultExpired(ctx context.Context, jobID string, expiredAt time.Time) error {
+	r.logger.Info("Marking job result as expired", zap.String("jobID", jobID))
+	docRef := r.client.Collection(jobCollection).Doc(jobID)
+	updates := []firestore.Update{
+		{Path: "resultExpiredAt", Value: expiredAt},
+		{Path: "updatedAt", Value: time.Now().UTC()},
+	}
+
+	_, err := docRef.Update(ctx, updates)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			r.logger.Info("Job document not found for result expiry", zap.String("jobID", jobID))
+			return core.ErrNotFound
+		}
+		r.logger.Error("Error marking job result as expired", zap.String("jobID", jobID), zap.Error(err))
+		return fmt.Errorf("failed to mark result expired for job %s: %w", jobID, err)
 	}
 
-	r.logger.Info("Successfully updated result URI for job", zap.String("jobID", jobID))
 	return nil
 }
 
+// MarkJobMetered records that a completed job's usage was recorded.
+func (r *jobRepository) MarkJobMetered(ctx context.Context, jobID string, meteredAt time.Time) error {
+	docRef := r.client.Collection(jobCollection).Doc(jobID)
+	updates := []firestore.Update{
+		{Path: "meteredAt", Value: meteredAt},
+		{Path: "updatedAt", Value: time.Now().UTC()},
+	}
+
+	_, err := docRef.Update(ctx, updates)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			r.logger.Info("Job document not found for metering update", zap.String("jobID", jobID))
+			return core.ErrNotFound
+		}
+		r.logger.Error("Error marking job as metered", zap.String("jobID", jobID), zap.Error(err))
+		return fmt.Errorf("failed to mark job %s as metered: %w", jobID, err)
+	}
+	return nil
+}
+
+// ListUnmeteredJobs retrieves completed jobs whose meteredAt is null. Jobs stored before
+// metering existed have no meteredAt field and are never returned.
+func (r *jobRepository) ListUnmeteredJobs(ctx context.Context, limit int) ([]*core.Job, error) {
+	query := r.client.Collection(jobCollection).
+		Where("status", "==", string(core.JobStatusCompleted)).
+		Where("meteredAt", "==", nil).
+		Limit(limit)
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	jobs := []*core.Job{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("Error iterating unmetered jobs", zap.Error(err))
+			return nil, fmt.Errorf("failed to list unmetered jobs: %w", err)
+		}
+		var job core.Job
+		if err := doc.DataTo(&job); err != nil {
+			r.logger.Warn("Failed to decode job document", zap.String("docId", doc.Ref.ID), zap.Error(err))
+			continue // Skip bad document
+		}
+		job.ID = doc.Ref.ID
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+// ListJobsByStatus retrieves a page of jobs with the given status, using the job ID as the
+// cursor so each page is a single query.
+func (r *jobRepository) ListJobsByStatus(ctx context.Context, jobStatus core.JobStatus, limit int, startAfterID string) ([]*core.Job, error) {
+	query := r.client.Collection(jobCollection).
+		Where("status", "==", string(jobStatus)).
+		OrderBy(firestore.DocumentID, firestore.Asc)
+	if startAfterID != "" {
+		query = query.StartAfter(startAfterID)
+	}
+	iter := query.Limit(limit).Documents(ctx)
+	defer iter.Stop()
+
+	jobs := []*core.Job{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("Error iterating jobs by status", zap.String("status", string(jobStatus)), zap.Error(err))
+			return nil, fmt.Errorf("failed to list %s jobs: %w", jobStatus, err)
+		}
+		var job core.Job
+		if err := doc.DataTo(&job); err != nil {
+			r.logger.Warn("Failed to decode job document", zap.String("docId", doc.Ref.ID), zap.Error(err))
+			continue // Skip bad document
+		}
+		job.ID = doc.Ref.ID
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
 // ListJobsAcrossProjects retrieves jobs from a list of specified project IDs.
 func (r *jobRepository) ListJobsAcrossProjects(ctx context.Context, projectIDs []string, statusFilter string, limit, offset int) ([]*core.Job, int, error) {
 	if len(projectIDs) == 0 {
@@ -328,6 +494,26 @@ func (r *jobRepository) ListJobsAcrossProjects(ctx context.Context, projectIDs [
 	return pagedJobs, totalCount, nil
 }
 
+// CountJobs counts the jobs with the given status across projects using aggregation
+// queries, one per chunk of project IDs.
+func (r *jobRepository) CountJobs(ctx context.Context, projectIDs []string, status core.JobStatus) (int, error) {
+	total := 0
+	for _, chunk := range chunkSlice(projectIDs, firestoreInLimit) {
+		query := r.client.Collection(jobCollection).Where("projectId", "in", chunk).Where("status", "==", string(status))
+		results, err := query.NewAggregationQuery().WithCount("all").Get(ctx)
+		if err != nil {
+			r.logger.Error("Failed to count jobs for project chunk", zap.Strings("projectIds", chunk), zap.String("status", string(status)), zap.Error(err))
+			return 0, fmt.Errorf("failed to count %s jobs: %w", status, err)
+		}
+		count, ok := results["all"].(*firestorepb.Value)
+		if !ok {
+			return 0, fmt.Errorf("failed to count %s jobs: unexpected aggregation result %T", status, results["all"])
+		}
+		total += int(count.GetIntegerValue())
+	}
+	return total, nil
+}
+
 // Helper function to chunk a slice
 func chunkSlice(slice []string, chunkSize int) [][]string {
 	var chunks [][]string
@@ -0,0 +1,71 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"SynDataGen/backend/internal/core"
+
+	"go.uber.org/zap"
+)
+
+const leaseCollection = "leases"
+
+// leaseDoc is the stored state of a lease.
+type leaseDoc struct {
+	Holder    string    `firestore:"holder"`
+	ExpiresAt time.Time `firestore:"expiresAt"`
+}
+
+// leaseRepository implements the core.LeaseRepository interface using Firestore.
+type leaseRepository struct {
+	client *firestore.Client
+	logger *zap.Logger
+}
+
+// NewLeaseRepository creates a new Firestore lease repository.
+func NewLeaseRepository(client *firestore.Client, logger *zap.Logger) core.LeaseRepository {
+	if logger == nil {
+		logger = zap.L() // Use global logger if none provided
+	}
+	return &leaseRepository{
+		client: client,
+		logger: logger.Named("LeaseRepository"),
+	}
+}
+
+// AcquireLease takes the lease if it is free, expired or already held by holder, inside a
+// transaction so two replicas cannot both take it.
+func (r *leaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	docRef := r.client.Collection(leaseCollection).Doc(name)
+	acquired := false
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		acquired = false // The function may be retried
+		now := time.Now().UTC()
+		dsnap, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			var current leaseDoc
+			if err := dsnap.DataTo(&current); err != nil {
+				return err
+			}
+			if current.Holder != holder && now.Before(current.ExpiresAt) {
+				return nil // Held by another replica
+			}
+		}
+		acquired = true
+		return tx.Set(docRef, leaseDoc{Holder: holder, ExpiresAt: now.Add(ttl)})
+	})
+	if err != nil {
+		r.logger.Error("Error acquiring lease", zap.String("lease", name), zap.String("holder", holder), zap.Error(err))
+		return false, fmt.Errorf("failed to acquire lease %s: %w", name, err)
+	}
+	return acquired, nil
+}
@@ -0,0 +1,189 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"SynDataGen/backend/internal/core"
+
+	"go.uber.org/zap"
+	firestorepb "google.golang.org/genproto/googleapis/firestore/v1"
+)
+
+const (
+	notificationPreferenceCollection = "notificationPreferences"
+	notificationDeliveryCollection   = "notificationDeliveries"
+)
+
+// notificationRepository implements the core.NotificationRepository interface using Firestore.
+type notificationRepository struct {
+	client *firestore.Client
+	logger *zap.Logger
+}
+
+// NewNotificationRepository creates a new Firestore notification repository.
+func NewNotificationRepository(client *firestore.Client, logger *zap.Logger) core.NotificationRepository {
+	if logger == nil {
+		logger = zap.L() // Use global logger if none provided
+	}
+	return &notificationRepository{
+		client: client,
+		logger: logger.Named("NotificationRepository"),
+	}
+}
+
+// GetPreference retrieves a preference document by its ID.
+func (r *notificationRepository) GetPreference(ctx context.Context, preferenceID string) (*core.NotificationPreference, error) {
+	dsnap, err := r.client.Collection(notificationPreferenceCollection).Doc(preferenceID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, core.ErrNotFound // Use predefined error
+		}
+		r.logger.Error("Error fetching notification preference", zap.String("preferenceID", preferenceID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get notification preference %s from firestore: %w", preferenceID, err)
+	}
+	var pref core.NotificationPreference
+	if err := dsnap.DataTo(&pref); err != nil {
+		return nil, fmt.Errorf("failed to decode notification preference %s: %w", preferenceID, err)
+	}
+	pref.ID = dsnap.Ref.ID
+	return &pref, nil
+}
+
+// SavePreference writes the whole preference document.
+func (r *notificationRepository) SavePreference(ctx context.Context, pref *core.NotificationPreference) error {
+	if pref.ID == "" {
+		return fmt.Errorf("notification preference ID cannot be empty")
+	}
+	if _, err := r.client.Collection(notificationPreferenceCollection).Doc(pref.ID).Set(ctx, pref); err != nil {
+		r.logger.Error("Error saving notification preference", zap.String("preferenceID", pref.ID), zap.Error(err))
+		return fmt.Errorf("failed to save notification preference %s: %w", pref.ID, err)
+	}
+	return nil
+}
+
+// DeletePreference removes a preference document.
+func (r *notificationRepository) DeletePreference(ctx context.Context, preferenceID string) error {
+	if _, err := r.client.Collection(notificationPreferenceCollection).Doc(preferenceID).Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		r.logger.Error("Error deleting notification preference", zap.String("preferenceID", preferenceID), zap.Error(err))
+		return fmt.Errorf("failed to delete notification preference %s: %w", preferenceID, err)
+	}
+	return nil
+}
+
+// CreateDelivery creates the delivery's document. Create fails on an existing document,
+// which makes creating a delivery with a deterministic ID idempotent.
+func (r *notificationRepository) CreateDelivery(ctx context.Context, delivery *core.NotificationDelivery) (bool, error) {
+	if delivery.ID == "" {
+		return false, fmt.Errorf("notification delivery ID cannot be empty")
+	}
+	if _, err := r.client.Collection(notificationDeliveryCollection).Doc(delivery.ID).Create(ctx, delivery); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return false, nil // Already created
+		}
+		r.logger.Error("Error creating notification delivery", zap.String("deliveryID", delivery.ID), zap.Error(err))
+		return false, fmt.Errorf("failed to create notification delivery %s: %w", delivery.ID, err)
+	}
+	return true, nil
+}
+
+// UpdateDelivery writes the fields that change with each attempt.
+func (r *notificationRepository) UpdateDelivery(ctx context.Context, delivery *core.NotificationDelivery) error {
+	updates := []firestore.Update{
+		{Path: "status", Value: delivery.Status},
+		{Path: "attempts", Value: delivery.Attempts},
+		{Path: "lastError", Value: delivery.LastError},
+		{Path: "nextAttemptAt", Value: delivery.NextAttemptAt},
+		{Path: "lastAttemptAt", Value: delivery.LastAttemptAt},
+		{Path: "deliveredAt", Value: delivery.DeliveredAt},
+	}
+	if _, err := r.client.Collection(notificationDeliveryCollection).Doc(delivery.ID).Update(ctx, updates); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		r.logger.Error("Error updating notification delivery", zap.String("deliveryID", delivery.ID), zap.Error(err))
+		return fmt.Errorf("failed to update notification delivery %s: %w", delivery.ID, err)
+	}
+	return nil
+}
+
+// ListDueDeliveries retrieves pending deliveries whose next attempt is due.
+func (r *notificationRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*core.NotificationDelivery, error) {
+	query := r.client.Collection(notificationDeliveryCollection).
+		Where("status", "==", string(core.NotificationDeliveryPending)).
+		Where("nextAttemptAt", "<=", now).
+		OrderBy("nextAttemptAt", firestore.Asc).
+		Limit(limit)
+	deliveries, err := r.queryDeliveries(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due notification deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ListDeliveries retrieves a preference's deliveries ordered by creation, newest first,
+// with pagination.
+func (r *notificationRepository) ListDeliveries(ctx context.Context, preferenceID string, limit, offset int) ([]*core.NotificationDelivery, int, error) {
+	if limit <= 0 {
+		limit = 20 // Default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	baseQuery := r.client.Collection(notificationDeliveryCollection).Where("preferenceId", "==", preferenceID)
+
+	// --- Get Total Count using Aggregation ---
+	results, err := baseQuery.NewAggregationQuery().WithCount("all").Get(ctx)
+	if err != nil {
+		r.logger.Error("Error executing notification delivery count aggregation", zap.String("preferenceID", preferenceID), zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count notification deliveries for %s: %w", preferenceID, err)
+	}
+	var totalCount int
+	if aggValue, ok := results["all"].(*firestorepb.Value); ok {
+		totalCount = int(aggValue.GetIntegerValue())
+	} else {
+		r.logger.Warn("Notification delivery count aggregation returned no value, assuming 0", zap.String("preferenceID", preferenceID))
+	}
+
+	// --- Get delivery documents with pagination ---
+	deliveries, err := r.queryDeliveries(ctx, baseQuery.OrderBy("createdAt", firestore.Desc).Offset(offset).Limit(limit))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list notification deliveries for %s: %w", preferenceID, err)
+	}
+	return deliveries, totalCount, nil
+}
+
+// queryDeliveries runs a query and decodes its documents, skipping corrupted ones.
+func (r *notificationRepository) queryDeliveries(ctx context.Context, query firestore.Query) ([]*core.NotificationDelivery, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	deliveries := []*core.NotificationDelivery{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("Error iterating notification delivery documents", zap.Error(err))
+			return nil, err
+		}
+		var delivery core.NotificationDelivery
+		if err := doc.DataTo(&delivery); err != nil {
+			r.logger.Warn("Error converting firestore data to NotificationDelivery struct", zap.String("docID", doc.Ref.ID), zap.Error(err))
+			continue // Skip corrupted document
+		}
+		delivery.ID = doc.Ref.ID
+		deliveries = append(deliveries, &delivery)
+	}
+	return deliveries, nil
+}
@@ -5,6 +5,7 @@ import (
 	"SynDataGen/backend/internal/platform/logger"
 	"context"
 	"fmt"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"go.uber.org/zap"
@@ -79,10 +80,10 @@ func (r *projectRepository) buildProjectQuery(ctx context.Context, userID string
 	// See: https://firebase.google.com/docs/firestore/query-data/queries#query_operators
 	query = query.Where(fmt.Sprintf("teamMembers.%s", userID), "!=", "__non_existent_value__") // Revert to != check
 
-	// TODO: Re-evaluate if filtering by project status is still needed or handled differently with RBAC.
-	// if statusFilter != "" && statusFilter != "all" { // Allow filtering by specific status
-	// 	query = query.Where("status", "==", statusFilter)
-	// }
+	// Filter by project status; an empty filter or "all" returns every status.
+	if statusFilter != "" && statusFilter != "all" {
+		query = query.Where("status", "==", statusFilter)
+	}
 	return query
 }
 
@@ -169,6 +170,71 @@ func (r *projectRepository) CountProjects(ctx context.Context, userID string, st
 	return int(countValue), nil
 }
 
+// customerProjectsQuery constructs the query for a customer's projects, optionally
+// filtered by status.
+func (r *projectRepository) customerProjectsQuery(customerID string, statusFilter string) firestore.Query {
+	query := r.client.Collection(projectsCollection).Where("customerId", "==", customerID)
+	if statusFilter != "" && statusFilter != "all" {
+		query = query.Where("status", "==", statusFilter)
+	}
+	return query
+}
+
+// ListProjectsByCustomer retrieves every project belonging to a customer.
+func (r *projectRepository) ListProjectsByCustomer(ctx context.Context, customerID string, statusFilter string) ([]*core.Project, error) {
+	iter := r.customerProjectsQuery(customerID, statusFilter).Documents(ctx)
+	defer iter.Stop()
+
+	var projects []*core.Project
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("ListProjectsByCustomer: Failed to iterate project documents", zap.Error(err), zap.String("customerID", customerID))
+			return nil, fmt.Errorf("failed to list projects of customer %s: %w", customerID, err)
+		}
+
+		var project core.Project
+		if err := doc.DataTo(&project); err != nil {
+			r.logger.Error("ListProjectsByCustomer: Failed to decode project data", zap.Error(err), zap.String("docID", doc.Ref.ID))
+			continue // Skip problematic document
+		}
+		project.ID = doc.Ref.ID
+		projects = append(projects, &project)
+	}
+	return projects, nil
+}
+
+// ListAllProjects retrieves every project regardless of membership or status.
+func (r *projectRepository) ListAllProjects(ctx context.Context) ([]*core.Project, error) {
+	iter := r.client.Collection(projectsCollection).Documents(ctx)
+	defer iter.Stop()
+
+	var projects []*core.Project
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("ListAllProjects: Failed to iterate project documents", zap.Error(err))
+			return nil, fmt.Errorf("failed to list all projects: %w", err)
+		}
+
+		var project core.Project
+		if err := doc.DataTo(&project); err != nil {
+			r.logger.Error("ListAllProjects: Failed to decode project data", zap.Error(err), zap.String("docID", doc.Ref.ID))
+			continue // Skip problematic document
+		}
+		project.ID = doc.Ref.ID
+		projects = append(projects, &project)
+	}
+
+	return projects, nil
+}
+
 // UpdateProject updates an existing project.
 func (r *projectRepository) UpdateProject(ctx context.Context, project *core.Project) error {
 	if project.ID == "" {
@@ -190,6 +256,34 @@ func (r *projectRepository) UpdateProject(ctx context.Context, project *core.Pro
 	return nil
 }
 
+// UpdateStorageUsage sets only storage.usedStorageBytes, leaving the rest of the document untouched.
+func (r *projectRepository) UpdateStorageUsage(ctx context.Context, projectID string, usedBytes int64) error {
+	docRef := r.client.Collection(projectsCollection).Doc(projectID)
+	_, err := docRef.Update(ctx, []firestore.Update{{Path: "storage.usedStorageBytes", Value: usedBytes}})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		r.logger.Error("Failed to update project storage usage", zap.Error(err), zap.String("projectID", projectID))
+		return fmt.Errorf("failed to update project storage usage: %w", err)
+	}
+	return nil
+}
+
+// UpdateStorageSampledPeriod sets only storage.sampledPeriod, leaving the rest of the document untouched.
+func (r *projectRepository) UpdateStorageSampledPeriod(ctx context.Context, projectID string, period time.Time) error {
+	docRef := r.client.Collection(projectsCollection).Doc(projectID)
+	_, err := docRef.Update(ctx, []firestore.Update{{Path: "storage.sampledPeriod", Value: period}})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		r.logger.Error("Failed to update project storage sampled period", zap.Error(err), zap.String("projectID", projectID))
+		return fmt.Errorf("failed to update project storage sampled period: %w", err)
+	}
+	return nil
+}
+
 // DeleteProject removes a project.
 func (r *projectRepository) DeleteProject(ctx context.Context, id string) error {
 	_, err := r.client.Collection(projectsCollection).Doc(id).Delete(ctx)
@@ -0,0 +1,55 @@
+package firestore
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	firestorepb "google.golang.org/genproto/googleapis/firestore/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// queryFilters returns the field equality filters of a query, keyed by field path.
+func queryFilters(t *testing.T, query firestore.Query) map[string]string {
+	t.Helper()
+	data, err := query.Serialize()
+	require.NoError(t, err)
+	var req firestorepb.RunQueryRequest
+	require.NoError(t, proto.Unmarshal(data, &req))
+
+	where := req.GetStructuredQuery().GetWhere()
+	filters := []*firestorepb.StructuredQuery_Filter{where}
+	if composite := where.GetCompositeFilter(); composite != nil {
+		filters = composite.GetFilters()
+	}
+	fields := make(map[string]string)
+	for _, f := range filters {
+		field := f.GetFieldFilter()
+		require.NotNil(t, field, "only field filters are expected")
+		assert.Equal(t, firestorepb.StructuredQuery_FieldFilter_EQUAL, field.GetOp())
+		fields[field.GetField().GetFieldPath()] = field.GetValue().GetStringValue()
+	}
+	return fields
+}
+
+func TestProjectRepository_CustomerProjectsQuery(t *testing.T) {
+	client, err := firestore.NewClient(context.Background(), "test-project", option.WithoutAuthentication())
+	require.NoError(t, err)
+	defer client.Close()
+	repo := &projectRepository{client: client}
+
+	t.Run("FiltersByCustomerAndStatus", func(t *testing.T) {
+		fields := queryFilters(t, repo.customerProjectsQuery("cust-1", "active"))
+
+		assert.Equal(t, map[string]string{"customerId": "cust-1", "status": "active"}, fields)
+	})
+
+	t.Run("AllStatuses", func(t *testing.T) {
+		fields := queryFilters(t, repo.customerProjectsQuery("cust-1", "all"))
+
+		assert.Equal(t, map[string]string{"customerId": "cust-1"}, fields, "the customer is never matched by team membership")
+	})
+}
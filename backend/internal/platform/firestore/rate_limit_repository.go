@@ -0,0 +1,75 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"SynDataGen/backend/internal/core"
+
+	"go.uber.org/zap"
+)
+
+const rateLimitCollection = "rateLimits"
+
+// rateLimitDoc is the event count of one key in one window. ExpiresAt is meant for a
+// Firestore TTL policy, so counters of past windows are removed.
+type rateLimitDoc struct {
+	Key       string    `firestore:"key"`
+	Count     int       `firestore:"count"`
+	ExpiresAt time.Time `firestore:"expiresAt"`
+}
+
+// rateLimitRepository implements the core.RateLimitRepository interface using Firestore.
+type rateLimitRepository struct {
+	client *firestore.Client
+	logger *zap.Logger
+}
+
+// NewRateLimitRepository creates a new Firestore rate limit repository.
+func NewRateLimitRepository(client *firestore.Client, logger *zap.Logger) core.RateLimitRepository {
+	if logger == nil {
+		logger = zap.L() // Use global logger if none provided
+	}
+	return &rateLimitRepository{
+		client: client,
+		logger: logger.Named("RateLimitRepository"),
+	}
+}
+
+// TakeRateLimitToken increments the window's counter inside a transaction, so replicas
+// counting the same key cannot exceed the limit together.
+func (r *rateLimitRepository) TakeRateLimitToken(ctx context.Context, key string, windowStart time.Time, window time.Duration, limit int) (bool, error) {
+	docID := fmt.Sprintf("%s@%d", strings.ReplaceAll(key, "/", "_"), windowStart.Unix())
+	docRef := r.client.Collection(rateLimitCollection).Doc(docID)
+	taken := false
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		taken = false // The function may be retried
+		counter := rateLimitDoc{Key: key, ExpiresAt: windowStart.Add(2 * window)}
+		dsnap, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := dsnap.DataTo(&counter); err != nil {
+				return err
+			}
+		}
+		if counter.Count >= limit {
+			return nil
+		}
+		counter.Count++
+		taken = true
+		return tx.Set(docRef, counter)
+	})
+	if err != nil {
+		r.logger.Error("Error taking rate limit token", zap.String("key", key), zap.Error(err))
+		return false, fmt.Errorf("failed to take rate limit token for %s: %w", key, err)
+	}
+	return taken, nil
+}
@@ -0,0 +1,235 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"SynDataGen/backend/internal/core"
+
+	"go.uber.org/zap"
+	firestorepb "google.golang.org/genproto/googleapis/firestore/v1"
+)
+
+const scheduleCollection = "schedules"
+
+// scheduleRepository implements the core.ScheduleRepository interface using Firestore.
+type scheduleRepository struct {
+	client *firestore.Client
+	logger *zap.Logger
+}
+
+// NewScheduleRepository creates a new Firestore schedule repository.
+func NewScheduleRepository(client *firestore.Client, logger *zap.Logger) core.ScheduleRepository {
+	if logger == nil {
+		logger = zap.L() // Use global logger if none provided
+	}
+	return &scheduleRepository{
+		client: client,
+		logger: logger.Named("ScheduleRepository"),
+	}
+}
+
+// CreateSchedule adds a new schedule document.
+func (r *scheduleRepository) CreateSchedule(ctx context.Context, schedule *core.Schedule) error {
+	if schedule.ID == "" {
+		return fmt.Errorf("schedule ID cannot be empty") // Ensure ID is set before creation
+	}
+	r.logger.Info("Creating schedule document", zap.String("scheduleID", schedule.ID))
+	if _, err := r.client.Collection(scheduleCollection).Doc(schedule.ID).Create(ctx, schedule); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return fmt.Errorf("schedule with ID %s already exists: %w", schedule.ID, core.ErrConflict)
+		}
+		r.logger.Error("Error creating schedule document", zap.String("scheduleID", schedule.ID), zap.Error(err))
+		return fmt.Errorf("failed to create schedule %s in firestore: %w", schedule.ID, err)
+	}
+	return nil
+}
+
+// GetSchedule retrieves a schedule document by its ID.
+func (r *scheduleRepository) GetSchedule(ctx context.Context, scheduleID string) (*core.Schedule, error) {
+	dsnap, err := r.client.Collection(scheduleCollection).Doc(scheduleID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, core.ErrNotFound // Use predefined error
+		}
+		r.logger.Error("Error fetching schedule document", zap.String("scheduleID", scheduleID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get schedule %s from firestore: %w", scheduleID, err)
+	}
+	return decodeSchedule(dsnap)
+}
+
+// decodeSchedule converts a schedule document to a Schedule.
+func decodeSchedule(dsnap *firestore.DocumentSnapshot) (*core.Schedule, error) {
+	var schedule core.Schedule
+	if err := dsnap.DataTo(&schedule); err != nil {
+		return nil, fmt.Errorf("failed to decode schedule %s: %w", dsnap.Ref.ID, err)
+	}
+	schedule.ID = dsnap.Ref.ID
+	return &schedule, nil
+}
+
+// ListSchedulesByProjectID retrieves a project's schedules ordered by name, with pagination.
+func (r *scheduleRepository) ListSchedulesByProjectID(ctx context.Context, projectID string, limit, offset int) ([]*core.Schedule, int, error) {
+	if limit <= 0 {
+		limit = 20 // Default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	baseQuery := r.client.Collection(scheduleCollection).Where("projectId", "==", projectID)
+
+	// --- Get Total Count using Aggregation ---
+	results, err := baseQuery.NewAggregationQuery().WithCount("all").Get(ctx)
+	if err != nil {
+		r.logger.Error("Error executing schedule count aggregation", zap.String("projectID", projectID), zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count schedules for project %s: %w", projectID, err)
+	}
+	var totalCount int
+	if aggValue, ok := results["all"].(*firestorepb.Value); ok {
+		totalCount = int(aggValue.GetIntegerValue())
+	} else {
+		r.logger.Warn("Schedule count aggregation returned no value, assuming 0", zap.String("projectID", projectID))
+	}
+
+	// --- Get schedule documents with pagination ---
+	schedules, err := r.querySchedules(ctx, baseQuery.OrderBy("name", firestore.Asc).Offset(offset).Limit(limit))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list schedules for project %s: %w", projectID, err)
+	}
+	return schedules, totalCount, nil
+}
+
+// querySchedules runs a query and decodes its documents, skipping corrupted ones.
+func (r *scheduleRepository) querySchedules(ctx context.Context, query firestore.Query) ([]*core.Schedule, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	schedules := []*core.Schedule{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("Error iterating schedule documents", zap.Error(err))
+			return nil, err
+		}
+		schedule, err := decodeSchedule(doc)
+		if err != nil {
+			r.logger.Warn("Error converting firestore data to Schedule struct", zap.String("docID", doc.Ref.ID), zap.Error(err))
+			continue // Skip corrupted document
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
+
+// UpdateSchedule writes the user-editable fields and NextRunAt of a schedule.
+func (r *scheduleRepository) UpdateSchedule(ctx context.Context, schedule *core.Schedule) error {
+	r.logger.Info("Updating schedule", zap.String("scheduleID", schedule.ID))
+	updates := []firestore.Update{
+		{Path: "name", Value: schedule.Name},
+		{Path: "cron", Value: schedule.Cron},
+		{Path: "timezone", Value: schedule.Timezone},
+		{Path: "enabled", Value: schedule.Enabled},
+		{Path: "overlapPolicy", Value: schedule.OverlapPolicy},
+		{Path: "templateId", Value: schedule.TemplateID},
+		{Path: "templateVersion", Value: schedule.TemplateVersion},
+		{Path: "templateVariables", Value: schedule.TemplateVariables},
+		{Path: "jobType", Value: schedule.JobType},
+		{Path: "jobConfig", Value: schedule.JobConfig},
+		{Path: "retryPolicy", Value: schedule.RetryPolicy},
+		{Path: "nextRunAt", Value: schedule.NextRunAt},
+		{Path: "updatedBy", Value: schedule.UpdatedBy},
+		{Path: "updatedAt", Value: schedule.UpdatedAt},
+	}
+	if _, err := r.client.Collection(scheduleCollection).Doc(schedule.ID).Update(ctx, updates); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		r.logger.Error("Error updating schedule", zap.String("scheduleID", schedule.ID), zap.Error(err))
+		return fmt.Errorf("failed to update schedule %s: %w", schedule.ID, err)
+	}
+	return nil
+}
+
+// DeleteSchedule removes a schedule document.
+func (r *scheduleRepository) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	r.logger.Info("Deleting schedule", zap.String("scheduleID", scheduleID))
+	if _, err := r.client.Collection(scheduleCollection).Doc(scheduleID).Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		r.logger.Error("Error deleting schedule", zap.String("scheduleID", scheduleID), zap.Error(err))
+		return fmt.Errorf("failed to delete schedule %s: %w", scheduleID, err)
+	}
+	return nil
+}
+
+// ListDueSchedules retrieves enabled schedules due at or before now. Disabled schedules have
+// no NextRunAt, so the range filter alone excludes them; enabled is checked for safety.
+func (r *scheduleRepository) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*core.Schedule, error) {
+	query := r.client.Collection(scheduleCollection).
+		Where("enabled", "==", true).
+		Where("nextRunAt", "<=", now).
+		OrderBy("nextRunAt", firestore.Asc).
+		Limit(limit)
+	schedules, err := r.querySchedules(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due schedules: %w", err)
+	}
+	return schedules, nil
+}
+
+// ClaimScheduleRun advances NextRunAt inside a transaction, failing with core.ErrConflict
+// if the stored NextRunAt is no longer due.
+func (r *scheduleRepository) ClaimScheduleRun(ctx context.Context, scheduleID string, due time.Time, next *time.Time) error {
+	docRef := r.client.Collection(scheduleCollection).Doc(scheduleID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		dsnap, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		schedule, err := decodeSchedule(dsnap)
+		if err != nil {
+			return err
+		}
+		if !schedule.Enabled || schedule.NextRunAt == nil || !schedule.NextRunAt.Equal(due) {
+			return fmt.Errorf("schedule %s is no longer due at %s: %w", scheduleID, due.Format(time.RFC3339), core.ErrConflict)
+		}
+		return tx.Update(docRef, []firestore.Update{{Path: "nextRunAt", Value: next}})
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		return fmt.Errorf("failed to claim run of schedule %s: %w", scheduleID, err)
+	}
+	return nil
+}
+
+// RecordScheduleRun stores the outcome of a run on the schedule document.
+func (r *scheduleRepository) RecordScheduleRun(ctx context.Context, scheduleID string, run core.ScheduleRun) error {
+	updates := []firestore.Update{
+		{Path: "lastRunAt", Value: run.RanAt},
+		{Path: "lastRunStatus", Value: run.Status},
+		{Path: "lastRunError", Value: run.Error},
+	}
+	if run.JobID != "" {
+		updates = append(updates, firestore.Update{Path: "lastJobId", Value: run.JobID})
+	}
+	if _, err := r.client.Collection(scheduleCollection).Doc(scheduleID).Update(ctx, updates); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		r.logger.Error("Error recording schedule run", zap.String("scheduleID", scheduleID), zap.Error(err))
+		return fmt.Errorf("failed to record run of schedule %s: %w", scheduleID, err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"SynDataGen/backend/internal/core"
+
+	"go.uber.org/zap"
+	firestorepb "google.golang.org/genproto/googleapis/firestore/v1"
+)
+
+const (
+	templateCollection        = "jobTemplates"
+	templateVersionCollection = "versions" // Subcollection of a template, keyed by version number
+)
+
+// templateRepository implements the core.JobTemplateRepository interface using Firestore.
+type templateRepository struct {
+	client *firestore.Client
+	logger *zap.Logger
+}
+
+// NewTemplateRepository creates a new Firestore job template repository.
+func NewTemplateRepository(client *firestore.Client, logger *zap.Logger) core.JobTemplateRepository {
+	if logger == nil {
+		logger = zap.L() // Use global logger if none provided
+	}
+	return &templateRepository{
+		client: client,
+		logger: logger.Named("TemplateRepository"),
+	}
+}
+
+// versionRef returns the document holding one version of a template.
+func (r *templateRepository) versionRef(templateID string, version int) *firestore.DocumentRef {
+	return r.client.Collection(templateCollection).Doc(templateID).Collection(templateVersionCollection).Doc(strconv.Itoa(version))
+}
+
+// SaveTemplateVersion writes the version snapshot and the template document in one
+// transaction. Creating the snapshot fails if the version exists, so of two concurrent
+// updates from the same version only one is saved.
+func (r *templateRepository) SaveTemplateVersion(ctx context.Context, template *core.JobTemplate) error {
+	if template.ID == "" {
+		return fmt.Errorf("template ID cannot be empty") // Ensure ID is set before saving
+	}
+	r.logger.Info("Saving template version", zap.String("templateID", template.ID), zap.Int("version", template.Version))
+
+	headRef := r.client.Collection(templateCollection).Doc(template.ID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if err := tx.Create(r.versionRef(template.ID, template.Version), template); err != nil {
+			return err
+		}
+		return tx.Set(headRef, template)
+	})
+	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			r.logger.Info("Template version already exists", zap.String("templateID", template.ID), zap.Int("version", template.Version))
+			return fmt.Errorf("template %s version %d already exists: %w", template.ID, template.Version, core.ErrConflict)
+		}
+		r.logger.Error("Error saving template version", zap.String("templateID", template.ID), zap.Error(err))
+		return fmt.Errorf("failed to save template %s version %d in firestore: %w", template.ID, template.Version, err)
+	}
+	return nil
+}
+
+// GetTemplate retrieves the current version of a template by its ID.
+func (r *templateRepository) GetTemplate(ctx context.Context, templateID string) (*core.JobTemplate, error) {
+	return r.getTemplateDoc(ctx, r.client.Collection(templateCollection).Doc(templateID), templateID)
+}
+
+// GetTemplateVersion retrieves one version of a template.
+func (r *templateRepository) GetTemplateVersion(ctx context.Context, templateID string, version int) (*core.JobTemplate, error) {
+	return r.getTemplateDoc(ctx, r.versionRef(templateID, version), templateID)
+}
+
+func (r *templateRepository) getTemplateDoc(ctx context.Context, ref *firestore.DocumentRef, templateID string) (*core.JobTemplate, error) {
+	dsnap, err := ref.Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, core.ErrNotFound // Use predefined error
+		}
+		r.logger.Error("Error fetching template document", zap.String("templateID", templateID), zap.String("path", ref.Path), zap.Error(err))
+		return nil, fmt.Errorf("failed to get template %s from firestore: %w", templateID, err)
+	}
+
+	var template core.JobTemplate
+	if err := dsnap.DataTo(&template); err != nil {
+		r.logger.Error("Error converting firestore data to JobTemplate struct", zap.String("templateID", templateID), zap.Error(err))
+		return nil, fmt.Errorf("failed to decode template %s: %w", templateID, err)
+	}
+	template.ID = templateID
+	return &template, nil
+}
+
+// ListTemplatesByProjectID retrieves a project's templates ordered by name, with pagination.
+func (r *templateRepository) ListTemplatesByProjectID(ctx context.Context, projectID string, limit, offset int) ([]*core.JobTemplate, int, error) {
+	if limit <= 0 {
+		limit = 20 // Default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	baseQuery := r.client.Collection(templateCollection).Where("projectId", "==", projectID)
+
+	// --- Get Total Count using Aggregation ---
+	results, err := baseQuery.NewAggregationQuery().WithCount("all").Get(ctx)
+	if err != nil {
+		r.logger.Error("Error executing template count aggregation", zap.String("projectID", projectID), zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count templates for project %s: %w", projectID, err)
+	}
+	var totalCount int
+	if aggValue, ok := results["all"].(*firestorepb.Value); ok {
+		totalCount = int(aggValue.GetIntegerValue())
+	} else {
+		r.logger.Warn("Template count aggregation returned no value, assuming 0", zap.String("projectID", projectID))
+	}
+
+	// --- Get template documents with pagination ---
+	iter := baseQuery.OrderBy("name", firestore.Asc).Offset(offset).Limit(limit).Documents(ctx)
+	defer iter.Stop()
+
+	templates := []*core.JobTemplate{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("Error iterating template documents", zap.String("projectID", projectID), zap.Error(err))
+			return nil, 0, fmt.Errorf("failed to iterate templates for project %s: %w", projectID, err)
+		}
+
+		var template core.JobTemplate
+		if err := doc.DataTo(&template); err != nil {
+			r.logger.Warn("Error converting firestore data to JobTemplate struct during list", zap.String("docID", doc.Ref.ID), zap.Error(err))
+			continue // Skip corrupted document
+		}
+		template.ID = doc.Ref.ID
+		templates = append(templates, &template)
+	}
+	return templates, totalCount, nil
+}
+
+// DeleteTemplate removes a template document. Its versions subcollection is left in place.
+func (r *templateRepository) DeleteTemplate(ctx context.Context, templateID string) error {
+	r.logger.Info("Deleting template", zap.String("templateID", templateID))
+	if _, err := r.client.Collection(templateCollection).Doc(templateID).Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		r.logger.Error("Error deleting template", zap.String("templateID", templateID), zap.Error(err))
+		return fmt.Errorf("failed to delete template %s: %w", templateID, err)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"SynDataGen/backend/internal/core"
+
+	"go.uber.org/zap"
+)
+
+const usageCollection = "usageEvents"
+
+// usageRepository implements the core.UsageRepository interface using Firestore.
+type usageRepository struct {
+	client *firestore.Client
+	logger *zap.Logger
+}
+
+// NewUsageRepository creates a new Firestore usage repository.
+func NewUsageRepository(client *firestore.Client, logger *zap.Logger) core.UsageRepository {
+	if logger == nil {
+		logger = zap.L() // Use global logger if none provided
+	}
+	return &usageRepository{
+		client: client,
+		logger: logger.Named("UsageRepository"),
+	}
+}
+
+// RecordUsage creates the event's document. Create fails on an existing document, which
+// makes recording an event with a deterministic ID idempotent.
+func (r *usageRepository) RecordUsage(ctx context.Context, event *core.UsageEvent) (bool, error) {
+	if event.ID == "" {
+		return false, fmt.Errorf("usage event ID cannot be empty") // Ensure ID is set before creation
+	}
+	if _, err := r.client.Collection(usageCollection).Doc(event.ID).Create(ctx, event); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return false, nil // Already recorded
+		}
+		r.logger.Error("Error creating usage event document", zap.String("eventID", event.ID), zap.Error(err))
+		return false, fmt.Errorf("failed to record usage event %s: %w", event.ID, err)
+	}
+	return true, nil
+}
+
+// ListUsage queries each chunk of project IDs for events in the time range.
+func (r *usageRepository) ListUsage(ctx context.Context, projectIDs []string, from, to time.Time) ([]*core.UsageEvent, error) {
+	events := []*core.UsageEvent{}
+	for _, chunk := range chunkSlice(projectIDs, firestoreInLimit) {
+		query := r.client.Collection(usageCollection).
+			Where("projectId", "in", chunk).
+			Where("occurredAt", ">=", from).
+			Where("occurredAt", "<", to)
+		iter := query.Documents(ctx)
+		for {
+			doc, err := iter.Next()
+			if err == iterator.Done {
+				break
+			}
+			if err != nil {
+				iter.Stop()
+				r.logger.Error("Error iterating usage events", zap.Strings("projectIds", chunk), zap.Error(err))
+				return nil, fmt.Errorf("failed to list usage events: %w", err)
+			}
+			var event core.UsageEvent
+			if err := doc.DataTo(&event); err != nil {
+				r.logger.Warn("Failed to decode usage event document", zap.String("docId", doc.Ref.ID), zap.Error(err))
+				continue // Skip bad document
+			}
+			event.ID = doc.Ref.ID
+			events = append(events, &event)
+		}
+		iter.Stop()
+	}
+	return events, nil
+}
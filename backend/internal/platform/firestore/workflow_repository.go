@@ -0,0 +1,191 @@
+package firestore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"SynDataGen/backend/internal/core"
+
+	"go.uber.org/zap"
+	firestorepb "google.golang.org/genproto/googleapis/firestore/v1"
+)
+
+const workflowCollection = "workflows"
+
+// workflowRepository implements the core.WorkflowRepository interface using Firestore.
+type workflowRepository struct {
+	client *firestore.Client
+	logger *zap.Logger
+}
+
+// NewWorkflowRepository creates a new Firestore workflow repository.
+func NewWorkflowRepository(client *firestore.Client, logger *zap.Logger) core.WorkflowRepository {
+	if logger == nil {
+		logger = zap.L() // Use global logger if none provided
+	}
+	return &workflowRepository{
+		client: client,
+		logger: logger.Named("WorkflowRepository"),
+	}
+}
+
+// CreateWorkflow adds a new workflow document.
+func (r *workflowRepository) CreateWorkflow(ctx context.Context, workflow *core.Workflow) error {
+	if workflow.ID == "" {
+		return fmt.Errorf("workflow ID cannot be empty") // Ensure ID is set before creation
+	}
+	r.logger.Info("Creating workflow document", zap.String("workflowID", workflow.ID))
+	if _, err := r.client.Collection(workflowCollection).Doc(workflow.ID).Create(ctx, workflow); err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return fmt.Errorf("workflow with ID %s already exists: %w", workflow.ID, core.ErrConflict)
+		}
+		r.logger.Error("Error creating workflow document", zap.String("workflowID", workflow.ID), zap.Error(err))
+		return fmt.Errorf("failed to create workflow %s in firestore: %w", workflow.ID, err)
+	}
+	return nil
+}
+
+// GetWorkflow retrieves a workflow document by its ID.
+func (r *workflowRepository) GetWorkflow(ctx context.Context, workflowID string) (*core.Workflow, error) {
+	dsnap, err := r.client.Collection(workflowCollection).Doc(workflowID).Get(ctx)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, core.ErrNotFound // Use predefined error
+		}
+		r.logger.Error("Error fetching workflow document", zap.String("workflowID", workflowID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get workflow %s from firestore: %w", workflowID, err)
+	}
+	return decodeWorkflow(dsnap)
+}
+
+// decodeWorkflow converts a workflow document to a Workflow.
+func decodeWorkflow(dsnap *firestore.DocumentSnapshot) (*core.Workflow, error) {
+	var workflow core.Workflow
+	if err := dsnap.DataTo(&workflow); err != nil {
+		return nil, fmt.Errorf("failed to decode workflow %s: %w", dsnap.Ref.ID, err)
+	}
+	workflow.ID = dsnap.Ref.ID
+	return &workflow, nil
+}
+
+// ListWorkflowsByProjectID retrieves a project's workflows newest first, with pagination.
+func (r *workflowRepository) ListWorkflowsByProjectID(ctx context.Context, projectID string, limit, offset int) ([]*core.Workflow, int, error) {
+	if limit <= 0 {
+		limit = 20 // Default limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	baseQuery := r.client.Collection(workflowCollection).Where("projectId", "==", projectID)
+
+	// --- Get Total Count using Aggregation ---
+	results, err := baseQuery.NewAggregationQuery().WithCount("all").Get(ctx)
+	if err != nil {
+		r.logger.Error("Error executing workflow count aggregation", zap.String("projectID", projectID), zap.Error(err))
+		return nil, 0, fmt.Errorf("failed to count workflows for project %s: %w", projectID, err)
+	}
+	var totalCount int
+	if aggValue, ok := results["all"].(*firestorepb.Value); ok {
+		totalCount = int(aggValue.GetIntegerValue())
+	} else {
+		r.logger.Warn("Workflow count aggregation returned no value, assuming 0", zap.String("projectID", projectID))
+	}
+
+	// --- Get workflow documents with pagination ---
+	workflows, err := r.queryWorkflows(ctx, baseQuery.OrderBy("createdAt", firestore.Desc).Offset(offset).Limit(limit))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list workflows for project %s: %w", projectID, err)
+	}
+	return workflows, totalCount, nil
+}
+
+// ListActiveWorkflows retrieves pending and running workflows, oldest first.
+func (r *workflowRepository) ListActiveWorkflows(ctx context.Context, limit int) ([]*core.Workflow, error) {
+	query := r.client.Collection(workflowCollection).
+		Where("status", "in", []string{string(core.JobStatusPending), string(core.JobStatusRunning)}).
+		OrderBy("createdAt", firestore.Asc).
+		Limit(limit)
+	workflows, err := r.queryWorkflows(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list active workflows: %w", err)
+	}
+	return workflows, nil
+}
+
+// queryWorkflows runs a query and decodes its documents, skipping corrupted ones.
+func (r *workflowRepository) queryWorkflows(ctx context.Context, query firestore.Query) ([]*core.Workflow, error) {
+	iter := query.Documents(ctx)
+	defer iter.Stop()
+
+	workflows := []*core.Workflow{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			r.logger.Error("Error iterating workflow documents", zap.Error(err))
+			return nil, err
+		}
+		workflow, err := decodeWorkflow(doc)
+		if err != nil {
+			r.logger.Warn("Error converting firestore data to Workflow struct", zap.String("docID", doc.Ref.ID), zap.Error(err))
+			continue // Skip corrupted document
+		}
+		workflows = append(workflows, workflow)
+	}
+	return workflows, nil
+}
+
+// SaveWorkflowState writes a workflow's run state inside a transaction, failing with
+// core.ErrConflict if the stored workflow was saved since prevUpdatedAt.
+func (r *workflowRepository) SaveWorkflowState(ctx context.Context, workflow *core.Workflow, prevUpdatedAt time.Time) error {
+	docRef := r.client.Collection(workflowCollection).Doc(workflow.ID)
+	err := r.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		dsnap, err := tx.Get(docRef)
+		if err != nil {
+			return err
+		}
+		stored, err := decodeWorkflow(dsnap)
+		if err != nil {
+			return err
+		}
+		if !stored.UpdatedAt.Equal(prevUpdatedAt) {
+			return fmt.Errorf("workflow %s was changed concurrently: %w", workflow.ID, core.ErrConflict)
+		}
+		return tx.Update(docRef, []firestore.Update{
+			{Path: "status", Value: workflow.Status},
+			{Path: "progress", Value: workflow.Progress},
+			{Path: "steps", Value: workflow.Steps},
+			{Path: "error", Value: workflow.Error},
+			{Path: "updatedAt", Value: workflow.UpdatedAt},
+			{Path: "completedAt", Value: workflow.CompletedAt},
+		})
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		return fmt.Errorf("failed to save state of workflow %s: %w", workflow.ID, err)
+	}
+	return nil
+}
+
+// DeleteWorkflow removes a workflow document.
+func (r *workflowRepository) DeleteWorkflow(ctx context.Context, workflowID string) error {
+	r.logger.Info("Deleting workflow", zap.String("workflowID", workflowID))
+	if _, err := r.client.Collection(workflowCollection).Doc(workflowID).Delete(ctx, firestore.Exists); err != nil {
+		if status.Code(err) == codes.NotFound {
+			return core.ErrNotFound
+		}
+		r.logger.Error("Error deleting workflow", zap.String("workflowID", workflowID), zap.Error(err))
+		return fmt.Errorf("failed to delete workflow %s: %w", workflowID, err)
+	}
+	return nil
+}
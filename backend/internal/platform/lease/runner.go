@@ -0,0 +1,66 @@
+// Package lease runs periodic background tasks on one replica at a time.
+package lease
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Runner runs a task once per interval on whichever replica holds a named lease. Every
+// replica runs one; the lease outlives two ticks, so the holder keeps it through a slow
+// tick and another replica takes over within two intervals if the holder stops.
+type Runner struct {
+	leaseRepo core.LeaseRepository
+	name      string
+	holderID  string
+	interval  time.Duration
+}
+
+// NewRunner creates a Runner for the lease name, held as holderID.
+func NewRunner(leaseRepo core.LeaseRepository, name, holderID string, interval time.Duration) *Runner {
+	if leaseRepo == nil {
+		panic("lease.NewRunner: leaseRepo is required")
+	}
+	if name == "" || holderID == "" {
+		panic("lease.NewRunner: name and holderID are required")
+	}
+	if interval <= 0 {
+		panic("lease.NewRunner: interval must be positive")
+	}
+	return &Runner{leaseRepo: leaseRepo, name: name, holderID: holderID, interval: interval}
+}
+
+// Run calls TickOnce immediately and then once per interval until ctx is cancelled,
+// logging ticks that fail.
+func (r *Runner) Run(ctx context.Context, tick func(ctx context.Context) error) {
+	log := logger.Logger.With(zap.String("lease", r.name), zap.String("holderID", r.holderID))
+	log.Info("Leased task started", zap.Duration("interval", r.interval))
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.TickOnce(ctx, tick); err != nil {
+			log.Error("Leased task tick finished with errors", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			log.Info("Leased task stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// TickOnce takes or renews the lease and runs tick if this replica holds it. It reports
+// whether tick ran.
+func (r *Runner) TickOnce(ctx context.Context, tick func(ctx context.Context) error) (bool, error) {
+	acquired, err := r.leaseRepo.AcquireLease(ctx, r.name, r.holderID, 2*r.interval)
+	if err != nil || !acquired {
+		return false, err // Without the lease another replica runs the task
+	}
+	return true, tick(ctx)
+}
@@ -0,0 +1,90 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockLeaseRepository is a mock implementation of core.LeaseRepository.
+type MockLeaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockLeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, name, holder, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestRunner_TickOnce(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_RunsWhileHoldingLease", func(t *testing.T) {
+		leases := new(MockLeaseRepository)
+		leases.On("AcquireLease", ctx, "task", "replica-1", 2*time.Minute).Return(true, nil).Once()
+		runner := NewRunner(leases, "task", "replica-1", time.Minute)
+		tickErr := errors.New("partial failure")
+
+		ran, err := runner.TickOnce(ctx, func(context.Context) error { return tickErr })
+
+		assert.True(t, ran)
+		assert.ErrorIs(t, err, tickErr)
+		leases.AssertExpectations(t)
+	})
+
+	t.Run("Success_SkipsWithoutLease", func(t *testing.T) {
+		leases := new(MockLeaseRepository)
+		leases.On("AcquireLease", ctx, "task", "replica-2", 2*time.Minute).Return(false, nil).Once()
+		runner := NewRunner(leases, "task", "replica-2", time.Minute)
+
+		ran, err := runner.TickOnce(ctx, func(context.Context) error {
+			t.Fatal("tick ran without the lease")
+			return nil
+		})
+
+		assert.False(t, ran)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Failure_LeaseError", func(t *testing.T) {
+		leases := new(MockLeaseRepository)
+		leaseErr := errors.New("unavailable")
+		leases.On("AcquireLease", ctx, "task", "replica-1", 2*time.Minute).Return(false, leaseErr).Once()
+		runner := NewRunner(leases, "task", "replica-1", time.Minute)
+
+		ran, err := runner.TickOnce(ctx, func(context.Context) error { return nil })
+
+		assert.False(t, ran)
+		assert.ErrorIs(t, err, leaseErr)
+	})
+}
+
+func TestRunner_Run(t *testing.T) {
+	leases := new(MockLeaseRepository)
+	leases.On("AcquireLease", mock.Anything, "task", "replica-1", 2*time.Hour).Return(true, nil)
+	runner := NewRunner(leases, "task", "replica-1", time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks := 0
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		runner.Run(ctx, func(context.Context) error {
+			ticks++
+			cancel() // Stop after the first tick
+			return nil
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not stop after ctx was cancelled")
+	}
+	require.Equal(t, 1, ticks, "the first tick runs immediately")
+}
@@ -6,12 +6,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"go.uber.org/zap" // Assuming use of global logger
 )
 
@@ -64,6 +68,19 @@ type dataGenJobStage struct {
 	Status string `json:"status"`
 }
 
+type dataGenJobLogsResponse struct {
+	JobID string            `json:"job_id"`
+	Logs  []dataGenLogEntry `json:"logs"`
+}
+
+type dataGenLogEntry struct {
+	Sequence  int64     `json:"sequence"`
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Stage     string    `json:"stage,omitempty"`
+	Message   string    `json:"message"`
+}
+
 type dataGenJobActionResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message,omitempty"`
@@ -73,19 +90,33 @@ type dataGenJobActionResponse struct {
 
 // dataGenPipelineClient implements the job.PipelineClient interface
 type dataGenPipelineClient struct {
-	baseURL    string
-	httpClient *http.Client
-	logger     *zap.Logger
-	// TODO: Add mechanism to obtain OAuth token (e.g., config, service)
-	// tokenSource TokenSource
+	baseURL     string
+	httpClient  *http.Client
+	logger      *zap.Logger
+	tokenSource TokenSource
+	retry       RetryPolicy
+	breaker     *circuitBreaker
+
+	progressMu sync.Mutex
+	progress   map[string]int // Progress from the last status check of each unfinished job
 }
 
+// Ensure dataGenPipelineClient satisfies the progress interface.
+var _ job.ProgressReporter = (*dataGenPipelineClient)(nil)
+
 // Config holds configuration for the DataGen Pipeline Client.
 type Config struct {
 	BaseURL string // e.g., "http://datagen-pipeline.internal:8000"
 	Timeout time.Duration
 	Logger  *zap.Logger
-	// TODO: Add configuration for authentication (e.g., token URL, credentials)
+	// TokenSource authenticates requests (see NewClientCredentialsTokenSource,
+	// NewGCPIDTokenSource and NewStaticTokenSource). Requests are sent without an
+	// Authorization header when nil.
+	TokenSource TokenSource
+	// Retry and CircuitBreaker tune how transient failures are handled; zero values use
+	// the defaults.
+	Retry          RetryPolicy
+	CircuitBreaker CircuitBreakerConfig
 }
 
 // NewDataGenPipelineClient creates a new client for the DataGen v2 API.
@@ -100,18 +131,134 @@ func NewDataGenPipelineClient(cfg Config) (job.PipelineClient, error) {
 		cfg.Logger = zap.L() // Use global logger if none provided
 	}
 
-	// TODO: Initialize token source based on config
+	if cfg.TokenSource == nil {
+		cfg.Logger.Warn("DataGen Pipeline client has no token source; requests will be unauthenticated")
+	}
 
 	return &dataGenPipelineClient{
 		baseURL: cfg.BaseURL,
 		httpClient: &http.Client{
 			Timeout: cfg.Timeout,
 		},
-		logger: cfg.Logger.Named("DataGenPipelineClient"),
-		// tokenSource: initializedTokenSource,
+		logger:      cfg.Logger.Named("DataGenPipelineClient"),
+		tokenSource: cfg.TokenSource,
+		retry:       cfg.Retry.withDefaults(),
+		breaker:     newCircuitBreaker(cfg.CircuitBreaker),
+		progress:    make(map[string]int),
 	}, nil
 }
 
+// apiCall describes one DataGen API request and the status that marks it successful.
+type apiCall struct {
+	method         string
+	endpoint       string
+	body           []byte
+	idempotencyKey string
+	wantStatus     int
+}
+
+// do sends a request, retrying transient failures with backoff while the circuit breaker
+// allows. Errors wrap job.ErrPipelineUnavailable when the pipeline may recover.
+func (c *dataGenPipelineClient) do(ctx context.Context, call apiCall) ([]byte, error) {
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		if ok, wait := c.breaker.allow(); !ok {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, fmt.Errorf("%w: %w (next attempt in %s)", job.ErrPipelineUnavailable, ErrCircuitOpen, wait.Round(time.Second))
+		}
+
+		body, err := c.send(ctx, call)
+		if err != nil && ctx.Err() != nil {
+			c.breaker.release() // Our own cancellation says nothing about the pipeline's health
+			return nil, err
+		}
+		var apiErr *APIError
+		throttled := errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests
+		c.breaker.record(errors.Is(err, job.ErrPipelineUnavailable) && !throttled)
+		if err == nil {
+			return body, nil
+		}
+		lastErr = err
+		if !errors.Is(err, job.ErrPipelineUnavailable) || attempt >= c.retry.MaxAttempts {
+			return nil, err
+		}
+
+		var retryAfter time.Duration
+		if apiErr != nil {
+			retryAfter = apiErr.RetryAfter
+		}
+		delay, ok := c.retry.backoff(attempt, retryAfter)
+		if !ok {
+			return nil, err
+		}
+		c.logger.Warn("DataGen API request failed, retrying",
+			zap.String("endpoint", call.endpoint),
+			zap.Int("attempt", attempt),
+			zap.Duration("delay", delay),
+			zap.Error(err),
+		)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, lastErr
+		}
+	}
+}
+
+// send makes a single authenticated attempt. A 401 discards the cached token and retries
+// once with a fresh one.
+func (c *dataGenPipelineClient) send(ctx context.Context, call apiCall) ([]byte, error) {
+	for refreshed := false; ; refreshed = true {
+		var reader io.Reader
+		if call.body != nil {
+			reader = bytes.NewReader(call.body)
+		}
+		req, err := http.NewRequestWithContext(ctx, call.method, call.endpoint, reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		if call.body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Accept", "application/json")
+		if call.idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", call.idempotencyKey)
+		}
+
+		if c.tokenSource != nil {
+			token, err := c.tokenSource.Token(ctx)
+			if err != nil {
+				if isTransientTokenError(err) {
+					return nil, fmt.Errorf("%w: authentication failed: %w", job.ErrPipelineUnavailable, err)
+				}
+				return nil, fmt.Errorf("authentication failed: %w", err)
+			}
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", job.ErrPipelineUnavailable, err)
+		}
+		bodyBytes, _ := io.ReadAll(resp.Body) // Read body for logging/errors
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == call.wantStatus:
+			return bodyBytes, nil
+		case resp.StatusCode == http.StatusUnauthorized && c.tokenSource != nil && !refreshed:
+			c.logger.Warn("DataGen API rejected access token, refreshing", zap.String("endpoint", call.endpoint))
+			c.tokenSource.Invalidate()
+			continue
+		}
+		return nil, &APIError{
+			StatusCode: resp.StatusCode,
+			Body:       string(bodyBytes),
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+	}
+}
+
 // Submit sends a job configuration to the DataGen pipeline.
 func (c *dataGenPipelineClient) Submit(ctx context.Context, jobConfig string, jobType string, projectID string) (string, error) {
 	c.logger.Info("Submitting job to DataGen Pipeline", zap.String("projectID", projectID), zap.String("jobType", jobType))
@@ -151,44 +298,25 @@ func (c *dataGenPipelineClient) Submit(ctx context.Context, jobConfig string, jo
 		return "", fmt.Errorf("failed to prepare request: %w", err)
 	}
 
-	// 3. Create HTTP request
-	endpoint := fmt.Sprintf("%s/api/v2/jobs", c.baseURL)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(jsonData))
-	if err != nil {
-		c.logger.Error("Failed to create DataGen submit request", zap.Error(err))
-		return "", fmt.Errorf("failed to create request: %w", err)
+	// 3. Execute request, expecting 202 Accepted. The idempotency key makes retries safe:
+	// the pipeline returns the job it already created for a repeated key.
+	idempotencyKey := job.IdempotencyKeyFromContext(ctx)
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.NewString() // Still deduplicates this call's own retries
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	// 4. Add Authentication Header
-	// TODO: Implement token retrieval and add Authorization header
-	// token, err := c.tokenSource.GetToken(ctx)
-	// if err != nil {
-	// 	 c.logger.Error("Failed to get auth token for DataGen API", zap.Error(err))
-	// 	 return "", fmt.Errorf("authentication failed: %w", err)
-	// }
-	// req.Header.Set("Authorization", "Bearer " + token)
-	req.Header.Set("Authorization", "Bearer TODO-REPLACE-ME") // Placeholder
-
-	// 5. Execute Request
-	resp, err := c.httpClient.Do(req)
+	bodyBytes, err := c.do(ctx, apiCall{
+		method:         http.MethodPost,
+		endpoint:       fmt.Sprintf("%s/api/v2/jobs", c.baseURL),
+		body:           jsonData,
+		idempotencyKey: idempotencyKey,
+		wantStatus:     http.StatusAccepted,
+	})
 	if err != nil {
-		c.logger.Error("Failed to execute DataGen submit request", zap.Error(err))
+		c.logger.Error("DataGen submit request failed", zap.Error(err))
 		return "", fmt.Errorf("failed to submit job to pipeline: %w", err)
 	}
-	defer resp.Body.Close()
-
-	// 6. Handle Response
-	bodyBytes, _ := io.ReadAll(resp.Body)       // Read body for logging/errors
-	if resp.StatusCode != http.StatusAccepted { // Expect 202 Accepted
-		c.logger.Error("DataGen API returned non-202 status on submit",
-			zap.Int("status", resp.StatusCode),
-			zap.String("response", string(bodyBytes)),
-		)
-		return "", fmt.Errorf("pipeline API error (%d): %s", resp.StatusCode, string(bodyBytes))
-	}
 
+	// 4. Handle Response
 	var creationResp dataGenJobCreationResponse
 	if err := json.Unmarshal(bodyBytes, &creationResp); err != nil {
 		c.logger.Error("Failed to unmarshal DataGen submit response", zap.Error(err), zap.String("response", string(bodyBytes)))
@@ -207,93 +335,83 @@ func (c *dataGenPipelineClient) Submit(ctx context.Context, jobConfig string, jo
 func (c *dataGenPipelineClient) CheckStatus(ctx context.Context, pipelineJobID string) (core.JobStatus, string, error) {
 	c.logger.Debug("Checking job status with DataGen Pipeline", zap.String("pipelineJobID", pipelineJobID))
 
-	// 1. Create HTTP request
-	endpoint := fmt.Sprintf("%s/api/v2/jobs/%s", c.baseURL, url.PathEscape(pipelineJobID))
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	// 1. Execute request, expecting 200 OK
+	bodyBytes, err := c.do(ctx, apiCall{
+		method:     http.MethodGet,
+		endpoint:   fmt.Sprintf("%s/api/v2/jobs/%s", c.baseURL, url.PathEscape(pipelineJobID)),
+		wantStatus: http.StatusOK,
+	})
 	if err != nil {
-		c.logger.Error("Failed to create DataGen status request", zap.Error(err))
-		return "", "", fmt.Errorf("failed to create status request: %w", err)
-	}
-	req.Header.Set("Accept", "application/json")
-
-	// 2. Add Authentication Header
-	// TODO: Implement token retrieval and add Authorization header
-	req.Header.Set("Authorization", "Bearer TODO-REPLACE-ME") // Placeholder
-
-	// 3. Execute Request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		c.logger.Error("Failed to execute DataGen status request", zap.Error(err))
-		return "", "", fmt.Errorf("failed to check pipeline status: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// 4. Handle Response
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		c.logger.Error("DataGen API returned non-200 status on status check",
-			zap.Int("status", resp.StatusCode),
-			zap.String("pipelineJobID", pipelineJobID),
-			zap.String("response", string(bodyBytes)),
-		)
 		// TODO: Handle 404 Not Found specifically?
-		return "", "", fmt.Errorf("pipeline API error (%d) checking status: %s", resp.StatusCode, string(bodyBytes))
+		c.logger.Error("DataGen status request failed", zap.String("pipelineJobID", pipelineJobID), zap.Error(err))
+		return "", "", fmt.Errorf("failed to check pipeline status: %w", err)
 	}
 
+	// 2. Parse Response
 	var statusResp dataGenJobStatusResponse
 	if err := json.Unmarshal(bodyBytes, &statusResp); err != nil {
 		c.logger.Error("Failed to unmarshal DataGen status response", zap.Error(err), zap.String("response", string(bodyBytes)))
 		return "", "", fmt.Errorf("failed to parse pipeline status response: %w", err)
 	}
 
-	// 5. Map status and error message
+	// 3. Map status and error message
 	coreStatus := mapPipelineStatusToCoreStatus(statusResp.Status)
 	pipelineErrorMsg := ""
 	if statusResp.Error != nil {
 		pipelineErrorMsg = statusResp.Error.Message
 	}
 
+	// 4. Remember progress until the job finishes
+	c.progressMu.Lock()
+	if coreStatus == core.JobStatusPending || coreStatus == core.JobStatusRunning {
+		c.progress[pipelineJobID] = statusResp.Progress
+	} else {
+		delete(c.progress, pipelineJobID)
+	}
+	c.progressMu.Unlock()
+
 	c.logger.Debug("DataGen Pipeline status retrieved", zap.String("pipelineJobID", pipelineJobID), zap.String("pipelineStatus", statusResp.Status), zap.String("coreStatus", string(coreStatus)))
 	return coreStatus, pipelineErrorMsg, nil
 }
 
-// Cancel sends a cancellation request to the DataGen pipeline.
-func (c *dataGenPipelineClient) Cancel(ctx context.Context, pipelineJobID string) error {
-	c.logger.Info("Requesting job cancellation from DataGen Pipeline", zap.String("pipelineJobID", pipelineJobID))
-
-	// 1. Create HTTP request (POST request to /cancel endpoint)
-	endpoint := fmt.Sprintf("%s/api/v2/jobs/%s/cancel", c.baseURL, url.PathEscape(pipelineJobID))
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil) // No body for cancel
+// Progress reports the progress seen by the last status check of a job, checking its
+// status first if it has not been checked yet.
+func (c *dataGenPipelineClient) Progress(ctx context.Context, pipelineJobID string) (int, error) {
+	c.progressMu.Lock()
+	progress, ok := c.progress[pipelineJobID]
+	c.progressMu.Unlock()
+	if ok {
+		return progress, nil
+	}
+	status, _, err := c.CheckStatus(ctx, pipelineJobID)
 	if err != nil {
-		c.logger.Error("Failed to create DataGen cancel request", zap.Error(err))
-		return fmt.Errorf("failed to create cancel request: %w", err)
+		return 0, err
 	}
-	req.Header.Set("Accept", "application/json")
+	if status == core.JobStatusCompleted {
+		return 100, nil
+	}
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	return c.progress[pipelineJobID], nil
+}
 
-	// 2. Add Authentication Header
-	// TODO: Implement token retrieval and add Authorization header
-	req.Header.Set("Authorization", "Bearer TODO-REPLACE-ME") // Placeholder
+// Cancel sends a cancellation request to the DataGen pipeline.
+func (c *dataGenPipelineClient) Cancel(ctx context.Context, pipelineJobID string) error {
+	c.logger.Info("Requesting job cancellation from DataGen Pipeline", zap.String("pipelineJobID", pipelineJobID))
 
-	// 3. Execute Request
-	resp, err := c.httpClient.Do(req)
+	// 1. Execute request (POST to /cancel endpoint, no body), expecting 200 OK
+	bodyBytes, err := c.do(ctx, apiCall{
+		method:     http.MethodPost,
+		endpoint:   fmt.Sprintf("%s/api/v2/jobs/%s/cancel", c.baseURL, url.PathEscape(pipelineJobID)),
+		wantStatus: http.StatusOK,
+	})
 	if err != nil {
-		c.logger.Error("Failed to execute DataGen cancel request", zap.Error(err))
-		return fmt.Errorf("failed to cancel job via pipeline: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// 4. Handle Response
-	bodyBytes, _ := io.ReadAll(resp.Body)
-	if resp.StatusCode != http.StatusOK {
-		c.logger.Error("DataGen API returned non-200 status on cancel request",
-			zap.Int("status", resp.StatusCode),
-			zap.String("pipelineJobID", pipelineJobID),
-			zap.String("response", string(bodyBytes)),
-		)
 		// TODO: Handle 400 Bad Request (e.g., already completed) specifically?
-		return fmt.Errorf("pipeline API error (%d) cancelling job: %s", resp.StatusCode, string(bodyBytes))
+		c.logger.Error("DataGen cancel request failed", zap.String("pipelineJobID", pipelineJobID), zap.Error(err))
+		return fmt.Errorf("failed to cancel job via pipeline: %w", err)
 	}
 
+	// 2. Handle Response
 	// Optional: Check response body for success field if needed
 	var actionResp dataGenJobActionResponse
 	if err := json.Unmarshal(bodyBytes, &actionResp); err == nil {
@@ -308,6 +426,42 @@ func (c *dataGenPipelineClient) Cancel(ctx context.Context, pipelineJobID string
 	return nil
 }
 
+// ResultURI reports no output location: the DataGen status response does not include one.
+// TODO: Return the request's output path once Submit maps it from the job config.
+func (c *dataGenPipelineClient) ResultURI(ctx context.Context, pipelineJobID string) (string, error) {
+	return "", nil
+}
+
+// GetLogs reads a page of a job's log from the DataGen pipeline.
+func (c *dataGenPipelineClient) GetLogs(ctx context.Context, pipelineJobID string, since int64, limit int) ([]job.LogEntry, error) {
+	// 1. Execute request, expecting 200 OK
+	query := url.Values{"since": {strconv.FormatInt(since, 10)}}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	bodyBytes, err := c.do(ctx, apiCall{
+		method:     http.MethodGet,
+		endpoint:   fmt.Sprintf("%s/api/v2/jobs/%s/logs?%s", c.baseURL, url.PathEscape(pipelineJobID), query.Encode()),
+		wantStatus: http.StatusOK,
+	})
+	if err != nil {
+		c.logger.Error("DataGen logs request failed", zap.String("pipelineJobID", pipelineJobID), zap.Error(err))
+		return nil, fmt.Errorf("failed to get pipeline logs: %w", err)
+	}
+
+	// 2. Parse Response
+	var logsResp dataGenJobLogsResponse
+	if err := json.Unmarshal(bodyBytes, &logsResp); err != nil {
+		c.logger.Error("Failed to unmarshal DataGen logs response", zap.Error(err), zap.String("response", string(bodyBytes)))
+		return nil, fmt.Errorf("failed to parse pipeline logs response: %w", err)
+	}
+	entries := make([]job.LogEntry, len(logsResp.Logs))
+	for i, entry := range logsResp.Logs {
+		entries[i] = job.LogEntry(entry)
+	}
+	return entries, nil
+}
+
 // Helper function to map pipeline status strings to internal core.JobStatus enum
 func mapPipelineStatusToCoreStatus(pipelineStatus string) core.JobStatus {
 	switch pipelineStatus {
@@ -0,0 +1,473 @@
+package pipeline
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Local engine tuning.
+const (
+	localChunkRows          = 5000           // Rows generated per work item
+	defaultLocalConcurrency = 2              // Jobs generated at the same time
+	localJobRetention       = 24 * time.Hour // How long finished jobs stay queryable
+	localOutputFolder       = "jobs"         // Default output folder, as jobs/<pipelineJobID>/
+)
+
+// errUploadStopped stops a table's generator when its upload returns early.
+var errUploadStopped = errors.New("upload stopped reading")
+
+// LocalConfig holds configuration for the in-process generation engine.
+type LocalConfig struct {
+	Storage  core.StorageService    // Output is written to the project's bucket
+	Projects core.ProjectRepository // Resolves a project's bucket
+	// Workers generating rows of a job; defaults to the number of CPUs.
+	Workers int
+	// MaxConcurrentJobs bounds the jobs generated at once; later jobs stay pending. Defaults to 2.
+	MaxConcurrentJobs int
+	// MaxRecords caps the rows of a job across its tables. Defaults to DefaultLocalMaxRecords.
+	MaxRecords int64
+	Logger     *zap.Logger
+}
+
+// localJob is the in-memory state of a locally generated job.
+type localJob struct {
+	status      core.JobStatus
+	errMsg      string
+	resultURI   string
+	cancel      context.CancelFunc
+	finishedAt  time.Time
+	logs        []job.LogEntry
+	tables      int   // Tables in the job
+	tablesDone  int   // Tables written so far
+	outputBytes int64 // Bytes written by the finished tables
+	records     int64 // Rows written by the finished tables
+}
+
+// localPipelineClient implements the job.PipelineClient interface by generating data
+// in-process from the job config's typed schema. Job state lives in memory, so jobs
+// in flight when the server stops, or submitted to another replica, are unknown here
+// and reported as failed.
+type localPipelineClient struct {
+	storage  core.StorageService
+	projects core.ProjectRepository
+	workers  int
+	maxRows  int64
+	slots    chan struct{}
+	logger   *zap.Logger
+
+	mu   sync.Mutex
+	jobs map[string]*localJob
+}
+
+// Ensure localPipelineClient satisfies the progress, output size and record count interfaces.
+var (
+	_ job.ProgressReporter    = (*localPipelineClient)(nil)
+	_ job.OutputSizeReporter  = (*localPipelineClient)(nil)
+	_ job.RecordCountReporter = (*localPipelineClient)(nil)
+)
+
+// NewLocalPipelineClient creates a pipeline client that generates data in-process.
+func NewLocalPipelineClient(cfg LocalConfig) (job.PipelineClient, error) {
+	if cfg.Storage == nil || cfg.Projects == nil {
+		return nil, fmt.Errorf("local pipeline requires a storage service and a project repository")
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = runtime.NumCPU()
+	}
+	if cfg.MaxConcurrentJobs <= 0 {
+		cfg.MaxConcurrentJobs = defaultLocalConcurrency
+	}
+	if cfg.MaxRecords <= 0 {
+		cfg.MaxRecords = DefaultLocalMaxRecords
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = zap.L() // Use global logger if none provided
+	}
+	return &localPipelineClient{
+		storage:  cfg.Storage,
+		projects: cfg.Projects,
+		workers:  cfg.Workers,
+		maxRows:  cfg.MaxRecords,
+		slots:    make(chan struct{}, cfg.MaxConcurrentJobs),
+		logger:   cfg.Logger.Named("LocalPipelineClient"),
+		jobs:     make(map[string]*localJob),
+	}, nil
+}
+
+// Submit validates the job config and starts generating it in the background.
+func (c *localPipelineClient) Submit(ctx context.Context, jobConfig string, jobType string, projectID string) (string, error) {
+	// 1. Validate and compile the schema before accepting the job
+	plan, err := planGeneration(jobConfig, jobType, c.maxRows)
+	if err != nil {
+		return "", err
+	}
+
+	// 2. Resolve the output location in the project's bucket
+	project, err := c.projects.GetProjectByID(ctx, projectID)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up project %s: %w", projectID, err)
+	}
+	bucketName := project.Storage.BucketName
+	if bucketName == "" {
+		return "", fmt.Errorf("project %s has no storage bucket", projectID)
+	}
+	pipelineJobID := uuid.NewString()
+	folder := path.Join(localOutputFolder, pipelineJobID)
+	if plan.prefix != "" {
+		object, ok := strings.CutPrefix(plan.prefix, "gs://"+bucketName+"/")
+		if !ok || strings.Trim(object, "/") == "" {
+			return "", fmt.Errorf("%w: destinationUri must be a folder in gs://%s/", ErrInvalidGenerationSpec, bucketName)
+		}
+		folder = strings.Trim(object, "/")
+	}
+
+	// 3. Register the job and generate it detached from the request
+	runCtx, cancel := context.WithCancel(context.Background())
+	c.mu.Lock()
+	c.pruneLocked(time.Now())
+	c.jobs[pipelineJobID] = &localJob{status: core.JobStatusPending, cancel: cancel, tables: len(plan.tables)}
+	c.mu.Unlock()
+	c.logJob(pipelineJobID, job.LogLevelInfo, "", fmt.Sprintf("Job accepted: %d tables as %s to gs://%s/%s/ (seed %d)", len(plan.tables), plan.format, bucketName, folder, plan.seed))
+
+	c.logger.Info("Accepted local generation job",
+		zap.String("pipelineJobID", pipelineJobID),
+		zap.String("projectID", projectID),
+		zap.String("format", plan.format),
+		zap.Uint64("seed", plan.seed),
+		zap.Int("tables", len(plan.tables)),
+	)
+	go c.run(runCtx, pipelineJobID, plan, bucketName, folder)
+	return pipelineJobID, nil
+}
+
+// CheckStatus reports the state of a local job. Jobs this process does not know, because
+// it restarted or another replica accepted them, are reported as failed: nothing here will
+// ever finish them, and an error would leave them running forever.
+func (c *localPipelineClient) CheckStatus(ctx context.Context, pipelineJobID string) (core.JobStatus, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j, ok := c.jobs[pipelineJobID]
+	if !ok {
+		return core.JobStatusFailed, fmt.Sprintf("local pipeline: job %s is not running in this process; it was lost in a restart or submitted to another replica", pipelineJobID), nil
+	}
+	return j.status, j.errMsg, nil
+}
+
+// Progress reports the share of a local job's tables written so far.
+func (c *localPipelineClient) Progress(ctx context.Context, pipelineJobID string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j, ok := c.jobs[pipelineJobID]
+	if !ok {
+		return 0, fmt.Errorf("local pipeline: job %s not found", pipelineJobID)
+	}
+	if j.status == core.JobStatusCompleted || j.tables == 0 {
+		return 100, nil
+	}
+	return j.tablesDone * 100 / j.tables, nil
+}
+
+// OutputBytes reports the bytes a local job has written so far.
+func (c *localPipelineClient) OutputBytes(ctx context.Context, pipelineJobID string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j, ok := c.jobs[pipelineJobID]
+	if !ok {
+		return 0, fmt.Errorf("local pipeline: job %s not found", pipelineJobID)
+	}
+	return j.outputBytes, nil
+}
+
+// GeneratedRecords reports the rows a local job has written so far.
+func (c *localPipelineClient) GeneratedRecords(ctx context.Context, pipelineJobID string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j, ok := c.jobs[pipelineJobID]
+	if !ok {
+		return 0, fmt.Errorf("local pipeline: job %s not found", pipelineJobID)
+	}
+	return j.records, nil
+}
+
+// Cancel stops a pending or running local job. Partially written output is discarded.
+func (c *localPipelineClient) Cancel(ctx context.Context, pipelineJobID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j, ok := c.jobs[pipelineJobID]
+	if !ok {
+		return fmt.Errorf("local pipeline: job %s not found", pipelineJobID)
+	}
+	if j.status != core.JobStatusPending && j.status != core.JobStatusRunning {
+		return fmt.Errorf("local pipeline: job %s cannot be cancelled in status %s", pipelineJobID, j.status)
+	}
+	j.cancel()
+	j.status = core.JobStatusCancelled
+	j.finishedAt = time.Now()
+	j.logs = appendLogEntry(j.logs, j.finishedAt, job.LogLevelWarn, "", "Job cancelled; partial output discarded")
+	c.logger.Info("Cancelled local generation job", zap.String("pipelineJobID", pipelineJobID))
+	return nil
+}
+
+// ResultURI returns the first generated table of a completed job; other tables are
+// written next to it.
+func (c *localPipelineClient) ResultURI(ctx context.Context, pipelineJobID string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j, ok := c.jobs[pipelineJobID]
+	if !ok {
+		return "", fmt.Errorf("local pipeline: job %s not found", pipelineJobID)
+	}
+	return j.resultURI, nil
+}
+
+// GetLogs returns the log a local job has written so far.
+func (c *localPipelineClient) GetLogs(ctx context.Context, pipelineJobID string, since int64, limit int) ([]job.LogEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j, ok := c.jobs[pipelineJobID]
+	if !ok {
+		return nil, fmt.Errorf("local pipeline: job %s not found", pipelineJobID)
+	}
+	return pageLogEntries(j.logs, since, limit), nil
+}
+
+// logJob appends a line to a job's log unless the job was cancelled, which ends its log.
+func (c *localPipelineClient) logJob(pipelineJobID, level, stage, message string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j := c.jobs[pipelineJobID]
+	if j == nil || j.status == core.JobStatusCancelled {
+		return
+	}
+	j.logs = appendLogEntry(j.logs, time.Now(), level, stage, message)
+}
+
+// pruneLocked forgets jobs that finished more than localJobRetention ago.
+func (c *localPipelineClient) pruneLocked(now time.Time) {
+	for id, j := range c.jobs {
+		if !j.finishedAt.IsZero() && now.Sub(j.finishedAt) > localJobRetention {
+			delete(c.jobs, id)
+		}
+	}
+}
+
+// setStatus moves a job to a new status unless it was cancelled in the meantime.
+func (c *localPipelineClient) setStatus(pipelineJobID string, status core.JobStatus, errMsg, resultURI string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	j := c.jobs[pipelineJobID]
+	if j == nil || j.status == core.JobStatusCancelled {
+		return
+	}
+	j.status, j.errMsg, j.resultURI = status, errMsg, resultURI
+	if status != core.JobStatusRunning {
+		j.finishedAt = time.Now()
+		j.cancel() // Release the context's resources
+	}
+}
+
+// run generates every table of a job once a concurrency slot frees up.
+func (c *localPipelineClient) run(ctx context.Context, pipelineJobID string, plan *generationPlan, bucketName, folder string) {
+	select {
+	case c.slots <- struct{}{}:
+		defer func() { <-c.slots }()
+	case <-ctx.Done():
+		return // Cancelled while pending
+	}
+	c.setStatus(pipelineJobID, core.JobStatusRunning, "", "")
+	c.logJob(pipelineJobID, job.LogLevelInfo, "", "Generation started")
+	started := time.Now()
+
+	refs := make(map[string][]interface{})
+	var resultURI string
+	for _, table := range plan.tables {
+		object := path.Join(folder, table.name+"."+plan.format)
+		c.logJob(pipelineJobID, job.LogLevelInfo, table.name, fmt.Sprintf("Generating %d rows", table.rows))
+		uri, written, err := c.writeTable(ctx, plan, table, refs, bucketName, object)
+		if err != nil {
+			if ctx.Err() != nil {
+				return // Cancelled; the status is already set
+			}
+			c.logger.Error("Local generation job failed",
+				zap.String("pipelineJobID", pipelineJobID),
+				zap.String("table", table.name),
+				zap.Error(err),
+			)
+			c.logJob(pipelineJobID, job.LogLevelError, table.name, fmt.Sprintf("Generation failed: %v", err))
+			c.setStatus(pipelineJobID, core.JobStatusFailed, fmt.Sprintf("generating table %s: %v", table.name, err), "")
+			return
+		}
+		c.logJob(pipelineJobID, job.LogLevelInfo, table.name, "Wrote "+uri)
+		c.mu.Lock()
+		if j := c.jobs[pipelineJobID]; j != nil {
+			j.tablesDone++
+			j.outputBytes += written
+			j.records += table.rows
+		}
+		c.mu.Unlock()
+		if resultURI == "" {
+			resultURI = uri
+		}
+	}
+
+	c.logger.Info("Local generation job completed",
+		zap.String("pipelineJobID", pipelineJobID),
+		zap.String("resultURI", resultURI),
+		zap.Duration("duration", time.Since(started)),
+	)
+	c.logJob(pipelineJobID, job.LogLevelInfo, "", fmt.Sprintf("Job completed in %s", time.Since(started).Round(time.Millisecond)))
+	c.setStatus(pipelineJobID, core.JobStatusCompleted, "", resultURI)
+}
+
+// writeTable streams a generated table into storage and records the values of its
+// referenced columns in refs. It returns the object's URI and size.
+func (c *localPipelineClient) writeTable(ctx context.Context, plan *generationPlan, table *tablePlan, refs map[string][]interface{}, bucketName, object string) (string, int64, error) {
+	pr, pw := io.Pipe()
+	out := &countingWriter{w: pw}
+	generated := make(chan error, 1)
+	go func() {
+		err := generateTable(ctx, plan, table, refs, out, c.workers)
+		pw.CloseWithError(err)
+		generated <- err
+	}()
+
+	uri, uploadErr := c.storage.UploadFile(ctx, bucketName, object, pr)
+	pr.CloseWithError(errUploadStopped) // Unblock the generator if the upload gave up
+	if err := <-generated; err != nil && !errors.Is(err, errUploadStopped) {
+		return "", 0, err
+	}
+	if uploadErr != nil {
+		return "", 0, fmt.Errorf("failed to upload %s: %w", object, uploadErr)
+	}
+	return uri, out.n, nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// generateTable writes a table's rows to w. Chunks of rows are generated by a pool of
+// workers and written in order; each chunk draws from its own random source seeded by
+// the job seed, the table and the chunk index, so output does not depend on the number
+// of workers.
+func generateTable(ctx context.Context, plan *generationPlan, table *tablePlan, refs map[string][]interface{}, w io.Writer, workers int) error {
+	// 1. Bind foreign keys to the values of their (already generated) parent columns
+	gens := make([]valueFunc, len(table.columns))
+	for i, column := range table.columns {
+		gens[i] = column.gen
+		if column.ref != "" {
+			values := refs[column.ref]
+			if len(values) == 0 {
+				return fmt.Errorf("column %s references %s, which has no values", column.name, column.ref)
+			}
+			gens[i] = foreignKeyGenerator(values)
+		}
+	}
+
+	out, err := newTableWriter(plan.format, w, table.columns)
+	if err != nil {
+		return err
+	}
+
+	// 2. Generate chunks in a worker pool, at most 2*workers ahead of the writer
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	chunks := int((table.rows + localChunkRows - 1) / localChunkRows)
+	results := make([]chan [][]interface{}, chunks)
+	for i := range results {
+		results[i] = make(chan [][]interface{}, 1)
+	}
+	ahead := make(chan struct{}, 2*workers)
+	next := make(chan int)
+	go func() {
+		defer close(next)
+		for i := 0; i < chunks; i++ {
+			select {
+			case ahead <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case next <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	for n := 0; n < workers; n++ {
+		go func() {
+			for i := range next {
+				results[i] <- generateChunk(plan.seed, table, gens, i)
+			}
+		}()
+	}
+
+	// 3. Write chunks in order, keeping referenced values for child tables
+	kept := make([][]interface{}, len(table.keep))
+	for i := 0; i < chunks; i++ {
+		var rows [][]interface{}
+		select {
+		case rows = <-results[i]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if err := out.WriteRows(rows); err != nil {
+			return err
+		}
+		for k, idx := range table.keep {
+			for _, row := range rows {
+				if row[idx] != nil {
+					kept[k] = append(kept[k], row[idx])
+				}
+			}
+		}
+		<-ahead
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	for k, idx := range table.keep {
+		refs[table.name+"."+table.columns[idx].name] = kept[k]
+	}
+	return nil
+}
+
+// generateChunk generates the rows of chunk i of a table.
+func generateChunk(seed uint64, table *tablePlan, gens []valueFunc, i int) [][]interface{} {
+	rng := rand.New(rand.NewPCG(seed, uint64(table.index)<<32|uint64(i)))
+	first := int64(i) * localChunkRows
+	last := min(first+localChunkRows, table.rows)
+	rows := make([][]interface{}, 0, last-first)
+	for r := first; r < last; r++ {
+		row := make([]interface{}, len(table.columns))
+		for c, column := range table.columns {
+			if column.nullRate > 0 && rng.Float64() < column.nullRate {
+				continue
+			}
+			row[c] = gens[c](rng, r)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,202 @@
+package pipeline
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockStorageService mocks the storage calls the local engine makes.
+type MockStorageService struct {
+	mock.Mock
+	core.StorageService
+
+	mu      sync.Mutex
+	uploads map[string][]byte
+}
+
+func (m *MockStorageService) UploadFile(ctx context.Context, bucketName, objectName string, reader io.Reader) (string, error) {
+	args := m.Called(ctx, bucketName, objectName) // The reader is a pipe the generator writes to
+	if err := args.Error(1); err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return "", err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.uploads == nil {
+		m.uploads = make(map[string][]byte)
+	}
+	m.uploads[objectName] = data
+	return args.String(0), nil
+}
+
+// MockProjectRepository mocks project lookups.
+type MockProjectRepository struct {
+	mock.Mock
+	core.ProjectRepository
+}
+
+func (m *MockProjectRepository) GetProjectByID(ctx context.Context, projectID string) (*core.Project, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+func setupLocalClientTest(t *testing.T) (*localPipelineClient, *MockStorageService, *MockProjectRepository) {
+	mockStorage := new(MockStorageService)
+	mockProjects := new(MockProjectRepository)
+	client, err := NewLocalPipelineClient(LocalConfig{Storage: mockStorage, Projects: mockProjects, Workers: 2})
+	require.NoError(t, err)
+	return client.(*localPipelineClient), mockStorage, mockProjects
+}
+
+// waitForStatus polls a local job until it leaves the pending and running states.
+func waitForStatus(t *testing.T, client *localPipelineClient, pipelineJobID string) (core.JobStatus, string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		status, errMsg, err := client.CheckStatus(context.Background(), pipelineJobID)
+		require.NoError(t, err)
+		if status != core.JobStatusPending && status != core.JobStatusRunning {
+			return status, errMsg
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", pipelineJobID)
+	return "", ""
+}
+
+func TestLocalPipelineClient(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-local"
+	bucketName := "bucket-local"
+	project := &core.Project{ID: projectID, Storage: core.ProjectStorage{BucketName: bucketName}}
+	config := `{"schema":[{"name":"id","type":"sequence"},{"name":"name","type":"text"}],"parameters":{"recordCount":20,"seed":1}}`
+
+	t.Run("Success_GeneratesIntoProjectBucket", func(t *testing.T) {
+		client, mockStorage, mockProjects := setupLocalClientTest(t)
+		mockProjects.On("GetProjectByID", ctx, projectID).Return(project, nil).Once()
+		mockStorage.On("UploadFile", mock.Anything, bucketName, mock.AnythingOfType("string")).Return("gs://"+bucketName+"/out", nil).Once()
+
+		pipelineJobID, err := client.Submit(ctx, config, "csv", projectID)
+		require.NoError(t, err)
+		status, errMsg := waitForStatus(t, client, pipelineJobID)
+
+		assert.Equal(t, core.JobStatusCompleted, status)
+		assert.Empty(t, errMsg)
+		object := "jobs/" + pipelineJobID + "/output.csv"
+		mockStorage.AssertCalled(t, "UploadFile", mock.Anything, bucketName, object)
+		assert.Contains(t, string(mockStorage.uploads[object]), "id,name\n1,")
+		resultURI, err := client.ResultURI(ctx, pipelineJobID)
+		require.NoError(t, err)
+		assert.Equal(t, "gs://"+bucketName+"/out", resultURI)
+		logs, err := client.GetLogs(ctx, pipelineJobID, 0, 0)
+		require.NoError(t, err)
+		require.Len(t, logs, 5)
+		assert.Equal(t, "Generation started", logs[1].Message)
+		assert.Equal(t, "output", logs[2].Stage)
+		assert.Equal(t, "Generating 20 rows", logs[2].Message)
+		assert.Contains(t, logs[4].Message, "Job completed")
+		progress, err := client.Progress(ctx, pipelineJobID)
+		require.NoError(t, err)
+		assert.Equal(t, 100, progress)
+		outputBytes, err := client.OutputBytes(ctx, pipelineJobID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(mockStorage.uploads[object])), outputBytes)
+		records, err := client.GeneratedRecords(ctx, pipelineJobID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(20), records)
+	})
+
+	t.Run("Success_DestinationFolder", func(t *testing.T) {
+		client, mockStorage, mockProjects := setupLocalClientTest(t)
+		mockProjects.On("GetProjectByID", ctx, projectID).Return(project, nil).Once()
+		mockStorage.On("UploadFile", mock.Anything, bucketName, "exports/nightly/output.json").Return("gs://"+bucketName+"/exports/nightly/output.json", nil).Once()
+		withDestination := `{"schema":[{"name":"id","type":"uuid"}],"parameters":{"recordCount":5,"format":"json"},"outputConfig":{"destinationUri":"gs://` + bucketName + `/exports/nightly/"}}`
+
+		pipelineJobID, err := client.Submit(ctx, withDestination, "", projectID)
+		require.NoError(t, err)
+		status, _ := waitForStatus(t, client, pipelineJobID)
+
+		assert.Equal(t, core.JobStatusCompleted, status)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Failure_UploadErrorFailsJob", func(t *testing.T) {
+		client, mockStorage, mockProjects := setupLocalClientTest(t)
+		mockProjects.On("GetProjectByID", ctx, projectID).Return(project, nil).Once()
+		mockStorage.On("UploadFile", mock.Anything, bucketName, mock.Anything).Return("", errors.New("bucket unavailable")).Once()
+
+		pipelineJobID, err := client.Submit(ctx, config, "csv", projectID)
+		require.NoError(t, err)
+		status, errMsg := waitForStatus(t, client, pipelineJobID)
+
+		assert.Equal(t, core.JobStatusFailed, status)
+		assert.Contains(t, errMsg, "bucket unavailable")
+		logs, _ := client.GetLogs(ctx, pipelineJobID, 0, 0)
+		assert.Equal(t, job.LogLevelError, logs[len(logs)-1].Level)
+		resultURI, _ := client.ResultURI(ctx, pipelineJobID)
+		assert.Empty(t, resultURI)
+	})
+
+	t.Run("Failure_InvalidConfigRejectedAtSubmit", func(t *testing.T) {
+		client, _, mockProjects := setupLocalClientTest(t)
+
+		_, err := client.Submit(ctx, `{"parameters":{"recordCount":5}}`, "csv", projectID)
+
+		assert.ErrorIs(t, err, ErrInvalidGenerationSpec)
+		mockProjects.AssertNotCalled(t, "GetProjectByID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_DestinationOutsideBucket", func(t *testing.T) {
+		client, _, mockProjects := setupLocalClientTest(t)
+		mockProjects.On("GetProjectByID", ctx, projectID).Return(project, nil).Once()
+
+		_, err := client.Submit(ctx, `{"schema":[{"name":"id","type":"uuid"}],"parameters":{"recordCount":5},"outputConfig":{"destinationUri":"gs://other-bucket/x/"}}`, "csv", projectID)
+
+		assert.ErrorIs(t, err, ErrInvalidGenerationSpec)
+	})
+
+	t.Run("Cancel_PendingJob", func(t *testing.T) {
+		client, _, mockProjects := setupLocalClientTest(t)
+		mockProjects.On("GetProjectByID", ctx, projectID).Return(project, nil).Once()
+		// Occupy every slot so the job stays pending
+		for i := 0; i < cap(client.slots); i++ {
+			client.slots <- struct{}{}
+		}
+
+		pipelineJobID, err := client.Submit(ctx, config, "csv", projectID)
+		require.NoError(t, err)
+		require.NoError(t, client.Cancel(ctx, pipelineJobID))
+
+		status, _, err := client.CheckStatus(ctx, pipelineJobID)
+		require.NoError(t, err)
+		assert.Equal(t, core.JobStatusCancelled, status)
+		assert.Error(t, client.Cancel(ctx, pipelineJobID), "cancelled jobs cannot be cancelled again")
+	})
+
+	t.Run("Success_UnknownJobFails", func(t *testing.T) {
+		client, _, _ := setupLocalClientTest(t)
+
+		status, pipelineError, err := client.CheckStatus(ctx, "missing")
+
+		require.NoError(t, err)
+		assert.Equal(t, core.JobStatusFailed, status, "jobs lost in a restart must not stay running")
+		assert.Contains(t, pipelineError, "not running in this process")
+		assert.ErrorContains(t, client.Cancel(ctx, "missing"), "not found")
+	})
+}
@@ -0,0 +1,678 @@
+package pipeline
+
+import (
+	"SynDataGen/backend/internal/project"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidGenerationSpec is returned when a job config cannot be generated locally.
+var ErrInvalidGenerationSpec = errors.New("invalid generation spec")
+
+// Local generation defaults.
+const (
+	DefaultLocalMaxRecords = 1_000_000 // Rows a local job may generate across all tables
+	defaultNullRate        = 0.1       // Share of nulls in nullable columns without a nullRate
+	defaultTableName       = "output"  // Table name of configs with a top-level schema
+	maxPatternRepeat       = 8         // Extra repetitions generated for *, + and open-ended {n,}
+)
+
+// Default date range, fixed so that seeded jobs stay reproducible.
+var (
+	defaultRangeFrom = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	defaultRangeTo   = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Column types the local engine generates beyond the inferred column types.
+const columnTypeSequence project.ColumnType = "sequence"
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// --- Job config ---
+
+// generationConfig is the part of a job config the local engine reads. A config either
+// has a top-level "schema" (as filled in from schemaFromDataset) or several "tables".
+type generationConfig struct {
+	Schema     []fieldSpec `json:"schema"`
+	Tables     []tableSpec `json:"tables"`
+	Parameters struct {
+		RecordCount int64   `json:"recordCount"`
+		Format      string  `json:"format"`
+		Seed        *uint64 `json:"seed"`
+	} `json:"parameters"`
+	OutputConfig struct {
+		DestinationURI string `json:"destinationUri"`
+	} `json:"outputConfig"`
+}
+
+// tableSpec is one generated table of a multi-table job.
+type tableSpec struct {
+	Name        string      `json:"name"`
+	RecordCount int64       `json:"recordCount"` // Defaults to parameters.recordCount
+	Schema      []fieldSpec `json:"schema"`
+}
+
+// fieldSpec is a job config schema field: the inferred JobSchemaField shape plus
+// generator options. Options that do not apply to the field's type are ignored.
+type fieldSpec struct {
+	Name     string             `json:"name"`
+	Type     project.ColumnType `json:"type"`
+	Nullable bool               `json:"nullable,omitempty"`
+	NullRate *float64           `json:"nullRate,omitempty"` // Defaults to 0.1 for nullable fields
+	Format   string             `json:"format,omitempty"`   // Go time layout for date and datetime fields
+	Values   []string           `json:"values,omitempty"`   // Categories
+	Weights  []float64          `json:"weights,omitempty"`  // Relative category weights, parallel to Values
+
+	Distribution string   `json:"distribution,omitempty"` // uniform (default), normal, lognormal or exponential
+	Min          *float64 `json:"min,omitempty"`
+	Max          *float64 `json:"max,omitempty"`
+	Mean         *float64 `json:"mean,omitempty"`
+	StdDev       *float64 `json:"stddev,omitempty"`
+
+	Start *int64 `json:"start,omitempty"` // Sequence start, default 1
+	Step  *int64 `json:"step,omitempty"`  // Sequence step, default 1
+
+	Pattern    string `json:"pattern,omitempty"`    // Regular expression for text and email fields
+	From       string `json:"from,omitempty"`       // Date range start, in Format
+	To         string `json:"to,omitempty"`         // Date range end (exclusive), in Format
+	References string `json:"references,omitempty"` // "table.column" of a foreign key
+}
+
+// --- Plan ---
+
+// valueKind is the physical kind of a generated column.
+type valueKind int
+
+const (
+	kindString valueKind = iota
+	kindInt
+	kindFloat
+	kindBool
+	kindDate
+	kindDateTime
+)
+
+// valueFunc produces the value of a column for a row. Values are int64, float64, bool,
+// string, time.Time or nil.
+type valueFunc func(rng *rand.Rand, row int64) interface{}
+
+// columnPlan is a validated, ready-to-run field.
+type columnPlan struct {
+	name     string
+	kind     valueKind
+	layout   string // Text layout of date and datetime values
+	nullRate float64
+	ref      string // "table.column" of a foreign key
+	gen      valueFunc
+}
+
+// tablePlan is a validated table, in generation order.
+type tablePlan struct {
+	index   int
+	name    string
+	rows    int64
+	columns []*columnPlan
+	keep    []int // Columns referenced by later tables, whose values are retained
+}
+
+// generationPlan is a job config checked and compiled for the local engine.
+type generationPlan struct {
+	seed   uint64
+	format string
+	tables []*tablePlan // Parents before the tables referencing them
+	prefix string       // Output folder from outputConfig.destinationUri, if set
+}
+
+// planGeneration validates a job config and compiles its fields. Tables are ordered so
+// that every foreign key refers to a table generated earlier.
+func planGeneration(jobConfig, jobType string, maxRecords int64) (*generationPlan, error) {
+	var cfg generationConfig
+	if err := json.Unmarshal([]byte(jobConfig), &cfg); err != nil {
+		return nil, fmt.Errorf("%w: job config is not a JSON object: %v", ErrInvalidGenerationSpec, err)
+	}
+
+	tables := cfg.Tables
+	if len(cfg.Schema) > 0 {
+		if len(tables) > 0 {
+			return nil, fmt.Errorf("%w: set either schema or tables, not both", ErrInvalidGenerationSpec)
+		}
+		tables = []tableSpec{{Name: defaultTableName, Schema: cfg.Schema}}
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("%w: job config has no schema", ErrInvalidGenerationSpec)
+	}
+
+	plan := &generationPlan{format: outputFormat(cfg.Parameters.Format, jobType)}
+	if plan.format == "" {
+		return nil, fmt.Errorf("%w: unsupported output format %q", ErrInvalidGenerationSpec, cfg.Parameters.Format)
+	}
+	if cfg.Parameters.Seed != nil {
+		plan.seed = *cfg.Parameters.Seed
+	} else {
+		plan.seed = rand.Uint64()
+	}
+	plan.prefix = cfg.OutputConfig.DestinationURI
+
+	ordered, err := orderTables(tables)
+	if err != nil {
+		return nil, err
+	}
+	var total int64
+	planned := make(map[string]*tablePlan, len(ordered))
+	for i, spec := range ordered {
+		rows := spec.RecordCount
+		if rows == 0 {
+			rows = cfg.Parameters.RecordCount
+		}
+		if rows <= 0 {
+			return nil, fmt.Errorf("%w: table %q needs a positive recordCount", ErrInvalidGenerationSpec, spec.Name)
+		}
+		total += rows
+		if total > maxRecords {
+			return nil, fmt.Errorf("%w: local generation is limited to %d records", ErrInvalidGenerationSpec, maxRecords)
+		}
+
+		table := &tablePlan{index: i, name: spec.Name, rows: rows}
+		seen := make(map[string]bool, len(spec.Schema))
+		for _, field := range spec.Schema {
+			if field.Name == "" {
+				return nil, fmt.Errorf("%w: table %q has a field without a name", ErrInvalidGenerationSpec, spec.Name)
+			}
+			if seen[field.Name] {
+				return nil, fmt.Errorf("%w: table %q has duplicate field %q", ErrInvalidGenerationSpec, spec.Name, field.Name)
+			}
+			seen[field.Name] = true
+			column, err := planColumn(field, planned)
+			if err != nil {
+				return nil, fmt.Errorf("%w: field %s.%s: %v", ErrInvalidGenerationSpec, spec.Name, field.Name, err)
+			}
+			table.columns = append(table.columns, column)
+		}
+		if len(table.columns) == 0 {
+			return nil, fmt.Errorf("%w: table %q has no fields", ErrInvalidGenerationSpec, spec.Name)
+		}
+		planned[spec.Name] = table
+		plan.tables = append(plan.tables, table)
+	}
+
+	// Retain the values of referenced columns so child tables can sample them
+	for _, table := range plan.tables {
+		for _, column := range table.columns {
+			if column.ref == "" {
+				continue
+			}
+			parent, idx := resolveReference(column.ref, planned)
+			if !containsInt(parent.keep, idx) {
+				parent.keep = append(parent.keep, idx)
+			}
+		}
+	}
+	return plan, nil
+}
+
+// outputFormat picks the output format from the job parameters, falling back to the job
+// type and then CSV. It returns "" for formats the local engine cannot write.
+func outputFormat(format, jobType string) string {
+	if format == "" {
+		switch strings.ToLower(jobType) {
+		case "csv", "json", "parquet":
+			format = jobType
+		default:
+			format = "csv"
+		}
+	}
+	switch format = strings.ToLower(format); format {
+	case "csv", "json", "parquet":
+		return format
+	}
+	return ""
+}
+
+// orderTables validates table names and sorts tables so that referenced tables come first.
+// Tables without dependencies keep their config order.
+func orderTables(tables []tableSpec) ([]tableSpec, error) {
+	byName := make(map[string]int, len(tables))
+	for i, table := range tables {
+		if !tableNamePattern.MatchString(table.Name) {
+			return nil, fmt.Errorf("%w: table name %q must be letters, digits, '-' or '_'", ErrInvalidGenerationSpec, table.Name)
+		}
+		if _, dup := byName[table.Name]; dup {
+			return nil, fmt.Errorf("%w: duplicate table %q", ErrInvalidGenerationSpec, table.Name)
+		}
+		byName[table.Name] = i
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(tables))
+	ordered := make([]tableSpec, 0, len(tables))
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("%w: foreign keys between tables form a cycle at %q", ErrInvalidGenerationSpec, tables[i].Name)
+		case done:
+			return nil
+		}
+		state[i] = visiting
+		for _, field := range tables[i].Schema {
+			if field.References == "" {
+				continue
+			}
+			parent, _, ok := strings.Cut(field.References, ".")
+			j, exists := byName[parent]
+			if !ok || !exists {
+				return fmt.Errorf("%w: field %s.%s references unknown column %q", ErrInvalidGenerationSpec, tables[i].Name, field.Name, field.References)
+			}
+			if j == i {
+				return fmt.Errorf("%w: field %s.%s references its own table", ErrInvalidGenerationSpec, tables[i].Name, field.Name)
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = done
+		ordered = append(ordered, tables[i])
+		return nil
+	}
+	for i := range tables {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
+
+// resolveReference finds the table and column index of a "table.column" reference.
+func resolveReference(ref string, planned map[string]*tablePlan) (*tablePlan, int) {
+	tableName, columnName, _ := strings.Cut(ref, ".")
+	table := planned[tableName]
+	if table == nil {
+		return nil, -1
+	}
+	for i, column := range table.columns {
+		if column.name == columnName {
+			return table, i
+		}
+	}
+	return table, -1
+}
+
+// planColumn compiles a field into its generator.
+func planColumn(field fieldSpec, planned map[string]*tablePlan) (*columnPlan, error) {
+	column := &columnPlan{name: field.Name}
+	if field.Nullable {
+		column.nullRate = defaultNullRate
+		if field.NullRate != nil {
+			column.nullRate = *field.NullRate
+		}
+		if column.nullRate < 0 || column.nullRate > 1 {
+			return nil, fmt.Errorf("nullRate must be between 0 and 1")
+		}
+	}
+
+	if field.References != "" {
+		parent, idx := resolveReference(field.References, planned)
+		if parent == nil || idx < 0 {
+			return nil, fmt.Errorf("references unknown column %q", field.References)
+		}
+		// Foreign keys take the type of the key they point at
+		column.kind = parent.columns[idx].kind
+		column.layout = parent.columns[idx].layout
+		column.ref = field.References
+		return column, nil // gen is bound to the parent's values at run time
+	}
+
+	var err error
+	switch field.Type {
+	case columnTypeSequence:
+		column.kind = kindInt
+		column.gen = sequenceGenerator(field)
+	case project.ColumnTypeInteger, project.ColumnTypeFloat:
+		column.kind = kindFloat
+		if field.Type == project.ColumnTypeInteger {
+			column.kind = kindInt
+		}
+		column.gen, err = numericGenerator(field, column.kind == kindInt)
+	case project.ColumnTypeBoolean:
+		column.kind = kindBool
+		column.gen = func(rng *rand.Rand, _ int64) interface{} { return rng.IntN(2) == 1 }
+	case project.ColumnTypeDate, project.ColumnTypeDateTime:
+		column.kind, column.layout = kindDate, time.DateOnly
+		if field.Type == project.ColumnTypeDateTime {
+			column.kind, column.layout = kindDateTime, time.RFC3339
+		}
+		if field.Format != "" {
+			column.layout = field.Format
+		}
+		column.gen, err = dateGenerator(field, column.kind, column.layout)
+	case project.ColumnTypeCategorical:
+		column.gen, err = categoricalGenerator(field)
+	case project.ColumnTypeText, project.ColumnTypeEmail:
+		pattern := field.Pattern
+		if pattern == "" {
+			pattern = `[A-Z][a-z]{2,9}( [a-z]{2,9}){0,5}`
+			if field.Type == project.ColumnTypeEmail {
+				pattern = `[a-z]{3,10}\.[a-z]{3,10}@example\.(com|org|net)`
+			}
+		}
+		column.gen, err = patternGenerator(pattern)
+	case project.ColumnTypeUUID:
+		column.gen = uuidGenerator
+	case project.ColumnTypeUnknown:
+		// Every sampled value was null, so the field only ever generates nulls
+		column.nullRate = 1
+		column.gen = func(*rand.Rand, int64) interface{} { return nil }
+	default:
+		return nil, fmt.Errorf("unsupported type %q", field.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return column, nil
+}
+
+// sequenceGenerator counts from start by step. Values depend only on the row index.
+func sequenceGenerator(field fieldSpec) valueFunc {
+	start, step := int64(1), int64(1)
+	if field.Start != nil {
+		start = *field.Start
+	}
+	if field.Step != nil {
+		step = *field.Step
+	}
+	return func(_ *rand.Rand, row int64) interface{} { return start + row*step }
+}
+
+// numericGenerator draws from the field's distribution, clamped to min and max when set.
+func numericGenerator(field fieldSpec, integer bool) (valueFunc, error) {
+	if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+		return nil, fmt.Errorf("min is greater than max")
+	}
+	mean, stddev := 0.0, 1.0
+	if field.Mean != nil {
+		mean = *field.Mean
+	}
+	if field.StdDev != nil {
+		if *field.StdDev < 0 {
+			return nil, fmt.Errorf("stddev must not be negative")
+		}
+		stddev = *field.StdDev
+	}
+
+	var draw func(rng *rand.Rand) float64
+	switch field.Distribution {
+	case "", "uniform":
+		lo, hi := 0.0, 1000.0
+		if field.Min != nil {
+			lo = *field.Min
+		}
+		if field.Max != nil {
+			hi = *field.Max
+		}
+		if lo > hi {
+			return nil, fmt.Errorf("uniform range is empty; set both min and max")
+		}
+		if integer {
+			lo, hi = math.Ceil(lo), math.Floor(hi)
+			if lo > hi {
+				return nil, fmt.Errorf("range [min, max] contains no integer")
+			}
+			span := int64(hi - lo + 1)
+			return func(rng *rand.Rand, _ int64) interface{} { return int64(lo) + rng.Int64N(span) }, nil
+		}
+		draw = func(rng *rand.Rand) float64 { return lo + rng.Float64()*(hi-lo) }
+	case "normal":
+		draw = func(rng *rand.Rand) float64 { return mean + rng.NormFloat64()*stddev }
+	case "lognormal":
+		// mean and stddev describe the underlying normal distribution
+		draw = func(rng *rand.Rand) float64 { return math.Exp(mean + rng.NormFloat64()*stddev) }
+	case "exponential":
+		if field.Mean == nil {
+			mean = 1
+		}
+		if mean <= 0 {
+			return nil, fmt.Errorf("exponential mean must be positive")
+		}
+		draw = func(rng *rand.Rand) float64 { return rng.ExpFloat64() * mean }
+	default:
+		return nil, fmt.Errorf("unsupported distribution %q", field.Distribution)
+	}
+
+	return func(rng *rand.Rand, _ int64) interface{} {
+		v := draw(rng)
+		if field.Min != nil && v < *field.Min {
+			v = *field.Min
+		}
+		if field.Max != nil && v > *field.Max {
+			v = *field.Max
+		}
+		if integer {
+			return int64(math.Round(v))
+		}
+		return v
+	}, nil
+}
+
+// dateGenerator draws uniformly from [from, to). Dates are whole days.
+func dateGenerator(field fieldSpec, kind valueKind, layout string) (valueFunc, error) {
+	from, to := defaultRangeFrom, defaultRangeTo
+	var err error
+	if field.From != "" {
+		if from, err = time.Parse(layout, field.From); err != nil {
+			return nil, fmt.Errorf("from %q does not match format %q", field.From, layout)
+		}
+	}
+	if field.To != "" {
+		if to, err = time.Parse(layout, field.To); err != nil {
+			return nil, fmt.Errorf("to %q does not match format %q", field.To, layout)
+		}
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	from, to = from.UTC(), to.UTC()
+
+	if kind == kindDate {
+		start := from.Truncate(24 * time.Hour)
+		days := int64(math.Ceil(to.Sub(start).Hours() / 24))
+		if days < 1 {
+			days = 1
+		}
+		return func(rng *rand.Rand, _ int64) interface{} {
+			return start.AddDate(0, 0, int(rng.Int64N(days)))
+		}, nil
+	}
+	seconds := int64(to.Sub(from).Seconds())
+	if seconds < 1 {
+		seconds = 1
+	}
+	return func(rng *rand.Rand, _ int64) interface{} {
+		return from.Add(time.Duration(rng.Int64N(seconds)) * time.Second)
+	}, nil
+}
+
+// categoricalGenerator picks values by their relative weights, uniformly without weights.
+func categoricalGenerator(field fieldSpec) (valueFunc, error) {
+	if len(field.Values) == 0 {
+		return nil, fmt.Errorf("categorical fields need values")
+	}
+	if len(field.Weights) == 0 {
+		values := field.Values
+		return func(rng *rand.Rand, _ int64) interface{} { return values[rng.IntN(len(values))] }, nil
+	}
+	if len(field.Weights) != len(field.Values) {
+		return nil, fmt.Errorf("weights must have one entry per value")
+	}
+	cumulative := make([]float64, len(field.Weights))
+	var total float64
+	for i, w := range field.Weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("weights must be non-negative numbers")
+		}
+		total += w
+		cumulative[i] = total
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("weights must not all be zero")
+	}
+	values := field.Values
+	return func(rng *rand.Rand, _ int64) interface{} {
+		target := rng.Float64() * total
+		i := sort.Search(len(cumulative), func(i int) bool { return cumulative[i] > target })
+		if i == len(cumulative) {
+			i--
+		}
+		return values[i]
+	}, nil
+}
+
+// uuidGenerator produces version 4 UUIDs from the row's random source.
+func uuidGenerator(rng *rand.Rand, _ int64) interface{} {
+	var id uuid.UUID
+	for i := 0; i < len(id); i += 8 {
+		n := rng.Uint64()
+		for j := 0; j < 8; j++ {
+			id[i+j] = byte(n >> (8 * j))
+		}
+	}
+	id[6] = id[6]&0x0f | 0x40 // Version 4
+	id[8] = id[8]&0x3f | 0x80 // RFC 4122 variant
+	return id.String()
+}
+
+// foreignKeyGenerator samples uniformly from a parent column's non-null values.
+func foreignKeyGenerator(values []interface{}) valueFunc {
+	return func(rng *rand.Rand, _ int64) interface{} { return values[rng.IntN(len(values))] }
+}
+
+// --- Regular expressions ---
+
+// patternGenerator compiles a regular expression into a generator of matching strings.
+// Anchors and word boundaries are ignored; unbounded repeats add up to maxPatternRepeat
+// repetitions, and classes prefer printable ASCII.
+func patternGenerator(pattern string) (valueFunc, error) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %v", err)
+	}
+	re = re.Simplify()
+	if err := checkPattern(re); err != nil {
+		return nil, err
+	}
+	return func(rng *rand.Rand, _ int64) interface{} {
+		var sb strings.Builder
+		writePattern(&sb, re, rng)
+		return sb.String()
+	}, nil
+}
+
+func checkPattern(re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return fmt.Errorf("pattern matches nothing")
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return fmt.Errorf("pattern has an empty character class")
+		}
+	}
+	for _, sub := range re.Sub {
+		if err := checkPattern(sub); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writePattern(sb *strings.Builder, re *syntax.Regexp, rng *rand.Rand) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			sb.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		sb.WriteRune(pickClassRune(re.Rune, rng))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteRune(rune(' ' + rng.IntN('~'-' '+1)))
+	case syntax.OpCapture:
+		writePattern(sb, re.Sub[0], rng)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writePattern(sb, sub, rng)
+		}
+	case syntax.OpAlternate:
+		writePattern(sb, re.Sub[rng.IntN(len(re.Sub))], rng)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := re.Min, re.Max
+		switch re.Op {
+		case syntax.OpStar:
+			lo, hi = 0, -1
+		case syntax.OpPlus:
+			lo, hi = 1, -1
+		case syntax.OpQuest:
+			lo, hi = 0, 1
+		}
+		if hi < 0 {
+			hi = lo + maxPatternRepeat
+		}
+		for n := lo + rng.IntN(hi-lo+1); n > 0; n-- {
+			writePattern(sb, re.Sub[0], rng)
+		}
+	}
+	// Empty matches, anchors and word boundaries produce no text
+}
+
+// pickClassRune picks a rune from a class given as [lo, hi] pairs, restricted to printable
+// ASCII when the class has any.
+func pickClassRune(ranges []rune, rng *rand.Rand) rune {
+	pick := func(lo, hi rune) rune {
+		var total int
+		for i := 0; i < len(ranges); i += 2 {
+			if a, b := max(ranges[i], lo), min(ranges[i+1], hi); a <= b {
+				total += int(b - a + 1)
+			}
+		}
+		if total == 0 {
+			return -1
+		}
+		n := rng.IntN(total)
+		for i := 0; i < len(ranges); i += 2 {
+			a, b := max(ranges[i], lo), min(ranges[i+1], hi)
+			if a > b {
+				continue
+			}
+			if size := int(b - a + 1); n >= size {
+				n -= size
+				continue
+			}
+			return a + rune(n)
+		}
+		return -1
+	}
+	if r := pick(' ', '~'); r >= 0 {
+		return r
+	}
+	return pick(0, 0x10FFFF)
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateCSV plans a config and generates its tables as CSV, keyed by table name.
+func generateCSV(t *testing.T, config string, workers int) map[string][][]string {
+	t.Helper()
+	plan, err := planGeneration(config, "csv", DefaultLocalMaxRecords)
+	require.NoError(t, err)
+	refs := make(map[string][]interface{})
+	tables := make(map[string][][]string)
+	for _, table := range plan.tables {
+		var buf bytes.Buffer
+		require.NoError(t, generateTable(context.Background(), plan, table, refs, &buf, workers))
+		records, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		tables[table.name] = records
+	}
+	return tables
+}
+
+func TestPlanGeneration_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		errMsg string
+	}{
+		{"NotJSON", `schema`, "not a JSON object"},
+		{"NoSchema", `{"parameters":{"recordCount":10}}`, "no schema"},
+		{"NoRecordCount", `{"schema":[{"name":"id","type":"sequence"}]}`, "positive recordCount"},
+		{"TooManyRecords", `{"schema":[{"name":"id","type":"sequence"}],"parameters":{"recordCount":2000000}}`, "limited to"},
+		{"UnknownType", `{"schema":[{"name":"id","type":"money"}],"parameters":{"recordCount":1}}`, `unsupported type "money"`},
+		{"DuplicateField", `{"schema":[{"name":"id","type":"uuid"},{"name":"id","type":"uuid"}],"parameters":{"recordCount":1}}`, "duplicate field"},
+		{"BadPattern", `{"schema":[{"name":"code","type":"text","pattern":"[a-"}],"parameters":{"recordCount":1}}`, "invalid pattern"},
+		{"WeightsMismatch", `{"schema":[{"name":"c","type":"categorical","values":["a","b"],"weights":[1]}],"parameters":{"recordCount":1}}`, "one entry per value"},
+		{"BadDateRange", `{"schema":[{"name":"d","type":"date","from":"2024-02-01","to":"2024-01-01"}],"parameters":{"recordCount":1}}`, "from must be before to"},
+		{"UnsupportedFormat", `{"schema":[{"name":"id","type":"uuid"}],"parameters":{"recordCount":1,"format":"xml"}}`, "unsupported output format"},
+		{"UnknownReference", `{"tables":[{"name":"orders","recordCount":1,"schema":[{"name":"customer","references":"customers.id"}]}]}`, "unknown column"},
+		{"ReferenceCycle", `{"tables":[
+			{"name":"a","recordCount":1,"schema":[{"name":"id","type":"sequence"},{"name":"b","references":"b.id"}]},
+			{"name":"b","recordCount":1,"schema":[{"name":"id","type":"sequence"},{"name":"a","references":"a.id"}]}]}`, "cycle"},
+		{"BadTableName", `{"tables":[{"name":"../x","recordCount":1,"schema":[{"name":"id","type":"uuid"}]}]}`, "table name"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := planGeneration(tc.config, "csv", DefaultLocalMaxRecords)
+
+			assert.ErrorIs(t, err, ErrInvalidGenerationSpec)
+			assert.ErrorContains(t, err, tc.errMsg)
+		})
+	}
+}
+
+func TestGenerateTable_ReproducibleAcrossWorkerCounts(t *testing.T) {
+	config := `{"schema":[
+		{"name":"id","type":"uuid"},
+		{"name":"score","type":"float","distribution":"normal","mean":50,"stddev":10,"nullable":true},
+		{"name":"tier","type":"categorical","values":["gold","silver"]}
+	],"parameters":{"recordCount":12000,"seed":7}}`
+
+	single := generateCSV(t, config, 1)
+	pooled := generateCSV(t, config, 4)
+	reseeded := generateCSV(t, `{"schema":[{"name":"id","type":"uuid"}],"parameters":{"recordCount":12000,"seed":8}}`, 4)
+
+	require.Len(t, single["output"], 12001) // Header plus rows
+	assert.Equal(t, single, pooled)
+	assert.NotEqual(t, single["output"][1][0], reseeded["output"][1][0])
+}
+
+func TestGenerateTable_ColumnTypes(t *testing.T) {
+	config := `{"tables":[
+		{"name":"orders","recordCount":2000,"schema":[
+			{"name":"order_id","type":"sequence","start":100,"step":5},
+			{"name":"customer_id","references":"customers.id"},
+			{"name":"status","type":"categorical","values":["open","closed","void"],"weights":[3,1,0]},
+			{"name":"placed","type":"date","from":"2024-01-01","to":"2024-02-01"},
+			{"name":"amount","type":"integer","min":10,"max":20},
+			{"name":"note","type":"unknown"}
+		]},
+		{"name":"customers","recordCount":50,"schema":[
+			{"name":"id","type":"sequence"},
+			{"name":"code","type":"text","pattern":"^CUST-[0-9]{4}[A-Z]?$"},
+			{"name":"email","type":"email"},
+			{"name":"active","type":"boolean"}
+		]}
+	],"parameters":{"seed":1}}`
+
+	tables := generateCSV(t, config, 3)
+
+	customers := tables["customers"]
+	require.Len(t, customers, 51)
+	assert.Equal(t, []string{"id", "code", "email", "active"}, customers[0])
+	code := regexp.MustCompile(`^CUST-[0-9]{4}[A-Z]?$`)
+	email := regexp.MustCompile(`^[a-z]{3,10}\.[a-z]{3,10}@example\.(com|org|net)$`)
+	ids := make(map[string]bool)
+	for _, row := range customers[1:] {
+		ids[row[0]] = true
+		assert.Regexp(t, code, row[1])
+		assert.Regexp(t, email, row[2])
+		assert.Contains(t, []string{"true", "false"}, row[3])
+	}
+
+	orders := tables["orders"]
+	require.Len(t, orders, 2001)
+	statuses := make(map[string]int)
+	for i, row := range orders[1:] {
+		assert.Equal(t, strconv.Itoa(100+5*i), row[0])
+		assert.True(t, ids[row[1]], "customer_id %s is a customer", row[1])
+		statuses[row[2]]++
+		placed, err := time.Parse(time.DateOnly, row[3])
+		require.NoError(t, err)
+		assert.Equal(t, 2024, placed.Year())
+		assert.Equal(t, time.January, placed.Month())
+		amount, err := strconv.Atoi(row[4])
+		require.NoError(t, err)
+		assert.True(t, amount >= 10 && amount <= 20, "amount %d in range", amount)
+		assert.Empty(t, row[5])
+	}
+	assert.Zero(t, statuses["void"], "zero-weight categories never appear")
+	assert.InDelta(t, 0.75, float64(statuses["open"])/2000, 0.05)
+}
+
+func TestGenerateTable_JSONAndParquet(t *testing.T) {
+	config := func(format string) string {
+		return `{"schema":[
+			{"name":"zeta","type":"sequence"},
+			{"name":"alpha","type":"datetime","from":"2024-01-01T00:00:00Z","to":"2024-01-02T00:00:00Z"},
+			{"name":"day","type":"date","from":"2024-03-01","to":"2024-03-02"},
+			{"name":"maybe","type":"text","nullable":true,"nullRate":1}
+		],"parameters":{"recordCount":3,"seed":3,"format":"` + format + `"}}`
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		plan, err := planGeneration(config("json"), "", DefaultLocalMaxRecords)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, generateTable(context.Background(), plan, plan.tables[0], nil, &buf, 2))
+
+		var rows []map[string]interface{}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &rows))
+		require.Len(t, rows, 3)
+		assert.Equal(t, float64(1), rows[0]["zeta"])
+		assert.Equal(t, "2024-03-01", rows[0]["day"])
+		assert.Nil(t, rows[0]["maybe"])
+		assert.Regexp(t, `^2024-01-01T`, rows[0]["alpha"])
+	})
+
+	t.Run("Parquet", func(t *testing.T) {
+		plan, err := planGeneration(config("parquet"), "", DefaultLocalMaxRecords)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, generateTable(context.Background(), plan, plan.tables[0], nil, &buf, 2))
+
+		file, err := parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), file.NumRows())
+		var names []string
+		for _, field := range file.Schema().Fields() {
+			names = append(names, field.Name())
+		}
+		assert.Equal(t, []string{"zeta", "alpha", "day", "maybe"}, names, "columns keep the schema order")
+
+		rows := make([]parquet.Row, 3)
+		n, _ := file.RowGroups()[0].Rows().ReadRows(rows)
+		require.Equal(t, 3, n)
+		assert.Equal(t, int64(2), rows[1][0].Int64())
+		assert.Equal(t, int32(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).Unix()/86400), rows[1][2].Int32())
+		assert.True(t, rows[1][3].IsNull())
+	})
+}
+
+func TestPatternGenerator(t *testing.T) {
+	patterns := []string{
+		`[A-Z]{2}-\d{3}`,
+		`(foo|bar)+baz?`,
+		`\w+@\w+\.io`,
+		`[^a-z]{4}`,
+		`x.y`,
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, pattern := range patterns {
+		gen, err := patternGenerator(pattern)
+		require.NoError(t, err, pattern)
+		re := regexp.MustCompile(`^(?:` + pattern + `)$`)
+		for i := 0; i < 50; i++ {
+			value := gen(rng, 0).(string)
+			assert.Regexp(t, re, value, "pattern %s", pattern)
+		}
+	}
+}
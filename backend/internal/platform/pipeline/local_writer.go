@@ -0,0 +1,258 @@
+package pipeline
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+	"github.com/parquet-go/parquet-go/encoding"
+)
+
+// tableWriter encodes generated rows in an output format.
+type tableWriter interface {
+	// WriteRows appends rows whose values follow the table's column order.
+	WriteRows(rows [][]interface{}) error
+	// Close flushes buffered output. It does not close the underlying writer.
+	Close() error
+}
+
+// newTableWriter returns a writer for format ("csv", "json" or "parquet").
+func newTableWriter(format string, w io.Writer, columns []*columnPlan) (tableWriter, error) {
+	switch format {
+	case "csv":
+		return newCSVTableWriter(w, columns)
+	case "json":
+		return newJSONTableWriter(w, columns), nil
+	case "parquet":
+		return newParquetTableWriter(w, columns), nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+// formatValue renders a value as text for CSV and JSON. Nulls render as "".
+func formatValue(column *columnPlan, value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(column.layout)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// --- CSV ---
+
+type csvTableWriter struct {
+	columns []*columnPlan
+	writer  *csv.Writer
+	record  []string
+}
+
+func newCSVTableWriter(w io.Writer, columns []*columnPlan) (*csvTableWriter, error) {
+	cw := &csvTableWriter{columns: columns, writer: csv.NewWriter(w), record: make([]string, len(columns))}
+	for i, column := range columns {
+		cw.record[i] = column.name
+	}
+	if err := cw.writer.Write(cw.record); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvTableWriter) WriteRows(rows [][]interface{}) error {
+	for _, row := range rows {
+		for i, value := range row {
+			cw.record[i] = formatValue(cw.columns[i], value)
+		}
+		if err := cw.writer.Write(cw.record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cw *csvTableWriter) Close() error {
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// --- JSON ---
+
+// jsonTableWriter writes a top-level array of objects whose keys keep the column order.
+type jsonTableWriter struct {
+	columns []*columnPlan
+	writer  *bufio.Writer
+	keys    [][]byte
+	rows    int
+}
+
+func newJSONTableWriter(w io.Writer, columns []*columnPlan) *jsonTableWriter {
+	jw := &jsonTableWriter{columns: columns, writer: bufio.NewWriter(w), keys: make([][]byte, len(columns))}
+	for i, column := range columns {
+		jw.keys[i], _ = json.Marshal(column.name)
+	}
+	return jw
+}
+
+func (jw *jsonTableWriter) WriteRows(rows [][]interface{}) error {
+	for _, row := range rows {
+		if jw.rows == 0 {
+			jw.writer.WriteString("[\n{")
+		} else {
+			jw.writer.WriteString(",\n{")
+		}
+		jw.rows++
+		for i, value := range row {
+			if i > 0 {
+				jw.writer.WriteByte(',')
+			}
+			jw.writer.Write(jw.keys[i])
+			jw.writer.WriteByte(':')
+			if t, ok := value.(time.Time); ok {
+				value = t.Format(jw.columns[i].layout)
+			}
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			if _, err := jw.writer.Write(encoded); err != nil {
+				return err
+			}
+		}
+		jw.writer.WriteByte('}')
+	}
+	return nil
+}
+
+func (jw *jsonTableWriter) Close() error {
+	if jw.rows == 0 {
+		jw.writer.WriteString("[")
+	}
+	jw.writer.WriteString("\n]\n")
+	return jw.writer.Flush()
+}
+
+// --- Parquet ---
+
+// parquetTableWriter writes optional columns: dates as DATE, datetimes as millisecond
+// TIMESTAMP and strings as UTF-8 byte arrays.
+type parquetTableWriter struct {
+	columns []*columnPlan
+	writer  *parquet.Writer
+	buffer  []parquet.Row
+}
+
+func newParquetTableWriter(w io.Writer, columns []*columnPlan) *parquetTableWriter {
+	fields := make([]parquet.Field, len(columns))
+	for i, column := range columns {
+		var leaf parquet.Node
+		switch column.kind {
+		case kindInt:
+			leaf = parquet.Int(64)
+		case kindFloat:
+			leaf = parquet.Leaf(parquet.DoubleType)
+		case kindBool:
+			leaf = parquet.Leaf(parquet.BooleanType)
+		case kindDate:
+			leaf = parquet.Date()
+		case kindDateTime:
+			leaf = parquet.Timestamp(parquet.Millisecond)
+		default:
+			leaf = parquet.String()
+		}
+		fields[i] = parquetField{Node: parquet.Optional(leaf), name: column.name}
+	}
+	schema := parquet.NewSchema("generated", parquetOrderedGroup(fields))
+	return &parquetTableWriter{columns: columns, writer: parquet.NewWriter(w, schema)}
+}
+
+func (pw *parquetTableWriter) WriteRows(rows [][]interface{}) error {
+	pw.buffer = pw.buffer[:0]
+	for _, row := range rows {
+		out := make(parquet.Row, len(row))
+		for i, value := range row {
+			if value == nil {
+				out[i] = parquet.NullValue().Level(0, 0, i)
+				continue
+			}
+			out[i] = parquetValueOf(pw.columns[i].kind, value).Level(0, 1, i)
+		}
+		pw.buffer = append(pw.buffer, out)
+	}
+	_, err := pw.writer.WriteRows(pw.buffer)
+	return err
+}
+
+func (pw *parquetTableWriter) Close() error {
+	return pw.writer.Close()
+}
+
+func parquetValueOf(kind valueKind, value interface{}) parquet.Value {
+	switch v := value.(type) {
+	case int64:
+		return parquet.Int64Value(v)
+	case float64:
+		return parquet.DoubleValue(v)
+	case bool:
+		return parquet.BooleanValue(v)
+	case string:
+		return parquet.ByteArrayValue([]byte(v))
+	case time.Time:
+		if kind == kindDate {
+			return parquet.Int32Value(int32(v.Unix() / (24 * 60 * 60))) // Days since the epoch
+		}
+		return parquet.Int64Value(v.UnixMilli())
+	}
+	return parquet.NullValue()
+}
+
+// parquetOrderedGroup is a group node whose fields keep the given order; parquet.Group
+// sorts its fields by name, which would reorder the generated columns.
+type parquetOrderedGroup []parquet.Field
+
+func (g parquetOrderedGroup) ID() int { return 0 }
+func (g parquetOrderedGroup) String() string {
+	group := make(parquet.Group, len(g))
+	for _, field := range g {
+		group[field.Name()] = field
+	}
+	return group.String()
+}
+func (g parquetOrderedGroup) Type() parquet.Type          { return parquet.Group{}.Type() }
+func (g parquetOrderedGroup) Optional() bool              { return false }
+func (g parquetOrderedGroup) Repeated() bool              { return false }
+func (g parquetOrderedGroup) Required() bool              { return true }
+func (g parquetOrderedGroup) Leaf() bool                  { return false }
+func (g parquetOrderedGroup) Fields() []parquet.Field     { return g }
+func (g parquetOrderedGroup) Encoding() encoding.Encoding { return nil }
+func (g parquetOrderedGroup) Compression() compress.Codec { return nil }
+func (g parquetOrderedGroup) GoType() reflect.Type {
+	return reflect.TypeOf(map[string]interface{}(nil))
+}
+
+// parquetField names a column of a parquetOrderedGroup. Rows are written as parquet.Row,
+// so the reflection-based Value is never used.
+type parquetField struct {
+	parquet.Node
+	name string
+}
+
+func (f parquetField) Name() string                           { return f.name }
+func (f parquetField) Value(base reflect.Value) reflect.Value { return reflect.Value{} }
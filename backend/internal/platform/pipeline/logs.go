@@ -0,0 +1,32 @@
+package pipeline
+
+import (
+	"SynDataGen/backend/internal/job"
+	"time"
+)
+
+// defaultLogLimit caps GetLogs calls that pass no limit.
+const defaultLogLimit = 100
+
+// appendLogEntry adds an entry to an in-memory job log, numbering it after the last one.
+func appendLogEntry(logs []job.LogEntry, at time.Time, level, stage, message string) []job.LogEntry {
+	return append(logs, job.LogEntry{
+		Sequence:  int64(len(logs)) + 1,
+		Timestamp: at.UTC(),
+		Level:     level,
+		Stage:     stage,
+		Message:   message,
+	})
+}
+
+// pageLogEntries returns a copy of up to limit entries after sequence since.
+func pageLogEntries(logs []job.LogEntry, since int64, limit int) []job.LogEntry {
+	if limit <= 0 {
+		limit = defaultLogLimit
+	}
+	if since < 0 || since >= int64(len(logs)) {
+		return []job.LogEntry{}
+	}
+	end := min(int(since)+limit, len(logs))
+	return append([]job.LogEntry{}, logs[since:end]...)
+}
@@ -0,0 +1,189 @@
+package pipeline
+
+import (
+	"SynDataGen/backend/internal/job"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without contacting the pipeline while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("pipeline circuit breaker is open")
+
+// APIError is a non-success response from the DataGen API.
+type APIError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration // From the Retry-After header, if any
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("pipeline API error (%d): %s", e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the request may succeed if sent again: timeouts, throttling and
+// server errors other than 501 Not Implemented.
+func (e *APIError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return e.StatusCode >= 500 && e.StatusCode != http.StatusNotImplemented
+}
+
+// Unwrap lets callers test retryable responses with errors.Is(err, job.ErrPipelineUnavailable).
+func (e *APIError) Unwrap() error {
+	if e.Retryable() {
+		return job.ErrPipelineUnavailable
+	}
+	return nil
+}
+
+// RetryPolicy configures exponential backoff with full jitter between attempts.
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts per call; defaults to 4, 1 disables retries
+	BaseDelay   time.Duration // Upper bound of the first backoff; defaults to 200ms
+	MaxDelay    time.Duration // Cap on any single wait, including Retry-After; defaults to 10s
+}
+
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 4
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = 200 * time.Millisecond
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = 10 * time.Second
+	}
+	return p
+}
+
+// backoff returns the wait before retry number attempt (1-based). A Retry-After hint longer
+// than MaxDelay is not waited for; ok is false and the caller should give up.
+func (p RetryPolicy) backoff(attempt int, retryAfter time.Duration) (delay time.Duration, ok bool) {
+	if retryAfter > p.MaxDelay {
+		return 0, false
+	}
+	ceiling := p.MaxDelay
+	if shift := attempt - 1; shift < 30 && p.BaseDelay<<shift < ceiling {
+		ceiling = p.BaseDelay << shift
+	}
+	delay = rand.N(ceiling + 1)
+	if delay < retryAfter {
+		delay = retryAfter
+	}
+	return delay, true
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// CircuitBreakerConfig configures when the client stops calling an unhealthy pipeline.
+type CircuitBreakerConfig struct {
+	FailureThreshold int           // Consecutive failed calls that open the circuit; defaults to 5
+	OpenDuration     time.Duration // How long calls fail fast before a probe; defaults to 30s
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker fails calls fast after repeated outages. Once OpenDuration has passed a
+// single probe call is let through; its outcome closes or reopens the circuit.
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    circuitState
+	failures int
+	openedAt time.Time
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenDuration <= 0 {
+		cfg.OpenDuration = 30 * time.Second
+	}
+	return &circuitBreaker{cfg: cfg, now: time.Now}
+}
+
+// allow reports whether a call may proceed, returning how long until the next probe if not.
+func (b *circuitBreaker) allow() (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case circuitOpen:
+		if wait := b.openedAt.Add(b.cfg.OpenDuration).Sub(b.now()); wait > 0 {
+			return false, wait
+		}
+		b.state = circuitHalfOpen
+		return true, 0
+	case circuitHalfOpen:
+		return false, b.cfg.OpenDuration // A probe is already in flight
+	default:
+		return true, 0
+	}
+}
+
+// record reports the outcome of an allowed call. Only outages count as failures; a pipeline
+// that answers with a client error is healthy.
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.state = circuitClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.state == circuitHalfOpen || b.failures >= b.cfg.FailureThreshold {
+		b.state = circuitOpen
+		b.openedAt = b.now()
+	}
+}
+
+// release ends an allowed call without an outcome, such as one cancelled by its caller. A
+// half-open circuit reopens with its original open time, so the next call probes again.
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == circuitHalfOpen {
+		b.state = circuitOpen
+	}
+}
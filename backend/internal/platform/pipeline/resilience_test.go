@@ -0,0 +1,240 @@
+package pipeline
+
+import (
+	"SynDataGen/backend/internal/job"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// flakyServer answers the first failures requests with status, then passes through to next.
+type flakyServer struct {
+	*httptest.Server
+	hits atomic.Int32
+}
+
+func newFlakyServer(t *testing.T, failures int32, status int, retryAfter string, next http.Handler) *flakyServer {
+	fs := &flakyServer{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fs.hits.Add(1) <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(status)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+func newResilientTestClient(t *testing.T, baseURL string, breaker CircuitBreakerConfig) *dataGenPipelineClient {
+	client, err := NewDataGenPipelineClient(Config{
+		BaseURL:        baseURL,
+		Logger:         zap.NewNop(),
+		Retry:          RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 20 * time.Millisecond},
+		CircuitBreaker: breaker,
+	})
+	require.NoError(t, err)
+	return client.(*dataGenPipelineClient)
+}
+
+func TestDataGenPipelineClient_Retries(t *testing.T) {
+	ctx := context.Background()
+	newStub := func() *StubPipeline { return NewStubPipeline(StubConfig{Logger: log.New(io.Discard, "", 0)}) }
+
+	t.Run("Success_RecoversFromTransientErrors", func(t *testing.T) {
+		stub := newStub()
+		server := newFlakyServer(t, 2, http.StatusServiceUnavailable, "", stub.Handler())
+		client := newResilientTestClient(t, server.URL, CircuitBreakerConfig{})
+
+		id, err := client.Submit(ctx, `{}`, "csv", "proj-1")
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, id)
+		assert.Equal(t, int32(3), server.hits.Load())
+	})
+
+	t.Run("Success_RetriedSubmissionIsIdempotent", func(t *testing.T) {
+		stub := newStub()
+		handler := stub.Handler()
+		var lost atomic.Bool
+		// The first submission reaches the pipeline but its response is lost in transit
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !lost.Swap(true) {
+				handler.ServeHTTP(httptest.NewRecorder(), r)
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		}))
+		defer server.Close()
+		client := newResilientTestClient(t, server.URL, CircuitBreakerConfig{})
+
+		id, err := client.Submit(job.WithIdempotencyKey(ctx, "job-123"), `{}`, "csv", "proj-1")
+		require.NoError(t, err)
+		again, err := client.Submit(job.WithIdempotencyKey(ctx, "job-123"), `{}`, "csv", "proj-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, id, again)
+		assert.Len(t, stub.jobs, 1, "retries never create a second pipeline job")
+	})
+
+	t.Run("Failure_GivesUpAfterMaxAttempts", func(t *testing.T) {
+		server := newFlakyServer(t, 100, http.StatusInternalServerError, "", newStub().Handler())
+		client := newResilientTestClient(t, server.URL, CircuitBreakerConfig{})
+
+		_, _, err := client.CheckStatus(ctx, "any")
+
+		assert.ErrorIs(t, err, job.ErrPipelineUnavailable)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusInternalServerError, apiErr.StatusCode)
+		assert.Equal(t, int32(3), server.hits.Load())
+	})
+
+	t.Run("Failure_TerminalErrorsAreNotRetried", func(t *testing.T) {
+		server := newFlakyServer(t, 100, http.StatusBadRequest, "", newStub().Handler())
+		client := newResilientTestClient(t, server.URL, CircuitBreakerConfig{})
+
+		err := client.Cancel(ctx, "any")
+
+		assert.ErrorContains(t, err, "400")
+		assert.NotErrorIs(t, err, job.ErrPipelineUnavailable)
+		assert.Equal(t, int32(1), server.hits.Load())
+	})
+
+	t.Run("Failure_RetryAfterBeyondMaxDelay", func(t *testing.T) {
+		server := newFlakyServer(t, 100, http.StatusTooManyRequests, "120", newStub().Handler())
+		client := newResilientTestClient(t, server.URL, CircuitBreakerConfig{})
+
+		_, _, err := client.CheckStatus(ctx, "any")
+
+		assert.ErrorIs(t, err, job.ErrPipelineUnavailable)
+		assert.Equal(t, int32(1), server.hits.Load(), "the client does not wait longer than MaxDelay")
+	})
+
+	t.Run("Failure_ConnectionRefused", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+		client := newResilientTestClient(t, server.URL, CircuitBreakerConfig{})
+
+		_, err := client.Submit(ctx, `{}`, "csv", "proj-1")
+
+		assert.ErrorIs(t, err, job.ErrPipelineUnavailable)
+	})
+}
+
+func TestDataGenPipelineClient_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	var healthy atomic.Bool
+	var hits atomic.Int32
+	stub := NewStubPipeline(StubConfig{Logger: log.New(io.Discard, "", 0)})
+	handler := stub.Handler()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := newResilientTestClient(t, server.URL, CircuitBreakerConfig{FailureThreshold: 3, OpenDuration: time.Minute})
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+
+	// Three failed attempts open the circuit
+	_, err := client.Submit(ctx, `{}`, "csv", "proj-1")
+	require.ErrorIs(t, err, job.ErrPipelineUnavailable)
+	require.Equal(t, int32(3), hits.Load())
+
+	// Calls now fail fast without reaching the pipeline
+	_, err = client.Submit(ctx, `{}`, "csv", "proj-1")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.ErrorIs(t, err, job.ErrPipelineUnavailable)
+	assert.Equal(t, int32(3), hits.Load())
+
+	// After the open period a failed probe reopens the circuit immediately
+	now = now.Add(time.Minute)
+	_, _, err = client.CheckStatus(ctx, "any")
+	assert.ErrorIs(t, err, job.ErrPipelineUnavailable)
+	assert.Equal(t, int32(4), hits.Load())
+	_, _, err = client.CheckStatus(ctx, "any")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// A successful probe closes it again
+	now = now.Add(time.Minute)
+	healthy.Store(true)
+	id, err := client.Submit(ctx, `{}`, "csv", "proj-1")
+	require.NoError(t, err)
+	_, _, err = client.CheckStatus(ctx, id)
+	assert.NoError(t, err)
+}
+
+func TestDataGenPipelineClient_CircuitBreaker_CancelledProbe(t *testing.T) {
+	var healthy atomic.Bool
+	var hits atomic.Int32
+	stub := NewStubPipeline(StubConfig{Logger: log.New(io.Discard, "", 0)})
+	handler := stub.Handler()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	client := newResilientTestClient(t, server.URL, CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+	now := time.Now()
+	client.breaker.now = func() time.Time { return now }
+
+	// One failed attempt opens the circuit
+	_, err := client.Submit(context.Background(), `{}`, "csv", "proj-1")
+	require.ErrorIs(t, err, job.ErrPipelineUnavailable)
+	require.Equal(t, int32(1), hits.Load())
+
+	// The caller gives up on the probe before it completes
+	now = now.Add(time.Minute)
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.Submit(cancelled, `{}`, "csv", "proj-1")
+	require.ErrorIs(t, err, context.Canceled)
+
+	// The next call is allowed to probe and closes the circuit
+	healthy.Store(true)
+	_, err = client.Submit(context.Background(), `{}`, "csv", "proj-1")
+	require.NoError(t, err)
+	assert.Equal(t, circuitClosed, client.breaker.state)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}.withDefaults()
+
+	for attempt := 1; attempt <= 8; attempt++ {
+		delay, ok := policy.backoff(attempt, 0)
+		require.True(t, ok)
+		assert.LessOrEqual(t, delay, min(100*time.Millisecond<<(attempt-1), time.Second))
+	}
+	delay, ok := policy.backoff(1, 500*time.Millisecond)
+	assert.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, delay, "Retry-After is a lower bound")
+	_, ok = policy.backoff(1, 2*time.Second)
+	assert.False(t, ok)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 7*time.Second, parseRetryAfter("7", now))
+	assert.Equal(t, 30*time.Second, parseRetryAfter(now.Add(30*time.Second).Format(http.TimeFormat), now))
+	assert.Zero(t, parseRetryAfter("soon", now))
+}
@@ -2,8 +2,14 @@ package pipeline
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,107 +18,596 @@ import (
 	"SynDataGen/backend/internal/job" // Import the job package to use the interface
 )
 
-// stubPipelineClient implements the job.PipelineClient interface with stub logic.
-type stubPipelineClient struct {
+// Stub simulator errors
+var (
+	ErrStubJobNotFound    = errors.New("stub pipeline: job not found")
+	ErrStubInvalidAction  = errors.New("stub pipeline: action not allowed in current status")
+	ErrStubScenarioFormat = errors.New("stub pipeline: invalid stubScenario")
+)
+
+// stubScenarioKey names the job config section that scripts a simulated job.
+const stubScenarioKey = "stubScenario"
+
+// Simulated DataGen job statuses, as reported by the DataGen v2 API.
+const (
+	stubStatusQueued    = "queued"
+	stubStatusRunning   = "running"
+	stubStatusPaused    = "paused"
+	stubStatusCompleted = "completed"
+	stubStatusFailed    = "failed"
+	stubStatusCancelled = "cancelled"
+)
+
+// StubStage is a step of a simulated job.
+type StubStage struct {
+	Name       string        `json:"name"`
+	Duration   time.Duration `json:"-"`
+	DurationMs int64         `json:"durationMs"` // Duration when scripted in a job config
+}
+
+// DefaultStubStages are the stages of a simulated job unless configured otherwise.
+var DefaultStubStages = []StubStage{
+	{Name: "validate", Duration: 2 * time.Second},
+	{Name: "generate", Duration: 5 * time.Second},
+	{Name: "export", Duration: 2 * time.Second},
+}
+
+// StubScenario scripts one simulated job. It is read from the "stubScenario" section of
+// a job config, so integration tests can choose each job's outcome.
+type StubScenario struct {
+	Reject    bool        `json:"reject"`    // Reject the submission (DataGen answers "rejected")
+	Fail      bool        `json:"fail"`      // Fail halfway through FailStage
+	FailStage string      `json:"failStage"` // Defaults to the last stage
+	Error     string      `json:"error"`     // Failure message
+	Stages    []StubStage `json:"stages"`    // Replaces the configured stages
+}
+
+// StubConfig configures the pipeline simulator.
+type StubConfig struct {
+	// Stages run in order after QueueDelay. Defaults to DefaultStubStages.
+	Stages     []StubStage
+	QueueDelay time.Duration
+	// ProgressStep rounds reported progress down to a multiple of this percentage, so
+	// progress advances in ticks. Defaults to 10.
+	ProgressStep int
+	// FailureRate is the share of jobs failed at a random stage. Draws come from Seed and
+	// the submission order, so a run with the same seed fails the same jobs.
+	FailureRate float64
+	Seed        uint64
+	// CancelDelay is how long a cancellation takes to land. A job that finishes within
+	// the delay keeps its outcome, simulating a cancel that loses the race.
+	CancelDelay time.Duration
+	// Token, when set, is the bearer token the HTTP API requires.
+	Token string
+	// Now is the simulator's clock. Defaults to time.Now.
+	Now    func() time.Time
+	Logger *log.Logger
+}
+
+// stubJob is the simulated state of one job. Its status is derived from how long it has
+// been active, so observing a job never changes its outcome.
+type stubJob struct {
+	id          string
+	projectID   string
+	stages      []StubStage
+	failStage   int // Index of the failing stage, -1 if the job succeeds
+	failMessage string
+	submittedAt time.Time
+	pausedAt    *time.Time
+	pausedFor   time.Duration
+	cancelAt    *time.Time
+
+	// The job's log. Timeline events are appended once the job's active time reaches them.
+	timeline []stubLogEvent
+	logged   int // Timeline events already in logs
+	logs     []job.LogEntry
+
+	// Set once the job reaches a final status
+	final      string
+	finishedAt time.Time
+}
+
+// stubLogEvent is a log line due once a job has been active for offset.
+type stubLogEvent struct {
+	offset  time.Duration
+	level   string
+	stage   string
+	message string
+}
+
+// StubPipeline is a deterministic, concurrency-safe simulator of the DataGen pipeline.
+// It implements job.PipelineClient and, through Handler, the DataGen v2 HTTP API.
+type StubPipeline struct {
+	cfg    StubConfig
 	logger *log.Logger
-	// Store job statuses in memory for basic state simulation (optional)
-	jobStates map[string]core.JobStatus
+
+	mu        sync.Mutex
+	jobs      map[string]*stubJob
+	byKey     map[string]string // Idempotency key -> job ID
+	submitted uint64            // Submission counter feeding the failure draws
 }
 
-// NewStubPipelineClient creates a new stub pipeline client.
+// Ensure StubPipeline satisfies the pipeline client and progress interfaces.
+var (
+	_ job.PipelineClient   = (*StubPipeline)(nil)
+	_ job.ProgressReporter = (*StubPipeline)(nil)
+)
+
+// NewStubPipelineClient creates a stub pipeline client with the default scenario.
 func NewStubPipelineClient(logger *log.Logger) job.PipelineClient {
-	if logger == nil {
-		logger = log.Default()
+	return NewStubPipeline(StubConfig{Logger: logger})
+}
+
+// NewStubPipeline creates a pipeline simulator.
+func NewStubPipeline(cfg StubConfig) *StubPipeline {
+	if cfg.Logger == nil {
+		cfg.Logger = log.Default()
 	}
-	logger.Println("Initialized Stub Pipeline Client")
-	return &stubPipelineClient{
-		logger:    logger,
-		jobStates: make(map[string]core.JobStatus),
+	if len(cfg.Stages) == 0 {
+		cfg.Stages = DefaultStubStages
+	}
+	if cfg.ProgressStep <= 0 || cfg.ProgressStep > 100 {
+		cfg.ProgressStep = 10
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	cfg.Logger.Println("Initialized Stub Pipeline Client")
+	return &StubPipeline{
+		cfg:    cfg,
+		logger: cfg.Logger,
+		jobs:   make(map[string]*stubJob),
+		byKey:  make(map[string]string),
 	}
 }
 
 // Submit simulates sending a job to the pipeline.
-func (s *stubPipelineClient) Submit(ctx context.Context, jobConfig string, jobType string, projectID string) (string, error) {
-	pipelineJobID := uuid.NewString() // Generate a fake pipeline ID
-	s.logger.Printf("[StubPipeline] Received Submit request for project %s, type %s. Assigned pipeline Job ID: %s", projectID, jobType, pipelineJobID)
-
-	// Simulate initial state
-	s.jobStates[pipelineJobID] = core.JobStatusPending
-
-	// Simulate potential immediate failure (optional, for testing)
-	// if rand.Intn(10) == 0 {
-	// 	 s.logger.Printf("[StubPipeline] Simulating immediate submission failure for %s", pipelineJobID)
-	// 	 s.jobStates[pipelineJobID] = core.JobStatusFailed
-	// 	 return "", fmt.Errorf("stub pipeline simulation: immediate submission failure")
-	// }
-
-	s.logger.Printf("[StubPipeline] Job %s accepted (simulated). Initial status: %s", pipelineJobID, core.JobStatusPending)
-	return pipelineJobID, nil
-}
-
-// CheckStatus simulates checking the status of a job in the pipeline.
-func (s *stubPipelineClient) CheckStatus(ctx context.Context, pipelineJobID string) (core.JobStatus, string, error) {
-	s.logger.Printf("[StubPipeline] Received CheckStatus request for pipeline Job ID: %s", pipelineJobID)
-
-	currentStatus, exists := s.jobStates[pipelineJobID]
-	if !exists {
-		s.logger.Printf("[StubPipeline] Job %s not found (simulated).", pipelineJobID)
-		return "", "", fmt.Errorf("stub pipeline simulation: job %s not found", pipelineJobID)
-	}
-
-	// Simple state progression simulation (can be made more complex)
-	var nextStatus core.JobStatus
-	var pipelineError string
-	switch currentStatus {
-	case core.JobStatusPending:
-		nextStatus = core.JobStatusRunning
-	case core.JobStatusRunning:
-		// Randomly complete or fail
-		if time.Now().Second()%3 == 0 { // Fail roughly 1/3 of the time
-			nextStatus = core.JobStatusFailed
-			pipelineError = "Stub simulation: random failure during processing."
-		} else {
-			nextStatus = core.JobStatusCompleted
+func (s *StubPipeline) Submit(ctx context.Context, jobConfig string, jobType string, projectID string) (string, error) {
+	scenario, err := parseStubScenario(jobConfig)
+	if err != nil {
+		return "", err
+	}
+	return s.submit(projectID, job.IdempotencyKeyFromContext(ctx), scenario)
+}
+
+// CheckStatus reports the simulated status of a job.
+func (s *StubPipeline) CheckStatus(ctx context.Context, pipelineJobID string) (core.JobStatus, string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[pipelineJobID]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrStubJobNotFound, pipelineJobID)
+	}
+	status, _, errMsg := s.observeLocked(j)
+	return mapPipelineStatusToCoreStatus(status), errMsg, nil
+}
+
+// Progress reports the simulated progress of a job, in ProgressStep ticks.
+func (s *StubPipeline) Progress(ctx context.Context, pipelineJobID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[pipelineJobID]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", ErrStubJobNotFound, pipelineJobID)
+	}
+	_, progress, _ := s.observeLocked(j)
+	return progress, nil
+}
+
+// Cancel requests cancellation of a queued, running or paused job. With a CancelDelay the
+// job may still finish before the cancellation lands.
+func (s *StubPipeline) Cancel(ctx context.Context, pipelineJobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[pipelineJobID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrStubJobNotFound, pipelineJobID)
+	}
+	status, _, _ := s.observeLocked(j)
+	if j.final != "" {
+		return fmt.Errorf("%w: job %s is %s", ErrStubInvalidAction, pipelineJobID, status)
+	}
+	if j.cancelAt != nil {
+		return nil // Already cancelling
+	}
+	now := s.cfg.Now()
+	cancelAt := now.Add(s.cfg.CancelDelay)
+	j.cancelAt = &cancelAt
+	j.logs = appendLogEntry(j.logs, now, job.LogLevelWarn, "", "Cancellation requested")
+	s.observeLocked(j)
+	s.logger.Printf("[StubPipeline] Cancellation of job %s requested (status %s)", pipelineJobID, status)
+	return nil
+}
+
+// ResultURI reports no output location; the stub does not generate data.
+func (s *StubPipeline) ResultURI(ctx context.Context, pipelineJobID string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.jobs[pipelineJobID]; !ok {
+		return "", fmt.Errorf("%w: %s", ErrStubJobNotFound, pipelineJobID)
+	}
+	return "", nil
+}
+
+// GetLogs returns the simulated log of a job: acceptance, stage transitions, pauses,
+// cancellation and the outcome, each timestamped on the simulator's clock.
+func (s *StubPipeline) GetLogs(ctx context.Context, pipelineJobID string, since int64, limit int) ([]job.LogEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[pipelineJobID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrStubJobNotFound, pipelineJobID)
+	}
+	s.observeLocked(j)
+	return pageLogEntries(j.logs, since, limit), nil
+}
+
+// Pause freezes a queued or running job until Resume.
+func (s *StubPipeline) Pause(pipelineJobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[pipelineJobID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrStubJobNotFound, pipelineJobID)
+	}
+	status, _, _ := s.observeLocked(j)
+	if (status != stubStatusQueued && status != stubStatusRunning) || j.cancelAt != nil {
+		return fmt.Errorf("%w: cannot pause job %s in status %s", ErrStubInvalidAction, pipelineJobID, status)
+	}
+	now := s.cfg.Now()
+	j.pausedAt = &now
+	j.logs = appendLogEntry(j.logs, now, job.LogLevelInfo, "", "Job paused")
+	return nil
+}
+
+// Resume continues a paused job where it left off.
+func (s *StubPipeline) Resume(pipelineJobID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[pipelineJobID]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrStubJobNotFound, pipelineJobID)
+	}
+	if j.pausedAt == nil || j.cancelAt != nil {
+		status, _, _ := s.observeLocked(j)
+		return fmt.Errorf("%w: cannot resume job %s in status %s", ErrStubInvalidAction, pipelineJobID, status)
+	}
+	now := s.cfg.Now()
+	j.pausedFor += now.Sub(*j.pausedAt)
+	j.pausedAt = nil
+	j.logs = appendLogEntry(j.logs, now, job.LogLevelInfo, "", "Job resumed")
+	return nil
+}
+
+// submit registers a simulated job. Rejected scenarios return an error, and a repeated
+// idempotency key returns the job created for it the first time.
+func (s *StubPipeline) submit(projectID, idempotencyKey string, scenario StubScenario) (string, error) {
+	if scenario.Reject {
+		return "", fmt.Errorf("stub pipeline: job rejected by scenario")
+	}
+	stages := s.cfg.Stages
+	if len(scenario.Stages) > 0 {
+		stages = make([]StubStage, len(scenario.Stages))
+		for i, stage := range scenario.Stages {
+			stages[i] = StubStage{Name: stage.Name, Duration: time.Duration(stage.DurationMs) * time.Millisecond}
 		}
-	case core.JobStatusCompleted:
-		nextStatus = core.JobStatusCompleted // Stay completed
-	case core.JobStatusFailed:
-		nextStatus = core.JobStatusFailed // Stay failed
-		pipelineError = "Stub simulation: job previously failed."
-	case core.JobStatusCancelled:
-		nextStatus = core.JobStatusCancelled // Stay cancelled
-	default:
-		nextStatus = currentStatus // No change for unknown states
 	}
 
-	if nextStatus != currentStatus {
-		s.logger.Printf("[StubPipeline] Simulating status change for job %s: %s -> %s", pipelineJobID, currentStatus, nextStatus)
-		s.jobStates[pipelineJobID] = nextStatus
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id, ok := s.byKey[idempotencyKey]; ok && idempotencyKey != "" {
+		return id, nil
 	}
+	s.submitted++
+	j := &stubJob{
+		id:          uuid.NewString(),
+		projectID:   projectID,
+		stages:      stages,
+		failStage:   -1,
+		submittedAt: s.cfg.Now(),
+	}
+	switch {
+	case scenario.Fail:
+		j.failStage = len(stages) - 1
+		for i, stage := range stages {
+			if stage.Name == scenario.FailStage {
+				j.failStage = i
+			}
+		}
+		j.failMessage = scenario.Error
+	case s.cfg.FailureRate > 0:
+		rng := rand.New(rand.NewPCG(s.cfg.Seed, s.submitted))
+		if rng.Float64() < s.cfg.FailureRate {
+			j.failStage = rng.IntN(len(stages))
+		}
+	}
+	if j.failStage >= 0 && j.failMessage == "" {
+		j.failMessage = fmt.Sprintf("Stub simulation: injected failure during %s.", stages[j.failStage].Name)
+	}
+	j.timeline = s.timeline(j)
+	j.logs = appendLogEntry(j.logs, j.submittedAt, job.LogLevelInfo, "", fmt.Sprintf("Job accepted with %d stages", len(stages)))
+	s.jobs[j.id] = j
+	if idempotencyKey != "" {
+		s.byKey[idempotencyKey] = j.id
+	}
+	s.logger.Printf("[StubPipeline] Accepted job %s for project %s (%d stages, fails at stage %d)", j.id, projectID, len(stages), j.failStage)
+	return j.id, nil
+}
 
-	s.logger.Printf("[StubPipeline] Current simulated status for job %s: %s", pipelineJobID, nextStatus)
-	return nextStatus, pipelineError, nil
+// activeTime is how long a job has been unpaused at wall time t.
+func (j *stubJob) activeTime(t time.Time) time.Duration {
+	active := t.Sub(j.submittedAt) - j.pausedFor
+	if j.pausedAt != nil && t.After(*j.pausedAt) {
+		active -= t.Sub(*j.pausedAt)
+	}
+	return max(active, 0)
 }
 
-// Cancel simulates sending a cancellation request.
-func (s *stubPipelineClient) Cancel(ctx context.Context, pipelineJobID string) error {
-	s.logger.Printf("[StubPipeline] Received Cancel request for pipeline Job ID: %s", pipelineJobID)
+// evaluate returns the status, progress, current stage and error of a job that has been
+// active for the given time, ignoring cancellation.
+func (s *StubPipeline) evaluate(j *stubJob, active time.Duration) (status string, progress int, stage int, errMsg string) {
+	if active < s.cfg.QueueDelay {
+		return stubStatusQueued, 0, -1, ""
+	}
+	elapsed := active - s.cfg.QueueDelay
+	var total time.Duration
+	for _, st := range j.stages {
+		total += st.Duration
+	}
+	var start time.Duration
+	for i, st := range j.stages {
+		if i == j.failStage && elapsed >= start+st.Duration/2 {
+			return stubStatusFailed, s.progress(start+st.Duration/2, total), i, j.failMessage
+		}
+		if elapsed < start+st.Duration {
+			return stubStatusRunning, s.progress(elapsed, total), i, ""
+		}
+		start += st.Duration
+	}
+	return stubStatusCompleted, 100, len(j.stages), ""
+}
+
+func (s *StubPipeline) progress(elapsed, total time.Duration) int {
+	if total <= 0 {
+		return 100
+	}
+	pct := int(100 * elapsed / total)
+	return min(pct/s.cfg.ProgressStep*s.cfg.ProgressStep, 100)
+}
+
+// observeLocked returns a job's current status, progress and error, recording the
+// outcome once it is final. The caller must hold s.mu.
+func (s *StubPipeline) observeLocked(j *stubJob) (status string, progress int, errMsg string) {
+	if j.final != "" {
+		status, progress, _, errMsg = s.evaluate(j, j.activeTime(j.finishedAt))
+		if j.final == stubStatusCancelled {
+			return stubStatusCancelled, progress, ""
+		}
+		return j.final, progress, errMsg
+	}
+
+	now := s.cfg.Now()
+	if j.cancelAt != nil && !now.Before(*j.cancelAt) {
+		// The cancellation landed; it only wins if the job was still going at that point
+		s.logDueLocked(j, *j.cancelAt)
+		status, progress, _, errMsg = s.evaluate(j, j.activeTime(*j.cancelAt))
+		if status == stubStatusQueued || status == stubStatusRunning {
+			j.logs = appendLogEntry(j.logs, *j.cancelAt, job.LogLevelWarn, "", "Job cancelled")
+			j.final, j.finishedAt = stubStatusCancelled, *j.cancelAt
+			s.logger.Printf("[StubPipeline] Job %s cancelled", j.id)
+			return stubStatusCancelled, progress, ""
+		}
+		j.final, j.finishedAt = status, s.finishTime(j, status)
+		return status, progress, errMsg
+	}
+
+	s.logDueLocked(j, now)
+	status, progress, _, errMsg = s.evaluate(j, j.activeTime(now))
+	switch status {
+	case stubStatusCompleted, stubStatusFailed:
+		j.final, j.finishedAt = status, s.finishTime(j, status)
+		s.logger.Printf("[StubPipeline] Job %s %s", j.id, status)
+	case stubStatusQueued, stubStatusRunning:
+		if j.pausedAt != nil {
+			status = stubStatusPaused
+		}
+	}
+	return status, progress, errMsg
+}
+
+// timeline lists the log events of a job's stages, ending with its outcome.
+func (s *StubPipeline) timeline(j *stubJob) []stubLogEvent {
+	var events []stubLogEvent
+	offset := s.cfg.QueueDelay
+	for i, st := range j.stages {
+		events = append(events, stubLogEvent{offset, job.LogLevelInfo, st.Name, fmt.Sprintf("Stage %s started", st.Name)})
+		if i == j.failStage {
+			return append(events, stubLogEvent{offset + st.Duration/2, job.LogLevelError, st.Name, fmt.Sprintf("Stage %s failed: %s", st.Name, j.failMessage)})
+		}
+		offset += st.Duration
+		events = append(events, stubLogEvent{offset, job.LogLevelInfo, st.Name, fmt.Sprintf("Stage %s completed", st.Name)})
+	}
+	return append(events, stubLogEvent{offset, job.LogLevelInfo, "", "Job completed"})
+}
+
+// logDueLocked appends the timeline events a job has reached by wall time t. Every pause
+// and resume observes the job first, so events due earlier are already logged and the
+// remaining ones fall after the pauses counted in pausedFor. The caller must hold s.mu.
+func (s *StubPipeline) logDueLocked(j *stubJob, t time.Time) {
+	active := j.activeTime(t)
+	for ; j.logged < len(j.timeline) && j.timeline[j.logged].offset <= active; j.logged++ {
+		ev := j.timeline[j.logged]
+		j.logs = appendLogEntry(j.logs, j.submittedAt.Add(ev.offset+j.pausedFor), ev.level, ev.stage, ev.message)
+	}
+}
 
-	currentStatus, exists := s.jobStates[pipelineJobID]
-	if !exists {
-		s.logger.Printf("[StubPipeline] Job %s not found for cancellation (simulated).", pipelineJobID)
-		// Depending on requirements, this might not be an error if the job is already gone
-		return fmt.Errorf("stub pipeline simulation: job %s not found for cancel", pipelineJobID)
+// finishTime is the wall time a job reached a final status, given no pause after it.
+func (s *StubPipeline) finishTime(j *stubJob, status string) time.Time {
+	duration := s.cfg.QueueDelay
+	for i, st := range j.stages {
+		if status == stubStatusFailed && i == j.failStage {
+			duration += st.Duration / 2
+			break
+		}
+		duration += st.Duration
 	}
+	return j.submittedAt.Add(duration + j.pausedFor)
+}
 
-	// Only allow cancellation if pending or running
-	if currentStatus == core.JobStatusPending || currentStatus == core.JobStatusRunning {
-		s.logger.Printf("[StubPipeline] Simulating cancellation for job %s (status was %s).", pipelineJobID, currentStatus)
-		s.jobStates[pipelineJobID] = core.JobStatusCancelled
-		return nil
+// parseStubScenario reads the optional stubScenario section of a job config. Configs
+// that are not JSON objects run the default scenario.
+func parseStubScenario(jobConfig string) (StubScenario, error) {
+	var scenario StubScenario
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(jobConfig), &fields); err != nil {
+		return scenario, nil
 	}
+	raw, ok := fields[stubScenarioKey]
+	if !ok {
+		return scenario, nil
+	}
+	if err := json.Unmarshal(raw, &scenario); err != nil {
+		return scenario, fmt.Errorf("%w: %v", ErrStubScenarioFormat, err)
+	}
+	return scenario, nil
+}
+
+// --- DataGen v2 HTTP API ---
+
+// Handler serves the DataGen v2 job API backed by the simulator, so the DataGen client
+// can be exercised end to end without the real pipeline.
+func (s *StubPipeline) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v2/jobs", s.handleCreate)
+	mux.HandleFunc("GET /api/v2/jobs/{jobId}", s.handleStatus)
+	mux.HandleFunc("GET /api/v2/jobs/{jobId}/logs", s.handleLogs)
+	mux.HandleFunc("POST /api/v2/jobs/{jobId}/cancel", s.handleAction(func(id string) error {
+		return s.Cancel(context.Background(), id)
+	}))
+	mux.HandleFunc("POST /api/v2/jobs/{jobId}/pause", s.handleAction(s.Pause))
+	mux.HandleFunc("POST /api/v2/jobs/{jobId}/resume", s.handleAction(s.Resume))
+	return s.requireToken(mux)
+}
+
+func (s *StubPipeline) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.cfg.Token != "" && r.Header.Get("Authorization") != "Bearer "+s.cfg.Token {
+			writeStubJSON(w, http.StatusUnauthorized, dataGenJobError{Code: "UNAUTHORIZED", Message: "missing or invalid bearer token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *StubPipeline) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req dataGenCreateJobRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeStubJSON(w, http.StatusBadRequest, dataGenJobError{Code: "INVALID_REQUEST", Message: err.Error()})
+		return
+	}
+	var scenario StubScenario
+	if raw, ok := req.Parameters[stubScenarioKey]; ok {
+		encoded, _ := json.Marshal(raw)
+		if err := json.Unmarshal(encoded, &scenario); err != nil {
+			writeStubJSON(w, http.StatusBadRequest, dataGenJobError{Code: "INVALID_REQUEST", Message: err.Error()})
+			return
+		}
+	}
+	if scenario.Reject {
+		writeStubJSON(w, http.StatusAccepted, dataGenJobCreationResponse{Status: "rejected", Message: "rejected by stub scenario"})
+		return
+	}
+	id, err := s.submit(req.ProjectID, r.Header.Get("Idempotency-Key"), scenario)
+	if err != nil {
+		writeStubJSON(w, http.StatusInternalServerError, dataGenJobError{Code: "INTERNAL", Message: err.Error()})
+		return
+	}
+	writeStubJSON(w, http.StatusAccepted, dataGenJobCreationResponse{JobID: id, Status: "accepted"})
+}
+
+func (s *StubPipeline) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	j, ok := s.jobs[r.PathValue("jobId")]
+	if !ok {
+		s.mu.Unlock()
+		writeStubJSON(w, http.StatusNotFound, dataGenJobError{Code: "NOT_FOUND", Message: "job not found"})
+		return
+	}
+	status, progress, errMsg := s.observeLocked(j)
+	resp := dataGenJobStatusResponse{
+		JobID:       j.id,
+		ProjectID:   j.projectID,
+		Status:      status,
+		Progress:    progress,
+		LastUpdated: s.cfg.Now().UTC(),
+	}
+	if status != stubStatusQueued {
+		start := j.submittedAt.Add(s.cfg.QueueDelay).UTC()
+		resp.StartTime = &start
+	}
+	if j.final != "" {
+		end := j.finishedAt.UTC()
+		resp.EndTime = &end
+	}
+	if errMsg != "" {
+		resp.Error = &dataGenJobError{Code: "JOB_FAILED", Message: errMsg}
+	}
+	at := s.cfg.Now()
+	if j.final != "" {
+		at = j.finishedAt
+	}
+	_, _, current, _ := s.evaluate(j, j.activeTime(at))
+	for i, stage := range j.stages {
+		stageStatus := "pending"
+		switch {
+		case i < current:
+			stageStatus = stubStatusCompleted
+		case i == current && status == stubStatusFailed:
+			stageStatus = stubStatusFailed
+		case i == current:
+			stageStatus = status
+		}
+		resp.Stages = append(resp.Stages, dataGenJobStage{Name: stage.Name, Status: stageStatus})
+	}
+	s.mu.Unlock()
+	writeStubJSON(w, http.StatusOK, resp)
+}
+
+func (s *StubPipeline) handleLogs(w http.ResponseWriter, r *http.Request) {
+	since, err := strconv.ParseInt(r.URL.Query().Get("since"), 10, 64)
+	if err != nil && r.URL.Query().Has("since") {
+		writeStubJSON(w, http.StatusBadRequest, dataGenJobError{Code: "INVALID_REQUEST", Message: "since must be an integer"})
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	entries, err := s.GetLogs(r.Context(), r.PathValue("jobId"), since, limit)
+	if err != nil {
+		writeStubJSON(w, http.StatusNotFound, dataGenJobError{Code: "NOT_FOUND", Message: "job not found"})
+		return
+	}
+	resp := dataGenJobLogsResponse{JobID: r.PathValue("jobId"), Logs: make([]dataGenLogEntry, len(entries))}
+	for i, entry := range entries {
+		resp.Logs[i] = dataGenLogEntry(entry)
+	}
+	writeStubJSON(w, http.StatusOK, resp)
+}
+
+func (s *StubPipeline) handleAction(action func(id string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := action(r.PathValue("jobId"))
+		switch {
+		case errors.Is(err, ErrStubJobNotFound):
+			writeStubJSON(w, http.StatusNotFound, dataGenJobActionResponse{Message: err.Error()})
+		case err != nil:
+			writeStubJSON(w, http.StatusBadRequest, dataGenJobActionResponse{Message: err.Error()})
+		default:
+			writeStubJSON(w, http.StatusOK, dataGenJobActionResponse{Success: true})
+		}
+	}
+}
 
-	s.logger.Printf("[StubPipeline] Cannot cancel job %s, status is already %s (simulated).", pipelineJobID, currentStatus)
-	// Return an error indicating it cannot be cancelled in its current state
-	return fmt.Errorf("stub pipeline simulation: job %s cannot be cancelled in status %s", pipelineJobID, currentStatus)
+func writeStubJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
 }
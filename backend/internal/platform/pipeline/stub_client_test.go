@@ -0,0 +1,368 @@
+package pipeline
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeClock is a manually advanced clock for the simulator.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func setupStubTest(cfg StubConfig) (*StubPipeline, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	cfg.Now = clock.Now
+	cfg.Logger = log.New(io.Discard, "", 0)
+	if len(cfg.Stages) == 0 {
+		cfg.Stages = []StubStage{{Name: "validate", Duration: time.Second}, {Name: "generate", Duration: 3 * time.Second}}
+	}
+	return NewStubPipeline(cfg), clock
+}
+
+func requireStubStatus(t *testing.T, stub *StubPipeline, id string, want core.JobStatus) string {
+	t.Helper()
+	status, errMsg, err := stub.CheckStatus(context.Background(), id)
+	require.NoError(t, err)
+	require.Equal(t, want, status)
+	return errMsg
+}
+
+func TestStubPipeline_Timeline(t *testing.T) {
+	ctx := context.Background()
+	stub, clock := setupStubTest(StubConfig{QueueDelay: time.Second})
+
+	id, err := stub.Submit(ctx, `{}`, "csv", "proj-1")
+	require.NoError(t, err)
+
+	requireStubStatus(t, stub, id, core.JobStatusPending)
+	clock.Advance(1500 * time.Millisecond)
+	requireStubStatus(t, stub, id, core.JobStatusRunning)
+	clock.Advance(3 * time.Second)
+	requireStubStatus(t, stub, id, core.JobStatusRunning)
+	clock.Advance(500 * time.Millisecond)
+	requireStubStatus(t, stub, id, core.JobStatusCompleted)
+	clock.Advance(time.Hour)
+	requireStubStatus(t, stub, id, core.JobStatusCompleted)
+
+	resultURI, err := stub.ResultURI(ctx, id)
+	require.NoError(t, err)
+	assert.Empty(t, resultURI)
+	_, _, err = stub.CheckStatus(ctx, "missing")
+	assert.ErrorIs(t, err, ErrStubJobNotFound)
+}
+
+func TestStubPipeline_ScriptedFailure(t *testing.T) {
+	ctx := context.Background()
+	stub, clock := setupStubTest(StubConfig{})
+
+	id, err := stub.Submit(ctx, `{"stubScenario":{"fail":true,"failStage":"validate","error":"schema rejected"}}`, "csv", "proj-1")
+	require.NoError(t, err)
+
+	clock.Advance(400 * time.Millisecond)
+	requireStubStatus(t, stub, id, core.JobStatusRunning)
+	clock.Advance(100 * time.Millisecond) // Halfway through validate
+	assert.Equal(t, "schema rejected", requireStubStatus(t, stub, id, core.JobStatusFailed))
+
+	_, err = stub.Submit(ctx, `{"stubScenario":{"reject":true}}`, "csv", "proj-1")
+	assert.ErrorContains(t, err, "rejected")
+	_, err = stub.Submit(ctx, `{"stubScenario":"fail"}`, "csv", "proj-1")
+	assert.ErrorIs(t, err, ErrStubScenarioFormat)
+}
+
+func TestStubPipeline_SeededFailureInjection(t *testing.T) {
+	ctx := context.Background()
+	outcomes := func(seed uint64) []core.JobStatus {
+		stub, clock := setupStubTest(StubConfig{FailureRate: 0.5, Seed: seed})
+		ids := make([]string, 20)
+		for i := range ids {
+			id, err := stub.Submit(ctx, `{}`, "csv", "proj-1")
+			require.NoError(t, err)
+			ids[i] = id
+		}
+		clock.Advance(time.Minute)
+		statuses := make([]core.JobStatus, len(ids))
+		for i, id := range ids {
+			statuses[i], _, _ = stub.CheckStatus(ctx, id)
+		}
+		return statuses
+	}
+
+	first := outcomes(42)
+	assert.Equal(t, first, outcomes(42), "the same seed fails the same jobs")
+	assert.Contains(t, first, core.JobStatusFailed)
+	assert.Contains(t, first, core.JobStatusCompleted)
+}
+
+func TestStubPipeline_PauseResume(t *testing.T) {
+	ctx := context.Background()
+	stub, clock := setupStubTest(StubConfig{})
+	id, err := stub.Submit(ctx, `{}`, "csv", "proj-1")
+	require.NoError(t, err)
+
+	clock.Advance(2 * time.Second)
+	require.NoError(t, stub.Pause(id))
+	clock.Advance(time.Hour)
+	requireStubStatus(t, stub, id, core.JobStatusRunning) // Paused maps to running
+	assert.ErrorIs(t, stub.Pause(id), ErrStubInvalidAction)
+
+	require.NoError(t, stub.Resume(id))
+	clock.Advance(1900 * time.Millisecond)
+	requireStubStatus(t, stub, id, core.JobStatusRunning)
+	clock.Advance(100 * time.Millisecond)
+	requireStubStatus(t, stub, id, core.JobStatusCompleted)
+	assert.ErrorIs(t, stub.Resume(id), ErrStubInvalidAction)
+}
+
+func TestStubPipeline_Cancel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Immediate", func(t *testing.T) {
+		stub, clock := setupStubTest(StubConfig{})
+		id, _ := stub.Submit(ctx, `{}`, "csv", "proj-1")
+		clock.Advance(time.Second)
+
+		require.NoError(t, stub.Cancel(ctx, id))
+		requireStubStatus(t, stub, id, core.JobStatusCancelled)
+		clock.Advance(time.Hour)
+		requireStubStatus(t, stub, id, core.JobStatusCancelled)
+		assert.ErrorIs(t, stub.Cancel(ctx, id), ErrStubInvalidAction)
+	})
+
+	t.Run("JobFinishesBeforeCancelLands", func(t *testing.T) {
+		stub, clock := setupStubTest(StubConfig{CancelDelay: time.Second})
+		id, _ := stub.Submit(ctx, `{}`, "csv", "proj-1")
+		clock.Advance(3500 * time.Millisecond)
+
+		require.NoError(t, stub.Cancel(ctx, id))
+		requireStubStatus(t, stub, id, core.JobStatusRunning)
+		clock.Advance(time.Second)
+		requireStubStatus(t, stub, id, core.JobStatusCompleted)
+	})
+
+	t.Run("CancelLandsFirst", func(t *testing.T) {
+		stub, clock := setupStubTest(StubConfig{CancelDelay: time.Second})
+		id, _ := stub.Submit(ctx, `{}`, "csv", "proj-1")
+		clock.Advance(time.Second)
+
+		require.NoError(t, stub.Cancel(ctx, id))
+		require.NoError(t, stub.Cancel(ctx, id), "repeated requests are accepted while cancelling")
+		assert.ErrorIs(t, stub.Pause(id), ErrStubInvalidAction)
+		clock.Advance(time.Hour)
+		requireStubStatus(t, stub, id, core.JobStatusCancelled)
+	})
+}
+
+func TestStubPipeline_Logs(t *testing.T) {
+	ctx := context.Background()
+	messages := func(entries []job.LogEntry) []string {
+		var out []string
+		for _, entry := range entries {
+			out = append(out, entry.Message)
+		}
+		return out
+	}
+
+	t.Run("FollowsTimelineAcrossPause", func(t *testing.T) {
+		stub, clock := setupStubTest(StubConfig{QueueDelay: time.Second})
+		start := clock.Now()
+		id, _ := stub.Submit(ctx, `{}`, "csv", "proj-1")
+
+		clock.Advance(1500 * time.Millisecond)
+		logs, err := stub.GetLogs(ctx, id, 0, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Job accepted with 2 stages", "Stage validate started"}, messages(logs))
+
+		require.NoError(t, stub.Pause(id))
+		clock.Advance(time.Minute)
+		require.NoError(t, stub.Resume(id))
+		clock.Advance(time.Hour)
+		logs, err = stub.GetLogs(ctx, id, 2, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Job paused", "Job resumed", "Stage validate completed", "Stage generate started", "Stage generate completed", "Job completed"}, messages(logs))
+		assert.Equal(t, int64(3), logs[0].Sequence)
+		assert.Equal(t, "validate", logs[2].Stage)
+		assert.Equal(t, start.Add(2*time.Second+time.Minute), logs[2].Timestamp, "stage times account for the pause")
+
+		page, _ := stub.GetLogs(ctx, id, 3, 2)
+		assert.Equal(t, []string{"Job resumed", "Stage validate completed"}, messages(page))
+		caughtUp, _ := stub.GetLogs(ctx, id, 8, 0)
+		assert.Empty(t, caughtUp)
+	})
+
+	t.Run("ScriptedFailure", func(t *testing.T) {
+		stub, clock := setupStubTest(StubConfig{})
+		id, _ := stub.Submit(ctx, `{"stubScenario":{"fail":true,"failStage":"generate","error":"disk full"}}`, "csv", "proj-1")
+		clock.Advance(time.Hour)
+
+		logs, _ := stub.GetLogs(ctx, id, 0, 0)
+
+		last := logs[len(logs)-1]
+		assert.Equal(t, job.LogLevelError, last.Level)
+		assert.Equal(t, "Stage generate failed: disk full", last.Message)
+	})
+
+	t.Run("CancelledWithDelay", func(t *testing.T) {
+		stub, clock := setupStubTest(StubConfig{CancelDelay: 2 * time.Second})
+		id, _ := stub.Submit(ctx, `{}`, "csv", "proj-1")
+		clock.Advance(500 * time.Millisecond)
+		require.NoError(t, stub.Cancel(ctx, id))
+		clock.Advance(time.Hour)
+
+		logs, _ := stub.GetLogs(ctx, id, 0, 0)
+
+		assert.Equal(t, []string{"Job accepted with 2 stages", "Stage validate started", "Cancellation requested", "Stage validate completed", "Stage generate started", "Job cancelled"}, messages(logs))
+		for i := 1; i < len(logs); i++ {
+			assert.False(t, logs[i].Timestamp.Before(logs[i-1].Timestamp), "entries are in time order")
+		}
+	})
+
+	t.Run("UnknownJob", func(t *testing.T) {
+		stub, _ := setupStubTest(StubConfig{})
+		_, err := stub.GetLogs(ctx, "missing", 0, 0)
+		assert.ErrorIs(t, err, ErrStubJobNotFound)
+	})
+}
+
+func TestStubPipeline_ConcurrentUse(t *testing.T) {
+	ctx := context.Background()
+	stub := NewStubPipeline(StubConfig{Logger: log.New(io.Discard, "", 0), Stages: []StubStage{{Name: "generate", Duration: time.Millisecond}}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := stub.Submit(ctx, `{}`, "csv", "proj-1")
+			assert.NoError(t, err)
+			for n := 0; n < 20; n++ {
+				_, _, err := stub.CheckStatus(ctx, id)
+				assert.NoError(t, err)
+			}
+			stub.Cancel(ctx, id)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStubPipeline_DataGenAPI(t *testing.T) {
+	ctx := context.Background()
+	stub, clock := setupStubTest(StubConfig{})
+	server := httptest.NewServer(stub.Handler())
+	defer server.Close()
+	client, err := NewDataGenPipelineClient(Config{BaseURL: server.URL, Logger: zap.NewNop()})
+	require.NoError(t, err)
+
+	// Submit, observe and finish through the DataGen client
+	id, err := client.Submit(ctx, `{"recordCount":10}`, "csv", "proj-1")
+	require.NoError(t, err)
+	status, _, err := client.CheckStatus(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, core.JobStatusRunning, status)
+	progress, err := client.(job.ProgressReporter).Progress(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, 0, progress)
+	clock.Advance(time.Minute)
+	status, _, err = client.CheckStatus(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, core.JobStatusCompleted, status)
+	progress, err = client.(job.ProgressReporter).Progress(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, 100, progress)
+	assert.Error(t, client.Cancel(ctx, id), "completed jobs cannot be cancelled")
+
+	// Scripted failures carry their message
+	failing, err := client.Submit(ctx, `{"stubScenario":{"fail":true,"error":"disk full"}}`, "csv", "proj-1")
+	require.NoError(t, err)
+	clock.Advance(time.Minute)
+	status, errMsg, err := client.CheckStatus(ctx, failing)
+	require.NoError(t, err)
+	assert.Equal(t, core.JobStatusFailed, status)
+	assert.Equal(t, "disk full", errMsg)
+
+	// Rejections and cancellation
+	_, err = client.Submit(ctx, `{"stubScenario":{"reject":true}}`, "csv", "proj-1")
+	assert.ErrorContains(t, err, "rejected")
+	running, err := client.Submit(ctx, `{}`, "csv", "proj-1")
+	require.NoError(t, err)
+	require.NoError(t, client.Cancel(ctx, running))
+	status, _, err = client.CheckStatus(ctx, running)
+	require.NoError(t, err)
+	assert.Equal(t, core.JobStatusCancelled, status)
+	_, _, err = client.CheckStatus(ctx, "missing")
+	assert.ErrorContains(t, err, "404")
+
+	// Logs page through the DataGen logs endpoint
+	logs, err := client.GetLogs(ctx, running, 0, 2)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "Job accepted with 2 stages", logs[0].Message)
+	logs, err = client.GetLogs(ctx, running, 2, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Cancellation requested", "Job cancelled"}, []string{logs[0].Message, logs[1].Message})
+	_, err = client.GetLogs(ctx, "missing", 0, 10)
+	assert.ErrorContains(t, err, "404")
+
+	// Raw status documents report stages and progress ticks
+	paused, _ := stub.Submit(ctx, `{}`, "csv", "proj-1")
+	clock.Advance(2 * time.Second)
+	resp, err := http.Post(server.URL+"/api/v2/jobs/"+paused+"/pause", "application/json", nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp, err = http.Get(server.URL + "/api/v2/jobs/" + paused)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var doc dataGenJobStatusResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	assert.Equal(t, "paused", doc.Status)
+	assert.Equal(t, 50, doc.Progress)
+	progress, err = stub.Progress(ctx, paused)
+	require.NoError(t, err)
+	assert.Equal(t, 50, progress)
+	assert.Equal(t, []dataGenJobStage{{Name: "validate", Status: "completed"}, {Name: "generate", Status: "paused"}}, doc.Stages)
+}
+
+func TestStubPipeline_DataGenAPIRequiresToken(t *testing.T) {
+	stub, _ := setupStubTest(StubConfig{Token: "secret"})
+	server := httptest.NewServer(stub.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/v2/jobs/any")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/v2/jobs/any", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
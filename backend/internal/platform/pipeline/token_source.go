@@ -0,0 +1,151 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"google.golang.org/api/idtoken"
+	"google.golang.org/api/option"
+)
+
+// DefaultTokenRefreshBefore is how long before expiry a cached token is replaced.
+const DefaultTokenRefreshBefore = time.Minute
+
+// TokenSource supplies bearer tokens for the DataGen API.
+type TokenSource interface {
+	// Token returns a valid access token, fetching a new one when the cached token is
+	// missing or about to expire.
+	Token(ctx context.Context) (string, error)
+
+	// Invalidate discards the cached token, e.g. after the API rejected it.
+	Invalidate()
+}
+
+// NewStaticTokenSource returns a token source that always returns token.
+func NewStaticTokenSource(token string) TokenSource {
+	return staticTokenSource(token)
+}
+
+type staticTokenSource string
+
+func (s staticTokenSource) Token(ctx context.Context) (string, error) {
+	if s == "" {
+		return "", errors.New("static token is empty")
+	}
+	return string(s), nil
+}
+
+func (s staticTokenSource) Invalidate() {}
+
+// ClientCredentialsConfig configures an OAuth2 client-credentials grant.
+type ClientCredentialsConfig struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Audience     string        // Sent as the "audience" parameter when set
+	RefreshAfter time.Duration // Defaults to DefaultTokenRefreshBefore before expiry
+}
+
+// NewClientCredentialsTokenSource returns a cached token source using the OAuth2
+// client-credentials grant.
+func NewClientCredentialsTokenSource(cfg ClientCredentialsConfig) (TokenSource, error) {
+	if cfg.TokenURL == "" || cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("client credentials require a token URL, client ID and client secret")
+	}
+	cc := &clientcredentials.Config{
+		ClientID:     cfg.ClientID,
+		ClientSecret: cfg.ClientSecret,
+		TokenURL:     cfg.TokenURL,
+		Scopes:       cfg.Scopes,
+	}
+	if cfg.Audience != "" {
+		cc.EndpointParams = map[string][]string{"audience": {cfg.Audience}}
+	}
+	return newCachedTokenSource(cc.Token, cfg.RefreshAfter), nil
+}
+
+// NewGCPIDTokenSource returns a cached token source of Google-signed ID tokens for
+// audience, from a service account key file or, without one, Application Default
+// Credentials (e.g. the metadata server on Cloud Run or GKE).
+func NewGCPIDTokenSource(ctx context.Context, audience, credentialsFile string) (TokenSource, error) {
+	if audience == "" {
+		return nil, fmt.Errorf("GCP ID tokens require an audience")
+	}
+	var opts []idtoken.ClientOption
+	if credentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(credentialsFile))
+	}
+	// idtoken sources fetch a token when created and reuse it until it expires, so each
+	// fetch creates a new one; otherwise Invalidate and the refresh margin would get the
+	// same token back. Creating one up front checks the credentials.
+	if _, err := idtoken.NewTokenSource(ctx, audience, opts...); err != nil {
+		return nil, fmt.Errorf("failed to create ID token source: %w", err)
+	}
+	return newCachedTokenSource(func(ctx context.Context) (*oauth2.Token, error) {
+		ts, err := idtoken.NewTokenSource(ctx, audience, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return ts.Token()
+	}, 0), nil
+}
+
+// cachedTokenSource reuses a fetched token until refreshBefore its expiry. Tokens
+// without an expiry are reused until invalidated.
+type cachedTokenSource struct {
+	fetch         func(ctx context.Context) (*oauth2.Token, error)
+	refreshBefore time.Duration
+	now           func() time.Time
+
+	mu    sync.Mutex
+	token *oauth2.Token
+}
+
+func newCachedTokenSource(fetch func(ctx context.Context) (*oauth2.Token, error), refreshBefore time.Duration) *cachedTokenSource {
+	if refreshBefore <= 0 {
+		refreshBefore = DefaultTokenRefreshBefore
+	}
+	return &cachedTokenSource{fetch: fetch, refreshBefore: refreshBefore, now: time.Now}
+}
+
+func (c *cachedTokenSource) Token(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token != nil && (c.token.Expiry.IsZero() || c.now().Add(c.refreshBefore).Before(c.token.Expiry)) {
+		return c.token.AccessToken, nil
+	}
+	token, err := c.fetch(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch access token: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", errors.New("token endpoint returned an empty access token")
+	}
+	c.token = token
+	return token.AccessToken, nil
+}
+
+func (c *cachedTokenSource) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = nil
+}
+
+// isTransientTokenError reports whether a token fetch failed because the token endpoint
+// was unreachable or failing, rather than because it rejected the credentials.
+func isTransientTokenError(err error) bool {
+	var retrieveErr *oauth2.RetrieveError
+	if errors.As(err, &retrieveErr) {
+		return retrieveErr.Response != nil && retrieveErr.Response.StatusCode >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
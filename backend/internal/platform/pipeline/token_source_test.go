@@ -0,0 +1,268 @@
+package pipeline
+
+import (
+	"SynDataGen/backend/internal/job"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// tokenServer stands in for an OAuth2 token endpoint, issuing "token-1", "token-2", ...
+type tokenServer struct {
+	*httptest.Server
+	issued    atomic.Int32
+	expiresIn int
+}
+
+func newTokenServer(t *testing.T, expiresIn int) *tokenServer {
+	ts := &tokenServer{expiresIn: expiresIn}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if !ok || clientID != "client" || secret != "secret" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := ts.issued.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": fmt.Sprintf("token-%d", n),
+			"token_type":   "Bearer",
+			"expires_in":   ts.expiresIn,
+			"scope":        r.FormValue("scope"),
+		})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestClientCredentialsTokenSource(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_CachesUntilRefreshWindow", func(t *testing.T) {
+		server := newTokenServer(t, 3600)
+		source, err := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: server.URL, ClientID: "client", ClientSecret: "secret", Scopes: []string{"jobs"}})
+		require.NoError(t, err)
+		cached := source.(*cachedTokenSource)
+		now := time.Now()
+		cached.now = func() time.Time { return now }
+
+		token, err := source.Token(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, "token-1", token)
+		token, _ = source.Token(ctx)
+		assert.Equal(t, "token-1", token, "a fresh token is reused")
+
+		now = now.Add(time.Hour - DefaultTokenRefreshBefore + time.Second)
+		token, _ = source.Token(ctx)
+		assert.Equal(t, "token-2", token, "tokens are replaced before they expire")
+		assert.Equal(t, int32(2), server.issued.Load())
+	})
+
+	t.Run("Success_Invalidate", func(t *testing.T) {
+		server := newTokenServer(t, 3600)
+		source, _ := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: server.URL, ClientID: "client", ClientSecret: "secret"})
+
+		source.Token(ctx)
+		source.Invalidate()
+		token, err := source.Token(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "token-2", token)
+	})
+
+	t.Run("Failure_BadCredentials", func(t *testing.T) {
+		server := newTokenServer(t, 3600)
+		source, _ := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: server.URL, ClientID: "client", ClientSecret: "wrong"})
+
+		_, err := source.Token(ctx)
+
+		assert.ErrorContains(t, err, "failed to fetch access token")
+	})
+
+	t.Run("Failure_MissingConfig", func(t *testing.T) {
+		_, err := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: "http://token"})
+		assert.Error(t, err)
+		_, err = NewGCPIDTokenSource(ctx, "", "")
+		assert.Error(t, err)
+	})
+}
+
+// idTokenServer stands in for Google's token endpoint, exchanging service account
+// assertions for ID tokens that expire in an hour.
+type idTokenServer struct {
+	*httptest.Server
+	issued atomic.Int32
+}
+
+func newIDTokenServer(t *testing.T) *idTokenServer {
+	ts := &idTokenServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.FormValue("assertion") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n := ts.issued.Add(1)
+		claims, _ := json.Marshal(map[string]interface{}{"aud": "https://datagen", "exp": time.Now().Add(time.Hour).Unix(), "n": n})
+		encode := base64.RawURLEncoding.EncodeToString
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"id_token": encode([]byte(`{"alg":"RS256"}`)) + "." + encode(claims) + ".sig"})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// writeServiceAccountKey writes a service account key file whose tokens come from tokenURL.
+func writeServiceAccountKey(t *testing.T, tokenURL string) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	data, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "datagen@example.iam.gserviceaccount.com",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":      tokenURL,
+	})
+	path := filepath.Join(t.TempDir(), "key.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestGCPIDTokenSource(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_InvalidateFetchesNewToken", func(t *testing.T) {
+		server := newIDTokenServer(t)
+		source, err := NewGCPIDTokenSource(ctx, "https://datagen", writeServiceAccountKey(t, server.URL))
+		require.NoError(t, err)
+
+		first, err := source.Token(ctx)
+		require.NoError(t, err)
+		again, _ := source.Token(ctx)
+		assert.Equal(t, first, again, "a fresh token is reused")
+		issued := server.issued.Load()
+
+		source.Invalidate()
+		second, err := source.Token(ctx)
+
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+		assert.Equal(t, issued+1, server.issued.Load())
+	})
+}
+
+func TestDataGenPipelineClient_Authentication(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_SendsBearerToken", func(t *testing.T) {
+		stub := NewStubPipeline(StubConfig{Token: "static-secret", Logger: log.New(io.Discard, "", 0)})
+		server := httptest.NewServer(stub.Handler())
+		defer server.Close()
+		client, err := NewDataGenPipelineClient(Config{BaseURL: server.URL, Logger: zap.NewNop(), TokenSource: NewStaticTokenSource("static-secret")})
+		require.NoError(t, err)
+
+		id, err := client.Submit(ctx, `{}`, "csv", "proj-1")
+		require.NoError(t, err)
+		_, _, err = client.CheckStatus(ctx, id)
+		assert.NoError(t, err)
+		assert.NoError(t, client.Cancel(ctx, id))
+	})
+
+	t.Run("Success_RetriesOnceOn401WithFreshToken", func(t *testing.T) {
+		tokens := newTokenServer(t, 3600)
+		stub := NewStubPipeline(StubConfig{Token: "token-2", Logger: log.New(io.Discard, "", 0)}) // The first token is already revoked
+		server := httptest.NewServer(stub.Handler())
+		defer server.Close()
+		source, _ := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"})
+		client, _ := NewDataGenPipelineClient(Config{BaseURL: server.URL, Logger: zap.NewNop(), TokenSource: source})
+
+		id, err := client.Submit(ctx, `{}`, "csv", "proj-1")
+		require.NoError(t, err)
+		_, _, err = client.CheckStatus(ctx, id)
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(2), tokens.issued.Load(), "the refreshed token is cached for later requests")
+	})
+
+	t.Run("Failure_RejectedAfterRetry", func(t *testing.T) {
+		tokens := newTokenServer(t, 3600)
+		stub := NewStubPipeline(StubConfig{Token: "never-issued", Logger: log.New(io.Discard, "", 0)})
+		server := httptest.NewServer(stub.Handler())
+		defer server.Close()
+		source, _ := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"})
+		client, _ := NewDataGenPipelineClient(Config{BaseURL: server.URL, Logger: zap.NewNop(), TokenSource: source})
+
+		_, _, err := client.CheckStatus(ctx, "any")
+
+		assert.ErrorContains(t, err, "401")
+		assert.Equal(t, int32(2), tokens.issued.Load(), "only one retry is made")
+	})
+
+	t.Run("Failure_TokenUnavailable", func(t *testing.T) {
+		client, _ := NewDataGenPipelineClient(Config{BaseURL: "http://127.0.0.1:0", Logger: zap.NewNop(), TokenSource: NewStaticTokenSource("")})
+
+		err := client.Cancel(ctx, "any")
+
+		assert.ErrorContains(t, err, "authentication failed")
+		assert.NotErrorIs(t, err, job.ErrPipelineUnavailable)
+	})
+
+	t.Run("Failure_TokenEndpointOutageIsRetried", func(t *testing.T) {
+		var fetches atomic.Int32
+		tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fetches.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer tokens.Close()
+		source, _ := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"})
+		client := newResilientTestClient(t, "http://127.0.0.1:0", CircuitBreakerConfig{})
+		client.tokenSource = source
+
+		_, _, err := client.CheckStatus(ctx, "any")
+
+		assert.ErrorIs(t, err, job.ErrPipelineUnavailable)
+		assert.Greater(t, fetches.Load(), int32(1), "the token fetch is retried")
+	})
+
+	t.Run("Failure_TokenEndpointUnreachable", func(t *testing.T) {
+		tokens := httptest.NewServer(http.NotFoundHandler())
+		tokens.Close()
+		source, _ := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "secret"})
+		client, _ := NewDataGenPipelineClient(Config{BaseURL: "http://127.0.0.1:0", Logger: zap.NewNop(), TokenSource: source})
+
+		err := client.Cancel(ctx, "any")
+
+		assert.ErrorIs(t, err, job.ErrPipelineUnavailable)
+	})
+
+	t.Run("Failure_RejectedCredentialsArePermanent", func(t *testing.T) {
+		tokens := newTokenServer(t, 3600)
+		source, _ := NewClientCredentialsTokenSource(ClientCredentialsConfig{TokenURL: tokens.URL, ClientID: "client", ClientSecret: "wrong"})
+		client := newResilientTestClient(t, "http://127.0.0.1:0", CircuitBreakerConfig{})
+		client.tokenSource = source
+
+		_, _, err := client.CheckStatus(ctx, "any")
+
+		assert.ErrorContains(t, err, "authentication failed")
+		assert.NotErrorIs(t, err, job.ErrPipelineUnavailable)
+	})
+}
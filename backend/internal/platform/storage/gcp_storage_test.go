@@ -6,11 +6,16 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net/http"
+	"strings"
 	"testing"
 
+	"SynDataGen/backend/internal/core"
+
 	"cloud.google.com/go/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/iterator"
 	// Adjust if your path differs
 )
@@ -22,13 +27,13 @@ type MockStorageClient struct {
 	mock.Mock
 }
 
-func (m *MockStorageClient) Bucket(name string) *storage.BucketHandle {
+func (m *MockStorageClient) Bucket(name string) gcpBucketHandle {
 	args := m.Called(name)
 	// Return nil if the mocked return value isn't set or isn't the correct type
 	if args.Get(0) == nil {
 		return nil
 	}
-	return args.Get(0).(*storage.BucketHandle)
+	return args.Get(0).(gcpBucketHandle)
 }
 
 // Close is required by the interface implicitly used in gcpStorageService.Close()
@@ -64,20 +69,95 @@ func (m *MockBucketHandle) Delete(ctx context.Context) error {
 	return args.Error(0)
 }
 
-func (m *MockBucketHandle) Objects(ctx context.Context, q *storage.Query) *storage.ObjectIterator {
+func (m *MockBucketHandle) Update(ctx context.Context, uattrs storage.BucketAttrsToUpdate) (*storage.BucketAttrs, error) {
+	args := m.Called(ctx, uattrs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*storage.BucketAttrs), args.Error(1)
+}
+
+func (m *MockBucketHandle) Objects(ctx context.Context, q *storage.Query) gcpObjectIterator {
 	args := m.Called(ctx, q)
 	if args.Get(0) == nil {
-		return nil
+		// Like the real iterator, listing errors surface on the first Next call.
+		return &errObjectIterator{err: args.Error(1)}
 	}
-	return args.Get(0).(*storage.ObjectIterator)
+	return args.Get(0).(gcpObjectIterator)
 }
 
-func (m *MockBucketHandle) Object(name string) *storage.ObjectHandle {
+func (m *MockBucketHandle) Object(name string) gcpObjectHandle {
 	args := m.Called(name)
 	if args.Get(0) == nil {
 		return nil
 	}
-	return args.Get(0).(*storage.ObjectHandle)
+	return args.Get(0).(gcpObjectHandle)
+}
+
+// errObjectIterator is an iterator whose Next always fails with err.
+type errObjectIterator struct {
+	err error
+}
+
+func (it *errObjectIterator) Next() (*storage.ObjectAttrs, error) {
+	return nil, it.err
+}
+
+func (it *errObjectIterator) PageInfo() *iterator.PageInfo {
+	pi, _ := iterator.NewPageInfo(
+		func(int, string) (string, error) { return "", it.err },
+		func() int { return 0 },
+		func() interface{} { return []*storage.ObjectAttrs(nil) },
+	)
+	return pi
+}
+
+// pagedObjectIterator serves fixed pages of results keyed by page token, like the GCS
+// list API. The first page has token "" and page i+1 has token "page-<i+1>".
+type pagedObjectIterator struct {
+	pages     [][]*storage.ObjectAttrs
+	buf       []*storage.ObjectAttrs
+	pageInfo  *iterator.PageInfo
+	nextFunc  func() error
+	gotTokens []string // Tokens requested, for assertions
+}
+
+func newPagedObjectIterator(pages ...[]*storage.ObjectAttrs) *pagedObjectIterator {
+	it := &pagedObjectIterator{pages: pages}
+	it.pageInfo, it.nextFunc = iterator.NewPageInfo(it.fetch, func() int { return len(it.buf) }, func() interface{} {
+		b := it.buf
+		it.buf = nil
+		return b
+	})
+	return it
+}
+
+func (it *pagedObjectIterator) fetch(pageSize int, pageToken string) (string, error) {
+	it.gotTokens = append(it.gotTokens, pageToken)
+	index := 0
+	if pageToken != "" {
+		if _, err := fmt.Sscanf(pageToken, "page-%d", &index); err != nil {
+			return "", err
+		}
+	}
+	it.buf = append(it.buf, it.pages[index]...)
+	if index+1 < len(it.pages) {
+		return fmt.Sprintf("page-%d", index+1), nil
+	}
+	return "", nil
+}
+
+func (it *pagedObjectIterator) Next() (*storage.ObjectAttrs, error) {
+	if err := it.nextFunc(); err != nil {
+		return nil, err
+	}
+	item := it.buf[0]
+	it.buf = it.buf[1:]
+	return item, nil
+}
+
+func (it *pagedObjectIterator) PageInfo() *iterator.PageInfo {
+	return it.pageInfo
 }
 
 // MockObjectIterator simulates storage.ObjectIterator behavior
@@ -88,11 +168,15 @@ type MockObjectIterator struct {
 	cursor  int
 }
 
-func (m *MockObjectIterator) Next() (*storage.ObjectAttrs, error) {
-	args := m.Called() // Allow mocking explicit Next calls if needed for complex scenarios
+// PageInfo is unused by the Next-based tests that rely on MockObjectIterator.
+func (m *MockObjectIterator) PageInfo() *iterator.PageInfo {
+	return nil
+}
 
+func (m *MockObjectIterator) Next() (*storage.ObjectAttrs, error) {
 	// If explicit mock expectations are set for Next(), use them
-	if len(args) > 0 {
+	if len(m.ExpectedCalls) > 0 {
+		args := m.Called()
 		retObj := args.Get(0)
 		retErr := args.Error(1)
 		if retObj == nil {
@@ -120,6 +204,64 @@ func (m *MockObjectHandle) Delete(ctx context.Context) error {
 	return args.Error(0)
 }
 
+func (m *MockObjectHandle) Attrs(ctx context.Context) (*storage.ObjectAttrs, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*storage.ObjectAttrs), args.Error(1)
+}
+
+func (m *MockObjectHandle) Update(ctx context.Context, uattrs storage.ObjectAttrsToUpdate) (*storage.ObjectAttrs, error) {
+	args := m.Called(ctx, uattrs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*storage.ObjectAttrs), args.Error(1)
+}
+
+func (m *MockObjectHandle) CopyFrom(ctx context.Context, src gcpObjectHandle) (*storage.ObjectAttrs, error) {
+	args := m.Called(ctx, src)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*storage.ObjectAttrs), args.Error(1)
+}
+
+func (m *MockObjectHandle) NewReader(ctx context.Context) (io.ReadCloser, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockObjectHandle) NewRangeReader(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	args := m.Called(ctx, offset, length)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockObjectHandle) NewWriter(ctx context.Context) io.WriteCloser {
+	args := m.Called(ctx)
+	return args.Get(0).(io.WriteCloser)
+}
+
+func (m *MockObjectHandle) IfNotExists() gcpObjectHandle {
+	args := m.Called()
+	return args.Get(0).(gcpObjectHandle)
+}
+
+// bufferWriter collects written bytes and returns closeErr when closed.
+type bufferWriter struct {
+	strings.Builder
+	closeErr error
+}
+
+func (w *bufferWriter) Close() error { return w.closeErr }
+
 // --- Test Functions ---
 
 func TestGCPStorageService_CreateProjectBucket_Success(t *testing.T) {
@@ -150,7 +292,7 @@ func TestGCPStorageService_CreateProjectBucket_Success(t *testing.T) {
 	})).Return(nil) // Success
 
 	// 3. Expect bucket.Attrs() to be called after creation
-	mockBucketHandle.On("Attrs", mock.AnythingOfType("*context.cancelCtx")).Return(&storage.BucketAttrs{
+	mockBucketHandle.On("Attrs", mock.Anything).Return(&storage.BucketAttrs{
 		Name:     expectedBucketName,
 		Location: expectedLocation, // Simulate GCP returning uppercase
 	}, nil) // Success
@@ -201,7 +343,7 @@ func TestGCPStorageService_CreateProjectBucket_Success_DefaultRegion(t *testing.
 	})).Return(nil) // Success
 
 	// 3. Expect bucket.Attrs
-	mockBucketHandle.On("Attrs", mock.AnythingOfType("*context.cancelCtx")).Return(&storage.BucketAttrs{
+	mockBucketHandle.On("Attrs", mock.Anything).Return(&storage.BucketAttrs{
 		Name:     expectedBucketName,
 		Location: expectedLocation,
 	}, nil) // Success
@@ -287,7 +429,7 @@ func TestGCPStorageService_CreateProjectBucket_AttrsError(t *testing.T) {
 	mockBucketHandle.On("Create", mock.AnythingOfType("*context.timerCtx"), projectID, mock.AnythingOfType("*storage.BucketAttrs")).Return(nil)
 
 	// 3. Expect bucket.Attrs to fail
-	mockBucketHandle.On("Attrs", mock.AnythingOfType("*context.cancelCtx")).Return(nil, mockError)
+	mockBucketHandle.On("Attrs", mock.Anything).Return(nil, mockError)
 
 	logger := log.New(io.Discard, "", 0)
 	service := &gcpStorageService{
@@ -596,3 +738,401 @@ func TestGCPStorageService_Close(t *testing.T) {
 // TODO: Add more test cases:
 // - TestGCPStorageService_DeleteProjectBucket_Error_BucketNotFound (Maybe, requires checking error type)
 // - TestNewGCPStorageService (More complex, might need env vars or skip)
+
+func TestGCPStorageService_SetBucketRetention(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "retention-bucket"
+
+	t.Run("Success_SetsDeleteRule", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Update", mock.Anything, mock.MatchedBy(func(u storage.BucketAttrsToUpdate) bool {
+			return u.Lifecycle != nil &&
+				len(u.Lifecycle.Rules) == 1 &&
+				u.Lifecycle.Rules[0].Action.Type == storage.DeleteAction &&
+				u.Lifecycle.Rules[0].Condition.AgeInDays == 30
+		})).Return(&storage.BucketAttrs{}, nil)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		err := service.SetBucketRetention(ctx, bucketName, 30)
+
+		assert.NoError(t, err)
+		mockBucketHandle.AssertExpectations(t)
+	})
+
+	t.Run("Success_ZeroClearsRules", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Update", mock.Anything, mock.MatchedBy(func(u storage.BucketAttrsToUpdate) bool {
+			return u.Lifecycle != nil && len(u.Lifecycle.Rules) == 0
+		})).Return(&storage.BucketAttrs{}, nil)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		err := service.SetBucketRetention(ctx, bucketName, 0)
+
+		assert.NoError(t, err)
+		mockBucketHandle.AssertExpectations(t)
+	})
+
+	t.Run("Error_Update", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockError := errors.New("permission denied")
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Update", mock.Anything, mock.Anything).Return(nil, mockError)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		err := service.SetBucketRetention(ctx, bucketName, 7)
+
+		assert.ErrorIs(t, err, mockError)
+	})
+}
+
+func TestGCPStorageService_DeleteObject(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "delete-bucket"
+	objectName := "data.csv"
+
+	t.Run("Success", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockObjectHandle := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+		mockObjectHandle.On("Delete", mock.Anything).Return(nil)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		err := service.DeleteObject(ctx, bucketName, objectName)
+
+		assert.NoError(t, err)
+		mockObjectHandle.AssertExpectations(t)
+	})
+
+	t.Run("Error_NotFoundMapsToCoreError", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockObjectHandle := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+		mockObjectHandle.On("Delete", mock.Anything).Return(storage.ErrObjectNotExist)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		err := service.DeleteObject(ctx, bucketName, objectName)
+
+		assert.ErrorIs(t, err, core.ErrNotFound)
+	})
+}
+
+func TestGCPStorageService_UploadNewFile(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "upload-bucket"
+	objectName := "data.csv"
+
+	t.Run("Success", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockObjectHandle := new(MockObjectHandle)
+		conditional := new(MockObjectHandle)
+		writer := &bufferWriter{}
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+		mockObjectHandle.On("IfNotExists").Return(conditional)
+		conditional.On("NewWriter", mock.Anything).Return(writer)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		uri, err := service.UploadNewFile(ctx, bucketName, objectName, strings.NewReader("a,b\n"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "gs://upload-bucket/data.csv", uri)
+		assert.Equal(t, "a,b\n", writer.String())
+		mockObjectHandle.AssertNotCalled(t, "NewWriter", mock.Anything)
+	})
+
+	t.Run("Error_ExistingObjectMapsToConflict", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockObjectHandle := new(MockObjectHandle)
+		conditional := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+		mockObjectHandle.On("IfNotExists").Return(conditional)
+		conditional.On("NewWriter", mock.Anything).Return(&bufferWriter{closeErr: &googleapi.Error{Code: http.StatusPreconditionFailed}})
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		_, err := service.UploadNewFile(ctx, bucketName, objectName, strings.NewReader("a,b\n"))
+
+		assert.ErrorIs(t, err, core.ErrConflict)
+	})
+}
+
+func TestGCPStorageService_OpenObject(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "open-bucket"
+	objectName := "data.csv"
+
+	t.Run("Success_StreamsContent", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockObjectHandle := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+		mockObjectHandle.On("NewReader", mock.Anything).Return(io.NopCloser(strings.NewReader("a,b\n1,2\n")), nil)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		reader, size, err := service.OpenObject(ctx, bucketName, objectName)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer reader.Close()
+
+		data, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, "a,b\n1,2\n", string(data))
+		assert.Equal(t, int64(-1), size, "size is unknown for non-GCS readers")
+	})
+
+	t.Run("Error_NotFoundMapsToCoreError", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockObjectHandle := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+		mockObjectHandle.On("NewReader", mock.Anything).Return(nil, storage.ErrObjectNotExist)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		reader, _, err := service.OpenObject(ctx, bucketName, objectName)
+
+		assert.Nil(t, reader)
+		assert.ErrorIs(t, err, core.ErrNotFound)
+	})
+}
+
+func TestGCPStorageService_OpenObjectReaderAt(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "range-bucket"
+	objectName := "data.parquet"
+
+	t.Run("Success_RangedReads", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockObjectHandle := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+		mockObjectHandle.On("Attrs", mock.Anything).Return(&storage.ObjectAttrs{Name: objectName, Size: 10}, nil)
+		mockObjectHandle.On("NewRangeReader", ctx, int64(2), int64(4)).Return(io.NopCloser(strings.NewReader("2345")), nil).Once()
+		mockObjectHandle.On("NewRangeReader", ctx, int64(8), int64(2)).Return(io.NopCloser(strings.NewReader("89")), nil).Once()
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		readerAt, size, err := service.OpenObjectReaderAt(ctx, bucketName, objectName)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, int64(10), size)
+
+		buf := make([]byte, 4)
+		n, err := readerAt.ReadAt(buf, 2)
+		assert.NoError(t, err)
+		assert.Equal(t, "2345", string(buf[:n]))
+
+		n, err = readerAt.ReadAt(buf, 8) // Crosses the end of the object
+		assert.ErrorIs(t, err, io.EOF)
+		assert.Equal(t, "89", string(buf[:n]))
+
+		n, err = readerAt.ReadAt(buf, 10)
+		assert.Equal(t, 0, n)
+		assert.ErrorIs(t, err, io.EOF)
+		mockObjectHandle.AssertExpectations(t)
+	})
+
+	t.Run("Error_NotFoundMapsToCoreError", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockObjectHandle := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+		mockObjectHandle.On("Attrs", mock.Anything).Return(nil, storage.ErrObjectNotExist)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		_, _, err := service.OpenObjectReaderAt(ctx, bucketName, objectName)
+
+		assert.ErrorIs(t, err, core.ErrNotFound)
+	})
+}
+
+func TestGCPStorageService_GetObjectMetadata(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "meta-bucket"
+	objectName := "data.csv"
+
+	t.Run("Success", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockObjectHandle := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+		mockObjectHandle.On("Attrs", mock.Anything).Return(&storage.ObjectAttrs{
+			Name:        objectName,
+			Size:        42,
+			ContentType: "text/csv",
+			MD5:         []byte{0xde, 0xad, 0xbe, 0xef},
+			CRC32C:      7,
+			Metadata:    map[string]string{"uploadedBy": "user-1"},
+		}, nil)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		meta, err := service.GetObjectMetadata(ctx, bucketName, objectName)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(42), meta.Size)
+		assert.Equal(t, "deadbeef", meta.MD5)
+		assert.Equal(t, "gs://meta-bucket/data.csv", meta.URI)
+		assert.Equal(t, "user-1", meta.Metadata["uploadedBy"])
+	})
+
+	t.Run("Error_NotFoundMapsToCoreError", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockObjectHandle := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+		mockObjectHandle.On("Attrs", mock.Anything).Return(nil, storage.ErrObjectNotExist)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		_, err := service.GetObjectMetadata(ctx, bucketName, objectName)
+
+		assert.ErrorIs(t, err, core.ErrNotFound)
+	})
+}
+
+func TestGCPStorageService_UpdateObjectMetadata(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "meta-bucket"
+	objectName := "data.csv"
+
+	mockClient := new(MockStorageClient)
+	mockBucketHandle := new(MockBucketHandle)
+	mockObjectHandle := new(MockObjectHandle)
+	mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+	mockBucketHandle.On("Object", objectName).Return(mockObjectHandle)
+	mockObjectHandle.On("Attrs", mock.Anything).Return(&storage.ObjectAttrs{Metadata: map[string]string{"uploadedBy": "user-1"}}, nil)
+	mockObjectHandle.On("Update", mock.Anything, storage.ObjectAttrsToUpdate{
+		Metadata: map[string]string{"uploadedBy": "user-1", "rowCount": "5"},
+	}).Return(&storage.ObjectAttrs{}, nil)
+
+	service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+	err := service.UpdateObjectMetadata(ctx, bucketName, objectName, map[string]string{"rowCount": "5"})
+
+	assert.NoError(t, err)
+	mockObjectHandle.AssertExpectations(t)
+}
+
+func TestGCPStorageService_CopyObject(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "copy-bucket"
+
+	t.Run("Success", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		srcHandle := new(MockObjectHandle)
+		dstHandle := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", "old.csv").Return(srcHandle)
+		mockBucketHandle.On("Object", "new.csv").Return(dstHandle)
+		dstHandle.On("CopyFrom", mock.Anything, srcHandle).Return(&storage.ObjectAttrs{Name: "new.csv"}, nil)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		err := service.CopyObject(ctx, bucketName, "old.csv", "new.csv")
+
+		assert.NoError(t, err)
+		dstHandle.AssertExpectations(t)
+	})
+
+	t.Run("Error_SourceNotFound", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		srcHandle := new(MockObjectHandle)
+		dstHandle := new(MockObjectHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Object", "old.csv").Return(srcHandle)
+		mockBucketHandle.On("Object", "new.csv").Return(dstHandle)
+		dstHandle.On("CopyFrom", mock.Anything, srcHandle).Return(nil, storage.ErrObjectNotExist)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		err := service.CopyObject(ctx, bucketName, "old.csv", "new.csv")
+
+		assert.ErrorIs(t, err, core.ErrNotFound)
+	})
+}
+
+func TestGCPStorageService_ListObjectsPage(t *testing.T) {
+	ctx := context.Background()
+	bucketName := "paged-bucket"
+
+	t.Run("Success_SplitsFoldersAndObjects", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		it := newPagedObjectIterator(
+			[]*storage.ObjectAttrs{
+				{Name: "raw/", Size: 0}, // Folder placeholder for the listed prefix
+				{Name: "raw/a.csv", Size: 10},
+				{Prefix: "raw/2025/"},
+			},
+			[]*storage.ObjectAttrs{{Name: "raw/b.csv", Size: 20}},
+		)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Objects", mock.Anything, &storage.Query{Prefix: "raw/", Delimiter: "/"}).Return(it)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		page, err := service.ListObjectsPage(ctx, bucketName, core.ListObjectsOptions{Prefix: "raw/", Delimiter: "/", PageSize: 3})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"raw/2025/"}, page.Prefixes)
+		if assert.Len(t, page.Objects, 1) {
+			assert.Equal(t, "raw/a.csv", page.Objects[0].Name)
+			assert.Equal(t, "gs://paged-bucket/raw/a.csv", page.Objects[0].URI)
+		}
+		assert.Equal(t, "page-1", page.NextPageToken)
+	})
+
+	t.Run("Success_ResumesFromToken", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		it := newPagedObjectIterator(
+			[]*storage.ObjectAttrs{{Name: "a.csv"}},
+			[]*storage.ObjectAttrs{{Name: "b.csv", Size: 5}},
+		)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Objects", mock.Anything, mock.Anything).Return(it)
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		page, err := service.ListObjectsPage(ctx, bucketName, core.ListObjectsOptions{PageSize: 10, PageToken: "page-1"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"page-1"}, it.gotTokens)
+		if assert.Len(t, page.Objects, 1) {
+			assert.Equal(t, "b.csv", page.Objects[0].Name)
+		}
+		assert.Empty(t, page.NextPageToken)
+	})
+
+	t.Run("Error_InvalidPageSize", func(t *testing.T) {
+		service := &gcpStorageService{client: new(MockStorageClient), projectID: "p", logger: log.New(io.Discard, "", 0)}
+		_, err := service.ListObjectsPage(ctx, bucketName, core.ListObjectsOptions{})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Error_Iteration", func(t *testing.T) {
+		mockClient := new(MockStorageClient)
+		mockBucketHandle := new(MockBucketHandle)
+		mockClient.On("Bucket", bucketName).Return(mockBucketHandle)
+		mockBucketHandle.On("Objects", mock.Anything, mock.Anything).Return(nil, errors.New("backend down"))
+
+		service := &gcpStorageService{client: mockClient, projectID: "p", logger: log.New(io.Discard, "", 0)}
+		_, err := service.ListObjectsPage(ctx, bucketName, core.ListObjectsOptions{PageSize: 10})
+
+		assert.Error(t, err)
+	})
+}
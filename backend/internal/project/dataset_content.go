@@ -0,0 +1,555 @@
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dataset content page sizes.
+const (
+	DefaultDatasetContentLimit = 100
+	MaxDatasetContentLimit     = 1000
+)
+
+var (
+	// ErrInvalidDatasetQuery is returned for malformed offset, limit, sort or filter parameters.
+	ErrInvalidDatasetQuery = errors.New("invalid dataset query")
+	// ErrUnsupportedDatasetFormat is returned for file types the dataset readers cannot parse.
+	ErrUnsupportedDatasetFormat = errors.New("unsupported dataset file type")
+)
+
+// DatasetContentQuery selects, filters and orders the rows returned by GetDatasetContent.
+type DatasetContentQuery struct {
+	Offset  int
+	Limit   int         // Defaults to DefaultDatasetContentLimit, capped at MaxDatasetContentLimit
+	Columns []string    // Projection; empty returns every column
+	Sort    []SortKey   // Applied in order; empty keeps file order
+	Filters []RowFilter // ANDed together
+}
+
+// SortKey orders rows by a single column.
+type SortKey struct {
+	Column     string
+	Descending bool
+}
+
+// Filter operators.
+const (
+	FilterEq       = "="
+	FilterNe       = "!="
+	FilterGt       = ">"
+	FilterGte      = ">="
+	FilterLt       = "<"
+	FilterLte      = "<="
+	FilterContains = "~" // Case-insensitive substring match
+)
+
+// filterOperators is ordered so two-character operators are matched before their prefixes.
+var filterOperators = []string{FilterGte, FilterLte, FilterNe, FilterEq, FilterGt, FilterLt, FilterContains}
+
+// RowFilter keeps rows whose column value satisfies Operator against Value.
+type RowFilter struct {
+	Column   string
+	Operator string
+	Value    string
+}
+
+// ParseDatasetSort parses a comma-separated sort parameter such as "-age,name",
+// where a leading '-' sorts that column descending.
+func ParseDatasetSort(param string) ([]SortKey, error) {
+	if strings.TrimSpace(param) == "" {
+		return nil, nil
+	}
+	var keys []SortKey
+	for _, part := range strings.Split(param, ",") {
+		part = strings.TrimSpace(part)
+		key := SortKey{Column: part}
+		if strings.HasPrefix(part, "-") {
+			key = SortKey{Column: strings.TrimSpace(part[1:]), Descending: true}
+		} else if strings.HasPrefix(part, "+") {
+			key.Column = strings.TrimSpace(part[1:])
+		}
+		if key.Column == "" {
+			return nil, fmt.Errorf("%w: empty column in sort %q", ErrInvalidDatasetQuery, param)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ParseDatasetColumns parses a comma-separated column projection such as "name,age".
+func ParseDatasetColumns(param string) ([]string, error) {
+	if strings.TrimSpace(param) == "" {
+		return nil, nil
+	}
+	var columns []string
+	seen := make(map[string]struct{})
+	for _, part := range strings.Split(param, ",") {
+		column := strings.TrimSpace(part)
+		if column == "" {
+			return nil, fmt.Errorf("%w: empty column in columns %q", ErrInvalidDatasetQuery, param)
+		}
+		if _, dup := seen[column]; !dup {
+			seen[column] = struct{}{}
+			columns = append(columns, column)
+		}
+	}
+	return columns, nil
+}
+
+// ParseDatasetFilter parses a filter expression "<column><op><value>", for example
+// "age>=30", "country=US" or "name~smith". Supported operators are =, !=, >, >=, <, <= and ~.
+func ParseDatasetFilter(expr string) (RowFilter, error) {
+	for i := 0; i < len(expr); i++ {
+		for _, op := range filterOperators {
+			if !strings.HasPrefix(expr[i:], op) {
+				continue
+			}
+			column := strings.TrimSpace(expr[:i])
+			if column == "" {
+				return RowFilter{}, fmt.Errorf("%w: filter %q has no column", ErrInvalidDatasetQuery, expr)
+			}
+			return RowFilter{Column: column, Operator: op, Value: strings.TrimSpace(expr[i+len(op):])}, nil
+		}
+	}
+	return RowFilter{}, fmt.Errorf("%w: filter %q has no operator (use =, !=, >, >=, <, <= or ~)", ErrInvalidDatasetQuery, expr)
+}
+
+// normalize applies defaults and validates the offset and limit.
+func (q DatasetContentQuery) normalize() (DatasetContentQuery, error) {
+	if q.Offset < 0 {
+		return q, fmt.Errorf("%w: offset cannot be negative", ErrInvalidDatasetQuery)
+	}
+	if q.Limit < 0 {
+		return q, fmt.Errorf("%w: limit cannot be negative", ErrInvalidDatasetQuery)
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultDatasetContentLimit
+	}
+	if q.Limit > MaxDatasetContentLimit {
+		q.Limit = MaxDatasetContentLimit
+	}
+	return q, nil
+}
+
+// neededColumns returns the columns a projected query must read: the projection plus any
+// sort and filter columns. It returns nil (read everything) when there is no projection.
+func (q DatasetContentQuery) neededColumns() []string {
+	if len(q.Columns) == 0 {
+		return nil
+	}
+	needed := append([]string(nil), q.Columns...)
+	for _, key := range q.Sort {
+		needed = append(needed, key.Column)
+	}
+	for _, f := range q.Filters {
+		needed = append(needed, f.Column)
+	}
+	return needed
+}
+
+// validateColumns rejects projections, sort keys and filters that reference columns not in the dataset.
+func (q DatasetContentQuery) validateColumns(columns []string) error {
+	known := make(map[string]struct{}, len(columns))
+	for _, c := range columns {
+		known[c] = struct{}{}
+	}
+	for _, c := range q.Columns {
+		if _, ok := known[c]; !ok {
+			return fmt.Errorf("%w: unknown column %q", ErrInvalidDatasetQuery, c)
+		}
+	}
+	for _, key := range q.Sort {
+		if _, ok := known[key.Column]; !ok {
+			return fmt.Errorf("%w: unknown sort column %q", ErrInvalidDatasetQuery, key.Column)
+		}
+	}
+	for _, f := range q.Filters {
+		if _, ok := known[f.Column]; !ok {
+			return fmt.Errorf("%w: unknown filter column %q", ErrInvalidDatasetQuery, f.Column)
+		}
+	}
+	return nil
+}
+
+// matches reports whether a row satisfies every filter.
+func (q DatasetContentQuery) matches(row map[string]interface{}) bool {
+	for _, f := range q.Filters {
+		if !f.matches(row) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f RowFilter) matches(row map[string]interface{}) bool {
+	value, present := row[f.Column]
+	if f.Operator == FilterContains {
+		return present && value != nil && strings.Contains(strings.ToLower(formatValue(value)), strings.ToLower(f.Value))
+	}
+	if !present || value == nil {
+		return f.Operator == FilterNe // Missing values only satisfy "not equal"
+	}
+	cmp := compareValues(value, f.Value)
+	switch f.Operator {
+	case FilterEq:
+		return cmp == 0
+	case FilterNe:
+		return cmp != 0
+	case FilterGt:
+		return cmp > 0
+	case FilterGte:
+		return cmp >= 0
+	case FilterLt:
+		return cmp < 0
+	case FilterLte:
+		return cmp <= 0
+	}
+	return false
+}
+
+// compareValues compares two cell values numerically when both are numbers (or numeric
+// strings) and as strings otherwise. nil sorts before everything else.
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return -1
+	case b == nil:
+		return 1
+	}
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			switch {
+			case af < bf:
+				return -1
+			case af > bf:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(formatValue(a), formatValue(b))
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func formatValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case time.Time:
+		return val.Format(time.RFC3339Nano) // Sorts chronologically for UTC values
+	}
+	return fmt.Sprint(v)
+}
+
+// sortedRow pairs a row with its position in the file so sorting is stable.
+type sortedRow struct {
+	seq int
+	row map[string]interface{}
+}
+
+// sortRows orders rows by the sort keys, falling back to file order.
+func sortRows(rows []sortedRow, keys []SortKey) {
+	sort.Slice(rows, func(i, j int) bool {
+		for _, key := range keys {
+			cmp := compareValues(rows[i].row[key.Column], rows[j].row[key.Column])
+			if cmp == 0 {
+				continue
+			}
+			if key.Descending {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return rows[i].seq < rows[j].seq
+	})
+}
+
+// --- Streaming row readers ---
+
+// rowReader yields dataset rows one at a time.
+type rowReader interface {
+	// Next returns the next row, or io.EOF when the dataset is exhausted.
+	Next() (map[string]interface{}, error)
+	// Columns returns the columns seen so far, in file order.
+	Columns() []string
+	// ColumnsKnown reports whether Columns is already complete, e.g. from a CSV header,
+	// rather than growing as rows are read.
+	ColumnsKnown() bool
+}
+
+// newRowReader returns a streaming reader for the dataset format implied by ext.
+func newRowReader(ext string, r io.Reader) (rowReader, error) {
+	switch ext {
+	case ".csv":
+		return newCSVRowReader(r)
+	case ".json":
+		return newJSONRowReader(r)
+	case ".jsonl":
+		return newJSONLRowReader(r), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedDatasetFormat, ext)
+	}
+}
+
+// csvRowReader reads a CSV file whose first row is the header.
+type csvRowReader struct {
+	reader  *csv.Reader
+	headers []string
+	line    int
+}
+
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+	headers, err := reader.Read()
+	if err == io.EOF {
+		return &csvRowReader{reader: reader}, nil // Empty file is valid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	cleaned := make([]string, len(headers))
+	for i := range headers {
+		cleaned[i] = strings.TrimSpace(headers[i])
+	}
+	return &csvRowReader{reader: reader, headers: cleaned, line: 1}, nil
+}
+
+func (c *csvRowReader) Next() (map[string]interface{}, error) {
+	if c.headers == nil {
+		return nil, io.EOF
+	}
+	c.line++
+	record, err := c.reader.Read()
+	if err == io.EOF {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV data on line %d: %w", c.line, err)
+	}
+	if len(record) != len(c.headers) {
+		return nil, fmt.Errorf("CSV row length mismatch on line %d: expected %d columns, got %d", c.line, len(c.headers), len(record))
+	}
+	row := make(map[string]interface{}, len(c.headers))
+	for i, header := range c.headers {
+		row[header] = record[i]
+	}
+	return row, nil
+}
+
+func (c *csvRowReader) Columns() []string { return c.headers }
+
+func (c *csvRowReader) ColumnsKnown() bool { return true }
+
+// columnTracker records object keys in first-seen order for JSON formats.
+type columnTracker struct {
+	columns []string
+	seen    map[string]struct{}
+}
+
+func (t *columnTracker) add(keys []string) {
+	if t.seen == nil {
+		t.seen = make(map[string]struct{})
+	}
+	for _, key := range keys {
+		if _, ok := t.seen[key]; !ok {
+			t.seen[key] = struct{}{}
+			t.columns = append(t.columns, key)
+		}
+	}
+}
+
+func (t *columnTracker) Columns() []string { return t.columns }
+
+// ColumnsKnown is false: JSON objects may introduce new keys on any row.
+func (t *columnTracker) ColumnsKnown() bool { return false }
+
+// jsonRowReader streams the elements of a top-level JSON array of objects.
+type jsonRowReader struct {
+	columnTracker
+	decoder *json.Decoder
+	index   int
+}
+
+// newJSONRowReader reads a JSON array of objects. Content that does not start with '['
+// is treated as JSON Lines, matching how .json uploads have always been accepted.
+func newJSONRowReader(r io.Reader) (rowReader, error) {
+	br := bufio.NewReader(r)
+	first, err := peekNonSpace(br)
+	if err == io.EOF {
+		return &jsonRowReader{decoder: json.NewDecoder(br), index: -1}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JSON content: %w", err)
+	}
+	if first != '[' {
+		return newJSONLRowReader(br), nil
+	}
+	decoder := json.NewDecoder(br)
+	if _, err := decoder.Token(); err != nil { // Consume '['
+		return nil, fmt.Errorf("failed to read JSON array: %w", err)
+	}
+	return &jsonRowReader{decoder: decoder}, nil
+}
+
+func (j *jsonRowReader) Next() (map[string]interface{}, error) {
+	if j.index < 0 || !j.decoder.More() {
+		return nil, io.EOF
+	}
+	var raw json.RawMessage
+	if err := j.decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON array element %d: %w", j.index, err)
+	}
+	row, keys, err := decodeOrderedObject(raw)
+	if err != nil {
+		return nil, fmt.Errorf("JSON array element %d: %w", j.index, err)
+	}
+	j.index++
+	j.add(keys)
+	return row, nil
+}
+
+// jsonlRowReader streams newline-delimited JSON objects, skipping blank lines.
+type jsonlRowReader struct {
+	columnTracker
+	reader *bufio.Reader
+	line   int
+}
+
+func newJSONLRowReader(r io.Reader) *jsonlRowReader {
+	br, ok := r.(*bufio.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &jsonlRowReader{reader: br}
+}
+
+func (j *jsonlRowReader) Next() (map[string]interface{}, error) {
+	for {
+		lineBytes, err := j.reader.ReadBytes('\n')
+		if len(lineBytes) == 0 && err != nil {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("error scanning JSON Lines data: %w", err)
+		}
+		j.line++
+		if len(bytes.TrimSpace(lineBytes)) == 0 {
+			if err == io.EOF {
+				return nil, io.EOF
+			}
+			continue
+		}
+		row, keys, decodeErr := decodeOrderedObject(lineBytes)
+		if decodeErr != nil {
+			return nil, fmt.Errorf("failed to parse JSON on line %d: %w", j.line, decodeErr)
+		}
+		j.add(keys)
+		return row, nil
+	}
+}
+
+// decodeOrderedObject decodes a JSON object and also returns its keys in document order.
+func decodeOrderedObject(data []byte) (map[string]interface{}, []string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	tok, err := decoder.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, nil, errors.New("expected a JSON object")
+	}
+	row := make(map[string]interface{})
+	var keys []string
+	for decoder.More() {
+		keyTok, err := decoder.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		key := keyTok.(string) // Object keys are always strings
+		var value interface{}
+		if err := decoder.Decode(&value); err != nil {
+			return nil, nil, err
+		}
+		if _, dup := row[key]; !dup {
+			keys = append(keys, key)
+		}
+		row[key] = value
+	}
+	return row, keys, nil
+}
+
+// peekNonSpace skips leading whitespace and returns the next byte without consuming it.
+func peekNonSpace(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			return b, br.UnreadByte()
+		}
+	}
+}
+
+// projectRows restricts each row to the given columns. Missing values are returned as null.
+func projectRows(rows []map[string]interface{}, columns []string) []map[string]interface{} {
+	projected := make([]map[string]interface{}, len(rows))
+	for i, row := range rows {
+		out := make(map[string]interface{}, len(columns))
+		for _, column := range columns {
+			out[column] = row[column]
+		}
+		projected[i] = out
+	}
+	return projected
+}
+
+// countingReader counts the bytes read through it, for total-row estimates.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
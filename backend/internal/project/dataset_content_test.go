@@ -0,0 +1,273 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trackingReader records how many bytes of a dataset were consumed.
+type trackingReader struct {
+	*strings.Reader
+	closed bool
+}
+
+func (r *trackingReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func newTrackingReader(content string) *trackingReader {
+	return &trackingReader{Reader: strings.NewReader(content)}
+}
+
+func TestParseDatasetSort(t *testing.T) {
+	keys, err := ParseDatasetSort("-age, name,+city")
+	require.NoError(t, err)
+	assert.Equal(t, []SortKey{{Column: "age", Descending: true}, {Column: "name"}, {Column: "city"}}, keys)
+
+	keys, err = ParseDatasetSort("")
+	assert.NoError(t, err)
+	assert.Nil(t, keys)
+
+	_, err = ParseDatasetSort("age,,name")
+	assert.ErrorIs(t, err, ErrInvalidDatasetQuery)
+}
+
+func TestParseDatasetColumns(t *testing.T) {
+	columns, err := ParseDatasetColumns("name, age,name")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "age"}, columns)
+
+	_, err = ParseDatasetColumns("name,")
+	assert.ErrorIs(t, err, ErrInvalidDatasetQuery)
+}
+
+func TestParseDatasetFilter(t *testing.T) {
+	tests := []struct {
+		expr string
+		want RowFilter
+	}{
+		{"age>=30", RowFilter{Column: "age", Operator: FilterGte, Value: "30"}},
+		{"age<=30", RowFilter{Column: "age", Operator: FilterLte, Value: "30"}},
+		{"country!=US", RowFilter{Column: "country", Operator: FilterNe, Value: "US"}},
+		{"country = US", RowFilter{Column: "country", Operator: FilterEq, Value: "US"}},
+		{"score>1.5", RowFilter{Column: "score", Operator: FilterGt, Value: "1.5"}},
+		{"score<0", RowFilter{Column: "score", Operator: FilterLt, Value: "0"}},
+		{"name~smith", RowFilter{Column: "name", Operator: FilterContains, Value: "smith"}},
+		{"expr=a=b", RowFilter{Column: "expr", Operator: FilterEq, Value: "a=b"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseDatasetFilter(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, got, tt.expr)
+	}
+
+	for _, bad := range []string{"age", "=30", ""} {
+		_, err := ParseDatasetFilter(bad)
+		assert.ErrorIs(t, err, ErrInvalidDatasetQuery, bad)
+	}
+}
+
+func TestRowReaders(t *testing.T) {
+	readAll := func(t *testing.T, ext, content string) ([]map[string]interface{}, []string) {
+		rows, err := newRowReader(ext, strings.NewReader(content))
+		require.NoError(t, err)
+		var out []map[string]interface{}
+		for {
+			row, err := rows.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			out = append(out, row)
+		}
+		return out, rows.Columns()
+	}
+
+	t.Run("CSV", func(t *testing.T) {
+		rows, cols := readAll(t, ".csv", " b ,a\n1,2\n3,4\n")
+		assert.Equal(t, []string{"b", "a"}, cols)
+		assert.Equal(t, []map[string]interface{}{{"b": "1", "a": "2"}, {"b": "3", "a": "4"}}, rows)
+	})
+
+	t.Run("CSV_RowLengthMismatch", func(t *testing.T) {
+		rows, err := newRowReader(".csv", strings.NewReader("a,b\n1\n"))
+		require.NoError(t, err)
+		_, err = rows.Next()
+		assert.Error(t, err)
+	})
+
+	t.Run("JSONArray_KeepsKeyOrder", func(t *testing.T) {
+		rows, cols := readAll(t, ".json", ` [{"z": 1, "a": "x"}, {"a": "y", "m": null}]`)
+		assert.Equal(t, []string{"z", "a", "m"}, cols)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, float64(1), rows[0]["z"])
+	})
+
+	t.Run("JSONFileContainingJSONLines", func(t *testing.T) {
+		rows, cols := readAll(t, ".json", "{\"a\":1}\n\n{\"b\":2}\n")
+		assert.Equal(t, []string{"a", "b"}, cols)
+		assert.Len(t, rows, 2)
+	})
+
+	t.Run("JSONLines_NoTrailingNewline", func(t *testing.T) {
+		rows, _ := readAll(t, ".jsonl", "{\"a\":1}\n{\"a\":2}")
+		assert.Len(t, rows, 2)
+	})
+
+	t.Run("EmptyFiles", func(t *testing.T) {
+		for _, ext := range []string{".csv", ".json", ".jsonl"} {
+			rows, cols := readAll(t, ext, "")
+			assert.Empty(t, rows, ext)
+			assert.Empty(t, cols, ext)
+		}
+	})
+
+	t.Run("JSONArray_NonObjectElement", func(t *testing.T) {
+		rows, err := newRowReader(".json", strings.NewReader(`[1]`))
+		require.NoError(t, err)
+		_, err = rows.Next()
+		assert.Error(t, err)
+	})
+
+	t.Run("UnsupportedType", func(t *testing.T) {
+		_, err := newRowReader(".xlsx", strings.NewReader(""))
+		assert.Error(t, err)
+	})
+}
+
+func TestCompareValues(t *testing.T) {
+	assert.Equal(t, -1, compareValues("9", "10"), "numeric strings compare as numbers")
+	assert.Equal(t, 1, compareValues("b", "a"))
+	assert.Equal(t, 0, compareValues(float64(2), "2"))
+	assert.Equal(t, -1, compareValues(nil, "a"))
+	assert.Equal(t, 1, compareValues("a", nil))
+}
+
+func TestProjectService_GetDatasetContent(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-content"
+	bucketName := "bucket-content"
+	viewerID := "user-viewer"
+	members := map[string]core.Role{viewerID: core.RoleViewer}
+
+	var sb strings.Builder
+	sb.WriteString("id,name,age\n")
+	for i := 1; i <= 50; i++ {
+		fmt.Fprintf(&sb, "%d,user%02d,%d\n", i, i, 20+i%10)
+	}
+	csvData := sb.String()
+
+	setup := func(t *testing.T) (ProjectService, *trackingReader) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		reader := newTrackingReader(csvData)
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("OpenObject", ctx, bucketName, "people.csv").Return(reader, int64(len(csvData)), nil).Once()
+		return service, reader
+	}
+
+	t.Run("Success_FirstPageStopsEarlyWithEstimate", func(t *testing.T) {
+		service, reader := setup(t)
+
+		content, err := service.GetDatasetContent(ctx, projectID, "people.csv", viewerID, DatasetContentQuery{Limit: 5})
+
+		require.NoError(t, err)
+		assert.Len(t, content.Data, 5)
+		assert.Equal(t, "1", content.Data[0]["id"])
+		assert.Equal(t, []string{"id", "name", "age"}, content.Columns)
+		assert.False(t, content.TotalRowsExact)
+		assert.GreaterOrEqual(t, content.TotalRows, 5)
+		assert.True(t, reader.closed)
+	})
+
+	t.Run("Success_OffsetPastEndIsExact", func(t *testing.T) {
+		service, _ := setup(t)
+
+		content, err := service.GetDatasetContent(ctx, projectID, "people.csv", viewerID, DatasetContentQuery{Offset: 48, Limit: 5})
+
+		require.NoError(t, err)
+		assert.Len(t, content.Data, 2)
+		assert.Equal(t, "49", content.Data[0]["id"])
+		assert.Equal(t, 50, content.TotalRows)
+		assert.True(t, content.TotalRowsExact)
+	})
+
+	t.Run("Success_FilterAndSort", func(t *testing.T) {
+		service, _ := setup(t)
+		query := DatasetContentQuery{
+			Limit:   3,
+			Sort:    []SortKey{{Column: "age", Descending: true}, {Column: "id", Descending: true}},
+			Filters: []RowFilter{{Column: "age", Operator: FilterGte, Value: "28"}},
+		}
+
+		content, err := service.GetDatasetContent(ctx, projectID, "people.csv", viewerID, query)
+
+		require.NoError(t, err)
+		assert.Equal(t, 10, content.TotalRows) // ages 28 and 29, five rows each
+		assert.True(t, content.TotalRowsExact)
+		require.Len(t, content.Data, 3)
+		assert.Equal(t, []interface{}{"49", "39", "29"}, []interface{}{content.Data[0]["id"], content.Data[1]["id"], content.Data[2]["id"]})
+	})
+
+	t.Run("Success_Projection", func(t *testing.T) {
+		service, _ := setup(t)
+
+		content, err := service.GetDatasetContent(ctx, projectID, "people.csv", viewerID, DatasetContentQuery{Limit: 2, Columns: []string{"name"}, Sort: []SortKey{{Column: "id", Descending: true}}})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"name"}, content.Columns)
+		assert.Equal(t, []map[string]interface{}{{"name": "user50"}, {"name": "user49"}}, content.Data)
+		assert.Empty(t, content.Schema)
+	})
+
+	t.Run("Failure_UnknownSortColumn", func(t *testing.T) {
+		service, _ := setup(t)
+
+		content, err := service.GetDatasetContent(ctx, projectID, "people.csv", viewerID, DatasetContentQuery{Sort: []SortKey{{Column: "salary"}}})
+
+		assert.Nil(t, content)
+		assert.ErrorIs(t, err, ErrInvalidDatasetQuery)
+	})
+
+	t.Run("Failure_UnknownFilterColumn", func(t *testing.T) {
+		service, _ := setup(t)
+
+		_, err := service.GetDatasetContent(ctx, projectID, "people.csv", viewerID, DatasetContentQuery{Filters: []RowFilter{{Column: "salary", Operator: FilterGt, Value: "1"}}})
+
+		assert.ErrorIs(t, err, ErrInvalidDatasetQuery)
+	})
+
+	t.Run("Failure_NegativeOffset", func(t *testing.T) {
+		service, _, _, _ := setupProjectServiceTest()
+
+		_, err := service.GetDatasetContent(ctx, projectID, "people.csv", viewerID, DatasetContentQuery{Offset: -1})
+
+		assert.ErrorIs(t, err, ErrInvalidDatasetQuery)
+	})
+
+	t.Run("Failure_DatasetNotFound", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("OpenObject", ctx, bucketName, "missing.csv").Return(nil, int64(0), core.ErrNotFound).Once()
+
+		_, err := service.GetDatasetContent(ctx, projectID, "missing.csv", viewerID, DatasetContentQuery{})
+
+		assert.ErrorIs(t, err, ErrDatasetNotFound)
+	})
+
+	t.Run("Failure_AccessDenied", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+
+		_, err := service.GetDatasetContent(ctx, projectID, "people.csv", "stranger", DatasetContentQuery{})
+
+		assert.ErrorIs(t, err, ErrProjectAccessDenied)
+	})
+}
@@ -0,0 +1,677 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Fidelity comparison limits. Both datasets are compared on their first
+// fidelitySampleRows rows; the privacy metric compares at most privacySyntheticRecords
+// synthetic records against privacyReferenceRecords real ones.
+const (
+	fidelitySampleRows      = 10000
+	fidelityHistogramBins   = 20
+	maxReportedCategories   = 20
+	maxCorrelationColumns   = 30
+	privacyReferenceRecords = 5000
+	privacySyntheticRecords = 1000
+)
+
+// DatasetComparisonRequest selects a real and a synthetic dataset to compare.
+type DatasetComparisonRequest struct {
+	RealDatasetID      string
+	SyntheticDatasetID string
+	ReportName         string // Object the report is stored in; empty disables storing
+	Refresh            bool   // Recompute even if a stored report is current
+}
+
+// ColumnFidelity compares one column's marginal distribution across the datasets.
+type ColumnFidelity struct {
+	Name              string     `json:"name"`
+	Type              ColumnType `json:"type"` // Inferred from the real dataset
+	RealNullRate      float64    `json:"realNullRate"`
+	SyntheticNullRate float64    `json:"syntheticNullRate"`
+	KS                *float64   `json:"ks,omitempty"`               // Two-sample Kolmogorov-Smirnov statistic, numeric and date columns
+	TVD               *float64   `json:"tvd,omitempty"`              // Total variation distance over categories or histogram bins
+	CategoryCoverage  *float64   `json:"categoryCoverage,omitempty"` // Share of real categories present in the synthetic data
+	MissingCategories []string   `json:"missingCategories,omitempty"`
+	NovelCategories   []string   `json:"novelCategories,omitempty"` // Synthetic values never seen in the real data
+}
+
+// CorrelationComparison holds Pearson correlation matrices over the numeric columns.
+// Entries are null where a column has no variance.
+type CorrelationComparison struct {
+	Columns           []string     `json:"columns"`
+	Real              [][]*float64 `json:"real"`
+	Synthetic         [][]*float64 `json:"synthetic"`
+	MeanAbsDifference *float64     `json:"meanAbsDifference,omitempty"` // Over column pairs defined in both
+}
+
+// PrivacyMetrics summarizes the distance from each synthetic record to its closest real
+// record (DCR). Distances are in [0, 1]; 0 is an exact copy. The baseline is the same
+// metric for held-out real records, which synthetic records should not undercut.
+type PrivacyMetrics struct {
+	Features          []string `json:"features"`
+	SyntheticRecords  int      `json:"syntheticRecords"`
+	ReferenceRecords  int      `json:"referenceRecords"`
+	DCRMedian         float64  `json:"dcrMedian"`
+	DCRP05            float64  `json:"dcrP05"`
+	ExactMatchRate    float64  `json:"exactMatchRate"`
+	BaselineDCRMedian *float64 `json:"baselineDcrMedian,omitempty"`
+	BaselineDCRP05    *float64 `json:"baselineDcrP05,omitempty"`
+}
+
+// FidelitySummary averages the per-column metrics.
+type FidelitySummary struct {
+	MeanKS               *float64 `json:"meanKs,omitempty"`
+	MeanTVD              *float64 `json:"meanTvd,omitempty"`
+	MeanCategoryCoverage *float64 `json:"meanCategoryCoverage,omitempty"`
+}
+
+// ComparisonSource identifies the dataset versions a report was computed from.
+type ComparisonSource struct {
+	Real      ProfileSource `json:"real"`
+	Synthetic ProfileSource `json:"synthetic"`
+}
+
+// FidelityReport compares a synthetic dataset with the real dataset it imitates.
+type FidelityReport struct {
+	RealDatasetID      string                 `json:"realDatasetId"`
+	SyntheticDatasetID string                 `json:"syntheticDatasetId"`
+	RealRows           int                    `json:"realRows"` // Rows compared from each dataset
+	SyntheticRows      int                    `json:"syntheticRows"`
+	Sampled            bool                   `json:"sampled"` // Either dataset has rows beyond those compared
+	Summary            FidelitySummary        `json:"summary"`
+	Columns            []ColumnFidelity       `json:"columns"`
+	MissingColumns     []string               `json:"missingColumns"` // Only in the real dataset
+	ExtraColumns       []string               `json:"extraColumns"`   // Only in the synthetic dataset
+	Correlations       *CorrelationComparison `json:"correlations,omitempty"`
+	Privacy            *PrivacyMetrics        `json:"privacy,omitempty"`
+	Source             ComparisonSource       `json:"source"`
+	GeneratedAt        time.Time              `json:"generatedAt"`
+	Cached             bool                   `json:"cached"` // Served from the stored report
+}
+
+// datasetSample holds the first rows of a dataset.
+type datasetSample struct {
+	rows     []map[string]interface{}
+	columns  []string
+	complete bool
+}
+
+// CompareDatasets builds a fidelity report for a synthetic dataset against a real one,
+// requiring Viewer role. With a ReportName the report is stored there and reused until
+// either dataset changes.
+func (s *projectService) CompareDatasets(ctx context.Context, projectID string, callerID string, req DatasetComparisonRequest) (*FidelityReport, error) {
+	log := logger.Logger.With(zap.String("projectID", projectID), zap.String("realDatasetID", req.RealDatasetID), zap.String("syntheticDatasetID", req.SyntheticDatasetID))
+
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleViewer, false)
+	if err != nil {
+		if errors.Is(err, ErrProjectStorageNotConfigured) {
+			return nil, fmt.Errorf("project %s storage is not configured", projectID)
+		}
+		return nil, err
+	}
+	bucketName := project.Storage.BucketName
+
+	// 1. Identify the current version of both datasets
+	var source ComparisonSource
+	for _, ds := range []struct {
+		name   string
+		source *ProfileSource
+	}{{req.RealDatasetID, &source.Real}, {req.SyntheticDatasetID, &source.Synthetic}} {
+		obj, err := s.storageSvc.GetObjectMetadata(ctx, bucketName, ds.name)
+		if err != nil {
+			if errors.Is(err, core.ErrNotFound) {
+				return nil, fmt.Errorf("%w: %s", ErrDatasetNotFound, ds.name)
+			}
+			return nil, fmt.Errorf("failed to get dataset metadata: %w", err)
+		}
+		*ds.source = ProfileSource{Size: obj.Size, CRC32C: obj.CRC32C, Created: obj.Created}
+	}
+
+	// 2. Serve the stored report if both datasets are unchanged
+	if req.ReportName != "" && !req.Refresh {
+		var stored FidelityReport
+		if s.readStoredJSON(ctx, bucketName, req.ReportName, &stored) &&
+			stored.Source.Real.matches(source.Real) && stored.Source.Synthetic.matches(source.Synthetic) {
+			stored.Cached = true
+			return &stored, nil
+		}
+	}
+
+	// 3. Read both samples and compare them
+	realSample, err := s.sampleDataset(ctx, bucketName, req.RealDatasetID, fidelitySampleRows)
+	if err != nil {
+		return nil, err
+	}
+	synthetic, err := s.sampleDataset(ctx, bucketName, req.SyntheticDatasetID, fidelitySampleRows)
+	if err != nil {
+		return nil, err
+	}
+	report := compareSamples(realSample, synthetic)
+	report.RealDatasetID = req.RealDatasetID
+	report.SyntheticDatasetID = req.SyntheticDatasetID
+	report.Source = source
+	report.GeneratedAt = time.Now().UTC()
+
+	// 4. Store it; a failed write only costs a recomputation
+	if req.ReportName != "" {
+		encoded, err := json.Marshal(report)
+		if err == nil {
+			_, err = s.storageSvc.UploadFile(ctx, bucketName, req.ReportName, bytes.NewReader(encoded))
+		}
+		if err != nil {
+			log.Warn("Failed to store fidelity report", zap.Error(err), zap.String("reportName", req.ReportName))
+		}
+	}
+
+	log.Info("Compared datasets", zap.Int("columns", len(report.Columns)), zap.Int("realRows", report.RealRows), zap.Int("syntheticRows", report.SyntheticRows))
+	return report, nil
+}
+
+// sampleDataset reads up to maxRows rows of a dataset.
+func (s *projectService) sampleDataset(ctx context.Context, bucketName, objectName string, maxRows int) (*datasetSample, error) {
+	rows, closeRows, err := s.openDatasetRows(ctx, bucketName, objectName)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows()
+
+	sample := &datasetSample{}
+	for len(sample.rows) < maxRows {
+		row, err := rows.Next()
+		if err == io.EOF {
+			sample.complete = true
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dataset content (type: %s): %w", getExtension(objectName), err)
+		}
+		sample.rows = append(sample.rows, row)
+	}
+	if !sample.complete {
+		if _, err := rows.Next(); err == io.EOF {
+			sample.complete = true
+		}
+	}
+	sample.columns = rows.Columns()
+	return sample, nil
+}
+
+// compareSamples computes every fidelity metric for two samples. Column types are
+// inferred from the real sample.
+func compareSamples(realSample, syntheticSample *datasetSample) *FidelityReport {
+	report := &FidelityReport{
+		RealRows:       len(realSample.rows),
+		SyntheticRows:  len(syntheticSample.rows),
+		Sampled:        !realSample.complete || !syntheticSample.complete,
+		Columns:        []ColumnFidelity{},
+		MissingColumns: []string{},
+		ExtraColumns:   []string{},
+	}
+
+	inSynthetic := make(map[string]bool, len(syntheticSample.columns))
+	for _, name := range syntheticSample.columns {
+		inSynthetic[name] = true
+	}
+	inReal := make(map[string]bool, len(realSample.columns))
+	var shared []InferredColumn
+	for _, name := range realSample.columns {
+		inReal[name] = true
+		if !inSynthetic[name] {
+			report.MissingColumns = append(report.MissingColumns, name)
+			continue
+		}
+		stats := newColumnStats()
+		for _, row := range realSample.rows {
+			stats.add(row[name])
+		}
+		shared = append(shared, stats.result(name))
+	}
+	for _, name := range syntheticSample.columns {
+		if !inReal[name] {
+			report.ExtraColumns = append(report.ExtraColumns, name)
+		}
+	}
+
+	var ks, tvd, coverage []float64
+	for _, col := range shared {
+		fidelity := compareColumn(col, realSample.rows, syntheticSample.rows)
+		report.Columns = append(report.Columns, fidelity)
+		if fidelity.KS != nil {
+			ks = append(ks, *fidelity.KS)
+		}
+		if fidelity.TVD != nil {
+			tvd = append(tvd, *fidelity.TVD)
+		}
+		if fidelity.CategoryCoverage != nil {
+			coverage = append(coverage, *fidelity.CategoryCoverage)
+		}
+	}
+	report.Summary = FidelitySummary{MeanKS: meanOf(ks), MeanTVD: meanOf(tvd), MeanCategoryCoverage: meanOf(coverage)}
+	report.Correlations = compareCorrelations(shared, realSample.rows, syntheticSample.rows)
+	report.Privacy = distanceToClosestRecord(shared, realSample.rows, syntheticSample.rows)
+	return report
+}
+
+func isNullValue(v interface{}) bool {
+	s, ok := v.(string)
+	return v == nil || ok && isNullToken(s)
+}
+
+func isNumericType(t ColumnType) bool {
+	switch t {
+	case ColumnTypeInteger, ColumnTypeFloat, ColumnTypeDate, ColumnTypeDateTime:
+		return true
+	}
+	return false
+}
+
+func isCategoricalType(t ColumnType) bool {
+	return t == ColumnTypeCategorical || t == ColumnTypeBoolean
+}
+
+// numericValue maps a value of a numeric or date column onto the real line; dates
+// become Unix seconds.
+func numericValue(col InferredColumn, v interface{}) (float64, bool) {
+	if isNullValue(v) {
+		return 0, false
+	}
+	if col.Type == ColumnTypeDate || col.Type == ColumnTypeDateTime {
+		switch t := v.(type) {
+		case time.Time:
+			return float64(t.Unix()), true
+		case string:
+			parsed, err := time.Parse(col.Format, strings.TrimSpace(t))
+			return float64(parsed.Unix()), err == nil
+		}
+	}
+	f, ok := toFloat(v)
+	return f, ok && !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+func categoryKey(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return strings.TrimSpace(s)
+	}
+	return formatValue(v)
+}
+
+// compareColumn computes null rates and the marginal distance metrics for one column.
+func compareColumn(col InferredColumn, realRows, synthetic []map[string]interface{}) ColumnFidelity {
+	fidelity := ColumnFidelity{
+		Name:              col.Name,
+		Type:              col.Type,
+		RealNullRate:      nullRate(col.Name, realRows),
+		SyntheticNullRate: nullRate(col.Name, synthetic),
+	}
+
+	switch {
+	case isNumericType(col.Type):
+		xs, ys := numericColumn(col, realRows), numericColumn(col, synthetic)
+		if len(xs) == 0 || len(ys) == 0 {
+			return fidelity
+		}
+		sort.Float64s(xs)
+		sort.Float64s(ys)
+		ks := ksStatistic(xs, ys)
+		tvd := totalVariation(binCounts(xs, xs), binCounts(xs, ys))
+		fidelity.KS, fidelity.TVD = &ks, &tvd
+
+	case isCategoricalType(col.Type):
+		realCounts, syntheticCounts := categoryCounts(col.Name, realRows), categoryCounts(col.Name, synthetic)
+		if len(realCounts) == 0 || len(syntheticCounts) == 0 {
+			return fidelity
+		}
+		tvd := totalVariation(realCounts, syntheticCounts)
+		covered := 0
+		for value := range realCounts {
+			if syntheticCounts[value] > 0 {
+				covered++
+			} else {
+				fidelity.MissingCategories = append(fidelity.MissingCategories, value)
+			}
+		}
+		for value := range syntheticCounts {
+			if realCounts[value] == 0 {
+				fidelity.NovelCategories = append(fidelity.NovelCategories, value)
+			}
+		}
+		coverage := float64(covered) / float64(len(realCounts))
+		fidelity.TVD, fidelity.CategoryCoverage = &tvd, &coverage
+		fidelity.MissingCategories = firstSorted(fidelity.MissingCategories, maxReportedCategories)
+		fidelity.NovelCategories = firstSorted(fidelity.NovelCategories, maxReportedCategories)
+	}
+	return fidelity
+}
+
+func nullRate(name string, rows []map[string]interface{}) float64 {
+	if len(rows) == 0 {
+		return 0
+	}
+	nulls := 0
+	for _, row := range rows {
+		if isNullValue(row[name]) {
+			nulls++
+		}
+	}
+	return float64(nulls) / float64(len(rows))
+}
+
+func numericColumn(col InferredColumn, rows []map[string]interface{}) []float64 {
+	values := make([]float64, 0, len(rows))
+	for _, row := range rows {
+		if f, ok := numericValue(col, row[col.Name]); ok {
+			values = append(values, f)
+		}
+	}
+	return values
+}
+
+func categoryCounts(name string, rows []map[string]interface{}) map[string]int {
+	counts := make(map[string]int)
+	for _, row := range rows {
+		if v := row[name]; !isNullValue(v) {
+			counts[categoryKey(v)]++
+		}
+	}
+	return counts
+}
+
+// ksStatistic is the largest gap between the empirical CDFs of two sorted samples.
+func ksStatistic(a, b []float64) float64 {
+	i, j, d := 0, 0, 0.0
+	for i < len(a) && j < len(b) {
+		x := math.Min(a[i], b[j])
+		for i < len(a) && a[i] <= x {
+			i++
+		}
+		for j < len(b) && b[j] <= x {
+			j++
+		}
+		d = math.Max(d, math.Abs(float64(i)/float64(len(a))-float64(j)/float64(len(b))))
+	}
+	return d
+}
+
+// binCounts buckets values into equal-width bins over the range of the sorted reference
+// sample, plus a bin on either side for values outside it.
+func binCounts(reference, values []float64) map[string]int {
+	min, max := reference[0], reference[len(reference)-1]
+	counts := make(map[string]int)
+	for _, v := range values {
+		switch {
+		case v < min:
+			counts["below"]++
+		case v > max:
+			counts["above"]++
+		case min == max:
+			counts["0"]++
+		default:
+			bin := int((v - min) / (max - min) * fidelityHistogramBins)
+			if bin == fidelityHistogramBins {
+				bin-- // max itself
+			}
+			counts[fmt.Sprint(bin)]++
+		}
+	}
+	return counts
+}
+
+// totalVariation is half the L1 distance between two normalized frequency tables.
+func totalVariation(a, b map[string]int) float64 {
+	totalA, totalB := 0, 0
+	for _, n := range a {
+		totalA += n
+	}
+	for _, n := range b {
+		totalB += n
+	}
+	sum := 0.0
+	for key, n := range a {
+		sum += math.Abs(float64(n)/float64(totalA) - float64(b[key])/float64(totalB))
+	}
+	for key, n := range b {
+		if _, ok := a[key]; !ok {
+			sum += float64(n) / float64(totalB)
+		}
+	}
+	return sum / 2
+}
+
+// compareCorrelations computes Pearson correlation matrices over the integer and float
+// columns, or returns nil with fewer than two of them.
+func compareCorrelations(columns []InferredColumn, realRows, synthetic []map[string]interface{}) *CorrelationComparison {
+	var numeric []InferredColumn
+	for _, col := range columns {
+		if (col.Type == ColumnTypeInteger || col.Type == ColumnTypeFloat) && len(numeric) < maxCorrelationColumns {
+			numeric = append(numeric, col)
+		}
+	}
+	if len(numeric) < 2 {
+		return nil
+	}
+
+	comparison := &CorrelationComparison{
+		Real:      correlationMatrix(numeric, realRows),
+		Synthetic: correlationMatrix(numeric, synthetic),
+	}
+	var diffs []float64
+	for i, col := range numeric {
+		comparison.Columns = append(comparison.Columns, col.Name)
+		for j := i + 1; j < len(numeric); j++ {
+			if r, s := comparison.Real[i][j], comparison.Synthetic[i][j]; r != nil && s != nil {
+				diffs = append(diffs, math.Abs(*r-*s))
+			}
+		}
+	}
+	comparison.MeanAbsDifference = meanOf(diffs)
+	return comparison
+}
+
+func correlationMatrix(columns []InferredColumn, rows []map[string]interface{}) [][]*float64 {
+	values := make([][]float64, len(rows))
+	present := make([][]bool, len(rows))
+	for r, row := range rows {
+		values[r] = make([]float64, len(columns))
+		present[r] = make([]bool, len(columns))
+		for c, col := range columns {
+			values[r][c], present[r][c] = numericValue(col, row[col.Name])
+		}
+	}
+
+	matrix := make([][]*float64, len(columns))
+	for i := range matrix {
+		matrix[i] = make([]*float64, len(columns))
+	}
+	for i := range columns {
+		for j := i; j < len(columns); j++ {
+			var n, sx, sy, sxx, syy, sxy float64
+			for r := range rows {
+				if !present[r][i] || !present[r][j] {
+					continue
+				}
+				x, y := values[r][i], values[r][j]
+				n++
+				sx += x
+				sy += y
+				sxx += x * x
+				syy += y * y
+				sxy += x * y
+			}
+			varX, varY := n*sxx-sx*sx, n*syy-sy*sy
+			if n < 2 || varX <= 0 || varY <= 0 {
+				continue
+			}
+			corr := math.Max(-1, math.Min(1, (n*sxy-sx*sy)/math.Sqrt(varX*varY)))
+			matrix[i][j], matrix[j][i] = &corr, &corr
+		}
+	}
+	return matrix
+}
+
+// dcrFeature is one column used in record distances.
+type dcrFeature struct {
+	col      InferredColumn
+	numeric  bool
+	min, max float64 // Range of the real values, for numeric features
+}
+
+// distanceToClosestRecord compares synthetic records with real reference records over
+// the numeric and categorical columns. Each feature contributes a distance in [0, 1]:
+// the range-normalized difference for numbers, 0 or 1 for categories, and 1 when only
+// one side is null. A record distance is the mean over features. Real rows at even
+// positions are the reference; odd positions provide the held-out baseline.
+func distanceToClosestRecord(columns []InferredColumn, realRows, synthetic []map[string]interface{}) *PrivacyMetrics {
+	var features []dcrFeature
+	for _, col := range columns {
+		switch {
+		case isNumericType(col.Type):
+			values := numericColumn(col, realRows)
+			if len(values) == 0 {
+				continue
+			}
+			sort.Float64s(values)
+			features = append(features, dcrFeature{col: col, numeric: true, min: values[0], max: values[len(values)-1]})
+		case isCategoricalType(col.Type):
+			features = append(features, dcrFeature{col: col})
+		}
+	}
+	if len(features) == 0 || len(realRows) == 0 || len(synthetic) == 0 {
+		return nil
+	}
+
+	var reference, holdout []map[string]interface{}
+	for i, row := range realRows {
+		if i%2 == 0 && len(reference) < privacyReferenceRecords {
+			reference = append(reference, row)
+		} else if i%2 == 1 && len(holdout) < privacySyntheticRecords {
+			holdout = append(holdout, row)
+		}
+	}
+	if len(synthetic) > privacySyntheticRecords {
+		synthetic = synthetic[:privacySyntheticRecords]
+	}
+
+	metrics := &PrivacyMetrics{SyntheticRecords: len(synthetic), ReferenceRecords: len(reference)}
+	for _, f := range features {
+		metrics.Features = append(metrics.Features, f.col.Name)
+	}
+
+	distances := closestDistances(features, synthetic, reference)
+	exact := 0
+	for _, d := range distances {
+		if d == 0 {
+			exact++
+		}
+	}
+	metrics.DCRMedian = percentile(distances, 0.5)
+	metrics.DCRP05 = percentile(distances, 0.05)
+	metrics.ExactMatchRate = float64(exact) / float64(len(distances))
+
+	if len(holdout) > 0 {
+		baseline := closestDistances(features, holdout, reference)
+		median, p05 := percentile(baseline, 0.5), percentile(baseline, 0.05)
+		metrics.BaselineDCRMedian, metrics.BaselineDCRP05 = &median, &p05
+	}
+	return metrics
+}
+
+// closestDistances returns, sorted, each query record's distance to its nearest reference record.
+func closestDistances(features []dcrFeature, queries, reference []map[string]interface{}) []float64 {
+	encode := func(row map[string]interface{}) []interface{} {
+		encoded := make([]interface{}, len(features))
+		for i, f := range features {
+			if f.numeric {
+				if v, ok := numericValue(f.col, row[f.col.Name]); ok {
+					encoded[i] = v
+				}
+			} else if v := row[f.col.Name]; !isNullValue(v) {
+				encoded[i] = categoryKey(v)
+			}
+		}
+		return encoded
+	}
+	refs := make([][]interface{}, len(reference))
+	for i, row := range reference {
+		refs[i] = encode(row)
+	}
+
+	distances := make([]float64, len(queries))
+	for q, row := range queries {
+		query := encode(row)
+		best := math.Inf(1)
+		for _, ref := range refs {
+			d := 0.0
+			for i, f := range features {
+				d += featureDistance(f, query[i], ref[i])
+				if d >= best*float64(len(features)) {
+					break // Cannot beat the closest record so far
+				}
+			}
+			best = math.Min(best, d/float64(len(features)))
+		}
+		distances[q] = best
+	}
+	sort.Float64s(distances)
+	return distances
+}
+
+func featureDistance(f dcrFeature, a, b interface{}) float64 {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil || b == nil:
+		return 1
+	case !f.numeric:
+		if a.(string) == b.(string) {
+			return 0
+		}
+		return 1
+	case f.max == f.min:
+		if a.(float64) == b.(float64) {
+			return 0
+		}
+		return 1
+	}
+	return math.Min(1, math.Abs(a.(float64)-b.(float64))/(f.max-f.min))
+}
+
+// percentile returns the nearest-rank percentile of a sorted slice.
+func percentile(sorted []float64, p float64) float64 {
+	return sorted[int(math.Round(p*float64(len(sorted)-1)))]
+}
+
+// meanOf returns the mean of values, or nil for an empty slice.
+func meanOf(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	return &mean
+}
+
+func firstSorted(values []string, n int) []string {
+	sort.Strings(values)
+	if len(values) > n {
+		values = values[:n]
+	}
+	return values
+}
@@ -0,0 +1,215 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func fidelityTestSample(t *testing.T, data string) *datasetSample {
+	t.Helper()
+	rows, err := newRowReader(".csv", strings.NewReader(data))
+	require.NoError(t, err)
+	sample := &datasetSample{complete: true}
+	for {
+		row, err := rows.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		sample.rows = append(sample.rows, row)
+	}
+	sample.columns = rows.Columns()
+	return sample
+}
+
+func TestKSStatistic(t *testing.T) {
+	assert.Equal(t, 0.0, ksStatistic([]float64{1, 2, 3}, []float64{1, 2, 3}))
+	assert.Equal(t, 1.0, ksStatistic([]float64{1, 2}, []float64{3, 4}))
+	assert.Equal(t, 0.5, ksStatistic([]float64{1, 2, 3, 4}, []float64{3, 4, 5, 6}))
+}
+
+func TestTotalVariation(t *testing.T) {
+	assert.Equal(t, 0.0, totalVariation(map[string]int{"a": 1, "b": 1}, map[string]int{"a": 5, "b": 5}))
+	assert.Equal(t, 1.0, totalVariation(map[string]int{"a": 3}, map[string]int{"b": 2}))
+	assert.InDelta(t, 0.25, totalVariation(map[string]int{"a": 1, "b": 1}, map[string]int{"a": 3, "b": 1}), 1e-9)
+}
+
+func TestCompareSamples(t *testing.T) {
+	var realCSV, shiftedCSV strings.Builder
+	realCSV.WriteString("x,y,plan,id\n")
+	shiftedCSV.WriteString("x,y,plan,extra\n")
+	plans := []string{"free", "pro", "team"}
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&realCSV, "%d,%d,%s,%d\n", i, 2*i, plans[i%3], i)
+		fmt.Fprintf(&shiftedCSV, "%d,%d,%s,1\n", i+100, 400-2*i, []string{"free", "enterprise"}[i%2])
+	}
+
+	t.Run("IdenticalSamples", func(t *testing.T) {
+		report := compareSamples(fidelityTestSample(t, realCSV.String()), fidelityTestSample(t, realCSV.String()))
+
+		assert.Equal(t, 200, report.RealRows)
+		assert.False(t, report.Sampled)
+		assert.Empty(t, report.MissingColumns)
+		require.Len(t, report.Columns, 4)
+		for _, col := range report.Columns {
+			if col.KS != nil {
+				assert.Equal(t, 0.0, *col.KS, col.Name)
+			}
+			require.NotNil(t, col.TVD, col.Name)
+			assert.Equal(t, 0.0, *col.TVD, col.Name)
+		}
+		plan := report.Columns[2]
+		assert.Equal(t, 1.0, *plan.CategoryCoverage)
+
+		require.NotNil(t, report.Correlations)
+		assert.Equal(t, []string{"x", "y", "id"}, report.Correlations.Columns)
+		assert.InDelta(t, 1.0, *report.Correlations.Real[0][1], 1e-9)
+		assert.Equal(t, 0.0, *report.Correlations.MeanAbsDifference)
+
+		require.NotNil(t, report.Privacy)
+		// Half the copies come from the held-out rows, which are not in the reference set
+		assert.Equal(t, 0.5, report.Privacy.ExactMatchRate)
+		assert.Equal(t, 0.0, report.Privacy.DCRP05)
+		require.NotNil(t, report.Privacy.BaselineDCRMedian)
+		assert.Greater(t, *report.Privacy.BaselineDCRMedian, 0.0)
+	})
+
+	t.Run("ShiftedSample", func(t *testing.T) {
+		report := compareSamples(fidelityTestSample(t, realCSV.String()), fidelityTestSample(t, shiftedCSV.String()))
+
+		assert.Equal(t, []string{"id"}, report.MissingColumns)
+		assert.Equal(t, []string{"extra"}, report.ExtraColumns)
+		require.Len(t, report.Columns, 3)
+
+		x := report.Columns[0]
+		assert.Equal(t, ColumnTypeInteger, x.Type)
+		assert.Equal(t, 0.5, *x.KS)
+		assert.InDelta(t, 0.5, *x.TVD, 1e-9, "half the synthetic values fall above the real range")
+
+		plan := report.Columns[2]
+		assert.InDelta(t, 1.0/3, *plan.CategoryCoverage, 1e-9)
+		assert.Equal(t, []string{"pro", "team"}, plan.MissingCategories)
+		assert.Equal(t, []string{"enterprise"}, plan.NovelCategories)
+
+		require.NotNil(t, report.Correlations)
+		assert.InDelta(t, -1.0, *report.Correlations.Synthetic[0][1], 1e-9)
+		assert.InDelta(t, 2.0, *report.Correlations.MeanAbsDifference, 1e-9)
+		require.NotNil(t, report.Summary.MeanKS)
+		assert.Less(t, report.Privacy.ExactMatchRate, 0.01)
+	})
+}
+
+func TestProjectService_CompareDatasets(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-fidelity"
+	bucketName := "bucket-fidelity"
+	viewerID := "user-viewer"
+	members := map[string]core.Role{viewerID: core.RoleViewer}
+	realCSV := "n,plan\n1,free\n2,pro\n3,free\n"
+	syntheticCSV := "n,plan\n1,free\n4,pro\n"
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	realObject := &core.ObjectMetadata{Name: "real.csv", Size: int64(len(realCSV)), CRC32C: 1, Created: created}
+	syntheticObject := &core.ObjectMetadata{Name: "out/data.csv", Size: int64(len(syntheticCSV)), CRC32C: 2, Created: created}
+	source := ComparisonSource{
+		Real:      ProfileSource{Size: realObject.Size, CRC32C: 1, Created: created},
+		Synthetic: ProfileSource{Size: syntheticObject.Size, CRC32C: 2, Created: created},
+	}
+	req := DatasetComparisonRequest{RealDatasetID: "real.csv", SyntheticDatasetID: "out/data.csv", ReportName: "out/quality-report.json"}
+
+	setup := func(t *testing.T) (ProjectService, *MockStorageService) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "real.csv").Return(realObject, nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "out/data.csv").Return(syntheticObject, nil).Once()
+		return service, mockStorage
+	}
+	expectComparison := func(mockStorage *MockStorageService) *[]byte {
+		var stored []byte
+		mockStorage.On("OpenObject", ctx, bucketName, "real.csv").Return(io.NopCloser(strings.NewReader(realCSV)), int64(len(realCSV)), nil).Once()
+		mockStorage.On("OpenObject", ctx, bucketName, "out/data.csv").Return(io.NopCloser(strings.NewReader(syntheticCSV)), int64(len(syntheticCSV)), nil).Once()
+		mockStorage.On("UploadFile", ctx, bucketName, "out/quality-report.json", mock.Anything).Run(func(args mock.Arguments) {
+			stored, _ = io.ReadAll(args.Get(3).(io.Reader))
+		}).Return("gs://bucket-fidelity/out/quality-report.json", nil).Once()
+		return &stored
+	}
+
+	t.Run("Success_ComputesAndStores", func(t *testing.T) {
+		service, mockStorage := setup(t)
+		mockStorage.On("ReadObject", ctx, bucketName, "out/quality-report.json").Return(nil, core.ErrNotFound).Once()
+		stored := expectComparison(mockStorage)
+
+		report, err := service.CompareDatasets(ctx, projectID, viewerID, req)
+
+		require.NoError(t, err)
+		assert.False(t, report.Cached)
+		assert.Equal(t, 3, report.RealRows)
+		assert.Equal(t, 2, report.SyntheticRows)
+		assert.Equal(t, source, report.Source)
+		require.Len(t, report.Columns, 2)
+		require.NotNil(t, report.Columns[0].KS)
+		assert.InDelta(t, 0.5, *report.Columns[0].KS, 1e-9)
+
+		var cached FidelityReport
+		require.NoError(t, json.Unmarshal(*stored, &cached))
+		assert.Equal(t, source, cached.Source)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_ServesStoredReport", func(t *testing.T) {
+		service, mockStorage := setup(t)
+		stored, _ := json.Marshal(FidelityReport{RealDatasetID: "real.csv", RealRows: 3, Source: source})
+		mockStorage.On("ReadObject", ctx, bucketName, "out/quality-report.json").Return(stored, nil).Once()
+
+		report, err := service.CompareDatasets(ctx, projectID, viewerID, req)
+
+		require.NoError(t, err)
+		assert.True(t, report.Cached)
+		assert.Equal(t, 3, report.RealRows)
+		mockStorage.AssertNotCalled(t, "OpenObject", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_RecomputesWhenSyntheticChanged", func(t *testing.T) {
+		service, mockStorage := setup(t)
+		stale := source
+		stale.Synthetic.CRC32C = 99
+		stored, _ := json.Marshal(FidelityReport{RealRows: 99, Source: stale})
+		mockStorage.On("ReadObject", ctx, bucketName, "out/quality-report.json").Return(stored, nil).Once()
+		expectComparison(mockStorage)
+
+		report, err := service.CompareDatasets(ctx, projectID, viewerID, req)
+
+		require.NoError(t, err)
+		assert.False(t, report.Cached)
+		assert.Equal(t, 3, report.RealRows)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Failure_DatasetNotFound", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "real.csv").Return(nil, core.ErrNotFound).Once()
+
+		_, err := service.CompareDatasets(ctx, projectID, viewerID, req)
+
+		assert.ErrorIs(t, err, ErrDatasetNotFound)
+	})
+
+	t.Run("Failure_NotMember", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+
+		_, err := service.CompareDatasets(ctx, projectID, "stranger", req)
+
+		assert.ErrorIs(t, err, ErrProjectAccessDenied)
+	})
+}
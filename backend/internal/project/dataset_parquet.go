@@ -0,0 +1,342 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+	"go.uber.org/zap"
+)
+
+// parquetReadBufferSize is the minimum size of each ranged read against the object, so a
+// page fetch from remote storage is one request rather than many small ones.
+const parquetReadBufferSize = 1 << 20
+
+// parquetRowBatch is the number of rows decoded per read from a row group.
+const parquetRowBatch = 128
+
+// DatasetColumn describes a typed column, reported for formats that carry a schema.
+type DatasetColumn struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`     // Parquet logical or physical type, e.g. STRING, INT(64,true), DOUBLE, LIST
+	Nullable bool   `json:"nullable"` // Optional field
+	Repeated bool   `json:"repeated"`
+}
+
+// openParquetFile reads the footer of a Parquet file. Column chunks are fetched later,
+// only for the columns and row groups that are actually read.
+func openParquetFile(r io.ReaderAt, size int64) (*parquet.File, error) {
+	file, err := parquet.OpenFile(r, size,
+		parquet.SkipBloomFilters(true),
+		parquet.ReadBufferSize(parquetReadBufferSize),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Parquet file: %w", err)
+	}
+	return file, nil
+}
+
+// parquetRowReader reads typed rows from a Parquet file one row group at a time, decoding
+// only the projected top-level columns.
+type parquetRowReader struct {
+	file     *parquet.File
+	schema   *parquet.Schema // Projected schema rows are reconstructed with
+	conv     parquet.Conversion
+	fields   []parquet.Field // Projected top-level fields, in file order
+	rowGroup int             // Index of the next row group to open
+	rows     parquet.Rows    // Open row group, nil between groups
+	buf      []parquet.Row
+	pending  []parquet.Row
+	read     int64 // Rows decoded so far, excluding skipped rows
+}
+
+// newParquetRowReader projects the file onto the named top-level columns (all columns when
+// projection is empty). Unknown columns yield ErrInvalidDatasetQuery.
+func newParquetRowReader(file *parquet.File, projection []string) (*parquetRowReader, error) {
+	byName := make(map[string]parquet.Field)
+	for _, field := range file.Schema().Fields() {
+		byName[field.Name()] = field
+	}
+	wanted := make(map[string]struct{}, len(projection))
+	for _, name := range projection {
+		if _, ok := byName[name]; !ok {
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidDatasetQuery, name)
+		}
+		wanted[name] = struct{}{}
+	}
+
+	reader := &parquetRowReader{file: file, buf: make([]parquet.Row, parquetRowBatch)}
+	group := parquet.Group{}
+	for _, field := range file.Schema().Fields() {
+		if _, ok := wanted[field.Name()]; ok || len(projection) == 0 {
+			reader.fields = append(reader.fields, field)
+			group[field.Name()] = field
+		}
+	}
+	if len(projection) == 0 {
+		reader.schema = file.Schema()
+	} else {
+		reader.schema = parquet.NewSchema(file.Schema().Name(), group)
+	}
+	conv, err := parquet.Convert(reader.schema, file.Schema())
+	if err != nil {
+		return nil, fmt.Errorf("failed to project Parquet columns: %w", err)
+	}
+	reader.conv = conv
+	return reader, nil
+}
+
+// SkipRows positions the reader n rows into the file. Whole row groups are skipped using
+// their row counts from the footer, so none of their pages are read.
+func (p *parquetRowReader) SkipRows(n int64) error {
+	groups := p.file.RowGroups()
+	for p.rowGroup < len(groups) && n >= groups[p.rowGroup].NumRows() {
+		n -= groups[p.rowGroup].NumRows()
+		p.rowGroup++
+	}
+	if n == 0 || p.rowGroup >= len(groups) {
+		return nil
+	}
+	p.openRowGroup()
+	if err := p.rows.SeekToRow(n); err != nil {
+		return fmt.Errorf("failed to seek to row %d of row group %d: %w", n, p.rowGroup-1, err)
+	}
+	return nil
+}
+
+func (p *parquetRowReader) openRowGroup() {
+	rowGroup := parquet.ConvertRowGroup(p.file.RowGroups()[p.rowGroup], p.conv)
+	p.rows = rowGroup.Rows()
+	p.rowGroup++
+}
+
+func (p *parquetRowReader) closeRowGroup() error {
+	err := p.rows.Close()
+	p.rows = nil
+	return err
+}
+
+func (p *parquetRowReader) Next() (map[string]interface{}, error) {
+	for len(p.pending) == 0 {
+		if p.rows == nil {
+			if p.rowGroup >= len(p.file.RowGroups()) {
+				return nil, io.EOF
+			}
+			p.openRowGroup()
+		}
+		n, err := p.rows.ReadRows(p.buf)
+		p.pending = p.buf[:n]
+		if err == io.EOF || (err == nil && n == 0) {
+			if closeErr := p.closeRowGroup(); closeErr != nil {
+				return nil, fmt.Errorf("failed to close Parquet row group: %w", closeErr)
+			}
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read Parquet rows from row group %d: %w", p.rowGroup-1, err)
+		}
+	}
+
+	row := p.pending[0]
+	p.pending = p.pending[1:]
+	p.read++
+
+	values := make(map[string]interface{}, len(p.fields))
+	if err := p.schema.Reconstruct(&values, row); err != nil {
+		return nil, fmt.Errorf("failed to decode Parquet row: %w", err)
+	}
+	for _, field := range p.fields {
+		values[field.Name()] = parquetValue(field, values[field.Name()])
+	}
+	return values, nil
+}
+
+func (p *parquetRowReader) Columns() []string {
+	columns := make([]string, len(p.fields))
+	for i, field := range p.fields {
+		columns[i] = field.Name()
+	}
+	return columns
+}
+
+// ColumnsKnown is always true: the schema comes from the footer.
+func (p *parquetRowReader) ColumnsKnown() bool { return true }
+
+// Close releases the open row group, if any.
+func (p *parquetRowReader) Close() error {
+	if p.rows == nil {
+		return nil
+	}
+	return p.closeRowGroup()
+}
+
+// Schema describes the projected columns.
+func (p *parquetRowReader) Schema() []DatasetColumn {
+	columns := make([]DatasetColumn, len(p.fields))
+	for i, field := range p.fields {
+		columns[i] = DatasetColumn{
+			Name:     field.Name(),
+			Type:     parquetTypeName(field),
+			Nullable: field.Optional(),
+			Repeated: field.Repeated(),
+		}
+	}
+	return columns
+}
+
+func parquetTypeName(field parquet.Field) string {
+	if field.Leaf() {
+		return field.Type().String()
+	}
+	if lt := field.Type().LogicalType(); lt != nil {
+		return lt.String()
+	}
+	return "GROUP"
+}
+
+// parquetValue converts a reconstructed top-level value to its JSON-friendly form:
+// timestamps become time.Time, dates "YYYY-MM-DD", and byte slices are copied out of the
+// page buffers they may alias.
+func parquetValue(field parquet.Field, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	if b, ok := value.([]byte); ok {
+		return append([]byte(nil), b...)
+	}
+	lt := field.Type().LogicalType()
+	if !field.Leaf() || lt == nil {
+		return value
+	}
+	n, ok := integerValue(value)
+	if !ok {
+		return value
+	}
+	switch {
+	case lt.Timestamp != nil:
+		return timestampValue(lt.Timestamp, n)
+	case lt.Date != nil:
+		return time.Unix(n*24*60*60, 0).UTC().Format(time.DateOnly)
+	}
+	return value
+}
+
+func timestampValue(ts *format.TimestampType, n int64) time.Time {
+	switch {
+	case ts.Unit.Nanos != nil:
+		return time.Unix(0, n).UTC()
+	case ts.Unit.Micros != nil:
+		return time.UnixMicro(n).UTC()
+	default:
+		return time.UnixMilli(n).UTC()
+	}
+}
+
+func integerValue(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int32:
+		return int64(n), true
+	case int:
+		return int64(n), true
+	}
+	return 0, false
+}
+
+// parquetShape returns the row count and top-level column count from a Parquet footer.
+func parquetShape(r io.ReaderAt, size int64) (rows, cols int, err error) {
+	file, err := openParquetFile(r, size)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(file.NumRows()), len(file.Schema().Fields()), nil
+}
+
+// getParquetDatasetContent serves a page of a Parquet dataset. Only the footer and the
+// column chunks of projected, sorted and filtered columns are fetched. Unfiltered,
+// unsorted pages skip whole row groups and seek within the first one they need.
+func (s *projectService) getParquetDatasetContent(ctx context.Context, log *zap.Logger, bucketName, objectName string, query DatasetContentQuery) (*DatasetContent, error) {
+	readerAt, size, err := s.storageSvc.OpenObjectReaderAt(ctx, bucketName, objectName)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			log.Warn("Dataset file not found in storage", zap.Error(err))
+			return nil, fmt.Errorf("dataset file '%s' not found in project storage: %w", objectName, ErrDatasetNotFound)
+		}
+		log.Error("Failed to open object in storage", zap.Error(err))
+		return nil, fmt.Errorf("failed to read dataset content: %w", err)
+	}
+	file, err := openParquetFile(readerAt, size)
+	if err != nil {
+		log.Error("Failed to parse dataset content", zap.Error(err), zap.String("fileExtension", ".parquet"))
+		return nil, fmt.Errorf("failed to parse dataset content (type: .parquet): %w", err)
+	}
+	rows, err := newParquetRowReader(file, query.neededColumns())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var content *DatasetContent
+	if len(query.Filters) == 0 && len(query.Sort) == 0 {
+		if err := rows.SkipRows(int64(query.Offset)); err != nil {
+			log.Error("Failed to seek in Parquet dataset", zap.Error(err))
+			return nil, fmt.Errorf("failed to parse dataset content (type: .parquet): %w", err)
+		}
+		page := query
+		page.Offset = 0
+		content, err = scanDatasetPage(rows, page)
+		if err == nil {
+			content.Offset = query.Offset
+			content.TotalRows, content.TotalRowsExact = int(file.NumRows()), true
+		}
+	} else {
+		content, err = scanDatasetPage(rows, query)
+		if err == nil && !content.TotalRowsExact && rows.read > 0 {
+			// Extrapolate the match rate over the rows not yet read
+			if estimate := int(float64(content.TotalRows) * float64(file.NumRows()) / float64(rows.read)); estimate > content.TotalRows {
+				content.TotalRows = estimate
+			}
+		}
+	}
+	if errors.Is(err, ErrInvalidDatasetQuery) {
+		return nil, err
+	}
+	if err != nil {
+		log.Error("Failed to parse dataset content", zap.Error(err), zap.String("fileExtension", ".parquet"))
+		return nil, fmt.Errorf("failed to parse dataset content (type: .parquet): %w", err)
+	}
+
+	content.Schema = rows.Schema()
+	if len(query.Columns) > 0 {
+		content.Schema = projectSchema(content.Schema, query.Columns)
+	}
+
+	log.Info("Served dataset content page",
+		zap.Int("offset", content.Offset),
+		zap.Int("rows", len(content.Data)),
+		zap.Int("totalRows", content.TotalRows),
+		zap.Bool("totalRowsExact", content.TotalRowsExact),
+		zap.Int("rowGroups", len(file.RowGroups())),
+		zap.Int64("rowsDecoded", rows.read),
+	)
+	return content, nil
+}
+
+// projectSchema orders schema entries to match a column projection.
+func projectSchema(schema []DatasetColumn, columns []string) []DatasetColumn {
+	byName := make(map[string]DatasetColumn, len(schema))
+	for _, column := range schema {
+		byName[column.Name] = column
+	}
+	projected := make([]DatasetColumn, 0, len(columns))
+	for _, name := range columns {
+		if column, ok := byName[name]; ok {
+			projected = append(projected, column)
+		}
+	}
+	return projected
+}
@@ -0,0 +1,216 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type parquetTestRecord struct {
+	ID      int64     `parquet:"id"`
+	Name    string    `parquet:"name"`
+	Score   *float64  `parquet:"score,optional"`
+	Tags    []string  `parquet:"tags,list"`
+	Created time.Time `parquet:"created,timestamp"`
+}
+
+// newParquetTestFile writes n records in row groups of groupSize rows.
+func newParquetTestFile(t *testing.T, n, groupSize int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := parquet.NewGenericWriter[parquetTestRecord](&buf, parquet.MaxRowsPerRowGroup(int64(groupSize)))
+	for i := 0; i < n; i++ {
+		record := parquetTestRecord{
+			ID:      int64(i),
+			Name:    fmt.Sprintf("user%02d", i),
+			Tags:    []string{"t", fmt.Sprint(i % 3)},
+			Created: time.Date(2025, 1, 1, 0, 0, i, 0, time.UTC),
+		}
+		if i%2 == 0 {
+			score := float64(i) / 2
+			record.Score = &score
+		}
+		_, err := writer.Write([]parquetTestRecord{record})
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buf.Bytes()
+}
+
+func TestParquetRowReader(t *testing.T) {
+	data := newParquetTestFile(t, 10, 4)
+	file, err := openParquetFile(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	require.Len(t, file.RowGroups(), 3)
+
+	t.Run("TypedRowsAndSchema", func(t *testing.T) {
+		rows, err := newParquetRowReader(file, nil)
+		require.NoError(t, err)
+		defer rows.Close()
+
+		assert.Equal(t, []string{"id", "name", "score", "tags", "created"}, rows.Columns())
+		schema := rows.Schema()
+		assert.Equal(t, DatasetColumn{Name: "score", Type: "DOUBLE", Nullable: true}, schema[2])
+		assert.Equal(t, "LIST", schema[3].Type)
+
+		first, err := rows.Next()
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), first["id"])
+		assert.Equal(t, "user00", first["name"])
+		assert.Equal(t, float64(0), first["score"])
+		assert.Equal(t, []interface{}{"t", "0"}, first["tags"])
+		assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), first["created"])
+
+		second, err := rows.Next()
+		require.NoError(t, err)
+		assert.Nil(t, second["score"])
+
+		count := 2
+		for {
+			_, err := rows.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			count++
+		}
+		assert.Equal(t, 10, count)
+	})
+
+	t.Run("Projection", func(t *testing.T) {
+		rows, err := newParquetRowReader(file, []string{"name", "id"})
+		require.NoError(t, err)
+		defer rows.Close()
+
+		assert.Equal(t, []string{"id", "name"}, rows.Columns(), "file order is kept")
+		row, err := rows.Next()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"id": int64(0), "name": "user00"}, row)
+	})
+
+	t.Run("UnknownColumn", func(t *testing.T) {
+		_, err := newParquetRowReader(file, []string{"salary"})
+		assert.ErrorIs(t, err, ErrInvalidDatasetQuery)
+	})
+
+	t.Run("SkipRowsAcrossRowGroups", func(t *testing.T) {
+		for _, skip := range []int64{0, 3, 4, 9} {
+			rows, err := newParquetRowReader(file, []string{"id"})
+			require.NoError(t, err)
+			require.NoError(t, rows.SkipRows(skip))
+
+			row, err := rows.Next()
+			require.NoError(t, err, "skip %d", skip)
+			assert.Equal(t, skip, row["id"], "skip %d", skip)
+			assert.Equal(t, int64(1), rows.read, "skipped rows are not decoded")
+			rows.Close()
+		}
+
+		rows, err := newParquetRowReader(file, nil)
+		require.NoError(t, err)
+		require.NoError(t, rows.SkipRows(10))
+		_, err = rows.Next()
+		assert.Equal(t, io.EOF, err)
+	})
+}
+
+func TestProjectService_GetDatasetContent_Parquet(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-parquet"
+	bucketName := "bucket-parquet"
+	viewerID := "user-viewer"
+	members := map[string]core.Role{viewerID: core.RoleViewer}
+	objectName := "jobs/job-1/output.parquet"
+	data := newParquetTestFile(t, 25, 10)
+
+	setup := func(t *testing.T) ProjectService {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("OpenObjectReaderAt", ctx, bucketName, objectName).Return(bytes.NewReader(data), int64(len(data)), nil).Once()
+		return service
+	}
+
+	t.Run("Success_PageFromLaterRowGroup", func(t *testing.T) {
+		service := setup(t)
+
+		content, err := service.GetDatasetContent(ctx, projectID, objectName, viewerID, DatasetContentQuery{Offset: 18, Limit: 5, Columns: []string{"name", "id"}})
+
+		require.NoError(t, err)
+		require.Len(t, content.Data, 5)
+		assert.Equal(t, map[string]interface{}{"name": "user18", "id": int64(18)}, content.Data[0])
+		assert.Equal(t, "user22", content.Data[4]["name"])
+		assert.Equal(t, []string{"name", "id"}, content.Columns)
+		assert.Equal(t, []DatasetColumn{{Name: "name", Type: "STRING"}, {Name: "id", Type: "INT(64,true)"}}, content.Schema)
+		assert.Equal(t, 18, content.Offset)
+		assert.Equal(t, 25, content.TotalRows)
+		assert.True(t, content.TotalRowsExact)
+	})
+
+	t.Run("Success_FilterAndSortOnUnprojectedColumns", func(t *testing.T) {
+		service := setup(t)
+		query := DatasetContentQuery{
+			Limit:   3,
+			Columns: []string{"name"},
+			Sort:    []SortKey{{Column: "score", Descending: true}},
+			Filters: []RowFilter{{Column: "score", Operator: FilterGte, Value: "5"}},
+		}
+
+		content, err := service.GetDatasetContent(ctx, projectID, objectName, viewerID, query)
+
+		require.NoError(t, err)
+		assert.Equal(t, 8, content.TotalRows) // Even ids 10-24 score 5 or more
+		assert.True(t, content.TotalRowsExact)
+		assert.Equal(t, []map[string]interface{}{{"name": "user24"}, {"name": "user22"}, {"name": "user20"}}, content.Data)
+	})
+
+	t.Run("Failure_UnknownColumn", func(t *testing.T) {
+		service := setup(t)
+
+		_, err := service.GetDatasetContent(ctx, projectID, objectName, viewerID, DatasetContentQuery{Columns: []string{"salary"}})
+
+		assert.ErrorIs(t, err, ErrInvalidDatasetQuery)
+	})
+
+	t.Run("Failure_NotParquet", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		notParquet := []byte("id,name\n1,a\n")
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("OpenObjectReaderAt", ctx, bucketName, "bad.parquet").Return(bytes.NewReader(notParquet), int64(len(notParquet)), nil).Once()
+
+		_, err := service.GetDatasetContent(ctx, projectID, "bad.parquet", viewerID, DatasetContentQuery{})
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, ErrInvalidDatasetQuery)
+	})
+}
+
+func TestProjectService_GetDatasetMetadata_ParquetShape(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-parquet-meta"
+	bucketName := "bucket-parquet-meta"
+	viewerID := "user-viewer"
+	data := newParquetTestFile(t, 7, 3)
+
+	service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+	mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, map[string]core.Role{viewerID: core.RoleViewer}), nil).Once()
+	mockStorage.On("GetObjectMetadata", ctx, bucketName, "data.parquet").Return(&core.ObjectMetadata{Name: "data.parquet", Size: int64(len(data))}, nil).Once()
+	mockStorage.On("OpenObjectReaderAt", ctx, bucketName, "data.parquet").Return(bytes.NewReader(data), int64(len(data)), nil).Once()
+	mockStorage.On("UpdateObjectMetadata", ctx, bucketName, "data.parquet", mock.Anything).Return(nil).Once()
+
+	meta, err := service.GetDatasetMetadata(ctx, projectID, "data.parquet", viewerID)
+
+	require.NoError(t, err)
+	require.NotNil(t, meta.RowCount)
+	assert.Equal(t, 7, *meta.RowCount)
+	assert.Equal(t, 5, *meta.ColumnCount)
+	mockStorage.AssertExpectations(t)
+}
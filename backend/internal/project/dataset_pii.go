@@ -0,0 +1,419 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap"
+)
+
+// PII scan limits. A column is flagged for a PII type when at least piiMatchThreshold
+// of its non-null sampled values match it.
+const (
+	piiScanRows       = 10000
+	piiMatchThreshold = 0.5
+	piiScanTimeout    = 10 * time.Minute
+	maxPIIFindings    = 32 // Keeps the stored scan within the object metadata size limit
+)
+
+// Custom object metadata keys written by the PII scanner.
+const (
+	metaPIIScan           = "piiScan"
+	metaPIIAcknowledgedBy = "piiAcknowledgedBy"
+	metaPIIAcknowledgedAt = "piiAcknowledgedAt"
+)
+
+// PII scan states.
+const (
+	PIIScanNotScanned = "not_scanned" // Uploaded before scanning existed
+	PIIScanPending    = "pending"
+	PIIScanCompleted  = "completed"
+	PIIScanFailed     = "failed"
+)
+
+// PIIType names a kind of personal data the scanner detects.
+type PIIType string
+
+const (
+	PIITypeEmail      PIIType = "email"
+	PIITypePhone      PIIType = "phone"
+	PIITypeCreditCard PIIType = "credit_card"
+	PIITypeSSN        PIIType = "ssn"
+	PIITypeIPAddress  PIIType = "ip_address"
+	PIITypeName       PIIType = "name"
+)
+
+// PIISeverity ranks findings. High-severity findings can block job submission.
+type PIISeverity string
+
+const (
+	PIISeverityHigh   PIISeverity = "high"
+	PIISeverityMedium PIISeverity = "medium"
+)
+
+var piiSeverities = map[PIIType]PIISeverity{
+	PIITypeEmail:      PIISeverityHigh,
+	PIITypePhone:      PIISeverityHigh,
+	PIITypeCreditCard: PIISeverityHigh,
+	PIITypeSSN:        PIISeverityHigh,
+	PIITypeIPAddress:  PIISeverityMedium,
+	PIITypeName:       PIISeverityMedium,
+}
+
+// ErrPIIScanIncomplete is returned when acknowledging a dataset without a completed scan.
+var ErrPIIScanIncomplete = errors.New("PII scan has not completed")
+
+var (
+	ssnPattern   = regexp.MustCompile(`^(\d{3})-?(\d{2})-?(\d{4})$`)
+	phonePattern = regexp.MustCompile(`^\+?[\d\s().-]+$`)
+	namePattern  = regexp.MustCompile(`^\p{Lu}[\p{L}'’.-]*(?:\s+\p{Lu}[\p{L}'’.-]*){0,3}$`)
+)
+
+// Normalized column headers that mark personal names, phone numbers and SSNs. Name
+// values are only flagged under such a header, since their shape alone is too common.
+var (
+	nameHeaders     = []string{"name", "firstname", "lastname", "surname", "fullname", "givenname", "familyname", "middlename"}
+	namePrefixes    = []string{"customer", "contact", "person", "patient", "employee", "holder", "cardholder", "client"}
+	phoneHeaderHint = []string{"phone", "mobile", "tel", "fax", "cell"}
+	ssnHeaderHint   = []string{"ssn", "socialsecurity"}
+)
+
+// PIIFinding flags one column as containing one kind of personal data.
+type PIIFinding struct {
+	Column    string      `json:"column"`
+	Type      PIIType     `json:"type"`
+	Severity  PIISeverity `json:"severity"`
+	Matches   int         `json:"matches"`   // Sampled values that matched
+	MatchRate float64     `json:"matchRate"` // Share of the column's non-null sampled values
+}
+
+// PIIScan is the outcome of scanning a dataset for personal data. Findings never
+// include the matched values.
+type PIIScan struct {
+	DatasetID         string       `json:"datasetId,omitempty"`
+	Status            string       `json:"status"`
+	ScannedAt         *time.Time   `json:"scannedAt,omitempty"`
+	RowsScanned       int          `json:"rowsScanned,omitempty"`
+	Sampled           bool         `json:"sampled,omitempty"` // Only the first rows were scanned
+	Findings          []PIIFinding `json:"findings,omitempty"`
+	FindingsTruncated bool         `json:"findingsTruncated,omitempty"`
+	HighSeverityCount int          `json:"highSeverityCount,omitempty"`
+	Error             string       `json:"error,omitempty"`
+	AcknowledgedBy    string       `json:"acknowledgedBy,omitempty"`
+	AcknowledgedAt    *time.Time   `json:"acknowledgedAt,omitempty"`
+}
+
+// NeedsReview reports whether jobs reading the dataset must wait: the scan has not
+// completed, or it has unacknowledged high-severity findings.
+func (p *PIIScan) NeedsReview() bool {
+	if p.Status != PIIScanCompleted {
+		return true
+	}
+	return p.HighSeverityCount > 0 && p.AcknowledgedAt == nil
+}
+
+// GetDatasetPIIScan returns the stored PII scan of a dataset, requiring Viewer role.
+func (s *projectService) GetDatasetPIIScan(ctx context.Context, projectID string, datasetID string, callerID string) (*PIIScan, error) {
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleViewer, false)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.storageSvc.GetObjectMetadata(ctx, project.Storage.BucketName, datasetID)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrDatasetNotFound, datasetID)
+		}
+		return nil, fmt.Errorf("failed to get dataset metadata: %w", err)
+	}
+	return piiScanFromMetadata(datasetID, obj.Metadata), nil
+}
+
+// ScanDatasetPII rescans a dataset synchronously, requiring Member role on an active
+// project. A rescan clears any earlier acknowledgement.
+func (s *projectService) ScanDatasetPII(ctx context.Context, projectID string, datasetID string, callerID string) (*PIIScan, error) {
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleMember, true)
+	if err != nil {
+		return nil, err
+	}
+	bucketName := project.Storage.BucketName
+	if _, err := s.storageSvc.GetObjectMetadata(ctx, bucketName, datasetID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrDatasetNotFound, datasetID)
+		}
+		return nil, fmt.Errorf("failed to get dataset metadata: %w", err)
+	}
+
+	return s.scanDatasetPII(ctx, bucketName, datasetID), nil
+}
+
+// AcknowledgeDatasetPII records that the caller reviewed a dataset's PII findings,
+// requiring Member role on an active project.
+func (s *projectService) AcknowledgeDatasetPII(ctx context.Context, projectID string, datasetID string, callerID string) (*PIIScan, error) {
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleMember, true)
+	if err != nil {
+		return nil, err
+	}
+	bucketName := project.Storage.BucketName
+
+	obj, err := s.storageSvc.GetObjectMetadata(ctx, bucketName, datasetID)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrDatasetNotFound, datasetID)
+		}
+		return nil, fmt.Errorf("failed to get dataset metadata: %w", err)
+	}
+	scan := piiScanFromMetadata(datasetID, obj.Metadata)
+	if scan.Status != PIIScanCompleted {
+		return nil, fmt.Errorf("%w: scan of %s is %s", ErrPIIScanIncomplete, datasetID, scan.Status)
+	}
+
+	now := time.Now().UTC().Truncate(time.Second) // Stored with second precision
+	ack := map[string]string{metaPIIAcknowledgedBy: callerID, metaPIIAcknowledgedAt: now.Format(time.RFC3339)}
+	if err := s.storageSvc.UpdateObjectMetadata(ctx, bucketName, datasetID, ack); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrDatasetNotFound, datasetID)
+		}
+		return nil, fmt.Errorf("failed to record PII acknowledgement: %w", err)
+	}
+	scan.AcknowledgedBy, scan.AcknowledgedAt = callerID, &now
+
+	logger.Logger.Info("Dataset PII findings acknowledged", zap.String("projectID", projectID), zap.String("datasetID", datasetID), zap.String("callerID", callerID), zap.Int("highSeverityCount", scan.HighSeverityCount))
+	return scan, nil
+}
+
+// schedulePIIScan scans a freshly uploaded dataset in the background. The upload
+// request's context ends with the response, so the scan gets its own.
+func (s *projectService) schedulePIIScan(bucketName, objectName string) {
+	s.runAsync(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), piiScanTimeout)
+		defer cancel()
+		s.scanDatasetPII(ctx, bucketName, objectName)
+	})
+}
+
+// scanDatasetPII scans a dataset sample and stores the outcome, replacing any earlier
+// scan and acknowledgement. Failures are recorded in the scan rather than returned.
+func (s *projectService) scanDatasetPII(ctx context.Context, bucketName, objectName string) *PIIScan {
+	log := logger.Logger.With(zap.String("bucketName", bucketName), zap.String("objectName", objectName))
+	now := time.Now().UTC()
+	scan := &PIIScan{Status: PIIScanCompleted, ScannedAt: &now}
+
+	sample, err := s.sampleDataset(ctx, bucketName, objectName, piiScanRows)
+	if err != nil {
+		log.Warn("PII scan failed", zap.Error(err))
+		scan.Status, scan.Error = PIIScanFailed, err.Error()
+	} else {
+		scan.RowsScanned = len(sample.rows)
+		scan.Sampled = !sample.complete
+		scan.Findings = detectPII(sample)
+		if len(scan.Findings) > maxPIIFindings {
+			scan.Findings, scan.FindingsTruncated = scan.Findings[:maxPIIFindings], true
+		}
+	}
+
+	encoded, err := json.Marshal(scan)
+	if err == nil {
+		err = s.storageSvc.UpdateObjectMetadata(ctx, bucketName, objectName, map[string]string{
+			metaPIIScan:           string(encoded),
+			metaPIIAcknowledgedBy: "",
+			metaPIIAcknowledgedAt: "",
+		})
+	}
+	if err != nil {
+		log.Error("Failed to store PII scan", zap.Error(err))
+	}
+
+	scan.DatasetID = objectName
+	scan.HighSeverityCount = countHighSeverity(scan.Findings)
+	log.Info("Dataset scanned for PII", zap.String("status", scan.Status), zap.Int("findings", len(scan.Findings)), zap.Int("highSeverityCount", scan.HighSeverityCount))
+	return scan
+}
+
+// piiScanFromMetadata decodes the scan and acknowledgement stored on a dataset object.
+func piiScanFromMetadata(datasetID string, metadata map[string]string) *PIIScan {
+	scan := &PIIScan{Status: PIIScanNotScanned}
+	if raw := metadata[metaPIIScan]; raw != "" {
+		if err := json.Unmarshal([]byte(raw), scan); err != nil {
+			scan = &PIIScan{Status: PIIScanFailed, Error: "stored scan is unreadable"}
+		}
+	}
+	scan.DatasetID = datasetID
+	scan.HighSeverityCount = countHighSeverity(scan.Findings)
+	if at, err := time.Parse(time.RFC3339, metadata[metaPIIAcknowledgedAt]); err == nil {
+		scan.AcknowledgedBy, scan.AcknowledgedAt = metadata[metaPIIAcknowledgedBy], &at
+	}
+	return scan
+}
+
+func countHighSeverity(findings []PIIFinding) int {
+	n := 0
+	for _, f := range findings {
+		if f.Severity == PIISeverityHigh {
+			n++
+		}
+	}
+	return n
+}
+
+// detectPII flags the columns of a sample whose values mostly match a PII type. Each
+// value counts towards the first type it matches. Findings are ordered high severity
+// first, then by column position.
+func detectPII(sample *datasetSample) []PIIFinding {
+	var findings []PIIFinding
+	for _, column := range sample.columns {
+		header := normalizeHeader(column)
+		counts := make(map[PIIType]int)
+		nonNull := 0
+		for _, row := range sample.rows {
+			v := row[column]
+			switch v.(type) {
+			case bool, time.Time, map[string]interface{}, []interface{}:
+				continue // Cannot hold any detected type
+			}
+			if isNullValue(v) {
+				continue
+			}
+			nonNull++
+			if t, ok := classifyPII(categoryKey(v), header); ok {
+				counts[t]++
+			}
+		}
+		for _, t := range []PIIType{PIITypeCreditCard, PIITypeSSN, PIITypeEmail, PIITypeIPAddress, PIITypePhone, PIITypeName} {
+			if counts[t] == 0 {
+				continue
+			}
+			rate := float64(counts[t]) / float64(nonNull)
+			if rate >= piiMatchThreshold {
+				findings = append(findings, PIIFinding{Column: column, Type: t, Severity: piiSeverities[t], Matches: counts[t], MatchRate: rate})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Severity == PIISeverityHigh && findings[j].Severity != PIISeverityHigh
+	})
+	return findings
+}
+
+// classifyPII returns the first PII type a value matches. header is the normalized
+// column name, which enables the header-dependent detectors.
+func classifyPII(value, header string) (PIIType, bool) {
+	switch {
+	case isCreditCardNumber(value):
+		return PIITypeCreditCard, true
+	case isSSN(value, hasAnyHint(header, ssnHeaderHint)):
+		return PIITypeSSN, true
+	case emailPattern.MatchString(value):
+		return PIITypeEmail, true
+	case isIPAddress(value):
+		return PIITypeIPAddress, true
+	case isPhoneNumber(value, hasAnyHint(header, phoneHeaderHint)):
+		return PIITypePhone, true
+	case isNameHeader(header) && namePattern.MatchString(value):
+		return PIITypeName, true
+	}
+	return "", false
+}
+
+// isCreditCardNumber accepts 13 to 19 digits, optionally grouped by spaces or dashes,
+// that pass the Luhn check.
+func isCreditCardNumber(s string) bool {
+	digits := strings.NewReplacer(" ", "", "-", "").Replace(s)
+	if len(digits) < 13 || len(digits) > 19 || strings.Count(digits, digits[:1]) == len(digits) {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		c := digits[len(digits)-1-i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// isSSN accepts US Social Security numbers in valid ranges. Undashed numbers are only
+// accepted under an SSN header.
+func isSSN(s string, hinted bool) bool {
+	m := ssnPattern.FindStringSubmatch(s)
+	if m == nil || (!hinted && !strings.Contains(s, "-")) || (strings.Contains(s, "-") && len(s) != 11) {
+		return false
+	}
+	area, group, serial := m[1], m[2], m[3]
+	return area != "000" && area != "666" && area[0] != '9' && group != "00" && serial != "0000"
+}
+
+func isIPAddress(s string) bool {
+	return strings.ContainsAny(s, ".:") && net.ParseIP(s) != nil
+}
+
+// isPhoneNumber accepts 10 to 15 digits with optional "+", spaces, dots, dashes and
+// parentheses. Bare digit strings are only accepted under a phone header, since they
+// are more often identifiers.
+func isPhoneNumber(s string, hinted bool) bool {
+	if !phonePattern.MatchString(s) {
+		return false
+	}
+	digits := 0
+	for _, r := range s {
+		if unicode.IsDigit(r) {
+			digits++
+		}
+	}
+	if digits < 10 || digits > 15 {
+		return false
+	}
+	return hinted || digits != len(s)
+}
+
+func isNameHeader(header string) bool {
+	for _, h := range nameHeaders {
+		if header == h || (h != "name" && strings.HasSuffix(header, h)) {
+			return true
+		}
+	}
+	for _, prefix := range namePrefixes {
+		if header == prefix+"name" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyHint(header string, hints []string) bool {
+	for _, hint := range hints {
+		if strings.Contains(header, hint) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeHeader lowercases a column name and drops everything but letters and digits,
+// so "First Name" and "first_name" compare equal.
+func normalizeHeader(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,220 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyPII(t *testing.T) {
+	tests := []struct {
+		value  string
+		header string
+		want   PIIType
+		ok     bool
+	}{
+		{"4111 1111 1111 1111", "", PIITypeCreditCard, true},
+		{"4111-1111-1111-1112", "", "", false}, // Fails the Luhn check
+		{"0000000000000000", "", "", false},
+		{"123-45-6789", "", PIITypeSSN, true},
+		{"666-45-6789", "", "", false},
+		{"123456789", "ssn", PIITypeSSN, true},
+		{"jane@example.com", "", PIITypeEmail, true},
+		{"192.168.0.1", "", PIITypeIPAddress, true},
+		{"2001:db8::1", "", PIITypeIPAddress, true},
+		{"+1 (415) 555-0100", "", PIITypePhone, true},
+		{"4155550100", "", "", false},
+		{"4155550100", "phone", PIITypePhone, true},
+		{"2024-01-01", "", "", false},
+		{"Ada Lovelace", "fullname", PIITypeName, true},
+		{"Ada Lovelace", "product", "", false},
+		{"ada", "firstname", "", false},
+	}
+	for _, tc := range tests {
+		got, ok := classifyPII(tc.value, tc.header)
+		assert.Equal(t, tc.ok, ok, "%q under %q", tc.value, tc.header)
+		assert.Equal(t, tc.want, got, "%q under %q", tc.value, tc.header)
+	}
+}
+
+func TestDetectPII(t *testing.T) {
+	csvData := "id,Contact Email,First Name,card,ip,notes\n" +
+		"1,ann@example.com,Ann,4111111111111111,10.0.0.1,hello\n" +
+		"2,bob@example.com,Bob,,10.0.0.2,call me at bob@example.com\n" +
+		"3,NA,Cleo,5500 0000 0000 0004,not an ip,ok\n" +
+		"4,dan@example.com,Dan,,10.0.0.4,fine\n"
+
+	findings := detectPII(fidelityTestSample(t, csvData))
+
+	require.Len(t, findings, 4)
+	assert.Equal(t, PIIFinding{Column: "Contact Email", Type: PIITypeEmail, Severity: PIISeverityHigh, Matches: 3, MatchRate: 1}, findings[0])
+	assert.Equal(t, PIIFinding{Column: "card", Type: PIITypeCreditCard, Severity: PIISeverityHigh, Matches: 2, MatchRate: 1}, findings[1])
+	assert.Equal(t, "First Name", findings[2].Column)
+	assert.Equal(t, PIITypeName, findings[2].Type)
+	assert.Equal(t, PIISeverityMedium, findings[2].Severity)
+	assert.Equal(t, PIIFinding{Column: "ip", Type: PIITypeIPAddress, Severity: PIISeverityMedium, Matches: 3, MatchRate: 0.75}, findings[3])
+}
+
+func TestProjectService_ScanDatasetPII(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-pii"
+	bucketName := "bucket-pii"
+	memberID := "user-member"
+	viewerID := "user-viewer"
+	members := map[string]core.Role{memberID: core.RoleMember, viewerID: core.RoleViewer}
+	csvData := "email,amount\na@example.com,1\nb@example.com,2\n"
+
+	expectScan := func(mockStorage *MockStorageService) *map[string]string {
+		var stored map[string]string
+		mockStorage.On("OpenObject", mock.Anything, bucketName, "people.csv").Return(io.NopCloser(strings.NewReader(csvData)), int64(len(csvData)), nil).Once()
+		mockStorage.On("UpdateObjectMetadata", mock.Anything, bucketName, "people.csv", mock.Anything).Run(func(args mock.Arguments) {
+			stored = args.Get(3).(map[string]string)
+		}).Return(nil).Once()
+		return &stored
+	}
+
+	t.Run("Success_StoresFindingsAndClearsAcknowledgement", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "people.csv").Return(&core.ObjectMetadata{Name: "people.csv"}, nil).Once()
+		stored := expectScan(mockStorage)
+
+		scan, err := service.ScanDatasetPII(ctx, projectID, "people.csv", memberID)
+
+		require.NoError(t, err)
+		assert.Equal(t, PIIScanCompleted, scan.Status)
+		assert.Equal(t, 2, scan.RowsScanned)
+		assert.Equal(t, 1, scan.HighSeverityCount)
+		require.Len(t, scan.Findings, 1)
+		assert.Equal(t, "email", scan.Findings[0].Column)
+		assert.True(t, scan.NeedsReview())
+
+		assert.Equal(t, "", (*stored)[metaPIIAcknowledgedAt])
+		assert.NotContains(t, (*stored)[metaPIIScan], "a@example.com", "matched values are never stored")
+		decoded := piiScanFromMetadata("people.csv", *stored)
+		assert.Equal(t, scan.Findings, decoded.Findings)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_UploadSchedulesScan", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		var scheduled []func()
+		service.(*projectService).runAsync = func(task func()) { scheduled = append(scheduled, task) }
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "people.csv").Return(nil, core.ErrNotFound).Once()
+		mockStorage.On("UploadNewFile", ctx, bucketName, "people.csv", mock.Anything).Return("gs://"+bucketName+"/people.csv", nil).Once()
+		mockStorage.On("UpdateObjectMetadata", ctx, bucketName, "people.csv", map[string]string{metaUploadedBy: memberID, metaPIIScan: `{"status":"pending"}`}).Return(nil).Once()
+		mockStorage.On("ListObjects", ctx, bucketName, "").Return([]core.ObjectSummary{}, nil).Once()
+
+		_, err := service.UploadDataset(ctx, projectID, memberID, UploadDatasetRequest{Name: "people.csv", Reader: strings.NewReader(csvData)})
+		require.NoError(t, err)
+		require.Len(t, scheduled, 1)
+
+		stored := expectScan(mockStorage)
+		scheduled[0]()
+
+		assert.Contains(t, (*stored)[metaPIIScan], `"status":"completed"`)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_UnreadableDatasetRecordsFailure", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "notes.txt").Return(&core.ObjectMetadata{Name: "notes.txt"}, nil).Once()
+		mockStorage.On("OpenObject", ctx, bucketName, "notes.txt").Return(io.NopCloser(strings.NewReader("hello")), int64(5), nil).Once()
+		mockStorage.On("UpdateObjectMetadata", ctx, bucketName, "notes.txt", mock.Anything).Return(nil).Once()
+
+		scan, err := service.ScanDatasetPII(ctx, projectID, "notes.txt", memberID)
+
+		require.NoError(t, err)
+		assert.Equal(t, PIIScanFailed, scan.Status)
+		assert.Contains(t, scan.Error, "unsupported")
+		assert.True(t, scan.NeedsReview())
+	})
+
+	t.Run("Failure_ViewerCannotScan", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+
+		_, err := service.ScanDatasetPII(ctx, projectID, "people.csv", viewerID)
+
+		assert.ErrorIs(t, err, ErrProjectAccessDenied)
+	})
+}
+
+func TestProjectService_GetAndAcknowledgeDatasetPII(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-pii-ack"
+	bucketName := "bucket-pii-ack"
+	memberID := "user-member"
+	members := map[string]core.Role{memberID: core.RoleMember}
+	completed, _ := json.Marshal(PIIScan{Status: PIIScanCompleted, Findings: []PIIFinding{
+		{Column: "email", Type: PIITypeEmail, Severity: PIISeverityHigh, Matches: 2, MatchRate: 1},
+		{Column: "ip", Type: PIITypeIPAddress, Severity: PIISeverityMedium, Matches: 2, MatchRate: 1},
+	}})
+
+	setup := func(metadata map[string]string) (ProjectService, *MockStorageService) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "people.csv").Return(&core.ObjectMetadata{Name: "people.csv", Metadata: metadata}, nil).Once()
+		return service, mockStorage
+	}
+
+	t.Run("NotScanned", func(t *testing.T) {
+		service, _ := setup(nil)
+
+		scan, err := service.GetDatasetPIIScan(ctx, projectID, "people.csv", memberID)
+
+		require.NoError(t, err)
+		assert.Equal(t, PIIScanNotScanned, scan.Status)
+		assert.True(t, scan.NeedsReview())
+	})
+
+	t.Run("AcknowledgedFindings", func(t *testing.T) {
+		service, _ := setup(map[string]string{
+			metaPIIScan:           string(completed),
+			metaPIIAcknowledgedBy: memberID,
+			metaPIIAcknowledgedAt: "2025-03-01T12:00:00Z",
+		})
+
+		scan, err := service.GetDatasetPIIScan(ctx, projectID, "people.csv", memberID)
+
+		require.NoError(t, err)
+		assert.Equal(t, "people.csv", scan.DatasetID)
+		assert.Equal(t, 1, scan.HighSeverityCount)
+		assert.Equal(t, memberID, scan.AcknowledgedBy)
+		assert.Equal(t, time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC), *scan.AcknowledgedAt)
+		assert.False(t, scan.NeedsReview())
+	})
+
+	t.Run("Acknowledge_Success", func(t *testing.T) {
+		service, mockStorage := setup(map[string]string{metaPIIScan: string(completed)})
+		mockStorage.On("UpdateObjectMetadata", ctx, bucketName, "people.csv", mock.MatchedBy(func(m map[string]string) bool {
+			return m[metaPIIAcknowledgedBy] == memberID && m[metaPIIAcknowledgedAt] != ""
+		})).Return(nil).Once()
+
+		scan, err := service.AcknowledgeDatasetPII(ctx, projectID, "people.csv", memberID)
+
+		require.NoError(t, err)
+		assert.Equal(t, memberID, scan.AcknowledgedBy)
+		assert.False(t, scan.NeedsReview())
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Acknowledge_PendingScan", func(t *testing.T) {
+		service, mockStorage := setup(map[string]string{metaPIIScan: `{"status":"pending"}`})
+
+		_, err := service.AcknowledgeDatasetPII(ctx, projectID, "people.csv", memberID)
+
+		assert.ErrorIs(t, err, ErrPIIScanIncomplete)
+		mockStorage.AssertNotCalled(t, "UpdateObjectMetadata", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,395 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Profiling limits. Columns with more distinct values than maxExactDistinct switch to a
+// HyperLogLog distinct count and an approximate (Misra-Gries) frequency summary.
+const (
+	maxExactDistinct     = 10000
+	profileSampleSize    = 10000 // Numeric values kept for quantiles and histograms
+	profileTopValues     = 10
+	profileHistogramBins = 20
+)
+
+// profileCacheSuffix names the cached profile stored next to its dataset object.
+// Objects with this suffix are hidden from listings and cannot be uploaded.
+const profileCacheSuffix = ".profile.json"
+
+// profileQuantiles are the quantiles reported for numeric columns.
+var profileQuantiles = []float64{0.01, 0.05, 0.25, 0.5, 0.75, 0.95, 0.99}
+
+// ValueCount is a value and the number of rows holding it.
+type ValueCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// Quantile is the value below which the given fraction of a numeric column falls.
+type Quantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+// HistogramBin counts numeric values in [Lower, Upper); the last bin includes Upper.
+type HistogramBin struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int     `json:"count"`
+}
+
+// ColumnProfile holds the statistics computed for one column.
+type ColumnProfile struct {
+	Name                 string         `json:"name"`
+	Type                 ColumnType     `json:"type"`
+	Count                int            `json:"count"` // Non-null values
+	NullCount            int            `json:"nullCount"`
+	DistinctCount        int            `json:"distinctCount"`
+	DistinctApproximate  bool           `json:"distinctApproximate,omitempty"` // HyperLogLog estimate
+	TopValues            []ValueCount   `json:"topValues"`
+	TopValuesApproximate bool           `json:"topValuesApproximate,omitempty"` // Counts are lower bounds
+	Min                  *float64       `json:"min,omitempty"`                  // Numeric columns only
+	Max                  *float64       `json:"max,omitempty"`
+	Mean                 *float64       `json:"mean,omitempty"`
+	StdDev               *float64       `json:"stddev,omitempty"` // Sample standard deviation
+	Quantiles            []Quantile     `json:"quantiles,omitempty"`
+	Histogram            []HistogramBin `json:"histogram,omitempty"`
+	Sampled              bool           `json:"sampled,omitempty"` // Quantiles and histogram come from a sample
+}
+
+// ProfileSource identifies the version of the dataset object a profile was computed from.
+type ProfileSource struct {
+	Size    int64     `json:"size"`
+	CRC32C  uint32    `json:"crc32c"`
+	Created time.Time `json:"created"` // Changes whenever the object is overwritten
+}
+
+func (p ProfileSource) matches(other ProfileSource) bool {
+	return p.Size == other.Size && p.CRC32C == other.CRC32C && p.Created.Equal(other.Created)
+}
+
+// DatasetProfile holds per-column statistics over a whole dataset.
+type DatasetProfile struct {
+	DatasetID  string          `json:"datasetId"`
+	RowCount   int             `json:"rowCount"`
+	Columns    []ColumnProfile `json:"columns"`
+	Source     ProfileSource   `json:"source"`
+	ProfiledAt time.Time       `json:"profiledAt"`
+	Cached     bool            `json:"cached"` // Served from the stored profile
+}
+
+// columnProfiler accumulates the statistics of one column in bounded memory.
+type columnProfiler struct {
+	types       *columnStats // Type inference over every value
+	count       int
+	nulls       int
+	counts      map[string]int // Exact frequencies, then a Misra-Gries summary
+	approximate bool
+	hll         *hyperLogLog // Set once counts stops being exact
+
+	numeric  int
+	mean, m2 float64 // Welford's running mean and sum of squared deviations
+	min, max float64
+	sample   []float64 // Reservoir of numeric values
+	rng      *rand.Rand
+}
+
+func newColumnProfiler() *columnProfiler {
+	return &columnProfiler{
+		types:  newColumnStats(),
+		counts: make(map[string]int),
+		min:    math.Inf(1),
+		max:    math.Inf(-1),
+		rng:    rand.New(rand.NewSource(1)), // Fixed seed so a profile is reproducible
+	}
+}
+
+func (p *columnProfiler) add(value interface{}) {
+	p.types.add(value)
+	if isNullValue(value) {
+		p.nulls++
+		return
+	}
+	p.count++
+	p.countValue(formatValue(value))
+	if f, ok := toFloat(value); ok && !math.IsNaN(f) && !math.IsInf(f, 0) {
+		p.addNumber(f)
+	}
+}
+
+func (p *columnProfiler) countValue(key string) {
+	if p.hll != nil {
+		p.hll.add(key)
+	}
+	if _, ok := p.counts[key]; ok || len(p.counts) < maxExactDistinct {
+		p.counts[key]++
+		return
+	}
+
+	// A new value beyond the exact limit: estimate distinct values from here on, seeded
+	// with every value seen so far, and keep a Misra-Gries summary of the frequencies
+	if p.hll == nil {
+		p.hll = newHyperLogLog()
+		for seen := range p.counts {
+			p.hll.add(seen)
+		}
+		p.hll.add(key)
+	}
+	p.approximate = true
+	for seen := range p.counts {
+		if p.counts[seen]--; p.counts[seen] == 0 {
+			delete(p.counts, seen)
+		}
+	}
+}
+
+func (p *columnProfiler) addNumber(f float64) {
+	p.numeric++
+	delta := f - p.mean
+	p.mean += delta / float64(p.numeric)
+	p.m2 += delta * (f - p.mean)
+	p.min = math.Min(p.min, f)
+	p.max = math.Max(p.max, f)
+
+	if len(p.sample) < profileSampleSize {
+		p.sample = append(p.sample, f)
+	} else if j := p.rng.Intn(p.numeric); j < profileSampleSize {
+		p.sample[j] = f
+	}
+}
+
+func (p *columnProfiler) result(name string) ColumnProfile {
+	inferred := p.types.result(name)
+	col := ColumnProfile{
+		Name:                 name,
+		Type:                 inferred.Type,
+		Count:                p.count,
+		NullCount:            p.nulls,
+		DistinctCount:        len(p.counts),
+		TopValues:            topValues(p.counts, profileTopValues),
+		TopValuesApproximate: p.approximate,
+	}
+	if p.hll != nil {
+		col.DistinctCount, col.DistinctApproximate = p.hll.estimate(), true
+	}
+
+	if (col.Type == ColumnTypeInteger || col.Type == ColumnTypeFloat) && p.numeric > 0 {
+		min, max, mean := p.min, p.max, p.mean
+		stddev := 0.0
+		if p.numeric > 1 {
+			stddev = math.Sqrt(p.m2 / float64(p.numeric-1))
+		}
+		col.Min, col.Max, col.Mean, col.StdDev = &min, &max, &mean, &stddev
+
+		sort.Float64s(p.sample)
+		col.Quantiles = quantiles(p.sample, profileQuantiles)
+		col.Histogram = histogram(p.sample, min, max, p.numeric, profileHistogramBins)
+		col.Sampled = p.numeric > len(p.sample)
+	}
+	return col
+}
+
+// topValues returns the k most frequent values, ties broken by value.
+func topValues(counts map[string]int, k int) []ValueCount {
+	values := make([]ValueCount, 0, len(counts))
+	for value, count := range counts {
+		values = append(values, ValueCount{Value: value, Count: count})
+	}
+	sort.Slice(values, func(i, j int) bool {
+		if values[i].Count != values[j].Count {
+			return values[i].Count > values[j].Count
+		}
+		return values[i].Value < values[j].Value
+	})
+	if len(values) > k {
+		values = values[:k]
+	}
+	return values
+}
+
+// quantiles interpolates linearly between the closest ranks of a sorted sample.
+func quantiles(sorted []float64, qs []float64) []Quantile {
+	result := make([]Quantile, len(qs))
+	for i, q := range qs {
+		pos := q * float64(len(sorted)-1)
+		lower := int(math.Floor(pos))
+		upper := int(math.Ceil(pos))
+		value := sorted[lower] + (sorted[upper]-sorted[lower])*(pos-float64(lower))
+		result[i] = Quantile{Quantile: q, Value: value}
+	}
+	return result
+}
+
+// histogram buckets a sample into equal-width bins over [min, max], scaling the counts
+// up to total when the sample holds only part of the values.
+func histogram(sample []float64, min, max float64, total, bins int) []HistogramBin {
+	if min == max {
+		return []HistogramBin{{Lower: min, Upper: max, Count: total}}
+	}
+	width := (max - min) / float64(bins)
+	counts := make([]int, bins)
+	for _, v := range sample {
+		bin := int((v - min) / width)
+		if bin >= bins {
+			bin = bins - 1 // max itself
+		}
+		counts[bin]++
+	}
+	scale := float64(total) / float64(len(sample))
+	result := make([]HistogramBin, bins)
+	for i := range result {
+		result[i] = HistogramBin{
+			Lower: min + float64(i)*width,
+			Upper: min + float64(i+1)*width,
+			Count: int(math.Round(float64(counts[i]) * scale)),
+		}
+	}
+	result[bins-1].Upper = max
+	return result
+}
+
+// profileDataset reads every row and profiles every column seen.
+func profileDataset(rows rowReader) (*DatasetProfile, error) {
+	profilers := make(map[string]*columnProfiler)
+	floatColumns := declaredFloatColumns(rows)
+	profile := &DatasetProfile{}
+	for {
+		row, err := rows.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		profile.RowCount++
+		for _, column := range rows.Columns() {
+			p, ok := profilers[column]
+			if !ok {
+				p = newColumnProfiler()
+				p.nulls = profile.RowCount - 1 // Absent from earlier JSON rows
+				p.types.nulls = p.nulls
+				p.types.isInt = !floatColumns[column]
+				profilers[column] = p
+			}
+			p.add(row[column]) // Missing keys count as null
+		}
+	}
+
+	profile.Columns = make([]ColumnProfile, 0, len(rows.Columns()))
+	for _, column := range rows.Columns() {
+		p, ok := profilers[column]
+		if !ok {
+			p = newColumnProfiler() // Header-only CSV
+		}
+		profile.Columns = append(profile.Columns, p.result(column))
+	}
+	return profile, nil
+}
+
+// GetDatasetProfile returns per-column statistics over a whole dataset, requiring Viewer
+// role. Profiles are stored next to the dataset as "<name>.profile.json" and reused until
+// the dataset is overwritten; refresh recomputes the profile regardless.
+func (s *projectService) GetDatasetProfile(ctx context.Context, projectID string, datasetID string, callerID string, refresh bool) (*DatasetProfile, error) {
+	log := logger.Logger.With(zap.String("projectID", projectID), zap.String("datasetID", datasetID), zap.String("callerID", callerID))
+
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleViewer, false)
+	if err != nil {
+		if errors.Is(err, ErrProjectStorageNotConfigured) {
+			return nil, fmt.Errorf("project %s storage is not configured", projectID)
+		}
+		return nil, err
+	}
+	bucketName := project.Storage.BucketName
+
+	// 1. Identify the current version of the dataset
+	obj, err := s.storageSvc.GetObjectMetadata(ctx, bucketName, datasetID)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrDatasetNotFound, datasetID)
+		}
+		return nil, fmt.Errorf("failed to get dataset metadata: %w", err)
+	}
+	source := ProfileSource{Size: obj.Size, CRC32C: obj.CRC32C, Created: obj.Created}
+	cacheName := datasetID + profileCacheSuffix
+
+	// 2. Serve the stored profile if it was computed from this version
+	if !refresh {
+		var cached DatasetProfile
+		if s.readStoredJSON(ctx, bucketName, cacheName, &cached) && cached.Source.matches(source) {
+			cached.Cached = true
+			return &cached, nil
+		}
+	}
+
+	// 3. Profile the whole dataset
+	rows, closeRows, err := s.openDatasetRows(ctx, bucketName, datasetID)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows()
+
+	started := time.Now()
+	profile, err := profileDataset(rows)
+	if err != nil {
+		log.Error("Failed to profile dataset", zap.Error(err))
+		return nil, fmt.Errorf("failed to parse dataset content (type: %s): %w", getExtension(datasetID), err)
+	}
+	profile.DatasetID = datasetID
+	profile.Source = source
+	profile.ProfiledAt = time.Now().UTC()
+
+	// 4. Store it for next time; a failed write only costs a recomputation
+	encoded, err := json.Marshal(profile)
+	if err == nil {
+		_, err = s.storageSvc.UploadFile(ctx, bucketName, cacheName, bytes.NewReader(encoded))
+	}
+	if err != nil {
+		log.Warn("Failed to cache dataset profile", zap.Error(err))
+	}
+
+	log.Info("Profiled dataset",
+		zap.Int("rows", profile.RowCount),
+		zap.Int("columns", len(profile.Columns)),
+		zap.Duration("duration", time.Since(started)),
+	)
+	return profile, nil
+}
+
+// readStoredJSON decodes a stored profile or report into v, reporting false if there is
+// none or it is unreadable.
+func (s *projectService) readStoredJSON(ctx context.Context, bucketName, objectName string, v interface{}) bool {
+	data, err := s.storageSvc.ReadObject(ctx, bucketName, objectName)
+	if err != nil {
+		if !errors.Is(err, core.ErrNotFound) {
+			logger.Logger.Warn("Failed to read stored dataset analysis", zap.Error(err), zap.String("objectName", objectName))
+		}
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		logger.Logger.Warn("Ignoring corrupt stored dataset analysis", zap.Error(err), zap.String("objectName", objectName))
+		return false
+	}
+	return true
+}
+
+// deleteCachedProfile removes a dataset's stored profile, if any.
+func (s *projectService) deleteCachedProfile(ctx context.Context, bucketName, datasetID string) {
+	err := s.storageSvc.DeleteObject(ctx, bucketName, datasetID+profileCacheSuffix)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		logger.Logger.Warn("Failed to delete cached dataset profile", zap.Error(err), zap.String("datasetID", datasetID))
+	}
+}
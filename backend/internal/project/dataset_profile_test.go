@@ -0,0 +1,210 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func profileTestRows(t *testing.T, ext, data string) *DatasetProfile {
+	t.Helper()
+	rows, err := newRowReader(ext, strings.NewReader(data))
+	require.NoError(t, err)
+	profile, err := profileDataset(rows)
+	require.NoError(t, err)
+	return profile
+}
+
+func TestProfileDataset(t *testing.T) {
+	csvData := "age,plan,note\n" +
+		"10,free,\n" +
+		"20,pro,a\n" +
+		"30,free,NA\n" +
+		"40,free,b\n" +
+		",team,c\n"
+
+	profile := profileTestRows(t, ".csv", csvData)
+
+	assert.Equal(t, 5, profile.RowCount)
+	require.Len(t, profile.Columns, 3)
+
+	age := profile.Columns[0]
+	assert.Equal(t, ColumnTypeInteger, age.Type)
+	assert.Equal(t, 4, age.Count)
+	assert.Equal(t, 1, age.NullCount)
+	assert.Equal(t, 4, age.DistinctCount)
+	assert.False(t, age.DistinctApproximate)
+	require.NotNil(t, age.Mean)
+	assert.Equal(t, 10.0, *age.Min)
+	assert.Equal(t, 40.0, *age.Max)
+	assert.Equal(t, 25.0, *age.Mean)
+	assert.InDelta(t, 12.9099, *age.StdDev, 1e-4)
+	assert.Contains(t, age.Quantiles, Quantile{Quantile: 0.5, Value: 25})
+	assert.Contains(t, age.Quantiles, Quantile{Quantile: 0.25, Value: 17.5})
+	require.Len(t, age.Histogram, profileHistogramBins)
+	assert.Equal(t, 10.0, age.Histogram[0].Lower)
+	assert.Equal(t, 40.0, age.Histogram[profileHistogramBins-1].Upper)
+	binTotal := 0
+	for _, bin := range age.Histogram {
+		binTotal += bin.Count
+	}
+	assert.Equal(t, 4, binTotal)
+	assert.False(t, age.Sampled)
+
+	plan := profile.Columns[1]
+	assert.Equal(t, []ValueCount{{"free", 3}, {"pro", 1}, {"team", 1}}, plan.TopValues)
+	assert.Nil(t, plan.Mean, "numeric statistics are only reported for numeric columns")
+	assert.Empty(t, plan.Histogram)
+
+	note := profile.Columns[2]
+	assert.Equal(t, 2, note.NullCount)
+	assert.Equal(t, 3, note.DistinctCount)
+}
+
+func TestProfileDataset_LargeColumns(t *testing.T) {
+	const rows = 30000
+	var sb strings.Builder
+	sb.WriteString("id,bucket\n")
+	for i := 0; i < rows; i++ {
+		fmt.Fprintf(&sb, "%d,%d\n", i, i%7)
+	}
+
+	profile := profileTestRows(t, ".csv", sb.String())
+
+	id := profile.Columns[0]
+	assert.True(t, id.DistinctApproximate)
+	assert.InEpsilon(t, rows, id.DistinctCount, 0.03)
+	assert.True(t, id.TopValuesApproximate)
+	assert.True(t, id.Sampled)
+	assert.Equal(t, float64(rows-1), *id.Max, "min and max are exact")
+	assert.Equal(t, float64(rows-1)/2, *id.Mean)
+	binTotal := 0
+	for _, bin := range id.Histogram {
+		binTotal += bin.Count
+	}
+	assert.InEpsilon(t, rows, binTotal, 0.01, "sampled bins are scaled to the full count")
+	median := id.Quantiles[3]
+	assert.Equal(t, 0.5, median.Quantile)
+	assert.InEpsilon(t, rows/2, median.Value, 0.05)
+
+	bucket := profile.Columns[1]
+	assert.False(t, bucket.DistinctApproximate)
+	assert.Equal(t, 7, bucket.DistinctCount)
+	assert.Equal(t, ValueCount{Value: "0", Count: 4286}, bucket.TopValues[0])
+}
+
+func TestHyperLogLog(t *testing.T) {
+	for _, n := range []int{100, 5000, 200000} {
+		hll := newHyperLogLog()
+		for i := 0; i < n; i++ {
+			hll.add(fmt.Sprintf("value-%d", i))
+			hll.add(fmt.Sprintf("value-%d", i)) // Duplicates do not count
+		}
+		assert.InEpsilon(t, n, hll.estimate(), 0.03, "n=%d", n)
+	}
+}
+
+func TestProjectService_GetDatasetProfile(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-profile"
+	bucketName := "bucket-profile"
+	viewerID := "user-viewer"
+	members := map[string]core.Role{viewerID: core.RoleViewer}
+	csvData := "n\n1\n2\n3\n"
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	object := &core.ObjectMetadata{Name: "nums.csv", Size: int64(len(csvData)), CRC32C: 42, Created: created}
+	source := ProfileSource{Size: object.Size, CRC32C: 42, Created: created}
+
+	setup := func(t *testing.T) (ProjectService, *MockStorageService) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "nums.csv").Return(object, nil).Once()
+		return service, mockStorage
+	}
+	expectProfiling := func(mockStorage *MockStorageService) *[]byte {
+		var stored []byte
+		mockStorage.On("OpenObject", ctx, bucketName, "nums.csv").Return(io.NopCloser(strings.NewReader(csvData)), int64(len(csvData)), nil).Once()
+		mockStorage.On("UploadFile", ctx, bucketName, "nums.csv.profile.json", mock.Anything).Run(func(args mock.Arguments) {
+			stored, _ = io.ReadAll(args.Get(3).(io.Reader))
+		}).Return("gs://bucket-profile/nums.csv.profile.json", nil).Once()
+		return &stored
+	}
+
+	t.Run("Success_ComputesAndStores", func(t *testing.T) {
+		service, mockStorage := setup(t)
+		mockStorage.On("ReadObject", ctx, bucketName, "nums.csv.profile.json").Return(nil, core.ErrNotFound).Once()
+		stored := expectProfiling(mockStorage)
+
+		profile, err := service.GetDatasetProfile(ctx, projectID, "nums.csv", viewerID, false)
+
+		require.NoError(t, err)
+		assert.False(t, profile.Cached)
+		assert.Equal(t, 3, profile.RowCount)
+		assert.Equal(t, source, profile.Source)
+		assert.Equal(t, 2.0, *profile.Columns[0].Mean)
+
+		var cached DatasetProfile
+		require.NoError(t, json.Unmarshal(*stored, &cached))
+		assert.Equal(t, source, cached.Source)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_ServesStoredProfile", func(t *testing.T) {
+		service, mockStorage := setup(t)
+		stored, _ := json.Marshal(DatasetProfile{DatasetID: "nums.csv", RowCount: 3, Source: source})
+		mockStorage.On("ReadObject", ctx, bucketName, "nums.csv.profile.json").Return(stored, nil).Once()
+
+		profile, err := service.GetDatasetProfile(ctx, projectID, "nums.csv", viewerID, false)
+
+		require.NoError(t, err)
+		assert.True(t, profile.Cached)
+		assert.Equal(t, 3, profile.RowCount)
+		mockStorage.AssertNotCalled(t, "OpenObject", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_RecomputesWhenDatasetChanged", func(t *testing.T) {
+		service, mockStorage := setup(t)
+		stale := source
+		stale.Created = created.Add(-time.Hour) // Profiled before the object was overwritten
+		stored, _ := json.Marshal(DatasetProfile{DatasetID: "nums.csv", RowCount: 99, Source: stale})
+		mockStorage.On("ReadObject", ctx, bucketName, "nums.csv.profile.json").Return(stored, nil).Once()
+		expectProfiling(mockStorage)
+
+		profile, err := service.GetDatasetProfile(ctx, projectID, "nums.csv", viewerID, false)
+
+		require.NoError(t, err)
+		assert.False(t, profile.Cached)
+		assert.Equal(t, 3, profile.RowCount)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_RefreshSkipsStoredProfile", func(t *testing.T) {
+		service, mockStorage := setup(t)
+		expectProfiling(mockStorage)
+
+		profile, err := service.GetDatasetProfile(ctx, projectID, "nums.csv", viewerID, true)
+
+		require.NoError(t, err)
+		assert.False(t, profile.Cached)
+		mockStorage.AssertNotCalled(t, "ReadObject", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_NotFound", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "missing.csv").Return(nil, core.ErrNotFound).Once()
+
+		_, err := service.GetDatasetProfile(ctx, projectID, "missing.csv", viewerID, false)
+
+		assert.ErrorIs(t, err, ErrDatasetNotFound)
+	})
+}
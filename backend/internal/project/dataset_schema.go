@@ -0,0 +1,424 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Schema inference sample sizes.
+const (
+	DefaultSchemaSampleRows = 1000
+	MaxSchemaSampleRows     = 100000
+)
+
+// Categorical detection: a text column is categorical when it has at most
+// maxCategoricalValues distinct values and each value repeats on average.
+const (
+	maxCategoricalValues = 20
+	maxTrackedDistinct   = 1000 // Distinct counts above this are reported as a lower bound
+)
+
+// ColumnType is an inferred semantic column type. The values double as the "type" of
+// a job config schema field.
+type ColumnType string
+
+const (
+	ColumnTypeInteger     ColumnType = "integer"
+	ColumnTypeFloat       ColumnType = "float"
+	ColumnTypeBoolean     ColumnType = "boolean"
+	ColumnTypeDate        ColumnType = "date"
+	ColumnTypeDateTime    ColumnType = "datetime"
+	ColumnTypeCategorical ColumnType = "categorical"
+	ColumnTypeText        ColumnType = "text"
+	ColumnTypeEmail       ColumnType = "email"
+	ColumnTypeUUID        ColumnType = "uuid"
+	ColumnTypeUnknown     ColumnType = "unknown" // Every sampled value was null
+)
+
+// InferredColumn describes a column detected in a dataset sample.
+type InferredColumn struct {
+	Name           string     `json:"name"`
+	Type           ColumnType `json:"type"`
+	Nullable       bool       `json:"nullable"`
+	Format         string     `json:"format,omitempty"` // Go time layout for dates, "json" for nested values
+	NullCount      int        `json:"nullCount"`
+	DistinctCount  int        `json:"distinctCount"`
+	DistinctCapped bool       `json:"distinctCapped,omitempty"` // DistinctCount is a lower bound
+	Values         []string   `json:"values,omitempty"`         // Categories, for categorical columns
+	Min            *float64   `json:"min,omitempty"`            // Numeric columns only
+	Max            *float64   `json:"max,omitempty"`
+}
+
+// JobSchemaField is the shape of an entry in a job config's "schema" array.
+type JobSchemaField struct {
+	Name     string     `json:"name"`
+	Type     ColumnType `json:"type"`
+	Nullable bool       `json:"nullable,omitempty"`
+	Format   string     `json:"format,omitempty"`
+	Values   []string   `json:"values,omitempty"`
+}
+
+// DatasetSchema is the result of schema inference over a dataset sample.
+type DatasetSchema struct {
+	DatasetID       string           `json:"datasetId"`
+	SampledRows     int              `json:"sampledRows"`
+	Complete        bool             `json:"complete"` // The whole dataset fit in the sample
+	Columns         []InferredColumn `json:"columns"`
+	JobConfigSchema []JobSchemaField `json:"jobConfigSchema"` // Ready to use as a job config "schema" section
+}
+
+// Date and date-time layouts tried in order; the first that parses every value wins.
+var (
+	dateLayouts     = []string{"2006-01-02", "2006/01/02", "01/02/2006", "02.01.2006", "Jan 2, 2006", "02-Jan-2006"}
+	dateTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "01/02/2006 15:04:05", time.RFC1123Z, time.RFC1123}
+)
+
+var (
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s.]+$`)
+	uuidPattern  = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// isNullToken reports whether a textual cell represents a missing value.
+func isNullToken(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "null", "nil", "none", "na", "n/a", "nan":
+		return true
+	}
+	return false
+}
+
+func parseBoolToken(s string) bool {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "t", "f", "y", "n":
+		return true
+	}
+	return false
+}
+
+// columnStats accumulates evidence for one column. Each flag starts true and is cleared
+// by the first non-null value that contradicts it.
+type columnStats struct {
+	nonNull, nulls int
+	isInt, isFloat bool
+	isBool         bool
+	isEmail        bool
+	isUUID         bool
+	isNested       bool
+	dateLayouts    []string // Layouts still consistent with every value
+	dateTimeLayout []string
+	distinct       map[string]struct{}
+	capped         bool
+	min, max       float64
+}
+
+func newColumnStats() *columnStats {
+	return &columnStats{
+		isInt: true, isFloat: true, isBool: true, isEmail: true, isUUID: true,
+		dateLayouts:    append([]string(nil), dateLayouts...),
+		dateTimeLayout: append([]string(nil), dateTimeLayouts...),
+		distinct:       make(map[string]struct{}),
+		min:            math.Inf(1),
+		max:            math.Inf(-1),
+	}
+}
+
+func (c *columnStats) add(value interface{}) {
+	switch v := value.(type) {
+	case nil:
+		c.nulls++
+		return
+	case string:
+		if isNullToken(v) {
+			c.nulls++
+			return
+		}
+		c.addText(strings.TrimSpace(v))
+		return
+	case bool:
+		c.nonNull++
+		c.isInt, c.isFloat, c.isEmail, c.isUUID = false, false, false, false
+		c.dateLayouts, c.dateTimeLayout = nil, nil
+		c.track(strconv.FormatBool(v))
+		return
+	case time.Time:
+		c.nonNull++
+		c.isInt, c.isFloat, c.isBool, c.isEmail, c.isUUID = false, false, false, false, false
+		c.dateLayouts = nil
+		c.dateTimeLayout = []string{time.RFC3339Nano}
+		c.track(v.Format(time.RFC3339Nano))
+		return
+	case map[string]interface{}, []interface{}:
+		c.nonNull++
+		c.isNested = true
+		c.track(fmt.Sprint(v))
+		return
+	}
+	if f, ok := toFloat(value); ok { // Typed numbers from JSON or Parquet
+		c.nonNull++
+		c.isBool, c.isEmail, c.isUUID = false, false, false
+		c.dateLayouts, c.dateTimeLayout = nil, nil
+		if f != math.Trunc(f) || math.IsInf(f, 0) || math.IsNaN(f) {
+			c.isInt = false
+		}
+		c.observeNumber(f)
+		c.track(formatValue(value))
+		return
+	}
+	c.addText(formatValue(value))
+}
+
+func (c *columnStats) addText(s string) {
+	c.nonNull++
+	c.track(s)
+	if c.isInt {
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+			c.isInt = false
+		}
+	}
+	if c.isFloat {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			c.isFloat = false
+		} else {
+			c.observeNumber(f)
+		}
+	}
+	if c.isBool && !parseBoolToken(s) {
+		c.isBool = false
+	}
+	if c.isEmail && !emailPattern.MatchString(s) {
+		c.isEmail = false
+	}
+	if c.isUUID && !uuidPattern.MatchString(s) {
+		c.isUUID = false
+	}
+	c.dateLayouts = keepParsingLayouts(c.dateLayouts, s)
+	c.dateTimeLayout = keepParsingLayouts(c.dateTimeLayout, s)
+}
+
+func (c *columnStats) observeNumber(f float64) {
+	c.min = math.Min(c.min, f)
+	c.max = math.Max(c.max, f)
+}
+
+func (c *columnStats) track(s string) {
+	if c.capped {
+		return
+	}
+	c.distinct[s] = struct{}{}
+	if len(c.distinct) > maxTrackedDistinct {
+		c.capped = true
+	}
+}
+
+// keepParsingLayouts returns the layouts that parse s.
+func keepParsingLayouts(layouts []string, s string) []string {
+	kept := layouts[:0]
+	for _, layout := range layouts {
+		if _, err := time.Parse(layout, s); err == nil {
+			kept = append(kept, layout)
+		}
+	}
+	return kept
+}
+
+// result picks the most specific type consistent with every sampled value.
+func (c *columnStats) result(name string) InferredColumn {
+	col := InferredColumn{
+		Name:           name,
+		Nullable:       c.nulls > 0,
+		NullCount:      c.nulls,
+		DistinctCount:  len(c.distinct),
+		DistinctCapped: c.capped,
+	}
+	switch {
+	case c.nonNull == 0:
+		col.Type = ColumnTypeUnknown
+	case c.isNested:
+		col.Type, col.Format = ColumnTypeText, "json"
+	case c.isBool:
+		col.Type = ColumnTypeBoolean
+	case c.isInt:
+		col.Type = ColumnTypeInteger
+	case c.isFloat:
+		col.Type = ColumnTypeFloat
+	case len(c.dateLayouts) > 0:
+		col.Type, col.Format = ColumnTypeDate, c.dateLayouts[0]
+	case len(c.dateTimeLayout) > 0:
+		col.Type, col.Format = ColumnTypeDateTime, c.dateTimeLayout[0]
+	case c.isUUID:
+		col.Type = ColumnTypeUUID
+	case c.isEmail:
+		col.Type = ColumnTypeEmail
+	case !c.capped && len(c.distinct) <= maxCategoricalValues && c.nonNull >= 2*len(c.distinct):
+		col.Type = ColumnTypeCategorical
+		for value := range c.distinct {
+			col.Values = append(col.Values, value)
+		}
+		sort.Strings(col.Values)
+	default:
+		col.Type = ColumnTypeText
+	}
+	if col.Type == ColumnTypeInteger || col.Type == ColumnTypeFloat {
+		min, max := c.min, c.max
+		col.Min, col.Max = &min, &max
+	}
+	return col
+}
+
+// declaredFloatColumns returns the columns a typed format declares as floating point, so
+// whole-valued samples from them are not mistaken for integers.
+func declaredFloatColumns(rows rowReader) map[string]bool {
+	typed, ok := rows.(interface{ Schema() []DatasetColumn })
+	if !ok {
+		return nil
+	}
+	floats := make(map[string]bool)
+	for _, column := range typed.Schema() {
+		if column.Type == "FLOAT" || column.Type == "DOUBLE" {
+			floats[column.Name] = true
+		}
+	}
+	return floats
+}
+
+// inferSchema samples up to maxRows rows and infers a type for every column seen.
+func inferSchema(rows rowReader, maxRows int) (*DatasetSchema, error) {
+	stats := make(map[string]*columnStats)
+	floatColumns := declaredFloatColumns(rows)
+	schema := &DatasetSchema{}
+	for schema.SampledRows < maxRows {
+		row, err := rows.Next()
+		if err == io.EOF {
+			schema.Complete = true
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		schema.SampledRows++
+		for _, column := range rows.Columns() {
+			st, ok := stats[column]
+			if !ok {
+				st = newColumnStats()
+				st.nulls = schema.SampledRows - 1 // Absent from earlier JSON rows
+				st.isInt = !floatColumns[column]
+				stats[column] = st
+			}
+			st.add(row[column]) // Missing keys count as null
+		}
+	}
+	if !schema.Complete {
+		if _, err := rows.Next(); err == io.EOF {
+			schema.Complete = true
+		}
+	}
+
+	schema.Columns = make([]InferredColumn, 0, len(rows.Columns()))
+	schema.JobConfigSchema = make([]JobSchemaField, 0, len(rows.Columns()))
+	for _, column := range rows.Columns() {
+		st, ok := stats[column]
+		if !ok {
+			st = newColumnStats() // Header-only CSV
+		}
+		col := st.result(column)
+		schema.Columns = append(schema.Columns, col)
+		schema.JobConfigSchema = append(schema.JobConfigSchema, JobSchemaField{
+			Name:     col.Name,
+			Type:     col.Type,
+			Nullable: col.Nullable,
+			Format:   col.Format,
+			Values:   col.Values,
+		})
+	}
+	return schema, nil
+}
+
+// GetDatasetSchema infers column types from the first sampleRows rows of a dataset.
+func (s *projectService) GetDatasetSchema(ctx context.Context, projectID string, datasetID string, callerID string, sampleRows int) (*DatasetSchema, error) {
+	log := logger.Logger.With(zap.String("projectID", projectID), zap.String("datasetID", datasetID), zap.String("callerID", callerID))
+	if sampleRows < 0 {
+		return nil, fmt.Errorf("%w: sampleRows cannot be negative", ErrInvalidDatasetQuery)
+	}
+	if sampleRows == 0 {
+		sampleRows = DefaultSchemaSampleRows
+	}
+	if sampleRows > MaxSchemaSampleRows {
+		sampleRows = MaxSchemaSampleRows
+	}
+
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleViewer, false)
+	if err != nil {
+		if errors.Is(err, ErrProjectStorageNotConfigured) {
+			return nil, fmt.Errorf("project %s storage is not configured", projectID)
+		}
+		return nil, err
+	}
+
+	rows, closeRows, err := s.openDatasetRows(ctx, project.Storage.BucketName, datasetID)
+	if err != nil {
+		return nil, err
+	}
+	defer closeRows()
+
+	schema, err := inferSchema(rows, sampleRows)
+	if err != nil {
+		log.Error("Failed to infer dataset schema", zap.Error(err))
+		return nil, fmt.Errorf("failed to parse dataset content (type: %s): %w", getExtension(datasetID), err)
+	}
+	schema.DatasetID = datasetID
+
+	log.Info("Inferred dataset schema", zap.Int("columns", len(schema.Columns)), zap.Int("sampledRows", schema.SampledRows))
+	return schema, nil
+}
+
+// openDatasetRows opens any supported dataset format as a rowReader over every column.
+// The returned func releases the underlying reader.
+func (s *projectService) openDatasetRows(ctx context.Context, bucketName, objectName string) (rowReader, func(), error) {
+	notFound := func(err error) error {
+		if errors.Is(err, core.ErrNotFound) {
+			return fmt.Errorf("dataset file '%s' not found in project storage: %w", objectName, ErrDatasetNotFound)
+		}
+		return fmt.Errorf("failed to read dataset content: %w", err)
+	}
+
+	fileExt := getExtension(objectName)
+	if fileExt == ".parquet" {
+		readerAt, size, err := s.storageSvc.OpenObjectReaderAt(ctx, bucketName, objectName)
+		if err != nil {
+			return nil, nil, notFound(err)
+		}
+		file, err := openParquetFile(readerAt, size)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse dataset content (type: %s): %w", fileExt, err)
+		}
+		rows, err := newParquetRowReader(file, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		return rows, func() { rows.Close() }, nil
+	}
+
+	reader, _, err := s.storageSvc.OpenObject(ctx, bucketName, objectName)
+	if err != nil {
+		return nil, nil, notFound(err)
+	}
+	rows, err := newRowReader(fileExt, reader)
+	if err != nil {
+		reader.Close()
+		return nil, nil, fmt.Errorf("failed to parse dataset content (type: %s): %w", fileExt, err)
+	}
+	return rows, func() { reader.Close() }, nil
+}
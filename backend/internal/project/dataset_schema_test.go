@@ -0,0 +1,212 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func inferTestSchema(t *testing.T, ext, data string, maxRows int) *DatasetSchema {
+	t.Helper()
+	rows, err := newRowReader(ext, strings.NewReader(data))
+	require.NoError(t, err)
+	schema, err := inferSchema(rows, maxRows)
+	require.NoError(t, err)
+	return schema
+}
+
+func columnByName(t *testing.T, schema *DatasetSchema, name string) InferredColumn {
+	t.Helper()
+	for _, col := range schema.Columns {
+		if col.Name == name {
+			return col
+		}
+	}
+	t.Fatalf("column %q not inferred", name)
+	return InferredColumn{}
+}
+
+func TestInferSchema_CSV(t *testing.T) {
+	csvData := "id,price,active,signup,last_seen,email,account,tier,bio,notes\n" +
+		"1,9.99,true,2024-01-31,2024-01-31T10:00:00Z,a@example.com,0b7e2c1a-5d1f-4a6e-9c2b-1f0e3d4c5b6a,gold,Loves hiking,\n" +
+		"2,10,false,2024-02-01,2024-02-01T11:30:00Z,b@example.org,6f1d8e2a-3b4c-4d5e-8f9a-0b1c2d3e4f5a,silver,Plays chess,NA\n" +
+		"3,,yes,2024-02-02,2024-02-02T12:45:00+02:00,c@example.net,9a8b7c6d-5e4f-4a3b-8c2d-1e0f9a8b7c6d,gold,Reads a lot,null\n" +
+		"4,12.5,no,2024-02-03,2024-02-03T13:00:00Z,d@example.com,1a2b3c4d-5e6f-4a7b-8c9d-0e1f2a3b4c5d,silver,Cooks dinner,\n"
+
+	schema := inferTestSchema(t, ".csv", csvData, 100)
+
+	assert.Equal(t, 4, schema.SampledRows)
+	assert.True(t, schema.Complete)
+	require.Len(t, schema.Columns, 10)
+
+	id := columnByName(t, schema, "id")
+	assert.Equal(t, ColumnTypeInteger, id.Type)
+	assert.False(t, id.Nullable)
+	require.NotNil(t, id.Min)
+	assert.Equal(t, 1.0, *id.Min)
+	assert.Equal(t, 4.0, *id.Max)
+
+	price := columnByName(t, schema, "price")
+	assert.Equal(t, ColumnTypeFloat, price.Type)
+	assert.True(t, price.Nullable)
+	assert.Equal(t, 1, price.NullCount)
+
+	assert.Equal(t, ColumnTypeBoolean, columnByName(t, schema, "active").Type)
+
+	signup := columnByName(t, schema, "signup")
+	assert.Equal(t, ColumnTypeDate, signup.Type)
+	assert.Equal(t, "2006-01-02", signup.Format)
+
+	lastSeen := columnByName(t, schema, "last_seen")
+	assert.Equal(t, ColumnTypeDateTime, lastSeen.Type)
+	assert.NotEmpty(t, lastSeen.Format)
+
+	assert.Equal(t, ColumnTypeEmail, columnByName(t, schema, "email").Type)
+	assert.Equal(t, ColumnTypeUUID, columnByName(t, schema, "account").Type)
+
+	tier := columnByName(t, schema, "tier")
+	assert.Equal(t, ColumnTypeCategorical, tier.Type)
+	assert.Equal(t, []string{"gold", "silver"}, tier.Values)
+
+	assert.Equal(t, ColumnTypeText, columnByName(t, schema, "bio").Type)
+
+	notes := columnByName(t, schema, "notes")
+	assert.Equal(t, ColumnTypeUnknown, notes.Type)
+	assert.Equal(t, 4, notes.NullCount)
+
+	require.Len(t, schema.JobConfigSchema, 10)
+	assert.Equal(t, JobSchemaField{Name: "tier", Type: ColumnTypeCategorical, Values: []string{"gold", "silver"}}, schema.JobConfigSchema[7])
+	assert.Equal(t, JobSchemaField{Name: "price", Type: ColumnTypeFloat, Nullable: true}, schema.JobConfigSchema[1])
+}
+
+func TestInferSchema_JSONLines(t *testing.T) {
+	jsonlData := `{"id": 1, "score": 0.5, "ok": true, "meta": {"a": 1}}
+{"id": 2, "score": 1, "ok": false, "meta": {"a": 2}, "late": "x"}
+{"id": 3, "score": null, "ok": true, "meta": null}
+`
+	schema := inferTestSchema(t, ".jsonl", jsonlData, 100)
+
+	assert.Equal(t, 3, schema.SampledRows)
+	assert.Equal(t, ColumnTypeInteger, columnByName(t, schema, "id").Type)
+	assert.Equal(t, ColumnTypeFloat, columnByName(t, schema, "score").Type)
+	assert.Equal(t, ColumnTypeBoolean, columnByName(t, schema, "ok").Type)
+
+	meta := columnByName(t, schema, "meta")
+	assert.Equal(t, ColumnTypeText, meta.Type)
+	assert.Equal(t, "json", meta.Format)
+	assert.True(t, meta.Nullable)
+
+	late := columnByName(t, schema, "late")
+	assert.True(t, late.Nullable, "keys missing from other rows count as null")
+	assert.Equal(t, 2, late.NullCount)
+}
+
+func TestInferSchema_SampleLimit(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("n\n")
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&sb, "%d\n", i)
+	}
+	sb.WriteString("not-a-number\n")
+
+	schema := inferTestSchema(t, ".csv", sb.String(), 10)
+	assert.Equal(t, 10, schema.SampledRows)
+	assert.False(t, schema.Complete)
+	assert.Equal(t, ColumnTypeInteger, schema.Columns[0].Type, "rows past the sample are not inspected")
+
+	schema = inferTestSchema(t, ".csv", sb.String(), 11)
+	assert.True(t, schema.Complete)
+	assert.Equal(t, ColumnTypeText, schema.Columns[0].Type)
+
+	schema = inferTestSchema(t, ".csv", "a,b\n", 10)
+	assert.Equal(t, 0, schema.SampledRows)
+	assert.Equal(t, []InferredColumn{{Name: "a", Type: ColumnTypeUnknown}, {Name: "b", Type: ColumnTypeUnknown}}, schema.Columns)
+}
+
+func TestInferSchema_DateFormats(t *testing.T) {
+	schema := inferTestSchema(t, ".csv", "d\n31/01/2024\n01/02/2024\n", 10)
+	assert.Equal(t, ColumnTypeText, schema.Columns[0].Type, "31/01 rules out month-first and no day-first slash layout is known")
+
+	schema = inferTestSchema(t, ".csv", "d\n01/31/2024\n02/01/2024\n", 10)
+	assert.Equal(t, ColumnTypeDate, schema.Columns[0].Type)
+	assert.Equal(t, "01/02/2006", schema.Columns[0].Format)
+}
+
+func TestProjectService_GetDatasetSchema(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-schema"
+	bucketName := "bucket-schema"
+	viewerID := "user-viewer"
+	members := map[string]core.Role{viewerID: core.RoleViewer}
+	csvData := "id,plan\n1,free\n2,pro\n3,free\n4,free\n"
+
+	t.Run("Success_CSV", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("OpenObject", ctx, bucketName, "plans.csv").Return(io.NopCloser(strings.NewReader(csvData)), int64(len(csvData)), nil).Once()
+
+		schema, err := service.GetDatasetSchema(ctx, projectID, "plans.csv", viewerID, 0)
+
+		require.NoError(t, err)
+		assert.Equal(t, "plans.csv", schema.DatasetID)
+		assert.Equal(t, 4, schema.SampledRows)
+		assert.Equal(t, ColumnTypeInteger, schema.Columns[0].Type)
+		assert.Equal(t, ColumnTypeCategorical, schema.Columns[1].Type)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_Parquet", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		data := newParquetTestFile(t, 6, 4)
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("OpenObjectReaderAt", ctx, bucketName, "out.parquet").Return(bytes.NewReader(data), int64(len(data)), nil).Once()
+
+		schema, err := service.GetDatasetSchema(ctx, projectID, "out.parquet", viewerID, 0)
+
+		require.NoError(t, err)
+		assert.True(t, schema.Complete)
+		assert.Equal(t, ColumnTypeInteger, columnByName(t, schema, "id").Type)
+		assert.Equal(t, ColumnTypeText, columnByName(t, schema, "name").Type)
+		score := columnByName(t, schema, "score")
+		assert.Equal(t, ColumnTypeFloat, score.Type)
+		assert.True(t, score.Nullable)
+		assert.Equal(t, "json", columnByName(t, schema, "tags").Format)
+		assert.Equal(t, ColumnTypeDateTime, columnByName(t, schema, "created").Type)
+	})
+
+	t.Run("Failure_NotFound", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("OpenObject", ctx, bucketName, "missing.csv").Return(nil, int64(0), core.ErrNotFound).Once()
+
+		_, err := service.GetDatasetSchema(ctx, projectID, "missing.csv", viewerID, 0)
+
+		assert.ErrorIs(t, err, ErrDatasetNotFound)
+	})
+
+	t.Run("Failure_UnsupportedFormat", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("OpenObject", ctx, bucketName, "notes.txt").Return(io.NopCloser(strings.NewReader("hello")), int64(5), nil).Once()
+
+		_, err := service.GetDatasetSchema(ctx, projectID, "notes.txt", viewerID, 0)
+
+		assert.ErrorIs(t, err, ErrUnsupportedDatasetFormat)
+	})
+
+	t.Run("Failure_Forbidden", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+
+		_, err := service.GetDatasetSchema(ctx, projectID, "plans.csv", "stranger", 0)
+
+		assert.ErrorIs(t, err, ErrProjectAccessDenied)
+	})
+}
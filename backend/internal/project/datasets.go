@@ -0,0 +1,545 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+// Overwrite policies for uploads whose dataset name already exists.
+const (
+	OverwriteReject  = "reject"  // Fail with ErrDatasetExists (default)
+	OverwriteReplace = "replace" // Replace the existing dataset
+	OverwriteVersion = "version" // Store the upload under the next free "<name>_vN.<ext>"
+)
+
+// Custom object metadata keys written for datasets.
+const (
+	metaUploadedBy  = "uploadedBy"
+	metaRowCount    = "rowCount"
+	metaColumnCount = "columnCount"
+)
+
+// maxDatasetVersions bounds the search for a free versioned name.
+const maxDatasetVersions = 1000
+
+// Dataset listing page sizes.
+const (
+	DefaultDatasetPageSize = 100
+	MaxDatasetPageSize     = 1000
+)
+
+// datasetDelimiter separates folders in dataset names.
+const datasetDelimiter = "/"
+
+// Dataset errors
+var (
+	ErrDatasetNotFound             = errors.New("dataset not found")
+	ErrDatasetExists               = errors.New("a dataset with this name already exists")
+	ErrInvalidDatasetName          = errors.New("invalid dataset name")
+	ErrInvalidOverwritePolicy      = errors.New("invalid overwrite policy")
+	ErrInvalidDatasetFolder        = errors.New("invalid dataset folder")
+	ErrProjectStorageNotConfigured = errors.New("project storage is not configured")
+)
+
+// UploadDatasetRequest carries a dataset upload from the handler to the service.
+type UploadDatasetRequest struct {
+	Folder    string    // Optional folder to upload into, e.g. "raw/2025"
+	Name      string    // Target file name, relative to Folder
+	Size      int64     // Declared size, used for the quota check
+	Reader    io.Reader // File content
+	Overwrite string    // One of OverwriteReject, OverwriteReplace, OverwriteVersion; empty means reject
+}
+
+// ListDatasetsRequest selects one page of a folder listing.
+type ListDatasetsRequest struct {
+	Prefix    string // Folder to list; empty for the bucket root
+	PageSize  int    // Defaults to DefaultDatasetPageSize, capped at MaxDatasetPageSize
+	PageToken string // From a previous DatasetListing
+}
+
+// DatasetListing is one page of a folder listing. Folders are the immediate sub-folders
+// of Prefix; Datasets are the files directly inside it.
+type DatasetListing struct {
+	Prefix        string               `json:"prefix"`
+	Folders       []string             `json:"folders"`
+	Datasets      []core.ObjectSummary `json:"datasets"`
+	NextPageToken string               `json:"nextPageToken,omitempty"`
+}
+
+// CreateFolderRequest defines the body for creating a dataset folder.
+type CreateFolderRequest struct {
+	Path string `json:"path" binding:"required"` // e.g. "raw/2025"
+}
+
+// RenameDatasetRequest defines the body for renaming (moving) a dataset.
+type RenameDatasetRequest struct {
+	NewName string `json:"newName" binding:"required"`
+}
+
+// DatasetMetadata describes a single dataset file.
+type DatasetMetadata struct {
+	Name        string    `json:"name"`
+	URI         string    `json:"uri"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"contentType"`
+	MD5         string    `json:"md5,omitempty"`
+	CRC32C      uint32    `json:"crc32c"`
+	UploadedBy  string    `json:"uploadedBy,omitempty"`
+	UploadedAt  time.Time `json:"uploadedAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+	RowCount    *int      `json:"rowCount,omitempty"`    // Nil when the format cannot be parsed
+	ColumnCount *int      `json:"columnCount,omitempty"` // Nil when the format cannot be parsed
+}
+
+// UploadDataset stores a dataset file in the project's bucket, applying the overwrite policy.
+func (s *projectService) UploadDataset(ctx context.Context, projectID string, callerID string, req UploadDatasetRequest) (*DatasetMetadata, error) {
+	log := logger.Logger.With(zap.String("projectID", projectID), zap.String("callerID", callerID), zap.String("datasetName", req.Name))
+
+	// 1. Validate input
+	if req.Folder != "" {
+		folder, err := normalizeFolder(req.Folder)
+		if err != nil {
+			return nil, err
+		}
+		req.Name = folder + req.Name
+	}
+	if err := validateDatasetName(req.Name); err != nil {
+		return nil, err
+	}
+	policy := req.Overwrite
+	if policy == "" {
+		policy = OverwriteReject
+	}
+	if policy != OverwriteReject && policy != OverwriteReplace && policy != OverwriteVersion {
+		return nil, fmt.Errorf("%w: %q (must be reject, replace or version)", ErrInvalidOverwritePolicy, req.Overwrite)
+	}
+
+	// 2. Load project, require Member and a writable project
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleMember, true)
+	if err != nil {
+		return nil, err
+	}
+	bucketName := project.Storage.BucketName
+
+	// 3. Resolve the target name against any existing dataset
+	objectName := req.Name
+	existing, err := s.storageSvc.GetObjectMetadata(ctx, bucketName, objectName)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		log.Error("Failed to check for existing dataset", zap.Error(err))
+		return nil, fmt.Errorf("failed to check for existing dataset: %w", err)
+	}
+	var replacedBytes int64
+	if existing != nil {
+		switch policy {
+		case OverwriteReject:
+			return nil, fmt.Errorf("%w: %s", ErrDatasetExists, objectName)
+		case OverwriteReplace:
+			replacedBytes = existing.Size
+		case OverwriteVersion:
+			objectName, err = s.nextVersionedName(ctx, bucketName, objectName)
+			if err != nil {
+				log.Error("Failed to find a free versioned dataset name", zap.Error(err))
+				return nil, err
+			}
+		}
+	}
+
+	// 4. Enforce quota; a replaced file frees its own bytes
+	if err := CheckStorageQuota(project, req.Size-replacedBytes); err != nil {
+		return nil, err
+	}
+
+	// 5. Upload and record the uploader and the pending PII scan. Only replace may
+	// overwrite; otherwise the upload fails if another one took the name since step 3.
+	upload := s.storageSvc.UploadNewFile
+	if policy == OverwriteReplace {
+		upload = s.storageSvc.UploadFile
+	}
+	uri, err := upload(ctx, bucketName, objectName, req.Reader)
+	if errors.Is(err, core.ErrConflict) {
+		return nil, fmt.Errorf("%w: %s", ErrDatasetExists, objectName)
+	}
+	if err != nil {
+		log.Error("Failed to upload dataset", zap.Error(err), zap.String("objectName", objectName))
+		return nil, fmt.Errorf("failed to upload dataset: %w", err)
+	}
+	pending := map[string]string{metaUploadedBy: callerID, metaPIIScan: `{"status":"` + PIIScanPending + `"}`}
+	if err := s.storageSvc.UpdateObjectMetadata(ctx, bucketName, objectName, pending); err != nil {
+		log.Warn("Failed to record dataset uploader", zap.Error(err), zap.String("objectName", objectName))
+	}
+	s.schedulePIIScan(bucketName, objectName)
+
+	// 6. Keep UsedStorageBytes current; a stale value only affects the next quota check
+	if _, err := s.refreshStorageUsage(ctx, project); err != nil {
+		log.Warn("Failed to refresh storage usage after upload", zap.Error(err))
+	}
+
+	log.Info("Dataset uploaded", zap.String("objectName", objectName), zap.String("policy", policy))
+	now := time.Now().UTC()
+	return &DatasetMetadata{
+		Name:       objectName,
+		URI:        uri,
+		Size:       req.Size,
+		UploadedBy: callerID,
+		UploadedAt: now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// ListDatasets lists one page of the folders and files directly under a prefix.
+func (s *projectService) ListDatasets(ctx context.Context, projectID string, callerID string, req ListDatasetsRequest) (*DatasetListing, error) {
+	prefix, err := normalizeFolder(req.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultDatasetPageSize
+	}
+	if pageSize > MaxDatasetPageSize {
+		pageSize = MaxDatasetPageSize
+	}
+
+	listing := &DatasetListing{Prefix: prefix, Folders: []string{}, Datasets: []core.ObjectSummary{}}
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleViewer, false)
+	if errors.Is(err, ErrProjectStorageNotConfigured) {
+		return listing, nil // Nothing uploaded yet
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := s.storageSvc.ListObjectsPage(ctx, project.Storage.BucketName, core.ListObjectsOptions{
+		Prefix:    prefix,
+		Delimiter: datasetDelimiter,
+		PageSize:  pageSize,
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		logger.Logger.Error("Failed to list datasets", zap.Error(err), zap.String("projectID", projectID), zap.String("prefix", prefix))
+		return nil, fmt.Errorf("failed to list datasets: %w", err)
+	}
+
+	if page.Prefixes != nil {
+		listing.Folders = page.Prefixes
+	}
+	for _, obj := range page.Objects {
+		if !strings.HasSuffix(obj.Name, profileCacheSuffix) { // Stored profiles are not datasets
+			listing.Datasets = append(listing.Datasets, obj)
+		}
+	}
+	listing.NextPageToken = page.NextPageToken
+	return listing, nil
+}
+
+// CreateDatasetFolder creates an empty folder by writing a zero-byte placeholder object
+// named "<path>/". Creating an existing folder succeeds.
+func (s *projectService) CreateDatasetFolder(ctx context.Context, projectID string, callerID string, req CreateFolderRequest) (string, error) {
+	folder, err := normalizeFolder(req.Path)
+	if err != nil {
+		return "", err
+	}
+	if folder == "" {
+		return "", fmt.Errorf("%w: path is required", ErrInvalidDatasetFolder)
+	}
+	if strings.HasPrefix(folder, core.JobOutputPrefix) {
+		return "", fmt.Errorf("%w: the %s prefix is reserved for job outputs", ErrInvalidDatasetFolder, core.JobOutputPrefix)
+	}
+
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleMember, true)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := s.storageSvc.UploadFile(ctx, project.Storage.BucketName, folder, strings.NewReader("")); err != nil {
+		logger.Logger.Error("Failed to create dataset folder", zap.Error(err), zap.String("projectID", projectID), zap.String("folder", folder))
+		return "", fmt.Errorf("failed to create folder: %w", err)
+	}
+
+	logger.Logger.Info("Dataset folder created", zap.String("projectID", projectID), zap.String("folder", folder), zap.String("callerID", callerID))
+	return folder, nil
+}
+
+// GetDatasetMetadata returns a dataset's attributes. Row and column counts are computed
+// on first request and cached in the object's metadata.
+func (s *projectService) GetDatasetMetadata(ctx context.Context, projectID string, datasetID string, callerID string) (*DatasetMetadata, error) {
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleViewer, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.describeDataset(ctx, project.Storage.BucketName, datasetID)
+}
+
+// RenameDataset moves a dataset to a new name within the project's bucket.
+func (s *projectService) RenameDataset(ctx context.Context, projectID string, datasetID string, callerID string, req RenameDatasetRequest) (*DatasetMetadata, error) {
+	log := logger.Logger.With(zap.String("projectID", projectID), zap.String("datasetID", datasetID), zap.String("newName", req.NewName), zap.String("callerID", callerID))
+
+	if err := validateDatasetName(datasetID); err != nil {
+		return nil, err
+	}
+	if err := validateDatasetName(req.NewName); err != nil {
+		return nil, err
+	}
+	if req.NewName == datasetID {
+		return nil, fmt.Errorf("%w: new name is the same as the current name", ErrInvalidDatasetName)
+	}
+
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleMember, true)
+	if err != nil {
+		return nil, err
+	}
+	bucketName := project.Storage.BucketName
+
+	// 1. Source must exist and destination must be free
+	if _, err := s.storageSvc.GetObjectMetadata(ctx, bucketName, datasetID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrDatasetNotFound, datasetID)
+		}
+		return nil, fmt.Errorf("failed to get dataset: %w", err)
+	}
+	if _, err := s.storageSvc.GetObjectMetadata(ctx, bucketName, req.NewName); err == nil {
+		return nil, fmt.Errorf("%w: %s", ErrDatasetExists, req.NewName)
+	} else if !errors.Is(err, core.ErrNotFound) {
+		return nil, fmt.Errorf("failed to check destination name: %w", err)
+	}
+
+	// 2. Copy then delete; GCS has no atomic rename
+	if err := s.storageSvc.CopyObject(ctx, bucketName, datasetID, req.NewName); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrDatasetNotFound, datasetID)
+		}
+		log.Error("Failed to copy dataset", zap.Error(err))
+		return nil, fmt.Errorf("failed to rename dataset: %w", err)
+	}
+	if err := s.storageSvc.DeleteObject(ctx, bucketName, datasetID); err != nil && !errors.Is(err, core.ErrNotFound) {
+		// The copy exists, so the rename succeeded; the old name lingers until removed
+		log.Error("Dataset copied but failed to delete original", zap.Error(err))
+	}
+	s.deleteCachedProfile(ctx, bucketName, datasetID)
+
+	log.Info("Dataset renamed")
+	return s.describeDataset(ctx, bucketName, req.NewName)
+}
+
+// DeleteDataset removes a dataset from the project's bucket.
+func (s *projectService) DeleteDataset(ctx context.Context, projectID string, datasetID string, callerID string) error {
+	log := logger.Logger.With(zap.String("projectID", projectID), zap.String("datasetID", datasetID), zap.String("callerID", callerID))
+
+	if err := validateDatasetName(datasetID); err != nil {
+		return err // Job outputs and stored profiles are not datasets
+	}
+
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleMember, true)
+	if err != nil {
+		return err
+	}
+
+	if err := s.storageSvc.DeleteObject(ctx, project.Storage.BucketName, datasetID); err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return fmt.Errorf("%w: %s", ErrDatasetNotFound, datasetID)
+		}
+		log.Error("Failed to delete dataset", zap.Error(err))
+		return fmt.Errorf("failed to delete dataset: %w", err)
+	}
+	s.deleteCachedProfile(ctx, project.Storage.BucketName, datasetID)
+
+	if _, err := s.refreshStorageUsage(ctx, project); err != nil {
+		log.Warn("Failed to refresh storage usage after delete", zap.Error(err))
+	}
+
+	log.Info("Dataset deleted")
+	return nil
+}
+
+// getDatasetProject loads a project for a dataset operation, enforcing the required role,
+// that the project is writable when write is set, and that storage is configured.
+func (s *projectService) getDatasetProject(ctx context.Context, projectID string, callerID string, requiredRole core.Role, write bool) (*core.Project, error) {
+	project, err := s.projectRepo.GetProjectByID(ctx, projectID)
+	if err != nil {
+		logger.Logger.Error("Failed to get project for dataset operation", zap.Error(err), zap.String("projectID", projectID))
+		return nil, fmt.Errorf("failed to retrieve project: %w", err)
+	}
+	if project == nil {
+		return nil, ErrProjectNotFound
+	}
+	if !s.checkProjectAccess(project, callerID, requiredRole) {
+		return nil, ErrProjectAccessDenied
+	}
+	if write && project.Status == core.ProjectStatusArchived {
+		return nil, core.ErrProjectArchived
+	}
+	if project.Storage.BucketName == "" {
+		return nil, ErrProjectStorageNotConfigured
+	}
+	return project, nil
+}
+
+// describeDataset builds DatasetMetadata for an object, computing and caching row and
+// column counts for parseable formats when they are not yet recorded.
+func (s *projectService) describeDataset(ctx context.Context, bucketName, objectName string) (*DatasetMetadata, error) {
+	obj, err := s.storageSvc.GetObjectMetadata(ctx, bucketName, objectName)
+	if err != nil {
+		if errors.Is(err, core.ErrNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrDatasetNotFound, objectName)
+		}
+		return nil, fmt.Errorf("failed to get dataset metadata: %w", err)
+	}
+
+	meta := &DatasetMetadata{
+		Name:        obj.Name,
+		URI:         obj.URI,
+		Size:        obj.Size,
+		ContentType: obj.ContentType,
+		MD5:         obj.MD5,
+		CRC32C:      obj.CRC32C,
+		UploadedBy:  obj.Metadata[metaUploadedBy],
+		UploadedAt:  obj.Created,
+		UpdatedAt:   obj.Updated,
+	}
+
+	rows, rowErr := strconv.Atoi(obj.Metadata[metaRowCount])
+	cols, colErr := strconv.Atoi(obj.Metadata[metaColumnCount])
+	if rowErr == nil && colErr == nil {
+		meta.RowCount, meta.ColumnCount = &rows, &cols
+		return meta, nil
+	}
+
+	// Not cached yet: parse the content if the format is supported
+	rows, cols, ok := s.countDatasetShape(ctx, bucketName, objectName)
+	if !ok {
+		return meta, nil
+	}
+	meta.RowCount, meta.ColumnCount = &rows, &cols
+	counts := map[string]string{metaRowCount: strconv.Itoa(rows), metaColumnCount: strconv.Itoa(cols)}
+	if err := s.storageSvc.UpdateObjectMetadata(ctx, bucketName, objectName, counts); err != nil {
+		logger.Logger.Warn("Failed to cache dataset row/column counts", zap.Error(err), zap.String("objectName", objectName))
+	}
+	return meta, nil
+}
+
+// countDatasetShape streams a dataset to count rows and distinct columns.
+// It returns ok=false for unsupported formats or unreadable content.
+func (s *projectService) countDatasetShape(ctx context.Context, bucketName, objectName string) (rows, cols int, ok bool) {
+	switch getExtension(objectName) {
+	case ".csv", ".json", ".jsonl":
+	case ".parquet":
+		return s.countParquetShape(ctx, bucketName, objectName)
+	default:
+		return 0, 0, false
+	}
+
+	reader, _, err := s.storageSvc.OpenObject(ctx, bucketName, objectName)
+	if err != nil {
+		logger.Logger.Warn("Failed to read dataset for row/column counts", zap.Error(err), zap.String("objectName", objectName))
+		return 0, 0, false
+	}
+	defer reader.Close()
+
+	records, err := newRowReader(getExtension(objectName), reader)
+	if err == nil {
+		for {
+			if _, err = records.Next(); err != nil {
+				break
+			}
+			rows++
+		}
+	}
+	if err != nil && err != io.EOF {
+		logger.Logger.Warn("Failed to parse dataset for row/column counts", zap.Error(err), zap.String("objectName", objectName))
+		return 0, 0, false
+	}
+	return rows, len(records.Columns()), true
+}
+
+// countParquetShape reads row and column counts from a Parquet footer.
+func (s *projectService) countParquetShape(ctx context.Context, bucketName, objectName string) (rows, cols int, ok bool) {
+	readerAt, size, err := s.storageSvc.OpenObjectReaderAt(ctx, bucketName, objectName)
+	if err != nil {
+		logger.Logger.Warn("Failed to read dataset for row/column counts", zap.Error(err), zap.String("objectName", objectName))
+		return 0, 0, false
+	}
+	rows, cols, err = parquetShape(readerAt, size)
+	if err != nil {
+		logger.Logger.Warn("Failed to parse dataset for row/column counts", zap.Error(err), zap.String("objectName", objectName))
+		return 0, 0, false
+	}
+	return rows, cols, true
+}
+
+// nextVersionedName returns the first "<base>_vN<ext>" (N >= 2) that does not exist yet.
+func (s *projectService) nextVersionedName(ctx context.Context, bucketName, objectName string) (string, error) {
+	ext := path.Ext(objectName)
+	base := strings.TrimSuffix(objectName, ext)
+	for n := 2; n <= maxDatasetVersions; n++ {
+		candidate := fmt.Sprintf("%s_v%d%s", base, n, ext)
+		_, err := s.storageSvc.GetObjectMetadata(ctx, bucketName, candidate)
+		if errors.Is(err, core.ErrNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to check versioned dataset name: %w", err)
+		}
+	}
+	return "", fmt.Errorf("%w: more than %d versions of %s", ErrDatasetExists, maxDatasetVersions, objectName)
+}
+
+// normalizeFolder validates a folder path and returns it with exactly one trailing
+// delimiter, or "" for the bucket root.
+func normalizeFolder(folder string) (string, error) {
+	folder = strings.Trim(strings.TrimSpace(folder), datasetDelimiter)
+	if folder == "" {
+		return "", nil
+	}
+	if err := validateObjectPath(folder); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidDatasetFolder, err)
+	}
+	return folder + datasetDelimiter, nil
+}
+
+// validateDatasetName rejects names that are not valid object paths or that would
+// collide with job outputs.
+func validateDatasetName(name string) error {
+	if err := validateObjectPath(name); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidDatasetName, err)
+	}
+	if strings.HasPrefix(name, core.JobOutputPrefix) {
+		return fmt.Errorf("%w: the %s prefix is reserved for job outputs", ErrInvalidDatasetName, core.JobOutputPrefix)
+	}
+	if strings.HasSuffix(name, profileCacheSuffix) {
+		return fmt.Errorf("%w: the %s suffix is reserved for dataset profiles", ErrInvalidDatasetName, profileCacheSuffix)
+	}
+	return nil
+}
+
+// validateObjectPath checks that p is a non-empty, slash-separated path without empty,
+// "." or ".." segments.
+func validateObjectPath(p string) error {
+	switch {
+	case strings.TrimSpace(p) == "":
+		return errors.New("name is required")
+	case len(p) > 1024 || !utf8.ValidString(p):
+		return errors.New("name must be valid UTF-8 and at most 1024 bytes")
+	case strings.ContainsAny(p, "\r\n"):
+		return errors.New("name cannot contain line breaks")
+	}
+	for _, segment := range strings.Split(p, datasetDelimiter) {
+		switch segment {
+		case "":
+			return errors.New("name cannot start or end with '/' or contain empty path segments")
+		case ".", "..":
+			return errors.New("name cannot contain '.' or '..' path segments")
+		}
+	}
+	return nil
+}
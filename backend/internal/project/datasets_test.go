@@ -0,0 +1,537 @@
+package project
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newDatasetTestProject(projectID, bucketName string, members map[string]core.Role) *core.Project {
+	return &core.Project{
+		ID:          projectID,
+		Status:      core.ProjectStatusActive,
+		Storage:     core.ProjectStorage{BucketName: bucketName},
+		Settings:    core.ProjectSettings{MaxStorageGB: 1},
+		TeamMembers: members,
+	}
+}
+
+func TestProjectService_UploadDataset(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-upload-" + uuid.NewString()
+	bucketName := "bucket-upload"
+	memberID := "user-member"
+	viewerID := "user-viewer"
+	members := map[string]core.Role{memberID: core.RoleMember, viewerID: core.RoleViewer}
+
+	t.Run("Success_NewDataset", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		reader := strings.NewReader("a,b\n1,2\n")
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "data.csv").Return(nil, core.ErrNotFound).Once()
+		mockStorage.On("UploadNewFile", ctx, bucketName, "data.csv", reader).Return("gs://"+bucketName+"/data.csv", nil).Once()
+		mockStorage.On("UpdateObjectMetadata", ctx, bucketName, "data.csv", map[string]string{metaUploadedBy: memberID, metaPIIScan: `{"status":"pending"}`}).Return(nil).Once()
+		mockStorage.On("ListObjects", ctx, bucketName, "").Return([]core.ObjectSummary{{Name: "data.csv", Size: 8}}, nil).Once()
+		mockProjectRepo.On("UpdateStorageUsage", ctx, projectID, int64(8)).Return(nil).Once()
+
+		dataset, err := service.UploadDataset(ctx, projectID, memberID, UploadDatasetRequest{Name: "data.csv", Size: 8, Reader: reader})
+
+		require.NoError(err)
+		assert.Equal("data.csv", dataset.Name)
+		assert.Equal(memberID, dataset.UploadedBy)
+		mockStorage.AssertExpectations(t)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_ExistingRejectedByDefault", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "data.csv").Return(&core.ObjectMetadata{Name: "data.csv", Size: 8}, nil).Once()
+
+		dataset, err := service.UploadDataset(ctx, projectID, memberID, UploadDatasetRequest{Name: "data.csv", Size: 8, Reader: strings.NewReader("")})
+
+		require.Error(err)
+		assert.Nil(dataset)
+		assert.ErrorIs(err, ErrDatasetExists)
+		mockStorage.AssertNotCalled(t, "UploadNewFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_ConcurrentUploadRejected", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "data.csv").Return(nil, core.ErrNotFound).Once()
+		mockStorage.On("UploadNewFile", ctx, bucketName, "data.csv", mock.Anything).Return("", fmt.Errorf("%w: object %s/data.csv", core.ErrConflict, bucketName)).Once()
+
+		_, err := service.UploadDataset(ctx, projectID, memberID, UploadDatasetRequest{Name: "data.csv", Size: 8, Reader: strings.NewReader("")})
+
+		assert.ErrorIs(err, ErrDatasetExists)
+		mockStorage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockStorage.AssertNotCalled(t, "UpdateObjectMetadata", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_VersionPicksNextFreeName", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "data.csv").Return(&core.ObjectMetadata{Name: "data.csv"}, nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "data_v2.csv").Return(&core.ObjectMetadata{Name: "data_v2.csv"}, nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "data_v3.csv").Return(nil, core.ErrNotFound).Once()
+		mockStorage.On("UploadNewFile", ctx, bucketName, "data_v3.csv", mock.Anything).Return("gs://"+bucketName+"/data_v3.csv", nil).Once()
+		mockStorage.On("UpdateObjectMetadata", ctx, bucketName, "data_v3.csv", mock.Anything).Return(nil).Once()
+		mockStorage.On("ListObjects", ctx, bucketName, "").Return([]core.ObjectSummary{}, nil).Once()
+
+		dataset, err := service.UploadDataset(ctx, projectID, memberID, UploadDatasetRequest{Name: "data.csv", Size: 8, Reader: strings.NewReader(""), Overwrite: OverwriteVersion})
+
+		require.NoError(err)
+		assert.Equal("data_v3.csv", dataset.Name)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_ReplaceCountsOnlyTheDifferenceAgainstQuota", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		project := newDatasetTestProject(projectID, bucketName, members)
+		project.Storage.UsedStorageBytes = 1<<30 - 10 // 10 bytes left
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(project, nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "data.csv").Return(&core.ObjectMetadata{Name: "data.csv", Size: 100}, nil).Once()
+		mockStorage.On("UploadFile", ctx, bucketName, "data.csv", mock.Anything).Return("gs://"+bucketName+"/data.csv", nil).Once()
+		mockStorage.On("UpdateObjectMetadata", ctx, bucketName, "data.csv", mock.Anything).Return(nil).Once()
+		mockStorage.On("ListObjects", ctx, bucketName, "").Return([]core.ObjectSummary{}, nil).Once()
+		mockProjectRepo.On("UpdateStorageUsage", ctx, projectID, int64(0)).Return(nil).Once()
+
+		_, err := service.UploadDataset(ctx, projectID, memberID, UploadDatasetRequest{Name: "data.csv", Size: 105, Reader: strings.NewReader(""), Overwrite: OverwriteReplace})
+
+		require.NoError(err)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Failure_InvalidPolicy", func(t *testing.T) {
+		service, _, _, _ := setupProjectServiceTest()
+
+		_, err := service.UploadDataset(ctx, projectID, memberID, UploadDatasetRequest{Name: "data.csv", Overwrite: "merge"})
+
+		assert.ErrorIs(err, ErrInvalidOverwritePolicy)
+	})
+
+	t.Run("Failure_ViewerDenied", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+
+		_, err := service.UploadDataset(ctx, projectID, viewerID, UploadDatasetRequest{Name: "data.csv"})
+
+		assert.ErrorIs(err, ErrProjectAccessDenied)
+	})
+
+	t.Run("Failure_ArchivedProject", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		project := newDatasetTestProject(projectID, bucketName, members)
+		project.Status = core.ProjectStatusArchived
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(project, nil).Once()
+
+		_, err := service.UploadDataset(ctx, projectID, memberID, UploadDatasetRequest{Name: "data.csv"})
+
+		assert.ErrorIs(err, core.ErrProjectArchived)
+	})
+}
+
+func TestProjectService_GetDatasetMetadata(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-meta-" + uuid.NewString()
+	bucketName := "bucket-meta"
+	viewerID := "user-viewer"
+	members := map[string]core.Role{viewerID: core.RoleViewer}
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	t.Run("Success_ComputesAndCachesCounts", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "data.csv").Return(&core.ObjectMetadata{
+			Name:        "data.csv",
+			Size:        20,
+			ContentType: "text/csv",
+			MD5:         "abc123",
+			Created:     created,
+			Metadata:    map[string]string{metaUploadedBy: "user-1"},
+		}, nil).Once()
+		mockStorage.On("OpenObject", ctx, bucketName, "data.csv").Return(io.NopCloser(strings.NewReader("a,b,c\n1,2,3\n4,5,6\n")), int64(18), nil).Once()
+		mockStorage.On("UpdateObjectMetadata", ctx, bucketName, "data.csv", map[string]string{metaRowCount: "2", metaColumnCount: "3"}).Return(nil).Once()
+
+		dataset, err := service.GetDatasetMetadata(ctx, projectID, "data.csv", viewerID)
+
+		require.NoError(err)
+		assert.Equal("text/csv", dataset.ContentType)
+		assert.Equal("abc123", dataset.MD5)
+		assert.Equal("user-1", dataset.UploadedBy)
+		assert.Equal(created, dataset.UploadedAt)
+		require.NotNil(dataset.RowCount)
+		require.NotNil(dataset.ColumnCount)
+		assert.Equal(2, *dataset.RowCount)
+		assert.Equal(3, *dataset.ColumnCount)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_UsesCachedCounts", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "data.csv").Return(&core.ObjectMetadata{
+			Name:     "data.csv",
+			Metadata: map[string]string{metaRowCount: "10", metaColumnCount: "4"},
+		}, nil).Once()
+
+		dataset, err := service.GetDatasetMetadata(ctx, projectID, "data.csv", viewerID)
+
+		require.NoError(err)
+		assert.Equal(10, *dataset.RowCount)
+		assert.Equal(4, *dataset.ColumnCount)
+		mockStorage.AssertNotCalled(t, "OpenObject", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_UnsupportedFormatHasNoCounts", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "image.png").Return(&core.ObjectMetadata{Name: "image.png"}, nil).Once()
+
+		dataset, err := service.GetDatasetMetadata(ctx, projectID, "image.png", viewerID)
+
+		require.NoError(err)
+		assert.Nil(dataset.RowCount)
+		assert.Nil(dataset.ColumnCount)
+	})
+
+	t.Run("Failure_NotFound", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "missing.csv").Return(nil, core.ErrNotFound).Once()
+
+		_, err := service.GetDatasetMetadata(ctx, projectID, "missing.csv", viewerID)
+
+		assert.ErrorIs(err, ErrDatasetNotFound)
+	})
+}
+
+func TestProjectService_RenameDataset(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-rename-" + uuid.NewString()
+	bucketName := "bucket-rename"
+	memberID := "user-member"
+	members := map[string]core.Role{memberID: core.RoleMember}
+
+	t.Run("Success_CopiesThenDeletes", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "old.csv").Return(&core.ObjectMetadata{Name: "old.csv"}, nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "archive/new.csv").Return(nil, core.ErrNotFound).Once()
+		mockStorage.On("CopyObject", ctx, bucketName, "old.csv", "archive/new.csv").Return(nil).Once()
+		mockStorage.On("DeleteObject", ctx, bucketName, "old.csv").Return(nil).Once()
+		mockStorage.On("DeleteObject", ctx, bucketName, "old.csv.profile.json").Return(core.ErrNotFound).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "archive/new.csv").Return(&core.ObjectMetadata{
+			Name:     "archive/new.csv",
+			Metadata: map[string]string{metaRowCount: "1", metaColumnCount: "1"},
+		}, nil).Once()
+
+		dataset, err := service.RenameDataset(ctx, projectID, "old.csv", memberID, RenameDatasetRequest{NewName: "archive/new.csv"})
+
+		require.NoError(err)
+		assert.Equal("archive/new.csv", dataset.Name)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Failure_DestinationExists", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "old.csv").Return(&core.ObjectMetadata{Name: "old.csv"}, nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "new.csv").Return(&core.ObjectMetadata{Name: "new.csv"}, nil).Once()
+
+		_, err := service.RenameDataset(ctx, projectID, "old.csv", memberID, RenameDatasetRequest{NewName: "new.csv"})
+
+		assert.ErrorIs(err, ErrDatasetExists)
+		mockStorage.AssertNotCalled(t, "CopyObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_SourceNotFound", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("GetObjectMetadata", ctx, bucketName, "old.csv").Return(nil, core.ErrNotFound).Once()
+
+		_, err := service.RenameDataset(ctx, projectID, "old.csv", memberID, RenameDatasetRequest{NewName: "new.csv"})
+
+		assert.ErrorIs(err, ErrDatasetNotFound)
+	})
+
+	t.Run("Failure_ReservedPrefix", func(t *testing.T) {
+		service, _, _, _ := setupProjectServiceTest()
+
+		_, err := service.RenameDataset(ctx, projectID, "old.csv", memberID, RenameDatasetRequest{NewName: "jobs/hijack.csv"})
+
+		assert.ErrorIs(err, ErrInvalidDatasetName)
+	})
+
+	t.Run("Failure_ReservedSource", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		for _, datasetID := range []string{"jobs/job-1/output.csv", "data.csv.profile.json", "../other/data.csv"} {
+			_, err := service.RenameDataset(ctx, projectID, datasetID, memberID, RenameDatasetRequest{NewName: "moved.csv"})
+
+			assert.ErrorIs(err, ErrInvalidDatasetName, datasetID)
+		}
+		mockProjectRepo.AssertNotCalled(t, "GetProjectByID", mock.Anything, mock.Anything)
+		mockStorage.AssertNotCalled(t, "CopyObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestProjectService_DeleteDataset(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-delete-ds-" + uuid.NewString()
+	bucketName := "bucket-delete-ds"
+	memberID := "user-member"
+	members := map[string]core.Role{memberID: core.RoleMember}
+
+	t.Run("Success_RefreshesUsage", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		project := newDatasetTestProject(projectID, bucketName, members)
+		project.Storage.UsedStorageBytes = 100
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(project, nil).Once()
+		mockStorage.On("DeleteObject", ctx, bucketName, "data.csv").Return(nil).Once()
+		mockStorage.On("DeleteObject", ctx, bucketName, "data.csv.profile.json").Return(nil).Once()
+		mockStorage.On("ListObjects", ctx, bucketName, "").Return([]core.ObjectSummary{}, nil).Once()
+		mockProjectRepo.On("UpdateStorageUsage", ctx, projectID, int64(0)).Return(nil).Once()
+
+		err := service.DeleteDataset(ctx, projectID, "data.csv", memberID)
+
+		require.NoError(err)
+		mockStorage.AssertExpectations(t)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_NotFound", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("DeleteObject", ctx, bucketName, "missing.csv").Return(core.ErrNotFound).Once()
+
+		err := service.DeleteDataset(ctx, projectID, "missing.csv", memberID)
+
+		assert.ErrorIs(err, ErrDatasetNotFound)
+	})
+
+	t.Run("Failure_StorageError", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		storageErr := errors.New("gcs unavailable")
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("DeleteObject", ctx, bucketName, "data.csv").Return(storageErr).Once()
+
+		err := service.DeleteDataset(ctx, projectID, "data.csv", memberID)
+
+		assert.ErrorIs(err, storageErr)
+	})
+
+	t.Run("Failure_ReservedName", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		for _, datasetID := range []string{"jobs/job-1/output.csv", "data.csv.profile.json"} {
+			err := service.DeleteDataset(ctx, projectID, datasetID, memberID)
+
+			assert.ErrorIs(err, ErrInvalidDatasetName, datasetID)
+		}
+		mockProjectRepo.AssertNotCalled(t, "GetProjectByID", mock.Anything, mock.Anything)
+		mockStorage.AssertNotCalled(t, "DeleteObject", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestValidateDatasetName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		valid bool
+	}{
+		{"Simple", "data.csv", true},
+		{"Nested", "raw/2025/data.csv", true},
+		{"Empty", "", false},
+		{"Whitespace", "   ", false},
+		{"LeadingSlash", "/data.csv", false},
+		{"TrailingSlash", "folder/", false},
+		{"DotDot", "a/../b.csv", false},
+		{"JobOutputPrefix", "jobs/x.csv", false},
+		{"Newline", "a\nb.csv", false},
+		{"ProfileSuffix", "data.csv.profile.json", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDatasetName(tt.input)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidDatasetName)
+			}
+		})
+	}
+}
+
+func TestProjectService_ListDatasets(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-list-ds-" + uuid.NewString()
+	bucketName := "bucket-list-ds"
+	viewerID := "user-viewer"
+	members := map[string]core.Role{viewerID: core.RoleViewer}
+
+	t.Run("Success_FolderListing", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("ListObjectsPage", ctx, bucketName, core.ListObjectsOptions{
+			Prefix:    "raw/",
+			Delimiter: "/",
+			PageSize:  50,
+			PageToken: "tok-1",
+		}).Return(&core.ObjectPage{
+			Objects:       []core.ObjectSummary{{Name: "raw/a.csv", Size: 10}, {Name: "raw/a.csv.profile.json", Size: 2}},
+			Prefixes:      []string{"raw/2025/"},
+			NextPageToken: "tok-2",
+		}, nil).Once()
+
+		listing, err := service.ListDatasets(ctx, projectID, viewerID, ListDatasetsRequest{Prefix: "/raw", PageSize: 50, PageToken: "tok-1"})
+
+		require.NoError(err)
+		assert.Equal("raw/", listing.Prefix)
+		assert.Equal([]string{"raw/2025/"}, listing.Folders)
+		require.Len(listing.Datasets, 1, "stored profiles are hidden")
+		assert.Equal("raw/a.csv", listing.Datasets[0].Name)
+		assert.Equal("tok-2", listing.NextPageToken)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_DefaultsAndCapsPageSize", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Twice()
+		mockStorage.On("ListObjectsPage", ctx, bucketName, core.ListObjectsOptions{Delimiter: "/", PageSize: DefaultDatasetPageSize}).Return(&core.ObjectPage{}, nil).Once()
+		mockStorage.On("ListObjectsPage", ctx, bucketName, core.ListObjectsOptions{Delimiter: "/", PageSize: MaxDatasetPageSize}).Return(&core.ObjectPage{}, nil).Once()
+
+		listing, err := service.ListDatasets(ctx, projectID, viewerID, ListDatasetsRequest{})
+		require.NoError(err)
+		assert.NotNil(listing.Folders)
+		assert.NotNil(listing.Datasets)
+
+		_, err = service.ListDatasets(ctx, projectID, viewerID, ListDatasetsRequest{PageSize: 50000})
+		require.NoError(err)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_NoBucketReturnsEmptyListing", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, "", members), nil).Once()
+
+		listing, err := service.ListDatasets(ctx, projectID, viewerID, ListDatasetsRequest{})
+
+		require.NoError(err)
+		assert.Empty(listing.Datasets)
+	})
+
+	t.Run("Failure_InvalidPrefix", func(t *testing.T) {
+		service, _, _, _ := setupProjectServiceTest()
+
+		_, err := service.ListDatasets(ctx, projectID, viewerID, ListDatasetsRequest{Prefix: "raw/../secret"})
+
+		assert.ErrorIs(err, ErrInvalidDatasetFolder)
+	})
+}
+
+func TestProjectService_CreateDatasetFolder(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-folder-" + uuid.NewString()
+	bucketName := "bucket-folder"
+	memberID := "user-member"
+	members := map[string]core.Role{memberID: core.RoleMember}
+
+	t.Run("Success_WritesPlaceholder", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+		mockStorage.On("UploadFile", ctx, bucketName, "raw/2025/", mock.Anything).Return("gs://"+bucketName+"/raw/2025/", nil).Once()
+
+		folder, err := service.CreateDatasetFolder(ctx, projectID, memberID, CreateFolderRequest{Path: "raw/2025/"})
+
+		require.NoError(err)
+		assert.Equal("raw/2025/", folder)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Failure_ReservedPrefix", func(t *testing.T) {
+		service, _, _, _ := setupProjectServiceTest()
+
+		_, err := service.CreateDatasetFolder(ctx, projectID, memberID, CreateFolderRequest{Path: "jobs/mine"})
+
+		assert.ErrorIs(err, ErrInvalidDatasetFolder)
+	})
+
+	t.Run("Failure_EmptyPath", func(t *testing.T) {
+		service, _, _, _ := setupProjectServiceTest()
+
+		_, err := service.CreateDatasetFolder(ctx, projectID, memberID, CreateFolderRequest{Path: "/"})
+
+		assert.ErrorIs(err, ErrInvalidDatasetFolder)
+	})
+}
+
+func TestProjectService_UploadDataset_IntoFolder(t *testing.T) {
+	ctx := context.Background()
+	projectID := "proj-upload-folder-" + uuid.NewString()
+	bucketName := "bucket-upload-folder"
+	memberID := "user-member"
+	members := map[string]core.Role{memberID: core.RoleMember}
+
+	service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+	mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newDatasetTestProject(projectID, bucketName, members), nil).Once()
+	mockStorage.On("GetObjectMetadata", ctx, bucketName, "raw/2025/data.csv").Return(nil, core.ErrNotFound).Once()
+	mockStorage.On("UploadNewFile", ctx, bucketName, "raw/2025/data.csv", mock.Anything).Return("gs://"+bucketName+"/raw/2025/data.csv", nil).Once()
+	mockStorage.On("UpdateObjectMetadata", ctx, bucketName, "raw/2025/data.csv", mock.Anything).Return(nil).Once()
+	mockStorage.On("ListObjects", ctx, bucketName, "").Return([]core.ObjectSummary{}, nil).Once()
+
+	dataset, err := service.UploadDataset(ctx, projectID, memberID, UploadDatasetRequest{Folder: "raw/2025", Name: "data.csv", Reader: strings.NewReader("")})
+
+	require.NoError(t, err)
+	assert.Equal(t, "raw/2025/data.csv", dataset.Name)
+	mockStorage.AssertExpectations(t)
+}
@@ -4,6 +4,7 @@ import (
 	"SynDataGen/backend/internal/auth" // Need this for GetUserIDFromContext
 	"SynDataGen/backend/internal/core"
 	"SynDataGen/backend/internal/platform/logger"
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -42,6 +43,8 @@ func RegisterProjectRoutes(rg *gin.RouterGroup, authSvc auth.AuthService, projec
 		protectedRoutes.GET("/:projectId", h.GetProject)
 		protectedRoutes.PATCH("/:projectId", h.UpdateProject) // Using PATCH for partial updates
 		protectedRoutes.DELETE("/:projectId", h.DeleteProject)
+		protectedRoutes.POST("/:projectId/archive", h.ArchiveProject)
+		protectedRoutes.POST("/:projectId/unarchive", h.UnarchiveProject)
 
 		// Team Management Routes
 		teamRoutes := protectedRoutes.Group("/:projectId/team")
@@ -54,9 +57,25 @@ func RegisterProjectRoutes(rg *gin.RouterGroup, authSvc auth.AuthService, projec
 		// Dataset Upload Route
 		protectedRoutes.POST("/:projectId/datasets", h.UploadDataset)
 		protectedRoutes.GET("/:projectId/datasets", h.ListDatasets)
+		protectedRoutes.POST("/:projectId/datasets/folders", h.CreateDatasetFolder)
 
 		// New Route for getting dataset content
 		protectedRoutes.GET("/:projectId/datasets/:datasetId/content", h.GetDatasetContentHandler)
+		protectedRoutes.GET("/:projectId/datasets/:datasetId/schema", h.GetDatasetSchema)
+		protectedRoutes.GET("/:projectId/datasets/:datasetId/profile", h.GetDatasetProfile)
+
+		// PII scan findings, rescans and acknowledgement
+		protectedRoutes.GET("/:projectId/datasets/:datasetId/pii", h.GetDatasetPIIScan)
+		protectedRoutes.POST("/:projectId/datasets/:datasetId/pii/scan", h.ScanDatasetPII)
+		protectedRoutes.POST("/:projectId/datasets/:datasetId/pii/acknowledge", h.AcknowledgeDatasetPII)
+
+		// Dataset metadata, rename (move) and delete
+		protectedRoutes.GET("/:projectId/datasets/:datasetId", h.GetDatasetMetadata)
+		protectedRoutes.DELETE("/:projectId/datasets/:datasetId", h.DeleteDataset)
+		protectedRoutes.POST("/:projectId/datasets/:datasetId/rename", h.RenameDataset)
+
+		// Storage usage breakdown (datasets vs job outputs)
+		protectedRoutes.GET("/:projectId/storage/usage", h.GetStorageUsage)
 	}
 }
 
@@ -79,7 +98,9 @@ func (h *ProjectHandlers) CreateProject(c *gin.Context) {
 	// Call the service (using callerID as customerID for now, adjust if needed)
 	project, err := h.Svc.CreateProject(c.Request.Context(), callerID, req)
 	if err != nil {
-		if errors.Is(err, ErrBucketCreationFailed) {
+		if errors.Is(err, ErrInvalidProjectSettings) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_PROJECT_SETTINGS", "message": err.Error()})
+		} else if errors.Is(err, ErrBucketCreationFailed) {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "BUCKET_CREATION_FAILED", "message": err.Error()})
 		} else {
 			logger.Logger.Error("Failed to create project", zap.Error(err), zap.String("callerID", callerID))
@@ -101,7 +122,11 @@ func (h *ProjectHandlers) ListProjects(c *gin.Context) {
 	}
 
 	// Parse query parameters
-	statusFilter := c.DefaultQuery("status", "active") // Default to active?
+	statusFilter := c.DefaultQuery("status", core.ProjectStatusActive) // "all" disables the filter
+	if statusFilter != core.ProjectStatusActive && statusFilter != core.ProjectStatusArchived && statusFilter != "all" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_INPUT", "message": "status must be one of: active, archived, all"})
+		return
+	}
 	limitStr := c.DefaultQuery("limit", "20")
 	offsetStr := c.DefaultQuery("offset", "0")
 
@@ -174,10 +199,14 @@ func (h *ProjectHandlers) UpdateProject(c *gin.Context) {
 
 	project, err := h.Svc.UpdateProject(c.Request.Context(), projectID, callerID, req)
 	if err != nil {
-		if errors.Is(err, ErrProjectNotFound) {
+		if errors.Is(err, ErrInvalidProjectSettings) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_PROJECT_SETTINGS", "message": err.Error()})
+		} else if errors.Is(err, ErrProjectNotFound) {
 			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "PROJECT_NOT_FOUND", "message": err.Error()})
 		} else if errors.Is(err, ErrProjectAccessDenied) {
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "ACCESS_DENIED", "message": err.Error()})
+		} else if errors.Is(err, core.ErrProjectArchived) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "PROJECT_ARCHIVED", "message": err.Error()})
 		} else if errors.Is(err, ErrProjectUpdateFailed) {
 			logger.Logger.Error("Failed to update project (service error)", zap.Error(err), zap.String("projectID", projectID), zap.String("callerID", callerID))
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "UPDATE_PROJECT_FAILED", "message": err.Error()})
@@ -221,6 +250,42 @@ func (h *ProjectHandlers) DeleteProject(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// ArchiveProject handles POST /projects/{projectId}/archive requests.
+func (h *ProjectHandlers) ArchiveProject(c *gin.Context) {
+	h.changeProjectStatus(c, h.Svc.ArchiveProject, "ARCHIVE_PROJECT_FAILED")
+}
+
+// UnarchiveProject handles POST /projects/{projectId}/unarchive requests.
+func (h *ProjectHandlers) UnarchiveProject(c *gin.Context) {
+	h.changeProjectStatus(c, h.Svc.UnarchiveProject, "UNARCHIVE_PROJECT_FAILED")
+}
+
+// changeProjectStatus runs an archive/unarchive service call and maps its errors.
+func (h *ProjectHandlers) changeProjectStatus(c *gin.Context, action func(ctx context.Context, projectID, callerID string) (*core.Project, error), failureCode string) {
+	projectID := c.Param("projectId")
+
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
+		return
+	}
+
+	project, err := action(c.Request.Context(), projectID, callerID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "PROJECT_NOT_FOUND", "message": err.Error()})
+		} else if errors.Is(err, ErrProjectAccessDenied) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "ACCESS_DENIED", "message": err.Error()})
+		} else {
+			logger.Logger.Error("Failed to change project status", zap.Error(err), zap.String("projectID", projectID), zap.String("callerID", callerID))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": failureCode, "message": "Internal server error changing project status"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
 // UpdateTeamMemberRole handles PUT /projects/:projectId/team/:memberId
 func (h *ProjectHandlers) UpdateTeamMemberRole(c *gin.Context) {
 	projectID := c.Param("projectId")
@@ -345,6 +410,8 @@ func (h *ProjectHandlers) InviteMember(c *gin.Context) {
 }
 
 // UploadDataset handles the dataset file upload for a specific project.
+// The optional "overwrite" query or form field selects reject (default), replace or version,
+// and the optional "folder" query or form field uploads into a folder.
 func (h *ProjectHandlers) UploadDataset(c *gin.Context) {
 	projectID := c.Param("projectId")
 	if projectID == "" {
@@ -355,34 +422,10 @@ func (h *ProjectHandlers) UploadDataset(c *gin.Context) {
 	// Get User ID from context (using auth helper)
 	userID, ok := auth.GetUserIDFromContext(c)
 	if !ok {
-		// Error response already handled by GetUserIDFromContext if it logs/aborts
-		// If it just returns false, send response here:
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User ID not found in context"})
 		return
 	}
 
-	// --- Get Project & Check Authorization ---
-	project, err := h.Svc.GetProjectByID(c.Request.Context(), projectID, userID)
-	if err != nil {
-		if errors.Is(err, ErrProjectNotFound) || errors.Is(err, core.ErrNotFound) { // Check both service and core errors potentially
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
-		} else if errors.Is(err, ErrProjectAccessDenied) || errors.Is(err, core.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to project"})
-		} else {
-			logger.Logger.Error("Failed to retrieve project for upload", zap.Error(err), zap.String("projectID", projectID))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve project details"})
-		}
-		return
-	}
-
-	// Check if the user has at least Member role using the local helper
-	userRole, exists := project.TeamMembers[userID]
-	if !exists || !isRoleSufficient(userRole, core.RoleMember) { // Use local helper
-		logger.Logger.Warn("UploadDataset: Insufficient permissions", zap.String("projectID", projectID), zap.String("userID", userID), zap.String("userRole", string(userRole)))
-		c.JSON(http.StatusForbidden, gin.H{"error": core.ErrForbidden.Error()})
-		return
-	}
-
 	// --- Parse Multipart Form ---
 	const maxUploadSize = 500 << 20 // 500 MB
 	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadSize)
@@ -398,91 +441,91 @@ func (h *ProjectHandlers) UploadDataset(c *gin.Context) {
 	}
 	defer file.Close()
 
-	objectName := header.Filename // Use original filename
-	// TODO: Sanitize objectName - replace invalid GCS characters
-
-	// --- Upload to GCS using the correct service ---
-	bucketName := project.Storage.BucketName
-	if bucketName == "" {
-		logger.Logger.Error("Project storage not configured", zap.String("projectID", projectID))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Project storage not configured"})
-		return
+	overwrite := c.Query("overwrite")
+	if overwrite == "" {
+		overwrite = c.PostForm("overwrite")
+	}
+	folder := c.Query("folder")
+	if folder == "" {
+		folder = c.PostForm("folder")
 	}
 
-	// Call storageService held by the handler
-	uri, err := h.storageService.UploadFile(c.Request.Context(), bucketName, objectName, file)
+	// The service handles authorization, archived/quota checks and the overwrite policy
+	dataset, err := h.Svc.UploadDataset(c.Request.Context(), projectID, userID, UploadDatasetRequest{
+		Folder:    folder,
+		Name:      header.Filename, // Use original filename
+		Size:      header.Size,
+		Reader:    file,
+		Overwrite: overwrite,
+	})
 	if err != nil {
-		logger.Logger.Error("Failed to upload file to GCS", zap.Error(err), zap.String("projectID", projectID), zap.String("bucketName", bucketName), zap.String("objectName", objectName))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to upload dataset file"})
+		h.respondDatasetError(c, err, "UPLOAD_DATASET_FAILED", "Failed to upload dataset file", zap.String("projectID", projectID), zap.String("fileName", header.Filename))
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":     "Dataset uploaded successfully",
-		"datasetName": objectName,
-		"uri":         uri,
+		"datasetName": dataset.Name,
+		"uri":         dataset.URI,
+		"dataset":     dataset,
 	})
 }
 
-// ListDatasets handles GET requests to list datasets (objects) in a project's bucket.
+// ListDatasets handles GET /projects/:projectId/datasets
+// Query parameters: prefix (folder to browse), pageSize and pageToken.
 func (h *ProjectHandlers) ListDatasets(c *gin.Context) {
 	projectID := c.Param("projectId")
-	if projectID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Project ID is required"})
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
 		return
 	}
 
-	userID, ok := auth.GetUserIDFromContext(c)
-	if !ok {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized: User ID not found in context"})
-		return
+	pageSize := 0 // Service default
+	if raw := c.Query("pageSize"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": "pageSize must be a positive integer"})
+			return
+		}
+		pageSize = parsed
 	}
 
-	// --- Get Project & Check Authorization (Viewer+ required) ---
-	project, err := h.Svc.GetProjectByID(c.Request.Context(), projectID, userID)
+	listing, err := h.Svc.ListDatasets(c.Request.Context(), projectID, callerID, ListDatasetsRequest{
+		Prefix:    c.Query("prefix"),
+		PageSize:  pageSize,
+		PageToken: c.Query("pageToken"),
+	})
 	if err != nil {
-		if errors.Is(err, ErrProjectNotFound) || errors.Is(err, core.ErrNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
-		} else if errors.Is(err, ErrProjectAccessDenied) || errors.Is(err, core.ErrForbidden) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied to project"})
-		} else {
-			logger.Logger.Error("Failed to retrieve project for listing datasets", zap.Error(err), zap.String("projectID", projectID))
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve project details"})
-		}
+		h.respondDatasetError(c, err, "LIST_DATASETS_FAILED", "Failed to list datasets", zap.String("projectID", projectID))
 		return
 	}
 
-	// Viewer role is sufficient for listing
-	if !isRoleSufficient(project.TeamMembers[userID], core.RoleViewer) {
-		logger.Logger.Warn("ListDatasets: Insufficient permissions", zap.String("projectID", projectID), zap.String("userID", userID))
-		c.JSON(http.StatusForbidden, gin.H{"error": core.ErrForbidden.Error()})
+	c.JSON(http.StatusOK, listing)
+}
+
+// CreateDatasetFolder handles POST /projects/:projectId/datasets/folders
+func (h *ProjectHandlers) CreateDatasetFolder(c *gin.Context) {
+	projectID := c.Param("projectId")
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
 		return
 	}
 
-	// --- List Objects from GCS ---
-	bucketName := project.Storage.BucketName
-	if bucketName == "" {
-		logger.Logger.Warn("Project storage not configured for listing datasets", zap.String("projectID", projectID))
-		// Return empty list if bucket doesn't exist?
-		c.JSON(http.StatusOK, []core.ObjectSummary{}) // Return empty list
-		// Or: c.JSON(http.StatusInternalServerError, gin.H{"error": "Project storage not configured"})
+	var req CreateFolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
 		return
 	}
 
-	// List objects at the root of the bucket (no specific prefix for now)
-	objects, err := h.storageService.ListObjects(c.Request.Context(), bucketName, "") // Empty prefix
+	folder, err := h.Svc.CreateDatasetFolder(c.Request.Context(), projectID, callerID, req)
 	if err != nil {
-		logger.Logger.Error("Failed to list objects from GCS", zap.Error(err), zap.String("projectID", projectID), zap.String("bucketName", bucketName))
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list datasets"})
+		h.respondDatasetError(c, err, "CREATE_FOLDER_FAILED", "Failed to create folder", zap.String("projectID", projectID), zap.String("path", req.Path))
 		return
 	}
 
-	// Ensure we return an empty slice, not nil, if no objects found
-	if objects == nil {
-		objects = []core.ObjectSummary{}
-	}
-
-	c.JSON(http.StatusOK, objects)
+	c.JSON(http.StatusCreated, gin.H{"folder": folder})
 }
 
 // GetDatasetContentHandler handles GET /projects/:projectId/datasets/:datasetId/content
@@ -504,8 +547,14 @@ func (h *ProjectHandlers) GetDatasetContentHandler(c *gin.Context) {
 		return
 	}
 
+	query, err := parseDatasetContentQuery(c)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
 	// Call the service method
-	content, err := h.Svc.GetDatasetContent(c.Request.Context(), projectID, datasetID, callerID)
+	content, err := h.Svc.GetDatasetContent(c.Request.Context(), projectID, datasetID, callerID, query)
 	if err != nil {
 		log := logger.Logger.With(zap.String("projectID", projectID), zap.String("datasetID", datasetID), zap.String("callerID", callerID))
 		if errors.Is(err, ErrProjectNotFound) {
@@ -514,9 +563,13 @@ func (h *ProjectHandlers) GetDatasetContentHandler(c *gin.Context) {
 		} else if errors.Is(err, ErrProjectAccessDenied) {
 			log.Warn("GetDatasetContentHandler: Access denied")
 			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "ACCESS_DENIED", "message": err.Error()})
-		} else if err.Error() == fmt.Sprintf("dataset file '%s' not found in project storage", datasetID) { // Check for specific dataset not found error from service
+		} else if errors.Is(err, ErrDatasetNotFound) {
 			log.Warn("GetDatasetContentHandler: Dataset file not found", zap.Error(err))
 			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "DATASET_NOT_FOUND", "message": err.Error()})
+		} else if errors.Is(err, ErrInvalidDatasetQuery) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		} else if errors.Is(err, ErrUnsupportedDatasetFormat) {
+			c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": "UNSUPPORTED_DATASET_FORMAT", "message": err.Error()})
 		} else {
 			log.Error("Failed to get dataset content", zap.Error(err))
 			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "GET_DATASET_CONTENT_FAILED", "message": "Internal server error retrieving dataset content"})
@@ -532,19 +585,298 @@ func (h *ProjectHandlers) GetDatasetContentHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, content)
 }
 
-// --- Helper for Role Check (Local to handlers.go for now) ---
-func isRoleSufficient(userRole core.Role, requiredRole core.Role) bool {
-	// Define role hierarchy (higher value means more permissions)
-	roleHierarchy := map[core.Role]int{
-		core.RoleViewer: 1,
-		core.RoleMember: 2,
-		core.RoleAdmin:  3,
-		core.RoleOwner:  4,
+// parseDatasetContentQuery reads the offset, limit, columns, sort and (repeatable) filter query
+// parameters, e.g. ?offset=100&limit=50&columns=name,age&sort=-age,name&filter=country=US
+func parseDatasetContentQuery(c *gin.Context) (DatasetContentQuery, error) {
+	var query DatasetContentQuery
+	for name, dest := range map[string]*int{"offset": &query.Offset, "limit": &query.Limit} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			return query, fmt.Errorf("%s must be a non-negative integer", name)
+		}
+		*dest = parsed
+	}
+
+	columns, err := ParseDatasetColumns(c.Query("columns"))
+	if err != nil {
+		return query, err
+	}
+	query.Columns = columns
+
+	sortKeys, err := ParseDatasetSort(c.Query("sort"))
+	if err != nil {
+		return query, err
+	}
+	query.Sort = sortKeys
+
+	for _, expr := range c.QueryArray("filter") {
+		filter, err := ParseDatasetFilter(expr)
+		if err != nil {
+			return query, err
+		}
+		query.Filters = append(query.Filters, filter)
+	}
+	return query, nil
+}
+
+// GetDatasetMetadata handles GET /projects/:projectId/datasets/:datasetId
+func (h *ProjectHandlers) GetDatasetMetadata(c *gin.Context) {
+	projectID := c.Param("projectId")
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
+		return
+	}
+	datasetID, ok := datasetIDParam(c)
+	if !ok {
+		return
+	}
+
+	dataset, err := h.Svc.GetDatasetMetadata(c.Request.Context(), projectID, datasetID, callerID)
+	if err != nil {
+		h.respondDatasetError(c, err, "GET_DATASET_FAILED", "Internal server error retrieving dataset metadata", zap.String("projectID", projectID), zap.String("datasetID", datasetID))
+		return
+	}
+	c.JSON(http.StatusOK, dataset)
+}
+
+// GetDatasetSchema handles GET /projects/:projectId/datasets/:datasetId/schema
+// Query parameters: sampleRows (rows to inspect, default 1000).
+func (h *ProjectHandlers) GetDatasetSchema(c *gin.Context) {
+	projectID := c.Param("projectId")
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
+		return
+	}
+	datasetID, ok := datasetIDParam(c)
+	if !ok {
+		return
+	}
+
+	sampleRows := 0 // Service default
+	if raw := c.Query("sampleRows"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": "sampleRows must be a positive integer"})
+			return
+		}
+		sampleRows = parsed
+	}
+
+	schema, err := h.Svc.GetDatasetSchema(c.Request.Context(), projectID, datasetID, callerID, sampleRows)
+	if err != nil {
+		h.respondDatasetError(c, err, "GET_DATASET_SCHEMA_FAILED", "Internal server error inferring dataset schema", zap.String("projectID", projectID), zap.String("datasetID", datasetID))
+		return
+	}
+	c.JSON(http.StatusOK, schema)
+}
+
+// GetDatasetProfile handles GET /projects/:projectId/datasets/:datasetId/profile
+// Query parameters: refresh (recompute instead of using the stored profile).
+func (h *ProjectHandlers) GetDatasetProfile(c *gin.Context) {
+	projectID := c.Param("projectId")
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
+		return
+	}
+	datasetID, ok := datasetIDParam(c)
+	if !ok {
+		return
+	}
+
+	refresh := false
+	if raw := c.Query("refresh"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": "refresh must be true or false"})
+			return
+		}
+		refresh = parsed
+	}
+
+	profile, err := h.Svc.GetDatasetProfile(c.Request.Context(), projectID, datasetID, callerID, refresh)
+	if err != nil {
+		h.respondDatasetError(c, err, "GET_DATASET_PROFILE_FAILED", "Internal server error profiling dataset", zap.String("projectID", projectID), zap.String("datasetID", datasetID))
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// GetDatasetPIIScan handles GET /projects/:projectId/datasets/:datasetId/pii
+func (h *ProjectHandlers) GetDatasetPIIScan(c *gin.Context) {
+	projectID := c.Param("projectId")
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
+		return
+	}
+	datasetID, ok := datasetIDParam(c)
+	if !ok {
+		return
+	}
+
+	scan, err := h.Svc.GetDatasetPIIScan(c.Request.Context(), projectID, datasetID, callerID)
+	if err != nil {
+		h.respondDatasetError(c, err, "GET_DATASET_PII_FAILED", "Internal server error retrieving PII scan", zap.String("projectID", projectID), zap.String("datasetID", datasetID))
+		return
+	}
+	c.JSON(http.StatusOK, scan)
+}
+
+// ScanDatasetPII handles POST /projects/:projectId/datasets/:datasetId/pii/scan
+func (h *ProjectHandlers) ScanDatasetPII(c *gin.Context) {
+	projectID := c.Param("projectId")
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
+		return
+	}
+	datasetID, ok := datasetIDParam(c)
+	if !ok {
+		return
+	}
+
+	scan, err := h.Svc.ScanDatasetPII(c.Request.Context(), projectID, datasetID, callerID)
+	if err != nil {
+		h.respondDatasetError(c, err, "SCAN_DATASET_PII_FAILED", "Internal server error scanning dataset for PII", zap.String("projectID", projectID), zap.String("datasetID", datasetID))
+		return
+	}
+	c.JSON(http.StatusOK, scan)
+}
+
+// AcknowledgeDatasetPII handles POST /projects/:projectId/datasets/:datasetId/pii/acknowledge
+func (h *ProjectHandlers) AcknowledgeDatasetPII(c *gin.Context) {
+	projectID := c.Param("projectId")
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
+		return
+	}
+	datasetID, ok := datasetIDParam(c)
+	if !ok {
+		return
+	}
+
+	scan, err := h.Svc.AcknowledgeDatasetPII(c.Request.Context(), projectID, datasetID, callerID)
+	if err != nil {
+		h.respondDatasetError(c, err, "ACKNOWLEDGE_DATASET_PII_FAILED", "Internal server error acknowledging PII findings", zap.String("projectID", projectID), zap.String("datasetID", datasetID))
+		return
+	}
+	c.JSON(http.StatusOK, scan)
+}
+
+// RenameDataset handles POST /projects/:projectId/datasets/:datasetId/rename
+func (h *ProjectHandlers) RenameDataset(c *gin.Context) {
+	projectID := c.Param("projectId")
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
+		return
+	}
+	datasetID, ok := datasetIDParam(c)
+	if !ok {
+		return
+	}
+
+	var req RenameDatasetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+		return
+	}
+
+	dataset, err := h.Svc.RenameDataset(c.Request.Context(), projectID, datasetID, callerID, req)
+	if err != nil {
+		h.respondDatasetError(c, err, "RENAME_DATASET_FAILED", "Internal server error renaming dataset", zap.String("projectID", projectID), zap.String("datasetID", datasetID))
+		return
+	}
+	c.JSON(http.StatusOK, dataset)
+}
+
+// DeleteDataset handles DELETE /projects/:projectId/datasets/:datasetId
+func (h *ProjectHandlers) DeleteDataset(c *gin.Context) {
+	projectID := c.Param("projectId")
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
+		return
+	}
+	datasetID, ok := datasetIDParam(c)
+	if !ok {
+		return
 	}
 
-	userLevel, okUser := roleHierarchy[userRole]
-	requiredLevel, okRequired := roleHierarchy[requiredRole]
+	if err := h.Svc.DeleteDataset(c.Request.Context(), projectID, datasetID, callerID); err != nil {
+		h.respondDatasetError(c, err, "DELETE_DATASET_FAILED", "Internal server error deleting dataset", zap.String("projectID", projectID), zap.String("datasetID", datasetID))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// datasetIDParam URL-decodes the :datasetId path parameter, writing a 400 on failure.
+func datasetIDParam(c *gin.Context) (string, bool) {
+	datasetID, err := url.PathUnescape(c.Param("datasetId"))
+	if err != nil || datasetID == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_DATASET_ID", "message": "Invalid dataset identifier in URL"})
+		return "", false
+	}
+	return datasetID, true
+}
+
+// respondDatasetError maps dataset service errors to HTTP responses.
+func (h *ProjectHandlers) respondDatasetError(c *gin.Context, err error, failureCode, failureMessage string, fields ...zap.Field) {
+	switch {
+	case errors.Is(err, ErrProjectNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "PROJECT_NOT_FOUND", "message": err.Error()})
+	case errors.Is(err, ErrProjectAccessDenied):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "ACCESS_DENIED", "message": err.Error()})
+	case errors.Is(err, ErrDatasetNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "DATASET_NOT_FOUND", "message": err.Error()})
+	case errors.Is(err, ErrDatasetExists):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "DATASET_EXISTS", "message": err.Error()})
+	case errors.Is(err, core.ErrProjectArchived):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "PROJECT_ARCHIVED", "message": "Datasets of an archived project cannot be modified"})
+	case errors.Is(err, core.ErrStorageQuotaExceeded):
+		c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "STORAGE_QUOTA_EXCEEDED", "message": err.Error()})
+	case errors.Is(err, ErrInvalidDatasetName), errors.Is(err, ErrInvalidDatasetFolder), errors.Is(err, ErrInvalidOverwritePolicy), errors.Is(err, ErrInvalidDatasetQuery):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "INVALID_REQUEST", "message": err.Error()})
+	case errors.Is(err, ErrUnsupportedDatasetFormat):
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": "UNSUPPORTED_DATASET_FORMAT", "message": err.Error()})
+	case errors.Is(err, ErrPIIScanIncomplete):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "PII_SCAN_INCOMPLETE", "message": err.Error()})
+	default:
+		logger.Logger.Error(failureMessage, append(fields, zap.Error(err))...)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": failureCode, "message": failureMessage})
+	}
+}
+
+// GetStorageUsage handles GET /projects/:projectId/storage/usage
+func (h *ProjectHandlers) GetStorageUsage(c *gin.Context) {
+	projectID := c.Param("projectId")
+
+	callerID, exists := auth.GetUserIDFromContext(c)
+	if !exists {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "UNAUTHORIZED", "message": "User ID not found in context"})
+		return
+	}
+
+	usage, err := h.Svc.GetStorageUsage(c.Request.Context(), projectID, callerID)
+	if err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "PROJECT_NOT_FOUND", "message": err.Error()})
+		} else if errors.Is(err, ErrProjectAccessDenied) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "ACCESS_DENIED", "message": err.Error()})
+		} else {
+			logger.Logger.Error("Failed to get storage usage", zap.Error(err), zap.String("projectID", projectID), zap.String("callerID", callerID))
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "GET_STORAGE_USAGE_FAILED", "message": "Internal server error computing storage usage"})
+		}
+		return
+	}
 
-	// Ensure both roles are valid and user's level is sufficient
-	return okUser && okRequired && userLevel >= requiredLevel
+	c.JSON(http.StatusOK, usage)
 }
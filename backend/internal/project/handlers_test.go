@@ -7,8 +7,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
@@ -86,6 +88,140 @@ func (m *MockProjectService) RemoveMember(ctx context.Context, projectID string,
 	return args.Get(0).(*core.Project), args.Error(1)
 }
 
+func (m *MockProjectService) GetDatasetContent(ctx context.Context, projectID string, datasetID string, callerID string, query DatasetContentQuery) (*DatasetContent, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DatasetContent), args.Error(1)
+}
+
+func (m *MockProjectService) GetDatasetSchema(ctx context.Context, projectID string, datasetID string, callerID string, sampleRows int) (*DatasetSchema, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID, sampleRows)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DatasetSchema), args.Error(1)
+}
+
+func (m *MockProjectService) GetDatasetProfile(ctx context.Context, projectID string, datasetID string, callerID string, refresh bool) (*DatasetProfile, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID, refresh)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DatasetProfile), args.Error(1)
+}
+
+func (m *MockProjectService) GetDatasetPIIScan(ctx context.Context, projectID string, datasetID string, callerID string) (*PIIScan, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PIIScan), args.Error(1)
+}
+
+func (m *MockProjectService) ScanDatasetPII(ctx context.Context, projectID string, datasetID string, callerID string) (*PIIScan, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PIIScan), args.Error(1)
+}
+
+func (m *MockProjectService) AcknowledgeDatasetPII(ctx context.Context, projectID string, datasetID string, callerID string) (*PIIScan, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*PIIScan), args.Error(1)
+}
+
+func (m *MockProjectService) CompareDatasets(ctx context.Context, projectID string, callerID string, req DatasetComparisonRequest) (*FidelityReport, error) {
+	args := m.Called(ctx, projectID, callerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*FidelityReport), args.Error(1)
+}
+
+func (m *MockProjectService) ArchiveProject(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+func (m *MockProjectService) UnarchiveProject(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+func (m *MockProjectService) SetArchiveHook(hook ArchiveHook) {
+	m.Called(hook)
+}
+
+func (m *MockProjectService) GetStorageUsage(ctx context.Context, projectID string, callerID string) (*StorageUsage, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*StorageUsage), args.Error(1)
+}
+
+func (m *MockProjectService) RefreshStorageUsage(ctx context.Context, projectID string) (*StorageUsage, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*StorageUsage), args.Error(1)
+}
+
+func (m *MockProjectService) UploadDataset(ctx context.Context, projectID string, callerID string, req UploadDatasetRequest) (*DatasetMetadata, error) {
+	args := m.Called(ctx, projectID, callerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DatasetMetadata), args.Error(1)
+}
+
+func (m *MockProjectService) GetDatasetMetadata(ctx context.Context, projectID string, datasetID string, callerID string) (*DatasetMetadata, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DatasetMetadata), args.Error(1)
+}
+
+func (m *MockProjectService) RenameDataset(ctx context.Context, projectID string, datasetID string, callerID string, req RenameDatasetRequest) (*DatasetMetadata, error) {
+	args := m.Called(ctx, projectID, datasetID, callerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DatasetMetadata), args.Error(1)
+}
+
+func (m *MockProjectService) DeleteDataset(ctx context.Context, projectID string, datasetID string, callerID string) error {
+	args := m.Called(ctx, projectID, datasetID, callerID)
+	return args.Error(0)
+}
+
+func (m *MockProjectService) ListDatasets(ctx context.Context, projectID string, callerID string, req ListDatasetsRequest) (*DatasetListing, error) {
+	args := m.Called(ctx, projectID, callerID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*DatasetListing), args.Error(1)
+}
+
+func (m *MockProjectService) CreateDatasetFolder(ctx context.Context, projectID string, callerID string, req CreateFolderRequest) (string, error) {
+	args := m.Called(ctx, projectID, callerID, req)
+	return args.String(0), args.Error(1)
+}
+
 // MockAuthService - Define a basic mock if one doesn't exist in auth package tests
 type MockAuthService struct {
 	mock.Mock
@@ -142,6 +278,7 @@ func setupProjectHandlersTestRouter() (*gin.Engine, *MockProjectService) {
 	}
 
 	router := gin.New()
+	router.UseRawPath = true // As in cmd/api, so %2F-encoded dataset IDs reach the handlers
 	mockService := new(MockProjectService)
 	mockStorageService := new(MockStorageService)
 	h := NewProjectHandlers(mockService, mockStorageService)
@@ -154,6 +291,21 @@ func setupProjectHandlersTestRouter() (*gin.Engine, *MockProjectService) {
 		protectedRoutes.GET("/:projectId", h.GetProject)
 		protectedRoutes.PATCH("/:projectId", h.UpdateProject)
 		protectedRoutes.DELETE("/:projectId", h.DeleteProject)
+		protectedRoutes.POST("/:projectId/archive", h.ArchiveProject)
+		protectedRoutes.POST("/:projectId/unarchive", h.UnarchiveProject)
+		protectedRoutes.GET("/:projectId/storage/usage", h.GetStorageUsage)
+		protectedRoutes.POST("/:projectId/datasets", h.UploadDataset)
+		protectedRoutes.GET("/:projectId/datasets", h.ListDatasets)
+		protectedRoutes.POST("/:projectId/datasets/folders", h.CreateDatasetFolder)
+		protectedRoutes.GET("/:projectId/datasets/:datasetId", h.GetDatasetMetadata)
+		protectedRoutes.GET("/:projectId/datasets/:datasetId/content", h.GetDatasetContentHandler)
+		protectedRoutes.GET("/:projectId/datasets/:datasetId/schema", h.GetDatasetSchema)
+		protectedRoutes.GET("/:projectId/datasets/:datasetId/profile", h.GetDatasetProfile)
+		protectedRoutes.GET("/:projectId/datasets/:datasetId/pii", h.GetDatasetPIIScan)
+		protectedRoutes.POST("/:projectId/datasets/:datasetId/pii/scan", h.ScanDatasetPII)
+		protectedRoutes.POST("/:projectId/datasets/:datasetId/pii/acknowledge", h.AcknowledgeDatasetPII)
+		protectedRoutes.DELETE("/:projectId/datasets/:datasetId", h.DeleteDataset)
+		protectedRoutes.POST("/:projectId/datasets/:datasetId/rename", h.RenameDataset)
 
 		teamRoutes := protectedRoutes.Group("/:projectId/team")
 		{
@@ -273,7 +425,7 @@ func TestInviteMemberHandler(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &errResp)
 		require.NoError(err)
 		assert.Equal("INVALID_INPUT", errResp.Error)
-		assert.Contains(errResp.Message, "oneof=admin member viewer")
+		assert.Contains(errResp.Message, "oneof")
 	})
 
 	t.Run("Failure - Invalid JSON", func(t *testing.T) {
@@ -405,7 +557,7 @@ func TestUpdateMemberRoleHandler(t *testing.T) {
 		err := json.Unmarshal(w.Body.Bytes(), &errResp)
 		require.NoError(err)
 		assert.Equal("INVALID_INPUT", errResp.Error)
-		assert.Contains(errResp.Message, "oneof=admin member viewer")
+		assert.Contains(errResp.Message, "oneof")
 	})
 
 	t.Run("Failure - Invalid JSON", func(t *testing.T) {
@@ -512,3 +664,589 @@ func TestRemoveMemberHandler(t *testing.T) {
 }
 
 // (Tests for new team handlers: InviteMember, UpdateMemberRole, RemoveMember will go here) // <-- Placeholder can be removed
+
+func TestListProjectsHandler_StatusFilter(t *testing.T) {
+	router, mockService := setupProjectHandlersTestRouter()
+	assert := assert.New(t)
+	callerID := "test-caller-id"
+
+	t.Run("Success - Archived Filter Passed Through", func(t *testing.T) {
+		listResp := &ListProjectsResponse{Projects: []*core.Project{}, Limit: 20}
+		mockService.On("ListProjects", mock.Anything, callerID, core.ProjectStatusArchived, 20, 0).Return(listResp, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects?status=archived", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Unknown Status", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/projects?status=deleted", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "ListProjects", mock.Anything, mock.Anything, "deleted", mock.Anything, mock.Anything)
+	})
+}
+
+func TestArchiveProjectHandlers(t *testing.T) {
+	router, mockService := setupProjectHandlersTestRouter()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	projectID := "project-123"
+	callerID := "test-caller-id"
+
+	t.Run("Success - Archive", func(t *testing.T) {
+		archived := &core.Project{ID: projectID, Status: core.ProjectStatusArchived}
+		mockService.On("ArchiveProject", mock.Anything, projectID, callerID).Return(archived, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/archive", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		var responseProject core.Project
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &responseProject))
+		assert.Equal(core.ProjectStatusArchived, responseProject.Status)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Success - Unarchive", func(t *testing.T) {
+		active := &core.Project{ID: projectID, Status: core.ProjectStatusActive}
+		mockService.On("UnarchiveProject", mock.Anything, projectID, callerID).Return(active, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/unarchive", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Unarchive Access Denied", func(t *testing.T) {
+		mockService.On("UnarchiveProject", mock.Anything, projectID, callerID).Return(nil, ErrProjectAccessDenied).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/unarchive", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusForbidden, w.Code)
+		var errResp ErrorResponse
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &errResp))
+		assert.Equal("ACCESS_DENIED", errResp.Error)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Update Archived Project", func(t *testing.T) {
+		name := "Renamed"
+		updateReq := UpdateProjectRequest{Name: &name}
+		mockService.On("UpdateProject", mock.Anything, projectID, callerID, updateReq).Return(nil, fmt.Errorf("%w: unarchive first", core.ErrProjectArchived)).Once()
+
+		body, _ := json.Marshal(updateReq)
+		req, _ := http.NewRequest(http.MethodPatch, "/projects/"+projectID, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusConflict, w.Code)
+		var errResp ErrorResponse
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &errResp))
+		assert.Equal("PROJECT_ARCHIVED", errResp.Error)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Update Invalid Settings", func(t *testing.T) {
+		updateReq := UpdateProjectRequest{Settings: &core.ProjectSettings{DataRetentionDays: -1}}
+		mockService.On("UpdateProject", mock.Anything, projectID, callerID, updateReq).Return(nil, fmt.Errorf("%w: dataRetentionDays must be between 0 and 3650", ErrInvalidProjectSettings)).Once()
+
+		body, _ := json.Marshal(updateReq)
+		req, _ := http.NewRequest(http.MethodPatch, "/projects/"+projectID, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+		var errResp ErrorResponse
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &errResp))
+		assert.Equal("INVALID_PROJECT_SETTINGS", errResp.Error)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestGetStorageUsageHandler(t *testing.T) {
+	router, mockService := setupProjectHandlersTestRouter()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	projectID := "project-123"
+	callerID := "test-caller-id"
+
+	t.Run("Success", func(t *testing.T) {
+		usage := &StorageUsage{
+			UsedBytes:  300,
+			QuotaBytes: 1 << 30,
+			Breakdown: []PrefixUsage{
+				{Category: "datasets", Bytes: 100, ObjectCount: 1},
+				{Category: "jobOutputs", Prefix: core.JobOutputPrefix, Bytes: 200, ObjectCount: 2},
+			},
+		}
+		mockService.On("GetStorageUsage", mock.Anything, projectID, callerID).Return(usage, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/storage/usage", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		var resp StorageUsage
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(int64(300), resp.UsedBytes)
+		require.Len(resp.Breakdown, 2)
+		assert.Equal("jobOutputs", resp.Breakdown[1].Category)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Access Denied", func(t *testing.T) {
+		mockService.On("GetStorageUsage", mock.Anything, projectID, callerID).Return(nil, ErrProjectAccessDenied).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/storage/usage", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusForbidden, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestDatasetHandlers(t *testing.T) {
+	router, mockService := setupProjectHandlersTestRouter()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	projectID := "project-123"
+	callerID := "test-caller-id"
+
+	newUploadRequest := func(t *testing.T, query string) *http.Request {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("datasetFile", "data.csv")
+		require.NoError(err)
+		_, err = part.Write([]byte("a,b\n1,2\n"))
+		require.NoError(err)
+		require.NoError(writer.Close())
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/datasets"+query, body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		return req
+	}
+
+	t.Run("Success - Upload With Version Policy", func(t *testing.T) {
+		dataset := &DatasetMetadata{Name: "data_v2.csv", URI: "gs://bucket/data_v2.csv"}
+		mockService.On("UploadDataset", mock.Anything, projectID, callerID, mock.MatchedBy(func(r UploadDatasetRequest) bool {
+			return r.Name == "data.csv" && r.Overwrite == OverwriteVersion && r.Size == 8
+		})).Return(dataset, nil).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newUploadRequest(t, "?overwrite=version"))
+
+		assert.Equal(http.StatusOK, w.Code)
+		var resp map[string]interface{}
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal("data_v2.csv", resp["datasetName"])
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Upload Name Exists", func(t *testing.T) {
+		mockService.On("UploadDataset", mock.Anything, projectID, callerID, mock.AnythingOfType("UploadDatasetRequest")).Return(nil, fmt.Errorf("%w: data.csv", ErrDatasetExists)).Once()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, newUploadRequest(t, ""))
+
+		assert.Equal(http.StatusConflict, w.Code)
+		var errResp ErrorResponse
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &errResp))
+		assert.Equal("DATASET_EXISTS", errResp.Error)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Success - Get Metadata", func(t *testing.T) {
+		rows, cols := 2, 3
+		dataset := &DatasetMetadata{Name: "data.csv", Size: 20, RowCount: &rows, ColumnCount: &cols}
+		mockService.On("GetDatasetMetadata", mock.Anything, projectID, "data.csv", callerID).Return(dataset, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/data.csv", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		var resp DatasetMetadata
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(2, *resp.RowCount)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Get Metadata Not Found", func(t *testing.T) {
+		mockService.On("GetDatasetMetadata", mock.Anything, projectID, "missing.csv", callerID).Return(nil, ErrDatasetNotFound).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/missing.csv", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Success - Delete", func(t *testing.T) {
+		mockService.On("DeleteDataset", mock.Anything, projectID, "data.csv", callerID).Return(nil).Once()
+
+		req, _ := http.NewRequest(http.MethodDelete, "/projects/"+projectID+"/datasets/data.csv", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusNoContent, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Delete Archived", func(t *testing.T) {
+		mockService.On("DeleteDataset", mock.Anything, projectID, "data.csv", callerID).Return(core.ErrProjectArchived).Once()
+
+		req, _ := http.NewRequest(http.MethodDelete, "/projects/"+projectID+"/datasets/data.csv", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusConflict, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Success - Rename", func(t *testing.T) {
+		renamed := &DatasetMetadata{Name: "renamed.csv"}
+		mockService.On("RenameDataset", mock.Anything, projectID, "data.csv", callerID, RenameDatasetRequest{NewName: "renamed.csv"}).Return(renamed, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/datasets/data.csv/rename", strings.NewReader(`{"newName":"renamed.csv"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Rename Missing Body", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/datasets/data.csv/rename", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestListDatasetsHandler(t *testing.T) {
+	router, mockService := setupProjectHandlersTestRouter()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	projectID := "project-123"
+	callerID := "test-caller-id"
+
+	t.Run("Success - Query Parameters Passed Through", func(t *testing.T) {
+		listing := &DatasetListing{
+			Prefix:        "raw/",
+			Folders:       []string{"raw/2025/"},
+			Datasets:      []core.ObjectSummary{{Name: "raw/a.csv"}},
+			NextPageToken: "next",
+		}
+		mockService.On("ListDatasets", mock.Anything, projectID, callerID, ListDatasetsRequest{Prefix: "raw", PageSize: 25, PageToken: "tok"}).Return(listing, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets?prefix=raw&pageSize=25&pageToken=tok", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		var resp DatasetListing
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal([]string{"raw/2025/"}, resp.Folders)
+		assert.Equal("next", resp.NextPageToken)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Invalid Page Size", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets?pageSize=abc", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Failure - Invalid Prefix", func(t *testing.T) {
+		mockService.On("ListDatasets", mock.Anything, projectID, callerID, mock.AnythingOfType("ListDatasetsRequest")).Return(nil, ErrInvalidDatasetFolder).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets?prefix=..", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestGetDatasetContentHandler(t *testing.T) {
+	router, mockService := setupProjectHandlersTestRouter()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	projectID := "project-123"
+	callerID := "test-caller-id"
+
+	t.Run("Success - Query Parameters Parsed", func(t *testing.T) {
+		expectedQuery := DatasetContentQuery{
+			Offset:  20,
+			Limit:   10,
+			Columns: []string{"name", "age"},
+			Sort:    []SortKey{{Column: "age", Descending: true}, {Column: "name"}},
+			Filters: []RowFilter{
+				{Column: "country", Operator: FilterEq, Value: "US"},
+				{Column: "age", Operator: FilterGte, Value: "30"},
+			},
+		}
+		content := &DatasetContent{
+			Data:           []map[string]interface{}{{"name": "a", "age": "40", "country": "US"}},
+			Columns:        []string{"name", "age", "country"},
+			Offset:         20,
+			Limit:          10,
+			TotalRows:      21,
+			TotalRowsExact: true,
+		}
+		mockService.On("GetDatasetContent", mock.Anything, projectID, "jobs/job-1/output.parquet", callerID, expectedQuery).Return(content, nil).Once()
+
+		target := "/projects/" + projectID + "/datasets/jobs%2Fjob-1%2Foutput.parquet/content?offset=20&limit=10&columns=name,age&sort=-age,name" +
+			"&filter=" + url.QueryEscape("country=US") + "&filter=" + url.QueryEscape("age>=30")
+		req, _ := http.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		var resp DatasetContent
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal([]string{"name", "age", "country"}, resp.Columns)
+		assert.Equal(21, resp.TotalRows)
+		assert.True(resp.TotalRowsExact)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Invalid Offset", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/data.csv/content?offset=-1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Failure - Filter Without Operator", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/data.csv/content?filter=age", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Failure - Unknown Column", func(t *testing.T) {
+		mockService.On("GetDatasetContent", mock.Anything, projectID, "data.csv", callerID, mock.AnythingOfType("DatasetContentQuery")).
+			Return(nil, fmt.Errorf("%w: unknown sort column \"nope\"", ErrInvalidDatasetQuery)).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/data.csv/content?sort=nope", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+		var resp map[string]string
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal("INVALID_REQUEST", resp["error"])
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestGetDatasetSchemaHandler(t *testing.T) {
+	router, mockService := setupProjectHandlersTestRouter()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	projectID := "project-123"
+	callerID := "test-caller-id"
+
+	t.Run("Success", func(t *testing.T) {
+		schema := &DatasetSchema{
+			DatasetID:       "uploads/people.csv",
+			SampledRows:     2,
+			Complete:        true,
+			Columns:         []InferredColumn{{Name: "id", Type: ColumnTypeInteger, DistinctCount: 2}},
+			JobConfigSchema: []JobSchemaField{{Name: "id", Type: ColumnTypeInteger}},
+		}
+		mockService.On("GetDatasetSchema", mock.Anything, projectID, "uploads/people.csv", callerID, 500).Return(schema, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/uploads%2Fpeople.csv/schema?sampleRows=500", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		var resp DatasetSchema
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(*schema, resp)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Invalid Sample Size", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/data.csv/schema?sampleRows=0", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Failure - Not Found", func(t *testing.T) {
+		mockService.On("GetDatasetSchema", mock.Anything, projectID, "missing.csv", callerID, 0).Return(nil, ErrDatasetNotFound).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/missing.csv/schema", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusNotFound, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Unsupported Format", func(t *testing.T) {
+		mockService.On("GetDatasetSchema", mock.Anything, projectID, "notes.txt", callerID, 0).
+			Return(nil, fmt.Errorf("%w: \".txt\"", ErrUnsupportedDatasetFormat)).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/notes.txt/schema", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusUnsupportedMediaType, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestGetDatasetProfileHandler(t *testing.T) {
+	router, mockService := setupProjectHandlersTestRouter()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	projectID := "project-123"
+	callerID := "test-caller-id"
+
+	t.Run("Success - Refresh", func(t *testing.T) {
+		profile := &DatasetProfile{DatasetID: "raw/people.csv", RowCount: 2, Columns: []ColumnProfile{{Name: "id", Type: ColumnTypeInteger, Count: 2, DistinctCount: 2}}}
+		mockService.On("GetDatasetProfile", mock.Anything, projectID, "raw/people.csv", callerID, true).Return(profile, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/raw%2Fpeople.csv/profile?refresh=true", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		var resp DatasetProfile
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(2, resp.RowCount)
+		assert.Equal("id", resp.Columns[0].Name)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Invalid Refresh", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/data.csv/profile?refresh=sometimes", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Failure - Access Denied", func(t *testing.T) {
+		mockService.On("GetDatasetProfile", mock.Anything, projectID, "data.csv", callerID, false).Return(nil, ErrProjectAccessDenied).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/data.csv/profile", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusForbidden, w.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestDatasetPIIHandlers(t *testing.T) {
+	router, mockService := setupProjectHandlersTestRouter()
+	assert := assert.New(t)
+	require := require.New(t)
+
+	projectID := "project-123"
+	callerID := "test-caller-id"
+
+	t.Run("Success - Get", func(t *testing.T) {
+		scan := &PIIScan{DatasetID: "raw/people.csv", Status: PIIScanCompleted, HighSeverityCount: 1, Findings: []PIIFinding{{Column: "email", Type: PIITypeEmail, Severity: PIISeverityHigh, Matches: 2, MatchRate: 1}}}
+		mockService.On("GetDatasetPIIScan", mock.Anything, projectID, "raw/people.csv", callerID).Return(scan, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodGet, "/projects/"+projectID+"/datasets/raw%2Fpeople.csv/pii", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		var resp PIIScan
+		require.NoError(json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(PIITypeEmail, resp.Findings[0].Type)
+		assert.Equal(1, resp.HighSeverityCount)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Success - Scan", func(t *testing.T) {
+		mockService.On("ScanDatasetPII", mock.Anything, projectID, "people.csv", callerID).Return(&PIIScan{Status: PIIScanCompleted}, nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/datasets/people.csv/pii/scan", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusOK, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Acknowledge Incomplete Scan", func(t *testing.T) {
+		mockService.On("AcknowledgeDatasetPII", mock.Anything, projectID, "people.csv", callerID).Return(nil, fmt.Errorf("%w: scan of people.csv is pending", ErrPIIScanIncomplete)).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/datasets/people.csv/pii/acknowledge", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusConflict, w.Code)
+		assert.Contains(w.Body.String(), "PII_SCAN_INCOMPLETE")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestCreateDatasetFolderHandler(t *testing.T) {
+	router, mockService := setupProjectHandlersTestRouter()
+	assert := assert.New(t)
+
+	projectID := "project-123"
+	callerID := "test-caller-id"
+
+	t.Run("Success", func(t *testing.T) {
+		mockService.On("CreateDatasetFolder", mock.Anything, projectID, callerID, CreateFolderRequest{Path: "raw/2025"}).Return("raw/2025/", nil).Once()
+
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/datasets/folders", strings.NewReader(`{"path":"raw/2025"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusCreated, w.Code)
+		assert.Contains(w.Body.String(), `"folder":"raw/2025/"`)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure - Missing Path", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodPost, "/projects/"+projectID+"/datasets/folders", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(http.StatusBadRequest, w.Code)
+	})
+}
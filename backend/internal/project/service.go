@@ -3,14 +3,10 @@ package project
 import (
 	"SynDataGen/backend/internal/core"
 	"SynDataGen/backend/internal/platform/logger" // Using platform logger
-	"bufio"
-	"bytes" // For CSV parsing example
 	"context"
-	"encoding/csv"  // For CSV parsing example
-	"encoding/json" // <-- Added for JSON parsing
 	"errors"
 	"fmt"
-	"io" // For CSV parsing example
+	"io"
 	"strings"
 	"time"
 
@@ -56,10 +52,30 @@ type InviteMemberRequest struct {
 	Role   core.Role `json:"role" binding:"required,oneof=admin member viewer"` // Can only invite as admin, member, or viewer
 }
 
-// DatasetContent defines the structure for returning dataset data.
+// DatasetContent defines the structure for returning a page of dataset data.
 type DatasetContent struct {
-	Data []map[string]interface{} `json:"data"`
-	// Could add metadata like row count, headers etc.
+	Data           []map[string]interface{} `json:"data"`
+	Columns        []string                 `json:"columns"` // Column names in file order
+	Offset         int                      `json:"offset"`
+	Limit          int                      `json:"limit"`
+	TotalRows      int                      `json:"totalRows"`        // Rows matching the filters
+	TotalRowsExact bool                     `json:"totalRowsExact"`   // False when TotalRows is estimated from a partial read
+	Schema         []DatasetColumn          `json:"schema,omitempty"` // Typed columns, for formats that carry a schema (Parquet)
+}
+
+// StorageUsage reports a project's storage consumption against its quota.
+type StorageUsage struct {
+	UsedBytes  int64         `json:"usedBytes"`
+	QuotaBytes int64         `json:"quotaBytes"` // 0 means unlimited
+	Breakdown  []PrefixUsage `json:"breakdown"`
+}
+
+// PrefixUsage summarizes the objects stored under one bucket prefix.
+type PrefixUsage struct {
+	Category    string `json:"category"` // "datasets", "profiles" or "jobOutputs"
+	Prefix      string `json:"prefix"`   // Empty for datasets (bucket root) and profiles (stored next to their datasets)
+	Bytes       int64  `json:"bytes"`
+	ObjectCount int    `json:"objectCount"`
 }
 
 // --- Service Interface ---
@@ -96,9 +112,79 @@ type ProjectService interface {
 	// Requires caller to be Admin or Owner.
 	InviteMember(ctx context.Context, projectID string, callerID string, req InviteMemberRequest) (*core.Project, error)
 
-	// GetDatasetContent retrieves the parsed content of a specific dataset file.
+	// GetDatasetContent retrieves one filtered, sorted page of a dataset file.
 	// Requires projectID, datasetID (likely name), and callerID for authorization.
-	GetDatasetContent(ctx context.Context, projectID string, datasetID string, callerID string) (*DatasetContent, error)
+	GetDatasetContent(ctx context.Context, projectID string, datasetID string, callerID string, query DatasetContentQuery) (*DatasetContent, error)
+
+	// GetDatasetSchema infers column types, nullability and formats from a dataset sample
+	// (sampleRows 0 uses DefaultSchemaSampleRows). Requires Viewer role.
+	GetDatasetSchema(ctx context.Context, projectID string, datasetID string, callerID string, sampleRows int) (*DatasetSchema, error)
+
+	// GetDatasetProfile computes per-column statistics over a whole dataset, reusing the
+	// stored profile unless the dataset changed or refresh is set. Requires Viewer role.
+	GetDatasetProfile(ctx context.Context, projectID string, datasetID string, callerID string, refresh bool) (*DatasetProfile, error)
+
+	// CompareDatasets reports how closely a synthetic dataset matches a real one: marginal
+	// distances, correlations, category coverage and distance to closest record. Requires
+	// Viewer role.
+	CompareDatasets(ctx context.Context, projectID string, callerID string, req DatasetComparisonRequest) (*FidelityReport, error)
+
+	// GetDatasetPIIScan returns the PII findings recorded for a dataset by the scan that runs
+	// after upload. Requires Viewer role.
+	GetDatasetPIIScan(ctx context.Context, projectID string, datasetID string, callerID string) (*PIIScan, error)
+
+	// ScanDatasetPII rescans a dataset for PII and clears any acknowledgement.
+	// Requires caller to be at least a Member of an active project.
+	ScanDatasetPII(ctx context.Context, projectID string, datasetID string, callerID string) (*PIIScan, error)
+
+	// AcknowledgeDatasetPII records that the caller reviewed a completed scan's findings,
+	// which lets jobs read the dataset when the project requires PII acknowledgement.
+	// Requires caller to be at least a Member of an active project.
+	AcknowledgeDatasetPII(ctx context.Context, projectID string, datasetID string, callerID string) (*PIIScan, error)
+
+	// ArchiveProject marks a project as archived (read-only) and runs the archive hook.
+	// Requires caller to be Admin or Owner.
+	ArchiveProject(ctx context.Context, projectID string, callerID string) (*core.Project, error)
+
+	// UnarchiveProject returns an archived project to the active state.
+	// Requires caller to be Admin or Owner.
+	UnarchiveProject(ctx context.Context, projectID string, callerID string) (*core.Project, error)
+
+	// SetArchiveHook registers a callback that runs after a project is archived.
+	// The job service uses it to cancel in-flight jobs without importing this package back.
+	SetArchiveHook(hook ArchiveHook)
+
+	// GetStorageUsage recomputes the project's storage usage and returns a breakdown by prefix.
+	// Requires caller to be at least a Viewer.
+	GetStorageUsage(ctx context.Context, projectID string, callerID string) (*StorageUsage, error)
+
+	// RefreshStorageUsage recomputes and persists the project's UsedStorageBytes.
+	// It performs no authorization and is meant to be called after uploads, deletions and job completions.
+	RefreshStorageUsage(ctx context.Context, projectID string) (*StorageUsage, error)
+
+	// ListDatasets lists one page of the folders and dataset files directly under a prefix.
+	// Requires caller to be at least a Viewer.
+	ListDatasets(ctx context.Context, projectID string, callerID string, req ListDatasetsRequest) (*DatasetListing, error)
+
+	// CreateDatasetFolder creates an empty dataset folder and returns its normalized prefix.
+	// Requires caller to be at least a Member of an active project.
+	CreateDatasetFolder(ctx context.Context, projectID string, callerID string, req CreateFolderRequest) (string, error)
+
+	// UploadDataset stores a dataset file, applying the request's overwrite policy.
+	// Requires caller to be at least a Member of an active project.
+	UploadDataset(ctx context.Context, projectID string, callerID string, req UploadDatasetRequest) (*DatasetMetadata, error)
+
+	// GetDatasetMetadata returns size, checksum, uploader and row/column counts for a dataset.
+	// Requires caller to be at least a Viewer.
+	GetDatasetMetadata(ctx context.Context, projectID string, datasetID string, callerID string) (*DatasetMetadata, error)
+
+	// RenameDataset moves a dataset to a new name. Fails with ErrDatasetExists if the name is taken.
+	// Requires caller to be at least a Member of an active project.
+	RenameDataset(ctx context.Context, projectID string, datasetID string, callerID string, req RenameDatasetRequest) (*DatasetMetadata, error)
+
+	// DeleteDataset removes a dataset file.
+	// Requires caller to be at least a Member of an active project.
+	DeleteDataset(ctx context.Context, projectID string, datasetID string, callerID string) error
 
 	// TODO: Add methods for managing team members (Invite, Remove, UpdateRole)
 }
@@ -112,13 +198,24 @@ var (
 	ErrBucketCreationFailed = errors.New("failed to create storage bucket")
 	ErrBucketDeletionFailed = errors.New("failed to delete storage bucket")
 	ErrProjectUpdateFailed  = errors.New("failed to update project")
+
+	ErrInvalidProjectSettings = errors.New("invalid project settings")
 )
 
+// MaxDataRetentionDays is the longest retention period a project can set.
+const MaxDataRetentionDays = 3650
+
+// ArchiveHook is invoked after a project has been archived.
+// Errors are logged but do not roll back the archive.
+type ArchiveHook func(ctx context.Context, projectID string) error
+
 // projectService provides implementations for the ProjectService interface.
 type projectService struct {
 	projectRepo core.ProjectRepository
 	userRepo    core.UserRepository
 	storageSvc  core.StorageService
+	archiveHook ArchiveHook       // Optional, set via SetArchiveHook
+	runAsync    func(task func()) // Runs background work such as PII scans
 }
 
 // NewProjectService creates a new instance of ProjectService.
@@ -137,11 +234,15 @@ func NewProjectService(projectRepo core.ProjectRepository, userRepo core.UserRep
 		projectRepo: projectRepo,
 		userRepo:    userRepo,
 		storageSvc:  storageSvc,
+		runAsync:    func(task func()) { go task() },
 	}
 }
 
 // CreateProject handles the logic for creating a new project.
 func (s *projectService) CreateProject(ctx context.Context, creatorID string, req CreateProjectRequest) (*core.Project, error) {
+	if err := validateSettings(req.Settings); err != nil {
+		return nil, err
+	}
 	now := time.Now().UTC()
 
 	// 1. Prepare core Project struct
@@ -154,7 +255,7 @@ func (s *projectService) CreateProject(ctx context.Context, creatorID string, re
 		TeamMembers: map[string]core.Role{
 			creatorID: core.RoleOwner,
 		},
-		Status:    "active", // Default status
+		Status:    core.ProjectStatusActive, // Default status
 		CreatedAt: now,
 		UpdatedAt: now,
 		// Storage field is zero-valued initially
@@ -183,6 +284,10 @@ func (s *projectService) CreateProject(ctx context.Context, creatorID string, re
 	}
 	logger.Logger.Info("GCS bucket created", zap.String("bucketName", bucketName), zap.String("projectID", projectID))
 
+	// 3b. Apply the data retention lifecycle. Failure is not fatal: the retention sweeper
+	// still enforces DataRetentionDays for buckets without a lifecycle rule.
+	s.applyBucketRetention(ctx, projectID, bucketName, req.Settings.DataRetentionDays)
+
 	// 4. Update project struct with storage details (using the CORRECT bucketName)
 	newProject.Storage = core.ProjectStorage{
 		BucketName: bucketName,
@@ -206,6 +311,20 @@ func (s *projectService) CreateProject(ctx context.Context, creatorID string, re
 	return newProject, nil
 }
 
+// applyBucketRetention sets the bucket lifecycle from retentionDays, logging rather than
+// failing because the retention sweeper covers buckets without a lifecycle rule.
+func (s *projectService) applyBucketRetention(ctx context.Context, projectID, bucketName string, retentionDays int) {
+	if retentionDays <= 0 {
+		return // Keep data forever; new buckets have no lifecycle rules
+	}
+	err := s.storageSvc.SetBucketRetention(ctx, bucketName, retentionDays)
+	if errors.Is(err, core.ErrNotSupported) {
+		logger.Logger.Info("Storage backend has no lifecycle support; retention left to the sweeper", zap.String("projectID", projectID))
+	} else if err != nil {
+		logger.Logger.Warn("Failed to set bucket retention; retention left to the sweeper", zap.Error(err), zap.String("projectID", projectID), zap.String("bucketName", bucketName))
+	}
+}
+
 // GetProjectByID retrieves a specific project, ensuring the caller has access.
 func (s *projectService) GetProjectByID(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
 	project, err := s.projectRepo.GetProjectByID(ctx, projectID)
@@ -272,6 +391,12 @@ func (s *projectService) ListProjects(ctx context.Context, userID string, status
 
 // UpdateProject handles updating project details.
 func (s *projectService) UpdateProject(ctx context.Context, projectID string, callerID string, req UpdateProjectRequest) (*core.Project, error) {
+	if req.Settings != nil {
+		if err := validateSettings(*req.Settings); err != nil {
+			return nil, err
+		}
+	}
+
 	// 1. Get the existing project
 	project, err := s.projectRepo.GetProjectByID(ctx, projectID)
 	if err != nil {
@@ -291,8 +416,17 @@ func (s *projectService) UpdateProject(ctx context.Context, projectID string, ca
 		return nil, ErrProjectAccessDenied
 	}
 
-	// 3. Apply updates from the request
+	// 3. Archived projects are read-only; only a status change (unarchive) is allowed.
+	if project.Status == core.ProjectStatusArchived && (req.Status == nil || *req.Status == core.ProjectStatusArchived) {
+		if req.Name != nil || req.Description != nil || req.Settings != nil {
+			logger.Logger.Warn("UpdateProject: Attempted to modify archived project", zap.String("projectID", projectID), zap.String("callerID", callerID))
+			return nil, fmt.Errorf("%w: unarchive project %s before editing it", core.ErrProjectArchived, projectID)
+		}
+	}
+
+	// 4. Apply updates from the request
 	updated := false
+	archived := false
 	if req.Name != nil && *req.Name != project.Name {
 		project.Name = *req.Name
 		updated = true
@@ -303,16 +437,24 @@ func (s *projectService) UpdateProject(ctx context.Context, projectID string, ca
 	}
 	if req.Status != nil && *req.Status != project.Status {
 		project.Status = *req.Status
+		archived = project.Status == core.ProjectStatusArchived
 		updated = true
 	}
 	if req.Settings != nil {
+		// Keep the bucket lifecycle in sync before persisting the new retention period
+		if req.Settings.DataRetentionDays != project.Settings.DataRetentionDays && project.Storage.BucketName != "" {
+			err := s.storageSvc.SetBucketRetention(ctx, project.Storage.BucketName, req.Settings.DataRetentionDays)
+			if err != nil && !errors.Is(err, core.ErrNotSupported) {
+				logger.Logger.Error("UpdateProject: Failed to update bucket retention", zap.Error(err), zap.String("projectID", projectID))
+				return nil, fmt.Errorf("%w: failed to update bucket retention: %v", ErrProjectUpdateFailed, err)
+			}
+		}
 		// Simple overwrite for now, could be more granular
 		project.Settings = *req.Settings
 		updated = true
-		// TODO: Potentially update bucket lifecycle policy if retention changes
 	}
 
-	// 4. If changes were made, update timestamp and save
+	// 5. If changes were made, update timestamp and save
 	if updated {
 		project.UpdatedAt = time.Now().UTC()
 		err = s.projectRepo.UpdateProject(ctx, project)
@@ -322,9 +464,80 @@ func (s *projectService) UpdateProject(ctx context.Context, projectID string, ca
 		}
 	}
 
+	// 6. Archiving through a status update has the same side effects as ArchiveProject
+	if archived {
+		s.runArchiveHook(ctx, projectID)
+	}
+
+	return project, nil
+}
+
+// ArchiveProject marks a project as archived and runs the archive hook.
+func (s *projectService) ArchiveProject(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	return s.setProjectStatus(ctx, projectID, callerID, core.ProjectStatusArchived)
+}
+
+// UnarchiveProject returns an archived project to the active state.
+func (s *projectService) UnarchiveProject(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	return s.setProjectStatus(ctx, projectID, callerID, core.ProjectStatusActive)
+}
+
+// SetArchiveHook registers a callback that runs after a project is archived.
+func (s *projectService) SetArchiveHook(hook ArchiveHook) {
+	s.archiveHook = hook
+}
+
+// setProjectStatus transitions a project to newStatus, requiring Admin or Owner.
+// Setting the status the project already has is a no-op.
+func (s *projectService) setProjectStatus(ctx context.Context, projectID string, callerID string, newStatus string) (*core.Project, error) {
+	// 1. Get the existing project
+	project, err := s.projectRepo.GetProjectByID(ctx, projectID)
+	if err != nil {
+		logger.Logger.Error("setProjectStatus: Failed to get project", zap.Error(err), zap.String("projectID", projectID))
+		return nil, fmt.Errorf("failed to retrieve project for status change: %w", err)
+	}
+	if project == nil { // Repo returns nil, nil for not found
+		return nil, ErrProjectNotFound
+	}
+
+	// 2. Authorization Check: User must be Admin or Owner to archive or unarchive
+	if !s.checkProjectAccess(project, callerID, core.RoleAdmin) {
+		logger.Logger.Warn("setProjectStatus: Access denied", zap.String("projectID", projectID), zap.String("callerID", callerID), zap.String("requiredRole", string(core.RoleAdmin)))
+		return nil, ErrProjectAccessDenied
+	}
+
+	if project.Status == newStatus {
+		logger.Logger.Info("setProjectStatus: Project already has the requested status", zap.String("projectID", projectID), zap.String("status", newStatus))
+		return project, nil
+	}
+
+	// 3. Save the new status
+	project.Status = newStatus
+	project.UpdatedAt = time.Now().UTC()
+	if err := s.projectRepo.UpdateProject(ctx, project); err != nil {
+		logger.Logger.Error("setProjectStatus: Failed to save status", zap.Error(err), zap.String("projectID", projectID), zap.String("status", newStatus))
+		return nil, ErrProjectUpdateFailed
+	}
+	logger.Logger.Info("Project status changed", zap.String("projectID", projectID), zap.String("status", newStatus), zap.String("callerID", callerID))
+
+	// 4. Run archive side effects (e.g. cancelling in-flight jobs)
+	if newStatus == core.ProjectStatusArchived {
+		s.runArchiveHook(ctx, projectID)
+	}
+
 	return project, nil
 }
 
+// runArchiveHook invokes the registered archive hook, if any, logging failures.
+func (s *projectService) runArchiveHook(ctx context.Context, projectID string) {
+	if s.archiveHook == nil {
+		return
+	}
+	if err := s.archiveHook(ctx, projectID); err != nil {
+		logger.Logger.Error("Archive hook failed; project remains archived", zap.Error(err), zap.String("projectID", projectID))
+	}
+}
+
 // DeleteProject handles deleting (or archiving) a project.
 func (s *projectService) DeleteProject(ctx context.Context, projectID string, callerID string) error {
 	// 1. Get the existing project
@@ -373,6 +586,113 @@ func (s *projectService) DeleteProject(ctx context.Context, projectID string, ca
 	return nil
 }
 
+// GetStorageUsage computes the project's storage usage and returns a breakdown by prefix.
+// It does not persist the result; uploads, deletes and the retention sweeper do that.
+func (s *projectService) GetStorageUsage(ctx context.Context, projectID string, callerID string) (*StorageUsage, error) {
+	// GetProjectByID performs the Viewer access check
+	project, err := s.GetProjectByID(ctx, projectID, callerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.computeStorageUsage(ctx, project)
+}
+
+// RefreshStorageUsage recomputes and persists the project's UsedStorageBytes.
+func (s *projectService) RefreshStorageUsage(ctx context.Context, projectID string) (*StorageUsage, error) {
+	project, err := s.projectRepo.GetProjectByID(ctx, projectID)
+	if err != nil {
+		logger.Logger.Error("RefreshStorageUsage: Failed to get project", zap.Error(err), zap.String("projectID", projectID))
+		return nil, fmt.Errorf("failed to retrieve project for usage refresh: %w", err)
+	}
+	if project == nil { // Repo returns nil, nil for not found
+		return nil, ErrProjectNotFound
+	}
+	return s.refreshStorageUsage(ctx, project)
+}
+
+// refreshStorageUsage computes the project's usage and saves UsedStorageBytes if it changed.
+// Only that field is written, so concurrent project updates are not overwritten.
+func (s *projectService) refreshStorageUsage(ctx context.Context, project *core.Project) (*StorageUsage, error) {
+	usage, err := s.computeStorageUsage(ctx, project)
+	if err != nil {
+		return nil, err
+	}
+	if usage.UsedBytes != project.Storage.UsedStorageBytes {
+		if err := s.projectRepo.UpdateStorageUsage(ctx, project.ID, usage.UsedBytes); err != nil {
+			logger.Logger.Error("Failed to persist storage usage", zap.Error(err), zap.String("projectID", project.ID))
+			return nil, ErrProjectUpdateFailed
+		}
+		project.Storage.UsedStorageBytes = usage.UsedBytes
+		logger.Logger.Info("Project storage usage updated", zap.String("projectID", project.ID), zap.Int64("usedBytes", usage.UsedBytes))
+	}
+	return usage, nil
+}
+
+// computeStorageUsage lists the project's bucket and returns the usage breakdown. Stored
+// dataset profiles count against the quota but are reported apart from the datasets;
+// PII scan results live in the datasets' object metadata and take no object of their own.
+func (s *projectService) computeStorageUsage(ctx context.Context, project *core.Project) (*StorageUsage, error) {
+	datasets := PrefixUsage{Category: "datasets", Prefix: ""}
+	profiles := PrefixUsage{Category: "profiles", Prefix: ""}
+	jobOutputs := PrefixUsage{Category: "jobOutputs", Prefix: core.JobOutputPrefix}
+
+	if project.Storage.BucketName != "" {
+		objects, err := s.storageSvc.ListObjects(ctx, project.Storage.BucketName, "")
+		if err != nil {
+			logger.Logger.Error("Failed to list objects for storage usage", zap.Error(err), zap.String("projectID", project.ID))
+			return nil, fmt.Errorf("failed to compute storage usage: %w", err)
+		}
+		for _, obj := range objects {
+			if strings.HasPrefix(obj.Name, core.JobOutputPrefix) {
+				jobOutputs.Bytes += obj.Size
+				jobOutputs.ObjectCount++
+			} else if strings.HasSuffix(obj.Name, profileCacheSuffix) {
+				profiles.Bytes += obj.Size
+				profiles.ObjectCount++
+			} else {
+				datasets.Bytes += obj.Size
+				datasets.ObjectCount++
+			}
+		}
+	}
+
+	return &StorageUsage{
+		UsedBytes:  datasets.Bytes + profiles.Bytes + jobOutputs.Bytes,
+		QuotaBytes: StorageQuotaBytes(project),
+		Breakdown:  []PrefixUsage{datasets, profiles, jobOutputs},
+	}, nil
+}
+
+// validateSettings rejects settings that the bucket lifecycle and quota checks cannot apply.
+func validateSettings(settings core.ProjectSettings) error {
+	if settings.DataRetentionDays < 0 || settings.DataRetentionDays > MaxDataRetentionDays {
+		return fmt.Errorf("%w: dataRetentionDays must be between 0 and %d", ErrInvalidProjectSettings, MaxDataRetentionDays)
+	}
+	if settings.MaxStorageGB < 0 {
+		return fmt.Errorf("%w: maxStorageGB cannot be negative", ErrInvalidProjectSettings)
+	}
+	return nil
+}
+
+// StorageQuotaBytes returns the project's storage quota in bytes, or 0 if unlimited.
+func StorageQuotaBytes(project *core.Project) int64 {
+	return int64(project.Settings.MaxStorageGB) << 30
+}
+
+// CheckStorageQuota returns core.ErrStorageQuotaExceeded if adding additionalBytes to the
+// project's recorded usage would exceed its MaxStorageGB. A zero quota means unlimited.
+func CheckStorageQuota(project *core.Project, additionalBytes int64) error {
+	quota := StorageQuotaBytes(project)
+	if quota <= 0 {
+		return nil
+	}
+	projected := project.Storage.UsedStorageBytes + additionalBytes
+	if projected > quota {
+		return fmt.Errorf("%w: projected usage of %d bytes exceeds quota of %d bytes", core.ErrStorageQuotaExceeded, projected, quota)
+	}
+	return nil
+}
+
 // --- Authorization Helper Methods ---
 
 // checkProjectAccess verifies if a user has the required minimum role for a project.
@@ -620,213 +940,159 @@ func (s *projectService) InviteMember(ctx context.Context, projectID string, cal
 	return project, nil
 }
 
-// GetDatasetContent retrieves the parsed content of a specific dataset file.
-func (s *projectService) GetDatasetContent(ctx context.Context, projectID string, datasetID string, callerID string) (*DatasetContent, error) {
+// GetDatasetContent streams one page of a dataset file, applying the query's filters and
+// sort. Without a sort, reading stops as soon as the page is filled and TotalRows is an
+// estimate extrapolated from the bytes consumed; with a sort the whole file is scanned.
+func (s *projectService) GetDatasetContent(ctx context.Context, projectID string, datasetID string, callerID string, query DatasetContentQuery) (*DatasetContent, error) {
 	log := logger.Logger.With(zap.String("projectID", projectID), zap.String("datasetID", datasetID), zap.String("callerID", callerID))
 
-	// 1. Get the project and perform authorization check
-	project, err := s.projectRepo.GetProjectByID(ctx, projectID)
+	query, err := query.normalize()
 	if err != nil {
-		if project == nil && err == nil {
-			log.Warn("Project not found")
-			return nil, ErrProjectNotFound
-		}
-		log.Error("Failed to get project", zap.Error(err))
-		return nil, fmt.Errorf("failed to retrieve project: %w", err)
-	}
-	if project == nil {
-		log.Warn("Project not found (explicit check)")
-		return nil, ErrProjectNotFound
-	}
-
-	if !s.checkProjectAccess(project, callerID, core.RoleViewer) {
-		log.Warn("Access denied")
-		return nil, ErrProjectAccessDenied
+		return nil, err
 	}
 
-	// 2. Determine the dataset's location (assuming datasetID is filename)
-	if project.Storage.BucketName == "" {
-		log.Error("Project storage bucket name is missing")
-		return nil, fmt.Errorf("project %s storage is not configured", projectID)
+	// 1. Get the project and perform authorization check
+	project, err := s.getDatasetProject(ctx, projectID, callerID, core.RoleViewer, false)
+	if err != nil {
+		if errors.Is(err, ErrProjectStorageNotConfigured) {
+			log.Error("Project storage bucket name is missing")
+			return nil, fmt.Errorf("project %s storage is not configured", projectID)
+		}
+		log.Warn("Dataset content request rejected", zap.Error(err))
+		return nil, err
 	}
 	bucketName := project.Storage.BucketName
-	objectPath := datasetID // Assuming datasetID is the filename, e.g., "my_data.csv" or "my_data.json"
-	log = log.With(zap.String("bucket", bucketName), zap.String("objectPath", objectPath))
-	log.Info("Attempting to read dataset from storage")
+	log = log.With(zap.String("bucket", bucketName))
 
-	// 3. Read the dataset content using the StorageService
-	datasetBytes, err := s.storageSvc.ReadObject(ctx, bucketName, objectPath) // <-- Use the new method
+	// 2. Open the dataset as a stream; Parquet needs random access instead
+	fileExt := getExtension(datasetID)
+	if fileExt == ".parquet" {
+		return s.getParquetDatasetContent(ctx, log, bucketName, datasetID, query)
+	}
+	reader, size, err := s.storageSvc.OpenObject(ctx, bucketName, datasetID)
 	if err != nil {
-		// Handle specific ErrNotFound from StorageService
 		if errors.Is(err, core.ErrNotFound) {
 			log.Warn("Dataset file not found in storage", zap.Error(err))
-			// Return a more specific error message to the handler
-			return nil, fmt.Errorf("dataset file '%s' not found in project storage", datasetID)
+			return nil, fmt.Errorf("dataset file '%s' not found in project storage: %w", datasetID, ErrDatasetNotFound)
 		}
-		// Handle other storage errors
-		log.Error("Failed to read object from storage", zap.Error(err))
+		log.Error("Failed to open object in storage", zap.Error(err))
 		return nil, fmt.Errorf("failed to read dataset content: %w", err)
 	}
-	if len(datasetBytes) == 0 {
-		log.Warn("Dataset file is empty")
-		return &DatasetContent{Data: []map[string]interface{}{}}, nil
-	}
+	defer reader.Close()
 
-	log.Info("Successfully read dataset bytes from storage", zap.Int("bytesRead", len(datasetBytes)))
-
-	// 4. Parse the content based on file extension (simple detection)
-	var parsedData []map[string]interface{}
-	fileExt := strings.ToLower(getExtension(objectPath)) // Helper to get extension
-
-	log.Info("Parsing dataset content", zap.String("detectedExtension", fileExt))
-
-	switch fileExt {
-	case ".csv":
-		parsedData, err = parseCSVContent(datasetBytes)
-	case ".json":
-		parsedData, err = parseJSONContent(datasetBytes)
-	// case ".jsonl":
-	// 	 parsedData, err = parseJSONLContent(datasetBytes)
-	// case ".parquet":
-	// 	 // Requires external library like github.com/xitongsys/parquet-go
-	// 	 // parsedData, err = parseParquetContent(datasetBytes)
-	// 	 err = fmt.Errorf("parsing for .parquet files is not yet implemented")
-	default:
-		err = fmt.Errorf("unsupported dataset file type: %s", fileExt)
+	counter := &countingReader{r: reader}
+	rows, err := newRowReader(fileExt, counter)
+	if err != nil {
+		log.Error("Failed to parse dataset content", zap.Error(err), zap.String("fileExtension", fileExt))
+		return nil, fmt.Errorf("failed to parse dataset content (type: %s): %w", fileExt, err)
 	}
 
+	// 3. Scan rows, keeping only what the requested page needs
+	content, err := scanDatasetPage(rows, query)
+	if errors.Is(err, ErrInvalidDatasetQuery) {
+		return nil, err
+	}
 	if err != nil {
 		log.Error("Failed to parse dataset content", zap.Error(err), zap.String("fileExtension", fileExt))
-		// Return a user-friendly parsing error
 		return nil, fmt.Errorf("failed to parse dataset content (type: %s): %w", fileExt, err)
 	}
-
-	log.Info("Successfully parsed dataset content", zap.Int("rowCount", len(parsedData)))
-
-	// 5. Return the parsed data
-	content := &DatasetContent{
-		Data: parsedData,
+	if !content.TotalRowsExact && counter.n > 0 && size > counter.n {
+		// Extrapolate the match rate over the unread remainder of the file
+		if estimate := int(float64(content.TotalRows) * float64(size) / float64(counter.n)); estimate > content.TotalRows {
+			content.TotalRows = estimate
+		}
 	}
+
+	log.Info("Served dataset content page",
+		zap.Int("offset", content.Offset),
+		zap.Int("rows", len(content.Data)),
+		zap.Int("totalRows", content.TotalRows),
+		zap.Bool("totalRowsExact", content.TotalRowsExact),
+		zap.Int64("bytesRead", counter.n),
+	)
 	return content, nil
 }
 
-// getExtension extracts the file extension (including the dot) in lowercase.
-func getExtension(filename string) string {
-	for i := len(filename) - 1; i >= 0; i-- {
-		if filename[i] == '.' {
-			return strings.ToLower(filename[i:])
-		}
-		if filename[i] == '/' || filename[i] == '\\' { // Stop if path separator found
-			break
+// scanDatasetPage reads rows until the page defined by query is complete. Sorted queries
+// read everything, holding at most offset+limit rows (plus a batch) in memory.
+func scanDatasetPage(rows rowReader, query DatasetContentQuery) (*DatasetContent, error) {
+	content := &DatasetContent{Data: []map[string]interface{}{}, Offset: query.Offset, Limit: query.Limit}
+	window := query.Offset + query.Limit
+	sorted := len(query.Sort) > 0
+
+	// Formats with a header or schema can reject unknown columns before reading any rows
+	if rows.ColumnsKnown() && len(rows.Columns()) > 0 {
+		if err := query.validateColumns(rows.Columns()); err != nil {
+			return nil, err
 		}
 	}
-	return "" // No extension found
-}
-
-// parseCSVContent parses CSV bytes into a slice of maps.
-func parseCSVContent(data []byte) ([]map[string]interface{}, error) {
-	reader := csv.NewReader(bytes.NewReader(data))
-
-	// Read header row
-	headers, err := reader.Read()
-	if err == io.EOF {
-		return []map[string]interface{}{}, nil // Empty file is valid
-	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to read CSV header: %w", err)
-	}
-	// Trim whitespace from headers
-	for i := range headers {
-		headers[i] = strings.TrimSpace(headers[i])
-	}
-
-	var results []map[string]interface{}
 
-	// Read data rows
-	lineNum := 1 // Start counting lines after header
+	var kept []sortedRow
+	matched, seq := 0, 0
 	for {
-		lineNum++
-		record, err := reader.Read()
+		row, err := rows.Next()
 		if err == io.EOF {
+			content.TotalRowsExact = true
 			break
 		}
-		// Handle parsing errors within a row (e.g., incorrect number of fields if LazyQuotes is false)
 		if err != nil {
-			// Log the specific error and line number
-			logger.Logger.Error("Error reading CSV row", zap.Error(err), zap.Int("lineNumber", lineNum))
-			// Return an error indicating the problematic line
-			return nil, fmt.Errorf("error reading CSV data on line %d: %w", lineNum, err)
+			return nil, err
 		}
-
-		// Handle rows with incorrect number of columns
-		if len(record) != len(headers) {
-			logger.Logger.Warn("CSV row length mismatch", zap.Int("headerLength", len(headers)), zap.Int("rowLength", len(record)), zap.Strings("row", record), zap.Int("lineNumber", lineNum))
-			// Skip row or return error? Returning error for stricter validation.
-			return nil, fmt.Errorf("CSV row length mismatch on line %d: expected %d columns, got %d", lineNum, len(headers), len(record))
+		seq++
+		if !query.matches(row) {
+			continue
 		}
-
-		rowMap := make(map[string]interface{})
-		for i, header := range headers {
-			// Store as string, frontend can attempt further parsing/display
-			rowMap[header] = record[i]
+		matched++
+		if sorted {
+			kept = append(kept, sortedRow{seq: seq, row: row})
+			if len(kept) >= 2*window+MaxDatasetContentLimit { // Trim periodically rather than per row
+				sortRows(kept, query.Sort)
+				kept = kept[:window]
+			}
+			continue
 		}
-		results = append(results, rowMap)
-	}
-
-	return results, nil
-}
-
-// parseJSONContent parses JSON bytes (expected as a JSON array of objects) into a slice of maps.
-func parseJSONContent(data []byte) ([]map[string]interface{}, error) {
-	var results []map[string]interface{}
-
-	err := json.Unmarshal(data, &results)
-	if err != nil {
-		// Check if it might be JSON Lines instead of a single array
-		// A simple check: does it NOT start with '[' and contain '}'?
-		trimmedData := bytes.TrimSpace(data)
-		if len(trimmedData) > 0 && trimmedData[0] != '[' && bytes.Contains(trimmedData, []byte("\n")) {
-			// Attempt to parse as JSON Lines if initial array parse failed
-			log := logger.Logger // Assuming logger is accessible or passed in
-			log.Info("JSON array parsing failed, attempting JSON Lines parsing")
-			results, err = parseJSONLContent(data)
-			if err == nil {
-				return results, nil // Successfully parsed as JSONL
+		if matched > query.Offset {
+			content.Data = append(content.Data, row)
+			if len(content.Data) == query.Limit {
+				break // Page is full; leave the rest of the file unread
 			}
-			// If JSONL also fails, return the original Unmarshal error
-			return nil, fmt.Errorf("failed to parse content as JSON array or JSON Lines: %w", err)
 		}
-		// Return the original JSON array unmarshal error
-		return nil, fmt.Errorf("failed to unmarshal JSON array: %w", err)
 	}
-	return results, nil
-}
 
-// parseJSONLContent parses JSON Lines bytes into a slice of maps.
-func parseJSONLContent(data []byte) ([]map[string]interface{}, error) {
-	var results []map[string]interface{}
-	scanner := bufio.NewScanner(bytes.NewReader(data))
-	lineNum := 0
-	for scanner.Scan() {
-		lineNum++
-		lineBytes := scanner.Bytes()
-		// Skip empty lines
-		if len(bytes.TrimSpace(lineBytes)) == 0 {
-			continue
+	content.Columns = rows.Columns()
+	if content.Columns == nil {
+		content.Columns = []string{}
+	}
+	// Otherwise unknown columns can only be reported once the whole file has been seen; an
+	// early stop means every filter already matched at least one row.
+	if !rows.ColumnsKnown() && content.TotalRowsExact && len(content.Columns) > 0 {
+		if err := query.validateColumns(content.Columns); err != nil {
+			return nil, err
 		}
-
-		var rowMap map[string]interface{}
-		if err := json.Unmarshal(lineBytes, &rowMap); err != nil {
-			logger.Logger.Error("Failed to unmarshal JSON line", zap.Error(err), zap.Int("lineNumber", lineNum))
-			return nil, fmt.Errorf("failed to parse JSON on line %d: %w", lineNum, err)
+	}
+	if sorted {
+		sortRows(kept, query.Sort)
+		for i := query.Offset; i < len(kept) && i < window; i++ {
+			content.Data = append(content.Data, kept[i].row)
 		}
-		results = append(results, rowMap)
 	}
-
-	if err := scanner.Err(); err != nil {
-		logger.Logger.Error("Error scanning JSON Lines data", zap.Error(err))
-		return nil, fmt.Errorf("error scanning JSON Lines data: %w", err)
+	if len(query.Columns) > 0 {
+		content.Data = projectRows(content.Data, query.Columns)
+		content.Columns = query.Columns
 	}
+	content.TotalRows = matched
+	return content, nil
+}
 
-	return results, nil
+// getExtension extracts the file extension (including the dot) in lowercase.
+func getExtension(filename string) string {
+	for i := len(filename) - 1; i >= 0; i-- {
+		if filename[i] == '.' {
+			return strings.ToLower(filename[i:])
+		}
+		if filename[i] == '/' || filename[i] == '\\' { // Stop if path separator found
+			break
+		}
+	}
+	return "" // No extension found
 }
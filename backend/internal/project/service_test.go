@@ -4,6 +4,7 @@ import (
 	"SynDataGen/backend/internal/core"
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -48,11 +49,29 @@ func (m *MockProjectRepository) ListProjects(ctx context.Context, userID string,
 	return args.Get(0).([]*core.Project), args.Error(1)
 }
 
+func (m *MockProjectRepository) ListProjectsByCustomer(ctx context.Context, customerID string, statusFilter string) ([]*core.Project, error) {
+	args := m.Called(ctx, customerID, statusFilter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Project), args.Error(1)
+}
+
 func (m *MockProjectRepository) CountProjects(ctx context.Context, userID string, statusFilter string) (int, error) {
 	args := m.Called(ctx, userID, statusFilter)
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockProjectRepository) UpdateStorageUsage(ctx context.Context, projectID string, usedBytes int64) error {
+	args := m.Called(ctx, projectID, usedBytes)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) UpdateStorageSampledPeriod(ctx context.Context, projectID string, period time.Time) error {
+	args := m.Called(ctx, projectID, period)
+	return args.Error(0)
+}
+
 func (m *MockProjectRepository) UpdateProject(ctx context.Context, project *core.Project) error {
 	args := m.Called(ctx, project)
 	// Simulate timestamp update on successful call
@@ -67,6 +86,14 @@ func (m *MockProjectRepository) DeleteProject(ctx context.Context, id string) er
 	return args.Error(0)
 }
 
+func (m *MockProjectRepository) ListAllProjects(ctx context.Context) ([]*core.Project, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Project), args.Error(1)
+}
+
 // MockUserRepository is a mock implementation of core.UserRepository
 type MockUserRepository struct {
 	mock.Mock
@@ -108,6 +135,89 @@ func (m *MockStorageService) DeleteProjectBucket(ctx context.Context, bucketName
 	return args.Error(0)
 }
 
+func (m *MockStorageService) UploadFile(ctx context.Context, bucketName, objectName string, reader io.Reader) (string, error) {
+	args := m.Called(ctx, bucketName, objectName, reader)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockStorageService) UploadNewFile(ctx context.Context, bucketName, objectName string, reader io.Reader) (string, error) {
+	args := m.Called(ctx, bucketName, objectName, reader)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockStorageService) ListObjects(ctx context.Context, bucketName, prefix string) ([]core.ObjectSummary, error) {
+	args := m.Called(ctx, bucketName, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]core.ObjectSummary), args.Error(1)
+}
+
+func (m *MockStorageService) ListObjectsPage(ctx context.Context, bucketName string, opts core.ListObjectsOptions) (*core.ObjectPage, error) {
+	args := m.Called(ctx, bucketName, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.ObjectPage), args.Error(1)
+}
+
+func (m *MockStorageService) ReadObject(ctx context.Context, bucketName, objectName string) ([]byte, error) {
+	args := m.Called(ctx, bucketName, objectName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockStorageService) OpenObject(ctx context.Context, bucketName, objectName string) (io.ReadCloser, int64, error) {
+	args := m.Called(ctx, bucketName, objectName)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).(io.ReadCloser), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStorageService) OpenObjectReaderAt(ctx context.Context, bucketName, objectName string) (io.ReaderAt, int64, error) {
+	args := m.Called(ctx, bucketName, objectName)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).(io.ReaderAt), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStorageService) DeleteObject(ctx context.Context, bucketName, objectName string) error {
+	args := m.Called(ctx, bucketName, objectName)
+	return args.Error(0)
+}
+
+func (m *MockStorageService) GetObjectMetadata(ctx context.Context, bucketName, objectName string) (*core.ObjectMetadata, error) {
+	args := m.Called(ctx, bucketName, objectName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.ObjectMetadata), args.Error(1)
+}
+
+func (m *MockStorageService) UpdateObjectMetadata(ctx context.Context, bucketName, objectName string, metadata map[string]string) error {
+	args := m.Called(ctx, bucketName, objectName, metadata)
+	return args.Error(0)
+}
+
+func (m *MockStorageService) CopyObject(ctx context.Context, bucketName, srcObjectName, dstObjectName string) error {
+	args := m.Called(ctx, bucketName, srcObjectName, dstObjectName)
+	return args.Error(0)
+}
+
+func (m *MockStorageService) SetBucketRetention(ctx context.Context, bucketName string, retentionDays int) error {
+	args := m.Called(ctx, bucketName, retentionDays)
+	return args.Error(0)
+}
+
+func (m *MockStorageService) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 // Helper to create service with mocks for testing project service methods
 func setupProjectServiceTest() (ProjectService, *MockProjectRepository, *MockUserRepository, *MockStorageService) {
 	mockProjectRepo := new(MockProjectRepository)
@@ -115,6 +225,7 @@ func setupProjectServiceTest() (ProjectService, *MockProjectRepository, *MockUse
 	mockStorageSvc := new(MockStorageService)
 
 	service := NewProjectService(mockProjectRepo, mockUserRepo, mockStorageSvc)
+	service.(*projectService).runAsync = func(func()) {} // Background scans are tested directly
 	return service, mockProjectRepo, mockUserRepo, mockStorageSvc
 }
 
@@ -836,3 +947,398 @@ func TestProjectService_RemoveMember(t *testing.T) {
 		mockProjectRepo.AssertExpectations(t)
 	})
 }
+
+func TestProjectService_ArchiveProject(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-archive-" + uuid.NewString()
+	ownerID := "user-owner"
+	adminID := "user-admin"
+	memberID := "user-member"
+
+	newProject := func(status string) *core.Project {
+		return &core.Project{
+			ID:     projectID,
+			Name:   "Archive Test Project",
+			Status: status,
+			TeamMembers: map[string]core.Role{
+				ownerID:  core.RoleOwner,
+				adminID:  core.RoleAdmin,
+				memberID: core.RoleMember,
+			},
+		}
+	}
+
+	t.Run("Success_AdminArchivesAndHookRuns", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		var hookProjectID string
+		service.SetArchiveHook(func(ctx context.Context, projectID string) error {
+			hookProjectID = projectID
+			return nil
+		})
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(core.ProjectStatusActive), nil).Once()
+		mockProjectRepo.On("UpdateProject", ctx, mock.MatchedBy(func(p *core.Project) bool {
+			return p.Status == core.ProjectStatusArchived
+		})).Return(nil).Once()
+
+		project, err := service.ArchiveProject(ctx, projectID, adminID)
+
+		require.NoError(err)
+		assert.Equal(core.ProjectStatusArchived, project.Status)
+		assert.Equal(projectID, hookProjectID)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_HookErrorDoesNotFailArchive", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		service.SetArchiveHook(func(ctx context.Context, projectID string) error {
+			return errors.New("cancel failed")
+		})
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(core.ProjectStatusActive), nil).Once()
+		mockProjectRepo.On("UpdateProject", ctx, mock.AnythingOfType("*core.Project")).Return(nil).Once()
+
+		project, err := service.ArchiveProject(ctx, projectID, ownerID)
+
+		require.NoError(err)
+		assert.Equal(core.ProjectStatusArchived, project.Status)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_AlreadyArchivedIsNoOp", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		service.SetArchiveHook(func(ctx context.Context, projectID string) error {
+			t.Fatal("hook should not run for an already archived project")
+			return nil
+		})
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(core.ProjectStatusArchived), nil).Once()
+		// UpdateProject should NOT be called
+
+		project, err := service.ArchiveProject(ctx, projectID, adminID)
+
+		require.NoError(err)
+		assert.Equal(core.ProjectStatusArchived, project.Status)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_MemberCannotArchive", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(core.ProjectStatusActive), nil).Once()
+
+		project, err := service.ArchiveProject(ctx, projectID, memberID)
+
+		require.Error(err)
+		assert.Nil(project)
+		assert.ErrorIs(err, ErrProjectAccessDenied)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_OwnerUnarchives", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(core.ProjectStatusArchived), nil).Once()
+		mockProjectRepo.On("UpdateProject", ctx, mock.MatchedBy(func(p *core.Project) bool {
+			return p.Status == core.ProjectStatusActive
+		})).Return(nil).Once()
+
+		project, err := service.UnarchiveProject(ctx, projectID, ownerID)
+
+		require.NoError(err)
+		assert.Equal(core.ProjectStatusActive, project.Status)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_MemberCannotUnarchive", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(core.ProjectStatusArchived), nil).Once()
+
+		project, err := service.UnarchiveProject(ctx, projectID, memberID)
+
+		require.Error(err)
+		assert.Nil(project)
+		assert.ErrorIs(err, ErrProjectAccessDenied)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_ProjectNotFound", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(nil, nil).Once()
+
+		project, err := service.ArchiveProject(ctx, projectID, ownerID)
+
+		require.Error(err)
+		assert.Nil(project)
+		assert.ErrorIs(err, ErrProjectNotFound)
+	})
+
+	t.Run("Failure_EditArchivedProject", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		name := "Renamed"
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(core.ProjectStatusArchived), nil).Once()
+		// UpdateProject should NOT be called
+
+		project, err := service.UpdateProject(ctx, projectID, adminID, UpdateProjectRequest{Name: &name})
+
+		require.Error(err)
+		assert.Nil(project)
+		assert.ErrorIs(err, core.ErrProjectArchived)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_ArchiveViaUpdateRunsHook", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+		hookCalled := false
+		service.SetArchiveHook(func(ctx context.Context, projectID string) error {
+			hookCalled = true
+			return nil
+		})
+		archivedStatus := core.ProjectStatusArchived
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(core.ProjectStatusActive), nil).Once()
+		mockProjectRepo.On("UpdateProject", ctx, mock.AnythingOfType("*core.Project")).Return(nil).Once()
+
+		project, err := service.UpdateProject(ctx, projectID, adminID, UpdateProjectRequest{Status: &archivedStatus})
+
+		require.NoError(err)
+		assert.Equal(core.ProjectStatusArchived, project.Status)
+		assert.True(hookCalled)
+		mockProjectRepo.AssertExpectations(t)
+	})
+}
+
+func TestProjectService_GetStorageUsage(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-usage-" + uuid.NewString()
+	viewerID := "user-viewer"
+	bucketName := "bucket-usage"
+
+	newProject := func(usedBytes int64) *core.Project {
+		return &core.Project{
+			ID:          projectID,
+			Storage:     core.ProjectStorage{BucketName: bucketName, UsedStorageBytes: usedBytes},
+			Settings:    core.ProjectSettings{MaxStorageGB: 2},
+			TeamMembers: map[string]core.Role{viewerID: core.RoleViewer},
+		}
+	}
+	objects := []core.ObjectSummary{
+		{Name: "customers.csv", Size: 80},
+		{Name: "customers.csv.profile.json", Size: 20},
+		{Name: "jobs/job-1/output.csv", Size: 250},
+		{Name: "jobs/job-2/output.json", Size: 50},
+	}
+
+	t.Run("Success_ComputesBreakdownWithoutWriting", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(0), nil).Once()
+		mockStorage.On("ListObjects", ctx, bucketName, "").Return(objects, nil).Once()
+		// Neither UpdateStorageUsage nor UpdateProject should be called
+
+		usage, err := service.GetStorageUsage(ctx, projectID, viewerID)
+
+		require.NoError(err)
+		assert.Equal(int64(400), usage.UsedBytes)
+		assert.Equal(int64(2)<<30, usage.QuotaBytes)
+		require.Len(usage.Breakdown, 3)
+		assert.Equal(PrefixUsage{Category: "datasets", Bytes: 80, ObjectCount: 1}, usage.Breakdown[0])
+		assert.Equal(PrefixUsage{Category: "profiles", Bytes: 20, ObjectCount: 1}, usage.Breakdown[1])
+		assert.Equal(PrefixUsage{Category: "jobOutputs", Prefix: core.JobOutputPrefix, Bytes: 300, ObjectCount: 2}, usage.Breakdown[2])
+		mockProjectRepo.AssertExpectations(t)
+		mockStorage.AssertExpectations(t)
+	})
+
+	t.Run("Success_RefreshPersistsOnlyUsage", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(0), nil).Once()
+		mockStorage.On("ListObjects", ctx, bucketName, "").Return(objects, nil).Once()
+		mockProjectRepo.On("UpdateStorageUsage", ctx, projectID, int64(400)).Return(nil).Once()
+
+		usage, err := service.RefreshStorageUsage(ctx, projectID)
+
+		require.NoError(err)
+		assert.Equal(int64(400), usage.UsedBytes)
+		mockProjectRepo.AssertExpectations(t)
+		mockProjectRepo.AssertNotCalled(t, "UpdateProject", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_UnchangedUsageIsNotPersisted", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(400), nil).Once()
+		mockStorage.On("ListObjects", ctx, bucketName, "").Return(objects, nil).Once()
+		// UpdateStorageUsage should NOT be called
+
+		usage, err := service.RefreshStorageUsage(ctx, projectID)
+
+		require.NoError(err)
+		assert.Equal(int64(400), usage.UsedBytes)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_StrangerDenied", func(t *testing.T) {
+		service, mockProjectRepo, _, _ := setupProjectServiceTest()
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(0), nil).Once()
+
+		usage, err := service.GetStorageUsage(ctx, projectID, "user-stranger")
+
+		require.Error(err)
+		assert.Nil(usage)
+		assert.ErrorIs(err, ErrProjectAccessDenied)
+	})
+
+	t.Run("Failure_ListObjectsError", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		listErr := errors.New("gcs unavailable")
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(0), nil).Once()
+		mockStorage.On("ListObjects", ctx, bucketName, "").Return(nil, listErr).Once()
+
+		usage, err := service.RefreshStorageUsage(ctx, projectID)
+
+		require.Error(err)
+		assert.Nil(usage)
+		assert.ErrorIs(err, listErr)
+	})
+}
+
+func TestCheckStorageQuota(t *testing.T) {
+	const gb = int64(1) << 30
+
+	tests := []struct {
+		name       string
+		maxGB      int
+		usedBytes  int64
+		additional int64
+		wantErr    bool
+	}{
+		{"Unlimited quota", 0, 100 * gb, gb, false},
+		{"Within quota", 2, gb, gb / 2, false},
+		{"Exactly at quota", 2, gb, gb, false},
+		{"Exceeds quota", 2, gb, gb + 1, true},
+		{"Already over quota", 1, 2 * gb, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := &core.Project{
+				Settings: core.ProjectSettings{MaxStorageGB: tt.maxGB},
+				Storage:  core.ProjectStorage{UsedStorageBytes: tt.usedBytes},
+			}
+			err := CheckStorageQuota(project, tt.additional)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, core.ErrStorageQuotaExceeded)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProjectService_UpdateProject_Retention(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	ctx := context.Background()
+
+	projectID := "proj-retention-" + uuid.NewString()
+	adminID := "user-admin"
+	bucketName := "bucket-retention"
+
+	newProject := func() *core.Project {
+		return &core.Project{
+			ID:          projectID,
+			Status:      core.ProjectStatusActive,
+			Storage:     core.ProjectStorage{BucketName: bucketName},
+			Settings:    core.ProjectSettings{DataRetentionDays: 30, MaxStorageGB: 10},
+			TeamMembers: map[string]core.Role{adminID: core.RoleAdmin},
+		}
+	}
+
+	t.Run("Success_RetentionChangeUpdatesBucket", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		settings := core.ProjectSettings{DataRetentionDays: 7, MaxStorageGB: 10}
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(), nil).Once()
+		mockStorage.On("SetBucketRetention", ctx, bucketName, 7).Return(nil).Once()
+		mockProjectRepo.On("UpdateProject", ctx, mock.MatchedBy(func(p *core.Project) bool {
+			return p.Settings.DataRetentionDays == 7
+		})).Return(nil).Once()
+
+		project, err := service.UpdateProject(ctx, projectID, adminID, UpdateProjectRequest{Settings: &settings})
+
+		require.NoError(err)
+		assert.Equal(7, project.Settings.DataRetentionDays)
+		mockStorage.AssertExpectations(t)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_UnsupportedBackendStillSaves", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		settings := core.ProjectSettings{DataRetentionDays: 90, MaxStorageGB: 10}
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(), nil).Once()
+		mockStorage.On("SetBucketRetention", ctx, bucketName, 90).Return(core.ErrNotSupported).Once()
+		mockProjectRepo.On("UpdateProject", ctx, mock.AnythingOfType("*core.Project")).Return(nil).Once()
+
+		_, err := service.UpdateProject(ctx, projectID, adminID, UpdateProjectRequest{Settings: &settings})
+
+		require.NoError(err)
+		mockProjectRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success_UnchangedRetentionSkipsBucket", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		settings := core.ProjectSettings{DataRetentionDays: 30, MaxStorageGB: 20}
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(), nil).Once()
+		mockProjectRepo.On("UpdateProject", ctx, mock.AnythingOfType("*core.Project")).Return(nil).Once()
+
+		_, err := service.UpdateProject(ctx, projectID, adminID, UpdateProjectRequest{Settings: &settings})
+
+		require.NoError(err)
+		mockStorage.AssertNotCalled(t, "SetBucketRetention", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_InvalidRetentionRejectedUpFront", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+
+		for _, days := range []int{-1, MaxDataRetentionDays + 1} {
+			settings := core.ProjectSettings{DataRetentionDays: days, MaxStorageGB: 10}
+
+			project, err := service.UpdateProject(ctx, projectID, adminID, UpdateProjectRequest{Settings: &settings})
+
+			assert.Nil(project)
+			assert.ErrorIs(err, ErrInvalidProjectSettings)
+		}
+		mockProjectRepo.AssertNotCalled(t, "GetProjectByID", mock.Anything, mock.Anything)
+		mockStorage.AssertNotCalled(t, "SetBucketRetention", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_BucketUpdateErrorDoesNotSave", func(t *testing.T) {
+		service, mockProjectRepo, _, mockStorage := setupProjectServiceTest()
+		settings := core.ProjectSettings{DataRetentionDays: 1, MaxStorageGB: 10}
+
+		mockProjectRepo.On("GetProjectByID", ctx, projectID).Return(newProject(), nil).Once()
+		mockStorage.On("SetBucketRetention", ctx, bucketName, 1).Return(errors.New("permission denied")).Once()
+		// UpdateProject should NOT be called
+
+		project, err := service.UpdateProject(ctx, projectID, adminID, UpdateProjectRequest{Settings: &settings})
+
+		require.Error(err)
+		assert.Nil(project)
+		assert.ErrorIs(err, ErrProjectUpdateFailed)
+		mockProjectRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,189 @@
+package retention
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/lease"
+	"SynDataGen/backend/internal/platform/logger"
+	"SynDataGen/backend/internal/project"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultInterval is how often the sweeper runs when no interval is configured.
+const DefaultInterval = 24 * time.Hour
+
+const (
+	leaseName   = "retention-sweeper" // Lease held by the replica that sweeps
+	jobPageSize = 100                 // Page size used when scanning a project's jobs
+)
+
+// UsageRefresher recomputes a project's stored usage after objects are deleted.
+// project.ProjectService satisfies this interface.
+type UsageRefresher interface {
+	RefreshStorageUsage(ctx context.Context, projectID string) (*project.StorageUsage, error)
+}
+
+// Config holds configuration for the retention Sweeper.
+type Config struct {
+	Interval time.Duration // Time between sweeps; defaults to DefaultInterval
+	HolderID string        // Identifies this replica in the lease; required
+}
+
+// SweepResult summarizes a single sweep.
+type SweepResult struct {
+	ProjectsSwept  int
+	ObjectsDeleted int
+	BytesFreed     int64
+	JobsExpired    int
+}
+
+// Sweeper enforces ProjectSettings.DataRetentionDays independently of the storage backend.
+// It deletes objects older than the retention period (a no-op where a bucket lifecycle rule
+// already removed them) and marks the affected jobs' results as expired. Only the replica
+// holding the retention lease sweeps.
+type Sweeper struct {
+	projectRepo core.ProjectRepository
+	jobRepo     core.JobRepository
+	storageSvc  core.StorageService
+	usage       UsageRefresher
+	runner      *lease.Runner
+	now         func() time.Time // Overridable for tests
+}
+
+// NewSweeper creates a new retention Sweeper.
+func NewSweeper(projectRepo core.ProjectRepository, jobRepo core.JobRepository, storageSvc core.StorageService, usage UsageRefresher, leaseRepo core.LeaseRepository, cfg Config) *Sweeper {
+	if projectRepo == nil || jobRepo == nil || storageSvc == nil || usage == nil || leaseRepo == nil {
+		panic("retention.NewSweeper: all dependencies are required")
+	}
+	if cfg.HolderID == "" {
+		panic("retention.NewSweeper: HolderID is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	return &Sweeper{
+		projectRepo: projectRepo,
+		jobRepo:     jobRepo,
+		storageSvc:  storageSvc,
+		usage:       usage,
+		runner:      lease.NewRunner(leaseRepo, leaseName, cfg.HolderID, cfg.Interval),
+		now:         func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run sweeps immediately and then once per interval until ctx is cancelled.
+func (s *Sweeper) Run(ctx context.Context) {
+	s.runner.Run(ctx, func(ctx context.Context) error {
+		_, err := s.sweep(ctx)
+		return err
+	})
+}
+
+// SweepOnce performs a single pass over all projects, if this replica holds the lease.
+func (s *Sweeper) SweepOnce(ctx context.Context) (SweepResult, error) {
+	var res SweepResult
+	_, err := s.runner.TickOnce(ctx, func(ctx context.Context) (err error) {
+		res, err = s.sweep(ctx)
+		return err
+	})
+	return res, err
+}
+
+// sweep passes over all projects. Per-project failures are logged and the sweep continues;
+// the returned error reports how many projects failed.
+func (s *Sweeper) sweep(ctx context.Context) (SweepResult, error) {
+	var total SweepResult
+
+	projects, err := s.projectRepo.ListAllProjects(ctx)
+	if err != nil {
+		return total, fmt.Errorf("failed to list projects for retention sweep: %w", err)
+	}
+
+	now := s.now()
+	failed := 0
+	for _, proj := range projects {
+		if proj.Settings.DataRetentionDays <= 0 {
+			continue // Data is kept forever
+		}
+		res, err := s.sweepProject(ctx, proj, now)
+		total.ProjectsSwept++
+		total.ObjectsDeleted += res.ObjectsDeleted
+		total.BytesFreed += res.BytesFreed
+		total.JobsExpired += res.JobsExpired
+		if err != nil {
+			failed++
+			logger.Logger.Error("Retention sweep failed for project", zap.String("projectID", proj.ID), zap.Error(err))
+		}
+	}
+
+	logger.Logger.Info("Retention sweep completed",
+		zap.Int("projectsSwept", total.ProjectsSwept),
+		zap.Int("objectsDeleted", total.ObjectsDeleted),
+		zap.Int64("bytesFreed", total.BytesFreed),
+		zap.Int("jobsExpired", total.JobsExpired),
+		zap.Int("projectsFailed", failed),
+	)
+	if failed > 0 {
+		return total, fmt.Errorf("retention sweep failed for %d of %d projects", failed, total.ProjectsSwept)
+	}
+	return total, nil
+}
+
+// sweepProject deletes expired objects and marks expired job results for one project.
+func (s *Sweeper) sweepProject(ctx context.Context, proj *core.Project, now time.Time) (SweepResult, error) {
+	var res SweepResult
+	cutoff := now.AddDate(0, 0, -proj.Settings.DataRetentionDays)
+	log := logger.Logger.With(zap.String("projectID", proj.ID), zap.Time("cutoff", cutoff))
+
+	// 1. Delete objects last written before the cutoff
+	if proj.Storage.BucketName != "" {
+		objects, err := s.storageSvc.ListObjects(ctx, proj.Storage.BucketName, "")
+		if err != nil {
+			return res, fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range objects {
+			if !obj.LastUpdated.Before(cutoff) {
+				continue
+			}
+			err := s.storageSvc.DeleteObject(ctx, proj.Storage.BucketName, obj.Name)
+			if err != nil && !errors.Is(err, core.ErrNotFound) { // Lifecycle rule may have won the race
+				return res, fmt.Errorf("failed to delete expired object %s: %w", obj.Name, err)
+			}
+			res.ObjectsDeleted++
+			res.BytesFreed += obj.Size
+			log.Debug("Deleted expired object", zap.String("object", obj.Name))
+		}
+		if res.ObjectsDeleted > 0 {
+			if _, err := s.usage.RefreshStorageUsage(ctx, proj.ID); err != nil {
+				log.Warn("Failed to refresh storage usage after retention sweep", zap.Error(err))
+			}
+		}
+	}
+
+	// 2. Mark results of jobs completed before the cutoff as expired, whether the sweeper
+	// or a bucket lifecycle rule removed the underlying objects.
+	for offset := 0; ; offset += jobPageSize {
+		jobs, total, err := s.jobRepo.ListJobsByProjectID(ctx, proj.ID, jobPageSize, offset)
+		if err != nil {
+			return res, fmt.Errorf("failed to list jobs: %w", err)
+		}
+		for _, job := range jobs {
+			if job.ResultURI == "" || job.ResultExpiredAt != nil || job.CompletedAt == nil || !job.CompletedAt.Before(cutoff) {
+				continue
+			}
+			if err := s.jobRepo.MarkJobResultExpired(ctx, job.ID, now); err != nil {
+				return res, fmt.Errorf("failed to mark result expired for job %s: %w", job.ID, err)
+			}
+			res.JobsExpired++
+		}
+		if len(jobs) < jobPageSize || offset+len(jobs) >= total {
+			break
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,406 @@
+package retention
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/project"
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mocks ---
+
+type MockProjectRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectRepository) CreateProject(ctx context.Context, p *core.Project) (string, error) {
+	args := m.Called(ctx, p)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockProjectRepository) GetProjectByID(ctx context.Context, id string) (*core.Project, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) ListProjects(ctx context.Context, customerID string, statusFilter string, limit, offset int) ([]*core.Project, error) {
+	args := m.Called(ctx, customerID, statusFilter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) ListProjectsByCustomer(ctx context.Context, customerID string, statusFilter string) ([]*core.Project, error) {
+	args := m.Called(ctx, customerID, statusFilter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) CountProjects(ctx context.Context, customerID string, statusFilter string) (int, error) {
+	args := m.Called(ctx, customerID, statusFilter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockProjectRepository) UpdateStorageUsage(ctx context.Context, projectID string, usedBytes int64) error {
+	args := m.Called(ctx, projectID, usedBytes)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) UpdateStorageSampledPeriod(ctx context.Context, projectID string, period time.Time) error {
+	args := m.Called(ctx, projectID, period)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) UpdateProject(ctx context.Context, p *core.Project) error {
+	args := m.Called(ctx, p)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) DeleteProject(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) ListAllProjects(ctx context.Context) ([]*core.Project, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Project), args.Error(1)
+}
+
+type MockJobRepository struct {
+	mock.Mock
+}
+
+func (m *MockJobRepository) CreateJob(ctx context.Context, job *core.Job) error {
+	args := m.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) GetJobByID(ctx context.Context, jobID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) ListJobsByProjectID(ctx context.Context, projectID string, limit, offset int) ([]*core.Job, int, error) {
+	args := m.Called(ctx, projectID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.Job), args.Int(1), args.Error(2)
+}
+
+func (m *MockJobRepository) UpdateJobStatus(ctx context.Context, jobID string, newStatus core.JobStatus, pipelineJobID string, startedAt, completedAt *time.Time, jobError string) error {
+	args := m.Called(ctx, jobID, newStatus, pipelineJobID, startedAt, completedAt, jobError)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) TransitionJobStatus(ctx context.Context, jobID string, fromStatus, newStatus core.JobStatus, pipelineJobID string, startedAt *time.Time, completedAt *time.Time, jobError string) error {
+	args := m.Called(ctx, jobID, fromStatus, newStatus, pipelineJobID, startedAt, completedAt, jobError)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateJobResult(ctx context.Context, jobID string, resultURI string) error {
+	args := m.Called(ctx, jobID, resultURI)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) UpdateJobActuals(ctx context.Context, jobID string, actuals *core.JobActuals) error {
+	args := m.Called(ctx, jobID, actuals)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) MarkJobMetered(ctx context.Context, jobID string, meteredAt time.Time) error {
+	args := m.Called(ctx, jobID, meteredAt)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListUnmeteredJobs(ctx context.Context, limit int) ([]*core.Job, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) MarkJobResultExpired(ctx context.Context, jobID string, expiredAt time.Time) error {
+	args := m.Called(ctx, jobID, expiredAt)
+	return args.Error(0)
+}
+
+func (m *MockJobRepository) ListJobsAcrossProjects(ctx context.Context, projectIDs []string, statusFilter string, limit, offset int) ([]*core.Job, int, error) {
+	args := m.Called(ctx, projectIDs, statusFilter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.Job), args.Int(1), args.Error(2)
+}
+
+func (m *MockJobRepository) ListJobsByStatus(ctx context.Context, status core.JobStatus, limit int, startAfterID string) ([]*core.Job, error) {
+	args := m.Called(ctx, status, limit, startAfterID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Job), args.Error(1)
+}
+
+func (m *MockJobRepository) CountJobs(ctx context.Context, projectIDs []string, status core.JobStatus) (int, error) {
+	args := m.Called(ctx, projectIDs, status)
+	return args.Int(0), args.Error(1)
+}
+
+type MockStorageService struct {
+	mock.Mock
+}
+
+func (m *MockStorageService) CreateProjectBucket(ctx context.Context, projectID, customerID, requestedRegion string) (string, string, error) {
+	args := m.Called(ctx, projectID, customerID, requestedRegion)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+func (m *MockStorageService) UploadFile(ctx context.Context, bucketName, objectName string, reader io.Reader) (string, error) {
+	args := m.Called(ctx, bucketName, objectName, reader)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockStorageService) UploadNewFile(ctx context.Context, bucketName, objectName string, reader io.Reader) (string, error) {
+	args := m.Called(ctx, bucketName, objectName, reader)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockStorageService) ListObjects(ctx context.Context, bucketName, prefix string) ([]core.ObjectSummary, error) {
+	args := m.Called(ctx, bucketName, prefix)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]core.ObjectSummary), args.Error(1)
+}
+
+func (m *MockStorageService) ListObjectsPage(ctx context.Context, bucketName string, opts core.ListObjectsOptions) (*core.ObjectPage, error) {
+	args := m.Called(ctx, bucketName, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.ObjectPage), args.Error(1)
+}
+
+func (m *MockStorageService) ReadObject(ctx context.Context, bucketName, objectName string) ([]byte, error) {
+	args := m.Called(ctx, bucketName, objectName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (m *MockStorageService) OpenObject(ctx context.Context, bucketName, objectName string) (io.ReadCloser, int64, error) {
+	args := m.Called(ctx, bucketName, objectName)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).(io.ReadCloser), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStorageService) OpenObjectReaderAt(ctx context.Context, bucketName, objectName string) (io.ReaderAt, int64, error) {
+	args := m.Called(ctx, bucketName, objectName)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).(io.ReaderAt), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStorageService) DeleteObject(ctx context.Context, bucketName, objectName string) error {
+	args := m.Called(ctx, bucketName, objectName)
+	return args.Error(0)
+}
+
+func (m *MockStorageService) GetObjectMetadata(ctx context.Context, bucketName, objectName string) (*core.ObjectMetadata, error) {
+	args := m.Called(ctx, bucketName, objectName)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.ObjectMetadata), args.Error(1)
+}
+
+func (m *MockStorageService) UpdateObjectMetadata(ctx context.Context, bucketName, objectName string, metadata map[string]string) error {
+	args := m.Called(ctx, bucketName, objectName, metadata)
+	return args.Error(0)
+}
+
+func (m *MockStorageService) CopyObject(ctx context.Context, bucketName, srcObjectName, dstObjectName string) error {
+	args := m.Called(ctx, bucketName, srcObjectName, dstObjectName)
+	return args.Error(0)
+}
+
+func (m *MockStorageService) SetBucketRetention(ctx context.Context, bucketName string, retentionDays int) error {
+	args := m.Called(ctx, bucketName, retentionDays)
+	return args.Error(0)
+}
+
+func (m *MockStorageService) DeleteProjectBucket(ctx context.Context, bucketName string, force bool) error {
+	args := m.Called(ctx, bucketName, force)
+	return args.Error(0)
+}
+
+func (m *MockStorageService) Close() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// MockLeaseRepository is a mock implementation of core.LeaseRepository.
+type MockLeaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockLeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, name, holder, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockUsageRefresher struct {
+	mock.Mock
+}
+
+func (m *MockUsageRefresher) RefreshStorageUsage(ctx context.Context, projectID string) (*project.StorageUsage, error) {
+	args := m.Called(ctx, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*project.StorageUsage), args.Error(1)
+}
+
+// --- Tests ---
+
+func setupSweeperTest(now time.Time) (*Sweeper, *MockProjectRepository, *MockJobRepository, *MockStorageService, *MockUsageRefresher) {
+	projectRepo := new(MockProjectRepository)
+	jobRepo := new(MockJobRepository)
+	storageSvc := new(MockStorageService)
+	usage := new(MockUsageRefresher)
+	leases := new(MockLeaseRepository)
+	leases.On("AcquireLease", mock.Anything, leaseName, "replica-1", 2*DefaultInterval).Return(true, nil)
+	sweeper := NewSweeper(projectRepo, jobRepo, storageSvc, usage, leases, Config{HolderID: "replica-1"})
+	sweeper.now = func() time.Time { return now }
+	return sweeper, projectRepo, jobRepo, storageSvc, usage
+}
+
+func TestSweeper_SweepOnce(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	old := now.AddDate(0, 0, -45)
+	recent := now.AddDate(0, 0, -5)
+
+	proj := &core.Project{
+		ID:       "proj-1",
+		Storage:  core.ProjectStorage{BucketName: "bucket-1"},
+		Settings: core.ProjectSettings{DataRetentionDays: 30},
+	}
+
+	t.Run("Success_DeletesExpiredObjectsAndMarksJobs", func(t *testing.T) {
+		sweeper, projectRepo, jobRepo, storageSvc, usage := setupSweeperTest(now)
+
+		projectRepo.On("ListAllProjects", ctx).Return([]*core.Project{proj}, nil).Once()
+		storageSvc.On("ListObjects", ctx, "bucket-1", "").Return([]core.ObjectSummary{
+			{Name: "datasets/old.csv", Size: 100, LastUpdated: old},
+			{Name: "jobs/job-old/out.csv", Size: 50, LastUpdated: old},
+			{Name: "datasets/new.csv", Size: 10, LastUpdated: recent},
+		}, nil).Once()
+		storageSvc.On("DeleteObject", ctx, "bucket-1", "datasets/old.csv").Return(nil).Once()
+		// A lifecycle rule may already have removed the object
+		storageSvc.On("DeleteObject", ctx, "bucket-1", "jobs/job-old/out.csv").Return(core.ErrNotFound).Once()
+		usage.On("RefreshStorageUsage", ctx, "proj-1").Return(&project.StorageUsage{}, nil).Once()
+
+		expiredAt := now.AddDate(0, 0, -1)
+		jobRepo.On("ListJobsByProjectID", ctx, "proj-1", jobPageSize, 0).Return([]*core.Job{
+			{ID: "job-old", ResultURI: "gs://bucket-1/jobs/job-old", CompletedAt: &old},
+			{ID: "job-new", ResultURI: "gs://bucket-1/jobs/job-new", CompletedAt: &recent},
+			{ID: "job-already", ResultURI: "gs://bucket-1/jobs/job-already", CompletedAt: &old, ResultExpiredAt: &expiredAt},
+			{ID: "job-noresult", CompletedAt: &old},
+		}, 4, nil).Once()
+		jobRepo.On("MarkJobResultExpired", ctx, "job-old", now).Return(nil).Once()
+
+		result, err := sweeper.SweepOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, SweepResult{ProjectsSwept: 1, ObjectsDeleted: 2, BytesFreed: 150, JobsExpired: 1}, result)
+		storageSvc.AssertNotCalled(t, "DeleteObject", ctx, "bucket-1", "datasets/new.csv")
+		projectRepo.AssertExpectations(t)
+		jobRepo.AssertExpectations(t)
+		storageSvc.AssertExpectations(t)
+		usage.AssertExpectations(t)
+	})
+
+	t.Run("Success_SkipsProjectsWithoutRetention", func(t *testing.T) {
+		sweeper, projectRepo, jobRepo, storageSvc, usage := setupSweeperTest(now)
+		keepForever := &core.Project{ID: "proj-forever", Storage: core.ProjectStorage{BucketName: "bucket-f"}}
+
+		projectRepo.On("ListAllProjects", ctx).Return([]*core.Project{keepForever}, nil).Once()
+
+		result, err := sweeper.SweepOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, SweepResult{}, result)
+		storageSvc.AssertNotCalled(t, "ListObjects", mock.Anything, mock.Anything, mock.Anything)
+		jobRepo.AssertNotCalled(t, "ListJobsByProjectID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		usage.AssertNotCalled(t, "RefreshStorageUsage", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_ProjectErrorDoesNotStopSweep", func(t *testing.T) {
+		sweeper, projectRepo, jobRepo, storageSvc, _ := setupSweeperTest(now)
+		broken := &core.Project{
+			ID:       "proj-broken",
+			Storage:  core.ProjectStorage{BucketName: "bucket-broken"},
+			Settings: core.ProjectSettings{DataRetentionDays: 7},
+		}
+
+		projectRepo.On("ListAllProjects", ctx).Return([]*core.Project{broken, proj}, nil).Once()
+		storageSvc.On("ListObjects", ctx, "bucket-broken", "").Return(nil, errors.New("gcs unavailable")).Once()
+		storageSvc.On("ListObjects", ctx, "bucket-1", "").Return([]core.ObjectSummary{}, nil).Once()
+		jobRepo.On("ListJobsByProjectID", ctx, "proj-1", jobPageSize, 0).Return([]*core.Job{}, 0, nil).Once()
+
+		result, err := sweeper.SweepOnce(ctx)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "1 of 2 projects")
+		assert.Equal(t, 2, result.ProjectsSwept)
+		storageSvc.AssertExpectations(t)
+		jobRepo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_ListProjects", func(t *testing.T) {
+		sweeper, projectRepo, _, _, _ := setupSweeperTest(now)
+		projectRepo.On("ListAllProjects", ctx).Return(nil, errors.New("firestore down")).Once()
+
+		_, err := sweeper.SweepOnce(ctx)
+
+		require.Error(t, err)
+	})
+	t.Run("Skip_NotLeaseHolder", func(t *testing.T) {
+		projectRepo := new(MockProjectRepository)
+		leases := new(MockLeaseRepository)
+		leases.On("AcquireLease", ctx, leaseName, "replica-2", 2*DefaultInterval).Return(false, nil).Once()
+		sweeper := NewSweeper(projectRepo, new(MockJobRepository), new(MockStorageService), new(MockUsageRefresher), leases, Config{HolderID: "replica-2"})
+
+		result, err := sweeper.SweepOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, SweepResult{}, result)
+		projectRepo.AssertNotCalled(t, "ListAllProjects", mock.Anything)
+		leases.AssertExpectations(t)
+	})
+}
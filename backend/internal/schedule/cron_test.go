@@ -0,0 +1,69 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCron(t *testing.T) {
+	for _, expr := range []string{"* * * * *", "*/15 9-17 * * MON-FRI", "0 2 1,15 * *", "30 4 * JAN,jul sun", "5/20 * * * 7", "@daily", "@Hourly"} {
+		t.Run("Success_"+expr, func(t *testing.T) {
+			_, err := parseCron(expr)
+			assert.NoError(t, err)
+		})
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8", "5-1 * * * *", "*/0 * * * *", "* * * FOO *", "@every 5m"} {
+		t.Run("Failure_"+expr, func(t *testing.T) {
+			_, err := parseCron(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCronSchedule_Next(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name     string
+		expr     string
+		loc      *time.Location
+		after    string
+		expected string
+	}{
+		{"EveryMinute", "* * * * *", time.UTC, "2026-03-10T10:15:30Z", "2026-03-10T10:16:00Z"},
+		{"StrictlyAfter", "0 2 * * *", time.UTC, "2026-03-10T02:00:00Z", "2026-03-11T02:00:00Z"},
+		{"Steps", "*/20 * * * *", time.UTC, "2026-03-10T10:41:00Z", "2026-03-10T11:00:00Z"},
+		{"StartWithStep", "5/20 * * * *", time.UTC, "2026-03-10T10:26:00Z", "2026-03-10T10:45:00Z"},
+		{"Weekdays", "0 9 * * MON-FRI", time.UTC, "2026-03-13T10:00:00Z", "2026-03-16T09:00:00Z"}, // Friday to Monday
+		{"SundayAsSeven", "0 0 * * 7", time.UTC, "2026-03-10T00:00:00Z", "2026-03-15T00:00:00Z"},
+		{"MonthEnd", "0 0 31 * *", time.UTC, "2026-04-01T00:00:00Z", "2026-05-31T00:00:00Z"},
+		{"LeapDay", "0 0 29 2 *", time.UTC, "2026-03-01T00:00:00Z", "2028-02-29T00:00:00Z"},
+		{"DayOfMonthOrWeek", "0 0 13 * FRI", time.UTC, "2026-03-01T00:00:00Z", "2026-03-06T00:00:00Z"},
+		{"Timezone", "0 2 * * *", berlin, "2026-01-10T12:00:00Z", "2026-01-11T01:00:00Z"},
+		{"SpringForwardSkipsMissingHour", "30 2 * * *", newYork, "2026-03-08T05:00:00Z", "2026-03-09T06:30:00Z"},
+		{"FallBackRunsOnce", "30 1 * * *", newYork, "2026-11-01T05:31:00Z", "2026-11-02T06:30:00Z"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			expr, err := parseCron(tc.expr)
+			require.NoError(t, err)
+			after, _ := time.Parse(time.RFC3339, tc.after)
+			expected, _ := time.Parse(time.RFC3339, tc.expected)
+
+			assert.Equal(t, expected, expr.Next(after, tc.loc).UTC())
+		})
+	}
+
+	t.Run("NeverMatches", func(t *testing.T) {
+		expr, err := parseCron("0 0 30 2 *")
+		require.NoError(t, err)
+
+		assert.True(t, expr.Next(time.Now(), time.UTC).IsZero())
+	})
+}
@@ -0,0 +1,200 @@
+package schedule
+
+import (
+	"SynDataGen/backend/internal/access"
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// ScheduleRequest defines the JSON body for creating or replacing a schedule. It names
+// either a template or a job type and config.
+type ScheduleRequest struct {
+	Name          string                     `json:"name" binding:"required"`
+	Cron          string                     `json:"cron" binding:"required"` // Five fields or a descriptor such as @daily
+	Timezone      string                     `json:"timezone"`                // IANA name; defaults to UTC
+	Enabled       *bool                      `json:"enabled"`                 // Defaults to true
+	OverlapPolicy core.ScheduleOverlapPolicy `json:"overlapPolicy"`           // Defaults to skip
+
+	TemplateID        string                 `json:"templateId,omitempty"`
+	TemplateVersion   int                    `json:"templateVersion,omitempty"` // 0 uses the current version at each run
+	TemplateVariables map[string]interface{} `json:"templateVariables,omitempty"`
+	JobType           string                 `json:"jobType,omitempty"`
+	JobConfig         string                 `json:"jobConfig,omitempty"`
+	RetryPolicy       *core.JobRetryPolicy   `json:"retryPolicy,omitempty"`
+}
+
+// ScheduleHandler handles HTTP requests for job schedules.
+type ScheduleHandler struct {
+	service ScheduleService
+}
+
+// NewScheduleHandler creates a new ScheduleHandler.
+func NewScheduleHandler(s ScheduleService) *ScheduleHandler {
+	return &ScheduleHandler{service: s}
+}
+
+// RegisterRoutes registers schedule routes with the Gin router group.
+func (h *ScheduleHandler) RegisterRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	schedules := rg.Group("/projects/:projectId/schedules")
+	schedules.Use(authMiddleware)
+	{
+		schedules.POST("", h.CreateSchedule)                        // POST /api/v1/projects/:projectId/schedules
+		schedules.GET("", h.ListSchedules)                          // GET /api/v1/projects/:projectId/schedules
+		schedules.GET("/:scheduleId", h.GetSchedule)                // GET /api/v1/projects/:projectId/schedules/:scheduleId
+		schedules.PUT("/:scheduleId", h.UpdateSchedule)             // PUT /api/v1/projects/:projectId/schedules/:scheduleId
+		schedules.DELETE("/:scheduleId", h.DeleteSchedule)          // DELETE /api/v1/projects/:projectId/schedules/:scheduleId
+		schedules.POST("/:scheduleId/enable", h.setEnabled(true))   // POST /api/v1/projects/:projectId/schedules/:scheduleId/enable
+		schedules.POST("/:scheduleId/disable", h.setEnabled(false)) // POST /api/v1/projects/:projectId/schedules/:scheduleId/disable
+	}
+}
+
+// errorCodes maps this package's errors to responses.
+var errorCodes = []access.ErrorCode{
+	{Err: ErrInvalidSchedule, Status: http.StatusBadRequest, Code: "INVALID_SCHEDULE"},
+}
+
+// abortWithError maps service errors to responses.
+func abortWithError(c *gin.Context, err error, fallback string) {
+	access.AbortWithError(c, err, "Schedule or project not found", fallback, errorCodes...)
+}
+
+// CreateSchedule handles POST /projects/:projectId/schedules requests.
+func (h *ScheduleHandler) CreateSchedule(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "CreateSchedule")
+	if !ok {
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Logger.Warn("Invalid request body for CreateSchedule", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	schedule, err := h.service.CreateSchedule(c.Request.Context(), projectID, userID, req)
+	if err != nil {
+		logger.Logger.Error("Failed to create schedule via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to create schedule")
+		return
+	}
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// ListSchedules handles GET /projects/:projectId/schedules requests.
+func (h *ScheduleHandler) ListSchedules(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "ListSchedules")
+	if !ok {
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "20")
+	offsetStr := c.DefaultQuery("offset", "0")
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid 'limit' query parameter"})
+		return
+	}
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid 'offset' query parameter"})
+		return
+	}
+
+	schedules, total, err := h.service.ListSchedules(c.Request.Context(), projectID, userID, limit, offset)
+	if err != nil {
+		logger.Logger.Error("Failed to list schedules via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to list schedules")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"schedules": schedules,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// GetSchedule handles GET /projects/:projectId/schedules/:scheduleId requests.
+func (h *ScheduleHandler) GetSchedule(c *gin.Context) {
+	projectID, scheduleID := c.Param("projectId"), c.Param("scheduleId")
+	userID, ok := access.RequireUserID(c, "GetSchedule")
+	if !ok {
+		return
+	}
+
+	schedule, err := h.service.GetSchedule(c.Request.Context(), projectID, scheduleID, userID)
+	if err != nil {
+		logger.Logger.Error("Failed to get schedule via service", zap.Error(err), zap.String("userId", userID), zap.String("scheduleId", scheduleID))
+		abortWithError(c, err, "Failed to retrieve schedule")
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// UpdateSchedule handles PUT /projects/:projectId/schedules/:scheduleId requests.
+func (h *ScheduleHandler) UpdateSchedule(c *gin.Context) {
+	projectID, scheduleID := c.Param("projectId"), c.Param("scheduleId")
+	userID, ok := access.RequireUserID(c, "UpdateSchedule")
+	if !ok {
+		return
+	}
+
+	var req ScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Logger.Warn("Invalid request body for UpdateSchedule", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	schedule, err := h.service.UpdateSchedule(c.Request.Context(), projectID, scheduleID, userID, req)
+	if err != nil {
+		logger.Logger.Error("Failed to update schedule via service", zap.Error(err), zap.String("userId", userID), zap.String("scheduleId", scheduleID))
+		abortWithError(c, err, "Failed to update schedule")
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// setEnabled returns the handler for POST /projects/:projectId/schedules/:scheduleId/enable
+// and /disable requests.
+func (h *ScheduleHandler) setEnabled(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, scheduleID := c.Param("projectId"), c.Param("scheduleId")
+		userID, ok := access.RequireUserID(c, "SetScheduleEnabled")
+		if !ok {
+			return
+		}
+
+		schedule, err := h.service.SetScheduleEnabled(c.Request.Context(), projectID, scheduleID, userID, enabled)
+		if err != nil {
+			logger.Logger.Error("Failed to change schedule state via service", zap.Error(err), zap.String("userId", userID), zap.String("scheduleId", scheduleID), zap.Bool("enabled", enabled))
+			abortWithError(c, err, "Failed to update schedule")
+			return
+		}
+		c.JSON(http.StatusOK, schedule)
+	}
+}
+
+// DeleteSchedule handles DELETE /projects/:projectId/schedules/:scheduleId requests.
+func (h *ScheduleHandler) DeleteSchedule(c *gin.Context) {
+	projectID, scheduleID := c.Param("projectId"), c.Param("scheduleId")
+	userID, ok := access.RequireUserID(c, "DeleteSchedule")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteSchedule(c.Request.Context(), projectID, scheduleID, userID); err != nil {
+		logger.Logger.Error("Failed to delete schedule via service", zap.Error(err), zap.String("userId", userID), zap.String("scheduleId", scheduleID))
+		abortWithError(c, err, "Failed to delete schedule")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,173 @@
+package schedule
+
+import (
+	"SynDataGen/backend/internal/auth"
+	"SynDataGen/backend/internal/core"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockScheduleService is a mock implementation of ScheduleService.
+type MockScheduleService struct {
+	mock.Mock
+}
+
+func (m *MockScheduleService) CreateSchedule(ctx context.Context, projectID, userID string, req ScheduleRequest) (*core.Schedule, error) {
+	args := m.Called(ctx, projectID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) GetSchedule(ctx context.Context, projectID, scheduleID, userID string) (*core.Schedule, error) {
+	args := m.Called(ctx, projectID, scheduleID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) ListSchedules(ctx context.Context, projectID, userID string, limit, offset int) ([]*core.Schedule, int, error) {
+	args := m.Called(ctx, projectID, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.Schedule), args.Int(1), args.Error(2)
+}
+
+func (m *MockScheduleService) UpdateSchedule(ctx context.Context, projectID, scheduleID, userID string, req ScheduleRequest) (*core.Schedule, error) {
+	args := m.Called(ctx, projectID, scheduleID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) SetScheduleEnabled(ctx context.Context, projectID, scheduleID, userID string, enabled bool) (*core.Schedule, error) {
+	args := m.Called(ctx, projectID, scheduleID, userID, enabled)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleService) DeleteSchedule(ctx context.Context, projectID, scheduleID, userID string) error {
+	args := m.Called(ctx, projectID, scheduleID, userID)
+	return args.Error(0)
+}
+
+func setupGinTestRouter() (*gin.Engine, *MockScheduleService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockService := new(MockScheduleService)
+	mockAuthMiddleware := func(c *gin.Context) {
+		if userID := c.GetHeader("X-User-ID"); userID != "" {
+			c.Set(auth.UserIDKey, userID)
+		}
+		c.Next()
+	}
+	NewScheduleHandler(mockService).RegisterRoutes(router.Group("/"), mockAuthMiddleware)
+	return router, mockService
+}
+
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "member")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestScheduleHandler(t *testing.T) {
+	base := "/projects/proj-1/schedules"
+
+	t.Run("Success_Create", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		expected := ScheduleRequest{Name: "Nightly", Cron: "0 2 * * *", Timezone: "Europe/Berlin", OverlapPolicy: core.ScheduleOverlapQueue, TemplateID: "tmpl-1"}
+		mockService.On("CreateSchedule", mock.Anything, "proj-1", "member", expected).Return(&core.Schedule{ID: "sched-1"}, nil).Once()
+
+		w := serve(router, http.MethodPost, base, `{"name":"Nightly","cron":"0 2 * * *","timezone":"Europe/Berlin","overlapPolicy":"queue","templateId":"tmpl-1"}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure_CreateMissingCron", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+
+		w := serve(router, http.MethodPost, base, `{"name":"Nightly"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "CreateSchedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_CreateInvalidSchedule", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("CreateSchedule", mock.Anything, "proj-1", "member", mock.Anything).
+			Return(nil, fmt.Errorf("%w: unknown timezone", ErrInvalidSchedule)).Once()
+
+		w := serve(router, http.MethodPost, base, `{"name":"Nightly","cron":"@daily","timezone":"Nowhere"}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_SCHEDULE")
+	})
+
+	t.Run("Success_List", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("ListSchedules", mock.Anything, "proj-1", "member", 20, 0).Return([]*core.Schedule{{ID: "sched-1"}}, 1, nil).Once()
+
+		w := serve(router, http.MethodGet, base, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"total":1`)
+	})
+
+	t.Run("Success_EnableAndDisable", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("SetScheduleEnabled", mock.Anything, "proj-1", "sched-1", "member", true).Return(&core.Schedule{ID: "sched-1", Enabled: true}, nil).Once()
+		mockService.On("SetScheduleEnabled", mock.Anything, "proj-1", "sched-1", "member", false).Return(&core.Schedule{ID: "sched-1"}, nil).Once()
+
+		assert.Equal(t, http.StatusOK, serve(router, http.MethodPost, base+"/sched-1/enable", "").Code)
+		assert.Equal(t, http.StatusOK, serve(router, http.MethodPost, base+"/sched-1/disable", "").Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Success_Delete", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("DeleteSchedule", mock.Anything, "proj-1", "sched-1", "member").Return(nil).Once()
+
+		w := serve(router, http.MethodDelete, base+"/sched-1", "")
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("Failure_UpdateArchived", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("UpdateSchedule", mock.Anything, "proj-1", "sched-1", "member", mock.Anything).
+			Return(nil, fmt.Errorf("%w: cannot change schedules", core.ErrProjectArchived)).Once()
+
+		w := serve(router, http.MethodPut, base+"/sched-1", `{"name":"x","cron":"@daily","jobType":"csv","jobConfig":"{}"}`)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "PROJECT_ARCHIVED")
+	})
+
+	t.Run("Failure_GetNotFound", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("GetSchedule", mock.Anything, "proj-1", "sched-9", "member").Return(nil, core.ErrNotFound).Once()
+
+		w := serve(router, http.MethodGet, base+"/sched-9", "")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
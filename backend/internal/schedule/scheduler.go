@@ -0,0 +1,242 @@
+package schedule
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"SynDataGen/backend/internal/jobtemplate"
+	"SynDataGen/backend/internal/platform/lease"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultTickInterval is how often the scheduler looks for due schedules when no interval
+// is configured.
+const DefaultTickInterval = 30 * time.Second
+
+const (
+	leaseName    = "scheduler" // Lease held by the replica that fires schedules
+	dueBatchSize = 100         // Schedules started per tick at most; the rest wait for the next
+)
+
+// JobRunner creates, submits and cancels jobs. job.JobService satisfies this interface.
+type JobRunner interface {
+	CreateJob(ctx context.Context, projectID, userID string, req job.CreateJobRequest) (*core.Job, error)
+	SubmitJob(ctx context.Context, jobID, userID string) (*core.Job, error)
+	CancelJob(ctx context.Context, jobID, userID string) (*core.Job, error)
+}
+
+// TemplateJobCreator creates jobs from templates. jobtemplate.TemplateService satisfies
+// this interface.
+type TemplateJobCreator interface {
+	CreateJobFromTemplate(ctx context.Context, projectID, templateID, userID string, req jobtemplate.CreateJobFromTemplateRequest) (*jobtemplate.CreateJobFromTemplateResponse, error)
+}
+
+// SchedulerConfig holds configuration for the Scheduler.
+type SchedulerConfig struct {
+	Interval time.Duration // Time between ticks; defaults to DefaultTickInterval
+	HolderID string        // Identifies this replica in the lease; required
+}
+
+// Scheduler creates and submits the jobs of due schedules. Only the replica holding the
+// scheduler lease fires schedules, and each run is claimed in a transaction before its job
+// is created, so runs are never started twice.
+//
+// A schedule that was due while no replica was running fires once and then continues from
+// its next run after the current time.
+type Scheduler struct {
+	scheduleRepo core.ScheduleRepository
+	jobRepo      core.JobRepository
+	jobs         JobRunner
+	templates    TemplateJobCreator
+	runner       *lease.Runner
+	now          func() time.Time // Overridable for tests
+}
+
+// NewScheduler creates a new Scheduler.
+func NewScheduler(scheduleRepo core.ScheduleRepository, leaseRepo core.LeaseRepository, jobRepo core.JobRepository, jobs JobRunner, templates TemplateJobCreator, cfg SchedulerConfig) *Scheduler {
+	if scheduleRepo == nil || leaseRepo == nil || jobRepo == nil || jobs == nil || templates == nil {
+		panic("schedule.NewScheduler: all dependencies are required")
+	}
+	if cfg.HolderID == "" {
+		panic("schedule.NewScheduler: HolderID is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultTickInterval
+	}
+	return &Scheduler{
+		scheduleRepo: scheduleRepo,
+		jobRepo:      jobRepo,
+		jobs:         jobs,
+		templates:    templates,
+		runner:       lease.NewRunner(leaseRepo, leaseName, cfg.HolderID, cfg.Interval),
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Run ticks immediately and then once per interval until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.runner.Run(ctx, func(ctx context.Context) error {
+		_, err := s.fireDue(ctx)
+		return err
+	})
+}
+
+// TickOnce fires the schedules that are due, if this replica holds the lease, and returns
+// how many runs it handled.
+func (s *Scheduler) TickOnce(ctx context.Context) (int, error) {
+	var handled int
+	_, err := s.runner.TickOnce(ctx, func(ctx context.Context) (err error) {
+		handled, err = s.fireDue(ctx)
+		return err
+	})
+	return handled, err
+}
+
+// fireDue fires the due schedules. Per-schedule failures are recorded on the schedule and
+// returned together; they do not stop the tick.
+func (s *Scheduler) fireDue(ctx context.Context) (int, error) {
+	now := s.now()
+	due, err := s.scheduleRepo.ListDueSchedules(ctx, now, dueBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due schedules: %w", err)
+	}
+	handled := 0
+	var errs []error
+	for _, schedule := range due {
+		ok, err := s.fire(ctx, schedule, now)
+		if ok {
+			handled++
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("schedule %s: %w", schedule.ID, err))
+		}
+	}
+	return handled, errors.Join(errs...)
+}
+
+// fire handles one due run of a schedule according to its overlap policy. It reports
+// whether the run was handled here; queued and already-claimed runs are not.
+func (s *Scheduler) fire(ctx context.Context, schedule *core.Schedule, now time.Time) (bool, error) {
+	log := logger.Logger.With(zap.String("scheduleID", schedule.ID), zap.String("projectID", schedule.ProjectID))
+
+	// 1. Check whether the previous run's job is still active
+	var previous *core.Job
+	if schedule.LastJobID != "" {
+		prev, err := s.jobRepo.GetJobByID(ctx, schedule.LastJobID)
+		if err != nil && !errors.Is(err, core.ErrNotFound) {
+			return false, fmt.Errorf("failed to get previous job %s: %w", schedule.LastJobID, err)
+		}
+		if prev != nil && (prev.Status == core.JobStatusPending || prev.Status == core.JobStatusQueued || prev.Status == core.JobStatusRunning) {
+			previous = prev
+		}
+	}
+	if previous != nil && schedule.OverlapPolicy == core.ScheduleOverlapQueue {
+		log.Debug("Schedule is due but its previous job is still active", zap.String("jobID", previous.ID))
+		return false, nil // Stays due until the previous job finishes
+	}
+
+	// 2. Claim the run, moving the schedule to its next run after now
+	next, err := nextRunAfter(schedule.Cron, schedule.Timezone, now)
+	if err != nil {
+		next = nil // A stored schedule that no longer parses stops running
+		log.Error("Schedule has no next run", zap.Error(err))
+	}
+	if err := s.scheduleRepo.ClaimScheduleRun(ctx, schedule.ID, *schedule.NextRunAt, next); err != nil {
+		if errors.Is(err, core.ErrConflict) || errors.Is(err, core.ErrNotFound) {
+			return false, nil // Claimed elsewhere, changed or deleted since it was listed
+		}
+		return false, err
+	}
+
+	// 3. Apply the overlap policy and start the job
+	run := core.ScheduleRun{RanAt: now}
+	switch {
+	case previous != nil && schedule.OverlapPolicy == core.ScheduleOverlapSkip:
+		run.Status = core.ScheduleRunSkipped
+		run.Error = fmt.Sprintf("previous job %s is still %s", previous.ID, previous.Status)
+	case previous != nil && schedule.OverlapPolicy == core.ScheduleOverlapCancelPrevious:
+		if _, err := s.jobs.CancelJob(ctx, previous.ID, schedule.UpdatedBy); err != nil {
+			run.Status = core.ScheduleRunFailed
+			run.Error = fmt.Sprintf("failed to cancel previous job %s: %v", previous.ID, err)
+			break
+		}
+		run.JobID, err = s.startJob(ctx, schedule)
+	default:
+		run.JobID, err = s.startJob(ctx, schedule)
+	}
+	if run.Status == "" {
+		run.Status = core.ScheduleRunSubmitted
+		if err != nil {
+			run.Status = core.ScheduleRunFailed
+			run.Error = err.Error()
+		}
+	}
+	log.Info("Schedule fired", zap.String("status", run.Status), zap.String("jobID", run.JobID), zap.String("error", run.Error))
+
+	if err := s.scheduleRepo.RecordScheduleRun(ctx, schedule.ID, run); err != nil && !errors.Is(err, core.ErrNotFound) {
+		return true, fmt.Errorf("failed to record run: %w", err)
+	}
+	if run.Status == core.ScheduleRunFailed {
+		return true, errors.New(run.Error)
+	}
+	return true, nil
+}
+
+// startJob creates and submits a schedule's job on behalf of the user who last saved the
+// schedule, so their current role in the project applies. It returns the ID of any job
+// created, together with the error if the job could not be created or submitted.
+func (s *Scheduler) startJob(ctx context.Context, schedule *core.Schedule) (string, error) {
+	userID := schedule.UpdatedBy
+
+	var created *core.Job
+	var submitErr error
+	if schedule.TemplateID != "" {
+		resp, err := s.templates.CreateJobFromTemplate(ctx, schedule.ProjectID, schedule.TemplateID, userID, jobtemplate.CreateJobFromTemplateRequest{
+			Version:     schedule.TemplateVersion,
+			Variables:   schedule.TemplateVariables,
+			Submit:      true,
+			RetryPolicy: schedule.RetryPolicy,
+			ScheduleID:  schedule.ID,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create job from template %s: %w", schedule.TemplateID, err)
+		}
+		created = resp.Job
+		if !resp.Submitted {
+			submitErr = errors.New(resp.SubmitError)
+		}
+	} else {
+		var err error
+		created, err = s.jobs.CreateJob(ctx, schedule.ProjectID, userID, job.CreateJobRequest{
+			ProjectID:   schedule.ProjectID,
+			JobType:     schedule.JobType,
+			JobConfig:   schedule.JobConfig,
+			RetryPolicy: schedule.RetryPolicy,
+			ScheduleID:  schedule.ID,
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to create job: %w", err)
+		}
+		submitted, err := s.jobs.SubmitJob(ctx, created.ID, userID)
+		if submitted != nil {
+			created = submitted
+		}
+		submitErr = err
+	}
+	if submitErr == nil {
+		return created.ID, nil
+	}
+
+	// A job left pending would hold up later runs, so it is cancelled
+	if created.Status == core.JobStatusPending {
+		if _, err := s.jobs.CancelJob(ctx, created.ID, userID); err != nil {
+			logger.Logger.Warn("Failed to cancel unsubmitted scheduled job", zap.String("jobID", created.ID), zap.Error(err))
+		}
+	}
+	return created.ID, fmt.Errorf("failed to submit job %s: %w", created.ID, submitErr)
+}
@@ -0,0 +1,263 @@
+package schedule
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"SynDataGen/backend/internal/jobtemplate"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockLeaseRepository is a mock implementation of core.LeaseRepository.
+type MockLeaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockLeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, name, holder, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockJobRepository mocks reading a single job.
+type MockJobRepository struct {
+	mock.Mock
+	core.JobRepository
+}
+
+func (m *MockJobRepository) GetJobByID(ctx context.Context, jobID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+// MockJobRunner is a mock implementation of JobRunner.
+type MockJobRunner struct {
+	mock.Mock
+}
+
+func (m *MockJobRunner) CreateJob(ctx context.Context, projectID, userID string, req job.CreateJobRequest) (*core.Job, error) {
+	args := m.Called(ctx, projectID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobRunner) SubmitJob(ctx context.Context, jobID, userID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobRunner) CancelJob(ctx context.Context, jobID, userID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+// MockTemplateJobCreator is a mock implementation of TemplateJobCreator.
+type MockTemplateJobCreator struct {
+	mock.Mock
+}
+
+func (m *MockTemplateJobCreator) CreateJobFromTemplate(ctx context.Context, projectID, templateID, userID string, req jobtemplate.CreateJobFromTemplateRequest) (*jobtemplate.CreateJobFromTemplateResponse, error) {
+	args := m.Called(ctx, projectID, templateID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*jobtemplate.CreateJobFromTemplateResponse), args.Error(1)
+}
+
+type schedulerMocks struct {
+	schedules *MockScheduleRepository
+	leases    *MockLeaseRepository
+	jobRepo   *MockJobRepository
+	jobs      *MockJobRunner
+	templates *MockTemplateJobCreator
+}
+
+func setupSchedulerTest() (*Scheduler, schedulerMocks) {
+	m := schedulerMocks{
+		schedules: new(MockScheduleRepository),
+		leases:    new(MockLeaseRepository),
+		jobRepo:   new(MockJobRepository),
+		jobs:      new(MockJobRunner),
+		templates: new(MockTemplateJobCreator),
+	}
+	s := NewScheduler(m.schedules, m.leases, m.jobRepo, m.jobs, m.templates, SchedulerConfig{Interval: time.Minute, HolderID: "replica-1"})
+	s.now = func() time.Time { return testNow }
+	m.leases.On("AcquireLease", mock.Anything, leaseName, "replica-1", 2*time.Minute).Return(true, nil).Maybe()
+	return s, m
+}
+
+// dueSchedule returns an hourly schedule that was due at 10:00 and whose last run left job-prev.
+func dueSchedule(policy core.ScheduleOverlapPolicy) *core.Schedule {
+	due := time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+	return &core.Schedule{
+		ID: "sched-1", ProjectID: "proj-1", Cron: "@hourly", Timezone: "UTC", Enabled: true,
+		OverlapPolicy: policy, UpdatedBy: "member", JobType: "csv", JobConfig: "{}",
+		NextRunAt: &due, LastJobID: "job-prev",
+	}
+}
+
+var nextHour = time.Date(2026, 3, 10, 11, 0, 0, 0, time.UTC)
+
+func TestScheduler_TickOnce(t *testing.T) {
+	ctx := context.Background()
+	matchNext := mock.MatchedBy(func(next *time.Time) bool { return next != nil && next.Equal(nextHour) })
+
+	t.Run("Success_CreatesAndSubmitsJob", func(t *testing.T) {
+		s, m := setupSchedulerTest()
+		schedule := dueSchedule(core.ScheduleOverlapSkip)
+		m.schedules.On("ListDueSchedules", ctx, testNow, dueBatchSize).Return([]*core.Schedule{schedule}, nil).Once()
+		m.jobRepo.On("GetJobByID", ctx, "job-prev").Return(&core.Job{ID: "job-prev", Status: core.JobStatusCompleted}, nil).Once()
+		m.schedules.On("ClaimScheduleRun", ctx, "sched-1", *schedule.NextRunAt, matchNext).Return(nil).Once()
+		m.jobs.On("CreateJob", ctx, "proj-1", "member", job.CreateJobRequest{ProjectID: "proj-1", JobType: "csv", JobConfig: "{}", ScheduleID: "sched-1"}).
+			Return(&core.Job{ID: "job-new", Status: core.JobStatusPending}, nil).Once()
+		m.jobs.On("SubmitJob", ctx, "job-new", "member").Return(&core.Job{ID: "job-new", Status: core.JobStatusRunning}, nil).Once()
+		m.schedules.On("RecordScheduleRun", ctx, "sched-1", core.ScheduleRun{RanAt: testNow, Status: core.ScheduleRunSubmitted, JobID: "job-new"}).Return(nil).Once()
+
+		handled, err := s.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		m.schedules.AssertExpectations(t)
+		m.jobs.AssertExpectations(t)
+	})
+
+	t.Run("Success_FromTemplate", func(t *testing.T) {
+		s, m := setupSchedulerTest()
+		schedule := dueSchedule(core.ScheduleOverlapSkip)
+		schedule.LastJobID, schedule.JobType, schedule.JobConfig = "", "", ""
+		schedule.TemplateID, schedule.TemplateVariables = "tmpl-1", map[string]interface{}{"rows": 5.0}
+		m.schedules.On("ListDueSchedules", ctx, testNow, dueBatchSize).Return([]*core.Schedule{schedule}, nil).Once()
+		m.schedules.On("ClaimScheduleRun", ctx, "sched-1", *schedule.NextRunAt, matchNext).Return(nil).Once()
+		m.templates.On("CreateJobFromTemplate", ctx, "proj-1", "tmpl-1", "member", jobtemplate.CreateJobFromTemplateRequest{
+			Variables: schedule.TemplateVariables, Submit: true, ScheduleID: "sched-1",
+		}).Return(&jobtemplate.CreateJobFromTemplateResponse{Job: &core.Job{ID: "job-new", Status: core.JobStatusRunning}, Submitted: true}, nil).Once()
+		m.schedules.On("RecordScheduleRun", ctx, "sched-1", core.ScheduleRun{RanAt: testNow, Status: core.ScheduleRunSubmitted, JobID: "job-new"}).Return(nil).Once()
+
+		handled, err := s.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		m.templates.AssertExpectations(t)
+	})
+
+	t.Run("Success_NotLeaseHolder", func(t *testing.T) {
+		m := schedulerMocks{schedules: new(MockScheduleRepository), leases: new(MockLeaseRepository), jobRepo: new(MockJobRepository), jobs: new(MockJobRunner), templates: new(MockTemplateJobCreator)}
+		s := NewScheduler(m.schedules, m.leases, m.jobRepo, m.jobs, m.templates, SchedulerConfig{Interval: time.Minute, HolderID: "replica-2"})
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-2", 2*time.Minute).Return(false, nil).Once()
+
+		handled, err := s.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, handled)
+		m.schedules.AssertNotCalled(t, "ListDueSchedules", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_ClaimedElsewhere", func(t *testing.T) {
+		s, m := setupSchedulerTest()
+		schedule := dueSchedule(core.ScheduleOverlapSkip)
+		schedule.LastJobID = ""
+		m.schedules.On("ListDueSchedules", ctx, testNow, dueBatchSize).Return([]*core.Schedule{schedule}, nil).Once()
+		m.schedules.On("ClaimScheduleRun", ctx, "sched-1", *schedule.NextRunAt, matchNext).Return(core.ErrConflict).Once()
+
+		handled, err := s.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, handled)
+		m.jobs.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_OverlapSkip", func(t *testing.T) {
+		s, m := setupSchedulerTest()
+		schedule := dueSchedule(core.ScheduleOverlapSkip)
+		m.schedules.On("ListDueSchedules", ctx, testNow, dueBatchSize).Return([]*core.Schedule{schedule}, nil).Once()
+		m.jobRepo.On("GetJobByID", ctx, "job-prev").Return(&core.Job{ID: "job-prev", Status: core.JobStatusRunning}, nil).Once()
+		m.schedules.On("ClaimScheduleRun", ctx, "sched-1", *schedule.NextRunAt, matchNext).Return(nil).Once()
+		m.schedules.On("RecordScheduleRun", ctx, "sched-1", mock.MatchedBy(func(run core.ScheduleRun) bool {
+			return run.Status == core.ScheduleRunSkipped && run.JobID == ""
+		})).Return(nil).Once()
+
+		handled, err := s.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		m.jobs.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_OverlapQueueWaits", func(t *testing.T) {
+		s, m := setupSchedulerTest()
+		schedule := dueSchedule(core.ScheduleOverlapQueue)
+		m.schedules.On("ListDueSchedules", ctx, testNow, dueBatchSize).Return([]*core.Schedule{schedule}, nil).Once()
+		m.jobRepo.On("GetJobByID", ctx, "job-prev").Return(&core.Job{ID: "job-prev", Status: core.JobStatusPending}, nil).Once()
+
+		handled, err := s.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Zero(t, handled)
+		m.schedules.AssertNotCalled(t, "ClaimScheduleRun", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_OverlapCancelPrevious", func(t *testing.T) {
+		s, m := setupSchedulerTest()
+		schedule := dueSchedule(core.ScheduleOverlapCancelPrevious)
+		m.schedules.On("ListDueSchedules", ctx, testNow, dueBatchSize).Return([]*core.Schedule{schedule}, nil).Once()
+		m.jobRepo.On("GetJobByID", ctx, "job-prev").Return(&core.Job{ID: "job-prev", Status: core.JobStatusRunning}, nil).Once()
+		m.schedules.On("ClaimScheduleRun", ctx, "sched-1", *schedule.NextRunAt, matchNext).Return(nil).Once()
+		m.jobs.On("CancelJob", ctx, "job-prev", "member").Return(&core.Job{ID: "job-prev", Status: core.JobStatusCancelled}, nil).Once()
+		m.jobs.On("CreateJob", ctx, "proj-1", "member", mock.Anything).Return(&core.Job{ID: "job-new", Status: core.JobStatusPending}, nil).Once()
+		m.jobs.On("SubmitJob", ctx, "job-new", "member").Return(&core.Job{ID: "job-new", Status: core.JobStatusRunning}, nil).Once()
+		m.schedules.On("RecordScheduleRun", ctx, "sched-1", core.ScheduleRun{RanAt: testNow, Status: core.ScheduleRunSubmitted, JobID: "job-new"}).Return(nil).Once()
+
+		handled, err := s.TickOnce(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, handled)
+		m.jobs.AssertExpectations(t)
+	})
+
+	t.Run("Failure_SubmitFailsCancelsJob", func(t *testing.T) {
+		s, m := setupSchedulerTest()
+		schedule := dueSchedule(core.ScheduleOverlapSkip)
+		schedule.LastJobID = ""
+		m.schedules.On("ListDueSchedules", ctx, testNow, dueBatchSize).Return([]*core.Schedule{schedule}, nil).Once()
+		m.schedules.On("ClaimScheduleRun", ctx, "sched-1", *schedule.NextRunAt, matchNext).Return(nil).Once()
+		m.jobs.On("CreateJob", ctx, "proj-1", "member", mock.Anything).Return(&core.Job{ID: "job-new", Status: core.JobStatusPending}, nil).Once()
+		m.jobs.On("SubmitJob", ctx, "job-new", "member").Return(nil, core.ErrForbidden).Once()
+		m.jobs.On("CancelJob", ctx, "job-new", "member").Return(&core.Job{ID: "job-new", Status: core.JobStatusCancelled}, nil).Once()
+		m.schedules.On("RecordScheduleRun", ctx, "sched-1", mock.MatchedBy(func(run core.ScheduleRun) bool {
+			return run.Status == core.ScheduleRunFailed && run.JobID == "job-new" && run.Error != ""
+		})).Return(nil).Once()
+
+		handled, err := s.TickOnce(ctx)
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, handled)
+		m.jobs.AssertExpectations(t)
+		m.schedules.AssertExpectations(t)
+	})
+
+	t.Run("Failure_LeaseError", func(t *testing.T) {
+		m := schedulerMocks{schedules: new(MockScheduleRepository), leases: new(MockLeaseRepository), jobRepo: new(MockJobRepository), jobs: new(MockJobRunner), templates: new(MockTemplateJobCreator)}
+		s := NewScheduler(m.schedules, m.leases, m.jobRepo, m.jobs, m.templates, SchedulerConfig{Interval: time.Minute, HolderID: "replica-1"})
+		m.leases.On("AcquireLease", ctx, leaseName, "replica-1", 2*time.Minute).Return(false, errors.New("unavailable")).Once()
+
+		_, err := s.TickOnce(ctx)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,294 @@
+package schedule
+
+import (
+	"SynDataGen/backend/internal/access"
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Schedule errors
+var (
+	ErrInvalidSchedule = errors.New("invalid schedule")
+)
+
+// TemplateGetter retrieves job templates. jobtemplate.TemplateService satisfies this interface.
+type TemplateGetter interface {
+	GetTemplate(ctx context.Context, projectID, templateID, userID string) (*core.JobTemplate, error)
+	GetTemplateVersion(ctx context.Context, projectID, templateID string, version int, userID string) (*core.JobTemplate, error)
+}
+
+// --- Service Interface ---
+
+// ScheduleService defines the interface for job schedule business logic.
+type ScheduleService interface {
+	// CreateSchedule creates a schedule, requiring Member role.
+	CreateSchedule(ctx context.Context, projectID, userID string, req ScheduleRequest) (*core.Schedule, error)
+
+	// GetSchedule retrieves a schedule, requiring Viewer role.
+	GetSchedule(ctx context.Context, projectID, scheduleID, userID string) (*core.Schedule, error)
+
+	// ListSchedules retrieves a paginated list of a project's schedules, requiring Viewer role.
+	ListSchedules(ctx context.Context, projectID, userID string, limit, offset int) ([]*core.Schedule, int, error)
+
+	// UpdateSchedule replaces a schedule's definition, requiring Member role. Its jobs are
+	// then created on behalf of the updating user.
+	UpdateSchedule(ctx context.Context, projectID, scheduleID, userID string, req ScheduleRequest) (*core.Schedule, error)
+
+	// SetScheduleEnabled enables or disables a schedule, requiring Member role.
+	SetScheduleEnabled(ctx context.Context, projectID, scheduleID, userID string, enabled bool) (*core.Schedule, error)
+
+	// DeleteSchedule deletes a schedule, requiring Member role. Jobs it created are kept.
+	DeleteSchedule(ctx context.Context, projectID, scheduleID, userID string) error
+}
+
+// scheduleService implements the ScheduleService interface.
+type scheduleService struct {
+	scheduleRepo core.ScheduleRepository
+	projects     access.ProjectGetter
+	templates    TemplateGetter
+	now          func() time.Time // Overridable for tests
+}
+
+// NewScheduleService creates a new job schedule service instance.
+func NewScheduleService(scheduleRepo core.ScheduleRepository, projects access.ProjectGetter, templates TemplateGetter) ScheduleService {
+	if scheduleRepo == nil || projects == nil || templates == nil {
+		panic("schedule.NewScheduleService: all dependencies are required")
+	}
+	return &scheduleService{
+		scheduleRepo: scheduleRepo,
+		projects:     projects,
+		templates:    templates,
+		now:          func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// getProjectSchedule returns a schedule in the given project. Schedules of other projects
+// are reported as not found.
+func (s *scheduleService) getProjectSchedule(ctx context.Context, projectID, scheduleID string) (*core.Schedule, error) {
+	schedule, err := s.scheduleRepo.GetSchedule(ctx, scheduleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule %s: %w", scheduleID, err)
+	}
+	if schedule.ProjectID != projectID {
+		return nil, fmt.Errorf("schedule %s is not in project %s: %w", scheduleID, projectID, core.ErrNotFound)
+	}
+	return schedule, nil
+}
+
+// validateRequest checks a schedule request and applies its defaults. The template, if
+// any, must exist and be visible to the user.
+func (s *scheduleService) validateRequest(ctx context.Context, projectID, userID string, req *ScheduleRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("%w: name cannot be empty", ErrInvalidSchedule)
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if req.OverlapPolicy == "" {
+		req.OverlapPolicy = core.ScheduleOverlapSkip
+	}
+	switch req.OverlapPolicy {
+	case core.ScheduleOverlapSkip, core.ScheduleOverlapQueue, core.ScheduleOverlapCancelPrevious:
+	default:
+		return fmt.Errorf("%w: unknown overlap policy %q", ErrInvalidSchedule, req.OverlapPolicy)
+	}
+	if _, err := nextRunAfter(req.Cron, req.Timezone, s.now()); err != nil {
+		return err
+	}
+
+	// Exactly one of a template or a job type and config
+	if req.TemplateID == "" {
+		if req.JobType == "" || req.JobConfig == "" {
+			return fmt.Errorf("%w: either templateId or jobType and jobConfig are required", ErrInvalidSchedule)
+		}
+		if req.TemplateVersion != 0 || len(req.TemplateVariables) > 0 {
+			return fmt.Errorf("%w: templateVersion and templateVariables require templateId", ErrInvalidSchedule)
+		}
+		return nil
+	}
+	if req.JobType != "" || req.JobConfig != "" {
+		return fmt.Errorf("%w: templateId cannot be combined with jobType or jobConfig", ErrInvalidSchedule)
+	}
+	var err error
+	if req.TemplateVersion == 0 {
+		_, err = s.templates.GetTemplate(ctx, projectID, req.TemplateID, userID)
+	} else {
+		_, err = s.templates.GetTemplateVersion(ctx, projectID, req.TemplateID, req.TemplateVersion, userID)
+	}
+	if errors.Is(err, core.ErrNotFound) {
+		return fmt.Errorf("%w: template %s version %d not found", ErrInvalidSchedule, req.TemplateID, req.TemplateVersion)
+	}
+	return err
+}
+
+// nextRunAfter returns the first run of a cron expression in a timezone after the given time.
+func nextRunAfter(cron, timezone string, after time.Time) (*time.Time, error) {
+	expr, err := parseCron(cron)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cron expression: %v", ErrInvalidSchedule, err)
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", ErrInvalidSchedule, timezone)
+	}
+	next := expr.Next(after, loc)
+	if next.IsZero() {
+		return nil, fmt.Errorf("%w: cron expression %q never matches", ErrInvalidSchedule, cron)
+	}
+	next = next.UTC()
+	return &next, nil
+}
+
+// applyRequest copies the request's definition onto a schedule and recomputes its next run.
+func (s *scheduleService) applyRequest(schedule *core.Schedule, userID string, req ScheduleRequest) error {
+	schedule.Name = req.Name
+	schedule.Cron = req.Cron
+	schedule.Timezone = req.Timezone
+	schedule.Enabled = req.Enabled == nil || *req.Enabled
+	schedule.OverlapPolicy = req.OverlapPolicy
+	schedule.TemplateID = req.TemplateID
+	schedule.TemplateVersion = req.TemplateVersion
+	schedule.TemplateVariables = req.TemplateVariables
+	schedule.JobType = req.JobType
+	schedule.JobConfig = req.JobConfig
+	schedule.RetryPolicy = req.RetryPolicy
+	schedule.UpdatedBy = userID
+	schedule.UpdatedAt = s.now()
+	return s.scheduleNextRun(schedule)
+}
+
+// scheduleNextRun sets NextRunAt from now, or clears it if the schedule is disabled.
+func (s *scheduleService) scheduleNextRun(schedule *core.Schedule) error {
+	schedule.NextRunAt = nil
+	if !schedule.Enabled {
+		return nil
+	}
+	next, err := nextRunAfter(schedule.Cron, schedule.Timezone, s.now())
+	if err != nil {
+		return err
+	}
+	schedule.NextRunAt = next
+	return nil
+}
+
+// CreateSchedule validates and stores a new schedule.
+func (s *scheduleService) CreateSchedule(ctx context.Context, projectID, userID string, req ScheduleRequest) (*core.Schedule, error) {
+	logger.Logger.Info("Attempting to create schedule", zap.String("projectID", projectID), zap.String("userID", userID))
+	// 1. Authorize and validate
+	if _, err := access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleMember, "schedules"); err != nil {
+		return nil, err
+	}
+	if err := s.validateRequest(ctx, projectID, userID, &req); err != nil {
+		return nil, err
+	}
+
+	// 2. Store it with its first run
+	schedule := &core.Schedule{
+		ID:        uuid.NewString(),
+		ProjectID: projectID,
+		CreatedBy: userID,
+		CreatedAt: s.now(),
+	}
+	if err := s.applyRequest(schedule, userID, req); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleRepo.CreateSchedule(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to store new schedule: %w", err)
+	}
+	logger.Logger.Info("Successfully created schedule", zap.String("scheduleID", schedule.ID), zap.String("projectID", projectID))
+	return schedule, nil
+}
+
+// GetSchedule retrieves a schedule.
+func (s *scheduleService) GetSchedule(ctx context.Context, projectID, scheduleID, userID string) (*core.Schedule, error) {
+	if _, err := access.Authorize(ctx, s.projects, projectID, userID, core.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.getProjectSchedule(ctx, projectID, scheduleID)
+}
+
+// ListSchedules retrieves a project's schedules.
+func (s *scheduleService) ListSchedules(ctx context.Context, projectID, userID string, limit, offset int) ([]*core.Schedule, int, error) {
+	if _, err := access.Authorize(ctx, s.projects, projectID, userID, core.RoleViewer); err != nil {
+		return nil, 0, err
+	}
+	schedules, total, err := s.scheduleRepo.ListSchedulesByProjectID(ctx, projectID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list schedules: %w", err)
+	}
+	return schedules, total, nil
+}
+
+// UpdateSchedule replaces a schedule's definition and recomputes its next run.
+func (s *scheduleService) UpdateSchedule(ctx context.Context, projectID, scheduleID, userID string, req ScheduleRequest) (*core.Schedule, error) {
+	logger.Logger.Info("Attempting to update schedule", zap.String("scheduleID", scheduleID), zap.String("userID", userID))
+	// 1. Authorize and validate
+	if _, err := access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleMember, "schedules"); err != nil {
+		return nil, err
+	}
+	if err := s.validateRequest(ctx, projectID, userID, &req); err != nil {
+		return nil, err
+	}
+	schedule, err := s.getProjectSchedule(ctx, projectID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Store the new definition
+	if err := s.applyRequest(schedule, userID, req); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleRepo.UpdateSchedule(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to update schedule %s: %w", scheduleID, err)
+	}
+	logger.Logger.Info("Successfully updated schedule", zap.String("scheduleID", scheduleID))
+	return schedule, nil
+}
+
+// SetScheduleEnabled enables a schedule from its next run after now, or disables it.
+func (s *scheduleService) SetScheduleEnabled(ctx context.Context, projectID, scheduleID, userID string, enabled bool) (*core.Schedule, error) {
+	if _, err := access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleMember, "schedules"); err != nil {
+		return nil, err
+	}
+	schedule, err := s.getProjectSchedule(ctx, projectID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.Enabled == enabled {
+		return schedule, nil
+	}
+
+	schedule.Enabled = enabled
+	schedule.UpdatedBy = userID
+	schedule.UpdatedAt = s.now()
+	if err := s.scheduleNextRun(schedule); err != nil {
+		return nil, err
+	}
+	if err := s.scheduleRepo.UpdateSchedule(ctx, schedule); err != nil {
+		return nil, fmt.Errorf("failed to update schedule %s: %w", scheduleID, err)
+	}
+	logger.Logger.Info("Changed schedule state", zap.String("scheduleID", scheduleID), zap.Bool("enabled", enabled), zap.String("userID", userID))
+	return schedule, nil
+}
+
+// DeleteSchedule deletes a schedule.
+func (s *scheduleService) DeleteSchedule(ctx context.Context, projectID, scheduleID, userID string) error {
+	if _, err := access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleMember, "schedules"); err != nil {
+		return err
+	}
+	if _, err := s.getProjectSchedule(ctx, projectID, scheduleID); err != nil {
+		return err
+	}
+	if err := s.scheduleRepo.DeleteSchedule(ctx, scheduleID); err != nil {
+		return fmt.Errorf("failed to delete schedule %s: %w", scheduleID, err)
+	}
+	logger.Logger.Info("Deleted schedule", zap.String("scheduleID", scheduleID), zap.String("userID", userID))
+	return nil
+}
@@ -0,0 +1,322 @@
+package schedule
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mocks ---
+
+// MockScheduleRepository is a mock implementation of core.ScheduleRepository.
+type MockScheduleRepository struct {
+	mock.Mock
+}
+
+func (m *MockScheduleRepository) CreateSchedule(ctx context.Context, schedule *core.Schedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) GetSchedule(ctx context.Context, scheduleID string) (*core.Schedule, error) {
+	args := m.Called(ctx, scheduleID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepository) ListSchedulesByProjectID(ctx context.Context, projectID string, limit, offset int) ([]*core.Schedule, int, error) {
+	args := m.Called(ctx, projectID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.Schedule), args.Int(1), args.Error(2)
+}
+
+func (m *MockScheduleRepository) UpdateSchedule(ctx context.Context, schedule *core.Schedule) error {
+	args := m.Called(ctx, schedule)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) DeleteSchedule(ctx context.Context, scheduleID string) error {
+	args := m.Called(ctx, scheduleID)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]*core.Schedule, error) {
+	args := m.Called(ctx, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Schedule), args.Error(1)
+}
+
+func (m *MockScheduleRepository) ClaimScheduleRun(ctx context.Context, scheduleID string, due time.Time, next *time.Time) error {
+	args := m.Called(ctx, scheduleID, due, next)
+	return args.Error(0)
+}
+
+func (m *MockScheduleRepository) RecordScheduleRun(ctx context.Context, scheduleID string, run core.ScheduleRun) error {
+	args := m.Called(ctx, scheduleID, run)
+	return args.Error(0)
+}
+
+// MockProjectGetter is a mock implementation of access.ProjectGetter.
+type MockProjectGetter struct {
+	mock.Mock
+}
+
+func (m *MockProjectGetter) GetProjectByID(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+// MockTemplateGetter is a mock implementation of TemplateGetter.
+type MockTemplateGetter struct {
+	mock.Mock
+}
+
+func (m *MockTemplateGetter) GetTemplate(ctx context.Context, projectID, templateID, userID string) (*core.JobTemplate, error) {
+	args := m.Called(ctx, projectID, templateID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.JobTemplate), args.Error(1)
+}
+
+func (m *MockTemplateGetter) GetTemplateVersion(ctx context.Context, projectID, templateID string, version int, userID string) (*core.JobTemplate, error) {
+	args := m.Called(ctx, projectID, templateID, version, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.JobTemplate), args.Error(1)
+}
+
+// --- Test Setup ---
+
+var testNow = time.Date(2026, 3, 10, 10, 15, 0, 0, time.UTC)
+
+func setupTestService() (*scheduleService, *MockScheduleRepository, *MockProjectGetter, *MockTemplateGetter) {
+	repo := new(MockScheduleRepository)
+	projects := new(MockProjectGetter)
+	templates := new(MockTemplateGetter)
+	svc := NewScheduleService(repo, projects, templates).(*scheduleService)
+	svc.now = func() time.Time { return testNow }
+	return svc, repo, projects, templates
+}
+
+func testProject(status string) *core.Project {
+	return &core.Project{
+		ID:          "proj-1",
+		Status:      status,
+		TeamMembers: map[string]core.Role{"owner": core.RoleOwner, "member": core.RoleMember, "viewer": core.RoleViewer},
+	}
+}
+
+// --- Tests ---
+
+func TestScheduleService_CreateSchedule(t *testing.T) {
+	ctx := context.Background()
+	req := ScheduleRequest{Name: "Nightly", Cron: "0 2 * * *", Timezone: "Europe/Berlin", JobType: "csv", JobConfig: `{"rows":100}`}
+
+	t.Run("Success_DefaultsAndNextRun", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("CreateSchedule", ctx, mock.AnythingOfType("*core.Schedule")).Return(nil).Once()
+
+		schedule, err := svc.CreateSchedule(ctx, "proj-1", "member", req)
+
+		require.NoError(t, err)
+		assert.True(t, schedule.Enabled)
+		assert.Equal(t, core.ScheduleOverlapSkip, schedule.OverlapPolicy)
+		assert.Equal(t, "member", schedule.UpdatedBy)
+		require.NotNil(t, schedule.NextRunAt)
+		assert.Equal(t, time.Date(2026, 3, 11, 1, 0, 0, 0, time.UTC), *schedule.NextRunAt) // 02:00 CET
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Success_Disabled", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("CreateSchedule", ctx, mock.AnythingOfType("*core.Schedule")).Return(nil).Once()
+		disabled := false
+		r := req
+		r.Enabled = &disabled
+
+		schedule, err := svc.CreateSchedule(ctx, "proj-1", "member", r)
+
+		require.NoError(t, err)
+		assert.False(t, schedule.Enabled)
+		assert.Nil(t, schedule.NextRunAt)
+	})
+
+	t.Run("Success_Template", func(t *testing.T) {
+		svc, repo, projects, templates := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		templates.On("GetTemplateVersion", ctx, "proj-1", "tmpl-1", 3, "member").Return(&core.JobTemplate{ID: "tmpl-1", Version: 3}, nil).Once()
+		repo.On("CreateSchedule", ctx, mock.AnythingOfType("*core.Schedule")).Return(nil).Once()
+
+		schedule, err := svc.CreateSchedule(ctx, "proj-1", "member", ScheduleRequest{
+			Name: "Weekly", Cron: "@weekly", TemplateID: "tmpl-1", TemplateVersion: 3,
+			TemplateVariables: map[string]interface{}{"rows": 10.0},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "tmpl-1", schedule.TemplateID)
+		templates.AssertExpectations(t)
+	})
+
+	for name, invalid := range map[string]ScheduleRequest{
+		"BadCron":          {Name: "x", Cron: "0 25 * * *", JobType: "csv", JobConfig: "{}"},
+		"NeverRuns":        {Name: "x", Cron: "0 0 31 2 *", JobType: "csv", JobConfig: "{}"},
+		"BadTimezone":      {Name: "x", Cron: "@daily", Timezone: "Mars/Olympus", JobType: "csv", JobConfig: "{}"},
+		"BadOverlap":       {Name: "x", Cron: "@daily", OverlapPolicy: "parallel", JobType: "csv", JobConfig: "{}"},
+		"NothingToRun":     {Name: "x", Cron: "@daily"},
+		"TemplateAndJob":   {Name: "x", Cron: "@daily", TemplateID: "tmpl-1", JobType: "csv"},
+		"VariablesWithout": {Name: "x", Cron: "@daily", JobType: "csv", JobConfig: "{}", TemplateVariables: map[string]interface{}{"a": 1.0}},
+	} {
+		t.Run("Failure_"+name, func(t *testing.T) {
+			svc, repo, projects, _ := setupTestService()
+			projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+
+			_, err := svc.CreateSchedule(ctx, "proj-1", "member", invalid)
+
+			assert.ErrorIs(t, err, ErrInvalidSchedule)
+			repo.AssertNotCalled(t, "CreateSchedule", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("Failure_TemplateNotFound", func(t *testing.T) {
+		svc, _, projects, templates := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		templates.On("GetTemplate", ctx, "proj-1", "missing", "member").Return(nil, core.ErrNotFound).Once()
+
+		_, err := svc.CreateSchedule(ctx, "proj-1", "member", ScheduleRequest{Name: "x", Cron: "@daily", TemplateID: "missing"})
+
+		assert.ErrorIs(t, err, ErrInvalidSchedule)
+	})
+
+	t.Run("Failure_ViewerForbidden", func(t *testing.T) {
+		svc, _, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "viewer").Return(testProject(core.ProjectStatusActive), nil).Once()
+
+		_, err := svc.CreateSchedule(ctx, "proj-1", "viewer", req)
+
+		assert.ErrorIs(t, err, core.ErrForbidden)
+	})
+
+	t.Run("Failure_ProjectArchived", func(t *testing.T) {
+		svc, _, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusArchived), nil).Once()
+
+		_, err := svc.CreateSchedule(ctx, "proj-1", "member", req)
+
+		assert.ErrorIs(t, err, core.ErrProjectArchived)
+	})
+}
+
+func TestScheduleService_UpdateSchedule(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_KeepsRunState", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		lastRun := testNow.Add(-time.Hour)
+		existing := &core.Schedule{ID: "sched-1", ProjectID: "proj-1", Name: "Old", Cron: "@daily", Timezone: "UTC", Enabled: true, CreatedBy: "owner", UpdatedBy: "owner", LastRunAt: &lastRun, LastJobID: "job-1"}
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetSchedule", ctx, "sched-1").Return(existing, nil).Once()
+		repo.On("UpdateSchedule", ctx, mock.AnythingOfType("*core.Schedule")).Return(nil).Once()
+
+		schedule, err := svc.UpdateSchedule(ctx, "proj-1", "sched-1", "member", ScheduleRequest{Name: "New", Cron: "0 * * * *", JobType: "csv", JobConfig: "{}", OverlapPolicy: core.ScheduleOverlapQueue})
+
+		require.NoError(t, err)
+		assert.Equal(t, "New", schedule.Name)
+		assert.Equal(t, "member", schedule.UpdatedBy)
+		assert.Equal(t, "owner", schedule.CreatedBy)
+		assert.Equal(t, "job-1", schedule.LastJobID)
+		assert.Equal(t, time.Date(2026, 3, 10, 11, 0, 0, 0, time.UTC), *schedule.NextRunAt)
+	})
+
+	t.Run("Failure_OtherProject", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetSchedule", ctx, "sched-2").Return(&core.Schedule{ID: "sched-2", ProjectID: "proj-2"}, nil).Once()
+
+		_, err := svc.UpdateSchedule(ctx, "proj-1", "sched-2", "member", ScheduleRequest{Name: "x", Cron: "@daily", JobType: "csv", JobConfig: "{}"})
+
+		assert.ErrorIs(t, err, core.ErrNotFound)
+		repo.AssertNotCalled(t, "UpdateSchedule", mock.Anything, mock.Anything)
+	})
+}
+
+func TestScheduleService_SetScheduleEnabled(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_EnableSchedulesNextRun", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetSchedule", ctx, "sched-1").Return(&core.Schedule{ID: "sched-1", ProjectID: "proj-1", Cron: "@hourly", Timezone: "UTC"}, nil).Once()
+		repo.On("UpdateSchedule", ctx, mock.MatchedBy(func(s *core.Schedule) bool {
+			return s.Enabled && s.NextRunAt != nil && s.NextRunAt.Equal(time.Date(2026, 3, 10, 11, 0, 0, 0, time.UTC))
+		})).Return(nil).Once()
+
+		_, err := svc.SetScheduleEnabled(ctx, "proj-1", "sched-1", "member", true)
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Success_DisableClearsNextRun", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		next := testNow.Add(time.Hour)
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetSchedule", ctx, "sched-1").Return(&core.Schedule{ID: "sched-1", ProjectID: "proj-1", Cron: "@hourly", Timezone: "UTC", Enabled: true, NextRunAt: &next}, nil).Once()
+		repo.On("UpdateSchedule", ctx, mock.MatchedBy(func(s *core.Schedule) bool { return !s.Enabled && s.NextRunAt == nil })).Return(nil).Once()
+
+		_, err := svc.SetScheduleEnabled(ctx, "proj-1", "sched-1", "member", false)
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Success_Unchanged", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetSchedule", ctx, "sched-1").Return(&core.Schedule{ID: "sched-1", ProjectID: "proj-1", Enabled: true}, nil).Once()
+
+		_, err := svc.SetScheduleEnabled(ctx, "proj-1", "sched-1", "member", true)
+
+		require.NoError(t, err)
+		repo.AssertNotCalled(t, "UpdateSchedule", mock.Anything, mock.Anything)
+	})
+}
+
+func TestScheduleService_DeleteSchedule(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetSchedule", ctx, "sched-1").Return(&core.Schedule{ID: "sched-1", ProjectID: "proj-1"}, nil).Once()
+		repo.On("DeleteSchedule", ctx, "sched-1").Return(nil).Once()
+
+		require.NoError(t, svc.DeleteSchedule(ctx, "proj-1", "sched-1", "member"))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_NotFound", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetSchedule", ctx, "sched-1").Return(nil, core.ErrNotFound).Once()
+
+		assert.ErrorIs(t, svc.DeleteSchedule(ctx, "proj-1", "sched-1", "member"), core.ErrNotFound)
+	})
+}
@@ -0,0 +1,137 @@
+package workflow
+
+import (
+	"SynDataGen/backend/internal/core"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// maxWorkflowSteps is the largest number of steps a workflow may have.
+const maxWorkflowSteps = 50
+
+// configUpstreamResultsKey is the job config key a step's job receives the result URIs of
+// the steps it depends on under, keyed by step name.
+const configUpstreamResultsKey = "upstreamResults"
+
+// stepNamePattern is the form of a step name, so it can appear in placeholders.
+var stepNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// stepResultPattern matches a {{steps.<name>.resultUri}} placeholder, allowing spaces inside
+// the braces.
+var stepResultPattern = regexp.MustCompile(`\{\{\s*steps\.([A-Za-z0-9_-]+)\.resultUri\s*\}\}`)
+
+// orderSteps validates the requested steps and returns them in dependency order: every step
+// comes after the steps it depends on, and otherwise keeps its requested position.
+func orderSteps(requested []WorkflowStepRequest) ([]core.WorkflowStep, error) {
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("%w: a workflow needs at least one step", ErrInvalidWorkflow)
+	}
+	if len(requested) > maxWorkflowSteps {
+		return nil, fmt.Errorf("%w: a workflow may have at most %d steps", ErrInvalidWorkflow, maxWorkflowSteps)
+	}
+
+	// 1. Check each step on its own
+	names := make(map[string]bool, len(requested))
+	for _, step := range requested {
+		if !stepNamePattern.MatchString(step.Name) {
+			return nil, fmt.Errorf("%w: invalid step name %q", ErrInvalidWorkflow, step.Name)
+		}
+		if names[step.Name] {
+			return nil, fmt.Errorf("%w: step %q is declared twice", ErrInvalidWorkflow, step.Name)
+		}
+		names[step.Name] = true
+	}
+	for _, step := range requested {
+		if err := checkStep(step, names); err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. Place steps once their dependencies are placed, failing if none can be
+	ordered := make([]core.WorkflowStep, 0, len(requested))
+	placed := make(map[string]bool, len(requested))
+	remaining := slices.Clone(requested)
+	for len(remaining) > 0 {
+		next := slices.IndexFunc(remaining, func(step WorkflowStepRequest) bool {
+			return !slices.ContainsFunc(step.DependsOn, func(dep string) bool { return !placed[dep] })
+		})
+		if next < 0 {
+			cycle := make([]string, len(remaining))
+			for i, step := range remaining {
+				cycle[i] = step.Name
+			}
+			return nil, fmt.Errorf("%w: steps %s depend on each other in a cycle", ErrInvalidWorkflow, strings.Join(cycle, ", "))
+		}
+		step := remaining[next]
+		remaining = slices.Delete(remaining, next, next+1)
+		placed[step.Name] = true
+		ordered = append(ordered, core.WorkflowStep{
+			Name:        step.Name,
+			JobType:     step.JobType,
+			JobConfig:   step.JobConfig,
+			DependsOn:   step.DependsOn,
+			RetryPolicy: step.RetryPolicy,
+			Status:      core.JobStatusPending,
+		})
+	}
+	return ordered, nil
+}
+
+// checkStep checks a step's job and that its dependencies and placeholders name other steps
+// of the workflow. Placeholders may only read the results of direct dependencies.
+func checkStep(step WorkflowStepRequest, names map[string]bool) error {
+	if step.JobType == "" {
+		return fmt.Errorf("%w: step %q needs a jobType", ErrInvalidWorkflow, step.Name)
+	}
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(step.JobConfig), &config); err != nil || config == nil {
+		return fmt.Errorf("%w: jobConfig of step %q must be a JSON object", ErrInvalidWorkflow, step.Name)
+	}
+	for i, dep := range step.DependsOn {
+		switch {
+		case dep == step.Name:
+			return fmt.Errorf("%w: step %q depends on itself", ErrInvalidWorkflow, step.Name)
+		case !names[dep]:
+			return fmt.Errorf("%w: step %q depends on unknown step %q", ErrInvalidWorkflow, step.Name, dep)
+		case slices.Contains(step.DependsOn[:i], dep):
+			return fmt.Errorf("%w: step %q lists dependency %q twice", ErrInvalidWorkflow, step.Name, dep)
+		}
+	}
+	for _, match := range stepResultPattern.FindAllStringSubmatch(step.JobConfig, -1) {
+		if !slices.Contains(step.DependsOn, match[1]) {
+			return fmt.Errorf("%w: step %q reads the result of %q without depending on it", ErrInvalidWorkflow, step.Name, match[1])
+		}
+	}
+	return nil
+}
+
+// stepJobConfig returns the job config a step runs with: its placeholders are replaced by
+// the result URIs of its dependencies, which are also passed in full under
+// configUpstreamResultsKey.
+func stepJobConfig(step core.WorkflowStep, results map[string]string) (string, error) {
+	substituted := stepResultPattern.ReplaceAllStringFunc(step.JobConfig, func(placeholder string) string {
+		uri := results[stepResultPattern.FindStringSubmatch(placeholder)[1]]
+		encoded, _ := json.Marshal(uri)
+		return string(encoded[1 : len(encoded)-1]) // Placeholders sit inside JSON strings
+	})
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(substituted), &config); err != nil || config == nil {
+		return "", fmt.Errorf("jobConfig of step %q is not a JSON object once results are filled in", step.Name)
+	}
+	if len(step.DependsOn) > 0 {
+		upstream := make(map[string]string, len(step.DependsOn))
+		for _, dep := range step.DependsOn {
+			upstream[dep] = results[dep]
+		}
+		config[configUpstreamResultsKey] = upstream
+	}
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode jobConfig of step %q: %w", step.Name, err)
+	}
+	return string(encoded), nil
+}
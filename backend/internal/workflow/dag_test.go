@@ -0,0 +1,99 @@
+package workflow
+
+import (
+	"SynDataGen/backend/internal/core"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderSteps(t *testing.T) {
+	step := func(name string, deps ...string) WorkflowStepRequest {
+		return WorkflowStepRequest{Name: name, JobType: "csv", JobConfig: `{"rows":10}`, DependsOn: deps}
+	}
+	names := func(steps []core.WorkflowStep) []string {
+		out := make([]string, len(steps))
+		for i, s := range steps {
+			out[i] = s.Name
+		}
+		return out
+	}
+
+	t.Run("Success_DependencyOrder", func(t *testing.T) {
+		steps, err := orderSteps([]WorkflowStepRequest{
+			step("orders", "customers", "products"),
+			step("customers"),
+			step("order_items", "orders", "products"),
+			step("products"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"customers", "products", "orders", "order_items"}, names(steps))
+		for _, s := range steps {
+			assert.Equal(t, core.JobStatusPending, s.Status)
+		}
+	})
+
+	t.Run("Success_IndependentStepsKeepOrder", func(t *testing.T) {
+		steps, err := orderSteps([]WorkflowStepRequest{step("b"), step("a"), step("c")})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b", "a", "c"}, names(steps))
+	})
+
+	t.Run("Success_PlaceholderOfDependency", func(t *testing.T) {
+		child := step("orders", "customers")
+		child.JobConfig = `{"inputDataset":{"storageUri":"{{ steps.customers.resultUri }}"}}`
+		_, err := orderSteps([]WorkflowStepRequest{step("customers"), child})
+		assert.NoError(t, err)
+	})
+
+	failures := []struct {
+		name  string
+		steps []WorkflowStepRequest
+		want  string
+	}{
+		{"NoSteps", nil, "at least one step"},
+		{"BadName", []WorkflowStepRequest{step("has space")}, "invalid step name"},
+		{"DuplicateName", []WorkflowStepRequest{step("a"), step("a")}, "declared twice"},
+		{"UnknownDependency", []WorkflowStepRequest{step("a", "b")}, "unknown step"},
+		{"SelfDependency", []WorkflowStepRequest{step("a", "a")}, "depends on itself"},
+		{"DuplicateDependency", []WorkflowStepRequest{step("a"), step("b", "a", "a")}, "twice"},
+		{"Cycle", []WorkflowStepRequest{step("root"), step("a", "c"), step("b", "a"), step("c", "b")}, "steps a, b, c depend on each other"},
+		{"ConfigNotObject", []WorkflowStepRequest{{Name: "a", JobType: "csv", JobConfig: `[1]`}}, "must be a JSON object"},
+		{"MissingJobType", []WorkflowStepRequest{{Name: "a", JobConfig: `{}`}}, "needs a jobType"},
+		{"PlaceholderWithoutDependency", []WorkflowStepRequest{step("a"), {Name: "b", JobType: "csv", JobConfig: `{"x":"{{steps.a.resultUri}}"}`}}, "without depending on it"},
+	}
+	for _, tc := range failures {
+		t.Run("Failure_"+tc.name, func(t *testing.T) {
+			_, err := orderSteps(tc.steps)
+			require.ErrorIs(t, err, ErrInvalidWorkflow)
+			assert.Contains(t, err.Error(), tc.want)
+		})
+	}
+}
+
+func TestStepJobConfig(t *testing.T) {
+	t.Run("Success_FillsPlaceholdersAndUpstreamResults", func(t *testing.T) {
+		step := core.WorkflowStep{
+			Name:      "orders",
+			JobConfig: `{"inputDataset":{"storageUri":"{{steps.customers.resultUri}}"},"rows":100}`,
+			DependsOn: []string{"customers", "products"},
+		}
+		config, err := stepJobConfig(step, map[string]string{
+			"customers": `gs://bucket/jobs/1/customers "v1".csv`,
+			"products":  "gs://bucket/jobs/2/",
+		})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"inputDataset": {"storageUri": "gs://bucket/jobs/1/customers \"v1\".csv"},
+			"rows": 100,
+			"upstreamResults": {"customers": "gs://bucket/jobs/1/customers \"v1\".csv", "products": "gs://bucket/jobs/2/"}
+		}`, config)
+	})
+
+	t.Run("Success_RootStepUnchanged", func(t *testing.T) {
+		config, err := stepJobConfig(core.WorkflowStep{Name: "a", JobConfig: `{"rows":1}`}, nil)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"rows":1}`, config)
+	})
+}
@@ -0,0 +1,173 @@
+package workflow
+
+import (
+	"SynDataGen/backend/internal/access"
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// WorkflowRequest defines the JSON body for creating a workflow.
+type WorkflowRequest struct {
+	Name  string                `json:"name" binding:"required"`
+	Steps []WorkflowStepRequest `json:"steps" binding:"required,dive"`
+}
+
+// WorkflowStepRequest defines one step of a WorkflowRequest. A step's jobConfig may read the
+// result URI of a step it depends on through a {{steps.<name>.resultUri}} placeholder; the
+// job also receives all of them under "upstreamResults".
+type WorkflowStepRequest struct {
+	Name        string               `json:"name" binding:"required"` // Letters, digits, '-' and '_'
+	JobType     string               `json:"jobType" binding:"required"`
+	JobConfig   string               `json:"jobConfig" binding:"required"`
+	DependsOn   []string             `json:"dependsOn,omitempty"` // Names of steps that must complete first
+	RetryPolicy *core.JobRetryPolicy `json:"retryPolicy,omitempty"`
+}
+
+// WorkflowHandler handles HTTP requests for workflows.
+type WorkflowHandler struct {
+	service WorkflowService
+}
+
+// NewWorkflowHandler creates a new WorkflowHandler.
+func NewWorkflowHandler(s WorkflowService) *WorkflowHandler {
+	return &WorkflowHandler{service: s}
+}
+
+// RegisterRoutes registers workflow routes with the Gin router group.
+func (h *WorkflowHandler) RegisterRoutes(rg *gin.RouterGroup, authMiddleware gin.HandlerFunc) {
+	workflows := rg.Group("/projects/:projectId/workflows")
+	workflows.Use(authMiddleware)
+	{
+		workflows.POST("", h.CreateWorkflow)                    // POST /api/v1/projects/:projectId/workflows
+		workflows.GET("", h.ListWorkflows)                      // GET /api/v1/projects/:projectId/workflows
+		workflows.GET("/:workflowId", h.GetWorkflow)            // GET /api/v1/projects/:projectId/workflows/:workflowId
+		workflows.DELETE("/:workflowId", h.DeleteWorkflow)      // DELETE /api/v1/projects/:projectId/workflows/:workflowId
+		workflows.POST("/:workflowId/cancel", h.CancelWorkflow) // POST /api/v1/projects/:projectId/workflows/:workflowId/cancel
+	}
+}
+
+// errorCodes maps this package's errors to responses.
+var errorCodes = []access.ErrorCode{
+	{Err: ErrInvalidWorkflow, Status: http.StatusBadRequest, Code: "INVALID_WORKFLOW"},
+	{Err: ErrWorkflowFinished, Status: http.StatusConflict, Code: "WORKFLOW_FINISHED"},
+	{Err: ErrWorkflowActive, Status: http.StatusConflict, Code: "WORKFLOW_ACTIVE"},
+}
+
+// abortWithError maps service errors to responses.
+func abortWithError(c *gin.Context, err error, fallback string) {
+	access.AbortWithError(c, err, "Workflow or project not found", fallback, errorCodes...)
+}
+
+// CreateWorkflow handles POST /projects/:projectId/workflows requests.
+func (h *WorkflowHandler) CreateWorkflow(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "CreateWorkflow")
+	if !ok {
+		return
+	}
+
+	var req WorkflowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Logger.Warn("Invalid request body for CreateWorkflow", zap.Error(err))
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	workflow, err := h.service.CreateWorkflow(c.Request.Context(), projectID, userID, req)
+	if err != nil {
+		logger.Logger.Error("Failed to create workflow via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to create workflow")
+		return
+	}
+	c.JSON(http.StatusCreated, workflow)
+}
+
+// ListWorkflows handles GET /projects/:projectId/workflows requests.
+func (h *WorkflowHandler) ListWorkflows(c *gin.Context) {
+	projectID := c.Param("projectId")
+	userID, ok := access.RequireUserID(c, "ListWorkflows")
+	if !ok {
+		return
+	}
+
+	limitStr := c.DefaultQuery("limit", "20")
+	offsetStr := c.DefaultQuery("offset", "0")
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid 'limit' query parameter"})
+		return
+	}
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid 'offset' query parameter"})
+		return
+	}
+
+	workflows, total, err := h.service.ListWorkflows(c.Request.Context(), projectID, userID, limit, offset)
+	if err != nil {
+		logger.Logger.Error("Failed to list workflows via service", zap.Error(err), zap.String("userId", userID), zap.String("projectId", projectID))
+		abortWithError(c, err, "Failed to list workflows")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"workflows": workflows,
+		"total":     total,
+		"limit":     limit,
+		"offset":    offset,
+	})
+}
+
+// GetWorkflow handles GET /projects/:projectId/workflows/:workflowId requests.
+func (h *WorkflowHandler) GetWorkflow(c *gin.Context) {
+	projectID, workflowID := c.Param("projectId"), c.Param("workflowId")
+	userID, ok := access.RequireUserID(c, "GetWorkflow")
+	if !ok {
+		return
+	}
+
+	workflow, err := h.service.GetWorkflow(c.Request.Context(), projectID, workflowID, userID)
+	if err != nil {
+		logger.Logger.Error("Failed to get workflow via service", zap.Error(err), zap.String("userId", userID), zap.String("workflowId", workflowID))
+		abortWithError(c, err, "Failed to retrieve workflow")
+		return
+	}
+	c.JSON(http.StatusOK, workflow)
+}
+
+// CancelWorkflow handles POST /projects/:projectId/workflows/:workflowId/cancel requests.
+func (h *WorkflowHandler) CancelWorkflow(c *gin.Context) {
+	projectID, workflowID := c.Param("projectId"), c.Param("workflowId")
+	userID, ok := access.RequireUserID(c, "CancelWorkflow")
+	if !ok {
+		return
+	}
+
+	workflow, err := h.service.CancelWorkflow(c.Request.Context(), projectID, workflowID, userID)
+	if err != nil {
+		logger.Logger.Error("Failed to cancel workflow via service", zap.Error(err), zap.String("userId", userID), zap.String("workflowId", workflowID))
+		abortWithError(c, err, "Failed to cancel workflow")
+		return
+	}
+	c.JSON(http.StatusOK, workflow)
+}
+
+// DeleteWorkflow handles DELETE /projects/:projectId/workflows/:workflowId requests.
+func (h *WorkflowHandler) DeleteWorkflow(c *gin.Context) {
+	projectID, workflowID := c.Param("projectId"), c.Param("workflowId")
+	userID, ok := access.RequireUserID(c, "DeleteWorkflow")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteWorkflow(c.Request.Context(), projectID, workflowID, userID); err != nil {
+		logger.Logger.Error("Failed to delete workflow via service", zap.Error(err), zap.String("userId", userID), zap.String("workflowId", workflowID))
+		abortWithError(c, err, "Failed to delete workflow")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,177 @@
+package workflow
+
+import (
+	"SynDataGen/backend/internal/auth"
+	"SynDataGen/backend/internal/core"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWorkflowService is a mock implementation of WorkflowService.
+type MockWorkflowService struct {
+	mock.Mock
+}
+
+func (m *MockWorkflowService) CreateWorkflow(ctx context.Context, projectID, userID string, req WorkflowRequest) (*core.Workflow, error) {
+	args := m.Called(ctx, projectID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Workflow), args.Error(1)
+}
+
+func (m *MockWorkflowService) GetWorkflow(ctx context.Context, projectID, workflowID, userID string) (*core.Workflow, error) {
+	args := m.Called(ctx, projectID, workflowID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Workflow), args.Error(1)
+}
+
+func (m *MockWorkflowService) ListWorkflows(ctx context.Context, projectID, userID string, limit, offset int) ([]*core.Workflow, int, error) {
+	args := m.Called(ctx, projectID, userID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.Workflow), args.Int(1), args.Error(2)
+}
+
+func (m *MockWorkflowService) CancelWorkflow(ctx context.Context, projectID, workflowID, userID string) (*core.Workflow, error) {
+	args := m.Called(ctx, projectID, workflowID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Workflow), args.Error(1)
+}
+
+func (m *MockWorkflowService) DeleteWorkflow(ctx context.Context, projectID, workflowID, userID string) error {
+	args := m.Called(ctx, projectID, workflowID, userID)
+	return args.Error(0)
+}
+
+func setupGinTestRouter() (*gin.Engine, *MockWorkflowService) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	mockService := new(MockWorkflowService)
+	mockAuthMiddleware := func(c *gin.Context) {
+		if userID := c.GetHeader("X-User-ID"); userID != "" {
+			c.Set(auth.UserIDKey, userID)
+		}
+		c.Next()
+	}
+	NewWorkflowHandler(mockService).RegisterRoutes(router.Group("/"), mockAuthMiddleware)
+	return router, mockService
+}
+
+func serve(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", "member")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestWorkflowHandler(t *testing.T) {
+	base := "/projects/proj-1/workflows"
+
+	t.Run("Success_Create", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		expected := WorkflowRequest{Name: "Shop", Steps: []WorkflowStepRequest{
+			{Name: "customers", JobType: "csv", JobConfig: `{"rows":10}`},
+			{Name: "orders", JobType: "csv", JobConfig: `{"rows":100}`, DependsOn: []string{"customers"}},
+		}}
+		mockService.On("CreateWorkflow", mock.Anything, "proj-1", "member", expected).Return(&core.Workflow{ID: "wf-1"}, nil).Once()
+
+		w := serve(router, http.MethodPost, base, `{"name":"Shop","steps":[
+			{"name":"customers","jobType":"csv","jobConfig":"{\"rows\":10}"},
+			{"name":"orders","jobType":"csv","jobConfig":"{\"rows\":100}","dependsOn":["customers"]}]}`)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("Failure_CreateStepMissingJobType", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+
+		w := serve(router, http.MethodPost, base, `{"name":"Shop","steps":[{"name":"customers","jobConfig":"{}"}]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockService.AssertNotCalled(t, "CreateWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_CreateInvalidWorkflow", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("CreateWorkflow", mock.Anything, "proj-1", "member", mock.Anything).
+			Return(nil, fmt.Errorf("%w: steps a, b depend on each other in a cycle", ErrInvalidWorkflow)).Once()
+
+		w := serve(router, http.MethodPost, base, `{"name":"Loop","steps":[{"name":"a","jobType":"csv","jobConfig":"{}","dependsOn":["a"]}]}`)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "INVALID_WORKFLOW")
+	})
+
+	t.Run("Success_List", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("ListWorkflows", mock.Anything, "proj-1", "member", 20, 0).Return([]*core.Workflow{{ID: "wf-1"}}, 1, nil).Once()
+
+		w := serve(router, http.MethodGet, base, "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"total":1`)
+	})
+
+	t.Run("Success_Get", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("GetWorkflow", mock.Anything, "proj-1", "wf-1", "member").Return(&core.Workflow{
+			ID:       "wf-1",
+			Status:   core.JobStatusRunning,
+			Progress: 50,
+			Steps:    []core.WorkflowStep{{Name: "customers", Status: core.JobStatusRunning, Progress: 50}},
+		}, nil).Once()
+
+		w := serve(router, http.MethodGet, base+"/wf-1", "")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"progress":50`)
+	})
+
+	t.Run("Failure_CancelFinished", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("CancelWorkflow", mock.Anything, "proj-1", "wf-1", "member").
+			Return(nil, fmt.Errorf("%w: workflow wf-1 is completed", ErrWorkflowFinished)).Once()
+
+		w := serve(router, http.MethodPost, base+"/wf-1/cancel", "")
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "WORKFLOW_FINISHED")
+	})
+
+	t.Run("Failure_DeleteActive", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("DeleteWorkflow", mock.Anything, "proj-1", "wf-1", "member").
+			Return(fmt.Errorf("%w: cancel workflow wf-1 before deleting it", ErrWorkflowActive)).Once()
+
+		w := serve(router, http.MethodDelete, base+"/wf-1", "")
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "WORKFLOW_ACTIVE")
+	})
+
+	t.Run("Success_Delete", func(t *testing.T) {
+		router, mockService := setupGinTestRouter()
+		mockService.On("DeleteWorkflow", mock.Anything, "proj-1", "wf-1", "member").Return(nil).Once()
+
+		w := serve(router, http.MethodDelete, base+"/wf-1", "")
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+}
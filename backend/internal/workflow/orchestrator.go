@@ -0,0 +1,420 @@
+package workflow
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"SynDataGen/backend/internal/platform/lease"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// DefaultOrchestratorInterval is how often the Orchestrator advances workflows when no
+// interval is configured.
+const DefaultOrchestratorInterval = 10 * time.Second
+
+const (
+	leaseName       = "workflow-orchestrator" // Lease held by the replica that advances workflows
+	activeBatchSize = 100                     // Workflows advanced per tick at most; the rest wait for the next
+)
+
+// JobRunner creates, submits, cancels and syncs jobs. job.JobService satisfies this interface.
+type JobRunner interface {
+	CreateJob(ctx context.Context, projectID, userID string, req job.CreateJobRequest) (*core.Job, error)
+	SubmitJob(ctx context.Context, jobID, userID string) (*core.Job, error)
+	CancelJob(ctx context.Context, jobID, userID string) (*core.Job, error)
+	RefreshJobStatus(ctx context.Context, jobID string) (*core.Job, error)
+}
+
+// OrchestratorConfig holds configuration for the Orchestrator.
+type OrchestratorConfig struct {
+	Interval time.Duration // Time between ticks; defaults to DefaultOrchestratorInterval
+	HolderID string        // Identifies this replica in the lease; required
+}
+
+// Orchestrator advances active workflows: it syncs the jobs of running steps, starts steps
+// whose dependencies have completed and cancels steps whose dependencies failed or were
+// cancelled. Only the replica holding the orchestrator lease advances workflows, so no step
+// is started twice.
+//
+// A step that fails does not stop steps that do not depend on it; the workflow fails once
+// every step has finished.
+type Orchestrator struct {
+	workflowRepo core.WorkflowRepository
+	jobRepo      core.JobRepository
+	jobs         JobRunner
+	pipeline     job.PipelineClient
+	runner       *lease.Runner
+	now          func() time.Time // Overridable for tests
+}
+
+// NewOrchestrator creates a new Orchestrator.
+func NewOrchestrator(workflowRepo core.WorkflowRepository, leaseRepo core.LeaseRepository, jobRepo core.JobRepository, jobs JobRunner, pipeline job.PipelineClient, cfg OrchestratorConfig) *Orchestrator {
+	if workflowRepo == nil || leaseRepo == nil || jobRepo == nil || jobs == nil || pipeline == nil {
+		panic("workflow.NewOrchestrator: all dependencies are required")
+	}
+	if cfg.HolderID == "" {
+		panic("workflow.NewOrchestrator: HolderID is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultOrchestratorInterval
+	}
+	return &Orchestrator{
+		workflowRepo: workflowRepo,
+		jobRepo:      jobRepo,
+		jobs:         jobs,
+		pipeline:     pipeline,
+		runner:       lease.NewRunner(leaseRepo, leaseName, cfg.HolderID, cfg.Interval),
+		now:          storedNow,
+	}
+}
+
+// storedNow returns the current time at the precision Firestore stores, so that a saved
+// UpdatedAt compares equal to the value read back.
+func storedNow() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// Run ticks immediately and then once per interval until ctx is cancelled.
+func (o *Orchestrator) Run(ctx context.Context) {
+	o.runner.Run(ctx, func(ctx context.Context) error {
+		_, err := o.advanceActive(ctx)
+		return err
+	})
+}
+
+// AdvanceOnce advances every active workflow, if this replica holds the lease, and returns
+// how many workflows changed.
+func (o *Orchestrator) AdvanceOnce(ctx context.Context) (int, error) {
+	var changed int
+	_, err := o.runner.TickOnce(ctx, func(ctx context.Context) (err error) {
+		changed, err = o.advanceActive(ctx)
+		return err
+	})
+	return changed, err
+}
+
+// advanceActive advances the active workflows. Per-workflow failures are returned together;
+// they do not stop the tick.
+func (o *Orchestrator) advanceActive(ctx context.Context) (int, error) {
+	active, err := o.workflowRepo.ListActiveWorkflows(ctx, activeBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list active workflows: %w", err)
+	}
+	changed := 0
+	var errs []error
+	for _, workflow := range active {
+		if ctx.Err() != nil {
+			return changed, ctx.Err()
+		}
+		ok, err := o.advance(ctx, workflow)
+		if ok {
+			changed++
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("workflow %s: %w", workflow.ID, err))
+		}
+	}
+	return changed, errors.Join(errs...)
+}
+
+// advance moves one workflow forward and saves it if anything changed, reporting whether it
+// did. Jobs started here are cancelled again if the workflow cannot be saved.
+func (o *Orchestrator) advance(ctx context.Context, workflow *core.Workflow) (bool, error) {
+	log := logger.Logger.With(zap.String("workflowID", workflow.ID), zap.String("projectID", workflow.ProjectID))
+	prevUpdatedAt := workflow.UpdatedAt
+	before := *workflow
+	before.Steps = slices.Clone(workflow.Steps)
+	now := o.now()
+	var errs []error
+
+	// 1. Follow the jobs of started steps
+	for i := range workflow.Steps {
+		step := &workflow.Steps[i]
+		if step.JobID == "" || isFinalStatus(step.Status) {
+			continue
+		}
+		if err := o.syncStep(ctx, workflow, step); err != nil {
+			errs = append(errs, fmt.Errorf("step %s: %w", step.Name, err))
+		}
+	}
+
+	// 2. In dependency order, cancel the steps that can no longer run and start the ready ones
+	var started []string
+	for i := range workflow.Steps {
+		step := &workflow.Steps[i]
+		if step.JobID != "" || isFinalStatus(step.Status) {
+			continue
+		}
+		blocker, ready := checkDependencies(workflow, step)
+		if blocker != nil {
+			step.Status = core.JobStatusCancelled
+			step.Error = fmt.Sprintf("Upstream step %q %s", blocker.Name, blocker.Status)
+			step.CompletedAt = &now
+			continue
+		}
+		if !ready {
+			continue
+		}
+		jobID, err := o.startStep(ctx, workflow, step, now)
+		if jobID != "" {
+			started = append(started, jobID)
+		}
+		if err != nil {
+			log.Warn("Failed to start workflow step", zap.String("step", step.Name), zap.Error(err))
+		}
+	}
+
+	// 3. Save the workflow if anything changed
+	summarize(workflow, now)
+	if reflect.DeepEqual(&before, workflow) {
+		return false, errors.Join(errs...)
+	}
+	workflow.UpdatedAt = now
+	if err := o.workflowRepo.SaveWorkflowState(ctx, workflow, prevUpdatedAt); err != nil {
+		for _, jobID := range started {
+			if _, cancelErr := o.jobs.CancelJob(ctx, jobID, workflow.CreatedBy); cancelErr != nil {
+				log.Error("Failed to cancel job of unsaved workflow step", zap.String("jobID", jobID), zap.Error(cancelErr))
+			}
+		}
+		if errors.Is(err, core.ErrConflict) || errors.Is(err, core.ErrNotFound) {
+			log.Debug("Workflow changed or deleted while advancing it", zap.Error(err))
+			return false, errors.Join(errs...) // Picked up again on the next tick, if still active
+		}
+		return false, errors.Join(append(errs, err)...)
+	}
+	if workflow.Status != before.Status {
+		log.Info("Workflow status changed", zap.String("oldStatus", string(before.Status)), zap.String("newStatus", string(workflow.Status)))
+	}
+	return true, errors.Join(errs...)
+}
+
+// syncStep updates a started step from its job, following the job's automatic retry if it
+// failed, and resubmits a job that a pipeline outage left unsubmitted.
+func (o *Orchestrator) syncStep(ctx context.Context, workflow *core.Workflow, step *core.WorkflowStep) error {
+	// 1. Sync the job with the pipeline; on a failed check the stored job is still returned
+	current, err := o.jobs.RefreshJobStatus(ctx, step.JobID)
+	if current == nil {
+		return fmt.Errorf("failed to refresh job %s: %w", step.JobID, err)
+	}
+	if err != nil {
+		logger.Logger.Debug("Failed to refresh workflow step job", zap.String("jobID", step.JobID), zap.Error(err))
+	}
+
+	// 2. A failed job whose retry policy applies has been replaced by its automatic retry
+	if current.Status == core.JobStatusFailed {
+		retry, err := o.jobRepo.GetJobByID(ctx, job.AutomaticRetryID(current.ID))
+		if err != nil && !errors.Is(err, core.ErrNotFound) {
+			return fmt.Errorf("failed to look up retry of job %s: %w", current.ID, err)
+		}
+		if retry != nil {
+			step.JobID = retry.ID
+			current = retry
+		}
+	}
+
+	// 3. Submit jobs left pending by a pipeline outage or a job limit; retries are submitted
+	// by the StatusWatcher
+	if current.Status == core.JobStatusPending && current.PipelineJobID == "" && current.RetryAt == nil {
+		submitted, err := o.jobs.SubmitJob(ctx, current.ID, workflow.CreatedBy)
+		if submitted != nil {
+			current = submitted
+		}
+		if err != nil && !canSubmitLater(err) {
+			applyJob(step, current, step.Progress)
+			return o.failUnsubmitted(ctx, workflow, step, err)
+		}
+	}
+
+	applyJob(step, current, o.progress(ctx, current, step.Progress))
+	return nil
+}
+
+// startStep creates and submits a step's job on behalf of the workflow's creator, passing it
+// the results of the steps it depends on. It returns the ID of any job created. A step
+// whose job cannot be created or submitted fails, except during a pipeline outage or while
+// a job limit is reached, when it is tried again on a later tick.
+func (o *Orchestrator) startStep(ctx context.Context, workflow *core.Workflow, step *core.WorkflowStep, now time.Time) (string, error) {
+	// 1. Fill in the upstream results
+	results := make(map[string]string, len(step.DependsOn))
+	for _, dep := range step.DependsOn {
+		results[dep] = findStep(workflow, dep).ResultURI
+	}
+	config, err := stepJobConfig(*step, results)
+	if err != nil {
+		failStep(step, err.Error(), now)
+		return "", err
+	}
+
+	// 2. Create the job
+	created, err := o.jobs.CreateJob(ctx, workflow.ProjectID, workflow.CreatedBy, job.CreateJobRequest{
+		ProjectID:   workflow.ProjectID,
+		JobType:     step.JobType,
+		JobConfig:   config,
+		RetryPolicy: step.RetryPolicy,
+		WorkflowID:  workflow.ID,
+	})
+	if errors.Is(err, job.ErrJobLimitExceeded) {
+		return "", nil // Stays unstarted until the creation rate allows another job
+	}
+	if err != nil {
+		failStep(step, fmt.Sprintf("Failed to create job: %v", err), now)
+		return "", err
+	}
+	applyJob(step, created, 0)
+
+	// 3. Submit it
+	submitted, err := o.jobs.SubmitJob(ctx, created.ID, workflow.CreatedBy)
+	if submitted != nil {
+		applyJob(step, submitted, 0)
+	}
+	if canSubmitLater(err) {
+		return created.ID, nil // Stays pending; syncStep submits it again
+	}
+	if err != nil {
+		return created.ID, o.failUnsubmitted(ctx, workflow, step, err)
+	}
+	return created.ID, nil
+}
+
+// canSubmitLater reports whether a job whose submission failed with err can be submitted
+// again later, because the pipeline is unavailable or a job limit is reached.
+func canSubmitLater(err error) bool {
+	return errors.Is(err, job.ErrPipelineUnavailable) || errors.Is(err, job.ErrJobLimitExceeded)
+}
+
+// failUnsubmitted fails a step whose job could not be submitted. A job the submission left
+// pending is cancelled so it is not mistaken for a waiting retry.
+func (o *Orchestrator) failUnsubmitted(ctx context.Context, workflow *core.Workflow, step *core.WorkflowStep, submitErr error) error {
+	if step.Status == core.JobStatusPending {
+		if _, err := o.jobs.CancelJob(ctx, step.JobID, workflow.CreatedBy); err != nil {
+			logger.Logger.Warn("Failed to cancel unsubmitted workflow step job", zap.String("jobID", step.JobID), zap.Error(err))
+		}
+	}
+	failStep(step, fmt.Sprintf("Failed to submit job: %v", submitErr), o.now())
+	return fmt.Errorf("failed to submit job %s: %w", step.JobID, submitErr)
+}
+
+// progress returns a job's progress percentage, asking the pipeline for running jobs if it
+// reports progress. last is kept when the pipeline cannot say.
+func (o *Orchestrator) progress(ctx context.Context, current *core.Job, last int) int {
+	switch current.Status {
+	case core.JobStatusCompleted:
+		return 100
+	case core.JobStatusPending:
+		return 0
+	case core.JobStatusRunning:
+		reporter, ok := o.pipeline.(job.ProgressReporter)
+		if !ok {
+			return last
+		}
+		progress, err := reporter.Progress(ctx, current.PipelineJobID)
+		if err != nil {
+			logger.Logger.Debug("Failed to get workflow step progress", zap.String("jobID", current.ID), zap.Error(err))
+			return last
+		}
+		return progress
+	}
+	return last
+}
+
+// applyJob copies the state of a step's job onto the step.
+func applyJob(step *core.WorkflowStep, current *core.Job, progress int) {
+	step.JobID = current.ID
+	step.Status = current.Status
+	step.Progress = progress
+	step.ResultURI = current.ResultURI
+	step.Error = current.Error
+	step.StartedAt = current.StartedAt
+	step.CompletedAt = current.CompletedAt
+}
+
+// failStep marks a step failed with the given error.
+func failStep(step *core.WorkflowStep, message string, now time.Time) {
+	step.Status = core.JobStatusFailed
+	step.Error = message
+	step.CompletedAt = &now
+}
+
+// checkDependencies returns the first dependency of a step that failed or was cancelled, if
+// any, and whether all of its dependencies have completed.
+func checkDependencies(workflow *core.Workflow, step *core.WorkflowStep) (*core.WorkflowStep, bool) {
+	ready := true
+	for _, dep := range step.DependsOn {
+		upstream := findStep(workflow, dep)
+		switch upstream.Status {
+		case core.JobStatusFailed, core.JobStatusCancelled:
+			return upstream, false
+		case core.JobStatusCompleted:
+		default:
+			ready = false
+		}
+	}
+	return nil, ready
+}
+
+// findStep returns the step with the given name. Step names are validated on creation.
+func findStep(workflow *core.Workflow, name string) *core.WorkflowStep {
+	for i := range workflow.Steps {
+		if workflow.Steps[i].Name == name {
+			return &workflow.Steps[i]
+		}
+	}
+	panic(fmt.Sprintf("workflow %s has no step %q", workflow.ID, name))
+}
+
+// summarize sets a workflow's status, progress and error from its steps. Once every step has
+// finished, the workflow completed if they all did, and otherwise failed if any step failed
+// or was cancelled if none did.
+func summarize(workflow *core.Workflow, now time.Time) {
+	total, finished, started := 0, 0, false
+	var failed, cancelled *core.WorkflowStep
+	for i := range workflow.Steps {
+		step := &workflow.Steps[i]
+		total += step.Progress
+		if step.JobID != "" {
+			started = true
+		}
+		if isFinalStatus(step.Status) {
+			finished++
+		}
+		if step.Status == core.JobStatusFailed && failed == nil {
+			failed = step
+		}
+		if step.Status == core.JobStatusCancelled && cancelled == nil {
+			cancelled = step
+		}
+	}
+	if len(workflow.Steps) > 0 {
+		workflow.Progress = total / len(workflow.Steps)
+	}
+
+	switch {
+	case finished < len(workflow.Steps) && started:
+		workflow.Status = core.JobStatusRunning
+	case finished < len(workflow.Steps):
+		workflow.Status = core.JobStatusPending
+	case failed != nil:
+		workflow.Status = core.JobStatusFailed
+		workflow.Error = fmt.Sprintf("Step %q failed: %s", failed.Name, failed.Error)
+	case cancelled != nil:
+		workflow.Status = core.JobStatusCancelled
+		workflow.Error = fmt.Sprintf("Step %q was cancelled: %s", cancelled.Name, cancelled.Error)
+	default:
+		workflow.Status = core.JobStatusCompleted
+	}
+	if isFinalStatus(workflow.Status) && workflow.CompletedAt == nil {
+		workflow.CompletedAt = &now
+	}
+}
+
+// isFinalStatus reports whether a job, step or workflow status is final.
+func isFinalStatus(status core.JobStatus) bool {
+	return status == core.JobStatusCompleted || status == core.JobStatusFailed || status == core.JobStatusCancelled
+}
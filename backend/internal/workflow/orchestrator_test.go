@@ -0,0 +1,384 @@
+package workflow
+
+import (
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/job"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockLeaseRepository is a mock implementation of core.LeaseRepository.
+type MockLeaseRepository struct {
+	mock.Mock
+}
+
+func (m *MockLeaseRepository) AcquireLease(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	args := m.Called(ctx, name, holder, ttl)
+	return args.Bool(0), args.Error(1)
+}
+
+// MockJobRepository mocks reading a single job.
+type MockJobRepository struct {
+	mock.Mock
+	core.JobRepository
+}
+
+func (m *MockJobRepository) GetJobByID(ctx context.Context, jobID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+// MockJobRunner is a mock implementation of JobRunner.
+type MockJobRunner struct {
+	mock.Mock
+}
+
+func (m *MockJobRunner) CreateJob(ctx context.Context, projectID, userID string, req job.CreateJobRequest) (*core.Job, error) {
+	args := m.Called(ctx, projectID, userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobRunner) SubmitJob(ctx context.Context, jobID, userID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobRunner) CancelJob(ctx context.Context, jobID, userID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+func (m *MockJobRunner) RefreshJobStatus(ctx context.Context, jobID string) (*core.Job, error) {
+	args := m.Called(ctx, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Job), args.Error(1)
+}
+
+// MockProgressPipeline mocks a pipeline client that reports progress.
+type MockProgressPipeline struct {
+	mock.Mock
+	job.PipelineClient
+}
+
+func (m *MockProgressPipeline) Progress(ctx context.Context, pipelineJobID string) (int, error) {
+	args := m.Called(ctx, pipelineJobID)
+	return args.Int(0), args.Error(1)
+}
+
+type orchestratorMocks struct {
+	workflows *MockWorkflowRepository
+	leases    *MockLeaseRepository
+	jobRepo   *MockJobRepository
+	jobs      *MockJobRunner
+	pipeline  *MockProgressPipeline
+}
+
+func setupOrchestratorTest() (*Orchestrator, orchestratorMocks) {
+	m := orchestratorMocks{
+		workflows: new(MockWorkflowRepository),
+		leases:    new(MockLeaseRepository),
+		jobRepo:   new(MockJobRepository),
+		jobs:      new(MockJobRunner),
+		pipeline:  new(MockProgressPipeline),
+	}
+	o := NewOrchestrator(m.workflows, m.leases, m.jobRepo, m.jobs, m.pipeline, OrchestratorConfig{Interval: time.Minute, HolderID: "replica-1"})
+	o.now = func() time.Time { return testNow }
+	m.leases.On("AcquireLease", mock.Anything, leaseName, "replica-1", 2*time.Minute).Return(true, nil).Maybe()
+	return o, m
+}
+
+// relationalWorkflow returns a workflow generating customers, then orders referencing them,
+// plus an independent products step.
+func relationalWorkflow() *core.Workflow {
+	return &core.Workflow{
+		ID:        "wf-1",
+		ProjectID: "proj-1",
+		CreatedBy: "member",
+		Status:    core.JobStatusPending,
+		UpdatedAt: testNow.Add(-time.Minute),
+		Steps: []core.WorkflowStep{
+			{Name: "customers", JobType: "csv", JobConfig: `{"rows":10}`, Status: core.JobStatusPending},
+			{Name: "products", JobType: "csv", JobConfig: `{"rows":5}`, Status: core.JobStatusPending},
+			{Name: "orders", JobType: "csv", JobConfig: `{"inputDataset":{"storageUri":"{{steps.customers.resultUri}}"}}`, DependsOn: []string{"customers"}, Status: core.JobStatusPending},
+		},
+	}
+}
+
+// expectSave captures the workflow state saved over prevUpdatedAt.
+func expectSave(m orchestratorMocks, prevUpdatedAt time.Time) *core.Workflow {
+	saved := &core.Workflow{}
+	m.workflows.On("SaveWorkflowState", mock.Anything, mock.AnythingOfType("*core.Workflow"), prevUpdatedAt).
+		Run(func(args mock.Arguments) { *saved = *args.Get(1).(*core.Workflow) }).
+		Return(nil).Once()
+	return saved
+}
+
+func runningJob(id string) *core.Job {
+	return &core.Job{ID: id, Status: core.JobStatusRunning, PipelineJobID: "pipe-" + id, StartedAt: &testNow}
+}
+
+func TestOrchestrator_AdvanceOnce(t *testing.T) {
+	t.Run("Success_StartsRootSteps", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		workflow := relationalWorkflow()
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+		for _, name := range []string{"customers", "products"} {
+			m.jobs.On("CreateJob", mock.Anything, "proj-1", "member", mock.MatchedBy(func(req job.CreateJobRequest) bool {
+				return req.WorkflowID == "wf-1" && req.JobType == "csv" && req.JobConfig != ""
+			})).Return(&core.Job{ID: "job-" + name, Status: core.JobStatusPending}, nil).Once()
+			m.jobs.On("SubmitJob", mock.Anything, "job-"+name, "member").Return(runningJob("job-"+name), nil).Once()
+		}
+		saved := expectSave(m, workflow.UpdatedAt)
+
+		changed, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, changed)
+		assert.Equal(t, core.JobStatusRunning, saved.Status)
+		assert.Equal(t, testNow, saved.UpdatedAt)
+		assert.Equal(t, "job-customers", saved.Steps[0].JobID)
+		assert.Equal(t, core.JobStatusRunning, saved.Steps[1].Status)
+		assert.Empty(t, saved.Steps[2].JobID, "orders waits for customers")
+		m.jobs.AssertExpectations(t)
+	})
+
+	t.Run("Success_CompletedStepStartsDependent", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		workflow := relationalWorkflow()
+		workflow.Status = core.JobStatusRunning
+		workflow.Steps[0].JobID, workflow.Steps[0].Status = "job-customers", core.JobStatusRunning
+		workflow.Steps[1].JobID, workflow.Steps[1].Status = "job-products", core.JobStatusRunning
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+
+		completed := &core.Job{ID: "job-customers", Status: core.JobStatusCompleted, ResultURI: "gs://bucket/jobs/job-customers/", CompletedAt: &testNow}
+		m.jobs.On("RefreshJobStatus", mock.Anything, "job-customers").Return(completed, nil).Once()
+		m.jobs.On("RefreshJobStatus", mock.Anything, "job-products").Return(runningJob("job-products"), nil).Once()
+		m.pipeline.On("Progress", mock.Anything, "pipe-job-products").Return(40, nil).Once()
+		m.jobs.On("CreateJob", mock.Anything, "proj-1", "member", mock.MatchedBy(func(req job.CreateJobRequest) bool {
+			return req.JobConfig == `{"inputDataset":{"storageUri":"gs://bucket/jobs/job-customers/"},"upstreamResults":{"customers":"gs://bucket/jobs/job-customers/"}}`
+		})).Return(&core.Job{ID: "job-orders", Status: core.JobStatusPending}, nil).Once()
+		m.jobs.On("SubmitJob", mock.Anything, "job-orders", "member").Return(runningJob("job-orders"), nil).Once()
+		saved := expectSave(m, workflow.UpdatedAt)
+
+		_, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, core.JobStatusCompleted, saved.Steps[0].Status)
+		assert.Equal(t, 100, saved.Steps[0].Progress)
+		assert.Equal(t, "gs://bucket/jobs/job-customers/", saved.Steps[0].ResultURI)
+		assert.Equal(t, 40, saved.Steps[1].Progress)
+		assert.Equal(t, "job-orders", saved.Steps[2].JobID)
+		assert.Equal(t, 46, saved.Progress) // (100 + 40 + 0) / 3
+		assert.Equal(t, core.JobStatusRunning, saved.Status)
+		m.jobs.AssertExpectations(t)
+	})
+
+	t.Run("Success_FailureCancelsDependentsOnly", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		workflow := relationalWorkflow()
+		workflow.Status = core.JobStatusRunning
+		workflow.Steps[0].JobID, workflow.Steps[0].Status = "job-customers", core.JobStatusRunning
+		workflow.Steps[1].JobID, workflow.Steps[1].Status = "job-products", core.JobStatusRunning
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+
+		failed := &core.Job{ID: "job-customers", Status: core.JobStatusFailed, Error: "invalid schema", CompletedAt: &testNow}
+		m.jobs.On("RefreshJobStatus", mock.Anything, "job-customers").Return(failed, nil).Once()
+		m.jobRepo.On("GetJobByID", mock.Anything, job.AutomaticRetryID("job-customers")).Return(nil, core.ErrNotFound).Once()
+		m.jobs.On("RefreshJobStatus", mock.Anything, "job-products").Return(runningJob("job-products"), nil).Once()
+		m.pipeline.On("Progress", mock.Anything, "pipe-job-products").Return(10, nil).Once()
+		saved := expectSave(m, workflow.UpdatedAt)
+
+		_, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, core.JobStatusFailed, saved.Steps[0].Status)
+		assert.Equal(t, core.JobStatusRunning, saved.Steps[1].Status, "independent step keeps running")
+		assert.Equal(t, core.JobStatusCancelled, saved.Steps[2].Status)
+		assert.Equal(t, `Upstream step "customers" failed`, saved.Steps[2].Error)
+		assert.Equal(t, core.JobStatusRunning, saved.Status)
+		m.jobs.AssertNotCalled(t, "CreateJob", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_FollowsAutomaticRetry", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		workflow := relationalWorkflow()
+		workflow.Status = core.JobStatusRunning
+		workflow.Steps = workflow.Steps[:1]
+		workflow.Steps[0].JobID, workflow.Steps[0].Status = "job-customers", core.JobStatusRunning
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+
+		retryID := job.AutomaticRetryID("job-customers")
+		retryAt := testNow.Add(time.Minute)
+		m.jobs.On("RefreshJobStatus", mock.Anything, "job-customers").Return(&core.Job{ID: "job-customers", Status: core.JobStatusFailed, Error: "timeout"}, nil).Once()
+		m.jobRepo.On("GetJobByID", mock.Anything, retryID).Return(&core.Job{ID: retryID, Status: core.JobStatusPending, RetryAt: &retryAt}, nil).Once()
+		saved := expectSave(m, workflow.UpdatedAt)
+
+		_, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, retryID, saved.Steps[0].JobID)
+		assert.Equal(t, core.JobStatusPending, saved.Steps[0].Status)
+		assert.Equal(t, core.JobStatusRunning, saved.Status)
+		m.jobs.AssertNotCalled(t, "SubmitJob", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_PipelineOutageLeavesStepPending", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		workflow := relationalWorkflow()
+		workflow.Steps = workflow.Steps[:1]
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+		m.jobs.On("CreateJob", mock.Anything, "proj-1", "member", mock.Anything).Return(&core.Job{ID: "job-customers", Status: core.JobStatusPending}, nil).Once()
+		m.jobs.On("SubmitJob", mock.Anything, "job-customers", "member").Return(nil, job.ErrPipelineUnavailable).Once()
+		saved := expectSave(m, workflow.UpdatedAt)
+
+		_, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, "job-customers", saved.Steps[0].JobID)
+		assert.Equal(t, core.JobStatusPending, saved.Steps[0].Status)
+		m.jobs.AssertNotCalled(t, "CancelJob", mock.Anything, mock.Anything, mock.Anything)
+
+		// The next tick submits the job again
+		workflow = saved
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+		m.jobs.On("RefreshJobStatus", mock.Anything, "job-customers").Return(&core.Job{ID: "job-customers", Status: core.JobStatusPending}, nil).Once()
+		m.jobs.On("SubmitJob", mock.Anything, "job-customers", "member").Return(runningJob("job-customers"), nil).Once()
+		m.pipeline.On("Progress", mock.Anything, "pipe-job-customers").Return(0, nil).Once()
+		saved = expectSave(m, testNow)
+
+		_, err = o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, core.JobStatusRunning, saved.Steps[0].Status)
+	})
+
+	t.Run("Success_JobLimitsDeferSteps", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		workflow := relationalWorkflow()
+		workflow.Steps = workflow.Steps[:2] // customers and products, both roots
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+		m.jobs.On("CreateJob", mock.Anything, "proj-1", "member", mock.MatchedBy(func(req job.CreateJobRequest) bool {
+			return req.JobType == workflow.Steps[0].JobType && req.JobConfig == workflow.Steps[0].JobConfig
+		})).
+			Return(&core.Job{ID: "job-customers", Status: core.JobStatusPending}, nil).Once()
+		m.jobs.On("SubmitJob", mock.Anything, "job-customers", "member").Return(nil, &job.LimitError{Limit: job.LimitProjectRunning}).Once()
+		m.jobs.On("CreateJob", mock.Anything, "proj-1", "member", mock.Anything).Return(nil, &job.LimitError{Limit: job.LimitCreateRate}).Once()
+		saved := expectSave(m, workflow.UpdatedAt)
+
+		_, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, "job-customers", saved.Steps[0].JobID, "the submission is retried by a later tick")
+		assert.Equal(t, core.JobStatusPending, saved.Steps[0].Status)
+		assert.Empty(t, saved.Steps[1].JobID, "the step is started by a later tick")
+		assert.Equal(t, core.JobStatusPending, saved.Steps[1].Status)
+		m.jobs.AssertNotCalled(t, "CancelJob", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_SubmitRejectedFailsStep", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		workflow := relationalWorkflow()
+		workflow.Steps = workflow.Steps[:1]
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+		m.jobs.On("CreateJob", mock.Anything, "proj-1", "member", mock.Anything).Return(&core.Job{ID: "job-customers", Status: core.JobStatusPending}, nil).Once()
+		m.jobs.On("SubmitJob", mock.Anything, "job-customers", "member").Return(nil, core.ErrStorageQuotaExceeded).Once()
+		m.jobs.On("CancelJob", mock.Anything, "job-customers", "member").Return(&core.Job{ID: "job-customers", Status: core.JobStatusCancelled}, nil).Once()
+		saved := expectSave(m, workflow.UpdatedAt)
+
+		_, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err, "step failures are recorded on the workflow")
+		assert.Equal(t, core.JobStatusFailed, saved.Steps[0].Status)
+		assert.Contains(t, saved.Steps[0].Error, "Failed to submit job")
+		assert.Equal(t, core.JobStatusFailed, saved.Status)
+		assert.Contains(t, saved.Error, `Step "customers" failed`)
+		assert.Equal(t, &testNow, saved.CompletedAt)
+		m.jobs.AssertExpectations(t)
+	})
+
+	t.Run("Success_AllStepsComplete", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		workflow := relationalWorkflow()
+		workflow.Status = core.JobStatusRunning
+		workflow.Steps = workflow.Steps[:1]
+		workflow.Steps[0].JobID, workflow.Steps[0].Status = "job-customers", core.JobStatusRunning
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+		m.jobs.On("RefreshJobStatus", mock.Anything, "job-customers").Return(&core.Job{ID: "job-customers", Status: core.JobStatusCompleted}, nil).Once()
+		saved := expectSave(m, workflow.UpdatedAt)
+
+		_, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, core.JobStatusCompleted, saved.Status)
+		assert.Equal(t, 100, saved.Progress)
+		assert.Empty(t, saved.Error)
+	})
+
+	t.Run("Success_UnchangedWorkflowNotSaved", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		workflow := relationalWorkflow()
+		workflow.Status = core.JobStatusRunning
+		workflow.Steps = workflow.Steps[:1]
+		workflow.Steps[0].JobID, workflow.Steps[0].Status = "job-customers", core.JobStatusRunning
+		workflow.Steps[0].StartedAt, workflow.Steps[0].Progress = &testNow, 20
+		workflow.Progress = 20
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+		m.jobs.On("RefreshJobStatus", mock.Anything, "job-customers").Return(runningJob("job-customers"), nil).Once()
+		m.pipeline.On("Progress", mock.Anything, "pipe-job-customers").Return(0, errors.New("unavailable")).Once()
+
+		changed, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, changed)
+		m.workflows.AssertNotCalled(t, "SaveWorkflowState", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success_ConcurrentChangeCancelsStartedJobs", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		workflow := relationalWorkflow()
+		workflow.Steps = workflow.Steps[:1]
+		m.workflows.On("ListActiveWorkflows", mock.Anything, activeBatchSize).Return([]*core.Workflow{workflow}, nil).Once()
+		m.jobs.On("CreateJob", mock.Anything, "proj-1", "member", mock.Anything).Return(&core.Job{ID: "job-customers", Status: core.JobStatusPending}, nil).Once()
+		m.jobs.On("SubmitJob", mock.Anything, "job-customers", "member").Return(runningJob("job-customers"), nil).Once()
+		m.workflows.On("SaveWorkflowState", mock.Anything, mock.Anything, mock.Anything).Return(core.ErrConflict).Once()
+		m.jobs.On("CancelJob", mock.Anything, "job-customers", "member").Return(&core.Job{}, nil).Once()
+
+		changed, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, changed)
+		m.jobs.AssertExpectations(t)
+	})
+
+	t.Run("Success_NotLeaseHolder", func(t *testing.T) {
+		o, m := setupOrchestratorTest()
+		m.leases.ExpectedCalls = nil
+		m.leases.On("AcquireLease", mock.Anything, leaseName, "replica-1", 2*time.Minute).Return(false, nil).Once()
+
+		changed, err := o.AdvanceOnce(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, 0, changed)
+		m.workflows.AssertNotCalled(t, "ListActiveWorkflows", mock.Anything, mock.Anything)
+	})
+}
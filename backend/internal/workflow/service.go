@@ -0,0 +1,211 @@
+package workflow
+
+import (
+	"SynDataGen/backend/internal/access"
+	"SynDataGen/backend/internal/core"
+	"SynDataGen/backend/internal/platform/logger"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Workflow errors
+var (
+	ErrInvalidWorkflow  = errors.New("invalid workflow")
+	ErrWorkflowFinished = errors.New("workflow has already finished")
+	ErrWorkflowActive   = errors.New("workflow is still pending or running")
+)
+
+// maxSaveAttempts is how often a cancellation is attempted when the orchestrator saves the
+// workflow at the same time.
+const maxSaveAttempts = 3
+
+// --- Service Interface ---
+
+// WorkflowService defines the interface for workflow business logic. Workflows are run by
+// the Orchestrator.
+type WorkflowService interface {
+	// CreateWorkflow validates and stores a workflow for the orchestrator to run, requiring
+	// Member role. Its jobs are created on behalf of the creating user.
+	CreateWorkflow(ctx context.Context, projectID, userID string, req WorkflowRequest) (*core.Workflow, error)
+
+	// GetWorkflow retrieves a workflow with the status and progress of each step, requiring
+	// Viewer role.
+	GetWorkflow(ctx context.Context, projectID, workflowID, userID string) (*core.Workflow, error)
+
+	// ListWorkflows retrieves a paginated list of a project's workflows, requiring Viewer role.
+	ListWorkflows(ctx context.Context, projectID, userID string, limit, offset int) ([]*core.Workflow, int, error)
+
+	// CancelWorkflow cancels the running jobs of a pending or running workflow and the steps
+	// not yet started, requiring Member role.
+	CancelWorkflow(ctx context.Context, projectID, workflowID, userID string) (*core.Workflow, error)
+
+	// DeleteWorkflow deletes a finished workflow, requiring Member role. Its jobs are kept.
+	DeleteWorkflow(ctx context.Context, projectID, workflowID, userID string) error
+}
+
+// workflowService implements the WorkflowService interface.
+type workflowService struct {
+	workflowRepo core.WorkflowRepository
+	projects     access.ProjectGetter
+	jobs         JobRunner
+	now          func() time.Time // Overridable for tests
+}
+
+// NewWorkflowService creates a new workflow service instance.
+func NewWorkflowService(workflowRepo core.WorkflowRepository, projects access.ProjectGetter, jobs JobRunner) WorkflowService {
+	if workflowRepo == nil || projects == nil || jobs == nil {
+		panic("workflow.NewWorkflowService: all dependencies are required")
+	}
+	return &workflowService{
+		workflowRepo: workflowRepo,
+		projects:     projects,
+		jobs:         jobs,
+		now:          storedNow,
+	}
+}
+
+// getProjectWorkflow returns a workflow in the given project. Workflows of other projects
+// are reported as not found.
+func (s *workflowService) getProjectWorkflow(ctx context.Context, projectID, workflowID string) (*core.Workflow, error) {
+	workflow, err := s.workflowRepo.GetWorkflow(ctx, workflowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow %s: %w", workflowID, err)
+	}
+	if workflow.ProjectID != projectID {
+		return nil, fmt.Errorf("workflow %s is not in project %s: %w", workflowID, projectID, core.ErrNotFound)
+	}
+	return workflow, nil
+}
+
+// CreateWorkflow validates and stores a new workflow.
+func (s *workflowService) CreateWorkflow(ctx context.Context, projectID, userID string, req WorkflowRequest) (*core.Workflow, error) {
+	logger.Logger.Info("Attempting to create workflow", zap.String("projectID", projectID), zap.String("userID", userID))
+	// 1. Authorize and validate
+	if _, err := access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleMember, "workflows"); err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, fmt.Errorf("%w: name cannot be empty", ErrInvalidWorkflow)
+	}
+	steps, err := orderSteps(req.Steps)
+	if err != nil {
+		return nil, err
+	}
+
+	// 2. Store it; the orchestrator starts its first steps on its next tick
+	now := s.now()
+	workflow := &core.Workflow{
+		ID:        uuid.NewString(),
+		ProjectID: projectID,
+		Name:      req.Name,
+		CreatedBy: userID,
+		Status:    core.JobStatusPending,
+		Steps:     steps,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.workflowRepo.CreateWorkflow(ctx, workflow); err != nil {
+		return nil, fmt.Errorf("failed to store new workflow: %w", err)
+	}
+	logger.Logger.Info("Successfully created workflow",
+		zap.String("workflowID", workflow.ID),
+		zap.String("projectID", projectID),
+		zap.Int("steps", len(steps)),
+	)
+	return workflow, nil
+}
+
+// GetWorkflow retrieves a workflow.
+func (s *workflowService) GetWorkflow(ctx context.Context, projectID, workflowID, userID string) (*core.Workflow, error) {
+	if _, err := access.Authorize(ctx, s.projects, projectID, userID, core.RoleViewer); err != nil {
+		return nil, err
+	}
+	return s.getProjectWorkflow(ctx, projectID, workflowID)
+}
+
+// ListWorkflows retrieves a project's workflows.
+func (s *workflowService) ListWorkflows(ctx context.Context, projectID, userID string, limit, offset int) ([]*core.Workflow, int, error) {
+	if _, err := access.Authorize(ctx, s.projects, projectID, userID, core.RoleViewer); err != nil {
+		return nil, 0, err
+	}
+	workflows, total, err := s.workflowRepo.ListWorkflowsByProjectID(ctx, projectID, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list workflows: %w", err)
+	}
+	return workflows, total, nil
+}
+
+// CancelWorkflow cancels the unfinished steps of a workflow. If the orchestrator saves the
+// workflow first, the cancellation is repeated on the saved state.
+func (s *workflowService) CancelWorkflow(ctx context.Context, projectID, workflowID, userID string) (*core.Workflow, error) {
+	logger.Logger.Info("Attempting to cancel workflow", zap.String("workflowID", workflowID), zap.String("userID", userID))
+	if _, err := access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleMember, "workflows"); err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		// 1. Load the current state
+		workflow, err := s.getProjectWorkflow(ctx, projectID, workflowID)
+		if err != nil {
+			return nil, err
+		}
+		if isFinalStatus(workflow.Status) {
+			return nil, fmt.Errorf("%w: workflow %s is %s", ErrWorkflowFinished, workflowID, workflow.Status)
+		}
+		prevUpdatedAt := workflow.UpdatedAt
+
+		// 2. Cancel every unfinished step, stopping the jobs of started ones
+		now := s.now()
+		for i := range workflow.Steps {
+			step := &workflow.Steps[i]
+			if isFinalStatus(step.Status) {
+				continue
+			}
+			if step.JobID != "" {
+				if _, err := s.jobs.CancelJob(ctx, step.JobID, userID); err != nil {
+					logger.Logger.Warn("Failed to cancel workflow step job", zap.String("workflowID", workflowID), zap.String("jobID", step.JobID), zap.Error(err))
+				}
+			}
+			step.Status = core.JobStatusCancelled
+			step.Error = "Cancelled with the workflow"
+			step.CompletedAt = &now
+		}
+		summarize(workflow, now)
+		workflow.UpdatedAt = now
+
+		// 3. Save it unless the orchestrator got there first
+		err = s.workflowRepo.SaveWorkflowState(ctx, workflow, prevUpdatedAt)
+		if errors.Is(err, core.ErrConflict) && attempt < maxSaveAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to save cancelled workflow %s: %w", workflowID, err)
+		}
+		logger.Logger.Info("Cancelled workflow", zap.String("workflowID", workflowID), zap.String("status", string(workflow.Status)))
+		return workflow, nil
+	}
+}
+
+// DeleteWorkflow deletes a finished workflow.
+func (s *workflowService) DeleteWorkflow(ctx context.Context, projectID, workflowID, userID string) error {
+	if _, err := access.AuthorizeChange(ctx, s.projects, projectID, userID, core.RoleMember, "workflows"); err != nil {
+		return err
+	}
+	workflow, err := s.getProjectWorkflow(ctx, projectID, workflowID)
+	if err != nil {
+		return err
+	}
+	if !isFinalStatus(workflow.Status) {
+		return fmt.Errorf("%w: cancel workflow %s before deleting it", ErrWorkflowActive, workflowID)
+	}
+	if err := s.workflowRepo.DeleteWorkflow(ctx, workflowID); err != nil {
+		return fmt.Errorf("failed to delete workflow %s: %w", workflowID, err)
+	}
+	logger.Logger.Info("Deleted workflow", zap.String("workflowID", workflowID), zap.String("userID", userID))
+	return nil
+}
@@ -0,0 +1,254 @@
+package workflow
+
+import (
+	"SynDataGen/backend/internal/core"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// --- Mocks ---
+
+// MockWorkflowRepository is a mock implementation of core.WorkflowRepository.
+type MockWorkflowRepository struct {
+	mock.Mock
+}
+
+func (m *MockWorkflowRepository) CreateWorkflow(ctx context.Context, workflow *core.Workflow) error {
+	args := m.Called(ctx, workflow)
+	return args.Error(0)
+}
+
+func (m *MockWorkflowRepository) GetWorkflow(ctx context.Context, workflowID string) (*core.Workflow, error) {
+	args := m.Called(ctx, workflowID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Workflow), args.Error(1)
+}
+
+func (m *MockWorkflowRepository) ListWorkflowsByProjectID(ctx context.Context, projectID string, limit, offset int) ([]*core.Workflow, int, error) {
+	args := m.Called(ctx, projectID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*core.Workflow), args.Int(1), args.Error(2)
+}
+
+func (m *MockWorkflowRepository) ListActiveWorkflows(ctx context.Context, limit int) ([]*core.Workflow, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*core.Workflow), args.Error(1)
+}
+
+func (m *MockWorkflowRepository) SaveWorkflowState(ctx context.Context, workflow *core.Workflow, prevUpdatedAt time.Time) error {
+	args := m.Called(ctx, workflow, prevUpdatedAt)
+	return args.Error(0)
+}
+
+func (m *MockWorkflowRepository) DeleteWorkflow(ctx context.Context, workflowID string) error {
+	args := m.Called(ctx, workflowID)
+	return args.Error(0)
+}
+
+// MockProjectGetter is a mock implementation of access.ProjectGetter.
+type MockProjectGetter struct {
+	mock.Mock
+}
+
+func (m *MockProjectGetter) GetProjectByID(ctx context.Context, projectID string, callerID string) (*core.Project, error) {
+	args := m.Called(ctx, projectID, callerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*core.Project), args.Error(1)
+}
+
+// --- Test Setup ---
+
+var testNow = time.Date(2026, 3, 10, 10, 15, 0, 0, time.UTC)
+
+func setupTestService() (*workflowService, *MockWorkflowRepository, *MockProjectGetter, *MockJobRunner) {
+	repo := new(MockWorkflowRepository)
+	projects := new(MockProjectGetter)
+	jobs := new(MockJobRunner)
+	svc := NewWorkflowService(repo, projects, jobs).(*workflowService)
+	svc.now = func() time.Time { return testNow }
+	return svc, repo, projects, jobs
+}
+
+func testProject(status string) *core.Project {
+	return &core.Project{
+		ID:          "proj-1",
+		Status:      status,
+		TeamMembers: map[string]core.Role{"owner": core.RoleOwner, "member": core.RoleMember, "viewer": core.RoleViewer},
+	}
+}
+
+// --- Tests ---
+
+func TestWorkflowService_CreateWorkflow(t *testing.T) {
+	ctx := context.Background()
+	req := WorkflowRequest{
+		Name: "Shop",
+		Steps: []WorkflowStepRequest{
+			{Name: "orders", JobType: "csv", JobConfig: `{"rows":100}`, DependsOn: []string{"customers"}},
+			{Name: "customers", JobType: "csv", JobConfig: `{"rows":10}`},
+		},
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("CreateWorkflow", ctx, mock.AnythingOfType("*core.Workflow")).Return(nil).Once()
+
+		workflow, err := svc.CreateWorkflow(ctx, "proj-1", "member", req)
+
+		require.NoError(t, err)
+		assert.NotEmpty(t, workflow.ID)
+		assert.Equal(t, "member", workflow.CreatedBy)
+		assert.Equal(t, core.JobStatusPending, workflow.Status)
+		assert.Equal(t, "customers", workflow.Steps[0].Name)
+		assert.Equal(t, "orders", workflow.Steps[1].Name)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_Cycle", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		cyclic := WorkflowRequest{Name: "Loop", Steps: []WorkflowStepRequest{
+			{Name: "a", JobType: "csv", JobConfig: `{}`, DependsOn: []string{"b"}},
+			{Name: "b", JobType: "csv", JobConfig: `{}`, DependsOn: []string{"a"}},
+		}}
+
+		_, err := svc.CreateWorkflow(ctx, "proj-1", "member", cyclic)
+
+		assert.ErrorIs(t, err, ErrInvalidWorkflow)
+		repo.AssertNotCalled(t, "CreateWorkflow", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Failure_ViewerForbidden", func(t *testing.T) {
+		svc, _, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "viewer").Return(testProject(core.ProjectStatusActive), nil).Once()
+
+		_, err := svc.CreateWorkflow(ctx, "proj-1", "viewer", req)
+
+		assert.ErrorIs(t, err, core.ErrForbidden)
+	})
+
+	t.Run("Failure_ArchivedProject", func(t *testing.T) {
+		svc, _, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusArchived), nil).Once()
+
+		_, err := svc.CreateWorkflow(ctx, "proj-1", "member", req)
+
+		assert.ErrorIs(t, err, core.ErrProjectArchived)
+	})
+}
+
+func TestWorkflowService_GetWorkflow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Failure_OtherProject", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "viewer").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetWorkflow", ctx, "wf-1").Return(&core.Workflow{ID: "wf-1", ProjectID: "proj-2"}, nil).Once()
+
+		_, err := svc.GetWorkflow(ctx, "proj-1", "wf-1", "viewer")
+
+		assert.ErrorIs(t, err, core.ErrNotFound)
+	})
+}
+
+func TestWorkflowService_CancelWorkflow(t *testing.T) {
+	ctx := context.Background()
+	running := func() *core.Workflow {
+		return &core.Workflow{
+			ID:        "wf-1",
+			ProjectID: "proj-1",
+			Status:    core.JobStatusRunning,
+			UpdatedAt: testNow.Add(-time.Minute),
+			Steps: []core.WorkflowStep{
+				{Name: "customers", Status: core.JobStatusCompleted, JobID: "job-customers", Progress: 100},
+				{Name: "orders", Status: core.JobStatusRunning, JobID: "job-orders", DependsOn: []string{"customers"}},
+				{Name: "items", Status: core.JobStatusPending, DependsOn: []string{"orders"}},
+			},
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		svc, repo, projects, jobs := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetWorkflow", ctx, "wf-1").Return(running(), nil).Once()
+		jobs.On("CancelJob", ctx, "job-orders", "member").Return(&core.Job{ID: "job-orders", Status: core.JobStatusCancelled}, nil).Once()
+		repo.On("SaveWorkflowState", ctx, mock.AnythingOfType("*core.Workflow"), testNow.Add(-time.Minute)).Return(nil).Once()
+
+		workflow, err := svc.CancelWorkflow(ctx, "proj-1", "wf-1", "member")
+
+		require.NoError(t, err)
+		assert.Equal(t, core.JobStatusCancelled, workflow.Status)
+		assert.Equal(t, core.JobStatusCompleted, workflow.Steps[0].Status)
+		assert.Equal(t, core.JobStatusCancelled, workflow.Steps[1].Status)
+		assert.Equal(t, core.JobStatusCancelled, workflow.Steps[2].Status)
+		assert.Equal(t, &testNow, workflow.CompletedAt)
+		jobs.AssertExpectations(t)
+	})
+
+	t.Run("Success_RetriesAfterConcurrentSave", func(t *testing.T) {
+		svc, repo, projects, jobs := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetWorkflow", ctx, "wf-1").Return(running(), nil).Once()
+		repo.On("GetWorkflow", ctx, "wf-1").Return(running(), nil).Once() // Reloaded after the conflict
+		jobs.On("CancelJob", ctx, "job-orders", "member").Return(&core.Job{}, nil)
+		repo.On("SaveWorkflowState", ctx, mock.Anything, mock.Anything).Return(core.ErrConflict).Once()
+		repo.On("SaveWorkflowState", ctx, mock.Anything, mock.Anything).Return(nil).Once()
+
+		_, err := svc.CancelWorkflow(ctx, "proj-1", "wf-1", "member")
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_AlreadyFinished", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetWorkflow", ctx, "wf-1").Return(&core.Workflow{ID: "wf-1", ProjectID: "proj-1", Status: core.JobStatusCompleted}, nil).Once()
+
+		_, err := svc.CancelWorkflow(ctx, "proj-1", "wf-1", "member")
+
+		assert.ErrorIs(t, err, ErrWorkflowFinished)
+	})
+}
+
+func TestWorkflowService_DeleteWorkflow(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success_Finished", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetWorkflow", ctx, "wf-1").Return(&core.Workflow{ID: "wf-1", ProjectID: "proj-1", Status: core.JobStatusFailed}, nil).Once()
+		repo.On("DeleteWorkflow", ctx, "wf-1").Return(nil).Once()
+
+		err := svc.DeleteWorkflow(ctx, "proj-1", "wf-1", "member")
+
+		require.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Failure_Active", func(t *testing.T) {
+		svc, repo, projects, _ := setupTestService()
+		projects.On("GetProjectByID", ctx, "proj-1", "member").Return(testProject(core.ProjectStatusActive), nil).Once()
+		repo.On("GetWorkflow", ctx, "wf-1").Return(&core.Workflow{ID: "wf-1", ProjectID: "proj-1", Status: core.JobStatusRunning}, nil).Once()
+
+		err := svc.DeleteWorkflow(ctx, "proj-1", "wf-1", "member")
+
+		assert.ErrorIs(t, err, ErrWorkflowActive)
+		repo.AssertNotCalled(t, "DeleteWorkflow", mock.Anything, mock.Anything)
+	})
+}
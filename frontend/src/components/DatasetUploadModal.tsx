@@ -41,9 +41,9 @@ export function DatasetUploadModal({
   const onDrop = useCallback((acceptedFiles: File[]) => {
     if (acceptedFiles.length > 0) {
       const file = acceptedFiles[0];
-      // Basic validation (e.g., file type - allow CSV for now)
-      if (file.type !== 'text/csv') {
-          toast.error("Invalid file type. Please upload a CSV file.");
+      // Basic validation by extension; browsers report no MIME type for .jsonl or .parquet
+      if (!/\.(csv|json|jsonl|parquet)$/i.test(file.name)) {
+          toast.error("Invalid file type. Please upload a CSV, JSON, JSON Lines or Parquet file.");
           return;
       }
       // Optional: Limit file size frontend
@@ -59,7 +59,12 @@ export function DatasetUploadModal({
   const { getRootProps, getInputProps, isDragActive } = useDropzone({
     onDrop,
     multiple: false,
-    accept: { 'text/csv': ['.csv'] } // Accept only CSV
+    accept: {
+      'text/csv': ['.csv'],
+      'application/json': ['.json'],
+      'application/x-ndjson': ['.jsonl'],
+      'application/vnd.apache.parquet': ['.parquet'],
+    } // Formats the dataset viewer can read
   });
 
   const handleUpload = async () => {
@@ -114,7 +119,7 @@ export function DatasetUploadModal({
         <DialogHeader>
           <DialogTitle>Upload Dataset</DialogTitle>
           <DialogDescription>
-            Select a CSV file to upload to project: {projectId}
+            Select a CSV, JSON, JSON Lines or Parquet file to upload to project: {projectId}
           </DialogDescription>
         </DialogHeader>
         <div className="grid gap-4 py-4">
@@ -135,7 +140,7 @@ export function DatasetUploadModal({
                  </Label>
                  <p className="pl-1">or drag and drop</p>
                </div>
-               <p className="text-xs text-muted-foreground">CSV up to 500MB</p>
+               <p className="text-xs text-muted-foreground">CSV, JSON, JSONL or Parquet up to 500MB</p>
              </div>
           </div>
 
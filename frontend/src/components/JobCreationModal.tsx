@@ -12,7 +12,9 @@ import { Select, SelectContent, SelectGroup, SelectItem, SelectLabel, SelectTrig
 import { Textarea } from "@/components/shadcn/textarea"
 import { Terminal, AnimatedSpan } from "@/components/magicui/terminal"
 import { IconCircleCheckFilled, IconAlertCircleFilled, IconLoader } from '@tabler/icons-react'; // Added IconLoader
-import { useCreateJobMutation } from '@/features/jobs/jobApiSlice'; // Import the hook
+import { useCreateJobMutation, useEstimateJobMutation } from '@/features/jobs/jobApiSlice'; // Import the hook
+import { JobEstimate } from '@/types/job.types';
+import { formatBytes, formatCost, formatDuration } from '@/lib/utils';
 import { toast } from "sonner"; // Import toast
 
 // Define expected props
@@ -34,9 +36,16 @@ export function JobCreationModal({ isOpen, onOpenChange, projectId }: JobCreatio
   const [formError, setFormError] = React.useState('');
   const [creationStatus, setCreationStatus] = React.useState<CreationStatus>('idle');
   const [creationLogs, setCreationLogs] = React.useState<string[]>([]);
+  const [estimate, setEstimate] = React.useState<JobEstimate | null>(null);
 
   // --- RTK Query Hook ---
   const [createJob, { isLoading, isError, error: apiError }] = useCreateJobMutation();
+  const [estimateJob, { isLoading: isEstimating }] = useEstimateJobMutation();
+
+  // An estimate only holds for the type and config it was made for
+  useEffect(() => {
+    setEstimate(null);
+  }, [jobType, jobConfig]);
 
   // Reset form and status when modal opens/closes
   useEffect(() => {
@@ -48,6 +57,7 @@ export function JobCreationModal({ isOpen, onOpenChange, projectId }: JobCreatio
         setFormError('');
         setCreationStatus('idle');
         setCreationLogs([]);
+        setEstimate(null);
       }, 300); 
     } else {
        // Reset status immediately when opened
@@ -61,6 +71,21 @@ export function JobCreationModal({ isOpen, onOpenChange, projectId }: JobCreatio
     setCreationLogs(prev => [...prev, message]);
   };
 
+  const handleEstimate = async () => {
+    try {
+      JSON.parse(jobConfig);
+    } catch (jsonError) {
+      setFormError('Configuration must be valid JSON to estimate it.');
+      return;
+    }
+    setFormError('');
+    try {
+      setEstimate(await estimateJob({ projectId, request: { jobType, jobConfig } }).unwrap());
+    } catch (err: any) {
+      toast.error(err?.data?.message || 'Could not estimate this job');
+    }
+  };
+
   const handleSubmit = async () => {
     if (!jobType || !jobConfig) {
         setFormError('Job Type and Configuration are required.');
@@ -86,13 +111,16 @@ export function JobCreationModal({ isOpen, onOpenChange, projectId }: JobCreatio
 
     try {
       // Call the actual mutation
-      await createJob({
+      const job = await createJob({
         projectId,
         // Pass jobName if backend supports it, otherwise derive/omit
         newJob: { jobType, jobConfig /*, name: jobName */ }, 
       }).unwrap();
 
       addLog('      -> Backend accepted job submission.');
+      if (job.estimate) {
+        addLog(`      -> Estimated ${formatBytes(job.estimate.outputBytes)} in ${formatDuration(job.estimate.durationSeconds)} (${formatCost(job.estimate.computeCost, job.estimate.currency)}).`);
+      }
       addLog('[3/3] Finalizing job creation...');
       await new Promise(resolve => setTimeout(resolve, 300));
       addLog('      -> Job creation process initiated successfully!');
@@ -203,6 +231,21 @@ export function JobCreationModal({ isOpen, onOpenChange, projectId }: JobCreatio
               <p className="text-xs text-muted-foreground mt-1">Enter the job configuration, typically in JSON format.</p>
             </div>
 
+            {/* Estimate of the configured job */}
+            {estimate && (
+              <div className="rounded-md border bg-muted p-3 text-sm">
+                <p className="font-medium">
+                  About {formatBytes(estimate.outputBytes)} in {formatDuration(estimate.durationSeconds)}, costing {formatCost(estimate.computeCost, estimate.currency)}
+                </p>
+                <p className="text-xs text-muted-foreground mt-1">
+                  {estimate.records.toLocaleString()} records
+                  {estimate.basis === 'calibrated'
+                    ? `, estimated from ${estimate.sampleSize ?? 0} completed ${jobType} jobs`
+                    : ', estimated from default rates'}
+                </p>
+              </div>
+            )}
+
             {/* Form Error Message (for frontend validation) */}
             {formError && (
               <p className="text-center text-sm text-destructive">
@@ -221,6 +264,12 @@ export function JobCreationModal({ isOpen, onOpenChange, projectId }: JobCreatio
                 </Button>
             </DialogClose>
           )}
+          {creationStatus === 'idle' && (
+            <Button type="button" variant="outline" onClick={handleEstimate} disabled={!canSubmit || isEstimating}>
+              {isEstimating ? <IconLoader className="mr-2 h-4 w-4 animate-spin" /> : null}
+              Estimate
+            </Button>
+          )}
           {creationStatus === 'idle' && (
             <Button type="button" onClick={handleSubmit} disabled={!canSubmit || isLoading}>
               {isLoading ? <IconLoader className="mr-2 h-4 w-4 animate-spin" /> : null}
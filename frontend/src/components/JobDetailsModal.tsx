@@ -14,6 +14,7 @@ import {
 import { Badge } from "@/components/shadcn/badge"
 import { useGetJobQuery } from '@/features/jobs/jobApiSlice';
 import { IconLoader } from '@tabler/icons-react';
+import { formatBytes, formatCost, formatDuration } from '@/lib/utils';
 
 // TODO: Import Job type from shared location
 type JobStatus = 'pending' | 'queued' | 'running' | 'completed' | 'failed' | 'cancelled';
@@ -51,6 +52,11 @@ function formatDetailDateTime(isoString?: string): string {
     }
 }
 
+// Actual value with its ratio to the estimate, e.g. "2.0 MB (125% of estimate)"
+function withRatio(actual: string, ratio?: number): string {
+    return ratio ? `${actual} (${Math.round(ratio * 100)}% of estimate)` : actual
+}
+
 // Updated badge function for new statuses
 function renderStatusBadge(status?: JobStatus) {
     if (!status) return null;
@@ -153,6 +159,31 @@ export function JobDetailsModal({ jobId, isOpen, onOpenChange }: JobDetailsModal
                    <span className="col-span-2">N/A</span>
                 )}
               </div>
+              {job.estimate && (
+               <div className="grid grid-cols-3 items-start gap-4">
+                 <span className="text-muted-foreground">Estimate</span>
+                 <div className="col-span-2 space-y-1">
+                   <div>
+                     {formatBytes(job.estimate.outputBytes)} in {formatDuration(job.estimate.durationSeconds)}, {formatCost(job.estimate.computeCost, job.estimate.currency)}
+                   </div>
+                   <div className="text-xs text-muted-foreground">
+                     {job.estimate.basis === 'calibrated'
+                       ? `Calibrated on ${job.estimate.sampleSize ?? 0} completed jobs`
+                       : 'Default rates (too few completed jobs to calibrate)'}
+                   </div>
+                 </div>
+               </div>
+              )}
+              {job.actuals && (
+               <div className="grid grid-cols-3 items-start gap-4">
+                 <span className="text-muted-foreground">Actual</span>
+                 <div className="col-span-2 space-y-1">
+                   <div>Output: {job.actuals.outputBytes ? withRatio(formatBytes(job.actuals.outputBytes), job.actuals.outputBytesVsEstimate) : 'Not reported'}</div>
+                   <div>Duration: {withRatio(formatDuration(job.actuals.durationSeconds), job.actuals.durationVsEstimate)}</div>
+                   <div>Compute cost: {formatCost(job.actuals.computeCost, job.actuals.currency || job.estimate?.currency || 'USD')}</div>
+                 </div>
+               </div>
+              )}
               {job.error && (
                <div className="grid grid-cols-3 items-start gap-4">
                  <span className="text-muted-foreground">Error</span>
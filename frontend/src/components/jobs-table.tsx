@@ -207,8 +207,12 @@ function JobActions({ job }: { job: Job }) {
 
   const handleSubmit = async () => {
     try {
-      await submitJob(job.id).unwrap();
-      toast.success(`Job ${job.id} submitted successfully.`);
+      const submitted = await submitJob(job.id).unwrap();
+      if (submitted.status === "queued") {
+        toast.info(`Job ${job.id} is queued and will start once the job limit allows it.`);
+      } else {
+        toast.success(`Job ${job.id} submitted successfully.`);
+      }
     } catch (err: any) {
       console.error("Failed to submit job:", err);
       toast.error(err?.data?.message || "Failed to submit job.");
@@ -1,14 +1,23 @@
-import { apiSlice } from '@/store/apiSlice';
+import { apiSlice, baseUrl } from '@/store/apiSlice';
 import { 
     Job, 
     JobStatus, 
     CreateJobRequest, 
+    CloneJobRequest,
+    EstimateJobRequest,
+    JobEstimate,
     ListJobsParams, 
     ListJobsResponse, 
     ListAllJobsResponse, 
-    ListAllJobsParams 
+    ListAllJobsParams,
+    FidelityReport,
+    JobLogPage,
+    JobEvent,
+    JobEventType
 } from '@/types/job.types';
 
+const jobEventTypes: JobEventType[] = ['job.status', 'job.progress', 'job.result'];
+
 // --- Types (Based on OpenAPI spec & backend analysis) ---
 // Moved to @/types/job.types.ts
 // type JobStatus = ...;
@@ -58,6 +67,48 @@ export const jobApiSlice = apiSlice.injectEndpoints({
       providesTags: (result, error, id) => [{ type: 'Job', id }],
     }),
 
+    getJobQualityReport: builder.query<FidelityReport, { jobId: string; refresh?: boolean }>({
+      query: ({ jobId, refresh }) => `/jobs/${jobId}/quality-report${refresh ? '?refresh=true' : ''}`,
+      providesTags: (result, error, { jobId }) => [{ type: 'Job', id: jobId }],
+    }),
+
+    // Pages through a job's log; use an EventSource on ?follow=true to stream it
+    getJobLogs: builder.query<JobLogPage, { jobId: string; cursor?: string; limit?: number }>({
+      query: ({ jobId, cursor, limit }) => ({
+        url: `/jobs/${jobId}/logs`,
+        params: { ...(cursor ? { cursor } : {}), ...(limit ? { limit } : {}) },
+      }),
+      providesTags: (result, error, { jobId }) => [{ type: 'Job', id: jobId }],
+    }),
+
+    // Streams GET /events while subscribed, keeping the latest event per job and
+    // refetching job queries whose data an event changes. Replaces polling GET /jobs.
+    jobEvents: builder.query<Record<string, JobEvent>, void>({
+      queryFn: () => ({ data: {} }),
+      async onCacheEntryAdded(arg, { updateCachedData, cacheDataLoaded, cacheEntryRemoved, dispatch }) {
+        await cacheDataLoaded;
+        const source = new EventSource(`${baseUrl}/api/v1/events`, { withCredentials: true });
+        const onEvent = (message: MessageEvent) => {
+          const event: JobEvent = JSON.parse(message.data);
+          updateCachedData((draft) => {
+            draft[event.jobId] = { ...draft[event.jobId], ...event };
+          });
+          if (event.type !== 'job.progress') {
+            dispatch(jobApiSlice.util.invalidateTags([
+              { type: 'Job', id: event.jobId },
+              { type: 'Job', id: 'LIST-ALL' },
+              { type: 'Job', id: `LIST-${event.projectId}` },
+            ]));
+          }
+        };
+        jobEventTypes.forEach((type) => source.addEventListener(type, onEvent as EventListener));
+        // Events are not replayed, so refetch job lists after the browser reconnects
+        source.onopen = () => dispatch(jobApiSlice.util.invalidateTags([{ type: 'Job', id: 'LIST-ALL' }]));
+        await cacheEntryRemoved;
+        source.close();
+      },
+    }),
+
     createJob: builder.mutation<Job, { projectId: string; newJob: Omit<CreateJobRequest, 'projectId'> }>({
       query: ({ projectId, newJob }) => ({
         url: `/projects/${projectId}/jobs`,
@@ -71,6 +122,15 @@ export const jobApiSlice = apiSlice.injectEndpoints({
       ],
     }),
 
+    // Predicts a config's output size, duration and cost without creating a job
+    estimateJob: builder.mutation<JobEstimate, { projectId: string; request: EstimateJobRequest }>({
+      query: ({ projectId, request }) => ({
+        url: `/projects/${projectId}/jobs/estimate`,
+        method: 'POST',
+        body: request,
+      }),
+    }),
+
     cancelJob: builder.mutation<Job, string>({
       query: (jobId) => ({
         url: `/jobs/${jobId}`,
@@ -118,6 +178,24 @@ export const jobApiSlice = apiSlice.injectEndpoints({
       },
     }),
 
+    // Both create a new pending job in the source job's project; submit it with submitJob
+    retryJob: builder.mutation<Job, string>({
+      query: (jobId) => ({
+        url: `/jobs/${jobId}/retry`,
+        method: 'POST',
+      }),
+      invalidatesTags: (result) => (result ? [{ type: 'Job', id: `LIST-${result.projectId}` }] : []),
+    }),
+
+    cloneJob: builder.mutation<Job, { jobId: string; overrides?: CloneJobRequest }>({
+      query: ({ jobId, overrides }) => ({
+        url: `/jobs/${jobId}/clone`,
+        method: 'POST',
+        body: overrides ?? {},
+      }),
+      invalidatesTags: (result) => (result ? [{ type: 'Job', id: `LIST-${result.projectId}` }] : []),
+    }),
+
     // TODO: Add mutations for submitJob and syncJobStatus once backend endpoints are available
     /*
     submitJob: builder.mutation<Job, string>({...}),
@@ -132,10 +210,16 @@ export const {
   useListAllAccessibleJobsQuery,
   useListJobsQuery,
   useGetJobQuery,
+  useGetJobQualityReportQuery,
+  useGetJobLogsQuery,
+  useJobEventsQuery,
   useCreateJobMutation,
+  useEstimateJobMutation,
   useCancelJobMutation,
   useSubmitJobMutation,
   useSyncJobStatusMutation,
+  useRetryJobMutation,
+  useCloneJobMutation,
   useLazyListProjectsQuery,
   useLazyGetProjectQuery,
   useLazyListJobsQuery,
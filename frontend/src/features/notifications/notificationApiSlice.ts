@@ -0,0 +1,72 @@
+import { apiSlice } from '@/store/apiSlice';
+import {
+    NotificationPreference,
+    NotificationPreferenceRequest,
+    NotificationScope,
+    ListNotificationDeliveriesParams,
+    ListNotificationDeliveriesResponse,
+    SendTestNotificationResponse
+} from '@/types/notification.types';
+
+// Enhance apiSlice tagTypes
+const enhancedApiSlice = apiSlice.enhanceEndpoints({ addTagTypes: ['NotificationPreference', 'NotificationDelivery'] });
+
+// Base URL of a project's notification routes, or the caller's own
+const notificationsUrl = ({ projectId }: NotificationScope) =>
+  projectId ? `/projects/${projectId}/notifications` : '/notifications';
+
+// Tag ID of a scope's preference and delivery log
+const scopeId = ({ projectId }: NotificationScope) => projectId || 'USER';
+
+// --- Inject Endpoints ---
+
+export const notificationApiSlice = enhancedApiSlice.injectEndpoints({
+  endpoints: (builder) => ({
+    getNotificationPreference: builder.query<NotificationPreference, NotificationScope | void>({
+      query: (scope) => `${notificationsUrl(scope || {})}/preferences`,
+      providesTags: (result, error, scope) => [{ type: 'NotificationPreference', id: scopeId(scope || {}) }],
+    }),
+
+    updateNotificationPreference: builder.mutation<NotificationPreference, NotificationScope & { preference: NotificationPreferenceRequest }>({
+      query: ({ preference, ...scope }) => ({
+        url: `${notificationsUrl(scope)}/preferences`,
+        method: 'PUT',
+        body: preference,
+      }),
+      invalidatesTags: (result, error, scope) => [{ type: 'NotificationPreference', id: scopeId(scope) }],
+    }),
+
+    deleteNotificationPreference: builder.mutation<void, NotificationScope | void>({
+      query: (scope) => ({
+        url: `${notificationsUrl(scope || {})}/preferences`,
+        method: 'DELETE',
+      }),
+      invalidatesTags: (result, error, scope) => [{ type: 'NotificationPreference', id: scopeId(scope || {}) }],
+    }),
+
+    // Sends to every channel right away; failures are reported on the returned deliveries
+    sendTestNotification: builder.mutation<SendTestNotificationResponse, NotificationScope | void>({
+      query: (scope) => ({
+        url: `${notificationsUrl(scope || {})}/preferences/test`,
+        method: 'POST',
+      }),
+      invalidatesTags: (result, error, scope) => [{ type: 'NotificationDelivery', id: scopeId(scope || {}) }],
+    }),
+
+    listNotificationDeliveries: builder.query<ListNotificationDeliveriesResponse, NotificationScope & { params?: ListNotificationDeliveriesParams }>({
+      query: ({ params, ...scope }) => ({
+        url: `${notificationsUrl(scope)}/deliveries`,
+        params: params || {},
+      }),
+      providesTags: (result, error, scope) => [{ type: 'NotificationDelivery', id: scopeId(scope) }],
+    }),
+  }),
+});
+
+export const {
+  useGetNotificationPreferenceQuery,
+  useUpdateNotificationPreferenceMutation,
+  useDeleteNotificationPreferenceMutation,
+  useSendTestNotificationMutation,
+  useListNotificationDeliveriesQuery,
+} = notificationApiSlice;
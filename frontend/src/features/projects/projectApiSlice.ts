@@ -36,7 +36,107 @@ interface DatasetSummary {
 // Define the type for the dataset content (matches page component)
 interface DatasetContent {
   data: Record<string, any>[];
-  // Add other relevant metadata if the API provides it
+  columns: string[]; // Column names in file order
+  offset: number;
+  limit: number;
+  totalRows: number;
+  totalRowsExact: boolean; // False when totalRows is estimated from a partial read
+  schema?: DatasetColumn[]; // Typed columns, present for Parquet datasets
+}
+
+interface DatasetColumn {
+  name: string;
+  type: string;
+  nullable: boolean;
+  repeated: boolean;
+}
+
+// Inferred column types, matching the backend's schema detector
+export type InferredColumnType =
+  | 'integer' | 'float' | 'boolean' | 'date' | 'datetime'
+  | 'categorical' | 'text' | 'email' | 'uuid' | 'unknown';
+
+// One entry of a job config "schema" section
+export interface JobSchemaField {
+  name: string;
+  type: InferredColumnType;
+  nullable?: boolean;
+  format?: string; // Go time layout for dates, "json" for nested values
+  values?: string[]; // Categories
+}
+
+export interface DatasetSchema {
+  datasetId: string;
+  sampledRows: number;
+  complete: boolean; // The whole dataset fit in the sample
+  columns: (JobSchemaField & {
+    nullCount: number;
+    distinctCount: number;
+    distinctCapped?: boolean;
+    min?: number;
+    max?: number;
+  })[];
+  jobConfigSchema: JobSchemaField[]; // Ready to drop into a job config
+}
+
+// Per-column statistics over a whole dataset
+export interface ColumnProfile {
+  name: string;
+  type: InferredColumnType;
+  count: number; // Non-null values
+  nullCount: number;
+  distinctCount: number;
+  distinctApproximate?: boolean; // HyperLogLog estimate
+  topValues: { value: string; count: number }[];
+  topValuesApproximate?: boolean;
+  min?: number; // Numeric columns only
+  max?: number;
+  mean?: number;
+  stddev?: number;
+  quantiles?: { quantile: number; value: number }[];
+  histogram?: { lower: number; upper: number; count: number }[];
+  sampled?: boolean; // Quantiles and histogram come from a sample
+}
+
+export interface DatasetProfile {
+  datasetId: string;
+  rowCount: number;
+  columns: ColumnProfile[];
+  profiledAt: string; // ISO Date string
+  cached: boolean; // Served from the stored profile
+}
+
+export interface PIIFinding {
+  column: string;
+  type: 'email' | 'phone' | 'credit_card' | 'ssn' | 'ip_address' | 'name';
+  severity: 'high' | 'medium';
+  matches: number;
+  matchRate: number;
+}
+
+export interface PIIScan {
+  datasetId: string;
+  status: 'not_scanned' | 'pending' | 'completed' | 'failed';
+  scannedAt?: string; // ISO Date string
+  rowsScanned?: number;
+  sampled?: boolean;
+  findings?: PIIFinding[]; // High severity first
+  findingsTruncated?: boolean;
+  highSeverityCount?: number;
+  error?: string; // Set when the scan failed
+  acknowledgedBy?: string;
+  acknowledgedAt?: string; // ISO Date string
+}
+
+// Paging, sorting and filtering options for dataset content
+interface DatasetContentParams {
+  projectId: string;
+  datasetId: string;
+  offset?: number;
+  limit?: number;
+  columns?: string[]; // Projection; omitted returns every column
+  sort?: string; // e.g. "-age,name"
+  filters?: string[]; // e.g. ["country=US", "age>=30"]
 }
 
 // Enhance apiSlice tagTypes
@@ -126,6 +226,8 @@ export const projectApiSlice = enhancedApiSlice.injectEndpoints({
     // New List Datasets Query
     listDatasets: builder.query<DatasetSummary[], string>({ // Returns array of summaries, takes projectId
         query: (projectId) => `/projects/${projectId}/datasets`,
+        // The backend returns a folder listing; the table shows the root-level files
+        transformResponse: (response: { datasets: DatasetSummary[] }) => response.datasets,
         providesTags: (result, error, projectId) => 
           result
             ? [
@@ -149,11 +251,56 @@ export const projectApiSlice = enhancedApiSlice.injectEndpoints({
       }),
 
     // New endpoint for fetching dataset content
-    getDatasetContent: builder.query<DatasetContent, { projectId: string; datasetId: string }>({ 
-      query: ({ projectId, datasetId }) => `/projects/${projectId}/datasets/${encodeURIComponent(datasetId)}/content`, // Assuming name is used as ID for now, encode it
+    getDatasetContent: builder.query<DatasetContent, DatasetContentParams>({ 
+      query: ({ projectId, datasetId, offset, limit, columns, sort, filters }) => {
+        const params = new URLSearchParams();
+        if (offset) params.set('offset', String(offset));
+        if (limit) params.set('limit', String(limit));
+        if (columns?.length) params.set('columns', columns.join(','));
+        if (sort) params.set('sort', sort);
+        filters?.forEach((filter) => params.append('filter', filter));
+        const qs = params.toString();
+        return `/projects/${projectId}/datasets/${encodeURIComponent(datasetId)}/content${qs ? `?${qs}` : ''}`; // Assuming name is used as ID for now, encode it
+      },
+      providesTags: (result, error, { projectId, datasetId }) => [{ type: 'DatasetContent', id: `${projectId}-${datasetId}` }],
+    }),
+
+    // Column type inference over a sample of the dataset
+    getDatasetSchema: builder.query<DatasetSchema, { projectId: string; datasetId: string; sampleRows?: number }>({
+      query: ({ projectId, datasetId, sampleRows }) =>
+        `/projects/${projectId}/datasets/${encodeURIComponent(datasetId)}/schema${sampleRows ? `?sampleRows=${sampleRows}` : ''}`,
       providesTags: (result, error, { projectId, datasetId }) => [{ type: 'DatasetContent', id: `${projectId}-${datasetId}` }],
     }),
 
+    // Per-column statistics; the backend reuses a stored profile unless refresh is set
+    getDatasetProfile: builder.query<DatasetProfile, { projectId: string; datasetId: string; refresh?: boolean }>({
+      query: ({ projectId, datasetId, refresh }) =>
+        `/projects/${projectId}/datasets/${encodeURIComponent(datasetId)}/profile${refresh ? '?refresh=true' : ''}`,
+      providesTags: (result, error, { projectId, datasetId }) => [{ type: 'DatasetContent', id: `${projectId}-${datasetId}` }],
+    }),
+
+    // PII findings of the latest scan; uploads are scanned in the background
+    getDatasetPIIScan: builder.query<PIIScan, { projectId: string; datasetId: string }>({
+      query: ({ projectId, datasetId }) => `/projects/${projectId}/datasets/${encodeURIComponent(datasetId)}/pii`,
+      providesTags: (result, error, { projectId, datasetId }) => [{ type: 'DatasetContent', id: `${projectId}-${datasetId}` }],
+    }),
+
+    scanDatasetPII: builder.mutation<PIIScan, { projectId: string; datasetId: string }>({
+      query: ({ projectId, datasetId }) => ({
+        url: `/projects/${projectId}/datasets/${encodeURIComponent(datasetId)}/pii/scan`,
+        method: 'POST',
+      }),
+      invalidatesTags: (result, error, { projectId, datasetId }) => [{ type: 'DatasetContent', id: `${projectId}-${datasetId}` }],
+    }),
+
+    acknowledgeDatasetPII: builder.mutation<PIIScan, { projectId: string; datasetId: string }>({
+      query: ({ projectId, datasetId }) => ({
+        url: `/projects/${projectId}/datasets/${encodeURIComponent(datasetId)}/pii/acknowledge`,
+        method: 'POST',
+      }),
+      invalidatesTags: (result, error, { projectId, datasetId }) => [{ type: 'DatasetContent', id: `${projectId}-${datasetId}` }],
+    }),
+
   }),
   overrideExisting: false, // Keep existing endpoints
 });
@@ -171,6 +318,11 @@ export const {
   useUploadDatasetMutation,
   useListDatasetsQuery, // Export the new query hook
   useGetDatasetContentQuery, // Export the new content query hook
+  useGetDatasetSchemaQuery,
+  useGetDatasetProfileQuery,
+  useGetDatasetPIIScanQuery,
+  useScanDatasetPIIMutation,
+  useAcknowledgeDatasetPIIMutation,
   // Add lazy query hooks if needed
   useLazyListProjectsQuery,
   useLazyGetProjectQuery,
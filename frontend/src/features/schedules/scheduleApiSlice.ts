@@ -0,0 +1,86 @@
+import { apiSlice } from '@/store/apiSlice';
+import {
+    Schedule,
+    ScheduleRequest,
+    ListSchedulesParams,
+    ListSchedulesResponse
+} from '@/types/schedule.types';
+
+// Enhance apiSlice tagTypes
+const enhancedApiSlice = apiSlice.enhanceEndpoints({ addTagTypes: ['Schedule'] });
+
+// Tags to invalidate after a schedule changes
+const scheduleTags = (projectId: string, scheduleId: string) => [
+  { type: 'Schedule' as const, id: scheduleId },
+  { type: 'Schedule' as const, id: `LIST-${projectId}` },
+];
+
+// --- Inject Endpoints ---
+
+export const scheduleApiSlice = enhancedApiSlice.injectEndpoints({
+  endpoints: (builder) => ({
+    listSchedules: builder.query<ListSchedulesResponse, { projectId: string; params?: ListSchedulesParams }>({
+      query: ({ projectId, params }) => ({
+        url: `/projects/${projectId}/schedules`,
+        params: params || {},
+      }),
+      providesTags: (result, error, { projectId }) => {
+        const tags =
+          result && Array.isArray(result.schedules)
+            ? result.schedules.map(({ id }) => ({ type: 'Schedule' as const, id }))
+            : [];
+        return [{ type: 'Schedule', id: `LIST-${projectId}` }, ...tags];
+      },
+    }),
+
+    getSchedule: builder.query<Schedule, { projectId: string; scheduleId: string }>({
+      query: ({ projectId, scheduleId }) => `/projects/${projectId}/schedules/${scheduleId}`,
+      providesTags: (result, error, { scheduleId }) => [{ type: 'Schedule', id: scheduleId }],
+    }),
+
+    createSchedule: builder.mutation<Schedule, { projectId: string; schedule: ScheduleRequest }>({
+      query: ({ projectId, schedule }) => ({
+        url: `/projects/${projectId}/schedules`,
+        method: 'POST',
+        body: schedule,
+      }),
+      invalidatesTags: (result, error, { projectId }) => [{ type: 'Schedule', id: `LIST-${projectId}` }],
+    }),
+
+    updateSchedule: builder.mutation<Schedule, { projectId: string; scheduleId: string; schedule: ScheduleRequest }>({
+      query: ({ projectId, scheduleId, schedule }) => ({
+        url: `/projects/${projectId}/schedules/${scheduleId}`,
+        method: 'PUT',
+        body: schedule,
+      }),
+      invalidatesTags: (result, error, { projectId, scheduleId }) => scheduleTags(projectId, scheduleId),
+    }),
+
+    setScheduleEnabled: builder.mutation<Schedule, { projectId: string; scheduleId: string; enabled: boolean }>({
+      query: ({ projectId, scheduleId, enabled }) => ({
+        url: `/projects/${projectId}/schedules/${scheduleId}/${enabled ? 'enable' : 'disable'}`,
+        method: 'POST',
+      }),
+      invalidatesTags: (result, error, { projectId, scheduleId }) => scheduleTags(projectId, scheduleId),
+    }),
+
+    deleteSchedule: builder.mutation<void, { projectId: string; scheduleId: string }>({
+      query: ({ projectId, scheduleId }) => ({
+        url: `/projects/${projectId}/schedules/${scheduleId}`,
+        method: 'DELETE',
+      }),
+      invalidatesTags: (result, error, { projectId, scheduleId }) => scheduleTags(projectId, scheduleId),
+    }),
+  }),
+  overrideExisting: true,
+});
+
+// Export hooks
+export const {
+  useListSchedulesQuery,
+  useGetScheduleQuery,
+  useCreateScheduleMutation,
+  useUpdateScheduleMutation,
+  useSetScheduleEnabledMutation,
+  useDeleteScheduleMutation,
+} = scheduleApiSlice;
@@ -0,0 +1,100 @@
+import { apiSlice } from '@/store/apiSlice';
+import {
+    JobTemplate,
+    TemplateRequest,
+    ListTemplatesParams,
+    ListTemplatesResponse,
+    CreateJobFromTemplateRequest,
+    CreateJobFromTemplateResponse
+} from '@/types/template.types';
+
+// Enhance apiSlice tagTypes
+const enhancedApiSlice = apiSlice.enhanceEndpoints({ addTagTypes: ['JobTemplate'] });
+
+// --- Inject Endpoints ---
+
+export const templateApiSlice = enhancedApiSlice.injectEndpoints({
+  endpoints: (builder) => ({
+    listTemplates: builder.query<ListTemplatesResponse, { projectId: string; params?: ListTemplatesParams }>({
+      query: ({ projectId, params }) => ({
+        url: `/projects/${projectId}/job-templates`,
+        params: params || {},
+      }),
+      providesTags: (result, error, { projectId }) => {
+        const templateTags =
+          result && Array.isArray(result.templates)
+            ? result.templates.map(({ id }) => ({ type: 'JobTemplate' as const, id }))
+            : [];
+        return [{ type: 'JobTemplate', id: `LIST-${projectId}` }, ...templateTags];
+      },
+    }),
+
+    getTemplate: builder.query<JobTemplate, { projectId: string; templateId: string }>({
+      query: ({ projectId, templateId }) => `/projects/${projectId}/job-templates/${templateId}`,
+      providesTags: (result, error, { templateId }) => [{ type: 'JobTemplate', id: templateId }],
+    }),
+
+    // Versions are immutable, so they are cached without tags
+    getTemplateVersion: builder.query<JobTemplate, { projectId: string; templateId: string; version: number }>({
+      query: ({ projectId, templateId, version }) =>
+        `/projects/${projectId}/job-templates/${templateId}/versions/${version}`,
+    }),
+
+    createTemplate: builder.mutation<JobTemplate, { projectId: string; template: TemplateRequest }>({
+      query: ({ projectId, template }) => ({
+        url: `/projects/${projectId}/job-templates`,
+        method: 'POST',
+        body: template,
+      }),
+      invalidatesTags: (result, error, { projectId }) => [{ type: 'JobTemplate', id: `LIST-${projectId}` }],
+    }),
+
+    // Saves a new version; pass baseVersion to detect concurrent edits
+    updateTemplate: builder.mutation<JobTemplate, { projectId: string; templateId: string; template: TemplateRequest }>({
+      query: ({ projectId, templateId, template }) => ({
+        url: `/projects/${projectId}/job-templates/${templateId}`,
+        method: 'PUT',
+        body: template,
+      }),
+      invalidatesTags: (result, error, { projectId, templateId }) => [
+        { type: 'JobTemplate', id: templateId },
+        { type: 'JobTemplate', id: `LIST-${projectId}` },
+      ],
+    }),
+
+    deleteTemplate: builder.mutation<void, { projectId: string; templateId: string }>({
+      query: ({ projectId, templateId }) => ({
+        url: `/projects/${projectId}/job-templates/${templateId}`,
+        method: 'DELETE',
+      }),
+      invalidatesTags: (result, error, { projectId, templateId }) => [
+        { type: 'JobTemplate', id: templateId },
+        { type: 'JobTemplate', id: `LIST-${projectId}` },
+      ],
+    }),
+
+    createJobFromTemplate: builder.mutation<
+      CreateJobFromTemplateResponse,
+      { projectId: string; templateId: string; request?: CreateJobFromTemplateRequest }
+    >({
+      query: ({ projectId, templateId, request }) => ({
+        url: `/projects/${projectId}/jobs/from-template/${templateId}`,
+        method: 'POST',
+        body: request ?? {},
+      }),
+      invalidatesTags: (result, error, { projectId }) => [{ type: 'Job', id: `LIST-${projectId}` }],
+    }),
+  }),
+  overrideExisting: true,
+});
+
+// Export hooks
+export const {
+  useListTemplatesQuery,
+  useGetTemplateQuery,
+  useGetTemplateVersionQuery,
+  useCreateTemplateMutation,
+  useUpdateTemplateMutation,
+  useDeleteTemplateMutation,
+  useCreateJobFromTemplateMutation,
+} = templateApiSlice;
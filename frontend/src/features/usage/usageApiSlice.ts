@@ -0,0 +1,35 @@
+import { apiSlice } from '@/store/apiSlice';
+import { UsageReport, UsageReportParams } from '@/types/usage.types';
+
+// Enhance apiSlice tagTypes
+const enhancedApiSlice = apiSlice.enhanceEndpoints({ addTagTypes: ['Usage'] });
+
+// --- Inject Endpoints ---
+
+export const usageApiSlice = enhancedApiSlice.injectEndpoints({
+  endpoints: (builder) => ({
+    getUsageReport: builder.query<UsageReport, UsageReportParams | void>({
+      query: (params) => ({
+        url: '/usage',
+        params: params || {},
+      }),
+      providesTags: [{ type: 'Usage', id: 'REPORT' }],
+    }),
+
+    // The report's groups as CSV text, e.g. for saving as a file
+    getUsageReportCsv: builder.query<string, UsageReportParams | void>({
+      query: (params) => ({
+        url: '/usage',
+        params: { ...(params || {}), format: 'csv' },
+        responseHandler: 'text',
+      }),
+      providesTags: [{ type: 'Usage', id: 'CSV' }],
+    }),
+  }),
+});
+
+export const {
+  useGetUsageReportQuery,
+  useGetUsageReportCsvQuery,
+  useLazyGetUsageReportCsvQuery,
+} = usageApiSlice;
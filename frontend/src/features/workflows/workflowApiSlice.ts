@@ -0,0 +1,76 @@
+import { apiSlice } from '@/store/apiSlice';
+import {
+    Workflow,
+    WorkflowRequest,
+    ListWorkflowsParams,
+    ListWorkflowsResponse
+} from '@/types/workflow.types';
+
+// Enhance apiSlice tagTypes
+const enhancedApiSlice = apiSlice.enhanceEndpoints({ addTagTypes: ['Workflow'] });
+
+// Tags to invalidate after a workflow changes
+const workflowTags = (projectId: string, workflowId: string) => [
+  { type: 'Workflow' as const, id: workflowId },
+  { type: 'Workflow' as const, id: `LIST-${projectId}` },
+];
+
+// --- Inject Endpoints ---
+
+export const workflowApiSlice = enhancedApiSlice.injectEndpoints({
+  endpoints: (builder) => ({
+    listWorkflows: builder.query<ListWorkflowsResponse, { projectId: string; params?: ListWorkflowsParams }>({
+      query: ({ projectId, params }) => ({
+        url: `/projects/${projectId}/workflows`,
+        params: params || {},
+      }),
+      providesTags: (result, error, { projectId }) => {
+        const tags =
+          result && Array.isArray(result.workflows)
+            ? result.workflows.map(({ id }) => ({ type: 'Workflow' as const, id }))
+            : [];
+        return [{ type: 'Workflow', id: `LIST-${projectId}` }, ...tags];
+      },
+    }),
+
+    getWorkflow: builder.query<Workflow, { projectId: string; workflowId: string }>({
+      query: ({ projectId, workflowId }) => `/projects/${projectId}/workflows/${workflowId}`,
+      providesTags: (result, error, { workflowId }) => [{ type: 'Workflow', id: workflowId }],
+    }),
+
+    createWorkflow: builder.mutation<Workflow, { projectId: string; workflow: WorkflowRequest }>({
+      query: ({ projectId, workflow }) => ({
+        url: `/projects/${projectId}/workflows`,
+        method: 'POST',
+        body: workflow,
+      }),
+      invalidatesTags: (result, error, { projectId }) => [{ type: 'Workflow', id: `LIST-${projectId}` }],
+    }),
+
+    cancelWorkflow: builder.mutation<Workflow, { projectId: string; workflowId: string }>({
+      query: ({ projectId, workflowId }) => ({
+        url: `/projects/${projectId}/workflows/${workflowId}/cancel`,
+        method: 'POST',
+      }),
+      invalidatesTags: (result, error, { projectId, workflowId }) => workflowTags(projectId, workflowId),
+    }),
+
+    deleteWorkflow: builder.mutation<void, { projectId: string; workflowId: string }>({
+      query: ({ projectId, workflowId }) => ({
+        url: `/projects/${projectId}/workflows/${workflowId}`,
+        method: 'DELETE',
+      }),
+      invalidatesTags: (result, error, { projectId, workflowId }) => workflowTags(projectId, workflowId),
+    }),
+  }),
+  overrideExisting: true,
+});
+
+// Export hooks
+export const {
+  useListWorkflowsQuery,
+  useGetWorkflowQuery,
+  useCreateWorkflowMutation,
+  useCancelWorkflowMutation,
+  useDeleteWorkflowMutation,
+} = workflowApiSlice;
@@ -15,6 +15,49 @@ export interface Job {
   updatedAt: string; // ISO Date string
   startedAt?: string; // ISO Date string
   completedAt?: string; // ISO Date string
+  retryOf?: string; // ID of the failed or cancelled job this one retries
+  clonedFrom?: string; // ID of the job this one was cloned from
+  attempt?: number; // Starts at 1 and increases with each retry
+  retryPolicy?: JobRetryPolicy;
+  retryAt?: string; // ISO Date string, when an automatic retry will be submitted
+  templateId?: string; // Job template the job was created from
+  templateVersion?: number;
+  scheduleId?: string; // Schedule that created the job
+  workflowId?: string; // Workflow that created the job as one of its steps
+  estimate?: JobEstimate; // Predicted when the job was created
+  actuals?: JobActuals; // Recorded when the job completed
+  meteredAt?: string; // ISO Date string, when the completed job's usage was recorded
+}
+
+// Type matching backend JobEstimate, served by POST /projects/:projectId/jobs/estimate
+export interface JobEstimate {
+  records: number;
+  columns: number; // 0 if the config has no schema
+  outputBytes: number;
+  durationSeconds: number;
+  computeCost: number; // In currency; 0 if no compute price is configured
+  currency: string;
+  basis: 'calibrated' | 'default'; // Fitted to completed jobs, or built-in rates
+  sampleSize?: number; // Completed jobs a calibrated model was fitted to
+  calibratedAt?: string; // ISO Date string
+}
+
+export interface JobActuals {
+  outputBytes?: number; // Absent if the pipeline does not report output sizes
+  durationSeconds: number;
+  computeCost: number;
+  currency: string;
+  durationVsEstimate?: number; // Actual divided by estimated duration
+  outputBytesVsEstimate?: number;
+}
+
+// Error classes of pipeline failures that a retry policy can cover
+export type JobErrorClass = 'transient' | 'resource' | 'invalid' | 'internal';
+
+export interface JobRetryPolicy {
+  maxAttempts: number; // Total attempts including the first, 1-10
+  backoffSeconds?: number; // Delay before the first retry, doubled for each further attempt
+  retryOn?: JobErrorClass[]; // Defaults to ['transient']
 }
 
 // Request/Response types
@@ -22,6 +65,20 @@ export interface CreateJobRequest {
   projectId: string; // Needed by backend service, though maybe redundant if passed in URL
   jobType: string;
   jobConfig: string;
+  retryPolicy?: JobRetryPolicy;
+}
+
+export interface EstimateJobRequest {
+  jobType: string;
+  jobConfig: string;
+}
+
+// Fields left out are copied from the source job
+export interface CloneJobRequest {
+  jobType?: string;
+  jobConfig?: string; // Replaces the source config
+  configOverrides?: Record<string, unknown>; // JSON merge patch applied to the source config
+  retryPolicy?: JobRetryPolicy;
 }
 
 export interface ListJobsParams {
@@ -51,4 +108,82 @@ export interface ListAllJobsParams {
   limit?: number;
   offset?: number;
   statusFilter?: JobStatus; // Corresponds to backend statusFilter
-} 
\ No newline at end of file
+} 
+// Per-column comparison in a job quality report
+export interface ColumnFidelity {
+  name: string;
+  type: string; // Inferred from the real dataset
+  realNullRate: number;
+  syntheticNullRate: number;
+  ks?: number; // Kolmogorov-Smirnov statistic, numeric and date columns
+  tvd?: number; // Total variation distance
+  categoryCoverage?: number;
+  missingCategories?: string[];
+  novelCategories?: string[];
+}
+
+// Type matching backend LogEntry, served by GET /jobs/:jobId/logs
+export interface JobLogEntry {
+  sequence: number;
+  timestamp: string; // ISO Date string
+  level: 'INFO' | 'WARN' | 'ERROR';
+  stage?: string;
+  message: string;
+}
+
+export interface JobLogPage {
+  logs: JobLogEntry[];
+  nextCursor: string; // Pass as cursor to read the following entries
+  hasMore: boolean;
+  jobStatus: JobStatus;
+}
+
+// Type matching backend events.Event, pushed by GET /events (the SSE event name is its type)
+export type JobEventType = 'job.status' | 'job.progress' | 'job.result';
+
+export interface JobEvent {
+  id: string;
+  type: JobEventType;
+  projectId: string;
+  jobId: string;
+  status?: JobStatus;
+  progress?: number; // Percentage, on job.progress events
+  resultUri?: string; // On job.result events
+  error?: string;
+  occurredAt: string; // ISO Date string
+}
+
+// Type matching backend FidelityReport, served by GET /jobs/:jobId/quality-report
+export interface FidelityReport {
+  realDatasetId: string;
+  syntheticDatasetId: string;
+  realRows: number;
+  syntheticRows: number;
+  sampled: boolean;
+  summary: {
+    meanKs?: number;
+    meanTvd?: number;
+    meanCategoryCoverage?: number;
+  };
+  columns: ColumnFidelity[];
+  missingColumns: string[];
+  extraColumns: string[];
+  correlations?: {
+    columns: string[];
+    real: (number | null)[][];
+    synthetic: (number | null)[][];
+    meanAbsDifference?: number;
+  };
+  privacy?: {
+    features: string[];
+    syntheticRecords: number;
+    referenceRecords: number;
+    dcrMedian: number;
+    dcrP05: number;
+    exactMatchRate: number;
+    baselineDcrMedian?: number;
+    baselineDcrP05?: number;
+  };
+  generatedAt: string; // ISO Date string
+  cached: boolean; // Served from the stored report
+}
@@ -0,0 +1,77 @@
+import { JobStatus } from './job.types';
+
+export type NotificationChannelType = 'email' | 'webhook' | 'slack';
+
+// Final job statuses that can be notified
+export type NotifiedJobStatus = Extract<JobStatus, 'completed' | 'failed' | 'cancelled'>;
+
+export type NotificationDeliveryStatus = 'pending' | 'delivered' | 'failed';
+
+// Type matching backend core.NotificationChannel; webhook secrets are never returned
+export interface NotificationChannel {
+  type: NotificationChannelType;
+  target: string; // Email address, or the https URL of the webhook
+}
+
+// Type matching backend core.NotificationPreference
+export interface NotificationPreference {
+  id: string; // user-{userId} or project-{projectId}
+  userId?: string; // Set for a user's preference
+  projectId?: string; // Set for a project's preference
+  enabled: boolean;
+  statuses: NotifiedJobStatus[];
+  channels: NotificationChannel[];
+  updatedBy?: string;
+  updatedAt?: string; // ISO Date string; unset until the preference is saved
+}
+
+export interface NotificationChannelRequest {
+  type: NotificationChannelType; // email is only available when the server has SMTP configured
+  target?: string; // An email channel of a user's preference defaults to the user's address
+  secret?: string; // Webhook signing key; omit to keep the current one for the same URL
+}
+
+export interface NotificationPreferenceRequest {
+  enabled?: boolean; // Defaults to true
+  statuses?: NotifiedJobStatus[]; // Defaults to completed and failed
+  channels: NotificationChannelRequest[]; // At most 10
+}
+
+// Type matching backend core.NotificationDelivery
+export interface NotificationDelivery {
+  id: string;
+  preferenceId: string;
+  projectId?: string;
+  jobId?: string; // Empty for test sends
+  jobStatus?: NotifiedJobStatus;
+  test?: boolean; // Sent from the test endpoint; never retried
+  channel: NotificationChannel;
+  status: NotificationDeliveryStatus;
+  attempts: number;
+  lastError?: string;
+  createdAt: string; // ISO Date string
+  nextAttemptAt?: string; // Set while pending
+  lastAttemptAt?: string;
+  deliveredAt?: string;
+}
+
+export interface ListNotificationDeliveriesParams {
+  limit?: number;
+  offset?: number;
+}
+
+export interface ListNotificationDeliveriesResponse {
+  deliveries: NotificationDelivery[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
+export interface SendTestNotificationResponse {
+  deliveries: NotificationDelivery[];
+}
+
+// Addresses a project's preference, or the caller's own when projectId is omitted
+export interface NotificationScope {
+  projectId?: string;
+}
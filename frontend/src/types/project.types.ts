@@ -3,6 +3,7 @@ import { Role } from './role.types';
 export interface ProjectSettings {
     dataRetentionDays: number;
     maxStorageGB: number;
+    requirePiiAcknowledgement?: boolean; // Block jobs on unreviewed PII in their input dataset
 }
 
 export interface ProjectStorage {
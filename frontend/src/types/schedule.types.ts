@@ -0,0 +1,50 @@
+import { JobRetryPolicy } from './job.types';
+
+export type ScheduleOverlapPolicy = 'skip' | 'queue' | 'cancel-previous';
+
+export type ScheduleRunStatus = 'submitted' | 'skipped' | 'failed';
+
+// Names either a template (templateId) or a job type and config
+export interface ScheduleRequest {
+  name: string;
+  cron: string; // Five fields, e.g. "0 2 * * MON-FRI", or a descriptor such as @daily
+  timezone?: string; // IANA name; defaults to UTC
+  enabled?: boolean; // Defaults to true
+  overlapPolicy?: ScheduleOverlapPolicy; // Defaults to skip
+  templateId?: string;
+  templateVersion?: number; // Omit to use the current version at each run
+  templateVariables?: Record<string, string | number | boolean>;
+  jobType?: string;
+  jobConfig?: string;
+  retryPolicy?: JobRetryPolicy;
+}
+
+// Type matching backend core.Schedule
+export interface Schedule extends ScheduleRequest {
+  id: string;
+  projectId: string;
+  timezone: string;
+  enabled: boolean;
+  overlapPolicy: ScheduleOverlapPolicy;
+  createdBy: string;
+  updatedBy: string; // Jobs are created on behalf of this user
+  createdAt: string; // ISO Date string
+  updatedAt: string; // ISO Date string
+  nextRunAt?: string; // ISO Date string; absent while disabled
+  lastRunAt?: string; // ISO Date string
+  lastRunStatus?: ScheduleRunStatus;
+  lastRunError?: string;
+  lastJobId?: string;
+}
+
+export interface ListSchedulesParams {
+  limit?: number;
+  offset?: number;
+}
+
+export interface ListSchedulesResponse {
+  schedules: Schedule[];
+  total: number;
+  limit: number;
+  offset: number;
+}
@@ -0,0 +1,61 @@
+import { Job, JobRetryPolicy } from './job.types';
+
+export type TemplateVariableType = 'string' | 'number' | 'integer' | 'boolean';
+
+// Declares a {{name}} placeholder in a template's jobConfig
+export interface TemplateVariable {
+  name: string;
+  type: TemplateVariableType;
+  description?: string;
+  default?: string | number | boolean; // Variables without a default are required
+}
+
+// Type matching backend core.JobTemplate
+export interface JobTemplate {
+  id: string;
+  projectId: string;
+  name: string;
+  description?: string;
+  jobType: string;
+  jobConfig: string; // JSON object with {{name}} placeholders
+  variables: TemplateVariable[];
+  version: number; // Incremented by every update
+  createdBy: string;
+  updatedBy: string; // Author of this version
+  createdAt: string; // ISO Date string
+  updatedAt: string; // ISO Date string, when this version was saved
+}
+
+export interface TemplateRequest {
+  name: string;
+  description?: string;
+  jobType: string;
+  jobConfig: string;
+  variables?: TemplateVariable[];
+  baseVersion?: number; // Updates only: rejected with 409 if the template has moved on
+}
+
+export interface ListTemplatesParams {
+  limit?: number;
+  offset?: number;
+}
+
+export interface ListTemplatesResponse {
+  templates: JobTemplate[];
+  total: number;
+  limit: number;
+  offset: number;
+}
+
+export interface CreateJobFromTemplateRequest {
+  version?: number; // Defaults to the current version
+  variables?: Record<string, string | number | boolean>;
+  submit?: boolean;
+  retryPolicy?: JobRetryPolicy;
+}
+
+export interface CreateJobFromTemplateResponse {
+  job: Job;
+  submitted: boolean;
+  submitError?: string; // The job was still created and can be submitted later
+}
@@ -0,0 +1,35 @@
+export type UsageGroupBy = 'project' | 'day' | 'jobType';
+
+// Metered quantities summed per metric
+export interface UsageTotals {
+  recordsGenerated: number;
+  computeSeconds: number;
+  storageByteHours: number; // Stored bytes multiplied by the hours they were stored
+  egressBytes: number; // Bytes sent by dataset content downloads
+}
+
+export interface UsageGroup extends UsageTotals {
+  key: string; // Project ID, UTC day (YYYY-MM-DD) or job type; empty for storage and egress when grouping by job type
+  projectName?: string; // Set when grouping by project
+  customerId?: string; // Set when grouping by project
+}
+
+export interface CustomerUsage extends UsageTotals {
+  customerId: string;
+}
+
+export interface UsageReport {
+  from: string; // ISO 8601
+  to: string; // ISO 8601, exclusive
+  groupBy: UsageGroupBy;
+  groups: UsageGroup[]; // Ordered by key
+  customers: CustomerUsage[]; // Ordered by customer ID
+  total: UsageTotals;
+}
+
+// Covers the projects the caller owns or administers
+export interface UsageReportParams {
+  from?: string; // RFC 3339 timestamp or YYYY-MM-DD; defaults to 30 days before `to`
+  to?: string; // Defaults to now
+  groupBy?: UsageGroupBy; // Defaults to project
+}
@@ -0,0 +1,52 @@
+import { JobRetryPolicy, JobStatus } from './job.types';
+
+export interface WorkflowStepRequest {
+  name: string; // Letters, digits, '-' and '_'
+  jobType: string;
+  jobConfig: string; // JSON object; may read upstream results through {{steps.<name>.resultUri}}
+  dependsOn?: string[]; // Names of steps that must complete first
+  retryPolicy?: JobRetryPolicy;
+}
+
+export interface WorkflowRequest {
+  name: string;
+  steps: WorkflowStepRequest[];
+}
+
+// Type matching backend core.WorkflowStep
+export interface WorkflowStep extends WorkflowStepRequest {
+  status: JobStatus;
+  jobId?: string; // Latest attempt, following automatic retries
+  progress: number; // Percentage
+  resultUri?: string;
+  error?: string;
+  startedAt?: string; // ISO Date string
+  completedAt?: string; // ISO Date string
+}
+
+// Type matching backend core.Workflow
+export interface Workflow {
+  id: string;
+  projectId: string;
+  name: string;
+  createdBy: string; // Step jobs are created on behalf of this user
+  status: JobStatus;
+  progress: number; // Percentage, averaged over the steps
+  steps: WorkflowStep[]; // In dependency order
+  error?: string;
+  createdAt: string; // ISO Date string
+  updatedAt: string; // ISO Date string
+  completedAt?: string; // ISO Date string
+}
+
+export interface ListWorkflowsParams {
+  limit?: number;
+  offset?: number;
+}
+
+export interface ListWorkflowsResponse {
+  workflows: Workflow[];
+  total: number;
+  limit: number;
+  offset: number;
+}